@@ -0,0 +1,183 @@
+package drand
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/binary"
+	"io"
+	"math/big"
+
+	"golang.org/x/crypto/bn256"
+
+	"github.com/pkg/errors"
+)
+
+// fieldModulus is the modulus of the field the BN256 curve y^2 = x^3 + 3 is defined over.
+var fieldModulus, _ = new(big.Int).SetString("65000549695646603732796438742359905742825358107623003571877145026864184071783", 10)
+
+// Share is a share of the group secret key, held by a single node.
+type Share struct {
+	// Index is the index of the share, starting from 1.
+	Index uint32
+	// Secret is the secret key share.
+	Secret *big.Int
+}
+
+// Group is the public information of a threshold BLS key shared amongst a group of nodes.
+type Group struct {
+	// Threshold is the number of partial signatures needed to recover a full signature.
+	Threshold int
+	// Size is the number of shares dealt, whose indices range from 1 to Size.
+	Size int
+	// Commitments are the coefficients of the polynomial the key shares were derived from,
+	// in G2. The first commitment is the group public key.
+	Commitments []*bn256.G2
+}
+
+// GenerateShares deals n shares of a random group secret key, of which any threshold
+// shares recover a full signature, through Shamir's secret sharing. The dealer learns the
+// group secret key, and must be trusted to discard it.
+func GenerateShares(threshold, n int, rand io.Reader) (*Group, []*Share, error) {
+	if threshold < 1 || threshold > n {
+		return nil, nil, errors.Errorf("drand: threshold must be within [1, %d]", n)
+	}
+
+	coefficients := make([]*big.Int, threshold)
+	commitments := make([]*bn256.G2, threshold)
+
+	for i := range coefficients {
+		coefficient, commitment, err := bn256.RandomG2(rand)
+		if err != nil {
+			return nil, nil, errors.Wrap(err, "drand: failed to generate polynomial")
+		}
+		coefficients[i], commitments[i] = coefficient, commitment
+	}
+
+	shares := make([]*Share, n)
+	for i := range shares {
+		index := uint32(i + 1)
+		shares[i] = &Share{Index: index, Secret: evaluate(coefficients, index)}
+	}
+
+	return &Group{Threshold: threshold, Size: n, Commitments: commitments}, shares, nil
+}
+
+// PublicKey returns the group public key, which full signatures are verified against.
+func (g *Group) PublicKey() *bn256.G2 {
+	return g.Commitments[0]
+}
+
+// SharePublicKey returns the public key of the share with an index, which partial
+// signatures made with the share are verified against.
+func (g *Group) SharePublicKey(index uint32) *bn256.G2 {
+	x := new(big.Int).SetUint64(uint64(index))
+	power := big.NewInt(1)
+
+	key := new(bn256.G2).ScalarBaseMult(big.NewInt(0))
+	for _, commitment := range g.Commitments {
+		key.Add(key, new(bn256.G2).ScalarMult(commitment, power))
+		power.Mod(power.Mul(power, x), bn256.Order)
+	}
+
+	return key
+}
+
+// sign signs a round with a secret key.
+func sign(secret *big.Int, round uint64) *bn256.G1 {
+	return new(bn256.G1).ScalarMult(hashRound(round), secret)
+}
+
+// verify verifies the signature of a round against a public key.
+func verify(key *bn256.G2, round uint64, signature *bn256.G1) bool {
+	generator := new(bn256.G2).ScalarBaseMult(big.NewInt(1))
+
+	lhs := bn256.Pair(signature, generator).Marshal()
+	rhs := bn256.Pair(hashRound(round), key).Marshal()
+
+	return bytes.Equal(lhs, rhs)
+}
+
+// recoverSignature interpolates a full signature from partial signatures keyed by the
+// index of the share they were made with. At least threshold valid partial signatures
+// must be given.
+func recoverSignature(partials map[uint32]*bn256.G1) *bn256.G1 {
+	signature := new(bn256.G1).ScalarBaseMult(big.NewInt(0))
+
+	for i, partial := range partials {
+		signature.Add(signature, new(bn256.G1).ScalarMult(partial, lagrange(i, partials)))
+	}
+
+	return signature
+}
+
+// lagrange returns the lagrange basis polynomial of an index evaluated at zero, over the
+// indices of a set of partial signatures.
+func lagrange(i uint32, partials map[uint32]*bn256.G1) *big.Int {
+	num, den := big.NewInt(1), big.NewInt(1)
+
+	xi := new(big.Int).SetUint64(uint64(i))
+
+	for j := range partials {
+		if j == i {
+			continue
+		}
+
+		xj := new(big.Int).SetUint64(uint64(j))
+
+		num.Mod(num.Mul(num, xj), bn256.Order)
+		den.Mod(den.Mul(den, new(big.Int).Sub(xj, xi)), bn256.Order)
+	}
+
+	return num.Mod(num.Mul(num, den.ModInverse(den, bn256.Order)), bn256.Order)
+}
+
+// evaluate evaluates a polynomial at an index.
+func evaluate(coefficients []*big.Int, index uint32) *big.Int {
+	x := new(big.Int).SetUint64(uint64(index))
+	result := new(big.Int)
+
+	for i := len(coefficients) - 1; i >= 0; i-- {
+		result.Mul(result, x)
+		result.Add(result, coefficients[i])
+		result.Mod(result, bn256.Order)
+	}
+
+	return result
+}
+
+// hashRound hashes a round onto a point of G1 by trying successive candidate x-coordinates
+// until one lies on the curve. Unlike multiplying the generator by a hash, the discrete
+// logarithm of the point is unknown, such that signatures of one round may not be derived
+// from the signatures of another.
+func hashRound(round uint64) *bn256.G1 {
+	var buf [12]byte
+	binary.BigEndian.PutUint64(buf[:8], round)
+
+	three := big.NewInt(3)
+
+	for counter := uint32(0); ; counter++ {
+		binary.BigEndian.PutUint32(buf[8:], counter)
+		digest := sha256.Sum256(buf[:])
+
+		x := new(big.Int).SetBytes(digest[:])
+		x.Mod(x, fieldModulus)
+
+		rhs := new(big.Int).Mul(x, x)
+		rhs.Mul(rhs, x)
+		rhs.Add(rhs, three)
+		rhs.Mod(rhs, fieldModulus)
+
+		y := new(big.Int).ModSqrt(rhs, fieldModulus)
+		if y == nil {
+			continue
+		}
+
+		encoded := make([]byte, 64)
+		x.FillBytes(encoded[:32])
+		y.FillBytes(encoded[32:])
+
+		if point, ok := new(bn256.G1).Unmarshal(encoded); ok {
+			return point
+		}
+	}
+}
@@ -0,0 +1,78 @@
+package drand
+
+import (
+	"crypto/rand"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/perlin-network/noise/clock"
+	"github.com/perlin-network/noise/internal/protobuf"
+	"github.com/perlin-network/noise/network"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func newTestPlugin(t *testing.T, now, genesis time.Time, share *Share, group *Group) *Plugin {
+	builder := network.NewBuilderWithOptions(network.WithClock(clock.NewFakeClock(now)))
+
+	net, err := builder.Build()
+	assert.Nil(t, err)
+
+	state := &Plugin{Share: share, Group: group, RoundInterval: time.Second, Genesis: genesis}
+	state.Startup(net)
+
+	return state
+}
+
+func TestReceivePartial(t *testing.T) {
+	t.Parallel()
+
+	group, shares, err := GenerateShares(2, 3, rand.Reader)
+	assert.Nil(t, err)
+
+	now := time.Now()
+
+	state := newTestPlugin(t, now, now, shares[0], group)
+	defer state.Cleanup(state.net)
+
+	outputs, unsubscribe := state.Subscribe()
+	defer unsubscribe()
+
+	partial := func(index uint32, secret *big.Int) *protobuf.BeaconPartial {
+		return &protobuf.BeaconPartial{Round: 1, Index: index, Signature: sign(secret, 1).Marshal()}
+	}
+
+	assert.True(t, state.receivePartial(partial(2, shares[1].Secret)))
+	assert.False(t, state.receivePartial(partial(2, shares[1].Secret)), "expected a collected partial signature to not be gossiped again")
+
+	assert.False(t, state.receivePartial(partial(4, shares[1].Secret)), "expected a partial signature of an out of range share to be rejected")
+	assert.False(t, state.receivePartial(partial(3, shares[1].Secret)), "expected a partial signature not made with its share to be rejected")
+
+	select {
+	case <-outputs:
+		t.Fatal("expected no randomness to be produced off of invalid partial signatures")
+	default:
+	}
+
+	assert.True(t, state.receivePartial(partial(3, shares[2].Secret)))
+
+	select {
+	case output := <-outputs:
+		assert.Equal(t, uint64(1), output.Round)
+	default:
+		t.Fatal("expected randomness to be produced once a threshold of valid partial signatures were collected")
+	}
+}
+
+func TestRoundWithoutGenesis(t *testing.T) {
+	t.Parallel()
+
+	group, shares, err := GenerateShares(1, 1, rand.Reader)
+	assert.Nil(t, err)
+
+	state := newTestPlugin(t, time.Unix(10, 500), time.Time{}, shares[0], group)
+	defer state.Cleanup(state.net)
+
+	assert.Equal(t, uint64(11), state.Round(), "expected rounds to be counted since the unix epoch")
+}
@@ -0,0 +1,289 @@
+package drand
+
+import (
+	"context"
+	"crypto/sha256"
+	"sync"
+	"time"
+
+	"golang.org/x/crypto/bn256"
+
+	"github.com/perlin-network/noise/internal/protobuf"
+	"github.com/perlin-network/noise/network"
+)
+
+const (
+	// DefaultRoundInterval is the default interval in between rounds of the beacon.
+	DefaultRoundInterval = 30 * time.Second
+
+	// subscriptionBufferSize is the number of outputs buffered per subscriber, beyond which
+	// outputs are dropped for subscribers which fail to keep up.
+	subscriptionBufferSize = 16
+)
+
+// BeaconOutput is the randomness produced by the beacon for a round.
+type BeaconOutput struct {
+	Round      uint64
+	Randomness []byte
+}
+
+// Plugin implements a distributed random beacon through threshold BLS signatures. Every
+// round interval, each node signs the round number with its key share and gossips the
+// partial signature. Once a threshold of partial signatures of a round are collected, they
+// are combined into the groups signature of the round, whose hash is the rounds randomness.
+//
+// As the signature of a round is unique and may not be produced by fewer nodes than the
+// threshold, the randomness is unpredictable and identical across all nodes.
+type Plugin struct {
+	*network.Plugin
+
+	// Share is the key share of the node.
+	Share *Share
+	// Group is the public information of the key shared amongst the nodes.
+	Group *Group
+
+	// RoundInterval is the interval in between rounds (default: DefaultRoundInterval).
+	RoundInterval time.Duration
+	// Genesis is the time the beacon started at, since which rounds are counted. Rounds are
+	// counted since the unix epoch should none be set.
+	Genesis time.Time
+
+	net *network.Network
+
+	// shareKeys caches the public keys of shares partial signatures are verified against.
+	shareKeys sync.Map // uint32 -> *bn256.G2
+
+	mutex       sync.Mutex
+	latest      uint64 // the latest round whose randomness was produced
+	partials    map[uint64]map[uint32]*bn256.G1
+	subscribers map[chan BeaconOutput]struct{}
+
+	stop chan struct{}
+}
+
+var (
+	PluginID                         = (*Plugin)(nil)
+	_        network.PluginInterface = (*Plugin)(nil)
+)
+
+func (state *Plugin) Startup(net *network.Network) {
+	state.net = net
+
+	state.mutex.Lock()
+	state.partials = make(map[uint64]map[uint32]*bn256.G1)
+	if state.subscribers == nil {
+		state.subscribers = make(map[chan BeaconOutput]struct{})
+	}
+	state.mutex.Unlock()
+
+	if state.Share == nil || state.Group == nil {
+		logger := net.PluginLogger(state)
+		logger.Error().Msg("A key share and group must be set for the random beacon to run.")
+		return
+	}
+
+	state.stop = make(chan struct{})
+	go state.roundLoop(state.stop)
+}
+
+func (state *Plugin) Cleanup(net *network.Network) {
+	if state.stop != nil {
+		close(state.stop)
+		state.stop = nil
+	}
+}
+
+func (state *Plugin) Receive(ctx *network.PluginContext) error {
+	switch msg := ctx.Message().(type) {
+	case *protobuf.BeaconPartial:
+		// Partial signatures are gossiped onwards the first time they are collected.
+		if state.receivePartial(msg) {
+			state.net.Broadcast(context.Background(), msg)
+		}
+	}
+
+	return nil
+}
+
+// Subscribe returns a channel which every output of the beacon is published to, and a
+// function which closes the channel once the subscriber is no longer interested.
+func (state *Plugin) Subscribe() (<-chan BeaconOutput, func()) {
+	ch := make(chan BeaconOutput, subscriptionBufferSize)
+
+	state.mutex.Lock()
+	if state.subscribers == nil {
+		state.subscribers = make(map[chan BeaconOutput]struct{})
+	}
+	state.subscribers[ch] = struct{}{}
+	state.mutex.Unlock()
+
+	var once sync.Once
+
+	return ch, func() {
+		once.Do(func() {
+			state.mutex.Lock()
+			delete(state.subscribers, ch)
+			state.mutex.Unlock()
+
+			close(ch)
+		})
+	}
+}
+
+// Round returns the current round, as timed by the networks clock. Rounds start from 1
+// at genesis, and are 0 before genesis.
+func (state *Plugin) Round() uint64 {
+	elapsed := state.net.Clock().Now().Sub(state.genesis())
+	if elapsed < 0 {
+		return 0
+	}
+	return uint64(elapsed/state.roundInterval()) + 1
+}
+
+// roundLoop signs and gossips a partial signature at the start of every round.
+func (state *Plugin) roundLoop(stop chan struct{}) {
+	for {
+		next := state.genesis().Add(time.Duration(state.Round()) * state.roundInterval())
+
+		wait := next.Sub(state.net.Clock().Now())
+		if wait < 0 {
+			wait = 0
+		}
+
+		select {
+		case <-stop:
+			return
+		case <-state.net.Clock().After(wait):
+		}
+
+		round := state.Round()
+		signature := sign(state.Share.Secret, round)
+
+		partial := &protobuf.BeaconPartial{
+			Round:     round,
+			Index:     state.Share.Index,
+			Signature: signature.Marshal(),
+		}
+
+		if state.collect(round, state.Share.Index, signature) {
+			state.net.Broadcast(context.Background(), partial)
+		}
+	}
+}
+
+// receivePartial verifies and collects a partial signature of a round received from a peer.
+// Returns true if the partial signature was valid and had yet to be collected.
+func (state *Plugin) receivePartial(msg *protobuf.BeaconPartial) bool {
+	// Partial signatures of rounds which are too far ahead for clocks to be out of sync are ignored.
+	if msg.Index == 0 || msg.Index > uint32(state.Group.Size) || msg.Round > state.Round()+1 {
+		return false
+	}
+
+	signature, ok := new(bn256.G1).Unmarshal(msg.Signature)
+	if !ok {
+		return false
+	}
+
+	if !state.pending(msg) {
+		return false
+	}
+
+	// As pairings are expensive, partial signatures are verified outside of the lock.
+	if !verify(state.shareKey(msg.Index), msg.Round, signature) {
+		logger := state.net.PluginLogger(state)
+		logger.Warn().Uint64("round", msg.Round).Uint32("index", msg.Index).Msg("Received an invalid partial signature.")
+		return false
+	}
+
+	return state.collect(msg.Round, msg.Index, signature)
+}
+
+// collect collects a valid partial signature of a round, and recovers the groups signature
+// of the round once a threshold of partial signatures have been collected. Returns true if
+// the partial signature had yet to be collected.
+func (state *Plugin) collect(round uint64, index uint32, signature *bn256.G1) bool {
+	state.mutex.Lock()
+	defer state.mutex.Unlock()
+
+	if round <= state.latest {
+		return false
+	}
+
+	partials, exists := state.partials[round]
+	if !exists {
+		partials = make(map[uint32]*bn256.G1)
+		state.partials[round] = partials
+	}
+
+	if _, collected := partials[index]; collected {
+		return false
+	}
+	partials[index] = signature
+
+	if len(partials) < state.Group.Threshold {
+		return true
+	}
+
+	full := recoverSignature(partials)
+
+	state.latest = round
+	for r := range state.partials {
+		if r <= round {
+			delete(state.partials, r)
+		}
+	}
+
+	digest := sha256.Sum256(full.Marshal())
+	output := BeaconOutput{Round: round, Randomness: digest[:]}
+
+	for ch := range state.subscribers {
+		select {
+		case ch <- output:
+		default:
+		}
+	}
+
+	return true
+}
+
+// pending returns true should a partial signature be of a round whose randomness has yet
+// to be produced, and not have been collected yet.
+func (state *Plugin) pending(msg *protobuf.BeaconPartial) bool {
+	state.mutex.Lock()
+	defer state.mutex.Unlock()
+
+	if msg.Round <= state.latest {
+		return false
+	}
+
+	_, collected := state.partials[msg.Round][msg.Index]
+	return !collected
+}
+
+// shareKey returns the public key of the share with an index.
+func (state *Plugin) shareKey(index uint32) *bn256.G2 {
+	if key, ok := state.shareKeys.Load(index); ok {
+		return key.(*bn256.G2)
+	}
+
+	key := state.Group.SharePublicKey(index)
+	state.shareKeys.Store(index, key)
+
+	return key
+}
+
+// genesis returns the time rounds are counted since, which is the unix epoch should no
+// genesis be set.
+func (state *Plugin) genesis() time.Time {
+	if state.Genesis.IsZero() {
+		return time.Unix(0, 0)
+	}
+	return state.Genesis
+}
+
+func (state *Plugin) roundInterval() time.Duration {
+	if state.RoundInterval <= 0 {
+		return DefaultRoundInterval
+	}
+	return state.RoundInterval
+}
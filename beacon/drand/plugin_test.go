@@ -0,0 +1,109 @@
+package drand_test
+
+import (
+	"crypto/rand"
+	"testing"
+	"time"
+
+	"github.com/perlin-network/noise/beacon/drand"
+	"github.com/perlin-network/noise/network"
+	"github.com/perlin-network/noise/testutil"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRandomBeacon(t *testing.T) {
+	t.Parallel()
+
+	const (
+		size          = 7
+		threshold     = 5
+		rounds        = 3
+		roundInterval = 3 * time.Second
+	)
+
+	group, shares, err := drand.GenerateShares(threshold, size, rand.Reader)
+	assert.Nil(t, err)
+
+	genesis := time.Now()
+
+	plugins := make([]*drand.Plugin, size)
+	outputs := make([]<-chan drand.BeaconOutput, size)
+	unsubscribe := make([]func(), size)
+
+	opts := testutil.WithBuilderOptions(network.WriteFlushLatency(time.Millisecond))
+
+	cluster, err := testutil.NewCluster(size, opts, testutil.WithSetup(func(i int, builder *network.Builder) {
+		plugins[i] = &drand.Plugin{
+			Share:         shares[i],
+			Group:         group,
+			RoundInterval: roundInterval,
+			Genesis:       genesis,
+		}
+
+		outputs[i], unsubscribe[i] = plugins[i].Subscribe()
+
+		builder.AddPlugin(plugins[i])
+	}))
+	assert.Nil(t, err)
+	defer cluster.Stop()
+
+	for _, fn := range unsubscribe {
+		defer fn()
+	}
+
+	// Rounds which started while the cluster was bootstrapping are skipped.
+	first := plugins[0].Round() + 1
+
+	received := make([]map[uint64][]byte, size)
+	timestamps := make(map[uint64]time.Time)
+
+	timeout := time.After(time.Duration(rounds+3) * roundInterval)
+
+	for i := range plugins {
+		received[i] = make(map[uint64][]byte)
+
+		for len(received[i]) < rounds {
+			select {
+			case output := <-outputs[i]:
+				if output.Round < first || output.Round >= first+rounds {
+					continue
+				}
+
+				received[i][output.Round] = output.Randomness
+
+				if i == 0 {
+					timestamps[output.Round] = time.Now()
+				}
+			case <-timeout:
+				t.Fatalf("expected node %d to produce randomness for %d rounds, got %d", i, rounds, len(received[i]))
+			}
+		}
+	}
+
+	for round := first; round < first+rounds; round++ {
+		assert.Len(t, received[0][round], 32)
+
+		for i := 1; i < size; i++ {
+			assert.Equal(t, received[0][round], received[i][round], "expected node %d to produce the same randomness for round %d", i, round)
+		}
+
+		if round > first {
+			assert.NotEqual(t, received[0][round-1], received[0][round])
+
+			// The beacon fires once every round interval.
+			elapsed := timestamps[round].Sub(timestamps[round-1])
+			assert.InDelta(t, float64(roundInterval), float64(elapsed), float64(roundInterval/2))
+		}
+	}
+}
+
+func TestGenerateSharesInvalidThreshold(t *testing.T) {
+	t.Parallel()
+
+	_, _, err := drand.GenerateShares(0, 7, rand.Reader)
+	assert.NotNil(t, err)
+
+	_, _, err = drand.GenerateShares(8, 7, rand.Reader)
+	assert.NotNil(t, err)
+}
@@ -0,0 +1,368 @@
+package blockgossip
+
+import (
+	"context"
+	"math/rand"
+	"sync"
+	"time"
+
+	"github.com/perlin-network/noise/blockchain/internal/bloom"
+	"github.com/perlin-network/noise/crypto/blake2b"
+	"github.com/perlin-network/noise/dht"
+	"github.com/perlin-network/noise/internal/protobuf"
+	"github.com/perlin-network/noise/network"
+	"github.com/perlin-network/noise/network/discovery"
+	"github.com/perlin-network/noise/peer"
+	"github.com/perlin-network/noise/types/lru"
+
+	"github.com/pkg/errors"
+)
+
+const (
+	// DefaultMaxBlocks is the default number of blocks held, beyond which the oldest blocks
+	// are forgotten.
+	DefaultMaxBlocks = 1000
+	// DefaultRequestTimeout is the default time to wait for a peer to respond with a block.
+	DefaultRequestTimeout = 3 * time.Second
+	// DefaultMaxFetches is the default number of blocks requested from peers at once.
+	DefaultMaxFetches = 16
+	// DefaultMaxPeers is the default number of peers the blocks known to are tracked for.
+	DefaultMaxPeers = 1024
+)
+
+// ErrInvalidBlock returns if a block submitted to the node fails validation.
+var ErrInvalidBlock = errors.New("blockgossip: invalid block")
+
+type block struct {
+	height uint64
+	data   []byte
+}
+
+// knownBlocks is a bloom filter of the hashes of the blocks a peer is known to have. It is
+// cleared once it holds as many blocks as it is sized for.
+type knownBlocks struct {
+	filter *bloom.Filter
+	count  int
+}
+
+// Plugin propagates blocks throughout a network through compact block relay. Rather than
+// pushing blocks, which may be large, nodes announce the header of every block they
+// receive to every peer of the routing table which is not known to have it yet. Peers
+// lacking the block request it from the announcer, validate it, and announce it in turn.
+//
+// The blocks each peer is known to have are tracked through a bloom filter per peer, such
+// that a block may rarely not be announced to a peer which lacks it. Only announcements of
+// peers of the routing table are acted upon, and announcements received while MaxFetches
+// blocks are being requested are ignored.
+type Plugin struct {
+	*network.Plugin
+
+	// ValidateBlock returns true if a block is valid. All blocks are valid should it be nil.
+	ValidateBlock func(height uint64, block []byte) bool
+
+	// MaxBlocks is the number of blocks held (default: DefaultMaxBlocks).
+	MaxBlocks int
+	// RequestTimeout is the time to wait for a peer to respond with a block (default: DefaultRequestTimeout).
+	RequestTimeout time.Duration
+	// MaxFetches is the number of blocks requested from peers at once (default: DefaultMaxFetches).
+	MaxFetches int
+	// MaxPeers is the number of peers the blocks known to are tracked for, beyond which the
+	// least recently seen peer is forgotten (default: DefaultMaxPeers).
+	MaxPeers int
+
+	// Routes is the routing table blocks are announced to the peers of. The routing table of
+	// the discovery plugin is used should none be set.
+	Routes *dht.RoutingTable
+
+	net *network.Network
+
+	mutex    sync.Mutex
+	blocks   map[string]*block
+	order    []string            // block hashes, oldest first
+	known    *lru.Cache          // public key hex -> blocks known to the peer
+	fetching map[string]struct{} // hashes of blocks being requested
+}
+
+var (
+	PluginID                         = (*Plugin)(nil)
+	_        network.PluginInterface = (*Plugin)(nil)
+)
+
+func (state *Plugin) Startup(net *network.Network) {
+	state.net = net
+
+	state.mutex.Lock()
+	state.blocks = make(map[string]*block)
+	state.order = nil
+	state.known = lru.NewCache(state.maxPeers())
+	state.fetching = make(map[string]struct{})
+	state.mutex.Unlock()
+
+	if state.Routes == nil {
+		if plugin, ok := net.Plugin(discovery.PluginID); ok {
+			state.Routes = plugin.(*discovery.Plugin).Routes
+		}
+	}
+}
+
+func (state *Plugin) Receive(ctx *network.PluginContext) error {
+	sender := ctx.Sender()
+
+	switch msg := ctx.Message().(type) {
+	case *protobuf.BlockHeader:
+		if len(msg.Hash) == 0 || !state.routed(sender) {
+			return nil
+		}
+
+		state.markKnown(sender, string(msg.Hash))
+
+		if state.startFetching(string(msg.Hash)) {
+			go state.fetch(ctx.Client(), sender, msg)
+		}
+	case *protobuf.GetBlock:
+		response := &protobuf.Block{}
+
+		state.mutex.Lock()
+		if b, exists := state.blocks[string(msg.Hash)]; exists {
+			response.Height, response.Data = b.height, b.data
+		}
+		state.mutex.Unlock()
+
+		// Peers which are sent a block need not have it announced to them.
+		if len(response.Data) > 0 {
+			state.markKnown(sender, string(msg.Hash))
+		}
+
+		return ctx.Reply(context.Background(), response)
+	}
+
+	return nil
+}
+
+// Submit validates a block, and announces it should it be valid.
+func (state *Plugin) Submit(height uint64, data []byte) error {
+	if !state.validate(height, data) {
+		return ErrInvalidBlock
+	}
+
+	h := string(Hash(data))
+
+	if state.add(h, height, data) {
+		state.announce(h)
+	}
+
+	return nil
+}
+
+// Has returns true if the node holds a block.
+func (state *Plugin) Has(data []byte) bool {
+	state.mutex.Lock()
+	defer state.mutex.Unlock()
+
+	_, exists := state.blocks[string(Hash(data))]
+	return exists
+}
+
+// Block returns the height and data of a block held by the node, given its hash.
+func (state *Plugin) Block(h []byte) (uint64, []byte, bool) {
+	state.mutex.Lock()
+	defer state.mutex.Unlock()
+
+	b, exists := state.blocks[string(h)]
+	if !exists {
+		return 0, nil, false
+	}
+	return b.height, b.data, true
+}
+
+// startFetching marks a block as being requested. Returns false should the block already
+// be held or requested, or should MaxFetches blocks already be requested.
+func (state *Plugin) startFetching(h string) bool {
+	state.mutex.Lock()
+	defer state.mutex.Unlock()
+
+	if _, exists := state.blocks[h]; exists {
+		return false
+	}
+
+	if _, fetching := state.fetching[h]; fetching {
+		return false
+	}
+
+	if len(state.fetching) >= state.maxFetches() {
+		return false
+	}
+
+	state.fetching[h] = struct{}{}
+	return true
+}
+
+// fetch requests a block announced by a peer, and announces it in turn should it be valid.
+// The block may be requested again upon being announced by another peer should the
+// request fail.
+func (state *Plugin) fetch(client *network.PeerClient, sender peer.ID, header *protobuf.BlockHeader) {
+	h := string(header.Hash)
+
+	defer func() {
+		state.mutex.Lock()
+		delete(state.fetching, h)
+		state.mutex.Unlock()
+	}()
+
+	ctx, cancel := context.WithTimeout(context.Background(), state.requestTimeout())
+	defer cancel()
+
+	logger := state.net.PluginLogger(state)
+
+	res, err := client.Request(ctx, &protobuf.GetBlock{Hash: header.Hash})
+	if err != nil {
+		logger.Debug().Err(err).Str("peer_address", sender.Address).Msg("Failed to request an announced block.")
+		return
+	}
+
+	response, ok := res.(*protobuf.Block)
+	if !ok || len(response.Data) == 0 {
+		return
+	}
+
+	if string(Hash(response.Data)) != h || !state.validate(response.Height, response.Data) {
+		logger.Debug().Str("peer_address", sender.Address).Msg("Received an invalid block.")
+		return
+	}
+
+	if state.add(h, response.Height, response.Data) {
+		state.announce(h)
+	}
+}
+
+// add stores a block. The oldest block is forgotten should too many be held. Returns false
+// should the block already be held.
+func (state *Plugin) add(h string, height uint64, data []byte) bool {
+	state.mutex.Lock()
+	defer state.mutex.Unlock()
+
+	if _, exists := state.blocks[h]; exists {
+		return false
+	}
+
+	state.blocks[h] = &block{height: height, data: data}
+	state.order = append(state.order, h)
+
+	if len(state.order) > state.maxBlocks() {
+		delete(state.blocks, state.order[0])
+		state.order = state.order[1:]
+	}
+
+	return true
+}
+
+// announce sends the header of a block to every peer of the routing table which is not
+// known to have it.
+func (state *Plugin) announce(h string) {
+	state.mutex.Lock()
+	b, exists := state.blocks[h]
+	state.mutex.Unlock()
+
+	if !exists {
+		return
+	}
+
+	header := &protobuf.BlockHeader{Hash: []byte(h), Height: b.height}
+
+	for _, id := range state.peers() {
+		if !state.markKnown(id, h) {
+			continue
+		}
+
+		client, err := state.net.Client(id.Address)
+		if err != nil {
+			continue
+		}
+
+		client.Tell(context.Background(), header)
+	}
+}
+
+// markKnown marks a block as known to a peer. Returns false should the peer already be
+// known to have it.
+func (state *Plugin) markKnown(id peer.ID, h string) bool {
+	key := id.PublicKeyHex()
+
+	state.mutex.Lock()
+	defer state.mutex.Unlock()
+
+	value, _ := state.known.Get(key, func() (interface{}, error) {
+		return new(knownBlocks), nil
+	})
+
+	known := value.(*knownBlocks)
+	if known.filter == nil || known.count >= state.maxBlocks() {
+		known.filter, known.count = bloom.New(state.maxBlocks(), rand.Uint64()), 0
+	}
+
+	if known.filter.Contains([]byte(h)) {
+		return false
+	}
+
+	known.filter.Add([]byte(h))
+	known.count++
+
+	return true
+}
+
+// peers returns the peers of the routing table, excluding the node itself.
+func (state *Plugin) peers() []peer.ID {
+	if state.Routes == nil {
+		return nil
+	}
+
+	var peers []peer.ID
+	for _, id := range state.Routes.GetPeers() {
+		if !id.Equals(state.net.SelfID()) {
+			peers = append(peers, id)
+		}
+	}
+	return peers
+}
+
+// routed returns true if a peer is of the routing table.
+func (state *Plugin) routed(id peer.ID) bool {
+	return state.Routes != nil && state.Routes.PeerExists(id)
+}
+
+func (state *Plugin) validate(height uint64, data []byte) bool {
+	return len(data) > 0 && (state.ValidateBlock == nil || state.ValidateBlock(height, data))
+}
+
+func (state *Plugin) maxBlocks() int {
+	if state.MaxBlocks > 0 {
+		return state.MaxBlocks
+	}
+	return DefaultMaxBlocks
+}
+
+func (state *Plugin) maxFetches() int {
+	if state.MaxFetches > 0 {
+		return state.MaxFetches
+	}
+	return DefaultMaxFetches
+}
+
+func (state *Plugin) maxPeers() int {
+	if state.MaxPeers > 0 {
+		return state.MaxPeers
+	}
+	return DefaultMaxPeers
+}
+
+func (state *Plugin) requestTimeout() time.Duration {
+	if state.RequestTimeout > 0 {
+		return state.RequestTimeout
+	}
+	return DefaultRequestTimeout
+}
+
+// Hash returns the hash blocks are identified by.
+func Hash(data []byte) []byte {
+	return blake2b.New().HashBytes(data)
+}
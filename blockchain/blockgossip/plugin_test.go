@@ -0,0 +1,101 @@
+package blockgossip_test
+
+import (
+	"bytes"
+	"crypto/rand"
+	"testing"
+	"time"
+
+	"github.com/perlin-network/noise/blockchain/blockgossip"
+	"github.com/perlin-network/noise/dht"
+	"github.com/perlin-network/noise/network"
+	"github.com/perlin-network/noise/testutil"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBlockPropagation(t *testing.T) {
+	t.Parallel()
+
+	const size = 5
+
+	plugins := make([]*blockgossip.Plugin, size)
+
+	validate := func(height uint64, block []byte) bool {
+		return height > 0
+	}
+
+	cluster, err := testutil.RingTopology(size, testutil.WithSetup(func(i int, builder *network.Builder) {
+		plugins[i] = &blockgossip.Plugin{ValidateBlock: validate}
+		builder.AddPlugin(plugins[i])
+	}))
+	assert.Nil(t, err)
+	defer cluster.Stop()
+
+	block := make([]byte, 1<<20)
+	_, err = rand.Read(block)
+	assert.Nil(t, err)
+
+	assert.Equal(t, blockgossip.ErrInvalidBlock, plugins[0].Submit(0, block))
+	assert.Nil(t, plugins[0].Submit(1, block))
+
+	// Nodes opposite of the announcer in the ring are two hops away from it.
+	deadline := time.Now().Add(2 * time.Second)
+	for i := 0; i < size; i++ {
+		for !plugins[i].Has(block) {
+			if time.Now().After(deadline) {
+				t.Fatalf("expected node %d to receive the block within 2 seconds", i)
+			}
+			time.Sleep(10 * time.Millisecond)
+		}
+
+		height, data, ok := plugins[i].Block(blockgossip.Hash(block))
+		assert.True(t, ok)
+		assert.Equal(t, uint64(1), height)
+		assert.True(t, bytes.Equal(block, data), "expected node %d to hold the block as submitted", i)
+	}
+}
+
+func TestUnroutedAnnouncements(t *testing.T) {
+	t.Parallel()
+
+	plugins := make([]*blockgossip.Plugin, 2)
+
+	cluster, err := testutil.NewCluster(2, testutil.WithSetup(func(i int, builder *network.Builder) {
+		plugins[i] = new(blockgossip.Plugin)
+		builder.AddPlugin(plugins[i])
+	}))
+	assert.Nil(t, err)
+	defer cluster.Stop()
+
+	nodes := cluster.Nodes()
+
+	// The announcer is not of the routing table of the node it announces blocks to.
+	routes := dht.CreateRoutingTable(nodes[1].ID)
+	plugins[1].Routes = routes
+
+	block := make([]byte, 1024)
+	_, err = rand.Read(block)
+	assert.Nil(t, err)
+
+	assert.Nil(t, plugins[0].Submit(1, block))
+
+	time.Sleep(500 * time.Millisecond)
+	assert.False(t, plugins[1].Has(block), "expected blocks announced by peers which are not routed to not be requested")
+
+	routes.Update(nodes[0].ID)
+
+	block = make([]byte, 1024)
+	_, err = rand.Read(block)
+	assert.Nil(t, err)
+
+	assert.Nil(t, plugins[0].Submit(1, block))
+
+	deadline := time.Now().Add(2 * time.Second)
+	for !plugins[1].Has(block) {
+		if time.Now().After(deadline) {
+			t.Fatal("expected blocks announced by routed peers to be requested")
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
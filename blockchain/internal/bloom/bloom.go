@@ -0,0 +1,97 @@
+// Package bloom implements bloom filters of hashes, as exchanged in between peers to
+// reconcile the items they hold.
+package bloom
+
+import (
+	"crypto/sha256"
+	"encoding/binary"
+	"math"
+
+	"github.com/perlin-network/noise/internal/protobuf"
+)
+
+const (
+	// falsePositiveRate is the false positive rate bloom filters are sized for.
+	falsePositiveRate = 0.01
+
+	minFilterBits = 64
+	maxHashes     = 16
+)
+
+// Filter is a probabilistic set of hashes, which may report hashes it does not hold but
+// never misses a hash it holds.
+type Filter struct {
+	bits   []byte
+	hashes uint32
+	seed   uint64
+}
+
+// New instantiates a bloom filter sized to hold n hashes at the false positive rate.
+func New(n int, seed uint64) *Filter {
+	if n < 1 {
+		n = 1
+	}
+
+	m := int(math.Ceil(-float64(n) * math.Log(falsePositiveRate) / (math.Ln2 * math.Ln2)))
+	if m < minFilterBits {
+		m = minFilterBits
+	}
+
+	k := uint32(math.Round(float64(m) / float64(n) * math.Ln2))
+	if k < 1 {
+		k = 1
+	}
+
+	return &Filter{bits: make([]byte, (m+7)/8), hashes: k, seed: seed}
+}
+
+// FromProto reconstructs a bloom filter received from a peer. Returns nil should the filter
+// be malformed.
+func FromProto(msg *protobuf.BloomFilter) *Filter {
+	if msg == nil || len(msg.Bits) == 0 || msg.Hashes == 0 || msg.Hashes > maxHashes {
+		return nil
+	}
+	return &Filter{bits: msg.Bits, hashes: msg.Hashes, seed: msg.Seed}
+}
+
+// Proto returns the bloom filter as sent to peers.
+func (f *Filter) Proto() *protobuf.BloomFilter {
+	return &protobuf.BloomFilter{Bits: f.bits, Hashes: f.hashes, Seed: f.seed}
+}
+
+// Add adds a hash to the bloom filter.
+func (f *Filter) Add(hash []byte) {
+	f.positions(hash, func(pos uint64) bool {
+		f.bits[pos/8] |= 1 << (pos % 8)
+		return true
+	})
+}
+
+// Contains returns true should the bloom filter possibly hold a hash.
+func (f *Filter) Contains(hash []byte) bool {
+	return f.positions(hash, func(pos uint64) bool {
+		return f.bits[pos/8]&(1<<(pos%8)) != 0
+	})
+}
+
+// positions calls fn with each bit position a hash maps to, through double hashing of the
+// salted hash. Returns false as soon as fn does.
+func (f *Filter) positions(hash []byte, fn func(pos uint64) bool) bool {
+	var seed [8]byte
+	binary.BigEndian.PutUint64(seed[:], f.seed)
+
+	digest := sha256.Sum256(append(seed[:], hash...))
+
+	h1 := binary.BigEndian.Uint64(digest[0:8])
+	h2 := binary.BigEndian.Uint64(digest[8:16])
+
+	m := uint64(len(f.bits)) * 8
+
+	for i := uint64(0); i < uint64(f.hashes); i++ {
+		if !fn((h1 + i*h2) % m) {
+			return false
+		}
+	}
+
+	return true
+}
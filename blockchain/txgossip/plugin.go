@@ -0,0 +1,378 @@
+package txgossip
+
+import (
+	"context"
+	"math/rand"
+	"sync"
+	"time"
+
+	"github.com/perlin-network/noise/blockchain/internal/bloom"
+	"github.com/perlin-network/noise/crypto/blake2b"
+	"github.com/perlin-network/noise/dht"
+	"github.com/perlin-network/noise/internal/protobuf"
+	"github.com/perlin-network/noise/network"
+	"github.com/perlin-network/noise/network/discovery"
+	"github.com/perlin-network/noise/peer"
+
+	"github.com/pkg/errors"
+)
+
+const (
+	// DefaultSyncInterval is the default interval in between reconciliations with a random peer.
+	DefaultSyncInterval = 1 * time.Second
+	// DefaultMaxTransactions is the default number of transactions held, beyond which the
+	// oldest transactions are forgotten.
+	DefaultMaxTransactions = 10000
+)
+
+// ErrInvalidTx returns if a transaction submitted to the node fails validation.
+var ErrInvalidTx = errors.New("txgossip: invalid transaction")
+
+type transaction struct {
+	tx []byte
+
+	// seenBy holds the public key hex of every peer known to have the transaction.
+	seenBy map[string]struct{}
+}
+
+// Plugin propagates transactions throughout a network. Transactions are validated upon being
+// received, and valid transactions are pushed to every peer of the routing table which is not
+// known to have them yet.
+//
+// As pushes may be lost, nodes periodically reconcile their transactions with a random peer
+// by exchanging bloom filters of the hashes of the transactions they know of, in turn pulling
+// the transactions they are missing and pushing the transactions the peer is missing.
+type Plugin struct {
+	*network.Plugin
+
+	// ValidateTx returns true if a transaction is valid. All transactions are valid should it be nil.
+	ValidateTx func(tx []byte) bool
+
+	// SyncInterval is the interval in between reconciliations (default: DefaultSyncInterval).
+	SyncInterval time.Duration
+	// MaxTransactions is the number of transactions held (default: DefaultMaxTransactions).
+	MaxTransactions int
+
+	// Routes is the routing table transactions are pushed to the peers of. The routing table
+	// of the discovery plugin is used should none be set.
+	Routes *dht.RoutingTable
+
+	net *network.Network
+
+	mutex sync.Mutex
+	txs   map[string]*transaction
+	order []string // transaction hashes, oldest first
+
+	stop chan struct{}
+}
+
+var (
+	PluginID                         = (*Plugin)(nil)
+	_        network.PluginInterface = (*Plugin)(nil)
+)
+
+func (state *Plugin) Startup(net *network.Network) {
+	state.net = net
+
+	state.mutex.Lock()
+	state.txs = make(map[string]*transaction)
+	state.order = nil
+	state.mutex.Unlock()
+
+	if state.Routes == nil {
+		if plugin, ok := net.Plugin(discovery.PluginID); ok {
+			state.Routes = plugin.(*discovery.Plugin).Routes
+		}
+	}
+
+	state.stop = make(chan struct{})
+	go state.syncLoop(state.stop)
+}
+
+func (state *Plugin) Cleanup(net *network.Network) {
+	if state.stop != nil {
+		close(state.stop)
+		state.stop = nil
+	}
+}
+
+func (state *Plugin) Receive(ctx *network.PluginContext) error {
+	sender := ctx.Sender()
+
+	switch msg := ctx.Message().(type) {
+	case *protobuf.Transactions:
+		state.receive(msg.Txs, sender)
+	case *protobuf.TxSyncRequest:
+		filter := bloom.FromProto(msg.Filter)
+		if filter == nil {
+			return nil
+		}
+
+		return ctx.Reply(context.Background(), &protobuf.TxSyncResponse{
+			Txs:    state.missing(filter, sender),
+			Filter: state.filter().Proto(),
+		})
+	}
+
+	return nil
+}
+
+// Submit validates a transaction, and gossips it should it be valid.
+func (state *Plugin) Submit(tx []byte) error {
+	if !state.validate(tx) {
+		return ErrInvalidTx
+	}
+
+	if state.add(tx, nil) {
+		state.push([]string{string(hash(tx))})
+	}
+
+	return nil
+}
+
+// Has returns true if the node holds a transaction.
+func (state *Plugin) Has(tx []byte) bool {
+	state.mutex.Lock()
+	defer state.mutex.Unlock()
+
+	_, exists := state.txs[string(hash(tx))]
+	return exists
+}
+
+// Transactions returns all transactions held by the node, oldest first.
+func (state *Plugin) Transactions() [][]byte {
+	state.mutex.Lock()
+	defer state.mutex.Unlock()
+
+	txs := make([][]byte, 0, len(state.order))
+	for _, h := range state.order {
+		txs = append(txs, state.txs[h].tx)
+	}
+	return txs
+}
+
+// receive validates transactions received from a peer, and gossips those which are valid
+// and were not held by the node yet.
+func (state *Plugin) receive(txs [][]byte, sender peer.ID) {
+	var added []string
+
+	for _, tx := range txs {
+		if state.seen(tx, sender) {
+			continue
+		}
+
+		if !state.validate(tx) {
+			logger := state.net.PluginLogger(state)
+			logger.Debug().Str("peer_address", sender.Address).Msg("Received an invalid transaction.")
+			continue
+		}
+
+		if state.add(tx, &sender) {
+			added = append(added, string(hash(tx)))
+		}
+	}
+
+	if len(added) > 0 {
+		state.push(added)
+	}
+}
+
+// seen marks a transaction as seen by a peer, and returns true should the node already hold it.
+func (state *Plugin) seen(tx []byte, id peer.ID) bool {
+	state.mutex.Lock()
+	defer state.mutex.Unlock()
+
+	t, exists := state.txs[string(hash(tx))]
+	if exists {
+		t.seenBy[id.PublicKeyHex()] = struct{}{}
+	}
+
+	return exists
+}
+
+// add stores a transaction, optionally marking it as seen by the peer it was received from.
+// The oldest transaction is forgotten should too many be held. Returns false should the
+// transaction already be held.
+func (state *Plugin) add(tx []byte, from *peer.ID) bool {
+	h := string(hash(tx))
+
+	state.mutex.Lock()
+	defer state.mutex.Unlock()
+
+	if _, exists := state.txs[h]; exists {
+		return false
+	}
+
+	t := &transaction{tx: tx, seenBy: make(map[string]struct{})}
+	if from != nil {
+		t.seenBy[from.PublicKeyHex()] = struct{}{}
+	}
+
+	state.txs[h] = t
+	state.order = append(state.order, h)
+
+	if len(state.order) > state.maxTransactions() {
+		delete(state.txs, state.order[0])
+		state.order = state.order[1:]
+	}
+
+	return true
+}
+
+// push sends every peer of the routing table those of a set of transactions, denoted by
+// their hashes, which it is not known to have.
+func (state *Plugin) push(hashes []string) {
+	for _, id := range state.peers() {
+		key := id.PublicKeyHex()
+
+		var txs [][]byte
+
+		state.mutex.Lock()
+		for _, h := range hashes {
+			t, exists := state.txs[h]
+			if !exists {
+				continue
+			}
+
+			if _, seen := t.seenBy[key]; !seen {
+				t.seenBy[key] = struct{}{}
+				txs = append(txs, t.tx)
+			}
+		}
+		state.mutex.Unlock()
+
+		if len(txs) == 0 {
+			continue
+		}
+
+		client, err := state.net.Client(id.Address)
+		if err != nil {
+			continue
+		}
+
+		client.Tell(context.Background(), &protobuf.Transactions{Txs: txs})
+	}
+}
+
+// syncLoop reconciles transactions with a random peer every sync interval, as timed by the
+// networks clock.
+func (state *Plugin) syncLoop(stop chan struct{}) {
+	for {
+		select {
+		case <-stop:
+			return
+		case <-state.net.Clock().After(state.syncInterval()):
+		}
+
+		peers := state.peers()
+		if len(peers) == 0 {
+			continue
+		}
+
+		state.sync(peers[rand.Intn(len(peers))])
+	}
+}
+
+// sync sends a peer a bloom filter of the transactions known to the node, receiving the
+// transactions the node is missing and a bloom filter of the transactions known to the peer.
+// The transactions the peer is missing are then pushed to it.
+func (state *Plugin) sync(id peer.ID) {
+	client, err := state.net.Client(id.Address)
+	if err != nil {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), state.syncInterval())
+	defer cancel()
+
+	res, err := client.Request(ctx, &protobuf.TxSyncRequest{Filter: state.filter().Proto()})
+	if err != nil {
+		return
+	}
+
+	response, ok := res.(*protobuf.TxSyncResponse)
+	if !ok {
+		return
+	}
+
+	state.receive(response.Txs, id)
+
+	if filter := bloom.FromProto(response.Filter); filter != nil {
+		if txs := state.missing(filter, id); len(txs) > 0 {
+			client.Tell(context.Background(), &protobuf.Transactions{Txs: txs})
+		}
+	}
+}
+
+// filter returns a freshly seeded bloom filter of the hashes of all transactions held.
+func (state *Plugin) filter() *bloom.Filter {
+	state.mutex.Lock()
+	defer state.mutex.Unlock()
+
+	filter := bloom.New(len(state.order), rand.Uint64())
+	for _, h := range state.order {
+		filter.Add([]byte(h))
+	}
+
+	return filter
+}
+
+// missing returns the transactions held which are missing from a peers bloom filter. All
+// transactions are marked as seen by the peer, as those missing are about to be sent to it.
+func (state *Plugin) missing(filter *bloom.Filter, id peer.ID) [][]byte {
+	key := id.PublicKeyHex()
+
+	state.mutex.Lock()
+	defer state.mutex.Unlock()
+
+	var txs [][]byte
+
+	for _, h := range state.order {
+		t := state.txs[h]
+
+		if !filter.Contains([]byte(h)) {
+			txs = append(txs, t.tx)
+		}
+		t.seenBy[key] = struct{}{}
+	}
+
+	return txs
+}
+
+// peers returns the peers of the routing table, excluding the node itself.
+func (state *Plugin) peers() []peer.ID {
+	if state.Routes == nil {
+		return nil
+	}
+
+	var peers []peer.ID
+	for _, id := range state.Routes.GetPeers() {
+		if !id.Equals(state.net.SelfID()) {
+			peers = append(peers, id)
+		}
+	}
+	return peers
+}
+
+func (state *Plugin) validate(tx []byte) bool {
+	return len(tx) > 0 && (state.ValidateTx == nil || state.ValidateTx(tx))
+}
+
+func (state *Plugin) syncInterval() time.Duration {
+	if state.SyncInterval > 0 {
+		return state.SyncInterval
+	}
+	return DefaultSyncInterval
+}
+
+func (state *Plugin) maxTransactions() int {
+	if state.MaxTransactions > 0 {
+		return state.MaxTransactions
+	}
+	return DefaultMaxTransactions
+}
+
+// hash returns the hash transactions are identified by.
+func hash(tx []byte) []byte {
+	return blake2b.New().HashBytes(tx)
+}
@@ -0,0 +1,82 @@
+package txgossip_test
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"github.com/perlin-network/noise/blockchain/txgossip"
+	"github.com/perlin-network/noise/network"
+	"github.com/perlin-network/noise/testutil"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTransactionGossip(t *testing.T) {
+	t.Parallel()
+
+	const size = 6
+
+	plugins := make([]*txgossip.Plugin, size)
+
+	validate := func(tx []byte) bool {
+		return bytes.HasPrefix(tx, []byte("valid"))
+	}
+
+	cluster, err := testutil.NewCluster(size, testutil.WithSetup(func(i int, builder *network.Builder) {
+		plugins[i] = &txgossip.Plugin{ValidateTx: validate, SyncInterval: 200 * time.Millisecond}
+		builder.AddPlugin(plugins[i])
+	}))
+	assert.Nil(t, err)
+	defer cluster.Stop()
+
+	assert.Equal(t, txgossip.ErrInvalidTx, plugins[0].Submit([]byte("invalid")))
+
+	tx := []byte("valid transaction")
+	assert.Nil(t, plugins[0].Submit(tx))
+
+	deadline := time.Now().Add(5 * time.Second)
+	for i := 0; i < size; i++ {
+		for !plugins[i].Has(tx) {
+			if time.Now().After(deadline) {
+				t.Fatalf("expected node %d to receive the transaction within 5 seconds", i)
+			}
+			time.Sleep(50 * time.Millisecond)
+		}
+
+		assert.Equal(t, [][]byte{tx}, plugins[i].Transactions())
+	}
+}
+
+func TestTransactionReconciliation(t *testing.T) {
+	t.Parallel()
+
+	const size = 6
+
+	plugins := make([]*txgossip.Plugin, size)
+
+	cluster, err := testutil.NewCluster(size, testutil.WithSetup(func(i int, builder *network.Builder) {
+		plugins[i] = &txgossip.Plugin{SyncInterval: 100 * time.Millisecond}
+		builder.AddPlugin(plugins[i])
+	}))
+	assert.Nil(t, err)
+	defer cluster.Stop()
+
+	// Cut the last node off while transactions are pushed, such that it may only learn of
+	// them by reconciling with its peers once reconnected.
+	cluster.Partition([]int{0, 1, 2, 3, 4}, []int{size - 1})
+
+	for i := 0; i < 10; i++ {
+		assert.Nil(t, plugins[i%(size-1)].Submit([]byte{byte(i) + 1}))
+	}
+
+	cluster.Heal()
+
+	deadline := time.Now().Add(5 * time.Second)
+	for len(plugins[size-1].Transactions()) < 10 {
+		if time.Now().After(deadline) {
+			t.Fatalf("expected the reconnected node to reconcile 10 transactions, got %d", len(plugins[size-1].Transactions()))
+		}
+		time.Sleep(50 * time.Millisecond)
+	}
+}
@@ -0,0 +1,233 @@
+// Package clock abstracts away the passage of time, such that timeouts and periodic tasks
+// may be tested deterministically with a FakeClock.
+package clock
+
+import (
+	"sort"
+	"sync"
+	"time"
+)
+
+var (
+	_ Clock = RealClock{}
+	_ Clock = (*FakeClock)(nil)
+)
+
+// Clock tells the current time, and waits for time to pass.
+type Clock interface {
+	Now() time.Time
+	Sleep(d time.Duration)
+	After(d time.Duration) <-chan time.Time
+	NewTicker(d time.Duration) Ticker
+}
+
+// Ticker periodically sends the current time on a channel until it is stopped.
+type Ticker interface {
+	C() <-chan time.Time
+	Stop()
+}
+
+// RealClock is a Clock backed by the time package.
+type RealClock struct{}
+
+// Now returns the current local time.
+func (RealClock) Now() time.Time {
+	return time.Now()
+}
+
+// Sleep pauses the current goroutine for at least a duration.
+func (RealClock) Sleep(d time.Duration) {
+	time.Sleep(d)
+}
+
+// After waits for a duration to elapse, and sends the current time on the returned channel.
+func (RealClock) After(d time.Duration) <-chan time.Time {
+	return time.After(d)
+}
+
+// NewTicker returns a ticker backed by a time.Ticker which ticks every period.
+func (RealClock) NewTicker(d time.Duration) Ticker {
+	return realTicker{time.NewTicker(d)}
+}
+
+type realTicker struct {
+	ticker *time.Ticker
+}
+
+func (t realTicker) C() <-chan time.Time {
+	return t.ticker.C
+}
+
+func (t realTicker) Stop() {
+	t.ticker.Stop()
+}
+
+// FakeClock is a Clock whose time only passes when advanced with Advance.
+type FakeClock struct {
+	mutex   sync.Mutex
+	now     time.Time
+	waiters []*fakeWaiter
+
+	// waitersChanged is closed and replaced whenever the set of waiters changes.
+	waitersChanged chan struct{}
+}
+
+type fakeWaiter struct {
+	deadline time.Time
+	c        chan time.Time
+
+	// period is non-zero for tickers, which are re-armed rather than removed once fired.
+	period time.Duration
+}
+
+// NewFakeClock instantiates a fake clock starting at a given time.
+func NewFakeClock(now time.Time) *FakeClock {
+	return &FakeClock{now: now, waitersChanged: make(chan struct{})}
+}
+
+// Now returns the current time of the fake clock.
+func (c *FakeClock) Now() time.Time {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	return c.now
+}
+
+// Sleep blocks until the fake clock is advanced by at least a duration.
+func (c *FakeClock) Sleep(d time.Duration) {
+	<-c.After(d)
+}
+
+// After returns a channel which receives the time of the fake clock once it has been
+// advanced by at least a duration.
+func (c *FakeClock) After(d time.Duration) <-chan time.Time {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	ch := make(chan time.Time, 1)
+
+	if d <= 0 {
+		ch <- c.now
+		return ch
+	}
+
+	c.waiters = append(c.waiters, &fakeWaiter{deadline: c.now.Add(d), c: ch})
+	c.notifyWaitersChanged()
+
+	return ch
+}
+
+// NewTicker returns a ticker which ticks every period the fake clock is advanced by. Like
+// a time.Ticker, ticks are dropped should the receiver of the tickers channel fall behind.
+func (c *FakeClock) NewTicker(d time.Duration) Ticker {
+	if d <= 0 {
+		panic("clock: non-positive interval for NewTicker")
+	}
+
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	waiter := &fakeWaiter{deadline: c.now.Add(d), c: make(chan time.Time, 1), period: d}
+
+	c.waiters = append(c.waiters, waiter)
+	c.notifyWaitersChanged()
+
+	return &fakeTicker{clock: c, waiter: waiter}
+}
+
+type fakeTicker struct {
+	clock  *FakeClock
+	waiter *fakeWaiter
+}
+
+func (t *fakeTicker) C() <-chan time.Time {
+	return t.waiter.c
+}
+
+func (t *fakeTicker) Stop() {
+	t.clock.mutex.Lock()
+	defer t.clock.mutex.Unlock()
+
+	for i, waiter := range t.clock.waiters {
+		if waiter == t.waiter {
+			t.clock.waiters = append(t.clock.waiters[:i], t.clock.waiters[i+1:]...)
+			t.clock.notifyWaitersChanged()
+			return
+		}
+	}
+}
+
+// Advance moves the fake clock forward by a duration, waking up all goroutines waiting
+// for the clock to reach a time up until the new time of the clock, in order.
+func (c *FakeClock) Advance(d time.Duration) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	c.now = c.now.Add(d)
+
+	sort.SliceStable(c.waiters, func(i, j int) bool {
+		return c.waiters[i].deadline.Before(c.waiters[j].deadline)
+	})
+
+	pending := c.waiters[:0]
+	fired := false
+
+	for _, waiter := range c.waiters {
+		if waiter.deadline.After(c.now) {
+			pending = append(pending, waiter)
+			continue
+		}
+
+		if waiter.period == 0 {
+			waiter.c <- c.now
+			fired = true
+			continue
+		}
+
+		select {
+		case waiter.c <- c.now:
+		default:
+		}
+
+		for !waiter.deadline.After(c.now) {
+			waiter.deadline = waiter.deadline.Add(waiter.period)
+		}
+		pending = append(pending, waiter)
+	}
+
+	c.waiters = pending
+
+	if fired {
+		c.notifyWaitersChanged()
+	}
+}
+
+// Waiters returns the number of pending calls to Sleep, channels returned by After which
+// have not yet fired, and tickers which have not been stopped.
+func (c *FakeClock) Waiters() int {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	return len(c.waiters)
+}
+
+// BlockUntil blocks until at least a given number of goroutines are waiting on the fake
+// clock, such that the clock may be advanced without racing against them.
+func (c *FakeClock) BlockUntil(n int) {
+	for {
+		c.mutex.Lock()
+		waiters, changed := len(c.waiters), c.waitersChanged
+		c.mutex.Unlock()
+
+		if waiters >= n {
+			return
+		}
+
+		<-changed
+	}
+}
+
+func (c *FakeClock) notifyWaitersChanged() {
+	close(c.waitersChanged)
+	c.waitersChanged = make(chan struct{})
+}
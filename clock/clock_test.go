@@ -0,0 +1,110 @@
+package clock
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFakeClockAdvance(t *testing.T) {
+	t.Parallel()
+
+	start := time.Date(2018, 1, 1, 0, 0, 0, 0, time.UTC)
+	clock := NewFakeClock(start)
+
+	early, late := clock.After(time.Second), clock.After(time.Minute)
+	assert.Equal(t, 2, clock.Waiters())
+
+	clock.Advance(30 * time.Second)
+	assert.Equal(t, start.Add(30*time.Second), clock.Now())
+
+	select {
+	case now := <-early:
+		assert.Equal(t, start.Add(30*time.Second), now)
+	default:
+		t.Fatal("expected timer within the advanced duration to fire")
+	}
+
+	select {
+	case <-late:
+		t.Fatal("expected timer beyond the advanced duration to not fire")
+	default:
+	}
+
+	clock.Advance(30 * time.Second)
+	assert.Equal(t, start.Add(time.Minute), <-late)
+	assert.Equal(t, 0, clock.Waiters())
+
+	select {
+	case <-clock.After(0):
+	default:
+		t.Fatal("expected timer of zero duration to fire immediately")
+	}
+}
+
+func TestFakeClockSleep(t *testing.T) {
+	t.Parallel()
+
+	clock := NewFakeClock(time.Now())
+
+	woken := make(chan struct{})
+	go func() {
+		clock.Sleep(time.Hour)
+		close(woken)
+	}()
+
+	clock.BlockUntil(1)
+	clock.Advance(time.Hour)
+
+	select {
+	case <-woken:
+	case <-time.After(time.Second):
+		t.Fatal("expected sleeping goroutine to be woken up")
+	}
+}
+
+func TestFakeClockTicker(t *testing.T) {
+	t.Parallel()
+
+	start := time.Date(2018, 1, 1, 0, 0, 0, 0, time.UTC)
+	clock := NewFakeClock(start)
+
+	ticker := clock.NewTicker(time.Minute)
+	assert.Equal(t, 1, clock.Waiters())
+
+	clock.Advance(30 * time.Second)
+
+	select {
+	case <-ticker.C():
+		t.Fatal("expected ticker to not tick before its period elapsed")
+	default:
+	}
+
+	clock.Advance(30 * time.Second)
+	assert.Equal(t, start.Add(time.Minute), <-ticker.C())
+	assert.Equal(t, 1, clock.Waiters(), "expected ticker to be re-armed rather than registering a new waiter")
+
+	clock.Advance(3 * time.Minute)
+	assert.Equal(t, start.Add(4*time.Minute), <-ticker.C())
+
+	select {
+	case <-ticker.C():
+		t.Fatal("expected ticks to be dropped while the receiver falls behind")
+	default:
+	}
+
+	clock.Advance(time.Minute)
+	assert.Equal(t, start.Add(5*time.Minute), <-ticker.C())
+
+	ticker.Stop()
+	assert.Equal(t, 0, clock.Waiters())
+
+	clock.Advance(time.Hour)
+
+	select {
+	case <-ticker.C():
+		t.Fatal("expected stopped ticker to not tick")
+	default:
+	}
+}
@@ -0,0 +1,199 @@
+package vivaldi
+
+import (
+	"math"
+	"math/rand"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/perlin-network/noise/peer"
+)
+
+const (
+	// Dimensions is the number of euclidean dimensions of a coordinate. Coordinates are
+	// additionally suffixed with a height, which models the latency of a nodes access link.
+	Dimensions = 2
+
+	// ce tunes how quickly the local error estimate adapts to new samples.
+	ce = 0.25
+	// cc tunes how far the local coordinate moves per sample.
+	cc = 0.25
+
+	// maxError is the error estimate of a node which has yet to take any samples.
+	maxError = 1.5
+	// minHeight is the smallest height a coordinate may have, in seconds.
+	minHeight = 10e-6
+	// zeroThreshold is the distance below which two coordinates are considered to overlap.
+	zeroThreshold = 1e-6
+
+	// filterSize is the number of RTT samples per peer whose median is used to update the
+	// local coordinate, such that outliers do not disturb the coordinate.
+	filterSize = 3
+)
+
+// Vivaldi maintains the network coordinate of a node, whose distance to the coordinate
+// of another node predicts the round-trip time between both nodes. Coordinates and RTTs
+// are in seconds.
+type Vivaldi struct {
+	mutex sync.Mutex
+
+	coords []float64
+	error  float64
+
+	// samples holds the most recent RTT samples per peer public key hash.
+	samples map[string][]float64
+}
+
+// New instantiates a new coordinate at the origin with a maximum error estimate.
+func New() *Vivaldi {
+	return &Vivaldi{
+		coords:  make([]float64, Dimensions+1),
+		error:   maxError,
+		samples: make(map[string][]float64),
+	}
+}
+
+// Coordinates returns a copy of the local coordinate, which is suffixed with its height.
+func (v *Vivaldi) Coordinates() []float64 {
+	v.mutex.Lock()
+	defer v.mutex.Unlock()
+
+	return append([]float64(nil), v.coords...)
+}
+
+// Error returns the relative error estimate of the local coordinate.
+func (v *Vivaldi) Error() float64 {
+	v.mutex.Lock()
+	defer v.mutex.Unlock()
+
+	return v.error
+}
+
+// Update moves the local coordinate towards or away from the coordinate of a remote peer,
+// such that their distance better predicts an RTT sample measured to the peer. Samples
+// are filtered per peer by their median. Malformed coordinates and RTTs are ignored.
+func (v *Vivaldi) Update(rtt time.Duration, remotePeer peer.ID, remoteCoords []float64) {
+	if rtt <= 0 || len(remoteCoords) != Dimensions+1 || !valid(remoteCoords) {
+		return
+	}
+
+	v.mutex.Lock()
+	defer v.mutex.Unlock()
+
+	sample := v.filter(remotePeer, rtt.Seconds())
+
+	dist := distance(v.coords, remoteCoords)
+
+	// The remote error estimate is not known, and thus both sides are weighed equally
+	// once the local estimate has converged.
+	weight := v.error / (v.error + maxError/2)
+
+	relativeError := math.Abs(dist-sample) / sample
+	v.error = relativeError*ce*weight + v.error*(1-ce*weight)
+	if v.error > maxError {
+		v.error = maxError
+	}
+
+	v.applyForce(cc*weight*(sample-dist), remoteCoords)
+}
+
+// EstimatedRTT predicts the round-trip time to a node with a coordinate.
+func (v *Vivaldi) EstimatedRTT(remoteCoords []float64) time.Duration {
+	if len(remoteCoords) != Dimensions+1 || !valid(remoteCoords) {
+		return 0
+	}
+
+	v.mutex.Lock()
+	defer v.mutex.Unlock()
+
+	return time.Duration(distance(v.coords, remoteCoords) * float64(time.Second))
+}
+
+// filter records an RTT sample to a peer, and returns the median of the peers most
+// recent samples.
+func (v *Vivaldi) filter(remotePeer peer.ID, rtt float64) float64 {
+	key := string(remotePeer.Id)
+
+	samples := append(v.samples[key], rtt)
+	if len(samples) > filterSize {
+		samples = samples[1:]
+	}
+	v.samples[key] = samples
+
+	sorted := append([]float64(nil), samples...)
+	sort.Float64s(sorted)
+
+	return sorted[len(sorted)/2]
+}
+
+// applyForce moves the local coordinate along the unit vector from a remote coordinate
+// by a force, in seconds. Positive forces move the coordinates apart.
+func (v *Vivaldi) applyForce(force float64, remoteCoords []float64) {
+	unit, magnitude := unitVector(v.coords[:Dimensions], remoteCoords[:Dimensions])
+
+	for i := 0; i < Dimensions; i++ {
+		v.coords[i] += unit[i] * force
+	}
+
+	height := v.coords[Dimensions]
+	if magnitude > zeroThreshold {
+		height += (height + remoteCoords[Dimensions]) * force / magnitude
+	}
+	v.coords[Dimensions] = math.Max(height, minHeight)
+}
+
+// distance returns the euclidean distance between two coordinates, plus both of their heights.
+func distance(a, b []float64) float64 {
+	sum := 0.0
+	for i := 0; i < Dimensions; i++ {
+		diff := a[i] - b[i]
+		sum += diff * diff
+	}
+	return math.Sqrt(sum) + a[Dimensions] + b[Dimensions]
+}
+
+// unitVector returns the unit vector pointing from b to a, and the distance between them.
+// A random unit vector is returned should a and b overlap.
+func unitVector(a, b []float64) ([]float64, float64) {
+	diff := make([]float64, len(a))
+	magnitude := 0.0
+
+	for i := range a {
+		diff[i] = a[i] - b[i]
+		magnitude += diff[i] * diff[i]
+	}
+	magnitude = math.Sqrt(magnitude)
+
+	if magnitude > zeroThreshold {
+		for i := range diff {
+			diff[i] /= magnitude
+		}
+		return diff, magnitude
+	}
+
+	// Push overlapping coordinates apart in a random direction.
+	norm := 0.0
+	for i := range diff {
+		diff[i] = rand.Float64() - 0.5
+		norm += diff[i] * diff[i]
+	}
+	norm = math.Sqrt(norm)
+
+	if norm > zeroThreshold {
+		for i := range diff {
+			diff[i] /= norm
+		}
+	}
+
+	return diff, 0
+}
+
+func valid(coords []float64) bool {
+	for _, c := range coords {
+		if math.IsNaN(c) || math.IsInf(c, 0) {
+			return false
+		}
+	}
+	return coords[Dimensions] >= 0
+}
@@ -0,0 +1,94 @@
+package vivaldi
+
+import (
+	"math"
+	"math/rand"
+	"testing"
+	"time"
+
+	"github.com/perlin-network/noise/peer"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestStarTopology(t *testing.T) {
+	t.Parallel()
+
+	const (
+		numNodes  = 20
+		numRounds = 1000
+	)
+
+	r := rand.New(rand.NewSource(1))
+
+	// Node 0 is the hub of the star. Leaves reach each other through the hub, and thus
+	// the RTT between two leaves is the sum of the latencies of their links to the hub.
+	links := make([]time.Duration, numNodes)
+	for i := 1; i < numNodes; i++ {
+		links[i] = time.Duration(5+r.Intn(45)) * time.Millisecond
+	}
+
+	rtt := func(i, j int) time.Duration {
+		return links[i] + links[j]
+	}
+
+	ids := make([]peer.ID, numNodes)
+	nodes := make([]*Vivaldi, numNodes)
+
+	for i := range nodes {
+		ids[i] = peer.ID{Id: []byte{byte(i)}}
+		nodes[i] = New()
+	}
+
+	for round := 0; round < numRounds; round++ {
+		for i, node := range nodes {
+			j := r.Intn(numNodes - 1)
+			if j >= i {
+				j++
+			}
+
+			node.Update(rtt(i, j), ids[j], nodes[j].Coordinates())
+		}
+	}
+
+	total, accurate := 0, 0
+
+	for i := range nodes {
+		for j := range nodes {
+			if i == j {
+				continue
+			}
+
+			actual := rtt(i, j).Seconds()
+			estimated := nodes[i].EstimatedRTT(nodes[j].Coordinates()).Seconds()
+
+			if math.Abs(estimated-actual)/actual < 0.2 {
+				accurate++
+			}
+			total++
+		}
+	}
+
+	assert.True(t, float64(accurate) >= 0.9*float64(total), "only %d of %d pairs were predicted within 20%% error", accurate, total)
+
+	for _, node := range nodes {
+		assert.True(t, node.Error() < 0.2)
+	}
+}
+
+func TestUpdateIgnoresMalformedSamples(t *testing.T) {
+	t.Parallel()
+
+	node := New()
+	remote := peer.ID{Id: []byte("remote")}
+
+	node.Update(0, remote, []float64{1, 1, 1})
+	node.Update(time.Millisecond, remote, []float64{1, 1})
+	node.Update(time.Millisecond, remote, []float64{math.NaN(), 1, 1})
+	node.Update(time.Millisecond, remote, []float64{1, 1, -1})
+
+	assert.Equal(t, []float64{0, 0, 0}, node.Coordinates())
+	assert.Equal(t, maxError, node.Error())
+
+	assert.Equal(t, time.Duration(0), node.EstimatedRTT([]float64{1, 1}))
+}
@@ -0,0 +1,36 @@
+package crypto_test
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/perlin-network/noise/crypto"
+	"github.com/perlin-network/noise/crypto/ed25519"
+)
+
+// FuzzFromPrivateKey feeds arbitrary hex-encoded private keys into FromPrivateKey. Key
+// pairs which are loaded must derive the same public key every time.
+func FuzzFromPrivateKey(f *testing.F) {
+	sp := ed25519.New()
+
+	f.Add(ed25519.RandomKeyPair().PrivateKeyHex())
+	f.Add("")
+	f.Add("00")
+	f.Add("zz")
+
+	f.Fuzz(func(t *testing.T, privateKey string) {
+		keys, err := crypto.FromPrivateKey(sp, privateKey)
+		if err != nil {
+			return
+		}
+
+		again, err := crypto.FromPrivateKey(sp, privateKey)
+		if err != nil {
+			t.Fatalf("expected private key %q to load twice, got %v", privateKey, err)
+		}
+
+		if !bytes.Equal(keys.PublicKey, again.PublicKey) {
+			t.Fatalf("expected private key %q to derive the same public key", privateKey)
+		}
+	})
+}
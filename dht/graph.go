@@ -0,0 +1,34 @@
+package dht
+
+import (
+	"fmt"
+	"io"
+
+	"github.com/perlin-network/noise/peer"
+)
+
+// FullyConnectedGraph generates n peer IDs with random public keys read from rng, and a
+// routing table for each which holds all n-1 other peers, for testing algorithms over
+// the topology. Routing tables are sized such that no peers are evicted, and do not warn
+// of peers sharing subnets. Panics should reading from rng fail.
+func FullyConnectedGraph(n int, rng io.Reader) (ids []peer.ID, tables []*RoutingTable) {
+	for i := 0; i < n; i++ {
+		publicKey := make([]byte, 32)
+		if _, err := io.ReadFull(rng, publicKey); err != nil {
+			panic(err)
+		}
+
+		ids = append(ids, peer.CreateID(fmt.Sprintf("tcp://127.0.0.1:%d", 3000+i), publicKey))
+	}
+
+	for _, id := range ids {
+		table := CreateRoutingTableWithBucketSize(id, n)
+		table.MaxPeersPerSubnet = 0
+
+		table.UpdateMany(ids)
+
+		tables = append(tables, table)
+	}
+
+	return ids, tables
+}
@@ -2,21 +2,52 @@ package dht
 
 import (
 	"container/list"
+	"encoding/gob"
+	"io"
+	"net"
+	"net/url"
 	"sort"
 	"sync"
+	"unsafe"
 
+	"github.com/perlin-network/noise/log"
 	"github.com/perlin-network/noise/peer"
+
+	"github.com/pkg/errors"
+	"github.com/rs/zerolog"
 )
 
 // BucketSize defines the NodeID, Key, and routing table data structures.
 const BucketSize = 16
 
+// DefaultMaxPeersPerSubnet is the default number of peers which may share a subnet
+// before the routing table warns of the risk of an eclipse attack.
+const DefaultMaxPeersPerSubnet = 8
+
+func init() {
+	gob.Register(peer.ID{})
+}
+
 // RoutingTable contains one bucket list for lookups.
 type RoutingTable struct {
 	// Current node's ID.
 	self peer.ID
 
+	// Maximum number of peers held per bucket.
+	bucketSize int
+
 	buckets []*Bucket
+
+	// MaxPeersPerSubnet is the number of peers which may share a /24 IPv4 or /48 IPv6
+	// subnet before a warning is logged. Disabled if zero. Should be set before the
+	// routing table is updated.
+	MaxPeersPerSubnet int
+
+	// Map of subnets (string) <-> number of peers within the subnet.
+	subnets     map[string]int
+	subnetMutex sync.Mutex
+
+	logger zerolog.Logger
 }
 
 // Bucket holds a list of contacts of this node.
@@ -35,9 +66,25 @@ func NewBucket() *Bucket {
 
 // CreateRoutingTable is a Factory method of RoutingTable containing empty buckets.
 func CreateRoutingTable(id peer.ID) *RoutingTable {
+	return CreateRoutingTableWithBucketSize(id, BucketSize)
+}
+
+// CreateRoutingTableWithBucketSize is a Factory method of RoutingTable containing empty
+// buckets which each hold at most bucketSize peers.
+func CreateRoutingTableWithBucketSize(id peer.ID, bucketSize int) *RoutingTable {
+	if bucketSize <= 0 {
+		bucketSize = BucketSize
+	}
+
 	table := &RoutingTable{
-		self:    id,
-		buckets: make([]*Bucket, len(id.Id)*8),
+		self:       id,
+		bucketSize: bucketSize,
+		buckets:    make([]*Bucket, len(id.Id)*8),
+
+		MaxPeersPerSubnet: DefaultMaxPeersPerSubnet,
+
+		subnets: make(map[string]int),
+		logger:  log.With().Logger(),
 	}
 	for i := 0; i < len(id.Id)*8; i++ {
 		table.buckets[i] = NewBucket()
@@ -53,6 +100,17 @@ func (t *RoutingTable) Self() peer.ID {
 	return t.self
 }
 
+// SetLogger sets the logger warnings about the routing table are logged to. Should be
+// set before the routing table is updated.
+func (t *RoutingTable) SetLogger(logger zerolog.Logger) {
+	t.logger = logger
+}
+
+// BucketSize returns the maximum number of peers held per bucket.
+func (t *RoutingTable) BucketSize() int {
+	return t.bucketSize
+}
+
 // Update moves a peer to the front of a bucket in the routing table.
 func (t *RoutingTable) Update(target peer.ID) {
 	if len(t.self.Id) != len(target.Id) {
@@ -62,11 +120,49 @@ func (t *RoutingTable) Update(target peer.ID) {
 	bucketID := target.XorID(t.self).PrefixLen()
 	bucket := t.Bucket(bucketID)
 
+	bucket.mutex.Lock()
+	t.update(bucket, target)
+	bucket.mutex.Unlock()
+}
+
+// UpdateMany moves a list of peers to the front of their respective buckets in the
+// routing table, acquiring each buckets lock only once. Returns the number of peers
+// which were newly added to the routing table.
+func (t *RoutingTable) UpdateMany(targets []peer.ID) int {
+	grouped := make(map[int][]peer.ID)
+
+	for _, target := range targets {
+		if len(t.self.Id) != len(target.Id) {
+			continue
+		}
+
+		bucketID := target.XorID(t.self).PrefixLen()
+		grouped[bucketID] = append(grouped[bucketID], target)
+	}
+
+	added := 0
+
+	for bucketID, targets := range grouped {
+		bucket := t.Bucket(bucketID)
+
+		bucket.mutex.Lock()
+		for _, target := range targets {
+			if t.update(bucket, target) {
+				added++
+			}
+		}
+		bucket.mutex.Unlock()
+	}
+
+	return added
+}
+
+// update moves a peer to the front of a bucket whose lock is held by the caller.
+// Returns true if the peer was newly added to the bucket.
+func (t *RoutingTable) update(bucket *Bucket, target peer.ID) bool {
 	var element *list.Element
 
 	// Find current node in bucket.
-	bucket.mutex.Lock()
-
 	for e := bucket.Front(); e != nil; e = e.Next() {
 		if e.Value.(peer.ID).Equals(target) {
 			element = e
@@ -74,16 +170,86 @@ func (t *RoutingTable) Update(target peer.ID) {
 		}
 	}
 
-	if element == nil {
-		// Populate bucket if its not full.
-		if bucket.Len() <= BucketSize {
-			bucket.PushFront(target)
+	if element != nil {
+		bucket.MoveToFront(element)
+		return false
+	}
+
+	// Populate bucket if its not full.
+	if bucket.Len() <= t.bucketSize {
+		bucket.PushFront(target)
+
+		if !target.Equals(t.self) {
+			t.trackSubnet(target, 1)
 		}
+
+		return true
+	}
+
+	return false
+}
+
+// trackSubnet adjusts the number of peers within a peers subnet, and warns should
+// too many peers share the subnet.
+func (t *RoutingTable) trackSubnet(target peer.ID, delta int) {
+	subnet := subnetOf(target.Address)
+
+	t.subnetMutex.Lock()
+	count := t.subnets[subnet] + delta
+	if count > 0 {
+		t.subnets[subnet] = count
 	} else {
-		bucket.MoveToFront(element)
+		delete(t.subnets, subnet)
 	}
+	t.subnetMutex.Unlock()
 
-	bucket.mutex.Unlock()
+	if delta > 0 && t.MaxPeersPerSubnet > 0 && count > t.MaxPeersPerSubnet {
+		t.logger.Warn().
+			Str("subnet", subnet).
+			Int("peers", count).
+			Msg("dht: too many peers share a subnet; the routing table may be at risk of an eclipse attack")
+	}
+}
+
+// subnetOf returns the /24 IPv4 or /48 IPv6 subnet of a peers address. Returns the
+// host itself should it not be an IP address.
+func subnetOf(address string) string {
+	host := address
+	if info, err := url.Parse(address); err == nil && len(info.Host) > 0 {
+		host = info.Host
+	}
+	if h, _, err := net.SplitHostPort(host); err == nil {
+		host = h
+	}
+
+	ip := net.ParseIP(host)
+	if ip == nil {
+		return host
+	}
+
+	if ip4 := ip.To4(); ip4 != nil {
+		return (&net.IPNet{IP: ip4.Mask(net.CIDRMask(24, 32)), Mask: net.CIDRMask(24, 32)}).String()
+	}
+	return (&net.IPNet{IP: ip.Mask(net.CIDRMask(48, 128)), Mask: net.CIDRMask(48, 128)}).String()
+}
+
+// CheckEclipseRisk returns the number of distinct subnets peers in the routing table
+// belong to relative to the total number of peers. A low value (e.g. < 0.1) signals
+// a high risk of an eclipse attack. Returns 1 should the routing table have no peers.
+func (t *RoutingTable) CheckEclipseRisk() float64 {
+	t.subnetMutex.Lock()
+	defer t.subnetMutex.Unlock()
+
+	total := 0
+	for _, count := range t.subnets {
+		total += count
+	}
+
+	if total == 0 {
+		return 1
+	}
+
+	return float64(len(t.subnets)) / float64(total)
 }
 
 // GetPeers returns a randomly-ordered, unique list of all peers within the routing network (excluding itself).
@@ -141,6 +307,10 @@ func (t *RoutingTable) RemovePeer(target peer.ID) bool {
 		if e.Value.(peer.ID).Equals(target) {
 			bucket.Remove(e)
 
+			if !target.Equals(t.self) {
+				t.trackSubnet(e.Value.(peer.ID), -1)
+			}
+
 			bucket.mutex.Unlock()
 			return true
 		}
@@ -220,6 +390,38 @@ func (t *RoutingTable) FindClosestPeers(target peer.ID, count int) (peers []peer
 	return peers
 }
 
+// BucketDistribution returns the number of peers held within each bucket, indexed by bucket ID.
+func (t *RoutingTable) BucketDistribution() []int {
+	distribution := make([]int, len(t.buckets))
+
+	for i, bucket := range t.buckets {
+		bucket.mutex.RLock()
+		distribution[i] = bucket.Len()
+		bucket.mutex.RUnlock()
+	}
+
+	return distribution
+}
+
+// SizeBytes estimates the memory used by the routing table in bytes, accounting for
+// its buckets and the peer IDs, addresses and keys held within them.
+func (t *RoutingTable) SizeBytes() int {
+	size := int(unsafe.Sizeof(*t)) + cap(t.buckets)*int(unsafe.Sizeof(t.buckets[0]))
+
+	for _, bucket := range t.buckets {
+		size += int(unsafe.Sizeof(*bucket) + unsafe.Sizeof(*bucket.List) + unsafe.Sizeof(*bucket.mutex))
+
+		bucket.mutex.RLock()
+		for e := bucket.Front(); e != nil; e = e.Next() {
+			id := e.Value.(peer.ID)
+			size += int(unsafe.Sizeof(*e)+unsafe.Sizeof(id)) + len(id.Address) + cap(id.PublicKey) + cap(id.Id)
+		}
+		bucket.mutex.RUnlock()
+	}
+
+	return size
+}
+
 // Bucket returns a specific Bucket by ID.
 func (t *RoutingTable) Bucket(id int) *Bucket {
 	if id >= 0 && id < len(t.buckets) {
@@ -227,3 +429,69 @@ func (t *RoutingTable) Bucket(id int) *Bucket {
 	}
 	return nil
 }
+
+// serializedRoutingTable is the gob-encoded form of a routing table.
+type serializedRoutingTable struct {
+	Self    peer.ID
+	Buckets [][]peer.ID
+}
+
+// Serialize writes the peers of the routing table to w with encoding/gob, preserving the
+// order of peers within each bucket.
+func (t *RoutingTable) Serialize(w io.Writer) error {
+	serialized := serializedRoutingTable{
+		Self:    t.self,
+		Buckets: make([][]peer.ID, len(t.buckets)),
+	}
+
+	for i, bucket := range t.buckets {
+		bucket.mutex.RLock()
+		for e := bucket.Front(); e != nil; e = e.Next() {
+			serialized.Buckets[i] = append(serialized.Buckets[i], e.Value.(peer.ID))
+		}
+		bucket.mutex.RUnlock()
+	}
+
+	return errors.Wrap(gob.NewEncoder(w).Encode(&serialized), "dht: failed to serialize routing table")
+}
+
+// Deserialize replaces the peers of the routing table with those of a routing table
+// serialized by Serialize. Errors should the serialized routing table belong to a
+// different node.
+func (t *RoutingTable) Deserialize(r io.Reader) error {
+	var serialized serializedRoutingTable
+	if err := gob.NewDecoder(r).Decode(&serialized); err != nil {
+		return errors.Wrap(err, "dht: failed to deserialize routing table")
+	}
+
+	if !serialized.Self.Equals(t.self) {
+		return errors.Errorf("dht: serialized routing table belongs to %s, not %s", serialized.Self.ShortString(), t.self.ShortString())
+	}
+
+	if len(serialized.Buckets) != len(t.buckets) {
+		return errors.Errorf("dht: serialized routing table has %d buckets, expected %d", len(serialized.Buckets), len(t.buckets))
+	}
+
+	for i, bucket := range t.buckets {
+		bucket.mutex.Lock()
+
+		for e := bucket.Front(); e != nil; e = e.Next() {
+			if id := e.Value.(peer.ID); !id.Equals(t.self) {
+				t.trackSubnet(id, -1)
+			}
+		}
+		bucket.Init()
+
+		for _, id := range serialized.Buckets[i] {
+			bucket.PushBack(id)
+
+			if !id.Equals(t.self) {
+				t.trackSubnet(id, 1)
+			}
+		}
+
+		bucket.mutex.Unlock()
+	}
+
+	return nil
+}
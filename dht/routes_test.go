@@ -4,8 +4,10 @@ import (
 	"bytes"
 	"crypto/rand"
 	"encoding/hex"
+	"fmt"
 	"reflect"
 	"sort"
+	"strings"
 	"sync"
 	"sync/atomic"
 	"testing"
@@ -13,6 +15,8 @@ import (
 
 	"github.com/perlin-network/noise/crypto/blake2b"
 	"github.com/perlin-network/noise/peer"
+
+	"github.com/rs/zerolog"
 )
 
 var (
@@ -89,6 +93,169 @@ func TestGetPeerAddresses(t *testing.T) {
 	}
 }
 
+func TestUpdateMany(t *testing.T) {
+	t.Parallel()
+
+	routingTable := CreateRoutingTable(id1)
+	routingTable.Update(id2)
+
+	var ids []peer.ID
+	for i := 0; i < 8; i++ {
+		ids = append(ids, peer.CreateID(hex.EncodeToString(MustReadRand(8)), MustReadRand(32)))
+	}
+
+	// Duplicates and peers already in the routing table should not count as added.
+	added := routingTable.UpdateMany(append(ids, id1, id2, ids[0], peer.ID{Address: "0003", Id: []byte("short")}))
+	if added != len(ids) {
+		t.Errorf("UpdateMany() = %d, expected %d", added, len(ids))
+	}
+
+	for _, id := range ids {
+		if !routingTable.PeerExists(id) {
+			t.Errorf("PeerExists(%v) = false, expected true", id)
+		}
+	}
+
+	if len(routingTable.GetPeers()) != len(ids)+1 {
+		t.Errorf("len(GetPeers()) = %d, expected %d", len(routingTable.GetPeers()), len(ids)+1)
+	}
+
+	if added := routingTable.UpdateMany(ids); added != 0 {
+		t.Errorf("UpdateMany() = %d, expected 0", added)
+	}
+}
+
+func TestSizeBytes(t *testing.T) {
+	t.Parallel()
+
+	routingTable := CreateRoutingTable(id1)
+	empty := routingTable.SizeBytes()
+	if empty <= 0 {
+		t.Fatalf("SizeBytes() = %d, expected a positive size", empty)
+	}
+
+	routingTable.Update(id2)
+	withPeer := routingTable.SizeBytes()
+
+	minimum := len(id2.Address) + len(id2.PublicKey) + len(id2.Id)
+	if withPeer-empty < minimum {
+		t.Errorf("SizeBytes() grew by %d after adding a peer, expected at least %d", withPeer-empty, minimum)
+	}
+
+	routingTable.RemovePeer(id2)
+	if routingTable.SizeBytes() != empty {
+		t.Errorf("SizeBytes() = %d after removing a peer, expected %d", routingTable.SizeBytes(), empty)
+	}
+}
+
+func TestCheckEclipseRisk(t *testing.T) {
+	t.Parallel()
+
+	output := new(bytes.Buffer)
+
+	routingTable := CreateRoutingTable(id1)
+	routingTable.SetLogger(zerolog.New(output))
+
+	if risk := routingTable.CheckEclipseRisk(); risk != 1 {
+		t.Errorf("CheckEclipseRisk() = %f, expected 1 for an empty routing table", risk)
+	}
+
+	var ids []peer.ID
+	for i := 0; i < 20; i++ {
+		ids = append(ids, peer.CreateID(fmt.Sprintf("tcp://10.0.0.%d:3000", i+1), MustReadRand(32)))
+	}
+
+	for i, id := range ids {
+		routingTable.Update(id)
+
+		warned := strings.Contains(output.String(), "eclipse attack")
+		if expected := i+1 > DefaultMaxPeersPerSubnet; warned != expected {
+			t.Fatalf("warned = %v after %d peers share a subnet, expected %v", warned, i+1, expected)
+		}
+	}
+
+	peers := len(routingTable.GetPeers())
+	if risk := routingTable.CheckEclipseRisk(); risk != 1/float64(peers) || risk >= 0.1 {
+		t.Errorf("CheckEclipseRisk() = %f, expected %f", risk, 1/float64(peers))
+	}
+
+	routingTable.Update(peer.CreateID("tcp://[2001:db8:1::1]:3000", MustReadRand(32)))
+	routingTable.Update(peer.CreateID("tcp://192.168.0.1:3000", MustReadRand(32)))
+
+	if risk := routingTable.CheckEclipseRisk(); risk != 3/float64(peers+2) {
+		t.Errorf("CheckEclipseRisk() = %f, expected %f", risk, 3/float64(peers+2))
+	}
+
+	for _, id := range ids {
+		routingTable.RemovePeer(id)
+	}
+
+	if risk := routingTable.CheckEclipseRisk(); risk != 1 {
+		t.Errorf("CheckEclipseRisk() = %f, expected 1 after removing peers sharing a subnet", risk)
+	}
+}
+
+func TestFullyConnectedGraph(t *testing.T) {
+	t.Parallel()
+
+	const n = 40
+
+	ids, tables := FullyConnectedGraph(n, rand.Reader)
+	if len(ids) != n || len(tables) != n {
+		t.Fatalf("expected %d ids and tables, got %d ids and %d tables", n, len(ids), len(tables))
+	}
+
+	for i, table := range tables {
+		if !table.Self().Equals(ids[i]) {
+			t.Errorf("expected table %d to belong to %v, got %v", i, ids[i], table.Self())
+		}
+
+		if peers := table.GetPeers(); len(peers) != n-1 {
+			t.Errorf("expected table %d to hold %d peers, got %d", i, n-1, len(peers))
+		}
+
+		for j, id := range ids {
+			if i != j && !table.PeerExists(id) {
+				t.Errorf("expected table %d to hold peer %d", i, j)
+			}
+		}
+	}
+}
+
+func TestSerialize(t *testing.T) {
+	t.Parallel()
+
+	ids, tables := FullyConnectedGraph(64, rand.Reader)
+	routingTable := tables[0]
+
+	var buf bytes.Buffer
+	if err := routingTable.Serialize(&buf); err != nil {
+		t.Fatalf("Serialize() = %v, expected nil", err)
+	}
+
+	deserialized := CreateRoutingTableWithBucketSize(ids[0], len(ids))
+	deserialized.MaxPeersPerSubnet = 0
+	if err := deserialized.Deserialize(bytes.NewReader(buf.Bytes())); err != nil {
+		t.Fatalf("Deserialize() = %v, expected nil", err)
+	}
+
+	if !reflect.DeepEqual(routingTable.GetPeers(), deserialized.GetPeers()) {
+		t.Error("expected deserialized routing table to hold the same peers")
+	}
+
+	for _, target := range append(ids, peer.CreateID("target", MustReadRand(32))) {
+		expected := routingTable.FindClosestPeers(target, BucketSize)
+		if actual := deserialized.FindClosestPeers(target, BucketSize); !reflect.DeepEqual(expected, actual) {
+			t.Errorf("FindClosestPeers(%s) = %v, expected %v", target.ShortString(), actual, expected)
+		}
+	}
+
+	// Routing tables may only be deserialized by the node they belong to.
+	if err := CreateRoutingTable(ids[1]).Deserialize(bytes.NewReader(buf.Bytes())); err == nil {
+		t.Error("Deserialize() = nil, expected an error for a routing table of a different node")
+	}
+}
+
 func TestGetPeers(t *testing.T) {
 	t.Parallel()
 
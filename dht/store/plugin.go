@@ -0,0 +1,421 @@
+package store
+
+import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/perlin-network/noise/crypto/blake2b"
+	"github.com/perlin-network/noise/dht"
+	"github.com/perlin-network/noise/internal/protobuf"
+	"github.com/perlin-network/noise/network"
+	"github.com/perlin-network/noise/network/discovery"
+	"github.com/perlin-network/noise/peer"
+
+	"github.com/pkg/errors"
+)
+
+const (
+	// DefaultMaxTTL is the default maximum time-to-live of a value.
+	DefaultMaxTTL = 24 * time.Hour
+	// DefaultMaxRecords is the default maximum number of values stored by a node.
+	DefaultMaxRecords = 4096
+	// DefaultMaxValueSize is the default maximum size of a value in bytes.
+	DefaultMaxValueSize = 64 * 1024
+	// DefaultMaxQueries is the default maximum number of peers queried per Get.
+	DefaultMaxQueries = 3 * dht.BucketSize
+)
+
+var (
+	// ErrNotFound returns if no peer holds a valid value under a key.
+	ErrNotFound = errors.New("store: value not found")
+
+	// ErrInvalidTTL returns if a value is put with a non-positive time-to-live, or one
+	// beyond the maximum time-to-live.
+	ErrInvalidTTL = errors.New("store: ttl must be positive and within the maximum ttl")
+
+	// ErrValueTooLarge returns if a value is put which exceeds the maximum value size.
+	ErrValueTooLarge = errors.New("store: value exceeds the maximum value size")
+)
+
+// Plugin extends the discovery plugin into a key-value store, replicating values amongst
+// the peers closest to their key as in Kademlia. Values are signed by the node which put
+// them, and are verified upon being stored and retrieved. A key belongs to the publisher
+// of the value first stored under it, whose values are only replaced by newer values of
+// the same publisher until they expire.
+//
+// As the plugin embeds the discovery plugin, it is registered in place of it.
+type Plugin struct {
+	discovery.Plugin
+
+	// Replication is the number of peers closest to a key a value is stored at (default: dht.BucketSize).
+	Replication int
+	// MaxTTL is the maximum time-to-live of a value (default: DefaultMaxTTL).
+	MaxTTL time.Duration
+	// MaxRecords is the maximum number of values stored by the node (default: DefaultMaxRecords).
+	MaxRecords int
+	// MaxValueSize is the maximum size of a value in bytes (default: DefaultMaxValueSize).
+	MaxValueSize int
+	// MaxQueries is the maximum number of peers queried per Get (default: DefaultMaxQueries).
+	MaxQueries int
+
+	net *network.Network
+
+	mutex  sync.Mutex
+	values map[string]*protobuf.ValueRecord
+}
+
+var (
+	PluginID                         = (*Plugin)(nil)
+	_        network.PluginInterface = (*Plugin)(nil)
+)
+
+func (state *Plugin) Startup(net *network.Network) {
+	state.Plugin.Startup(net)
+
+	state.net = net
+
+	state.mutex.Lock()
+	state.values = make(map[string]*protobuf.ValueRecord)
+	state.mutex.Unlock()
+}
+
+func (state *Plugin) Receive(ctx *network.PluginContext) error {
+	if err := state.Plugin.Receive(ctx); err != nil {
+		return err
+	}
+
+	// Drop messages from banned peers, which the discovery plugin ignores.
+	if state.IsBanned(ctx.Sender().PublicKeyHex()) {
+		return nil
+	}
+
+	gCtx := network.WithSignMessage(context.Background(), true)
+
+	switch msg := ctx.Message().(type) {
+	case *protobuf.StoreRequest:
+		return ctx.Reply(gCtx, &protobuf.StoreResponse{Stored: state.store(msg.Record)})
+	case *protobuf.FindValueRequest:
+		response := &protobuf.FindValueResponse{}
+
+		if record, ok := state.load(msg.Key); ok {
+			response.Record = record
+		} else {
+			for _, id := range state.Routes.FindClosestPeers(keyID(msg.Key), state.Routes.BucketSize()) {
+				pid := protobuf.ID(id)
+				response.Peers = append(response.Peers, &pid)
+			}
+		}
+
+		return ctx.Reply(gCtx, response)
+	}
+
+	return nil
+}
+
+// Put signs a value under a key, and stores it for a time-to-live at the peers closest to
+// the key, as well as locally. Errors should the value not be stored at any peer.
+func (state *Plugin) Put(ctx context.Context, key, value []byte, ttl time.Duration) error {
+	if ttl <= 0 || ttl > state.maxTTL() {
+		return ErrInvalidTTL
+	}
+
+	if len(value) > state.maxValueSize() {
+		return ErrValueTooLarge
+	}
+
+	id := protobuf.ID(state.net.SelfID())
+
+	record := &protobuf.ValueRecord{
+		Key:       key,
+		Value:     value,
+		Publisher: &id,
+		Expiry:    state.net.Clock().Now().Add(ttl).UnixNano(),
+	}
+
+	signature, err := state.net.Sign(serializeRecord(record))
+	if err != nil {
+		return errors.Wrap(err, "store: failed to sign value")
+	}
+	record.Signature = signature
+
+	state.store(record)
+
+	var targets []peer.ID
+	for _, target := range state.Lookup(state.net, keyID(key), state.replication()) {
+		if !target.Equals(state.net.SelfID()) {
+			targets = append(targets, target)
+		}
+	}
+
+	if len(targets) == 0 {
+		return discovery.ErrNoRoute
+	}
+
+	var wg sync.WaitGroup
+	stored := make(chan struct{}, len(targets))
+
+	for _, target := range targets {
+		wg.Add(1)
+
+		go func(target peer.ID) {
+			defer wg.Done()
+
+			client, err := state.net.Client(target.Address)
+			if err != nil {
+				return
+			}
+
+			res, err := client.Request(ctx, &protobuf.StoreRequest{Record: record})
+			if err != nil {
+				return
+			}
+
+			if res, ok := res.(*protobuf.StoreResponse); ok && res.Stored {
+				stored <- struct{}{}
+			}
+		}(target)
+	}
+
+	wg.Wait()
+
+	if len(stored) == 0 {
+		return errors.Errorf("store: failed to store value at any of %d peers", len(targets))
+	}
+
+	return nil
+}
+
+// Get retrieves the value stored under a key, iteratively querying peers closer and closer
+// to the key until one holds a valid value, or MaxQueries peers have been queried.
+func (state *Plugin) Get(ctx context.Context, key []byte) ([]byte, error) {
+	if record, ok := state.load(key); ok {
+		return record.Value, nil
+	}
+
+	target := keyID(key)
+
+	visited := map[string]struct{}{state.net.SelfID().PublicKeyHex(): {}}
+	candidates := state.Routes.FindClosestPeers(target, state.Routes.BucketSize())
+
+	for {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+
+		// Query the closest alpha peers which have yet to be queried.
+		var queries []peer.ID
+		for _, id := range candidates {
+			if len(queries) == state.alpha() || len(visited)-1 == state.maxQueries() {
+				break
+			}
+			if _, seen := visited[id.PublicKeyHex()]; !seen {
+				visited[id.PublicKeyHex()] = struct{}{}
+				queries = append(queries, id)
+			}
+		}
+
+		if len(queries) == 0 {
+			return nil, ErrNotFound
+		}
+
+		responses := make(chan *protobuf.FindValueResponse, len(queries))
+
+		for _, id := range queries {
+			go func(id peer.ID) {
+				responses <- state.findValue(ctx, id, key)
+			}(id)
+		}
+
+		var (
+			value []byte
+			found bool
+		)
+
+		for range queries {
+			res := <-responses
+			if res == nil {
+				continue
+			}
+
+			if res.Record != nil && !found && bytes.Equal(res.Record.Key, key) && state.verify(res.Record) {
+				value, found = res.Record.Value, true
+			}
+
+			// Peers respond with at most a bucket of the peers closest to the key.
+			for i, id := range res.Peers {
+				if i == state.Routes.BucketSize() {
+					break
+				}
+				candidates = append(candidates, peer.ID(*id))
+			}
+		}
+
+		if found {
+			return value, nil
+		}
+
+		sort.Slice(candidates, func(i, j int) bool {
+			return candidates[i].XorID(target).Less(candidates[j].XorID(target))
+		})
+
+		if len(candidates) > state.maxQueries() {
+			candidates = candidates[:state.maxQueries()]
+		}
+	}
+}
+
+// findValue sends a FIND_VALUE request for a key to a peer, returning nil should it fail.
+func (state *Plugin) findValue(ctx context.Context, id peer.ID, key []byte) *protobuf.FindValueResponse {
+	client, err := state.net.Client(id.Address)
+	if err != nil {
+		return nil
+	}
+
+	res, err := client.Request(ctx, &protobuf.FindValueRequest{Key: key})
+	if err != nil {
+		return nil
+	}
+
+	response, _ := res.(*protobuf.FindValueResponse)
+	return response
+}
+
+// store stores a record should it be valid, replacing any unexpired record of the same key
+// only should it have been published by the same publisher and expire sooner. Returns true
+// if the record was stored.
+func (state *Plugin) store(record *protobuf.ValueRecord) bool {
+	if record == nil || !state.verify(record) {
+		return false
+	}
+
+	state.mutex.Lock()
+	defer state.mutex.Unlock()
+
+	existing, exists := state.values[string(record.Key)]
+	if exists && state.expired(existing) {
+		delete(state.values, string(record.Key))
+		exists = false
+	}
+
+	if exists {
+		if !bytes.Equal(existing.Publisher.PublicKey, record.Publisher.PublicKey) || existing.Expiry >= record.Expiry {
+			return false
+		}
+	} else if len(state.values) >= state.maxRecords() {
+		// Make room by evicting expired records, refusing the record should none have expired.
+		for key, value := range state.values {
+			if state.expired(value) {
+				delete(state.values, key)
+			}
+		}
+
+		if len(state.values) >= state.maxRecords() {
+			return false
+		}
+	}
+
+	state.values[string(record.Key)] = record
+
+	return true
+}
+
+// load returns the record stored locally under a key, discarding it should it have expired.
+func (state *Plugin) load(key []byte) (*protobuf.ValueRecord, bool) {
+	state.mutex.Lock()
+	defer state.mutex.Unlock()
+
+	record, ok := state.values[string(key)]
+	if !ok {
+		return nil, false
+	}
+
+	if state.expired(record) {
+		delete(state.values, string(key))
+		return nil, false
+	}
+
+	return record, true
+}
+
+// verify checks that a record has not expired, expires within the maximum time-to-live,
+// holds a value within the maximum value size, and was signed by its publisher.
+func (state *Plugin) verify(record *protobuf.ValueRecord) bool {
+	if record.Publisher == nil || state.expired(record) || len(record.Value) > state.maxValueSize() {
+		return false
+	}
+
+	if record.Expiry > state.net.Clock().Now().Add(state.maxTTL()).UnixNano() {
+		return false
+	}
+
+	return state.net.Verify(record.Publisher.PublicKey, serializeRecord(record), record.Signature)
+}
+
+func (state *Plugin) expired(record *protobuf.ValueRecord) bool {
+	return state.net.Clock().Now().UnixNano() >= record.Expiry
+}
+
+func (state *Plugin) alpha() int {
+	if state.Alpha > 0 {
+		return state.Alpha
+	}
+	return dht.BucketSize
+}
+
+func (state *Plugin) replication() int {
+	if state.Replication > 0 {
+		return state.Replication
+	}
+	return dht.BucketSize
+}
+
+func (state *Plugin) maxTTL() time.Duration {
+	if state.MaxTTL > 0 {
+		return state.MaxTTL
+	}
+	return DefaultMaxTTL
+}
+
+func (state *Plugin) maxRecords() int {
+	if state.MaxRecords > 0 {
+		return state.MaxRecords
+	}
+	return DefaultMaxRecords
+}
+
+func (state *Plugin) maxValueSize() int {
+	if state.MaxValueSize > 0 {
+		return state.MaxValueSize
+	}
+	return DefaultMaxValueSize
+}
+
+func (state *Plugin) maxQueries() int {
+	if state.MaxQueries > 0 {
+		return state.MaxQueries
+	}
+	return DefaultMaxQueries
+}
+
+// keyID returns the ID a key is routed by, which is the hash of the key.
+func keyID(key []byte) peer.ID {
+	return peer.ID{Id: blake2b.New().HashBytes(key)}
+}
+
+// serializeRecord serializes the key, value and expiry of a record, which are signed by its publisher.
+func serializeRecord(record *protobuf.ValueRecord) []byte {
+	buf := make([]byte, 4, 4+len(record.Key)+len(record.Value)+8)
+
+	binary.BigEndian.PutUint32(buf, uint32(len(record.Key)))
+	buf = append(buf, record.Key...)
+	buf = append(buf, record.Value...)
+
+	var expiry [8]byte
+	binary.BigEndian.PutUint64(expiry[:], uint64(record.Expiry))
+
+	return append(buf, expiry[:]...)
+}
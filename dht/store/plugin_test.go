@@ -0,0 +1,74 @@
+package store_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/perlin-network/noise/dht/store"
+	"github.com/perlin-network/noise/network"
+	"github.com/perlin-network/noise/network/discovery"
+	"github.com/perlin-network/noise/testutil"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPutGet(t *testing.T) {
+	t.Parallel()
+
+	const size = 5
+
+	plugins := make([]*store.Plugin, size)
+
+	// The store plugins are registered in place of the discovery plugins of the cluster.
+	noDiscovery := testutil.WithDiscovery(func(int) *discovery.Plugin { return nil })
+
+	cluster, err := testutil.NewCluster(size, noDiscovery, testutil.WithSetup(func(i int, builder *network.Builder) {
+		plugins[i] = new(store.Plugin)
+		builder.AddPlugin(plugins[i])
+	}))
+	assert.Nil(t, err)
+	defer cluster.Stop()
+
+	deadline := time.Now().Add(5 * time.Second)
+	for i := 1; i < size; i++ {
+		for !plugins[0].Routes.PeerExists(cluster.Node(i).ID) || !plugins[i].Routes.PeerExists(cluster.Node(0).ID) {
+			if time.Now().After(deadline) {
+				t.Fatalf("expected node %d to be routed", i)
+			}
+			cluster.Node(i).Bootstrap(cluster.Node(0).Address)
+			time.Sleep(50 * time.Millisecond)
+		}
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	assert.Equal(t, store.ErrInvalidTTL, plugins[1].Put(ctx, []byte("key"), []byte("value"), 0))
+	assert.Nil(t, plugins[1].Put(ctx, []byte("key"), []byte("value"), time.Minute))
+
+	value, err := plugins[4].Get(ctx, []byte("key"))
+	assert.Nil(t, err)
+	assert.Equal(t, []byte("value"), value)
+
+	_, err = plugins[4].Get(ctx, []byte("missing"))
+	assert.Equal(t, store.ErrNotFound, err)
+
+	// Values under a key may only be replaced by newer values of the same publisher.
+	assert.NotNil(t, plugins[2].Put(ctx, []byte("key"), []byte("hijacked"), 2*time.Minute))
+	assert.Nil(t, plugins[1].Put(ctx, []byte("key"), []byte("updated"), 2*time.Minute))
+
+	value, err = plugins[3].Get(ctx, []byte("key"))
+	assert.Nil(t, err)
+	assert.Equal(t, []byte("updated"), value)
+
+	assert.Equal(t, store.ErrInvalidTTL, plugins[1].Put(ctx, []byte("key"), []byte("value"), store.DefaultMaxTTL+time.Second))
+	assert.Equal(t, store.ErrValueTooLarge, plugins[1].Put(ctx, []byte("large"), make([]byte, store.DefaultMaxValueSize+1), time.Minute))
+
+	// Values expire once their time-to-live elapses.
+	assert.Nil(t, plugins[2].Put(ctx, []byte("ephemeral"), []byte("value"), time.Second))
+	time.Sleep(1100 * time.Millisecond)
+
+	_, err = plugins[3].Get(ctx, []byte("ephemeral"))
+	assert.Equal(t, store.ErrNotFound, err)
+}
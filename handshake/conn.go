@@ -0,0 +1,144 @@
+package handshake
+
+import (
+	"net"
+	"sync"
+	"time"
+
+	"github.com/perlin-network/noise/crypto"
+)
+
+// maxPayloadSize is the maximum size of plaintext sealed within a single transport message.
+const maxPayloadSize = maxMessageSize - tagLen
+
+// Conn is a connection secured by a Noise XX handshake. The handshake is performed
+// upon the first call to Read or Write should Handshake not have been called beforehand.
+type Conn struct {
+	net.Conn
+
+	keys     *crypto.KeyPair
+	isClient bool
+
+	handshakeOnce    sync.Once
+	handshakeErr     error
+	handshakeTimeout time.Duration
+
+	remoteStatic []byte
+
+	send, recv *cipherState
+
+	readMutex  sync.Mutex
+	writeMutex sync.Mutex
+
+	// buffer holds decrypted plaintext which has yet to be read.
+	buffer []byte
+}
+
+// Client returns a connection which performs the initiators side of a Noise XX handshake.
+func Client(conn net.Conn, keys *crypto.KeyPair) *Conn {
+	return &Conn{Conn: conn, keys: keys, isClient: true}
+}
+
+// Server returns a connection which performs the responders side of a Noise XX handshake.
+func Server(conn net.Conn, keys *crypto.KeyPair) *Conn {
+	return &Conn{Conn: conn, keys: keys}
+}
+
+// SetHandshakeTimeout bounds how long the handshake may take, closing the connection
+// should it not complete in time. Must be called before the handshake is performed.
+func (c *Conn) SetHandshakeTimeout(timeout time.Duration) {
+	c.handshakeTimeout = timeout
+}
+
+// Handshake performs the Noise XX handshake should it not have been performed already.
+func (c *Conn) Handshake() error {
+	c.handshakeOnce.Do(func() {
+		// The connection is closed rather than given a deadline, as deadlines set by the
+		// owner of the connection would otherwise be overridden.
+		if c.handshakeTimeout > 0 {
+			timer := time.AfterFunc(c.handshakeTimeout, func() { c.Conn.Close() })
+			defer func() {
+				if !timer.Stop() {
+					c.handshakeErr = ErrHandshakeTimeout
+				}
+			}()
+		}
+
+		static, err := staticKeyPair(c.keys)
+		if err != nil {
+			c.handshakeErr = err
+			return
+		}
+
+		if c.isClient {
+			c.handshakeErr = c.handshakeClient(static)
+		} else {
+			c.handshakeErr = c.handshakeServer(static)
+		}
+	})
+
+	return c.handshakeErr
+}
+
+// RemoteStatic returns the X25519 static public key of the remote peer. Returns nil
+// should the handshake not have completed.
+func (c *Conn) RemoteStatic() []byte {
+	if err := c.Handshake(); err != nil {
+		return nil
+	}
+	return c.remoteStatic
+}
+
+// Read reads and decrypts data from the connection.
+func (c *Conn) Read(out []byte) (int, error) {
+	if err := c.Handshake(); err != nil {
+		return 0, err
+	}
+
+	c.readMutex.Lock()
+	defer c.readMutex.Unlock()
+
+	for len(c.buffer) == 0 {
+		message, err := readMessage(c.Conn)
+		if err != nil {
+			return 0, err
+		}
+
+		c.buffer, err = c.recv.decryptWithAd(nil, message)
+		if err != nil {
+			return 0, err
+		}
+	}
+
+	n := copy(out, c.buffer)
+	c.buffer = c.buffer[n:]
+
+	return n, nil
+}
+
+// Write encrypts and writes data to the connection.
+func (c *Conn) Write(data []byte) (int, error) {
+	if err := c.Handshake(); err != nil {
+		return 0, err
+	}
+
+	c.writeMutex.Lock()
+	defer c.writeMutex.Unlock()
+
+	written := 0
+	for len(data) > 0 {
+		chunk := data
+		if len(chunk) > maxPayloadSize {
+			chunk = chunk[:maxPayloadSize]
+		}
+
+		if err := writeMessage(c.Conn, c.send.encryptWithAd(nil, chunk)); err != nil {
+			return written, err
+		}
+
+		written += len(chunk)
+		data = data[len(chunk):]
+	}
+
+	return written, nil
+}
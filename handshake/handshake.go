@@ -0,0 +1,294 @@
+// Package handshake implements the Noise_XX_25519_ChaChaPoly_BLAKE2b handshake of the
+// Noise Protocol Framework (https://noiseprotocol.org), providing mutually authenticated
+// connections with forward secrecy.
+package handshake
+
+import (
+	"crypto/rand"
+	"crypto/sha512"
+	"encoding/binary"
+	"io"
+	"math/big"
+	"net"
+
+	"github.com/perlin-network/noise/crypto"
+
+	"github.com/pkg/errors"
+	"golang.org/x/crypto/curve25519"
+)
+
+// maxMessageSize is the maximum size of a single Noise message.
+const maxMessageSize = 65535
+
+var (
+	// ErrDecrypt returns if a message failed to be authenticated and decrypted.
+	ErrDecrypt = errors.New("handshake: failed to decrypt message")
+	// ErrMessageSize returns if a handshake message is malformed.
+	ErrMessageSize = errors.New("handshake: message has an invalid size")
+	// ErrKeyPair returns if a static key could not be derived from a key pair.
+	ErrKeyPair = errors.New("handshake: key pair must have a private key of at least 32 bytes")
+	// ErrPublicKey returns if a static public key could not be derived from a public key.
+	ErrPublicKey = errors.New("handshake: public key is not a valid Ed25519 public key")
+	// ErrHandshakeTimeout returns if a handshake did not complete within its timeout.
+	ErrHandshakeTimeout = errors.New("handshake: timed out")
+)
+
+// HandshakeClient performs the initiators side of a Noise XX handshake over conn, returning a
+// connection which encrypts and decrypts all reads and writes.
+func HandshakeClient(conn net.Conn, keys *crypto.KeyPair) (net.Conn, error) {
+	secured := Client(conn, keys)
+	if err := secured.Handshake(); err != nil {
+		return nil, err
+	}
+	return secured, nil
+}
+
+// HandshakeServer performs the responders side of a Noise XX handshake over conn, returning a
+// connection which encrypts and decrypts all reads and writes.
+func HandshakeServer(conn net.Conn, keys *crypto.KeyPair) (net.Conn, error) {
+	secured := Server(conn, keys)
+	if err := secured.Handshake(); err != nil {
+		return nil, err
+	}
+	return secured, nil
+}
+
+// keyPair is a X25519 key pair.
+type keyPair struct {
+	private [dhLen]byte
+	public  [dhLen]byte
+}
+
+// staticKeyPair derives a X25519 key pair from a nodes key pair. For ed25519 key pairs,
+// the derived key pair is the X25519 equivalent of the ed25519 key pair.
+func staticKeyPair(keys *crypto.KeyPair) (*keyPair, error) {
+	if keys == nil || len(keys.PrivateKey) < dhLen {
+		return nil, ErrKeyPair
+	}
+
+	digest := sha512.Sum512(keys.PrivateKey[:dhLen])
+
+	kp := new(keyPair)
+	copy(kp.private[:], digest[:dhLen])
+	clamp(&kp.private)
+
+	curve25519.ScalarBaseMult(&kp.public, &kp.private)
+
+	return kp, nil
+}
+
+// fieldPrime is the order of the field Curve25519 is defined over, 2^255 - 19.
+var fieldPrime = new(big.Int).Sub(new(big.Int).Lsh(big.NewInt(1), 255), big.NewInt(19))
+
+// StaticPublicKey returns the X25519 static public key a peer performs handshakes with,
+// given its Ed25519 public key, such that peers may be bound to the keys they identify
+// with. The Montgomery u-coordinate is derived from the Edwards y-coordinate as
+// u = (1 + y) / (1 - y).
+func StaticPublicKey(publicKey []byte) ([]byte, error) {
+	if len(publicKey) != dhLen {
+		return nil, ErrPublicKey
+	}
+
+	// Decode the little-endian y-coordinate, dropping the sign bit of x.
+	var encoded [dhLen]byte
+	for i := range encoded {
+		encoded[i] = publicKey[dhLen-1-i]
+	}
+	encoded[0] &= 0x7f
+
+	y := new(big.Int).SetBytes(encoded[:])
+	if y.Cmp(fieldPrime) >= 0 {
+		return nil, ErrPublicKey
+	}
+
+	denominator := new(big.Int).Sub(big.NewInt(1), y)
+	denominator.Mod(denominator, fieldPrime)
+	if denominator.Sign() == 0 {
+		return nil, ErrPublicKey
+	}
+
+	u := new(big.Int).Add(big.NewInt(1), y)
+	u.Mul(u, new(big.Int).ModInverse(denominator, fieldPrime))
+	u.Mod(u, fieldPrime)
+
+	// Encode the u-coordinate little-endian.
+	raw := u.Bytes()
+
+	static := make([]byte, dhLen)
+	for i, b := range raw {
+		static[len(raw)-1-i] = b
+	}
+
+	return static, nil
+}
+
+// ephemeralKeyPair generates a random X25519 key pair.
+func ephemeralKeyPair() (*keyPair, error) {
+	kp := new(keyPair)
+	if _, err := io.ReadFull(rand.Reader, kp.private[:]); err != nil {
+		return nil, err
+	}
+	clamp(&kp.private)
+
+	curve25519.ScalarBaseMult(&kp.public, &kp.private)
+
+	return kp, nil
+}
+
+func clamp(private *[dhLen]byte) {
+	private[0] &= 248
+	private[31] &= 127
+	private[31] |= 64
+}
+
+// writeMessage writes a length-prefixed message to a connection.
+func writeMessage(conn net.Conn, message []byte) error {
+	if len(message) > maxMessageSize {
+		return ErrMessageSize
+	}
+
+	buffer := make([]byte, 2+len(message))
+	binary.BigEndian.PutUint16(buffer, uint16(len(message)))
+	copy(buffer[2:], message)
+
+	_, err := conn.Write(buffer)
+	return err
+}
+
+// readMessage reads a length-prefixed message from a connection.
+func readMessage(conn net.Conn) ([]byte, error) {
+	var size [2]byte
+	if _, err := io.ReadFull(conn, size[:]); err != nil {
+		return nil, err
+	}
+
+	message := make([]byte, binary.BigEndian.Uint16(size[:]))
+	if _, err := io.ReadFull(conn, message); err != nil {
+		return nil, err
+	}
+
+	return message, nil
+}
+
+// handshakeClient performs the initiators side of the XX pattern:
+//
+//	-> e
+//	<- e, ee, s, es
+//	-> s, se
+func (c *Conn) handshakeClient(static *keyPair) error {
+	s := newSymmetricState()
+
+	e, err := ephemeralKeyPair()
+	if err != nil {
+		return err
+	}
+
+	// -> e
+	s.mixHash(e.public[:])
+	if err := writeMessage(c.Conn, append(e.public[:], s.encryptAndHash(nil)...)); err != nil {
+		return err
+	}
+
+	// <- e, ee, s, es
+	message, err := readMessage(c.Conn)
+	if err != nil {
+		return err
+	}
+	if len(message) < 2*dhLen+tagLen {
+		return ErrMessageSize
+	}
+
+	var re, rs [dhLen]byte
+	copy(re[:], message[:dhLen])
+	s.mixHash(re[:])
+	s.mixKey(dh(e.private, re))
+
+	remoteStatic, err := s.decryptAndHash(message[dhLen : 2*dhLen+tagLen])
+	if err != nil {
+		return err
+	}
+	copy(rs[:], remoteStatic)
+	s.mixKey(dh(e.private, rs))
+
+	if _, err := s.decryptAndHash(message[2*dhLen+tagLen:]); err != nil {
+		return err
+	}
+
+	// -> s, se
+	message = s.encryptAndHash(static.public[:])
+	s.mixKey(dh(static.private, re))
+	message = append(message, s.encryptAndHash(nil)...)
+
+	if err := writeMessage(c.Conn, message); err != nil {
+		return err
+	}
+
+	c.send, c.recv = s.split()
+	c.remoteStatic = rs[:]
+
+	return nil
+}
+
+// handshakeServer performs the responders side of the XX pattern.
+func (c *Conn) handshakeServer(static *keyPair) error {
+	s := newSymmetricState()
+
+	// -> e
+	message, err := readMessage(c.Conn)
+	if err != nil {
+		return err
+	}
+	if len(message) < dhLen {
+		return ErrMessageSize
+	}
+
+	var re, rs [dhLen]byte
+	copy(re[:], message[:dhLen])
+	s.mixHash(re[:])
+
+	if _, err := s.decryptAndHash(message[dhLen:]); err != nil {
+		return err
+	}
+
+	// <- e, ee, s, es
+	e, err := ephemeralKeyPair()
+	if err != nil {
+		return err
+	}
+
+	s.mixHash(e.public[:])
+	s.mixKey(dh(e.private, re))
+
+	message = append(e.public[:], s.encryptAndHash(static.public[:])...)
+	s.mixKey(dh(static.private, re))
+	message = append(message, s.encryptAndHash(nil)...)
+
+	if err := writeMessage(c.Conn, message); err != nil {
+		return err
+	}
+
+	// -> s, se
+	message, err = readMessage(c.Conn)
+	if err != nil {
+		return err
+	}
+	if len(message) < dhLen+tagLen {
+		return ErrMessageSize
+	}
+
+	remoteStatic, err := s.decryptAndHash(message[:dhLen+tagLen])
+	if err != nil {
+		return err
+	}
+	copy(rs[:], remoteStatic)
+	s.mixKey(dh(e.private, rs))
+
+	if _, err := s.decryptAndHash(message[dhLen+tagLen:]); err != nil {
+		return err
+	}
+
+	c.recv, c.send = s.split()
+	c.remoteStatic = rs[:]
+
+	return nil
+}
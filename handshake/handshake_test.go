@@ -0,0 +1,181 @@
+package handshake
+
+import (
+	"bytes"
+	"crypto/rand"
+	"io"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/perlin-network/noise/crypto"
+	"github.com/perlin-network/noise/crypto/ed25519"
+)
+
+type handshakeResult struct {
+	conn net.Conn
+	err  error
+}
+
+func handshakePipe(t *testing.T, clientKeys, serverKeys *crypto.KeyPair) (net.Conn, net.Conn) {
+	clientConn, serverConn := net.Pipe()
+
+	results := make(chan handshakeResult)
+	go func() {
+		conn, err := HandshakeServer(serverConn, serverKeys)
+		results <- handshakeResult{conn, err}
+	}()
+
+	client, err := HandshakeClient(clientConn, clientKeys)
+	if err != nil {
+		t.Fatalf("HandshakeClient() = expected no error, got %v", err)
+	}
+
+	result := <-results
+	if result.err != nil {
+		t.Fatalf("HandshakeServer() = expected no error, got %v", result.err)
+	}
+
+	return client, result.conn
+}
+
+func TestHandshake(t *testing.T) {
+	t.Parallel()
+
+	clientKeys, serverKeys := ed25519.RandomKeyPair(), ed25519.RandomKeyPair()
+
+	client, server := handshakePipe(t, clientKeys, serverKeys)
+	defer client.Close()
+	defer server.Close()
+
+	clientStatic, _ := staticKeyPair(clientKeys)
+	serverStatic, _ := staticKeyPair(serverKeys)
+
+	if !bytes.Equal(client.(*Conn).RemoteStatic(), serverStatic.public[:]) {
+		t.Errorf("client RemoteStatic() = %x, expected %x", client.(*Conn).RemoteStatic(), serverStatic.public)
+	}
+	if !bytes.Equal(server.(*Conn).RemoteStatic(), clientStatic.public[:]) {
+		t.Errorf("server RemoteStatic() = %x, expected %x", server.(*Conn).RemoteStatic(), clientStatic.public)
+	}
+
+	// Payloads larger than a single Noise message should be split up transparently.
+	for _, size := range []int{1, 1024, 3 * maxMessageSize} {
+		payload := make([]byte, size)
+		if _, err := rand.Read(payload); err != nil {
+			t.Fatal(err)
+		}
+
+		for _, pair := range [][2]net.Conn{{client, server}, {server, client}} {
+			go func(w net.Conn) {
+				if _, err := w.Write(payload); err != nil {
+					t.Errorf("Write() = expected no error, got %v", err)
+				}
+			}(pair[0])
+
+			received := make([]byte, size)
+			if _, err := io.ReadFull(pair[1], received); err != nil {
+				t.Fatalf("Read() = expected no error, got %v", err)
+			}
+
+			if !bytes.Equal(payload, received) {
+				t.Fatalf("received payload of size %d does not match sent payload", size)
+			}
+		}
+	}
+}
+
+func TestHandshakeEncrypted(t *testing.T) {
+	t.Parallel()
+
+	clientConn, serverConn := net.Pipe()
+	defer clientConn.Close()
+	defer serverConn.Close()
+
+	go func() {
+		server, err := HandshakeServer(serverConn, ed25519.RandomKeyPair())
+		if err != nil {
+			t.Errorf("HandshakeServer() = expected no error, got %v", err)
+			return
+		}
+		server.Write([]byte("secret payload"))
+	}()
+
+	client := Client(clientConn, ed25519.RandomKeyPair())
+	if err := client.Handshake(); err != nil {
+		t.Fatalf("Handshake() = expected no error, got %v", err)
+	}
+
+	// Read the raw transport message off of the underlying connection.
+	message, err := readMessage(clientConn)
+	if err != nil {
+		t.Fatalf("readMessage() = expected no error, got %v", err)
+	}
+
+	if bytes.Contains(message, []byte("secret payload")) {
+		t.Fatal("expected payload to be encrypted on the wire")
+	}
+
+	plaintext, err := client.recv.decryptWithAd(nil, message)
+	if err != nil {
+		t.Fatalf("decryptWithAd() = expected no error, got %v", err)
+	}
+	if string(plaintext) != "secret payload" {
+		t.Errorf("decryptWithAd() = %q, expected %q", plaintext, "secret payload")
+	}
+
+	// Tampered messages should fail to be authenticated.
+	message[0] ^= 0xff
+	if _, err := client.recv.decryptWithAd(nil, message); err != ErrDecrypt {
+		t.Errorf("decryptWithAd() = %v, expected %v", err, ErrDecrypt)
+	}
+}
+
+func TestHandshakeInvalidKeys(t *testing.T) {
+	t.Parallel()
+
+	clientConn, serverConn := net.Pipe()
+	defer clientConn.Close()
+	defer serverConn.Close()
+
+	if _, err := HandshakeClient(clientConn, &crypto.KeyPair{PrivateKey: []byte("short")}); err != ErrKeyPair {
+		t.Errorf("HandshakeClient() = %v, expected %v", err, ErrKeyPair)
+	}
+}
+
+func TestStaticPublicKey(t *testing.T) {
+	t.Parallel()
+
+	for i := 0; i < 16; i++ {
+		keys := ed25519.RandomKeyPair()
+
+		expected, _ := staticKeyPair(keys)
+
+		static, err := StaticPublicKey(keys.PublicKey)
+		if err != nil {
+			t.Fatalf("StaticPublicKey() = expected no error, got %v", err)
+		}
+
+		if !bytes.Equal(static, expected.public[:]) {
+			t.Fatalf("StaticPublicKey() = %x, expected %x", static, expected.public)
+		}
+	}
+
+	if _, err := StaticPublicKey([]byte("short")); err != ErrPublicKey {
+		t.Errorf("StaticPublicKey() = %v, expected %v", err, ErrPublicKey)
+	}
+}
+
+func TestHandshakeTimeout(t *testing.T) {
+	t.Parallel()
+
+	clientConn, serverConn := net.Pipe()
+	defer clientConn.Close()
+
+	// The client never starts its handshake.
+	server := Server(serverConn, ed25519.RandomKeyPair())
+	server.SetHandshakeTimeout(100 * time.Millisecond)
+
+	if err := server.Handshake(); err != ErrHandshakeTimeout {
+		t.Errorf("Handshake() = %v, expected %v", err, ErrHandshakeTimeout)
+	}
+}
@@ -0,0 +1,156 @@
+package handshake
+
+import (
+	"crypto/hmac"
+	"encoding/binary"
+	"hash"
+
+	"golang.org/x/crypto/blake2b"
+	"golang.org/x/crypto/chacha20poly1305"
+	"golang.org/x/crypto/curve25519"
+)
+
+const (
+	// protocolName is the name of the Noise protocol implemented by this package.
+	protocolName = "Noise_XX_25519_ChaChaPoly_BLAKE2b"
+
+	dhLen   = 32
+	hashLen = blake2b.Size
+	tagLen  = 16
+)
+
+func newHash() hash.Hash {
+	h, _ := blake2b.New512(nil)
+	return h
+}
+
+// dh performs a X25519 Diffie-Hellman key exchange.
+func dh(private, public [dhLen]byte) []byte {
+	var shared [dhLen]byte
+	curve25519.ScalarMult(&shared, &private, &public)
+	return shared[:]
+}
+
+// hkdf derives two keys off of a chaining key and input key material.
+func hkdf(chainingKey, ikm []byte) ([]byte, []byte) {
+	mac := hmac.New(newHash, chainingKey)
+	mac.Write(ikm)
+	tempKey := mac.Sum(nil)
+
+	mac = hmac.New(newHash, tempKey)
+	mac.Write([]byte{0x01})
+	out1 := mac.Sum(nil)
+
+	mac = hmac.New(newHash, tempKey)
+	mac.Write(out1)
+	mac.Write([]byte{0x02})
+	out2 := mac.Sum(nil)
+
+	return out1, out2
+}
+
+// cipherState encrypts and decrypts messages with a key and incrementing nonce.
+type cipherState struct {
+	key    [chacha20poly1305.KeySize]byte
+	hasKey bool
+	nonce  uint64
+}
+
+func (c *cipherState) initializeKey(key []byte) {
+	copy(c.key[:], key)
+	c.hasKey = true
+	c.nonce = 0
+}
+
+func (c *cipherState) nextNonce() []byte {
+	var nonce [chacha20poly1305.NonceSize]byte
+	binary.LittleEndian.PutUint64(nonce[4:], c.nonce)
+	c.nonce++
+	return nonce[:]
+}
+
+func (c *cipherState) encryptWithAd(ad, plaintext []byte) []byte {
+	if !c.hasKey {
+		return append([]byte(nil), plaintext...)
+	}
+
+	aead, _ := chacha20poly1305.New(c.key[:])
+	return aead.Seal(nil, c.nextNonce(), plaintext, ad)
+}
+
+func (c *cipherState) decryptWithAd(ad, ciphertext []byte) ([]byte, error) {
+	if !c.hasKey {
+		return append([]byte(nil), ciphertext...), nil
+	}
+
+	aead, _ := chacha20poly1305.New(c.key[:])
+
+	plaintext, err := aead.Open(nil, c.nextNonce(), ciphertext, ad)
+	if err != nil {
+		return nil, ErrDecrypt
+	}
+	return plaintext, nil
+}
+
+// symmetricState holds the chaining key and handshake hash mixed throughout a handshake.
+type symmetricState struct {
+	cipherState
+
+	chainingKey []byte
+	h           []byte
+}
+
+func newSymmetricState() *symmetricState {
+	h := make([]byte, hashLen)
+	copy(h, protocolName)
+
+	s := &symmetricState{
+		chainingKey: append([]byte(nil), h...),
+		h:           h,
+	}
+
+	// Mix in an empty prologue.
+	s.mixHash(nil)
+
+	return s
+}
+
+func (s *symmetricState) mixKey(ikm []byte) {
+	var tempKey []byte
+	s.chainingKey, tempKey = hkdf(s.chainingKey, ikm)
+	s.initializeKey(tempKey[:chacha20poly1305.KeySize])
+}
+
+func (s *symmetricState) mixHash(data []byte) {
+	h := newHash()
+	h.Write(s.h)
+	h.Write(data)
+	s.h = h.Sum(nil)
+}
+
+func (s *symmetricState) encryptAndHash(plaintext []byte) []byte {
+	ciphertext := s.encryptWithAd(s.h, plaintext)
+	s.mixHash(ciphertext)
+	return ciphertext
+}
+
+func (s *symmetricState) decryptAndHash(ciphertext []byte) ([]byte, error) {
+	plaintext, err := s.decryptWithAd(s.h, ciphertext)
+	if err != nil {
+		return nil, err
+	}
+	s.mixHash(ciphertext)
+	return plaintext, nil
+}
+
+// split derives the pair of cipher states used to encrypt transport messages
+// once a handshake completes.
+func (s *symmetricState) split() (*cipherState, *cipherState) {
+	k1, k2 := hkdf(s.chainingKey, nil)
+
+	c1, c2 := new(cipherState), new(cipherState)
+	c1.initializeKey(k1[:chacha20poly1305.KeySize])
+	c2.initializeKey(k2[:chacha20poly1305.KeySize])
+
+	return c1, c2
+}
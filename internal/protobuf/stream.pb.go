@@ -15,6 +15,29 @@
 		LookupNodeRequest
 		LookupNodeResponse
 		Bytes
+		AddressMigration
+		MembershipUpdate
+		SwimPing
+		SwimPingRequest
+		SwimAck
+		BeaconPartial
+		ValueRecord
+		StoreRequest
+		StoreResponse
+		FindValueRequest
+		FindValueResponse
+		Subscribe
+		Publish
+		BloomFilter
+		Transactions
+		TxSyncRequest
+		TxSyncResponse
+		ReliableMessage
+		ReliableAck
+		BlockHeader
+		GetBlock
+		Block
+		KeyRevocation
 */
 package protobuf
 
@@ -27,6 +50,7 @@ import bytes "bytes"
 
 import strings "strings"
 import reflect "reflect"
+import sortkeys "github.com/gogo/protobuf/sortkeys"
 
 import io "io"
 
@@ -41,6 +65,32 @@ var _ = math.Inf
 // proto package needs to be updated.
 const _ = proto.GoGoProtoPackageIsVersion2 // please upgrade the proto package
 
+type MembershipUpdate_State int32
+
+const (
+	MembershipUpdate_ALIVE   MembershipUpdate_State = 0
+	MembershipUpdate_SUSPECT MembershipUpdate_State = 1
+	MembershipUpdate_DEAD    MembershipUpdate_State = 2
+)
+
+var MembershipUpdate_State_name = map[int32]string{
+	0: "ALIVE",
+	1: "SUSPECT",
+	2: "DEAD",
+}
+var MembershipUpdate_State_value = map[string]int32{
+	"ALIVE":   0,
+	"SUSPECT": 1,
+	"DEAD":    2,
+}
+
+func (x MembershipUpdate_State) String() string {
+	return proto.EnumName(MembershipUpdate_State_name, int32(x))
+}
+func (MembershipUpdate_State) EnumDescriptor() ([]byte, []int) {
+	return fileDescriptorStream, []int{8, 0}
+}
+
 type ID struct {
 	// public_key of the peer (we no longer use the public key as the peer ID, but use it to verify messages)
 	PublicKey []byte `protobuf:"bytes,1,opt,name=public_key,json=publicKey,proto3" json:"public_key,omitempty"`
@@ -48,6 +98,8 @@ type ID struct {
 	Address string `protobuf:"bytes,2,opt,name=address,proto3" json:"address,omitempty"`
 	// id is the computed hash of the public key
 	Id []byte `protobuf:"bytes,3,opt,name=id,proto3" json:"id,omitempty"`
+	// multiaddr lists every address the peer may be reached at in preference order
+	Multiaddr string `protobuf:"bytes,4,opt,name=multiaddr,proto3" json:"multiaddr,omitempty"`
 }
 
 func (m *ID) Reset()                    { *m = ID{} }
@@ -75,6 +127,13 @@ func (m *ID) GetId() []byte {
 	return nil
 }
 
+func (m *ID) GetMultiaddr() string {
+	if m != nil {
+		return m.Multiaddr
+	}
+	return ""
+}
+
 type Message struct {
 	Message []byte `protobuf:"bytes,1,opt,name=message,proto3" json:"message,omitempty"`
 	// Sender's address and public key.
@@ -89,6 +148,21 @@ type Message struct {
 	ReplyFlag bool `protobuf:"varint,6,opt,name=reply_flag,json=replyFlag,proto3" json:"reply_flag,omitempty"`
 	// opcode specifies the message type
 	Opcode uint32 `protobuf:"varint,7,opt,name=opcode,proto3" json:"opcode,omitempty"`
+	// relayed_from is the ID of the peer a relayed message originated from. Null if the message was sent directly.
+	RelayedFrom *ID `protobuf:"bytes,8,opt,name=relayed_from,json=relayedFrom" json:"relayed_from,omitempty"`
+	// sequence is the sender's signed, monotonically increasing counter used to detect replayed messages.
+	Sequence uint64 `protobuf:"varint,9,opt,name=sequence,proto3" json:"sequence,omitempty"`
+	// trace_context carries the W3C Trace Context headers (traceparent, tracestate) of the span the message was sent within.
+	TraceContext map[string]string `protobuf:"bytes,10,rep,name=trace_context,json=traceContext" json:"trace_context,omitempty" protobuf_key:"bytes,1,opt,name=key,proto3" protobuf_val:"bytes,2,opt,name=value,proto3"`
+	// group_id is the ID of the peer group the message was sent within. Null if the message was not sent within a group.
+	GroupId []byte `protobuf:"bytes,11,opt,name=group_id,json=groupId,proto3" json:"group_id,omitempty"`
+	// compressed indicates message is compressed with DEFLATE.
+	Compressed bool `protobuf:"varint,12,opt,name=compressed,proto3" json:"compressed,omitempty"`
+	// timeout is how long in nanoseconds the sender awaits a reply for as of sending the message. Zero if none.
+	// It is relative rather than absolute, such that it does not depend on the clocks of peers being in sync.
+	Timeout int64 `protobuf:"varint,13,opt,name=timeout,proto3" json:"timeout,omitempty"`
+	// relay_signature is the signature of the peer a relayed message originated from over the message and its recipient.
+	RelaySignature []byte `protobuf:"bytes,14,opt,name=relay_signature,json=relaySignature,proto3" json:"relay_signature,omitempty"`
 }
 
 func (m *Message) Reset()                    { *m = Message{} }
@@ -144,20 +218,87 @@ func (m *Message) GetOpcode() uint32 {
 	return 0
 }
 
+func (m *Message) GetRelayedFrom() *ID {
+	if m != nil {
+		return m.RelayedFrom
+	}
+	return nil
+}
+
+func (m *Message) GetSequence() uint64 {
+	if m != nil {
+		return m.Sequence
+	}
+	return 0
+}
+
+func (m *Message) GetTraceContext() map[string]string {
+	if m != nil {
+		return m.TraceContext
+	}
+	return nil
+}
+
+func (m *Message) GetGroupId() []byte {
+	if m != nil {
+		return m.GroupId
+	}
+	return nil
+}
+
+func (m *Message) GetCompressed() bool {
+	if m != nil {
+		return m.Compressed
+	}
+	return false
+}
+
+func (m *Message) GetTimeout() int64 {
+	if m != nil {
+		return m.Timeout
+	}
+	return 0
+}
+
+func (m *Message) GetRelaySignature() []byte {
+	if m != nil {
+		return m.RelaySignature
+	}
+	return nil
+}
+
 type Ping struct {
+	// capabilities is the bitmask of features the sender supports.
+	Capabilities uint64 `protobuf:"varint,1,opt,name=capabilities,proto3" json:"capabilities,omitempty"`
 }
 
 func (m *Ping) Reset()                    { *m = Ping{} }
 func (*Ping) ProtoMessage()               {}
 func (*Ping) Descriptor() ([]byte, []int) { return fileDescriptorStream, []int{2} }
 
+func (m *Ping) GetCapabilities() uint64 {
+	if m != nil {
+		return m.Capabilities
+	}
+	return 0
+}
+
 type Pong struct {
+	// capabilities is the bitmask of features the sender supports.
+	Capabilities uint64 `protobuf:"varint,1,opt,name=capabilities,proto3" json:"capabilities,omitempty"`
 }
 
 func (m *Pong) Reset()                    { *m = Pong{} }
 func (*Pong) ProtoMessage()               {}
 func (*Pong) Descriptor() ([]byte, []int) { return fileDescriptorStream, []int{3} }
 
+func (m *Pong) GetCapabilities() uint64 {
+	if m != nil {
+		return m.Capabilities
+	}
+	return 0
+}
+
 type LookupNodeRequest struct {
 	Target *ID `protobuf:"bytes,1,opt,name=target" json:"target,omitempty"`
 }
@@ -203,172 +344,634 @@ func (m *Bytes) GetData() []byte {
 	return nil
 }
 
-func init() {
-	proto.RegisterType((*ID)(nil), "protobuf.ID")
-	proto.RegisterType((*Message)(nil), "protobuf.Message")
-	proto.RegisterType((*Ping)(nil), "protobuf.Ping")
-	proto.RegisterType((*Pong)(nil), "protobuf.Pong")
-	proto.RegisterType((*LookupNodeRequest)(nil), "protobuf.LookupNodeRequest")
-	proto.RegisterType((*LookupNodeResponse)(nil), "protobuf.LookupNodeResponse")
-	proto.RegisterType((*Bytes)(nil), "protobuf.Bytes")
+type AddressMigration struct {
+	// new_address is the address the sender may be reached at from now on.
+	NewAddress string `protobuf:"bytes,1,opt,name=new_address,json=newAddress,proto3" json:"new_address,omitempty"`
+	// signature is the sender's signature of the domain-separated migration without its signature, made with its static private key.
+	Signature []byte `protobuf:"bytes,2,opt,name=signature,proto3" json:"signature,omitempty"`
+	// timestamp is the unix time in nanoseconds at which the sender migrated, which increases with every migration.
+	Timestamp uint64 `protobuf:"varint,3,opt,name=timestamp,proto3" json:"timestamp,omitempty"`
 }
-func (this *ID) VerboseEqual(that interface{}) error {
-	if that == nil {
-		if this == nil {
-			return nil
-		}
-		return fmt.Errorf("that == nil && this != nil")
-	}
 
-	that1, ok := that.(*ID)
-	if !ok {
-		that2, ok := that.(ID)
-		if ok {
-			that1 = &that2
-		} else {
-			return fmt.Errorf("that is not of type *ID")
-		}
-	}
-	if that1 == nil {
-		if this == nil {
-			return nil
-		}
-		return fmt.Errorf("that is type *ID but is nil && this != nil")
-	} else if this == nil {
-		return fmt.Errorf("that is type *ID but is not nil && this == nil")
+func (m *AddressMigration) Reset()                    { *m = AddressMigration{} }
+func (*AddressMigration) ProtoMessage()               {}
+func (*AddressMigration) Descriptor() ([]byte, []int) { return fileDescriptorStream, []int{7} }
+
+func (m *AddressMigration) GetNewAddress() string {
+	if m != nil {
+		return m.NewAddress
 	}
-	if !bytes.Equal(this.PublicKey, that1.PublicKey) {
-		return fmt.Errorf("PublicKey this(%v) Not Equal that(%v)", this.PublicKey, that1.PublicKey)
+	return ""
+}
+
+func (m *AddressMigration) GetSignature() []byte {
+	if m != nil {
+		return m.Signature
 	}
-	if this.Address != that1.Address {
-		return fmt.Errorf("Address this(%v) Not Equal that(%v)", this.Address, that1.Address)
+	return nil
+}
+
+func (m *AddressMigration) GetTimestamp() uint64 {
+	if m != nil {
+		return m.Timestamp
 	}
-	if !bytes.Equal(this.Id, that1.Id) {
-		return fmt.Errorf("Id this(%v) Not Equal that(%v)", this.Id, that1.Id)
+	return 0
+}
+
+type MembershipUpdate struct {
+	// member is the ID of the peer whose membership state changed.
+	Member *ID                    `protobuf:"bytes,1,opt,name=member" json:"member,omitempty"`
+	State  MembershipUpdate_State `protobuf:"varint,2,opt,name=state,proto3,enum=protobuf.MembershipUpdate_State" json:"state,omitempty"`
+	// incarnation is the member's counter, incremented by the member to refute suspicion of it having failed.
+	Incarnation uint64 `protobuf:"varint,3,opt,name=incarnation,proto3" json:"incarnation,omitempty"`
+	// origin is the ID of the peer which made the update, being either the member itself or the peer which probed it.
+	Origin *ID `protobuf:"bytes,4,opt,name=origin" json:"origin,omitempty"`
+	// signature is the origin's signature of the update without its signature.
+	Signature []byte `protobuf:"bytes,5,opt,name=signature,proto3" json:"signature,omitempty"`
+}
+
+func (m *MembershipUpdate) Reset()                    { *m = MembershipUpdate{} }
+func (*MembershipUpdate) ProtoMessage()               {}
+func (*MembershipUpdate) Descriptor() ([]byte, []int) { return fileDescriptorStream, []int{8} }
+
+func (m *MembershipUpdate) GetMember() *ID {
+	if m != nil {
+		return m.Member
 	}
 	return nil
 }
-func (this *ID) Equal(that interface{}) bool {
-	if that == nil {
-		return this == nil
+
+func (m *MembershipUpdate) GetState() MembershipUpdate_State {
+	if m != nil {
+		return m.State
 	}
+	return MembershipUpdate_ALIVE
+}
 
-	that1, ok := that.(*ID)
-	if !ok {
-		that2, ok := that.(ID)
-		if ok {
-			that1 = &that2
-		} else {
-			return false
-		}
+func (m *MembershipUpdate) GetIncarnation() uint64 {
+	if m != nil {
+		return m.Incarnation
 	}
-	if that1 == nil {
-		return this == nil
-	} else if this == nil {
-		return false
+	return 0
+}
+
+func (m *MembershipUpdate) GetOrigin() *ID {
+	if m != nil {
+		return m.Origin
 	}
-	if !bytes.Equal(this.PublicKey, that1.PublicKey) {
-		return false
+	return nil
+}
+
+func (m *MembershipUpdate) GetSignature() []byte {
+	if m != nil {
+		return m.Signature
 	}
-	if this.Address != that1.Address {
-		return false
+	return nil
+}
+
+type SwimPing struct {
+	// updates are membership updates piggybacked onto the probe.
+	Updates []*MembershipUpdate `protobuf:"bytes,1,rep,name=updates" json:"updates,omitempty"`
+}
+
+func (m *SwimPing) Reset()                    { *m = SwimPing{} }
+func (*SwimPing) ProtoMessage()               {}
+func (*SwimPing) Descriptor() ([]byte, []int) { return fileDescriptorStream, []int{9} }
+
+func (m *SwimPing) GetUpdates() []*MembershipUpdate {
+	if m != nil {
+		return m.Updates
 	}
-	if !bytes.Equal(this.Id, that1.Id) {
-		return false
+	return nil
+}
+
+type SwimPingRequest struct {
+	// target is the peer to be probed on behalf of the sender.
+	Target *ID `protobuf:"bytes,1,opt,name=target" json:"target,omitempty"`
+	// updates are membership updates piggybacked onto the probe.
+	Updates []*MembershipUpdate `protobuf:"bytes,2,rep,name=updates" json:"updates,omitempty"`
+}
+
+func (m *SwimPingRequest) Reset()                    { *m = SwimPingRequest{} }
+func (*SwimPingRequest) ProtoMessage()               {}
+func (*SwimPingRequest) Descriptor() ([]byte, []int) { return fileDescriptorStream, []int{10} }
+
+func (m *SwimPingRequest) GetTarget() *ID {
+	if m != nil {
+		return m.Target
 	}
-	return true
+	return nil
 }
-func (this *Message) VerboseEqual(that interface{}) error {
-	if that == nil {
-		if this == nil {
-			return nil
-		}
-		return fmt.Errorf("that == nil && this != nil")
+
+func (m *SwimPingRequest) GetUpdates() []*MembershipUpdate {
+	if m != nil {
+		return m.Updates
 	}
+	return nil
+}
 
-	that1, ok := that.(*Message)
-	if !ok {
-		that2, ok := that.(Message)
-		if ok {
-			that1 = &that2
-		} else {
-			return fmt.Errorf("that is not of type *Message")
-		}
+type SwimAck struct {
+	// updates are membership updates piggybacked onto the acknowledgement.
+	Updates []*MembershipUpdate `protobuf:"bytes,1,rep,name=updates" json:"updates,omitempty"`
+}
+
+func (m *SwimAck) Reset()                    { *m = SwimAck{} }
+func (*SwimAck) ProtoMessage()               {}
+func (*SwimAck) Descriptor() ([]byte, []int) { return fileDescriptorStream, []int{11} }
+
+func (m *SwimAck) GetUpdates() []*MembershipUpdate {
+	if m != nil {
+		return m.Updates
 	}
-	if that1 == nil {
-		if this == nil {
-			return nil
-		}
-		return fmt.Errorf("that is type *Message but is nil && this != nil")
-	} else if this == nil {
-		return fmt.Errorf("that is type *Message but is not nil && this == nil")
+	return nil
+}
+
+type BeaconPartial struct {
+	// round is the round of the random beacon the partial signature was made for.
+	Round uint64 `protobuf:"varint,1,opt,name=round,proto3" json:"round,omitempty"`
+	// index is the index of the key share the partial signature was made with.
+	Index uint32 `protobuf:"varint,2,opt,name=index,proto3" json:"index,omitempty"`
+	// signature is the BLS signature of the round, made with the key share.
+	Signature []byte `protobuf:"bytes,3,opt,name=signature,proto3" json:"signature,omitempty"`
+}
+
+func (m *BeaconPartial) Reset()                    { *m = BeaconPartial{} }
+func (*BeaconPartial) ProtoMessage()               {}
+func (*BeaconPartial) Descriptor() ([]byte, []int) { return fileDescriptorStream, []int{12} }
+
+func (m *BeaconPartial) GetRound() uint64 {
+	if m != nil {
+		return m.Round
 	}
-	if !bytes.Equal(this.Message, that1.Message) {
-		return fmt.Errorf("Message this(%v) Not Equal that(%v)", this.Message, that1.Message)
+	return 0
+}
+
+func (m *BeaconPartial) GetIndex() uint32 {
+	if m != nil {
+		return m.Index
 	}
-	if !this.Sender.Equal(that1.Sender) {
-		return fmt.Errorf("Sender this(%v) Not Equal that(%v)", this.Sender, that1.Sender)
+	return 0
+}
+
+func (m *BeaconPartial) GetSignature() []byte {
+	if m != nil {
+		return m.Signature
 	}
-	if !bytes.Equal(this.Signature, that1.Signature) {
-		return fmt.Errorf("Signature this(%v) Not Equal that(%v)", this.Signature, that1.Signature)
+	return nil
+}
+
+type ValueRecord struct {
+	Key   []byte `protobuf:"bytes,1,opt,name=key,proto3" json:"key,omitempty"`
+	Value []byte `protobuf:"bytes,2,opt,name=value,proto3" json:"value,omitempty"`
+	// publisher is the ID of the node which stored the value.
+	Publisher *ID `protobuf:"bytes,3,opt,name=publisher" json:"publisher,omitempty"`
+	// expiry is the unix time in nanoseconds after which the value is discarded.
+	Expiry int64 `protobuf:"varint,4,opt,name=expiry,proto3" json:"expiry,omitempty"`
+	// signature is the publisher's signature of the key, value and expiry, made with its static private key.
+	Signature []byte `protobuf:"bytes,5,opt,name=signature,proto3" json:"signature,omitempty"`
+}
+
+func (m *ValueRecord) Reset()                    { *m = ValueRecord{} }
+func (*ValueRecord) ProtoMessage()               {}
+func (*ValueRecord) Descriptor() ([]byte, []int) { return fileDescriptorStream, []int{13} }
+
+func (m *ValueRecord) GetKey() []byte {
+	if m != nil {
+		return m.Key
 	}
-	if this.RequestNonce != that1.RequestNonce {
-		return fmt.Errorf("RequestNonce this(%v) Not Equal that(%v)", this.RequestNonce, that1.RequestNonce)
+	return nil
+}
+
+func (m *ValueRecord) GetValue() []byte {
+	if m != nil {
+		return m.Value
 	}
-	if this.MessageNonce != that1.MessageNonce {
-		return fmt.Errorf("MessageNonce this(%v) Not Equal that(%v)", this.MessageNonce, that1.MessageNonce)
+	return nil
+}
+
+func (m *ValueRecord) GetPublisher() *ID {
+	if m != nil {
+		return m.Publisher
 	}
-	if this.ReplyFlag != that1.ReplyFlag {
-		return fmt.Errorf("ReplyFlag this(%v) Not Equal that(%v)", this.ReplyFlag, that1.ReplyFlag)
+	return nil
+}
+
+func (m *ValueRecord) GetExpiry() int64 {
+	if m != nil {
+		return m.Expiry
 	}
-	if this.Opcode != that1.Opcode {
-		return fmt.Errorf("Opcode this(%v) Not Equal that(%v)", this.Opcode, that1.Opcode)
+	return 0
+}
+
+func (m *ValueRecord) GetSignature() []byte {
+	if m != nil {
+		return m.Signature
 	}
 	return nil
 }
-func (this *Message) Equal(that interface{}) bool {
-	if that == nil {
-		return this == nil
-	}
 
-	that1, ok := that.(*Message)
-	if !ok {
-		that2, ok := that.(Message)
-		if ok {
-			that1 = &that2
-		} else {
-			return false
-		}
+type StoreRequest struct {
+	Record *ValueRecord `protobuf:"bytes,1,opt,name=record" json:"record,omitempty"`
+}
+
+func (m *StoreRequest) Reset()                    { *m = StoreRequest{} }
+func (*StoreRequest) ProtoMessage()               {}
+func (*StoreRequest) Descriptor() ([]byte, []int) { return fileDescriptorStream, []int{14} }
+
+func (m *StoreRequest) GetRecord() *ValueRecord {
+	if m != nil {
+		return m.Record
 	}
-	if that1 == nil {
-		return this == nil
-	} else if this == nil {
-		return false
+	return nil
+}
+
+type StoreResponse struct {
+	// stored is whether the record was valid and has been stored.
+	Stored bool `protobuf:"varint,1,opt,name=stored,proto3" json:"stored,omitempty"`
+}
+
+func (m *StoreResponse) Reset()                    { *m = StoreResponse{} }
+func (*StoreResponse) ProtoMessage()               {}
+func (*StoreResponse) Descriptor() ([]byte, []int) { return fileDescriptorStream, []int{15} }
+
+func (m *StoreResponse) GetStored() bool {
+	if m != nil {
+		return m.Stored
 	}
-	if !bytes.Equal(this.Message, that1.Message) {
-		return false
+	return false
+}
+
+type FindValueRequest struct {
+	Key []byte `protobuf:"bytes,1,opt,name=key,proto3" json:"key,omitempty"`
+}
+
+func (m *FindValueRequest) Reset()                    { *m = FindValueRequest{} }
+func (*FindValueRequest) ProtoMessage()               {}
+func (*FindValueRequest) Descriptor() ([]byte, []int) { return fileDescriptorStream, []int{16} }
+
+func (m *FindValueRequest) GetKey() []byte {
+	if m != nil {
+		return m.Key
 	}
-	if !this.Sender.Equal(that1.Sender) {
-		return false
+	return nil
+}
+
+type FindValueResponse struct {
+	// record is the value stored under the key, should the responder hold it.
+	Record *ValueRecord `protobuf:"bytes,1,opt,name=record" json:"record,omitempty"`
+	// peers are the peers closest to the key known to the responder, should it not hold the value.
+	Peers []*ID `protobuf:"bytes,2,rep,name=peers" json:"peers,omitempty"`
+}
+
+func (m *FindValueResponse) Reset()                    { *m = FindValueResponse{} }
+func (*FindValueResponse) ProtoMessage()               {}
+func (*FindValueResponse) Descriptor() ([]byte, []int) { return fileDescriptorStream, []int{17} }
+
+func (m *FindValueResponse) GetRecord() *ValueRecord {
+	if m != nil {
+		return m.Record
 	}
-	if !bytes.Equal(this.Signature, that1.Signature) {
-		return false
+	return nil
+}
+
+func (m *FindValueResponse) GetPeers() []*ID {
+	if m != nil {
+		return m.Peers
 	}
-	if this.RequestNonce != that1.RequestNonce {
-		return false
+	return nil
+}
+
+type Subscribe struct {
+	// topics are all topics the sender is subscribed to, periodically sent as a heartbeat.
+	Topics []string `protobuf:"bytes,1,rep,name=topics" json:"topics,omitempty"`
+}
+
+func (m *Subscribe) Reset()                    { *m = Subscribe{} }
+func (*Subscribe) ProtoMessage()               {}
+func (*Subscribe) Descriptor() ([]byte, []int) { return fileDescriptorStream, []int{18} }
+
+func (m *Subscribe) GetTopics() []string {
+	if m != nil {
+		return m.Topics
 	}
-	if this.MessageNonce != that1.MessageNonce {
-		return false
+	return nil
+}
+
+type Publish struct {
+	// id uniquely identifies the published message, such that it is only delivered once.
+	Id    []byte `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	Topic string `protobuf:"bytes,2,opt,name=topic,proto3" json:"topic,omitempty"`
+	Data  []byte `protobuf:"bytes,3,opt,name=data,proto3" json:"data,omitempty"`
+}
+
+func (m *Publish) Reset()                    { *m = Publish{} }
+func (*Publish) ProtoMessage()               {}
+func (*Publish) Descriptor() ([]byte, []int) { return fileDescriptorStream, []int{19} }
+
+func (m *Publish) GetId() []byte {
+	if m != nil {
+		return m.Id
 	}
-	if this.ReplyFlag != that1.ReplyFlag {
-		return false
+	return nil
+}
+
+func (m *Publish) GetTopic() string {
+	if m != nil {
+		return m.Topic
 	}
-	if this.Opcode != that1.Opcode {
-		return false
+	return ""
+}
+
+func (m *Publish) GetData() []byte {
+	if m != nil {
+		return m.Data
 	}
-	return true
+	return nil
 }
-func (this *Ping) VerboseEqual(that interface{}) error {
+
+type BloomFilter struct {
+	Bits []byte `protobuf:"bytes,1,opt,name=bits,proto3" json:"bits,omitempty"`
+	// hashes is the number of bit positions each element is hashed to.
+	Hashes uint32 `protobuf:"varint,2,opt,name=hashes,proto3" json:"hashes,omitempty"`
+	// seed salts the hashes of elements, such that false positives differ in between filters.
+	Seed uint64 `protobuf:"varint,3,opt,name=seed,proto3" json:"seed,omitempty"`
+}
+
+func (m *BloomFilter) Reset()                    { *m = BloomFilter{} }
+func (*BloomFilter) ProtoMessage()               {}
+func (*BloomFilter) Descriptor() ([]byte, []int) { return fileDescriptorStream, []int{20} }
+
+func (m *BloomFilter) GetBits() []byte {
+	if m != nil {
+		return m.Bits
+	}
+	return nil
+}
+
+func (m *BloomFilter) GetHashes() uint32 {
+	if m != nil {
+		return m.Hashes
+	}
+	return 0
+}
+
+func (m *BloomFilter) GetSeed() uint64 {
+	if m != nil {
+		return m.Seed
+	}
+	return 0
+}
+
+type Transactions struct {
+	Txs [][]byte `protobuf:"bytes,1,rep,name=txs" json:"txs,omitempty"`
+}
+
+func (m *Transactions) Reset()                    { *m = Transactions{} }
+func (*Transactions) ProtoMessage()               {}
+func (*Transactions) Descriptor() ([]byte, []int) { return fileDescriptorStream, []int{21} }
+
+func (m *Transactions) GetTxs() [][]byte {
+	if m != nil {
+		return m.Txs
+	}
+	return nil
+}
+
+type TxSyncRequest struct {
+	// filter holds the hashes of all transactions known to the sender.
+	Filter *BloomFilter `protobuf:"bytes,1,opt,name=filter" json:"filter,omitempty"`
+}
+
+func (m *TxSyncRequest) Reset()                    { *m = TxSyncRequest{} }
+func (*TxSyncRequest) ProtoMessage()               {}
+func (*TxSyncRequest) Descriptor() ([]byte, []int) { return fileDescriptorStream, []int{22} }
+
+func (m *TxSyncRequest) GetFilter() *BloomFilter {
+	if m != nil {
+		return m.Filter
+	}
+	return nil
+}
+
+type TxSyncResponse struct {
+	// txs are the transactions known to the responder which are missing from the requester's filter.
+	Txs [][]byte `protobuf:"bytes,1,rep,name=txs" json:"txs,omitempty"`
+	// filter holds the hashes of all transactions known to the responder.
+	Filter *BloomFilter `protobuf:"bytes,2,opt,name=filter" json:"filter,omitempty"`
+}
+
+func (m *TxSyncResponse) Reset()                    { *m = TxSyncResponse{} }
+func (*TxSyncResponse) ProtoMessage()               {}
+func (*TxSyncResponse) Descriptor() ([]byte, []int) { return fileDescriptorStream, []int{23} }
+
+func (m *TxSyncResponse) GetTxs() [][]byte {
+	if m != nil {
+		return m.Txs
+	}
+	return nil
+}
+
+func (m *TxSyncResponse) GetFilter() *BloomFilter {
+	if m != nil {
+		return m.Filter
+	}
+	return nil
+}
+
+type ReliableMessage struct {
+	// sequence numbers the message among all reliable messages sent to a peer, such that
+	// the peer may acknowledge it.
+	Sequence uint64 `protobuf:"varint,1,opt,name=sequence,proto3" json:"sequence,omitempty"`
+	Data     []byte `protobuf:"bytes,2,opt,name=data,proto3" json:"data,omitempty"`
+}
+
+func (m *ReliableMessage) Reset()                    { *m = ReliableMessage{} }
+func (*ReliableMessage) ProtoMessage()               {}
+func (*ReliableMessage) Descriptor() ([]byte, []int) { return fileDescriptorStream, []int{24} }
+
+func (m *ReliableMessage) GetSequence() uint64 {
+	if m != nil {
+		return m.Sequence
+	}
+	return 0
+}
+
+func (m *ReliableMessage) GetData() []byte {
+	if m != nil {
+		return m.Data
+	}
+	return nil
+}
+
+type ReliableAck struct {
+	// sequence is the sequence number of the message being acknowledged.
+	Sequence uint64 `protobuf:"varint,1,opt,name=sequence,proto3" json:"sequence,omitempty"`
+}
+
+func (m *ReliableAck) Reset()                    { *m = ReliableAck{} }
+func (*ReliableAck) ProtoMessage()               {}
+func (*ReliableAck) Descriptor() ([]byte, []int) { return fileDescriptorStream, []int{25} }
+
+func (m *ReliableAck) GetSequence() uint64 {
+	if m != nil {
+		return m.Sequence
+	}
+	return 0
+}
+
+type BlockHeader struct {
+	// hash is the hash of the serialized block, which identifies it.
+	Hash   []byte `protobuf:"bytes,1,opt,name=hash,proto3" json:"hash,omitempty"`
+	Height uint64 `protobuf:"varint,2,opt,name=height,proto3" json:"height,omitempty"`
+}
+
+func (m *BlockHeader) Reset()                    { *m = BlockHeader{} }
+func (*BlockHeader) ProtoMessage()               {}
+func (*BlockHeader) Descriptor() ([]byte, []int) { return fileDescriptorStream, []int{26} }
+
+func (m *BlockHeader) GetHash() []byte {
+	if m != nil {
+		return m.Hash
+	}
+	return nil
+}
+
+func (m *BlockHeader) GetHeight() uint64 {
+	if m != nil {
+		return m.Height
+	}
+	return 0
+}
+
+type GetBlock struct {
+	Hash []byte `protobuf:"bytes,1,opt,name=hash,proto3" json:"hash,omitempty"`
+}
+
+func (m *GetBlock) Reset()                    { *m = GetBlock{} }
+func (*GetBlock) ProtoMessage()               {}
+func (*GetBlock) Descriptor() ([]byte, []int) { return fileDescriptorStream, []int{27} }
+
+func (m *GetBlock) GetHash() []byte {
+	if m != nil {
+		return m.Hash
+	}
+	return nil
+}
+
+type Block struct {
+	Height uint64 `protobuf:"varint,1,opt,name=height,proto3" json:"height,omitempty"`
+	// data is the serialized block, which is empty should the responder not hold the
+	// block requested.
+	Data []byte `protobuf:"bytes,2,opt,name=data,proto3" json:"data,omitempty"`
+}
+
+func (m *Block) Reset()                    { *m = Block{} }
+func (*Block) ProtoMessage()               {}
+func (*Block) Descriptor() ([]byte, []int) { return fileDescriptorStream, []int{28} }
+
+func (m *Block) GetHeight() uint64 {
+	if m != nil {
+		return m.Height
+	}
+	return 0
+}
+
+func (m *Block) GetData() []byte {
+	if m != nil {
+		return m.Data
+	}
+	return nil
+}
+
+type KeyRevocation struct {
+	// old_public_key is the revoked public key, which signs the revocation.
+	OldPublicKey []byte `protobuf:"bytes,1,opt,name=old_public_key,json=oldPublicKey,proto3" json:"old_public_key,omitempty"`
+	// new_public_key is the public key the revoked key is replaced with, which is empty
+	// should the key not be replaced.
+	NewPublicKey []byte `protobuf:"bytes,2,opt,name=new_public_key,json=newPublicKey,proto3" json:"new_public_key,omitempty"`
+	// signature is the signature of the revocation, made with signature and new_signature
+	// empty, under the old key.
+	Signature []byte `protobuf:"bytes,3,opt,name=signature,proto3" json:"signature,omitempty"`
+	// timestamp is the unix time in seconds the revocation was issued at.
+	Timestamp uint64 `protobuf:"varint,4,opt,name=timestamp,proto3" json:"timestamp,omitempty"`
+	// new_signature is the signature of the revocation, made with signature and
+	// new_signature empty, under the new key. Empty should the key not be replaced.
+	NewSignature []byte `protobuf:"bytes,5,opt,name=new_signature,json=newSignature,proto3" json:"new_signature,omitempty"`
+}
+
+func (m *KeyRevocation) Reset()                    { *m = KeyRevocation{} }
+func (*KeyRevocation) ProtoMessage()               {}
+func (*KeyRevocation) Descriptor() ([]byte, []int) { return fileDescriptorStream, []int{29} }
+
+func (m *KeyRevocation) GetOldPublicKey() []byte {
+	if m != nil {
+		return m.OldPublicKey
+	}
+	return nil
+}
+
+func (m *KeyRevocation) GetNewPublicKey() []byte {
+	if m != nil {
+		return m.NewPublicKey
+	}
+	return nil
+}
+
+func (m *KeyRevocation) GetSignature() []byte {
+	if m != nil {
+		return m.Signature
+	}
+	return nil
+}
+
+func (m *KeyRevocation) GetTimestamp() uint64 {
+	if m != nil {
+		return m.Timestamp
+	}
+	return 0
+}
+
+func (m *KeyRevocation) GetNewSignature() []byte {
+	if m != nil {
+		return m.NewSignature
+	}
+	return nil
+}
+
+func init() {
+	proto.RegisterType((*ID)(nil), "protobuf.ID")
+	proto.RegisterType((*Message)(nil), "protobuf.Message")
+	proto.RegisterType((*Ping)(nil), "protobuf.Ping")
+	proto.RegisterType((*Pong)(nil), "protobuf.Pong")
+	proto.RegisterType((*LookupNodeRequest)(nil), "protobuf.LookupNodeRequest")
+	proto.RegisterType((*LookupNodeResponse)(nil), "protobuf.LookupNodeResponse")
+	proto.RegisterType((*Bytes)(nil), "protobuf.Bytes")
+	proto.RegisterType((*AddressMigration)(nil), "protobuf.AddressMigration")
+	proto.RegisterType((*MembershipUpdate)(nil), "protobuf.MembershipUpdate")
+	proto.RegisterType((*SwimPing)(nil), "protobuf.SwimPing")
+	proto.RegisterType((*SwimPingRequest)(nil), "protobuf.SwimPingRequest")
+	proto.RegisterType((*SwimAck)(nil), "protobuf.SwimAck")
+	proto.RegisterType((*BeaconPartial)(nil), "protobuf.BeaconPartial")
+	proto.RegisterType((*ValueRecord)(nil), "protobuf.ValueRecord")
+	proto.RegisterType((*StoreRequest)(nil), "protobuf.StoreRequest")
+	proto.RegisterType((*StoreResponse)(nil), "protobuf.StoreResponse")
+	proto.RegisterType((*FindValueRequest)(nil), "protobuf.FindValueRequest")
+	proto.RegisterType((*FindValueResponse)(nil), "protobuf.FindValueResponse")
+	proto.RegisterType((*Subscribe)(nil), "protobuf.Subscribe")
+	proto.RegisterType((*Publish)(nil), "protobuf.Publish")
+	proto.RegisterType((*BloomFilter)(nil), "protobuf.BloomFilter")
+	proto.RegisterType((*Transactions)(nil), "protobuf.Transactions")
+	proto.RegisterType((*TxSyncRequest)(nil), "protobuf.TxSyncRequest")
+	proto.RegisterType((*TxSyncResponse)(nil), "protobuf.TxSyncResponse")
+	proto.RegisterType((*ReliableMessage)(nil), "protobuf.ReliableMessage")
+	proto.RegisterType((*ReliableAck)(nil), "protobuf.ReliableAck")
+	proto.RegisterType((*BlockHeader)(nil), "protobuf.BlockHeader")
+	proto.RegisterType((*GetBlock)(nil), "protobuf.GetBlock")
+	proto.RegisterType((*Block)(nil), "protobuf.Block")
+	proto.RegisterType((*KeyRevocation)(nil), "protobuf.KeyRevocation")
+	proto.RegisterEnum("protobuf.MembershipUpdate_State", MembershipUpdate_State_name, MembershipUpdate_State_value)
+}
+func (this *ID) VerboseEqual(that interface{}) error {
 	if that == nil {
 		if this == nil {
 			return nil
@@ -376,33 +979,45 @@ func (this *Ping) VerboseEqual(that interface{}) error {
 		return fmt.Errorf("that == nil && this != nil")
 	}
 
-	that1, ok := that.(*Ping)
+	that1, ok := that.(*ID)
 	if !ok {
-		that2, ok := that.(Ping)
+		that2, ok := that.(ID)
 		if ok {
 			that1 = &that2
 		} else {
-			return fmt.Errorf("that is not of type *Ping")
+			return fmt.Errorf("that is not of type *ID")
 		}
 	}
 	if that1 == nil {
 		if this == nil {
 			return nil
 		}
-		return fmt.Errorf("that is type *Ping but is nil && this != nil")
+		return fmt.Errorf("that is type *ID but is nil && this != nil")
 	} else if this == nil {
-		return fmt.Errorf("that is type *Ping but is not nil && this == nil")
+		return fmt.Errorf("that is type *ID but is not nil && this == nil")
+	}
+	if !bytes.Equal(this.PublicKey, that1.PublicKey) {
+		return fmt.Errorf("PublicKey this(%v) Not Equal that(%v)", this.PublicKey, that1.PublicKey)
+	}
+	if this.Address != that1.Address {
+		return fmt.Errorf("Address this(%v) Not Equal that(%v)", this.Address, that1.Address)
+	}
+	if !bytes.Equal(this.Id, that1.Id) {
+		return fmt.Errorf("Id this(%v) Not Equal that(%v)", this.Id, that1.Id)
+	}
+	if this.Multiaddr != that1.Multiaddr {
+		return fmt.Errorf("Multiaddr this(%v) Not Equal that(%v)", this.Multiaddr, that1.Multiaddr)
 	}
 	return nil
 }
-func (this *Ping) Equal(that interface{}) bool {
+func (this *ID) Equal(that interface{}) bool {
 	if that == nil {
 		return this == nil
 	}
 
-	that1, ok := that.(*Ping)
+	that1, ok := that.(*ID)
 	if !ok {
-		that2, ok := that.(Ping)
+		that2, ok := that.(ID)
 		if ok {
 			that1 = &that2
 		} else {
@@ -414,10 +1029,218 @@ func (this *Ping) Equal(that interface{}) bool {
 	} else if this == nil {
 		return false
 	}
-	return true
-}
-func (this *Pong) VerboseEqual(that interface{}) error {
-	if that == nil {
+	if !bytes.Equal(this.PublicKey, that1.PublicKey) {
+		return false
+	}
+	if this.Address != that1.Address {
+		return false
+	}
+	if !bytes.Equal(this.Id, that1.Id) {
+		return false
+	}
+	if this.Multiaddr != that1.Multiaddr {
+		return false
+	}
+	return true
+}
+func (this *Message) VerboseEqual(that interface{}) error {
+	if that == nil {
+		if this == nil {
+			return nil
+		}
+		return fmt.Errorf("that == nil && this != nil")
+	}
+
+	that1, ok := that.(*Message)
+	if !ok {
+		that2, ok := that.(Message)
+		if ok {
+			that1 = &that2
+		} else {
+			return fmt.Errorf("that is not of type *Message")
+		}
+	}
+	if that1 == nil {
+		if this == nil {
+			return nil
+		}
+		return fmt.Errorf("that is type *Message but is nil && this != nil")
+	} else if this == nil {
+		return fmt.Errorf("that is type *Message but is not nil && this == nil")
+	}
+	if !bytes.Equal(this.Message, that1.Message) {
+		return fmt.Errorf("Message this(%v) Not Equal that(%v)", this.Message, that1.Message)
+	}
+	if !this.Sender.Equal(that1.Sender) {
+		return fmt.Errorf("Sender this(%v) Not Equal that(%v)", this.Sender, that1.Sender)
+	}
+	if !bytes.Equal(this.Signature, that1.Signature) {
+		return fmt.Errorf("Signature this(%v) Not Equal that(%v)", this.Signature, that1.Signature)
+	}
+	if this.RequestNonce != that1.RequestNonce {
+		return fmt.Errorf("RequestNonce this(%v) Not Equal that(%v)", this.RequestNonce, that1.RequestNonce)
+	}
+	if this.MessageNonce != that1.MessageNonce {
+		return fmt.Errorf("MessageNonce this(%v) Not Equal that(%v)", this.MessageNonce, that1.MessageNonce)
+	}
+	if this.ReplyFlag != that1.ReplyFlag {
+		return fmt.Errorf("ReplyFlag this(%v) Not Equal that(%v)", this.ReplyFlag, that1.ReplyFlag)
+	}
+	if this.Opcode != that1.Opcode {
+		return fmt.Errorf("Opcode this(%v) Not Equal that(%v)", this.Opcode, that1.Opcode)
+	}
+	if !this.RelayedFrom.Equal(that1.RelayedFrom) {
+		return fmt.Errorf("RelayedFrom this(%v) Not Equal that(%v)", this.RelayedFrom, that1.RelayedFrom)
+	}
+	if this.Sequence != that1.Sequence {
+		return fmt.Errorf("Sequence this(%v) Not Equal that(%v)", this.Sequence, that1.Sequence)
+	}
+	if len(this.TraceContext) != len(that1.TraceContext) {
+		return fmt.Errorf("TraceContext this(%v) Not Equal that(%v)", len(this.TraceContext), len(that1.TraceContext))
+	}
+	for i := range this.TraceContext {
+		if this.TraceContext[i] != that1.TraceContext[i] {
+			return fmt.Errorf("TraceContext this[%v](%v) Not Equal that[%v](%v)", i, this.TraceContext[i], i, that1.TraceContext[i])
+		}
+	}
+	if !bytes.Equal(this.GroupId, that1.GroupId) {
+		return fmt.Errorf("GroupId this(%v) Not Equal that(%v)", this.GroupId, that1.GroupId)
+	}
+	if this.Compressed != that1.Compressed {
+		return fmt.Errorf("Compressed this(%v) Not Equal that(%v)", this.Compressed, that1.Compressed)
+	}
+	if this.Timeout != that1.Timeout {
+		return fmt.Errorf("Timeout this(%v) Not Equal that(%v)", this.Timeout, that1.Timeout)
+	}
+	if !bytes.Equal(this.RelaySignature, that1.RelaySignature) {
+		return fmt.Errorf("RelaySignature this(%v) Not Equal that(%v)", this.RelaySignature, that1.RelaySignature)
+	}
+	return nil
+}
+func (this *Message) Equal(that interface{}) bool {
+	if that == nil {
+		return this == nil
+	}
+
+	that1, ok := that.(*Message)
+	if !ok {
+		that2, ok := that.(Message)
+		if ok {
+			that1 = &that2
+		} else {
+			return false
+		}
+	}
+	if that1 == nil {
+		return this == nil
+	} else if this == nil {
+		return false
+	}
+	if !bytes.Equal(this.Message, that1.Message) {
+		return false
+	}
+	if !this.Sender.Equal(that1.Sender) {
+		return false
+	}
+	if !bytes.Equal(this.Signature, that1.Signature) {
+		return false
+	}
+	if this.RequestNonce != that1.RequestNonce {
+		return false
+	}
+	if this.MessageNonce != that1.MessageNonce {
+		return false
+	}
+	if this.ReplyFlag != that1.ReplyFlag {
+		return false
+	}
+	if this.Opcode != that1.Opcode {
+		return false
+	}
+	if !this.RelayedFrom.Equal(that1.RelayedFrom) {
+		return false
+	}
+	if this.Sequence != that1.Sequence {
+		return false
+	}
+	if len(this.TraceContext) != len(that1.TraceContext) {
+		return false
+	}
+	for i := range this.TraceContext {
+		if this.TraceContext[i] != that1.TraceContext[i] {
+			return false
+		}
+	}
+	if !bytes.Equal(this.GroupId, that1.GroupId) {
+		return false
+	}
+	if this.Compressed != that1.Compressed {
+		return false
+	}
+	if this.Timeout != that1.Timeout {
+		return false
+	}
+	if !bytes.Equal(this.RelaySignature, that1.RelaySignature) {
+		return false
+	}
+	return true
+}
+func (this *Ping) VerboseEqual(that interface{}) error {
+	if that == nil {
+		if this == nil {
+			return nil
+		}
+		return fmt.Errorf("that == nil && this != nil")
+	}
+
+	that1, ok := that.(*Ping)
+	if !ok {
+		that2, ok := that.(Ping)
+		if ok {
+			that1 = &that2
+		} else {
+			return fmt.Errorf("that is not of type *Ping")
+		}
+	}
+	if that1 == nil {
+		if this == nil {
+			return nil
+		}
+		return fmt.Errorf("that is type *Ping but is nil && this != nil")
+	} else if this == nil {
+		return fmt.Errorf("that is type *Ping but is not nil && this == nil")
+	}
+	if this.Capabilities != that1.Capabilities {
+		return fmt.Errorf("Capabilities this(%v) Not Equal that(%v)", this.Capabilities, that1.Capabilities)
+	}
+	return nil
+}
+func (this *Ping) Equal(that interface{}) bool {
+	if that == nil {
+		return this == nil
+	}
+
+	that1, ok := that.(*Ping)
+	if !ok {
+		that2, ok := that.(Ping)
+		if ok {
+			that1 = &that2
+		} else {
+			return false
+		}
+	}
+	if that1 == nil {
+		return this == nil
+	} else if this == nil {
+		return false
+	}
+	if this.Capabilities != that1.Capabilities {
+		return false
+	}
+	return true
+}
+func (this *Pong) VerboseEqual(that interface{}) error {
+	if that == nil {
 		if this == nil {
 			return nil
 		}
@@ -441,6 +1264,9 @@ func (this *Pong) VerboseEqual(that interface{}) error {
 	} else if this == nil {
 		return fmt.Errorf("that is type *Pong but is not nil && this == nil")
 	}
+	if this.Capabilities != that1.Capabilities {
+		return fmt.Errorf("Capabilities this(%v) Not Equal that(%v)", this.Capabilities, that1.Capabilities)
+	}
 	return nil
 }
 func (this *Pong) Equal(that interface{}) bool {
@@ -462,6 +1288,9 @@ func (this *Pong) Equal(that interface{}) bool {
 	} else if this == nil {
 		return false
 	}
+	if this.Capabilities != that1.Capabilities {
+		return false
+	}
 	return true
 }
 func (this *LookupNodeRequest) VerboseEqual(that interface{}) error {
@@ -636,514 +1465,6296 @@ func (this *Bytes) Equal(that interface{}) bool {
 	}
 	return true
 }
-func (this *ID) GoString() string {
-	if this == nil {
-		return "nil"
-	}
-	s := make([]string, 0, 7)
-	s = append(s, "&protobuf.ID{")
-	s = append(s, "PublicKey: "+fmt.Sprintf("%#v", this.PublicKey)+",\n")
-	s = append(s, "Address: "+fmt.Sprintf("%#v", this.Address)+",\n")
-	s = append(s, "Id: "+fmt.Sprintf("%#v", this.Id)+",\n")
-	s = append(s, "}")
-	return strings.Join(s, "")
-}
-func (this *Message) GoString() string {
-	if this == nil {
-		return "nil"
+func (this *AddressMigration) VerboseEqual(that interface{}) error {
+	if that == nil {
+		if this == nil {
+			return nil
+		}
+		return fmt.Errorf("that == nil && this != nil")
 	}
-	s := make([]string, 0, 11)
-	s = append(s, "&protobuf.Message{")
-	s = append(s, "Message: "+fmt.Sprintf("%#v", this.Message)+",\n")
-	if this.Sender != nil {
-		s = append(s, "Sender: "+fmt.Sprintf("%#v", this.Sender)+",\n")
+
+	that1, ok := that.(*AddressMigration)
+	if !ok {
+		that2, ok := that.(AddressMigration)
+		if ok {
+			that1 = &that2
+		} else {
+			return fmt.Errorf("that is not of type *AddressMigration")
+		}
 	}
-	s = append(s, "Signature: "+fmt.Sprintf("%#v", this.Signature)+",\n")
-	s = append(s, "RequestNonce: "+fmt.Sprintf("%#v", this.RequestNonce)+",\n")
-	s = append(s, "MessageNonce: "+fmt.Sprintf("%#v", this.MessageNonce)+",\n")
-	s = append(s, "ReplyFlag: "+fmt.Sprintf("%#v", this.ReplyFlag)+",\n")
-	s = append(s, "Opcode: "+fmt.Sprintf("%#v", this.Opcode)+",\n")
-	s = append(s, "}")
-	return strings.Join(s, "")
-}
-func (this *Ping) GoString() string {
-	if this == nil {
-		return "nil"
+	if that1 == nil {
+		if this == nil {
+			return nil
+		}
+		return fmt.Errorf("that is type *AddressMigration but is nil && this != nil")
+	} else if this == nil {
+		return fmt.Errorf("that is type *AddressMigration but is not nil && this == nil")
 	}
-	s := make([]string, 0, 4)
-	s = append(s, "&protobuf.Ping{")
-	s = append(s, "}")
-	return strings.Join(s, "")
-}
-func (this *Pong) GoString() string {
-	if this == nil {
-		return "nil"
+	if this.NewAddress != that1.NewAddress {
+		return fmt.Errorf("NewAddress this(%v) Not Equal that(%v)", this.NewAddress, that1.NewAddress)
 	}
-	s := make([]string, 0, 4)
-	s = append(s, "&protobuf.Pong{")
-	s = append(s, "}")
-	return strings.Join(s, "")
-}
-func (this *LookupNodeRequest) GoString() string {
-	if this == nil {
-		return "nil"
+	if !bytes.Equal(this.Signature, that1.Signature) {
+		return fmt.Errorf("Signature this(%v) Not Equal that(%v)", this.Signature, that1.Signature)
 	}
-	s := make([]string, 0, 5)
-	s = append(s, "&protobuf.LookupNodeRequest{")
-	if this.Target != nil {
-		s = append(s, "Target: "+fmt.Sprintf("%#v", this.Target)+",\n")
+	if this.Timestamp != that1.Timestamp {
+		return fmt.Errorf("Timestamp this(%v) Not Equal that(%v)", this.Timestamp, that1.Timestamp)
 	}
-	s = append(s, "}")
-	return strings.Join(s, "")
+	return nil
 }
-func (this *LookupNodeResponse) GoString() string {
-	if this == nil {
-		return "nil"
+func (this *AddressMigration) Equal(that interface{}) bool {
+	if that == nil {
+		return this == nil
 	}
-	s := make([]string, 0, 5)
-	s = append(s, "&protobuf.LookupNodeResponse{")
-	if this.Peers != nil {
-		s = append(s, "Peers: "+fmt.Sprintf("%#v", this.Peers)+",\n")
+
+	that1, ok := that.(*AddressMigration)
+	if !ok {
+		that2, ok := that.(AddressMigration)
+		if ok {
+			that1 = &that2
+		} else {
+			return false
+		}
 	}
-	s = append(s, "}")
-	return strings.Join(s, "")
-}
-func (this *Bytes) GoString() string {
-	if this == nil {
-		return "nil"
+	if that1 == nil {
+		return this == nil
+	} else if this == nil {
+		return false
 	}
-	s := make([]string, 0, 5)
-	s = append(s, "&protobuf.Bytes{")
-	s = append(s, "Data: "+fmt.Sprintf("%#v", this.Data)+",\n")
-	s = append(s, "}")
-	return strings.Join(s, "")
-}
-func valueToGoStringStream(v interface{}, typ string) string {
-	rv := reflect.ValueOf(v)
-	if rv.IsNil() {
-		return "nil"
+	if this.NewAddress != that1.NewAddress {
+		return false
 	}
-	pv := reflect.Indirect(rv).Interface()
-	return fmt.Sprintf("func(v %v) *%v { return &v } ( %#v )", typ, typ, pv)
-}
-func (m *ID) Marshal() (dAtA []byte, err error) {
-	size := m.Size()
-	dAtA = make([]byte, size)
-	n, err := m.MarshalTo(dAtA)
-	if err != nil {
-		return nil, err
+	if !bytes.Equal(this.Signature, that1.Signature) {
+		return false
 	}
-	return dAtA[:n], nil
+	if this.Timestamp != that1.Timestamp {
+		return false
+	}
+	return true
 }
+func (this *MembershipUpdate) VerboseEqual(that interface{}) error {
+	if that == nil {
+		if this == nil {
+			return nil
+		}
+		return fmt.Errorf("that == nil && this != nil")
+	}
 
-func (m *ID) MarshalTo(dAtA []byte) (int, error) {
-	var i int
-	_ = i
-	var l int
-	_ = l
-	if len(m.PublicKey) > 0 {
-		dAtA[i] = 0xa
-		i++
-		i = encodeVarintStream(dAtA, i, uint64(len(m.PublicKey)))
-		i += copy(dAtA[i:], m.PublicKey)
+	that1, ok := that.(*MembershipUpdate)
+	if !ok {
+		that2, ok := that.(MembershipUpdate)
+		if ok {
+			that1 = &that2
+		} else {
+			return fmt.Errorf("that is not of type *MembershipUpdate")
+		}
 	}
-	if len(m.Address) > 0 {
-		dAtA[i] = 0x12
-		i++
-		i = encodeVarintStream(dAtA, i, uint64(len(m.Address)))
-		i += copy(dAtA[i:], m.Address)
+	if that1 == nil {
+		if this == nil {
+			return nil
+		}
+		return fmt.Errorf("that is type *MembershipUpdate but is nil && this != nil")
+	} else if this == nil {
+		return fmt.Errorf("that is type *MembershipUpdate but is not nil && this == nil")
 	}
-	if len(m.Id) > 0 {
-		dAtA[i] = 0x1a
-		i++
-		i = encodeVarintStream(dAtA, i, uint64(len(m.Id)))
-		i += copy(dAtA[i:], m.Id)
+	if !this.Member.Equal(that1.Member) {
+		return fmt.Errorf("Member this(%v) Not Equal that(%v)", this.Member, that1.Member)
 	}
-	return i, nil
+	if this.State != that1.State {
+		return fmt.Errorf("State this(%v) Not Equal that(%v)", this.State, that1.State)
+	}
+	if this.Incarnation != that1.Incarnation {
+		return fmt.Errorf("Incarnation this(%v) Not Equal that(%v)", this.Incarnation, that1.Incarnation)
+	}
+	if !this.Origin.Equal(that1.Origin) {
+		return fmt.Errorf("Origin this(%v) Not Equal that(%v)", this.Origin, that1.Origin)
+	}
+	if !bytes.Equal(this.Signature, that1.Signature) {
+		return fmt.Errorf("Signature this(%v) Not Equal that(%v)", this.Signature, that1.Signature)
+	}
+	return nil
 }
+func (this *MembershipUpdate) Equal(that interface{}) bool {
+	if that == nil {
+		return this == nil
+	}
 
-func (m *Message) Marshal() (dAtA []byte, err error) {
-	size := m.Size()
-	dAtA = make([]byte, size)
-	n, err := m.MarshalTo(dAtA)
-	if err != nil {
-		return nil, err
+	that1, ok := that.(*MembershipUpdate)
+	if !ok {
+		that2, ok := that.(MembershipUpdate)
+		if ok {
+			that1 = &that2
+		} else {
+			return false
+		}
 	}
-	return dAtA[:n], nil
+	if that1 == nil {
+		return this == nil
+	} else if this == nil {
+		return false
+	}
+	if !this.Member.Equal(that1.Member) {
+		return false
+	}
+	if this.State != that1.State {
+		return false
+	}
+	if this.Incarnation != that1.Incarnation {
+		return false
+	}
+	if !this.Origin.Equal(that1.Origin) {
+		return false
+	}
+	if !bytes.Equal(this.Signature, that1.Signature) {
+		return false
+	}
+	return true
 }
+func (this *SwimPing) VerboseEqual(that interface{}) error {
+	if that == nil {
+		if this == nil {
+			return nil
+		}
+		return fmt.Errorf("that == nil && this != nil")
+	}
 
-func (m *Message) MarshalTo(dAtA []byte) (int, error) {
-	var i int
-	_ = i
-	var l int
-	_ = l
-	if len(m.Message) > 0 {
-		dAtA[i] = 0xa
-		i++
-		i = encodeVarintStream(dAtA, i, uint64(len(m.Message)))
-		i += copy(dAtA[i:], m.Message)
+	that1, ok := that.(*SwimPing)
+	if !ok {
+		that2, ok := that.(SwimPing)
+		if ok {
+			that1 = &that2
+		} else {
+			return fmt.Errorf("that is not of type *SwimPing")
+		}
 	}
-	if m.Sender != nil {
-		dAtA[i] = 0x12
-		i++
-		i = encodeVarintStream(dAtA, i, uint64(m.Sender.Size()))
-		n1, err := m.Sender.MarshalTo(dAtA[i:])
-		if err != nil {
-			return 0, err
+	if that1 == nil {
+		if this == nil {
+			return nil
 		}
-		i += n1
+		return fmt.Errorf("that is type *SwimPing but is nil && this != nil")
+	} else if this == nil {
+		return fmt.Errorf("that is type *SwimPing but is not nil && this == nil")
 	}
-	if len(m.Signature) > 0 {
-		dAtA[i] = 0x1a
-		i++
-		i = encodeVarintStream(dAtA, i, uint64(len(m.Signature)))
-		i += copy(dAtA[i:], m.Signature)
+	if len(this.Updates) != len(that1.Updates) {
+		return fmt.Errorf("Updates this(%v) Not Equal that(%v)", len(this.Updates), len(that1.Updates))
 	}
-	if m.RequestNonce != 0 {
-		dAtA[i] = 0x20
-		i++
-		i = encodeVarintStream(dAtA, i, uint64(m.RequestNonce))
+	for i := range this.Updates {
+		if !this.Updates[i].Equal(that1.Updates[i]) {
+			return fmt.Errorf("Updates this[%v](%v) Not Equal that[%v](%v)", i, this.Updates[i], i, that1.Updates[i])
+		}
 	}
-	if m.MessageNonce != 0 {
-		dAtA[i] = 0x28
-		i++
-		i = encodeVarintStream(dAtA, i, uint64(m.MessageNonce))
+	return nil
+}
+func (this *SwimPing) Equal(that interface{}) bool {
+	if that == nil {
+		return this == nil
 	}
-	if m.ReplyFlag {
-		dAtA[i] = 0x30
-		i++
-		if m.ReplyFlag {
-			dAtA[i] = 1
+
+	that1, ok := that.(*SwimPing)
+	if !ok {
+		that2, ok := that.(SwimPing)
+		if ok {
+			that1 = &that2
 		} else {
-			dAtA[i] = 0
+			return false
 		}
-		i++
 	}
-	if m.Opcode != 0 {
-		dAtA[i] = 0x38
-		i++
-		i = encodeVarintStream(dAtA, i, uint64(m.Opcode))
+	if that1 == nil {
+		return this == nil
+	} else if this == nil {
+		return false
 	}
-	return i, nil
-}
-
-func (m *Ping) Marshal() (dAtA []byte, err error) {
-	size := m.Size()
-	dAtA = make([]byte, size)
-	n, err := m.MarshalTo(dAtA)
-	if err != nil {
-		return nil, err
+	if len(this.Updates) != len(that1.Updates) {
+		return false
 	}
-	return dAtA[:n], nil
-}
-
-func (m *Ping) MarshalTo(dAtA []byte) (int, error) {
-	var i int
-	_ = i
-	var l int
-	_ = l
-	return i, nil
+	for i := range this.Updates {
+		if !this.Updates[i].Equal(that1.Updates[i]) {
+			return false
+		}
+	}
+	return true
 }
+func (this *SwimPingRequest) VerboseEqual(that interface{}) error {
+	if that == nil {
+		if this == nil {
+			return nil
+		}
+		return fmt.Errorf("that == nil && this != nil")
+	}
 
-func (m *Pong) Marshal() (dAtA []byte, err error) {
-	size := m.Size()
-	dAtA = make([]byte, size)
-	n, err := m.MarshalTo(dAtA)
-	if err != nil {
-		return nil, err
+	that1, ok := that.(*SwimPingRequest)
+	if !ok {
+		that2, ok := that.(SwimPingRequest)
+		if ok {
+			that1 = &that2
+		} else {
+			return fmt.Errorf("that is not of type *SwimPingRequest")
+		}
 	}
-	return dAtA[:n], nil
+	if that1 == nil {
+		if this == nil {
+			return nil
+		}
+		return fmt.Errorf("that is type *SwimPingRequest but is nil && this != nil")
+	} else if this == nil {
+		return fmt.Errorf("that is type *SwimPingRequest but is not nil && this == nil")
+	}
+	if !this.Target.Equal(that1.Target) {
+		return fmt.Errorf("Target this(%v) Not Equal that(%v)", this.Target, that1.Target)
+	}
+	if len(this.Updates) != len(that1.Updates) {
+		return fmt.Errorf("Updates this(%v) Not Equal that(%v)", len(this.Updates), len(that1.Updates))
+	}
+	for i := range this.Updates {
+		if !this.Updates[i].Equal(that1.Updates[i]) {
+			return fmt.Errorf("Updates this[%v](%v) Not Equal that[%v](%v)", i, this.Updates[i], i, that1.Updates[i])
+		}
+	}
+	return nil
 }
+func (this *SwimPingRequest) Equal(that interface{}) bool {
+	if that == nil {
+		return this == nil
+	}
 
-func (m *Pong) MarshalTo(dAtA []byte) (int, error) {
-	var i int
-	_ = i
-	var l int
-	_ = l
-	return i, nil
-}
-
-func (m *LookupNodeRequest) Marshal() (dAtA []byte, err error) {
-	size := m.Size()
-	dAtA = make([]byte, size)
-	n, err := m.MarshalTo(dAtA)
-	if err != nil {
-		return nil, err
+	that1, ok := that.(*SwimPingRequest)
+	if !ok {
+		that2, ok := that.(SwimPingRequest)
+		if ok {
+			that1 = &that2
+		} else {
+			return false
+		}
 	}
-	return dAtA[:n], nil
-}
-
-func (m *LookupNodeRequest) MarshalTo(dAtA []byte) (int, error) {
-	var i int
-	_ = i
-	var l int
-	_ = l
-	if m.Target != nil {
-		dAtA[i] = 0xa
-		i++
-		i = encodeVarintStream(dAtA, i, uint64(m.Target.Size()))
-		n2, err := m.Target.MarshalTo(dAtA[i:])
-		if err != nil {
-			return 0, err
+	if that1 == nil {
+		return this == nil
+	} else if this == nil {
+		return false
+	}
+	if !this.Target.Equal(that1.Target) {
+		return false
+	}
+	if len(this.Updates) != len(that1.Updates) {
+		return false
+	}
+	for i := range this.Updates {
+		if !this.Updates[i].Equal(that1.Updates[i]) {
+			return false
 		}
-		i += n2
 	}
-	return i, nil
+	return true
 }
-
-func (m *LookupNodeResponse) Marshal() (dAtA []byte, err error) {
-	size := m.Size()
-	dAtA = make([]byte, size)
-	n, err := m.MarshalTo(dAtA)
-	if err != nil {
-		return nil, err
+func (this *SwimAck) VerboseEqual(that interface{}) error {
+	if that == nil {
+		if this == nil {
+			return nil
+		}
+		return fmt.Errorf("that == nil && this != nil")
 	}
-	return dAtA[:n], nil
-}
 
-func (m *LookupNodeResponse) MarshalTo(dAtA []byte) (int, error) {
-	var i int
-	_ = i
-	var l int
-	_ = l
-	if len(m.Peers) > 0 {
-		for _, msg := range m.Peers {
-			dAtA[i] = 0xa
-			i++
-			i = encodeVarintStream(dAtA, i, uint64(msg.Size()))
-			n, err := msg.MarshalTo(dAtA[i:])
-			if err != nil {
-				return 0, err
-			}
-			i += n
+	that1, ok := that.(*SwimAck)
+	if !ok {
+		that2, ok := that.(SwimAck)
+		if ok {
+			that1 = &that2
+		} else {
+			return fmt.Errorf("that is not of type *SwimAck")
 		}
 	}
-	return i, nil
+	if that1 == nil {
+		if this == nil {
+			return nil
+		}
+		return fmt.Errorf("that is type *SwimAck but is nil && this != nil")
+	} else if this == nil {
+		return fmt.Errorf("that is type *SwimAck but is not nil && this == nil")
+	}
+	if len(this.Updates) != len(that1.Updates) {
+		return fmt.Errorf("Updates this(%v) Not Equal that(%v)", len(this.Updates), len(that1.Updates))
+	}
+	for i := range this.Updates {
+		if !this.Updates[i].Equal(that1.Updates[i]) {
+			return fmt.Errorf("Updates this[%v](%v) Not Equal that[%v](%v)", i, this.Updates[i], i, that1.Updates[i])
+		}
+	}
+	return nil
 }
+func (this *SwimAck) Equal(that interface{}) bool {
+	if that == nil {
+		return this == nil
+	}
 
-func (m *Bytes) Marshal() (dAtA []byte, err error) {
-	size := m.Size()
-	dAtA = make([]byte, size)
-	n, err := m.MarshalTo(dAtA)
-	if err != nil {
-		return nil, err
+	that1, ok := that.(*SwimAck)
+	if !ok {
+		that2, ok := that.(SwimAck)
+		if ok {
+			that1 = &that2
+		} else {
+			return false
+		}
 	}
-	return dAtA[:n], nil
+	if that1 == nil {
+		return this == nil
+	} else if this == nil {
+		return false
+	}
+	if len(this.Updates) != len(that1.Updates) {
+		return false
+	}
+	for i := range this.Updates {
+		if !this.Updates[i].Equal(that1.Updates[i]) {
+			return false
+		}
+	}
+	return true
 }
+func (this *BeaconPartial) VerboseEqual(that interface{}) error {
+	if that == nil {
+		if this == nil {
+			return nil
+		}
+		return fmt.Errorf("that == nil && this != nil")
+	}
 
-func (m *Bytes) MarshalTo(dAtA []byte) (int, error) {
-	var i int
-	_ = i
-	var l int
-	_ = l
-	if len(m.Data) > 0 {
-		dAtA[i] = 0xa
-		i++
-		i = encodeVarintStream(dAtA, i, uint64(len(m.Data)))
-		i += copy(dAtA[i:], m.Data)
+	that1, ok := that.(*BeaconPartial)
+	if !ok {
+		that2, ok := that.(BeaconPartial)
+		if ok {
+			that1 = &that2
+		} else {
+			return fmt.Errorf("that is not of type *BeaconPartial")
+		}
 	}
-	return i, nil
+	if that1 == nil {
+		if this == nil {
+			return nil
+		}
+		return fmt.Errorf("that is type *BeaconPartial but is nil && this != nil")
+	} else if this == nil {
+		return fmt.Errorf("that is type *BeaconPartial but is not nil && this == nil")
+	}
+	if this.Round != that1.Round {
+		return fmt.Errorf("Round this(%v) Not Equal that(%v)", this.Round, that1.Round)
+	}
+	if this.Index != that1.Index {
+		return fmt.Errorf("Index this(%v) Not Equal that(%v)", this.Index, that1.Index)
+	}
+	if !bytes.Equal(this.Signature, that1.Signature) {
+		return fmt.Errorf("Signature this(%v) Not Equal that(%v)", this.Signature, that1.Signature)
+	}
+	return nil
 }
+func (this *BeaconPartial) Equal(that interface{}) bool {
+	if that == nil {
+		return this == nil
+	}
 
-func encodeVarintStream(dAtA []byte, offset int, v uint64) int {
-	for v >= 1<<7 {
-		dAtA[offset] = uint8(v&0x7f | 0x80)
-		v >>= 7
-		offset++
+	that1, ok := that.(*BeaconPartial)
+	if !ok {
+		that2, ok := that.(BeaconPartial)
+		if ok {
+			that1 = &that2
+		} else {
+			return false
+		}
 	}
-	dAtA[offset] = uint8(v)
-	return offset + 1
-}
-func (m *ID) Size() (n int) {
-	var l int
-	_ = l
-	l = len(m.PublicKey)
-	if l > 0 {
-		n += 1 + l + sovStream(uint64(l))
+	if that1 == nil {
+		return this == nil
+	} else if this == nil {
+		return false
 	}
-	l = len(m.Address)
-	if l > 0 {
-		n += 1 + l + sovStream(uint64(l))
+	if this.Round != that1.Round {
+		return false
 	}
-	l = len(m.Id)
-	if l > 0 {
-		n += 1 + l + sovStream(uint64(l))
+	if this.Index != that1.Index {
+		return false
 	}
-	return n
+	if !bytes.Equal(this.Signature, that1.Signature) {
+		return false
+	}
+	return true
 }
+func (this *ValueRecord) VerboseEqual(that interface{}) error {
+	if that == nil {
+		if this == nil {
+			return nil
+		}
+		return fmt.Errorf("that == nil && this != nil")
+	}
 
-func (m *Message) Size() (n int) {
-	var l int
-	_ = l
-	l = len(m.Message)
-	if l > 0 {
-		n += 1 + l + sovStream(uint64(l))
+	that1, ok := that.(*ValueRecord)
+	if !ok {
+		that2, ok := that.(ValueRecord)
+		if ok {
+			that1 = &that2
+		} else {
+			return fmt.Errorf("that is not of type *ValueRecord")
+		}
 	}
-	if m.Sender != nil {
-		l = m.Sender.Size()
-		n += 1 + l + sovStream(uint64(l))
+	if that1 == nil {
+		if this == nil {
+			return nil
+		}
+		return fmt.Errorf("that is type *ValueRecord but is nil && this != nil")
+	} else if this == nil {
+		return fmt.Errorf("that is type *ValueRecord but is not nil && this == nil")
 	}
-	l = len(m.Signature)
-	if l > 0 {
-		n += 1 + l + sovStream(uint64(l))
+	if !bytes.Equal(this.Key, that1.Key) {
+		return fmt.Errorf("Key this(%v) Not Equal that(%v)", this.Key, that1.Key)
 	}
-	if m.RequestNonce != 0 {
-		n += 1 + sovStream(uint64(m.RequestNonce))
+	if !bytes.Equal(this.Value, that1.Value) {
+		return fmt.Errorf("Value this(%v) Not Equal that(%v)", this.Value, that1.Value)
 	}
-	if m.MessageNonce != 0 {
-		n += 1 + sovStream(uint64(m.MessageNonce))
+	if !this.Publisher.Equal(that1.Publisher) {
+		return fmt.Errorf("Publisher this(%v) Not Equal that(%v)", this.Publisher, that1.Publisher)
 	}
-	if m.ReplyFlag {
-		n += 2
+	if this.Expiry != that1.Expiry {
+		return fmt.Errorf("Expiry this(%v) Not Equal that(%v)", this.Expiry, that1.Expiry)
 	}
-	if m.Opcode != 0 {
-		n += 1 + sovStream(uint64(m.Opcode))
+	if !bytes.Equal(this.Signature, that1.Signature) {
+		return fmt.Errorf("Signature this(%v) Not Equal that(%v)", this.Signature, that1.Signature)
 	}
-	return n
-}
-
-func (m *Ping) Size() (n int) {
-	var l int
-	_ = l
-	return n
-}
-
-func (m *Pong) Size() (n int) {
-	var l int
-	_ = l
-	return n
+	return nil
 }
-
-func (m *LookupNodeRequest) Size() (n int) {
-	var l int
-	_ = l
-	if m.Target != nil {
-		l = m.Target.Size()
-		n += 1 + l + sovStream(uint64(l))
+func (this *ValueRecord) Equal(that interface{}) bool {
+	if that == nil {
+		return this == nil
 	}
-	return n
-}
 
-func (m *LookupNodeResponse) Size() (n int) {
-	var l int
-	_ = l
-	if len(m.Peers) > 0 {
-		for _, e := range m.Peers {
-			l = e.Size()
-			n += 1 + l + sovStream(uint64(l))
+	that1, ok := that.(*ValueRecord)
+	if !ok {
+		that2, ok := that.(ValueRecord)
+		if ok {
+			that1 = &that2
+		} else {
+			return false
 		}
 	}
-	return n
+	if that1 == nil {
+		return this == nil
+	} else if this == nil {
+		return false
+	}
+	if !bytes.Equal(this.Key, that1.Key) {
+		return false
+	}
+	if !bytes.Equal(this.Value, that1.Value) {
+		return false
+	}
+	if !this.Publisher.Equal(that1.Publisher) {
+		return false
+	}
+	if this.Expiry != that1.Expiry {
+		return false
+	}
+	if !bytes.Equal(this.Signature, that1.Signature) {
+		return false
+	}
+	return true
 }
+func (this *StoreRequest) VerboseEqual(that interface{}) error {
+	if that == nil {
+		if this == nil {
+			return nil
+		}
+		return fmt.Errorf("that == nil && this != nil")
+	}
 
-func (m *Bytes) Size() (n int) {
-	var l int
-	_ = l
-	l = len(m.Data)
-	if l > 0 {
-		n += 1 + l + sovStream(uint64(l))
+	that1, ok := that.(*StoreRequest)
+	if !ok {
+		that2, ok := that.(StoreRequest)
+		if ok {
+			that1 = &that2
+		} else {
+			return fmt.Errorf("that is not of type *StoreRequest")
+		}
 	}
-	return n
+	if that1 == nil {
+		if this == nil {
+			return nil
+		}
+		return fmt.Errorf("that is type *StoreRequest but is nil && this != nil")
+	} else if this == nil {
+		return fmt.Errorf("that is type *StoreRequest but is not nil && this == nil")
+	}
+	if !this.Record.Equal(that1.Record) {
+		return fmt.Errorf("Record this(%v) Not Equal that(%v)", this.Record, that1.Record)
+	}
+	return nil
 }
+func (this *StoreRequest) Equal(that interface{}) bool {
+	if that == nil {
+		return this == nil
+	}
 
-func sovStream(x uint64) (n int) {
-	for {
-		n++
-		x >>= 7
-		if x == 0 {
-			break
+	that1, ok := that.(*StoreRequest)
+	if !ok {
+		that2, ok := that.(StoreRequest)
+		if ok {
+			that1 = &that2
+		} else {
+			return false
 		}
 	}
-	return n
-}
-func sozStream(x uint64) (n int) {
-	return sovStream(uint64((x << 1) ^ uint64((int64(x) >> 63))))
-}
-func (this *ID) String() string {
-	if this == nil {
-		return "nil"
+	if that1 == nil {
+		return this == nil
+	} else if this == nil {
+		return false
 	}
-	s := strings.Join([]string{`&ID{`,
-		`PublicKey:` + fmt.Sprintf("%v", this.PublicKey) + `,`,
-		`Address:` + fmt.Sprintf("%v", this.Address) + `,`,
-		`Id:` + fmt.Sprintf("%v", this.Id) + `,`,
-		`}`,
-	}, "")
-	return s
+	if !this.Record.Equal(that1.Record) {
+		return false
+	}
+	return true
 }
-func (this *Message) String() string {
-	if this == nil {
-		return "nil"
+func (this *StoreResponse) VerboseEqual(that interface{}) error {
+	if that == nil {
+		if this == nil {
+			return nil
+		}
+		return fmt.Errorf("that == nil && this != nil")
 	}
-	s := strings.Join([]string{`&Message{`,
-		`Message:` + fmt.Sprintf("%v", this.Message) + `,`,
-		`Sender:` + strings.Replace(fmt.Sprintf("%v", this.Sender), "ID", "ID", 1) + `,`,
-		`Signature:` + fmt.Sprintf("%v", this.Signature) + `,`,
-		`RequestNonce:` + fmt.Sprintf("%v", this.RequestNonce) + `,`,
-		`MessageNonce:` + fmt.Sprintf("%v", this.MessageNonce) + `,`,
-		`ReplyFlag:` + fmt.Sprintf("%v", this.ReplyFlag) + `,`,
-		`Opcode:` + fmt.Sprintf("%v", this.Opcode) + `,`,
-		`}`,
-	}, "")
-	return s
+
+	that1, ok := that.(*StoreResponse)
+	if !ok {
+		that2, ok := that.(StoreResponse)
+		if ok {
+			that1 = &that2
+		} else {
+			return fmt.Errorf("that is not of type *StoreResponse")
+		}
+	}
+	if that1 == nil {
+		if this == nil {
+			return nil
+		}
+		return fmt.Errorf("that is type *StoreResponse but is nil && this != nil")
+	} else if this == nil {
+		return fmt.Errorf("that is type *StoreResponse but is not nil && this == nil")
+	}
+	if this.Stored != that1.Stored {
+		return fmt.Errorf("Stored this(%v) Not Equal that(%v)", this.Stored, that1.Stored)
+	}
+	return nil
 }
-func (this *Ping) String() string {
-	if this == nil {
-		return "nil"
+func (this *StoreResponse) Equal(that interface{}) bool {
+	if that == nil {
+		return this == nil
 	}
-	s := strings.Join([]string{`&Ping{`,
-		`}`,
-	}, "")
-	return s
+
+	that1, ok := that.(*StoreResponse)
+	if !ok {
+		that2, ok := that.(StoreResponse)
+		if ok {
+			that1 = &that2
+		} else {
+			return false
+		}
+	}
+	if that1 == nil {
+		return this == nil
+	} else if this == nil {
+		return false
+	}
+	if this.Stored != that1.Stored {
+		return false
+	}
+	return true
 }
-func (this *Pong) String() string {
-	if this == nil {
-		return "nil"
+func (this *FindValueRequest) VerboseEqual(that interface{}) error {
+	if that == nil {
+		if this == nil {
+			return nil
+		}
+		return fmt.Errorf("that == nil && this != nil")
 	}
-	s := strings.Join([]string{`&Pong{`,
-		`}`,
-	}, "")
-	return s
+
+	that1, ok := that.(*FindValueRequest)
+	if !ok {
+		that2, ok := that.(FindValueRequest)
+		if ok {
+			that1 = &that2
+		} else {
+			return fmt.Errorf("that is not of type *FindValueRequest")
+		}
+	}
+	if that1 == nil {
+		if this == nil {
+			return nil
+		}
+		return fmt.Errorf("that is type *FindValueRequest but is nil && this != nil")
+	} else if this == nil {
+		return fmt.Errorf("that is type *FindValueRequest but is not nil && this == nil")
+	}
+	if !bytes.Equal(this.Key, that1.Key) {
+		return fmt.Errorf("Key this(%v) Not Equal that(%v)", this.Key, that1.Key)
+	}
+	return nil
 }
-func (this *LookupNodeRequest) String() string {
-	if this == nil {
-		return "nil"
+func (this *FindValueRequest) Equal(that interface{}) bool {
+	if that == nil {
+		return this == nil
 	}
-	s := strings.Join([]string{`&LookupNodeRequest{`,
-		`Target:` + strings.Replace(fmt.Sprintf("%v", this.Target), "ID", "ID", 1) + `,`,
-		`}`,
-	}, "")
-	return s
+
+	that1, ok := that.(*FindValueRequest)
+	if !ok {
+		that2, ok := that.(FindValueRequest)
+		if ok {
+			that1 = &that2
+		} else {
+			return false
+		}
+	}
+	if that1 == nil {
+		return this == nil
+	} else if this == nil {
+		return false
+	}
+	if !bytes.Equal(this.Key, that1.Key) {
+		return false
+	}
+	return true
 }
-func (this *LookupNodeResponse) String() string {
-	if this == nil {
-		return "nil"
+func (this *FindValueResponse) VerboseEqual(that interface{}) error {
+	if that == nil {
+		if this == nil {
+			return nil
+		}
+		return fmt.Errorf("that == nil && this != nil")
 	}
-	s := strings.Join([]string{`&LookupNodeResponse{`,
-		`Peers:` + strings.Replace(fmt.Sprintf("%v", this.Peers), "ID", "ID", 1) + `,`,
-		`}`,
-	}, "")
-	return s
+
+	that1, ok := that.(*FindValueResponse)
+	if !ok {
+		that2, ok := that.(FindValueResponse)
+		if ok {
+			that1 = &that2
+		} else {
+			return fmt.Errorf("that is not of type *FindValueResponse")
+		}
+	}
+	if that1 == nil {
+		if this == nil {
+			return nil
+		}
+		return fmt.Errorf("that is type *FindValueResponse but is nil && this != nil")
+	} else if this == nil {
+		return fmt.Errorf("that is type *FindValueResponse but is not nil && this == nil")
+	}
+	if !this.Record.Equal(that1.Record) {
+		return fmt.Errorf("Record this(%v) Not Equal that(%v)", this.Record, that1.Record)
+	}
+	if len(this.Peers) != len(that1.Peers) {
+		return fmt.Errorf("Peers this(%v) Not Equal that(%v)", len(this.Peers), len(that1.Peers))
+	}
+	for i := range this.Peers {
+		if !this.Peers[i].Equal(that1.Peers[i]) {
+			return fmt.Errorf("Peers this[%v](%v) Not Equal that[%v](%v)", i, this.Peers[i], i, that1.Peers[i])
+		}
+	}
+	return nil
 }
-func (this *Bytes) String() string {
-	if this == nil {
-		return "nil"
+func (this *FindValueResponse) Equal(that interface{}) bool {
+	if that == nil {
+		return this == nil
+	}
+
+	that1, ok := that.(*FindValueResponse)
+	if !ok {
+		that2, ok := that.(FindValueResponse)
+		if ok {
+			that1 = &that2
+		} else {
+			return false
+		}
+	}
+	if that1 == nil {
+		return this == nil
+	} else if this == nil {
+		return false
+	}
+	if !this.Record.Equal(that1.Record) {
+		return false
+	}
+	if len(this.Peers) != len(that1.Peers) {
+		return false
+	}
+	for i := range this.Peers {
+		if !this.Peers[i].Equal(that1.Peers[i]) {
+			return false
+		}
+	}
+	return true
+}
+func (this *Subscribe) VerboseEqual(that interface{}) error {
+	if that == nil {
+		if this == nil {
+			return nil
+		}
+		return fmt.Errorf("that == nil && this != nil")
+	}
+
+	that1, ok := that.(*Subscribe)
+	if !ok {
+		that2, ok := that.(Subscribe)
+		if ok {
+			that1 = &that2
+		} else {
+			return fmt.Errorf("that is not of type *Subscribe")
+		}
+	}
+	if that1 == nil {
+		if this == nil {
+			return nil
+		}
+		return fmt.Errorf("that is type *Subscribe but is nil && this != nil")
+	} else if this == nil {
+		return fmt.Errorf("that is type *Subscribe but is not nil && this == nil")
+	}
+	if len(this.Topics) != len(that1.Topics) {
+		return fmt.Errorf("Topics this(%v) Not Equal that(%v)", len(this.Topics), len(that1.Topics))
+	}
+	for i := range this.Topics {
+		if this.Topics[i] != that1.Topics[i] {
+			return fmt.Errorf("Topics this[%v](%v) Not Equal that[%v](%v)", i, this.Topics[i], i, that1.Topics[i])
+		}
+	}
+	return nil
+}
+func (this *Subscribe) Equal(that interface{}) bool {
+	if that == nil {
+		return this == nil
+	}
+
+	that1, ok := that.(*Subscribe)
+	if !ok {
+		that2, ok := that.(Subscribe)
+		if ok {
+			that1 = &that2
+		} else {
+			return false
+		}
+	}
+	if that1 == nil {
+		return this == nil
+	} else if this == nil {
+		return false
+	}
+	if len(this.Topics) != len(that1.Topics) {
+		return false
+	}
+	for i := range this.Topics {
+		if this.Topics[i] != that1.Topics[i] {
+			return false
+		}
+	}
+	return true
+}
+func (this *Publish) VerboseEqual(that interface{}) error {
+	if that == nil {
+		if this == nil {
+			return nil
+		}
+		return fmt.Errorf("that == nil && this != nil")
+	}
+
+	that1, ok := that.(*Publish)
+	if !ok {
+		that2, ok := that.(Publish)
+		if ok {
+			that1 = &that2
+		} else {
+			return fmt.Errorf("that is not of type *Publish")
+		}
+	}
+	if that1 == nil {
+		if this == nil {
+			return nil
+		}
+		return fmt.Errorf("that is type *Publish but is nil && this != nil")
+	} else if this == nil {
+		return fmt.Errorf("that is type *Publish but is not nil && this == nil")
+	}
+	if !bytes.Equal(this.Id, that1.Id) {
+		return fmt.Errorf("Id this(%v) Not Equal that(%v)", this.Id, that1.Id)
+	}
+	if this.Topic != that1.Topic {
+		return fmt.Errorf("Topic this(%v) Not Equal that(%v)", this.Topic, that1.Topic)
+	}
+	if !bytes.Equal(this.Data, that1.Data) {
+		return fmt.Errorf("Data this(%v) Not Equal that(%v)", this.Data, that1.Data)
+	}
+	return nil
+}
+func (this *Publish) Equal(that interface{}) bool {
+	if that == nil {
+		return this == nil
+	}
+
+	that1, ok := that.(*Publish)
+	if !ok {
+		that2, ok := that.(Publish)
+		if ok {
+			that1 = &that2
+		} else {
+			return false
+		}
+	}
+	if that1 == nil {
+		return this == nil
+	} else if this == nil {
+		return false
+	}
+	if !bytes.Equal(this.Id, that1.Id) {
+		return false
+	}
+	if this.Topic != that1.Topic {
+		return false
+	}
+	if !bytes.Equal(this.Data, that1.Data) {
+		return false
+	}
+	return true
+}
+func (this *BloomFilter) VerboseEqual(that interface{}) error {
+	if that == nil {
+		if this == nil {
+			return nil
+		}
+		return fmt.Errorf("that == nil && this != nil")
+	}
+
+	that1, ok := that.(*BloomFilter)
+	if !ok {
+		that2, ok := that.(BloomFilter)
+		if ok {
+			that1 = &that2
+		} else {
+			return fmt.Errorf("that is not of type *BloomFilter")
+		}
+	}
+	if that1 == nil {
+		if this == nil {
+			return nil
+		}
+		return fmt.Errorf("that is type *BloomFilter but is nil && this != nil")
+	} else if this == nil {
+		return fmt.Errorf("that is type *BloomFilter but is not nil && this == nil")
+	}
+	if !bytes.Equal(this.Bits, that1.Bits) {
+		return fmt.Errorf("Bits this(%v) Not Equal that(%v)", this.Bits, that1.Bits)
+	}
+	if this.Hashes != that1.Hashes {
+		return fmt.Errorf("Hashes this(%v) Not Equal that(%v)", this.Hashes, that1.Hashes)
+	}
+	if this.Seed != that1.Seed {
+		return fmt.Errorf("Seed this(%v) Not Equal that(%v)", this.Seed, that1.Seed)
+	}
+	return nil
+}
+func (this *BloomFilter) Equal(that interface{}) bool {
+	if that == nil {
+		return this == nil
+	}
+
+	that1, ok := that.(*BloomFilter)
+	if !ok {
+		that2, ok := that.(BloomFilter)
+		if ok {
+			that1 = &that2
+		} else {
+			return false
+		}
+	}
+	if that1 == nil {
+		return this == nil
+	} else if this == nil {
+		return false
+	}
+	if !bytes.Equal(this.Bits, that1.Bits) {
+		return false
+	}
+	if this.Hashes != that1.Hashes {
+		return false
+	}
+	if this.Seed != that1.Seed {
+		return false
+	}
+	return true
+}
+func (this *Transactions) VerboseEqual(that interface{}) error {
+	if that == nil {
+		if this == nil {
+			return nil
+		}
+		return fmt.Errorf("that == nil && this != nil")
+	}
+
+	that1, ok := that.(*Transactions)
+	if !ok {
+		that2, ok := that.(Transactions)
+		if ok {
+			that1 = &that2
+		} else {
+			return fmt.Errorf("that is not of type *Transactions")
+		}
+	}
+	if that1 == nil {
+		if this == nil {
+			return nil
+		}
+		return fmt.Errorf("that is type *Transactions but is nil && this != nil")
+	} else if this == nil {
+		return fmt.Errorf("that is type *Transactions but is not nil && this == nil")
+	}
+	if len(this.Txs) != len(that1.Txs) {
+		return fmt.Errorf("Txs this(%v) Not Equal that(%v)", len(this.Txs), len(that1.Txs))
+	}
+	for i := range this.Txs {
+		if !bytes.Equal(this.Txs[i], that1.Txs[i]) {
+			return fmt.Errorf("Txs this[%v](%v) Not Equal that[%v](%v)", i, this.Txs[i], i, that1.Txs[i])
+		}
+	}
+	return nil
+}
+func (this *Transactions) Equal(that interface{}) bool {
+	if that == nil {
+		return this == nil
+	}
+
+	that1, ok := that.(*Transactions)
+	if !ok {
+		that2, ok := that.(Transactions)
+		if ok {
+			that1 = &that2
+		} else {
+			return false
+		}
+	}
+	if that1 == nil {
+		return this == nil
+	} else if this == nil {
+		return false
+	}
+	if len(this.Txs) != len(that1.Txs) {
+		return false
+	}
+	for i := range this.Txs {
+		if !bytes.Equal(this.Txs[i], that1.Txs[i]) {
+			return false
+		}
+	}
+	return true
+}
+func (this *TxSyncRequest) VerboseEqual(that interface{}) error {
+	if that == nil {
+		if this == nil {
+			return nil
+		}
+		return fmt.Errorf("that == nil && this != nil")
+	}
+
+	that1, ok := that.(*TxSyncRequest)
+	if !ok {
+		that2, ok := that.(TxSyncRequest)
+		if ok {
+			that1 = &that2
+		} else {
+			return fmt.Errorf("that is not of type *TxSyncRequest")
+		}
+	}
+	if that1 == nil {
+		if this == nil {
+			return nil
+		}
+		return fmt.Errorf("that is type *TxSyncRequest but is nil && this != nil")
+	} else if this == nil {
+		return fmt.Errorf("that is type *TxSyncRequest but is not nil && this == nil")
+	}
+	if !this.Filter.Equal(that1.Filter) {
+		return fmt.Errorf("Filter this(%v) Not Equal that(%v)", this.Filter, that1.Filter)
+	}
+	return nil
+}
+func (this *TxSyncRequest) Equal(that interface{}) bool {
+	if that == nil {
+		return this == nil
+	}
+
+	that1, ok := that.(*TxSyncRequest)
+	if !ok {
+		that2, ok := that.(TxSyncRequest)
+		if ok {
+			that1 = &that2
+		} else {
+			return false
+		}
+	}
+	if that1 == nil {
+		return this == nil
+	} else if this == nil {
+		return false
+	}
+	if !this.Filter.Equal(that1.Filter) {
+		return false
+	}
+	return true
+}
+func (this *TxSyncResponse) VerboseEqual(that interface{}) error {
+	if that == nil {
+		if this == nil {
+			return nil
+		}
+		return fmt.Errorf("that == nil && this != nil")
+	}
+
+	that1, ok := that.(*TxSyncResponse)
+	if !ok {
+		that2, ok := that.(TxSyncResponse)
+		if ok {
+			that1 = &that2
+		} else {
+			return fmt.Errorf("that is not of type *TxSyncResponse")
+		}
+	}
+	if that1 == nil {
+		if this == nil {
+			return nil
+		}
+		return fmt.Errorf("that is type *TxSyncResponse but is nil && this != nil")
+	} else if this == nil {
+		return fmt.Errorf("that is type *TxSyncResponse but is not nil && this == nil")
+	}
+	if len(this.Txs) != len(that1.Txs) {
+		return fmt.Errorf("Txs this(%v) Not Equal that(%v)", len(this.Txs), len(that1.Txs))
+	}
+	for i := range this.Txs {
+		if !bytes.Equal(this.Txs[i], that1.Txs[i]) {
+			return fmt.Errorf("Txs this[%v](%v) Not Equal that[%v](%v)", i, this.Txs[i], i, that1.Txs[i])
+		}
+	}
+	if !this.Filter.Equal(that1.Filter) {
+		return fmt.Errorf("Filter this(%v) Not Equal that(%v)", this.Filter, that1.Filter)
+	}
+	return nil
+}
+func (this *TxSyncResponse) Equal(that interface{}) bool {
+	if that == nil {
+		return this == nil
+	}
+
+	that1, ok := that.(*TxSyncResponse)
+	if !ok {
+		that2, ok := that.(TxSyncResponse)
+		if ok {
+			that1 = &that2
+		} else {
+			return false
+		}
+	}
+	if that1 == nil {
+		return this == nil
+	} else if this == nil {
+		return false
+	}
+	if len(this.Txs) != len(that1.Txs) {
+		return false
+	}
+	for i := range this.Txs {
+		if !bytes.Equal(this.Txs[i], that1.Txs[i]) {
+			return false
+		}
+	}
+	if !this.Filter.Equal(that1.Filter) {
+		return false
+	}
+	return true
+}
+func (this *ReliableMessage) VerboseEqual(that interface{}) error {
+	if that == nil {
+		if this == nil {
+			return nil
+		}
+		return fmt.Errorf("that == nil && this != nil")
+	}
+
+	that1, ok := that.(*ReliableMessage)
+	if !ok {
+		that2, ok := that.(ReliableMessage)
+		if ok {
+			that1 = &that2
+		} else {
+			return fmt.Errorf("that is not of type *ReliableMessage")
+		}
+	}
+	if that1 == nil {
+		if this == nil {
+			return nil
+		}
+		return fmt.Errorf("that is type *ReliableMessage but is nil && this != nil")
+	} else if this == nil {
+		return fmt.Errorf("that is type *ReliableMessage but is not nil && this == nil")
+	}
+	if this.Sequence != that1.Sequence {
+		return fmt.Errorf("Sequence this(%v) Not Equal that(%v)", this.Sequence, that1.Sequence)
+	}
+	if !bytes.Equal(this.Data, that1.Data) {
+		return fmt.Errorf("Data this(%v) Not Equal that(%v)", this.Data, that1.Data)
+	}
+	return nil
+}
+func (this *ReliableMessage) Equal(that interface{}) bool {
+	if that == nil {
+		return this == nil
+	}
+
+	that1, ok := that.(*ReliableMessage)
+	if !ok {
+		that2, ok := that.(ReliableMessage)
+		if ok {
+			that1 = &that2
+		} else {
+			return false
+		}
+	}
+	if that1 == nil {
+		return this == nil
+	} else if this == nil {
+		return false
+	}
+	if this.Sequence != that1.Sequence {
+		return false
+	}
+	if !bytes.Equal(this.Data, that1.Data) {
+		return false
+	}
+	return true
+}
+func (this *ReliableAck) VerboseEqual(that interface{}) error {
+	if that == nil {
+		if this == nil {
+			return nil
+		}
+		return fmt.Errorf("that == nil && this != nil")
+	}
+
+	that1, ok := that.(*ReliableAck)
+	if !ok {
+		that2, ok := that.(ReliableAck)
+		if ok {
+			that1 = &that2
+		} else {
+			return fmt.Errorf("that is not of type *ReliableAck")
+		}
+	}
+	if that1 == nil {
+		if this == nil {
+			return nil
+		}
+		return fmt.Errorf("that is type *ReliableAck but is nil && this != nil")
+	} else if this == nil {
+		return fmt.Errorf("that is type *ReliableAck but is not nil && this == nil")
+	}
+	if this.Sequence != that1.Sequence {
+		return fmt.Errorf("Sequence this(%v) Not Equal that(%v)", this.Sequence, that1.Sequence)
+	}
+	return nil
+}
+func (this *ReliableAck) Equal(that interface{}) bool {
+	if that == nil {
+		return this == nil
+	}
+
+	that1, ok := that.(*ReliableAck)
+	if !ok {
+		that2, ok := that.(ReliableAck)
+		if ok {
+			that1 = &that2
+		} else {
+			return false
+		}
+	}
+	if that1 == nil {
+		return this == nil
+	} else if this == nil {
+		return false
+	}
+	if this.Sequence != that1.Sequence {
+		return false
+	}
+	return true
+}
+func (this *BlockHeader) VerboseEqual(that interface{}) error {
+	if that == nil {
+		if this == nil {
+			return nil
+		}
+		return fmt.Errorf("that == nil && this != nil")
+	}
+
+	that1, ok := that.(*BlockHeader)
+	if !ok {
+		that2, ok := that.(BlockHeader)
+		if ok {
+			that1 = &that2
+		} else {
+			return fmt.Errorf("that is not of type *BlockHeader")
+		}
+	}
+	if that1 == nil {
+		if this == nil {
+			return nil
+		}
+		return fmt.Errorf("that is type *BlockHeader but is nil && this != nil")
+	} else if this == nil {
+		return fmt.Errorf("that is type *BlockHeader but is not nil && this == nil")
+	}
+	if !bytes.Equal(this.Hash, that1.Hash) {
+		return fmt.Errorf("Hash this(%v) Not Equal that(%v)", this.Hash, that1.Hash)
+	}
+	if this.Height != that1.Height {
+		return fmt.Errorf("Height this(%v) Not Equal that(%v)", this.Height, that1.Height)
+	}
+	return nil
+}
+func (this *BlockHeader) Equal(that interface{}) bool {
+	if that == nil {
+		return this == nil
+	}
+
+	that1, ok := that.(*BlockHeader)
+	if !ok {
+		that2, ok := that.(BlockHeader)
+		if ok {
+			that1 = &that2
+		} else {
+			return false
+		}
+	}
+	if that1 == nil {
+		return this == nil
+	} else if this == nil {
+		return false
+	}
+	if !bytes.Equal(this.Hash, that1.Hash) {
+		return false
+	}
+	if this.Height != that1.Height {
+		return false
+	}
+	return true
+}
+func (this *GetBlock) VerboseEqual(that interface{}) error {
+	if that == nil {
+		if this == nil {
+			return nil
+		}
+		return fmt.Errorf("that == nil && this != nil")
+	}
+
+	that1, ok := that.(*GetBlock)
+	if !ok {
+		that2, ok := that.(GetBlock)
+		if ok {
+			that1 = &that2
+		} else {
+			return fmt.Errorf("that is not of type *GetBlock")
+		}
+	}
+	if that1 == nil {
+		if this == nil {
+			return nil
+		}
+		return fmt.Errorf("that is type *GetBlock but is nil && this != nil")
+	} else if this == nil {
+		return fmt.Errorf("that is type *GetBlock but is not nil && this == nil")
+	}
+	if !bytes.Equal(this.Hash, that1.Hash) {
+		return fmt.Errorf("Hash this(%v) Not Equal that(%v)", this.Hash, that1.Hash)
+	}
+	return nil
+}
+func (this *GetBlock) Equal(that interface{}) bool {
+	if that == nil {
+		return this == nil
+	}
+
+	that1, ok := that.(*GetBlock)
+	if !ok {
+		that2, ok := that.(GetBlock)
+		if ok {
+			that1 = &that2
+		} else {
+			return false
+		}
+	}
+	if that1 == nil {
+		return this == nil
+	} else if this == nil {
+		return false
+	}
+	if !bytes.Equal(this.Hash, that1.Hash) {
+		return false
+	}
+	return true
+}
+func (this *Block) VerboseEqual(that interface{}) error {
+	if that == nil {
+		if this == nil {
+			return nil
+		}
+		return fmt.Errorf("that == nil && this != nil")
+	}
+
+	that1, ok := that.(*Block)
+	if !ok {
+		that2, ok := that.(Block)
+		if ok {
+			that1 = &that2
+		} else {
+			return fmt.Errorf("that is not of type *Block")
+		}
+	}
+	if that1 == nil {
+		if this == nil {
+			return nil
+		}
+		return fmt.Errorf("that is type *Block but is nil && this != nil")
+	} else if this == nil {
+		return fmt.Errorf("that is type *Block but is not nil && this == nil")
+	}
+	if this.Height != that1.Height {
+		return fmt.Errorf("Height this(%v) Not Equal that(%v)", this.Height, that1.Height)
+	}
+	if !bytes.Equal(this.Data, that1.Data) {
+		return fmt.Errorf("Data this(%v) Not Equal that(%v)", this.Data, that1.Data)
+	}
+	return nil
+}
+func (this *Block) Equal(that interface{}) bool {
+	if that == nil {
+		return this == nil
+	}
+
+	that1, ok := that.(*Block)
+	if !ok {
+		that2, ok := that.(Block)
+		if ok {
+			that1 = &that2
+		} else {
+			return false
+		}
+	}
+	if that1 == nil {
+		return this == nil
+	} else if this == nil {
+		return false
+	}
+	if this.Height != that1.Height {
+		return false
+	}
+	if !bytes.Equal(this.Data, that1.Data) {
+		return false
+	}
+	return true
+}
+func (this *KeyRevocation) VerboseEqual(that interface{}) error {
+	if that == nil {
+		if this == nil {
+			return nil
+		}
+		return fmt.Errorf("that == nil && this != nil")
+	}
+
+	that1, ok := that.(*KeyRevocation)
+	if !ok {
+		that2, ok := that.(KeyRevocation)
+		if ok {
+			that1 = &that2
+		} else {
+			return fmt.Errorf("that is not of type *KeyRevocation")
+		}
+	}
+	if that1 == nil {
+		if this == nil {
+			return nil
+		}
+		return fmt.Errorf("that is type *KeyRevocation but is nil && this != nil")
+	} else if this == nil {
+		return fmt.Errorf("that is type *KeyRevocation but is not nil && this == nil")
+	}
+	if !bytes.Equal(this.OldPublicKey, that1.OldPublicKey) {
+		return fmt.Errorf("OldPublicKey this(%v) Not Equal that(%v)", this.OldPublicKey, that1.OldPublicKey)
+	}
+	if !bytes.Equal(this.NewPublicKey, that1.NewPublicKey) {
+		return fmt.Errorf("NewPublicKey this(%v) Not Equal that(%v)", this.NewPublicKey, that1.NewPublicKey)
+	}
+	if !bytes.Equal(this.Signature, that1.Signature) {
+		return fmt.Errorf("Signature this(%v) Not Equal that(%v)", this.Signature, that1.Signature)
+	}
+	if this.Timestamp != that1.Timestamp {
+		return fmt.Errorf("Timestamp this(%v) Not Equal that(%v)", this.Timestamp, that1.Timestamp)
+	}
+	if !bytes.Equal(this.NewSignature, that1.NewSignature) {
+		return fmt.Errorf("NewSignature this(%v) Not Equal that(%v)", this.NewSignature, that1.NewSignature)
+	}
+	return nil
+}
+func (this *KeyRevocation) Equal(that interface{}) bool {
+	if that == nil {
+		return this == nil
+	}
+
+	that1, ok := that.(*KeyRevocation)
+	if !ok {
+		that2, ok := that.(KeyRevocation)
+		if ok {
+			that1 = &that2
+		} else {
+			return false
+		}
+	}
+	if that1 == nil {
+		return this == nil
+	} else if this == nil {
+		return false
+	}
+	if !bytes.Equal(this.OldPublicKey, that1.OldPublicKey) {
+		return false
+	}
+	if !bytes.Equal(this.NewPublicKey, that1.NewPublicKey) {
+		return false
+	}
+	if !bytes.Equal(this.Signature, that1.Signature) {
+		return false
+	}
+	if this.Timestamp != that1.Timestamp {
+		return false
+	}
+	if !bytes.Equal(this.NewSignature, that1.NewSignature) {
+		return false
+	}
+	return true
+}
+func (this *ID) GoString() string {
+	if this == nil {
+		return "nil"
+	}
+	s := make([]string, 0, 8)
+	s = append(s, "&protobuf.ID{")
+	s = append(s, "PublicKey: "+fmt.Sprintf("%#v", this.PublicKey)+",\n")
+	s = append(s, "Address: "+fmt.Sprintf("%#v", this.Address)+",\n")
+	s = append(s, "Id: "+fmt.Sprintf("%#v", this.Id)+",\n")
+	s = append(s, "Multiaddr: "+fmt.Sprintf("%#v", this.Multiaddr)+",\n")
+	s = append(s, "}")
+	return strings.Join(s, "")
+}
+func (this *Message) GoString() string {
+	if this == nil {
+		return "nil"
+	}
+	s := make([]string, 0, 18)
+	s = append(s, "&protobuf.Message{")
+	s = append(s, "Message: "+fmt.Sprintf("%#v", this.Message)+",\n")
+	if this.Sender != nil {
+		s = append(s, "Sender: "+fmt.Sprintf("%#v", this.Sender)+",\n")
+	}
+	s = append(s, "Signature: "+fmt.Sprintf("%#v", this.Signature)+",\n")
+	s = append(s, "RequestNonce: "+fmt.Sprintf("%#v", this.RequestNonce)+",\n")
+	s = append(s, "MessageNonce: "+fmt.Sprintf("%#v", this.MessageNonce)+",\n")
+	s = append(s, "ReplyFlag: "+fmt.Sprintf("%#v", this.ReplyFlag)+",\n")
+	s = append(s, "Opcode: "+fmt.Sprintf("%#v", this.Opcode)+",\n")
+	if this.RelayedFrom != nil {
+		s = append(s, "RelayedFrom: "+fmt.Sprintf("%#v", this.RelayedFrom)+",\n")
+	}
+	s = append(s, "Sequence: "+fmt.Sprintf("%#v", this.Sequence)+",\n")
+	keysForTraceContext := make([]string, 0, len(this.TraceContext))
+	for k, _ := range this.TraceContext {
+		keysForTraceContext = append(keysForTraceContext, k)
+	}
+	sortkeys.Strings(keysForTraceContext)
+	mapStringForTraceContext := "map[string]string{"
+	for _, k := range keysForTraceContext {
+		mapStringForTraceContext += fmt.Sprintf("%#v: %#v,", k, this.TraceContext[k])
+	}
+	mapStringForTraceContext += "}"
+	if this.TraceContext != nil {
+		s = append(s, "TraceContext: "+mapStringForTraceContext+",\n")
+	}
+	s = append(s, "GroupId: "+fmt.Sprintf("%#v", this.GroupId)+",\n")
+	s = append(s, "Compressed: "+fmt.Sprintf("%#v", this.Compressed)+",\n")
+	s = append(s, "Timeout: "+fmt.Sprintf("%#v", this.Timeout)+",\n")
+	s = append(s, "RelaySignature: "+fmt.Sprintf("%#v", this.RelaySignature)+",\n")
+	s = append(s, "}")
+	return strings.Join(s, "")
+}
+func (this *Ping) GoString() string {
+	if this == nil {
+		return "nil"
+	}
+	s := make([]string, 0, 5)
+	s = append(s, "&protobuf.Ping{")
+	s = append(s, "Capabilities: "+fmt.Sprintf("%#v", this.Capabilities)+",\n")
+	s = append(s, "}")
+	return strings.Join(s, "")
+}
+func (this *Pong) GoString() string {
+	if this == nil {
+		return "nil"
+	}
+	s := make([]string, 0, 5)
+	s = append(s, "&protobuf.Pong{")
+	s = append(s, "Capabilities: "+fmt.Sprintf("%#v", this.Capabilities)+",\n")
+	s = append(s, "}")
+	return strings.Join(s, "")
+}
+func (this *LookupNodeRequest) GoString() string {
+	if this == nil {
+		return "nil"
+	}
+	s := make([]string, 0, 5)
+	s = append(s, "&protobuf.LookupNodeRequest{")
+	if this.Target != nil {
+		s = append(s, "Target: "+fmt.Sprintf("%#v", this.Target)+",\n")
+	}
+	s = append(s, "}")
+	return strings.Join(s, "")
+}
+func (this *LookupNodeResponse) GoString() string {
+	if this == nil {
+		return "nil"
+	}
+	s := make([]string, 0, 5)
+	s = append(s, "&protobuf.LookupNodeResponse{")
+	if this.Peers != nil {
+		s = append(s, "Peers: "+fmt.Sprintf("%#v", this.Peers)+",\n")
+	}
+	s = append(s, "}")
+	return strings.Join(s, "")
+}
+func (this *Bytes) GoString() string {
+	if this == nil {
+		return "nil"
+	}
+	s := make([]string, 0, 5)
+	s = append(s, "&protobuf.Bytes{")
+	s = append(s, "Data: "+fmt.Sprintf("%#v", this.Data)+",\n")
+	s = append(s, "}")
+	return strings.Join(s, "")
+}
+func (this *AddressMigration) GoString() string {
+	if this == nil {
+		return "nil"
+	}
+	s := make([]string, 0, 7)
+	s = append(s, "&protobuf.AddressMigration{")
+	s = append(s, "NewAddress: "+fmt.Sprintf("%#v", this.NewAddress)+",\n")
+	s = append(s, "Signature: "+fmt.Sprintf("%#v", this.Signature)+",\n")
+	s = append(s, "Timestamp: "+fmt.Sprintf("%#v", this.Timestamp)+",\n")
+	s = append(s, "}")
+	return strings.Join(s, "")
+}
+func (this *MembershipUpdate) GoString() string {
+	if this == nil {
+		return "nil"
+	}
+	s := make([]string, 0, 9)
+	s = append(s, "&protobuf.MembershipUpdate{")
+	if this.Member != nil {
+		s = append(s, "Member: "+fmt.Sprintf("%#v", this.Member)+",\n")
+	}
+	s = append(s, "State: "+fmt.Sprintf("%#v", this.State)+",\n")
+	s = append(s, "Incarnation: "+fmt.Sprintf("%#v", this.Incarnation)+",\n")
+	if this.Origin != nil {
+		s = append(s, "Origin: "+fmt.Sprintf("%#v", this.Origin)+",\n")
+	}
+	s = append(s, "Signature: "+fmt.Sprintf("%#v", this.Signature)+",\n")
+	s = append(s, "}")
+	return strings.Join(s, "")
+}
+func (this *SwimPing) GoString() string {
+	if this == nil {
+		return "nil"
+	}
+	s := make([]string, 0, 5)
+	s = append(s, "&protobuf.SwimPing{")
+	if this.Updates != nil {
+		s = append(s, "Updates: "+fmt.Sprintf("%#v", this.Updates)+",\n")
+	}
+	s = append(s, "}")
+	return strings.Join(s, "")
+}
+func (this *SwimPingRequest) GoString() string {
+	if this == nil {
+		return "nil"
+	}
+	s := make([]string, 0, 6)
+	s = append(s, "&protobuf.SwimPingRequest{")
+	if this.Target != nil {
+		s = append(s, "Target: "+fmt.Sprintf("%#v", this.Target)+",\n")
+	}
+	if this.Updates != nil {
+		s = append(s, "Updates: "+fmt.Sprintf("%#v", this.Updates)+",\n")
+	}
+	s = append(s, "}")
+	return strings.Join(s, "")
+}
+func (this *SwimAck) GoString() string {
+	if this == nil {
+		return "nil"
+	}
+	s := make([]string, 0, 5)
+	s = append(s, "&protobuf.SwimAck{")
+	if this.Updates != nil {
+		s = append(s, "Updates: "+fmt.Sprintf("%#v", this.Updates)+",\n")
+	}
+	s = append(s, "}")
+	return strings.Join(s, "")
+}
+func (this *BeaconPartial) GoString() string {
+	if this == nil {
+		return "nil"
+	}
+	s := make([]string, 0, 7)
+	s = append(s, "&protobuf.BeaconPartial{")
+	s = append(s, "Round: "+fmt.Sprintf("%#v", this.Round)+",\n")
+	s = append(s, "Index: "+fmt.Sprintf("%#v", this.Index)+",\n")
+	s = append(s, "Signature: "+fmt.Sprintf("%#v", this.Signature)+",\n")
+	s = append(s, "}")
+	return strings.Join(s, "")
+}
+func (this *ValueRecord) GoString() string {
+	if this == nil {
+		return "nil"
+	}
+	s := make([]string, 0, 9)
+	s = append(s, "&protobuf.ValueRecord{")
+	s = append(s, "Key: "+fmt.Sprintf("%#v", this.Key)+",\n")
+	s = append(s, "Value: "+fmt.Sprintf("%#v", this.Value)+",\n")
+	if this.Publisher != nil {
+		s = append(s, "Publisher: "+fmt.Sprintf("%#v", this.Publisher)+",\n")
+	}
+	s = append(s, "Expiry: "+fmt.Sprintf("%#v", this.Expiry)+",\n")
+	s = append(s, "Signature: "+fmt.Sprintf("%#v", this.Signature)+",\n")
+	s = append(s, "}")
+	return strings.Join(s, "")
+}
+func (this *StoreRequest) GoString() string {
+	if this == nil {
+		return "nil"
+	}
+	s := make([]string, 0, 5)
+	s = append(s, "&protobuf.StoreRequest{")
+	if this.Record != nil {
+		s = append(s, "Record: "+fmt.Sprintf("%#v", this.Record)+",\n")
+	}
+	s = append(s, "}")
+	return strings.Join(s, "")
+}
+func (this *StoreResponse) GoString() string {
+	if this == nil {
+		return "nil"
+	}
+	s := make([]string, 0, 5)
+	s = append(s, "&protobuf.StoreResponse{")
+	s = append(s, "Stored: "+fmt.Sprintf("%#v", this.Stored)+",\n")
+	s = append(s, "}")
+	return strings.Join(s, "")
+}
+func (this *FindValueRequest) GoString() string {
+	if this == nil {
+		return "nil"
+	}
+	s := make([]string, 0, 5)
+	s = append(s, "&protobuf.FindValueRequest{")
+	s = append(s, "Key: "+fmt.Sprintf("%#v", this.Key)+",\n")
+	s = append(s, "}")
+	return strings.Join(s, "")
+}
+func (this *FindValueResponse) GoString() string {
+	if this == nil {
+		return "nil"
+	}
+	s := make([]string, 0, 6)
+	s = append(s, "&protobuf.FindValueResponse{")
+	if this.Record != nil {
+		s = append(s, "Record: "+fmt.Sprintf("%#v", this.Record)+",\n")
+	}
+	if this.Peers != nil {
+		s = append(s, "Peers: "+fmt.Sprintf("%#v", this.Peers)+",\n")
+	}
+	s = append(s, "}")
+	return strings.Join(s, "")
+}
+func (this *Subscribe) GoString() string {
+	if this == nil {
+		return "nil"
+	}
+	s := make([]string, 0, 5)
+	s = append(s, "&protobuf.Subscribe{")
+	s = append(s, "Topics: "+fmt.Sprintf("%#v", this.Topics)+",\n")
+	s = append(s, "}")
+	return strings.Join(s, "")
+}
+func (this *Publish) GoString() string {
+	if this == nil {
+		return "nil"
+	}
+	s := make([]string, 0, 7)
+	s = append(s, "&protobuf.Publish{")
+	s = append(s, "Id: "+fmt.Sprintf("%#v", this.Id)+",\n")
+	s = append(s, "Topic: "+fmt.Sprintf("%#v", this.Topic)+",\n")
+	s = append(s, "Data: "+fmt.Sprintf("%#v", this.Data)+",\n")
+	s = append(s, "}")
+	return strings.Join(s, "")
+}
+func (this *BloomFilter) GoString() string {
+	if this == nil {
+		return "nil"
+	}
+	s := make([]string, 0, 7)
+	s = append(s, "&protobuf.BloomFilter{")
+	s = append(s, "Bits: "+fmt.Sprintf("%#v", this.Bits)+",\n")
+	s = append(s, "Hashes: "+fmt.Sprintf("%#v", this.Hashes)+",\n")
+	s = append(s, "Seed: "+fmt.Sprintf("%#v", this.Seed)+",\n")
+	s = append(s, "}")
+	return strings.Join(s, "")
+}
+func (this *Transactions) GoString() string {
+	if this == nil {
+		return "nil"
+	}
+	s := make([]string, 0, 5)
+	s = append(s, "&protobuf.Transactions{")
+	s = append(s, "Txs: "+fmt.Sprintf("%#v", this.Txs)+",\n")
+	s = append(s, "}")
+	return strings.Join(s, "")
+}
+func (this *TxSyncRequest) GoString() string {
+	if this == nil {
+		return "nil"
+	}
+	s := make([]string, 0, 5)
+	s = append(s, "&protobuf.TxSyncRequest{")
+	if this.Filter != nil {
+		s = append(s, "Filter: "+fmt.Sprintf("%#v", this.Filter)+",\n")
+	}
+	s = append(s, "}")
+	return strings.Join(s, "")
+}
+func (this *TxSyncResponse) GoString() string {
+	if this == nil {
+		return "nil"
+	}
+	s := make([]string, 0, 6)
+	s = append(s, "&protobuf.TxSyncResponse{")
+	s = append(s, "Txs: "+fmt.Sprintf("%#v", this.Txs)+",\n")
+	if this.Filter != nil {
+		s = append(s, "Filter: "+fmt.Sprintf("%#v", this.Filter)+",\n")
+	}
+	s = append(s, "}")
+	return strings.Join(s, "")
+}
+func (this *ReliableMessage) GoString() string {
+	if this == nil {
+		return "nil"
+	}
+	s := make([]string, 0, 6)
+	s = append(s, "&protobuf.ReliableMessage{")
+	s = append(s, "Sequence: "+fmt.Sprintf("%#v", this.Sequence)+",\n")
+	s = append(s, "Data: "+fmt.Sprintf("%#v", this.Data)+",\n")
+	s = append(s, "}")
+	return strings.Join(s, "")
+}
+func (this *ReliableAck) GoString() string {
+	if this == nil {
+		return "nil"
+	}
+	s := make([]string, 0, 5)
+	s = append(s, "&protobuf.ReliableAck{")
+	s = append(s, "Sequence: "+fmt.Sprintf("%#v", this.Sequence)+",\n")
+	s = append(s, "}")
+	return strings.Join(s, "")
+}
+func (this *BlockHeader) GoString() string {
+	if this == nil {
+		return "nil"
+	}
+	s := make([]string, 0, 6)
+	s = append(s, "&protobuf.BlockHeader{")
+	s = append(s, "Hash: "+fmt.Sprintf("%#v", this.Hash)+",\n")
+	s = append(s, "Height: "+fmt.Sprintf("%#v", this.Height)+",\n")
+	s = append(s, "}")
+	return strings.Join(s, "")
+}
+func (this *GetBlock) GoString() string {
+	if this == nil {
+		return "nil"
+	}
+	s := make([]string, 0, 5)
+	s = append(s, "&protobuf.GetBlock{")
+	s = append(s, "Hash: "+fmt.Sprintf("%#v", this.Hash)+",\n")
+	s = append(s, "}")
+	return strings.Join(s, "")
+}
+func (this *Block) GoString() string {
+	if this == nil {
+		return "nil"
+	}
+	s := make([]string, 0, 6)
+	s = append(s, "&protobuf.Block{")
+	s = append(s, "Height: "+fmt.Sprintf("%#v", this.Height)+",\n")
+	s = append(s, "Data: "+fmt.Sprintf("%#v", this.Data)+",\n")
+	s = append(s, "}")
+	return strings.Join(s, "")
+}
+func (this *KeyRevocation) GoString() string {
+	if this == nil {
+		return "nil"
+	}
+	s := make([]string, 0, 9)
+	s = append(s, "&protobuf.KeyRevocation{")
+	s = append(s, "OldPublicKey: "+fmt.Sprintf("%#v", this.OldPublicKey)+",\n")
+	s = append(s, "NewPublicKey: "+fmt.Sprintf("%#v", this.NewPublicKey)+",\n")
+	s = append(s, "Signature: "+fmt.Sprintf("%#v", this.Signature)+",\n")
+	s = append(s, "Timestamp: "+fmt.Sprintf("%#v", this.Timestamp)+",\n")
+	s = append(s, "NewSignature: "+fmt.Sprintf("%#v", this.NewSignature)+",\n")
+	s = append(s, "}")
+	return strings.Join(s, "")
+}
+func valueToGoStringStream(v interface{}, typ string) string {
+	rv := reflect.ValueOf(v)
+	if rv.IsNil() {
+		return "nil"
+	}
+	pv := reflect.Indirect(rv).Interface()
+	return fmt.Sprintf("func(v %v) *%v { return &v } ( %#v )", typ, typ, pv)
+}
+func (m *ID) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalTo(dAtA)
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *ID) MarshalTo(dAtA []byte) (int, error) {
+	var i int
+	_ = i
+	var l int
+	_ = l
+	if len(m.PublicKey) > 0 {
+		dAtA[i] = 0xa
+		i++
+		i = encodeVarintStream(dAtA, i, uint64(len(m.PublicKey)))
+		i += copy(dAtA[i:], m.PublicKey)
+	}
+	if len(m.Address) > 0 {
+		dAtA[i] = 0x12
+		i++
+		i = encodeVarintStream(dAtA, i, uint64(len(m.Address)))
+		i += copy(dAtA[i:], m.Address)
+	}
+	if len(m.Id) > 0 {
+		dAtA[i] = 0x1a
+		i++
+		i = encodeVarintStream(dAtA, i, uint64(len(m.Id)))
+		i += copy(dAtA[i:], m.Id)
+	}
+	if len(m.Multiaddr) > 0 {
+		dAtA[i] = 0x22
+		i++
+		i = encodeVarintStream(dAtA, i, uint64(len(m.Multiaddr)))
+		i += copy(dAtA[i:], m.Multiaddr)
+	}
+	return i, nil
+}
+
+func (m *Message) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalTo(dAtA)
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *Message) MarshalTo(dAtA []byte) (int, error) {
+	var i int
+	_ = i
+	var l int
+	_ = l
+	if len(m.Message) > 0 {
+		dAtA[i] = 0xa
+		i++
+		i = encodeVarintStream(dAtA, i, uint64(len(m.Message)))
+		i += copy(dAtA[i:], m.Message)
+	}
+	if m.Sender != nil {
+		dAtA[i] = 0x12
+		i++
+		i = encodeVarintStream(dAtA, i, uint64(m.Sender.Size()))
+		n1, err := m.Sender.MarshalTo(dAtA[i:])
+		if err != nil {
+			return 0, err
+		}
+		i += n1
+	}
+	if len(m.Signature) > 0 {
+		dAtA[i] = 0x1a
+		i++
+		i = encodeVarintStream(dAtA, i, uint64(len(m.Signature)))
+		i += copy(dAtA[i:], m.Signature)
+	}
+	if m.RequestNonce != 0 {
+		dAtA[i] = 0x20
+		i++
+		i = encodeVarintStream(dAtA, i, uint64(m.RequestNonce))
+	}
+	if m.MessageNonce != 0 {
+		dAtA[i] = 0x28
+		i++
+		i = encodeVarintStream(dAtA, i, uint64(m.MessageNonce))
+	}
+	if m.ReplyFlag {
+		dAtA[i] = 0x30
+		i++
+		if m.ReplyFlag {
+			dAtA[i] = 1
+		} else {
+			dAtA[i] = 0
+		}
+		i++
+	}
+	if m.Opcode != 0 {
+		dAtA[i] = 0x38
+		i++
+		i = encodeVarintStream(dAtA, i, uint64(m.Opcode))
+	}
+	if m.RelayedFrom != nil {
+		dAtA[i] = 0x42
+		i++
+		i = encodeVarintStream(dAtA, i, uint64(m.RelayedFrom.Size()))
+		n2, err := m.RelayedFrom.MarshalTo(dAtA[i:])
+		if err != nil {
+			return 0, err
+		}
+		i += n2
+	}
+	if m.Sequence != 0 {
+		dAtA[i] = 0x48
+		i++
+		i = encodeVarintStream(dAtA, i, uint64(m.Sequence))
+	}
+	if len(m.TraceContext) > 0 {
+		for k, _ := range m.TraceContext {
+			dAtA[i] = 0x52
+			i++
+			v := m.TraceContext[k]
+			mapSize := 1 + len(k) + sovStream(uint64(len(k))) + 1 + len(v) + sovStream(uint64(len(v)))
+			i = encodeVarintStream(dAtA, i, uint64(mapSize))
+			dAtA[i] = 0xa
+			i++
+			i = encodeVarintStream(dAtA, i, uint64(len(k)))
+			i += copy(dAtA[i:], k)
+			dAtA[i] = 0x12
+			i++
+			i = encodeVarintStream(dAtA, i, uint64(len(v)))
+			i += copy(dAtA[i:], v)
+		}
+	}
+	if len(m.GroupId) > 0 {
+		dAtA[i] = 0x5a
+		i++
+		i = encodeVarintStream(dAtA, i, uint64(len(m.GroupId)))
+		i += copy(dAtA[i:], m.GroupId)
+	}
+	if m.Compressed {
+		dAtA[i] = 0x60
+		i++
+		if m.Compressed {
+			dAtA[i] = 1
+		} else {
+			dAtA[i] = 0
+		}
+		i++
+	}
+	if m.Timeout != 0 {
+		dAtA[i] = 0x68
+		i++
+		i = encodeVarintStream(dAtA, i, uint64(m.Timeout))
+	}
+	if len(m.RelaySignature) > 0 {
+		dAtA[i] = 0x72
+		i++
+		i = encodeVarintStream(dAtA, i, uint64(len(m.RelaySignature)))
+		i += copy(dAtA[i:], m.RelaySignature)
+	}
+	return i, nil
+}
+
+func (m *Ping) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalTo(dAtA)
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *Ping) MarshalTo(dAtA []byte) (int, error) {
+	var i int
+	_ = i
+	var l int
+	_ = l
+	if m.Capabilities != 0 {
+		dAtA[i] = 0x8
+		i++
+		i = encodeVarintStream(dAtA, i, uint64(m.Capabilities))
+	}
+	return i, nil
+}
+
+func (m *Pong) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalTo(dAtA)
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *Pong) MarshalTo(dAtA []byte) (int, error) {
+	var i int
+	_ = i
+	var l int
+	_ = l
+	if m.Capabilities != 0 {
+		dAtA[i] = 0x8
+		i++
+		i = encodeVarintStream(dAtA, i, uint64(m.Capabilities))
+	}
+	return i, nil
+}
+
+func (m *LookupNodeRequest) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalTo(dAtA)
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *LookupNodeRequest) MarshalTo(dAtA []byte) (int, error) {
+	var i int
+	_ = i
+	var l int
+	_ = l
+	if m.Target != nil {
+		dAtA[i] = 0xa
+		i++
+		i = encodeVarintStream(dAtA, i, uint64(m.Target.Size()))
+		n3, err := m.Target.MarshalTo(dAtA[i:])
+		if err != nil {
+			return 0, err
+		}
+		i += n3
+	}
+	return i, nil
+}
+
+func (m *LookupNodeResponse) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalTo(dAtA)
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *LookupNodeResponse) MarshalTo(dAtA []byte) (int, error) {
+	var i int
+	_ = i
+	var l int
+	_ = l
+	if len(m.Peers) > 0 {
+		for _, msg := range m.Peers {
+			dAtA[i] = 0xa
+			i++
+			i = encodeVarintStream(dAtA, i, uint64(msg.Size()))
+			n, err := msg.MarshalTo(dAtA[i:])
+			if err != nil {
+				return 0, err
+			}
+			i += n
+		}
+	}
+	return i, nil
+}
+
+func (m *Bytes) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalTo(dAtA)
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *Bytes) MarshalTo(dAtA []byte) (int, error) {
+	var i int
+	_ = i
+	var l int
+	_ = l
+	if len(m.Data) > 0 {
+		dAtA[i] = 0xa
+		i++
+		i = encodeVarintStream(dAtA, i, uint64(len(m.Data)))
+		i += copy(dAtA[i:], m.Data)
+	}
+	return i, nil
+}
+
+func (m *AddressMigration) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalTo(dAtA)
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *AddressMigration) MarshalTo(dAtA []byte) (int, error) {
+	var i int
+	_ = i
+	var l int
+	_ = l
+	if len(m.NewAddress) > 0 {
+		dAtA[i] = 0xa
+		i++
+		i = encodeVarintStream(dAtA, i, uint64(len(m.NewAddress)))
+		i += copy(dAtA[i:], m.NewAddress)
+	}
+	if len(m.Signature) > 0 {
+		dAtA[i] = 0x12
+		i++
+		i = encodeVarintStream(dAtA, i, uint64(len(m.Signature)))
+		i += copy(dAtA[i:], m.Signature)
+	}
+	if m.Timestamp != 0 {
+		dAtA[i] = 0x18
+		i++
+		i = encodeVarintStream(dAtA, i, uint64(m.Timestamp))
+	}
+	return i, nil
+}
+
+func (m *MembershipUpdate) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalTo(dAtA)
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *MembershipUpdate) MarshalTo(dAtA []byte) (int, error) {
+	var i int
+	_ = i
+	var l int
+	_ = l
+	if m.Member != nil {
+		dAtA[i] = 0xa
+		i++
+		i = encodeVarintStream(dAtA, i, uint64(m.Member.Size()))
+		n4, err := m.Member.MarshalTo(dAtA[i:])
+		if err != nil {
+			return 0, err
+		}
+		i += n4
+	}
+	if m.State != 0 {
+		dAtA[i] = 0x10
+		i++
+		i = encodeVarintStream(dAtA, i, uint64(m.State))
+	}
+	if m.Incarnation != 0 {
+		dAtA[i] = 0x18
+		i++
+		i = encodeVarintStream(dAtA, i, uint64(m.Incarnation))
+	}
+	if m.Origin != nil {
+		dAtA[i] = 0x22
+		i++
+		i = encodeVarintStream(dAtA, i, uint64(m.Origin.Size()))
+		n5, err := m.Origin.MarshalTo(dAtA[i:])
+		if err != nil {
+			return 0, err
+		}
+		i += n5
+	}
+	if len(m.Signature) > 0 {
+		dAtA[i] = 0x2a
+		i++
+		i = encodeVarintStream(dAtA, i, uint64(len(m.Signature)))
+		i += copy(dAtA[i:], m.Signature)
+	}
+	return i, nil
+}
+
+func (m *SwimPing) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalTo(dAtA)
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *SwimPing) MarshalTo(dAtA []byte) (int, error) {
+	var i int
+	_ = i
+	var l int
+	_ = l
+	if len(m.Updates) > 0 {
+		for _, msg := range m.Updates {
+			dAtA[i] = 0xa
+			i++
+			i = encodeVarintStream(dAtA, i, uint64(msg.Size()))
+			n, err := msg.MarshalTo(dAtA[i:])
+			if err != nil {
+				return 0, err
+			}
+			i += n
+		}
+	}
+	return i, nil
+}
+
+func (m *SwimPingRequest) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalTo(dAtA)
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *SwimPingRequest) MarshalTo(dAtA []byte) (int, error) {
+	var i int
+	_ = i
+	var l int
+	_ = l
+	if m.Target != nil {
+		dAtA[i] = 0xa
+		i++
+		i = encodeVarintStream(dAtA, i, uint64(m.Target.Size()))
+		n6, err := m.Target.MarshalTo(dAtA[i:])
+		if err != nil {
+			return 0, err
+		}
+		i += n6
+	}
+	if len(m.Updates) > 0 {
+		for _, msg := range m.Updates {
+			dAtA[i] = 0x12
+			i++
+			i = encodeVarintStream(dAtA, i, uint64(msg.Size()))
+			n, err := msg.MarshalTo(dAtA[i:])
+			if err != nil {
+				return 0, err
+			}
+			i += n
+		}
+	}
+	return i, nil
+}
+
+func (m *SwimAck) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalTo(dAtA)
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *SwimAck) MarshalTo(dAtA []byte) (int, error) {
+	var i int
+	_ = i
+	var l int
+	_ = l
+	if len(m.Updates) > 0 {
+		for _, msg := range m.Updates {
+			dAtA[i] = 0xa
+			i++
+			i = encodeVarintStream(dAtA, i, uint64(msg.Size()))
+			n, err := msg.MarshalTo(dAtA[i:])
+			if err != nil {
+				return 0, err
+			}
+			i += n
+		}
+	}
+	return i, nil
+}
+
+func (m *BeaconPartial) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalTo(dAtA)
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *BeaconPartial) MarshalTo(dAtA []byte) (int, error) {
+	var i int
+	_ = i
+	var l int
+	_ = l
+	if m.Round != 0 {
+		dAtA[i] = 0x8
+		i++
+		i = encodeVarintStream(dAtA, i, uint64(m.Round))
+	}
+	if m.Index != 0 {
+		dAtA[i] = 0x10
+		i++
+		i = encodeVarintStream(dAtA, i, uint64(m.Index))
+	}
+	if len(m.Signature) > 0 {
+		dAtA[i] = 0x1a
+		i++
+		i = encodeVarintStream(dAtA, i, uint64(len(m.Signature)))
+		i += copy(dAtA[i:], m.Signature)
+	}
+	return i, nil
+}
+
+func (m *ValueRecord) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalTo(dAtA)
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *ValueRecord) MarshalTo(dAtA []byte) (int, error) {
+	var i int
+	_ = i
+	var l int
+	_ = l
+	if len(m.Key) > 0 {
+		dAtA[i] = 0xa
+		i++
+		i = encodeVarintStream(dAtA, i, uint64(len(m.Key)))
+		i += copy(dAtA[i:], m.Key)
+	}
+	if len(m.Value) > 0 {
+		dAtA[i] = 0x12
+		i++
+		i = encodeVarintStream(dAtA, i, uint64(len(m.Value)))
+		i += copy(dAtA[i:], m.Value)
+	}
+	if m.Publisher != nil {
+		dAtA[i] = 0x1a
+		i++
+		i = encodeVarintStream(dAtA, i, uint64(m.Publisher.Size()))
+		n7, err := m.Publisher.MarshalTo(dAtA[i:])
+		if err != nil {
+			return 0, err
+		}
+		i += n7
+	}
+	if m.Expiry != 0 {
+		dAtA[i] = 0x20
+		i++
+		i = encodeVarintStream(dAtA, i, uint64(m.Expiry))
+	}
+	if len(m.Signature) > 0 {
+		dAtA[i] = 0x2a
+		i++
+		i = encodeVarintStream(dAtA, i, uint64(len(m.Signature)))
+		i += copy(dAtA[i:], m.Signature)
+	}
+	return i, nil
+}
+
+func (m *StoreRequest) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalTo(dAtA)
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *StoreRequest) MarshalTo(dAtA []byte) (int, error) {
+	var i int
+	_ = i
+	var l int
+	_ = l
+	if m.Record != nil {
+		dAtA[i] = 0xa
+		i++
+		i = encodeVarintStream(dAtA, i, uint64(m.Record.Size()))
+		n8, err := m.Record.MarshalTo(dAtA[i:])
+		if err != nil {
+			return 0, err
+		}
+		i += n8
+	}
+	return i, nil
+}
+
+func (m *StoreResponse) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalTo(dAtA)
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *StoreResponse) MarshalTo(dAtA []byte) (int, error) {
+	var i int
+	_ = i
+	var l int
+	_ = l
+	if m.Stored {
+		dAtA[i] = 0x8
+		i++
+		if m.Stored {
+			dAtA[i] = 1
+		} else {
+			dAtA[i] = 0
+		}
+		i++
+	}
+	return i, nil
+}
+
+func (m *FindValueRequest) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalTo(dAtA)
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *FindValueRequest) MarshalTo(dAtA []byte) (int, error) {
+	var i int
+	_ = i
+	var l int
+	_ = l
+	if len(m.Key) > 0 {
+		dAtA[i] = 0xa
+		i++
+		i = encodeVarintStream(dAtA, i, uint64(len(m.Key)))
+		i += copy(dAtA[i:], m.Key)
+	}
+	return i, nil
+}
+
+func (m *FindValueResponse) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalTo(dAtA)
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *FindValueResponse) MarshalTo(dAtA []byte) (int, error) {
+	var i int
+	_ = i
+	var l int
+	_ = l
+	if m.Record != nil {
+		dAtA[i] = 0xa
+		i++
+		i = encodeVarintStream(dAtA, i, uint64(m.Record.Size()))
+		n9, err := m.Record.MarshalTo(dAtA[i:])
+		if err != nil {
+			return 0, err
+		}
+		i += n9
+	}
+	if len(m.Peers) > 0 {
+		for _, msg := range m.Peers {
+			dAtA[i] = 0x12
+			i++
+			i = encodeVarintStream(dAtA, i, uint64(msg.Size()))
+			n, err := msg.MarshalTo(dAtA[i:])
+			if err != nil {
+				return 0, err
+			}
+			i += n
+		}
+	}
+	return i, nil
+}
+
+func (m *Subscribe) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalTo(dAtA)
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *Subscribe) MarshalTo(dAtA []byte) (int, error) {
+	var i int
+	_ = i
+	var l int
+	_ = l
+	if len(m.Topics) > 0 {
+		for _, s := range m.Topics {
+			dAtA[i] = 0xa
+			i++
+			l = len(s)
+			for l >= 1<<7 {
+				dAtA[i] = uint8(uint64(l)&0x7f | 0x80)
+				l >>= 7
+				i++
+			}
+			dAtA[i] = uint8(l)
+			i++
+			i += copy(dAtA[i:], s)
+		}
+	}
+	return i, nil
+}
+
+func (m *Publish) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalTo(dAtA)
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *Publish) MarshalTo(dAtA []byte) (int, error) {
+	var i int
+	_ = i
+	var l int
+	_ = l
+	if len(m.Id) > 0 {
+		dAtA[i] = 0xa
+		i++
+		i = encodeVarintStream(dAtA, i, uint64(len(m.Id)))
+		i += copy(dAtA[i:], m.Id)
+	}
+	if len(m.Topic) > 0 {
+		dAtA[i] = 0x12
+		i++
+		i = encodeVarintStream(dAtA, i, uint64(len(m.Topic)))
+		i += copy(dAtA[i:], m.Topic)
+	}
+	if len(m.Data) > 0 {
+		dAtA[i] = 0x1a
+		i++
+		i = encodeVarintStream(dAtA, i, uint64(len(m.Data)))
+		i += copy(dAtA[i:], m.Data)
+	}
+	return i, nil
+}
+
+func (m *BloomFilter) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalTo(dAtA)
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *BloomFilter) MarshalTo(dAtA []byte) (int, error) {
+	var i int
+	_ = i
+	var l int
+	_ = l
+	if len(m.Bits) > 0 {
+		dAtA[i] = 0xa
+		i++
+		i = encodeVarintStream(dAtA, i, uint64(len(m.Bits)))
+		i += copy(dAtA[i:], m.Bits)
+	}
+	if m.Hashes != 0 {
+		dAtA[i] = 0x10
+		i++
+		i = encodeVarintStream(dAtA, i, uint64(m.Hashes))
+	}
+	if m.Seed != 0 {
+		dAtA[i] = 0x18
+		i++
+		i = encodeVarintStream(dAtA, i, uint64(m.Seed))
+	}
+	return i, nil
+}
+
+func (m *Transactions) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalTo(dAtA)
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *Transactions) MarshalTo(dAtA []byte) (int, error) {
+	var i int
+	_ = i
+	var l int
+	_ = l
+	if len(m.Txs) > 0 {
+		for _, b := range m.Txs {
+			dAtA[i] = 0xa
+			i++
+			i = encodeVarintStream(dAtA, i, uint64(len(b)))
+			i += copy(dAtA[i:], b)
+		}
+	}
+	return i, nil
+}
+
+func (m *TxSyncRequest) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalTo(dAtA)
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *TxSyncRequest) MarshalTo(dAtA []byte) (int, error) {
+	var i int
+	_ = i
+	var l int
+	_ = l
+	if m.Filter != nil {
+		dAtA[i] = 0xa
+		i++
+		i = encodeVarintStream(dAtA, i, uint64(m.Filter.Size()))
+		n10, err := m.Filter.MarshalTo(dAtA[i:])
+		if err != nil {
+			return 0, err
+		}
+		i += n10
+	}
+	return i, nil
+}
+
+func (m *TxSyncResponse) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalTo(dAtA)
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *TxSyncResponse) MarshalTo(dAtA []byte) (int, error) {
+	var i int
+	_ = i
+	var l int
+	_ = l
+	if len(m.Txs) > 0 {
+		for _, b := range m.Txs {
+			dAtA[i] = 0xa
+			i++
+			i = encodeVarintStream(dAtA, i, uint64(len(b)))
+			i += copy(dAtA[i:], b)
+		}
+	}
+	if m.Filter != nil {
+		dAtA[i] = 0x12
+		i++
+		i = encodeVarintStream(dAtA, i, uint64(m.Filter.Size()))
+		n11, err := m.Filter.MarshalTo(dAtA[i:])
+		if err != nil {
+			return 0, err
+		}
+		i += n11
+	}
+	return i, nil
+}
+
+func (m *ReliableMessage) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalTo(dAtA)
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *ReliableMessage) MarshalTo(dAtA []byte) (int, error) {
+	var i int
+	_ = i
+	var l int
+	_ = l
+	if m.Sequence != 0 {
+		dAtA[i] = 0x8
+		i++
+		i = encodeVarintStream(dAtA, i, uint64(m.Sequence))
+	}
+	if len(m.Data) > 0 {
+		dAtA[i] = 0x12
+		i++
+		i = encodeVarintStream(dAtA, i, uint64(len(m.Data)))
+		i += copy(dAtA[i:], m.Data)
+	}
+	return i, nil
+}
+
+func (m *ReliableAck) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalTo(dAtA)
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *ReliableAck) MarshalTo(dAtA []byte) (int, error) {
+	var i int
+	_ = i
+	var l int
+	_ = l
+	if m.Sequence != 0 {
+		dAtA[i] = 0x8
+		i++
+		i = encodeVarintStream(dAtA, i, uint64(m.Sequence))
+	}
+	return i, nil
+}
+
+func (m *BlockHeader) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalTo(dAtA)
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *BlockHeader) MarshalTo(dAtA []byte) (int, error) {
+	var i int
+	_ = i
+	var l int
+	_ = l
+	if len(m.Hash) > 0 {
+		dAtA[i] = 0xa
+		i++
+		i = encodeVarintStream(dAtA, i, uint64(len(m.Hash)))
+		i += copy(dAtA[i:], m.Hash)
+	}
+	if m.Height != 0 {
+		dAtA[i] = 0x10
+		i++
+		i = encodeVarintStream(dAtA, i, uint64(m.Height))
+	}
+	return i, nil
+}
+
+func (m *GetBlock) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalTo(dAtA)
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *GetBlock) MarshalTo(dAtA []byte) (int, error) {
+	var i int
+	_ = i
+	var l int
+	_ = l
+	if len(m.Hash) > 0 {
+		dAtA[i] = 0xa
+		i++
+		i = encodeVarintStream(dAtA, i, uint64(len(m.Hash)))
+		i += copy(dAtA[i:], m.Hash)
+	}
+	return i, nil
+}
+
+func (m *Block) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalTo(dAtA)
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *Block) MarshalTo(dAtA []byte) (int, error) {
+	var i int
+	_ = i
+	var l int
+	_ = l
+	if m.Height != 0 {
+		dAtA[i] = 0x8
+		i++
+		i = encodeVarintStream(dAtA, i, uint64(m.Height))
+	}
+	if len(m.Data) > 0 {
+		dAtA[i] = 0x12
+		i++
+		i = encodeVarintStream(dAtA, i, uint64(len(m.Data)))
+		i += copy(dAtA[i:], m.Data)
+	}
+	return i, nil
+}
+
+func (m *KeyRevocation) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalTo(dAtA)
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *KeyRevocation) MarshalTo(dAtA []byte) (int, error) {
+	var i int
+	_ = i
+	var l int
+	_ = l
+	if len(m.OldPublicKey) > 0 {
+		dAtA[i] = 0xa
+		i++
+		i = encodeVarintStream(dAtA, i, uint64(len(m.OldPublicKey)))
+		i += copy(dAtA[i:], m.OldPublicKey)
+	}
+	if len(m.NewPublicKey) > 0 {
+		dAtA[i] = 0x12
+		i++
+		i = encodeVarintStream(dAtA, i, uint64(len(m.NewPublicKey)))
+		i += copy(dAtA[i:], m.NewPublicKey)
+	}
+	if len(m.Signature) > 0 {
+		dAtA[i] = 0x1a
+		i++
+		i = encodeVarintStream(dAtA, i, uint64(len(m.Signature)))
+		i += copy(dAtA[i:], m.Signature)
+	}
+	if m.Timestamp != 0 {
+		dAtA[i] = 0x20
+		i++
+		i = encodeVarintStream(dAtA, i, uint64(m.Timestamp))
+	}
+	if len(m.NewSignature) > 0 {
+		dAtA[i] = 0x2a
+		i++
+		i = encodeVarintStream(dAtA, i, uint64(len(m.NewSignature)))
+		i += copy(dAtA[i:], m.NewSignature)
+	}
+	return i, nil
+}
+
+func encodeVarintStream(dAtA []byte, offset int, v uint64) int {
+	for v >= 1<<7 {
+		dAtA[offset] = uint8(v&0x7f | 0x80)
+		v >>= 7
+		offset++
+	}
+	dAtA[offset] = uint8(v)
+	return offset + 1
+}
+func (m *ID) Size() (n int) {
+	var l int
+	_ = l
+	l = len(m.PublicKey)
+	if l > 0 {
+		n += 1 + l + sovStream(uint64(l))
+	}
+	l = len(m.Address)
+	if l > 0 {
+		n += 1 + l + sovStream(uint64(l))
+	}
+	l = len(m.Id)
+	if l > 0 {
+		n += 1 + l + sovStream(uint64(l))
+	}
+	l = len(m.Multiaddr)
+	if l > 0 {
+		n += 1 + l + sovStream(uint64(l))
+	}
+	return n
+}
+
+func (m *Message) Size() (n int) {
+	var l int
+	_ = l
+	l = len(m.Message)
+	if l > 0 {
+		n += 1 + l + sovStream(uint64(l))
+	}
+	if m.Sender != nil {
+		l = m.Sender.Size()
+		n += 1 + l + sovStream(uint64(l))
+	}
+	l = len(m.Signature)
+	if l > 0 {
+		n += 1 + l + sovStream(uint64(l))
+	}
+	if m.RequestNonce != 0 {
+		n += 1 + sovStream(uint64(m.RequestNonce))
+	}
+	if m.MessageNonce != 0 {
+		n += 1 + sovStream(uint64(m.MessageNonce))
+	}
+	if m.ReplyFlag {
+		n += 2
+	}
+	if m.Opcode != 0 {
+		n += 1 + sovStream(uint64(m.Opcode))
+	}
+	if m.RelayedFrom != nil {
+		l = m.RelayedFrom.Size()
+		n += 1 + l + sovStream(uint64(l))
+	}
+	if m.Sequence != 0 {
+		n += 1 + sovStream(uint64(m.Sequence))
+	}
+	if len(m.TraceContext) > 0 {
+		for k, v := range m.TraceContext {
+			_ = k
+			_ = v
+			mapEntrySize := 1 + len(k) + sovStream(uint64(len(k))) + 1 + len(v) + sovStream(uint64(len(v)))
+			n += mapEntrySize + 1 + sovStream(uint64(mapEntrySize))
+		}
+	}
+	l = len(m.GroupId)
+	if l > 0 {
+		n += 1 + l + sovStream(uint64(l))
+	}
+	if m.Compressed {
+		n += 2
+	}
+	if m.Timeout != 0 {
+		n += 1 + sovStream(uint64(m.Timeout))
+	}
+	l = len(m.RelaySignature)
+	if l > 0 {
+		n += 1 + l + sovStream(uint64(l))
+	}
+	return n
+}
+
+func (m *Ping) Size() (n int) {
+	var l int
+	_ = l
+	if m.Capabilities != 0 {
+		n += 1 + sovStream(uint64(m.Capabilities))
+	}
+	return n
+}
+
+func (m *Pong) Size() (n int) {
+	var l int
+	_ = l
+	if m.Capabilities != 0 {
+		n += 1 + sovStream(uint64(m.Capabilities))
+	}
+	return n
+}
+
+func (m *LookupNodeRequest) Size() (n int) {
+	var l int
+	_ = l
+	if m.Target != nil {
+		l = m.Target.Size()
+		n += 1 + l + sovStream(uint64(l))
+	}
+	return n
+}
+
+func (m *LookupNodeResponse) Size() (n int) {
+	var l int
+	_ = l
+	if len(m.Peers) > 0 {
+		for _, e := range m.Peers {
+			l = e.Size()
+			n += 1 + l + sovStream(uint64(l))
+		}
+	}
+	return n
+}
+
+func (m *Bytes) Size() (n int) {
+	var l int
+	_ = l
+	l = len(m.Data)
+	if l > 0 {
+		n += 1 + l + sovStream(uint64(l))
+	}
+	return n
+}
+
+func (m *AddressMigration) Size() (n int) {
+	var l int
+	_ = l
+	l = len(m.NewAddress)
+	if l > 0 {
+		n += 1 + l + sovStream(uint64(l))
+	}
+	l = len(m.Signature)
+	if l > 0 {
+		n += 1 + l + sovStream(uint64(l))
+	}
+	if m.Timestamp != 0 {
+		n += 1 + sovStream(uint64(m.Timestamp))
+	}
+	return n
+}
+
+func (m *MembershipUpdate) Size() (n int) {
+	var l int
+	_ = l
+	if m.Member != nil {
+		l = m.Member.Size()
+		n += 1 + l + sovStream(uint64(l))
+	}
+	if m.State != 0 {
+		n += 1 + sovStream(uint64(m.State))
+	}
+	if m.Incarnation != 0 {
+		n += 1 + sovStream(uint64(m.Incarnation))
+	}
+	if m.Origin != nil {
+		l = m.Origin.Size()
+		n += 1 + l + sovStream(uint64(l))
+	}
+	l = len(m.Signature)
+	if l > 0 {
+		n += 1 + l + sovStream(uint64(l))
+	}
+	return n
+}
+
+func (m *SwimPing) Size() (n int) {
+	var l int
+	_ = l
+	if len(m.Updates) > 0 {
+		for _, e := range m.Updates {
+			l = e.Size()
+			n += 1 + l + sovStream(uint64(l))
+		}
+	}
+	return n
+}
+
+func (m *SwimPingRequest) Size() (n int) {
+	var l int
+	_ = l
+	if m.Target != nil {
+		l = m.Target.Size()
+		n += 1 + l + sovStream(uint64(l))
+	}
+	if len(m.Updates) > 0 {
+		for _, e := range m.Updates {
+			l = e.Size()
+			n += 1 + l + sovStream(uint64(l))
+		}
+	}
+	return n
+}
+
+func (m *SwimAck) Size() (n int) {
+	var l int
+	_ = l
+	if len(m.Updates) > 0 {
+		for _, e := range m.Updates {
+			l = e.Size()
+			n += 1 + l + sovStream(uint64(l))
+		}
+	}
+	return n
+}
+
+func (m *BeaconPartial) Size() (n int) {
+	var l int
+	_ = l
+	if m.Round != 0 {
+		n += 1 + sovStream(uint64(m.Round))
+	}
+	if m.Index != 0 {
+		n += 1 + sovStream(uint64(m.Index))
+	}
+	l = len(m.Signature)
+	if l > 0 {
+		n += 1 + l + sovStream(uint64(l))
+	}
+	return n
+}
+
+func (m *ValueRecord) Size() (n int) {
+	var l int
+	_ = l
+	l = len(m.Key)
+	if l > 0 {
+		n += 1 + l + sovStream(uint64(l))
+	}
+	l = len(m.Value)
+	if l > 0 {
+		n += 1 + l + sovStream(uint64(l))
+	}
+	if m.Publisher != nil {
+		l = m.Publisher.Size()
+		n += 1 + l + sovStream(uint64(l))
+	}
+	if m.Expiry != 0 {
+		n += 1 + sovStream(uint64(m.Expiry))
+	}
+	l = len(m.Signature)
+	if l > 0 {
+		n += 1 + l + sovStream(uint64(l))
+	}
+	return n
+}
+
+func (m *StoreRequest) Size() (n int) {
+	var l int
+	_ = l
+	if m.Record != nil {
+		l = m.Record.Size()
+		n += 1 + l + sovStream(uint64(l))
+	}
+	return n
+}
+
+func (m *StoreResponse) Size() (n int) {
+	var l int
+	_ = l
+	if m.Stored {
+		n += 2
+	}
+	return n
+}
+
+func (m *FindValueRequest) Size() (n int) {
+	var l int
+	_ = l
+	l = len(m.Key)
+	if l > 0 {
+		n += 1 + l + sovStream(uint64(l))
+	}
+	return n
+}
+
+func (m *FindValueResponse) Size() (n int) {
+	var l int
+	_ = l
+	if m.Record != nil {
+		l = m.Record.Size()
+		n += 1 + l + sovStream(uint64(l))
+	}
+	if len(m.Peers) > 0 {
+		for _, e := range m.Peers {
+			l = e.Size()
+			n += 1 + l + sovStream(uint64(l))
+		}
+	}
+	return n
+}
+
+func (m *Subscribe) Size() (n int) {
+	var l int
+	_ = l
+	if len(m.Topics) > 0 {
+		for _, s := range m.Topics {
+			l = len(s)
+			n += 1 + l + sovStream(uint64(l))
+		}
+	}
+	return n
+}
+
+func (m *Publish) Size() (n int) {
+	var l int
+	_ = l
+	l = len(m.Id)
+	if l > 0 {
+		n += 1 + l + sovStream(uint64(l))
+	}
+	l = len(m.Topic)
+	if l > 0 {
+		n += 1 + l + sovStream(uint64(l))
+	}
+	l = len(m.Data)
+	if l > 0 {
+		n += 1 + l + sovStream(uint64(l))
+	}
+	return n
+}
+
+func (m *BloomFilter) Size() (n int) {
+	var l int
+	_ = l
+	l = len(m.Bits)
+	if l > 0 {
+		n += 1 + l + sovStream(uint64(l))
+	}
+	if m.Hashes != 0 {
+		n += 1 + sovStream(uint64(m.Hashes))
+	}
+	if m.Seed != 0 {
+		n += 1 + sovStream(uint64(m.Seed))
+	}
+	return n
+}
+
+func (m *Transactions) Size() (n int) {
+	var l int
+	_ = l
+	if len(m.Txs) > 0 {
+		for _, b := range m.Txs {
+			l = len(b)
+			n += 1 + l + sovStream(uint64(l))
+		}
+	}
+	return n
+}
+
+func (m *TxSyncRequest) Size() (n int) {
+	var l int
+	_ = l
+	if m.Filter != nil {
+		l = m.Filter.Size()
+		n += 1 + l + sovStream(uint64(l))
+	}
+	return n
+}
+
+func (m *TxSyncResponse) Size() (n int) {
+	var l int
+	_ = l
+	if len(m.Txs) > 0 {
+		for _, b := range m.Txs {
+			l = len(b)
+			n += 1 + l + sovStream(uint64(l))
+		}
+	}
+	if m.Filter != nil {
+		l = m.Filter.Size()
+		n += 1 + l + sovStream(uint64(l))
+	}
+	return n
+}
+
+func (m *ReliableMessage) Size() (n int) {
+	var l int
+	_ = l
+	if m.Sequence != 0 {
+		n += 1 + sovStream(uint64(m.Sequence))
+	}
+	l = len(m.Data)
+	if l > 0 {
+		n += 1 + l + sovStream(uint64(l))
+	}
+	return n
+}
+
+func (m *ReliableAck) Size() (n int) {
+	var l int
+	_ = l
+	if m.Sequence != 0 {
+		n += 1 + sovStream(uint64(m.Sequence))
+	}
+	return n
+}
+
+func (m *BlockHeader) Size() (n int) {
+	var l int
+	_ = l
+	l = len(m.Hash)
+	if l > 0 {
+		n += 1 + l + sovStream(uint64(l))
+	}
+	if m.Height != 0 {
+		n += 1 + sovStream(uint64(m.Height))
+	}
+	return n
+}
+
+func (m *GetBlock) Size() (n int) {
+	var l int
+	_ = l
+	l = len(m.Hash)
+	if l > 0 {
+		n += 1 + l + sovStream(uint64(l))
+	}
+	return n
+}
+
+func (m *Block) Size() (n int) {
+	var l int
+	_ = l
+	if m.Height != 0 {
+		n += 1 + sovStream(uint64(m.Height))
+	}
+	l = len(m.Data)
+	if l > 0 {
+		n += 1 + l + sovStream(uint64(l))
+	}
+	return n
+}
+
+func (m *KeyRevocation) Size() (n int) {
+	var l int
+	_ = l
+	l = len(m.OldPublicKey)
+	if l > 0 {
+		n += 1 + l + sovStream(uint64(l))
+	}
+	l = len(m.NewPublicKey)
+	if l > 0 {
+		n += 1 + l + sovStream(uint64(l))
+	}
+	l = len(m.Signature)
+	if l > 0 {
+		n += 1 + l + sovStream(uint64(l))
+	}
+	if m.Timestamp != 0 {
+		n += 1 + sovStream(uint64(m.Timestamp))
+	}
+	l = len(m.NewSignature)
+	if l > 0 {
+		n += 1 + l + sovStream(uint64(l))
+	}
+	return n
+}
+
+func sovStream(x uint64) (n int) {
+	for {
+		n++
+		x >>= 7
+		if x == 0 {
+			break
+		}
+	}
+	return n
+}
+func sozStream(x uint64) (n int) {
+	return sovStream(uint64((x << 1) ^ uint64((int64(x) >> 63))))
+}
+func (this *ID) String() string {
+	if this == nil {
+		return "nil"
+	}
+	s := strings.Join([]string{`&ID{`,
+		`PublicKey:` + fmt.Sprintf("%v", this.PublicKey) + `,`,
+		`Address:` + fmt.Sprintf("%v", this.Address) + `,`,
+		`Id:` + fmt.Sprintf("%v", this.Id) + `,`,
+		`Multiaddr:` + fmt.Sprintf("%v", this.Multiaddr) + `,`,
+		`}`,
+	}, "")
+	return s
+}
+func (this *Message) String() string {
+	if this == nil {
+		return "nil"
+	}
+	keysForTraceContext := make([]string, 0, len(this.TraceContext))
+	for k, _ := range this.TraceContext {
+		keysForTraceContext = append(keysForTraceContext, k)
+	}
+	sortkeys.Strings(keysForTraceContext)
+	mapStringForTraceContext := "map[string]string{"
+	for _, k := range keysForTraceContext {
+		mapStringForTraceContext += fmt.Sprintf("%v: %v,", k, this.TraceContext[k])
+	}
+	mapStringForTraceContext += "}"
+	s := strings.Join([]string{`&Message{`,
+		`Message:` + fmt.Sprintf("%v", this.Message) + `,`,
+		`Sender:` + strings.Replace(fmt.Sprintf("%v", this.Sender), "ID", "ID", 1) + `,`,
+		`Signature:` + fmt.Sprintf("%v", this.Signature) + `,`,
+		`RequestNonce:` + fmt.Sprintf("%v", this.RequestNonce) + `,`,
+		`MessageNonce:` + fmt.Sprintf("%v", this.MessageNonce) + `,`,
+		`ReplyFlag:` + fmt.Sprintf("%v", this.ReplyFlag) + `,`,
+		`Opcode:` + fmt.Sprintf("%v", this.Opcode) + `,`,
+		`RelayedFrom:` + strings.Replace(fmt.Sprintf("%v", this.RelayedFrom), "ID", "ID", 1) + `,`,
+		`Sequence:` + fmt.Sprintf("%v", this.Sequence) + `,`,
+		`TraceContext:` + mapStringForTraceContext + `,`,
+		`GroupId:` + fmt.Sprintf("%v", this.GroupId) + `,`,
+		`Compressed:` + fmt.Sprintf("%v", this.Compressed) + `,`,
+		`Timeout:` + fmt.Sprintf("%v", this.Timeout) + `,`,
+		`RelaySignature:` + fmt.Sprintf("%v", this.RelaySignature) + `,`,
+		`}`,
+	}, "")
+	return s
+}
+func (this *Ping) String() string {
+	if this == nil {
+		return "nil"
+	}
+	s := strings.Join([]string{`&Ping{`,
+		`Capabilities:` + fmt.Sprintf("%v", this.Capabilities) + `,`,
+		`}`,
+	}, "")
+	return s
+}
+func (this *Pong) String() string {
+	if this == nil {
+		return "nil"
+	}
+	s := strings.Join([]string{`&Pong{`,
+		`Capabilities:` + fmt.Sprintf("%v", this.Capabilities) + `,`,
+		`}`,
+	}, "")
+	return s
+}
+func (this *LookupNodeRequest) String() string {
+	if this == nil {
+		return "nil"
+	}
+	s := strings.Join([]string{`&LookupNodeRequest{`,
+		`Target:` + strings.Replace(fmt.Sprintf("%v", this.Target), "ID", "ID", 1) + `,`,
+		`}`,
+	}, "")
+	return s
+}
+func (this *LookupNodeResponse) String() string {
+	if this == nil {
+		return "nil"
+	}
+	s := strings.Join([]string{`&LookupNodeResponse{`,
+		`Peers:` + strings.Replace(fmt.Sprintf("%v", this.Peers), "ID", "ID", 1) + `,`,
+		`}`,
+	}, "")
+	return s
+}
+func (this *Bytes) String() string {
+	if this == nil {
+		return "nil"
+	}
+	s := strings.Join([]string{`&Bytes{`,
+		`Data:` + fmt.Sprintf("%v", this.Data) + `,`,
+		`}`,
+	}, "")
+	return s
+}
+func (this *AddressMigration) String() string {
+	if this == nil {
+		return "nil"
+	}
+	s := strings.Join([]string{`&AddressMigration{`,
+		`NewAddress:` + fmt.Sprintf("%v", this.NewAddress) + `,`,
+		`Signature:` + fmt.Sprintf("%v", this.Signature) + `,`,
+		`Timestamp:` + fmt.Sprintf("%v", this.Timestamp) + `,`,
+		`}`,
+	}, "")
+	return s
+}
+func (this *MembershipUpdate) String() string {
+	if this == nil {
+		return "nil"
+	}
+	s := strings.Join([]string{`&MembershipUpdate{`,
+		`Member:` + strings.Replace(fmt.Sprintf("%v", this.Member), "ID", "ID", 1) + `,`,
+		`State:` + fmt.Sprintf("%v", this.State) + `,`,
+		`Incarnation:` + fmt.Sprintf("%v", this.Incarnation) + `,`,
+		`Origin:` + strings.Replace(fmt.Sprintf("%v", this.Origin), "ID", "ID", 1) + `,`,
+		`Signature:` + fmt.Sprintf("%v", this.Signature) + `,`,
+		`}`,
+	}, "")
+	return s
+}
+func (this *SwimPing) String() string {
+	if this == nil {
+		return "nil"
+	}
+	s := strings.Join([]string{`&SwimPing{`,
+		`Updates:` + strings.Replace(fmt.Sprintf("%v", this.Updates), "MembershipUpdate", "MembershipUpdate", 1) + `,`,
+		`}`,
+	}, "")
+	return s
+}
+func (this *SwimPingRequest) String() string {
+	if this == nil {
+		return "nil"
+	}
+	s := strings.Join([]string{`&SwimPingRequest{`,
+		`Target:` + strings.Replace(fmt.Sprintf("%v", this.Target), "ID", "ID", 1) + `,`,
+		`Updates:` + strings.Replace(fmt.Sprintf("%v", this.Updates), "MembershipUpdate", "MembershipUpdate", 1) + `,`,
+		`}`,
+	}, "")
+	return s
+}
+func (this *SwimAck) String() string {
+	if this == nil {
+		return "nil"
+	}
+	s := strings.Join([]string{`&SwimAck{`,
+		`Updates:` + strings.Replace(fmt.Sprintf("%v", this.Updates), "MembershipUpdate", "MembershipUpdate", 1) + `,`,
+		`}`,
+	}, "")
+	return s
+}
+func (this *BeaconPartial) String() string {
+	if this == nil {
+		return "nil"
+	}
+	s := strings.Join([]string{`&BeaconPartial{`,
+		`Round:` + fmt.Sprintf("%v", this.Round) + `,`,
+		`Index:` + fmt.Sprintf("%v", this.Index) + `,`,
+		`Signature:` + fmt.Sprintf("%v", this.Signature) + `,`,
+		`}`,
+	}, "")
+	return s
+}
+func (this *ValueRecord) String() string {
+	if this == nil {
+		return "nil"
+	}
+	s := strings.Join([]string{`&ValueRecord{`,
+		`Key:` + fmt.Sprintf("%v", this.Key) + `,`,
+		`Value:` + fmt.Sprintf("%v", this.Value) + `,`,
+		`Publisher:` + strings.Replace(fmt.Sprintf("%v", this.Publisher), "ID", "ID", 1) + `,`,
+		`Expiry:` + fmt.Sprintf("%v", this.Expiry) + `,`,
+		`Signature:` + fmt.Sprintf("%v", this.Signature) + `,`,
+		`}`,
+	}, "")
+	return s
+}
+func (this *StoreRequest) String() string {
+	if this == nil {
+		return "nil"
+	}
+	s := strings.Join([]string{`&StoreRequest{`,
+		`Record:` + strings.Replace(fmt.Sprintf("%v", this.Record), "ValueRecord", "ValueRecord", 1) + `,`,
+		`}`,
+	}, "")
+	return s
+}
+func (this *StoreResponse) String() string {
+	if this == nil {
+		return "nil"
+	}
+	s := strings.Join([]string{`&StoreResponse{`,
+		`Stored:` + fmt.Sprintf("%v", this.Stored) + `,`,
+		`}`,
+	}, "")
+	return s
+}
+func (this *FindValueRequest) String() string {
+	if this == nil {
+		return "nil"
+	}
+	s := strings.Join([]string{`&FindValueRequest{`,
+		`Key:` + fmt.Sprintf("%v", this.Key) + `,`,
+		`}`,
+	}, "")
+	return s
+}
+func (this *FindValueResponse) String() string {
+	if this == nil {
+		return "nil"
+	}
+	s := strings.Join([]string{`&FindValueResponse{`,
+		`Record:` + strings.Replace(fmt.Sprintf("%v", this.Record), "ValueRecord", "ValueRecord", 1) + `,`,
+		`Peers:` + strings.Replace(fmt.Sprintf("%v", this.Peers), "ID", "ID", 1) + `,`,
+		`}`,
+	}, "")
+	return s
+}
+func (this *Subscribe) String() string {
+	if this == nil {
+		return "nil"
+	}
+	s := strings.Join([]string{`&Subscribe{`,
+		`Topics:` + fmt.Sprintf("%v", this.Topics) + `,`,
+		`}`,
+	}, "")
+	return s
+}
+func (this *Publish) String() string {
+	if this == nil {
+		return "nil"
+	}
+	s := strings.Join([]string{`&Publish{`,
+		`Id:` + fmt.Sprintf("%v", this.Id) + `,`,
+		`Topic:` + fmt.Sprintf("%v", this.Topic) + `,`,
+		`Data:` + fmt.Sprintf("%v", this.Data) + `,`,
+		`}`,
+	}, "")
+	return s
+}
+func (this *BloomFilter) String() string {
+	if this == nil {
+		return "nil"
+	}
+	s := strings.Join([]string{`&BloomFilter{`,
+		`Bits:` + fmt.Sprintf("%v", this.Bits) + `,`,
+		`Hashes:` + fmt.Sprintf("%v", this.Hashes) + `,`,
+		`Seed:` + fmt.Sprintf("%v", this.Seed) + `,`,
+		`}`,
+	}, "")
+	return s
+}
+func (this *Transactions) String() string {
+	if this == nil {
+		return "nil"
+	}
+	s := strings.Join([]string{`&Transactions{`,
+		`Txs:` + fmt.Sprintf("%v", this.Txs) + `,`,
+		`}`,
+	}, "")
+	return s
+}
+func (this *TxSyncRequest) String() string {
+	if this == nil {
+		return "nil"
+	}
+	s := strings.Join([]string{`&TxSyncRequest{`,
+		`Filter:` + strings.Replace(fmt.Sprintf("%v", this.Filter), "BloomFilter", "BloomFilter", 1) + `,`,
+		`}`,
+	}, "")
+	return s
+}
+func (this *TxSyncResponse) String() string {
+	if this == nil {
+		return "nil"
+	}
+	s := strings.Join([]string{`&TxSyncResponse{`,
+		`Txs:` + fmt.Sprintf("%v", this.Txs) + `,`,
+		`Filter:` + strings.Replace(fmt.Sprintf("%v", this.Filter), "BloomFilter", "BloomFilter", 1) + `,`,
+		`}`,
+	}, "")
+	return s
+}
+func (this *ReliableMessage) String() string {
+	if this == nil {
+		return "nil"
+	}
+	s := strings.Join([]string{`&ReliableMessage{`,
+		`Sequence:` + fmt.Sprintf("%v", this.Sequence) + `,`,
+		`Data:` + fmt.Sprintf("%v", this.Data) + `,`,
+		`}`,
+	}, "")
+	return s
+}
+func (this *ReliableAck) String() string {
+	if this == nil {
+		return "nil"
+	}
+	s := strings.Join([]string{`&ReliableAck{`,
+		`Sequence:` + fmt.Sprintf("%v", this.Sequence) + `,`,
+		`}`,
+	}, "")
+	return s
+}
+func (this *BlockHeader) String() string {
+	if this == nil {
+		return "nil"
+	}
+	s := strings.Join([]string{`&BlockHeader{`,
+		`Hash:` + fmt.Sprintf("%v", this.Hash) + `,`,
+		`Height:` + fmt.Sprintf("%v", this.Height) + `,`,
+		`}`,
+	}, "")
+	return s
+}
+func (this *GetBlock) String() string {
+	if this == nil {
+		return "nil"
+	}
+	s := strings.Join([]string{`&GetBlock{`,
+		`Hash:` + fmt.Sprintf("%v", this.Hash) + `,`,
+		`}`,
+	}, "")
+	return s
+}
+func (this *Block) String() string {
+	if this == nil {
+		return "nil"
+	}
+	s := strings.Join([]string{`&Block{`,
+		`Height:` + fmt.Sprintf("%v", this.Height) + `,`,
+		`Data:` + fmt.Sprintf("%v", this.Data) + `,`,
+		`}`,
+	}, "")
+	return s
+}
+func (this *KeyRevocation) String() string {
+	if this == nil {
+		return "nil"
+	}
+	s := strings.Join([]string{`&KeyRevocation{`,
+		`OldPublicKey:` + fmt.Sprintf("%v", this.OldPublicKey) + `,`,
+		`NewPublicKey:` + fmt.Sprintf("%v", this.NewPublicKey) + `,`,
+		`Signature:` + fmt.Sprintf("%v", this.Signature) + `,`,
+		`Timestamp:` + fmt.Sprintf("%v", this.Timestamp) + `,`,
+		`NewSignature:` + fmt.Sprintf("%v", this.NewSignature) + `,`,
+		`}`,
+	}, "")
+	return s
+}
+func valueToStringStream(v interface{}) string {
+	rv := reflect.ValueOf(v)
+	if rv.IsNil() {
+		return "nil"
+	}
+	pv := reflect.Indirect(rv).Interface()
+	return fmt.Sprintf("*%v", pv)
+}
+func (m *ID) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowStream
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= (uint64(b) & 0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: ID: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: ID: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field PublicKey", wireType)
+			}
+			var byteLen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowStream
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				byteLen |= (int(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if byteLen < 0 {
+				return ErrInvalidLengthStream
+			}
+			postIndex := iNdEx + byteLen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.PublicKey = append(m.PublicKey[:0], dAtA[iNdEx:postIndex]...)
+			if m.PublicKey == nil {
+				m.PublicKey = []byte{}
+			}
+			iNdEx = postIndex
+		case 2:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Address", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowStream
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= (uint64(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthStream
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Address = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 3:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Id", wireType)
+			}
+			var byteLen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowStream
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				byteLen |= (int(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if byteLen < 0 {
+				return ErrInvalidLengthStream
+			}
+			postIndex := iNdEx + byteLen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Id = append(m.Id[:0], dAtA[iNdEx:postIndex]...)
+			if m.Id == nil {
+				m.Id = []byte{}
+			}
+			iNdEx = postIndex
+		case 4:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Multiaddr", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowStream
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= (uint64(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthStream
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Multiaddr = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		default:
+			iNdEx = preIndex
+			skippy, err := skipStream(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if skippy < 0 {
+				return ErrInvalidLengthStream
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *Message) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowStream
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= (uint64(b) & 0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: Message: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: Message: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Message", wireType)
+			}
+			var byteLen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowStream
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				byteLen |= (int(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if byteLen < 0 {
+				return ErrInvalidLengthStream
+			}
+			postIndex := iNdEx + byteLen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Message = append(m.Message[:0], dAtA[iNdEx:postIndex]...)
+			if m.Message == nil {
+				m.Message = []byte{}
+			}
+			iNdEx = postIndex
+		case 2:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Sender", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowStream
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= (int(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthStream
+			}
+			postIndex := iNdEx + msglen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			if m.Sender == nil {
+				m.Sender = &ID{}
+			}
+			if err := m.Sender.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		case 3:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Signature", wireType)
+			}
+			var byteLen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowStream
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				byteLen |= (int(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if byteLen < 0 {
+				return ErrInvalidLengthStream
+			}
+			postIndex := iNdEx + byteLen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Signature = append(m.Signature[:0], dAtA[iNdEx:postIndex]...)
+			if m.Signature == nil {
+				m.Signature = []byte{}
+			}
+			iNdEx = postIndex
+		case 4:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field RequestNonce", wireType)
+			}
+			m.RequestNonce = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowStream
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.RequestNonce |= (uint64(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 5:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field MessageNonce", wireType)
+			}
+			m.MessageNonce = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowStream
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.MessageNonce |= (uint64(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 6:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field ReplyFlag", wireType)
+			}
+			var v int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowStream
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				v |= (int(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			m.ReplyFlag = bool(v != 0)
+		case 7:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Opcode", wireType)
+			}
+			m.Opcode = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowStream
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.Opcode |= (uint32(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 8:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field RelayedFrom", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowStream
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= (int(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthStream
+			}
+			postIndex := iNdEx + msglen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			if m.RelayedFrom == nil {
+				m.RelayedFrom = &ID{}
+			}
+			if err := m.RelayedFrom.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		case 9:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Sequence", wireType)
+			}
+			m.Sequence = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowStream
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.Sequence |= (uint64(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 10:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field TraceContext", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowStream
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= (int(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthStream
+			}
+			postIndex := iNdEx + msglen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			if m.TraceContext == nil {
+				m.TraceContext = make(map[string]string)
+			}
+			var mapkey string
+			var mapvalue string
+			for iNdEx < postIndex {
+				entryPreIndex := iNdEx
+				var wire uint64
+				for shift := uint(0); ; shift += 7 {
+					if shift >= 64 {
+						return ErrIntOverflowStream
+					}
+					if iNdEx >= l {
+						return io.ErrUnexpectedEOF
+					}
+					b := dAtA[iNdEx]
+					iNdEx++
+					wire |= (uint64(b) & 0x7F) << shift
+					if b < 0x80 {
+						break
+					}
+				}
+				fieldNum := int32(wire >> 3)
+				if fieldNum == 1 {
+					var stringLenmapkey uint64
+					for shift := uint(0); ; shift += 7 {
+						if shift >= 64 {
+							return ErrIntOverflowStream
+						}
+						if iNdEx >= l {
+							return io.ErrUnexpectedEOF
+						}
+						b := dAtA[iNdEx]
+						iNdEx++
+						stringLenmapkey |= (uint64(b) & 0x7F) << shift
+						if b < 0x80 {
+							break
+						}
+					}
+					intStringLenmapkey := int(stringLenmapkey)
+					if intStringLenmapkey < 0 {
+						return ErrInvalidLengthStream
+					}
+					postStringIndexmapkey := iNdEx + intStringLenmapkey
+					if postStringIndexmapkey > l {
+						return io.ErrUnexpectedEOF
+					}
+					mapkey = string(dAtA[iNdEx:postStringIndexmapkey])
+					iNdEx = postStringIndexmapkey
+				} else if fieldNum == 2 {
+					var stringLenmapvalue uint64
+					for shift := uint(0); ; shift += 7 {
+						if shift >= 64 {
+							return ErrIntOverflowStream
+						}
+						if iNdEx >= l {
+							return io.ErrUnexpectedEOF
+						}
+						b := dAtA[iNdEx]
+						iNdEx++
+						stringLenmapvalue |= (uint64(b) & 0x7F) << shift
+						if b < 0x80 {
+							break
+						}
+					}
+					intStringLenmapvalue := int(stringLenmapvalue)
+					if intStringLenmapvalue < 0 {
+						return ErrInvalidLengthStream
+					}
+					postStringIndexmapvalue := iNdEx + intStringLenmapvalue
+					if postStringIndexmapvalue > l {
+						return io.ErrUnexpectedEOF
+					}
+					mapvalue = string(dAtA[iNdEx:postStringIndexmapvalue])
+					iNdEx = postStringIndexmapvalue
+				} else {
+					iNdEx = entryPreIndex
+					skippy, err := skipStream(dAtA[iNdEx:])
+					if err != nil {
+						return err
+					}
+					if skippy < 0 {
+						return ErrInvalidLengthStream
+					}
+					if (iNdEx + skippy) > postIndex {
+						return io.ErrUnexpectedEOF
+					}
+					iNdEx += skippy
+				}
+			}
+			m.TraceContext[mapkey] = mapvalue
+			iNdEx = postIndex
+		case 11:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field GroupId", wireType)
+			}
+			var byteLen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowStream
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				byteLen |= (int(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if byteLen < 0 {
+				return ErrInvalidLengthStream
+			}
+			postIndex := iNdEx + byteLen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.GroupId = append(m.GroupId[:0], dAtA[iNdEx:postIndex]...)
+			if m.GroupId == nil {
+				m.GroupId = []byte{}
+			}
+			iNdEx = postIndex
+		case 12:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Compressed", wireType)
+			}
+			var v int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowStream
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				v |= (int(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			m.Compressed = bool(v != 0)
+		case 13:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Timeout", wireType)
+			}
+			m.Timeout = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowStream
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.Timeout |= (int64(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 14:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field RelaySignature", wireType)
+			}
+			var byteLen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowStream
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				byteLen |= (int(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if byteLen < 0 {
+				return ErrInvalidLengthStream
+			}
+			postIndex := iNdEx + byteLen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.RelaySignature = append(m.RelaySignature[:0], dAtA[iNdEx:postIndex]...)
+			if m.RelaySignature == nil {
+				m.RelaySignature = []byte{}
+			}
+			iNdEx = postIndex
+		default:
+			iNdEx = preIndex
+			skippy, err := skipStream(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if skippy < 0 {
+				return ErrInvalidLengthStream
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *Ping) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowStream
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= (uint64(b) & 0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: Ping: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: Ping: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Capabilities", wireType)
+			}
+			m.Capabilities = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowStream
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.Capabilities |= (uint64(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		default:
+			iNdEx = preIndex
+			skippy, err := skipStream(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if skippy < 0 {
+				return ErrInvalidLengthStream
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *Pong) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowStream
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= (uint64(b) & 0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: Pong: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: Pong: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Capabilities", wireType)
+			}
+			m.Capabilities = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowStream
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.Capabilities |= (uint64(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		default:
+			iNdEx = preIndex
+			skippy, err := skipStream(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if skippy < 0 {
+				return ErrInvalidLengthStream
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *LookupNodeRequest) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowStream
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= (uint64(b) & 0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: LookupNodeRequest: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: LookupNodeRequest: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Target", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowStream
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= (int(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthStream
+			}
+			postIndex := iNdEx + msglen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			if m.Target == nil {
+				m.Target = &ID{}
+			}
+			if err := m.Target.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		default:
+			iNdEx = preIndex
+			skippy, err := skipStream(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if skippy < 0 {
+				return ErrInvalidLengthStream
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *LookupNodeResponse) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowStream
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= (uint64(b) & 0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: LookupNodeResponse: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: LookupNodeResponse: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Peers", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowStream
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= (int(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthStream
+			}
+			postIndex := iNdEx + msglen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Peers = append(m.Peers, &ID{})
+			if err := m.Peers[len(m.Peers)-1].Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		default:
+			iNdEx = preIndex
+			skippy, err := skipStream(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if skippy < 0 {
+				return ErrInvalidLengthStream
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *Bytes) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowStream
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= (uint64(b) & 0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: Bytes: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: Bytes: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Data", wireType)
+			}
+			var byteLen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowStream
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				byteLen |= (int(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if byteLen < 0 {
+				return ErrInvalidLengthStream
+			}
+			postIndex := iNdEx + byteLen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Data = append(m.Data[:0], dAtA[iNdEx:postIndex]...)
+			if m.Data == nil {
+				m.Data = []byte{}
+			}
+			iNdEx = postIndex
+		default:
+			iNdEx = preIndex
+			skippy, err := skipStream(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if skippy < 0 {
+				return ErrInvalidLengthStream
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *AddressMigration) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowStream
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= (uint64(b) & 0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: AddressMigration: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: AddressMigration: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field NewAddress", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowStream
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= (uint64(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthStream
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.NewAddress = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 2:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Signature", wireType)
+			}
+			var byteLen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowStream
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				byteLen |= (int(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if byteLen < 0 {
+				return ErrInvalidLengthStream
+			}
+			postIndex := iNdEx + byteLen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Signature = append(m.Signature[:0], dAtA[iNdEx:postIndex]...)
+			if m.Signature == nil {
+				m.Signature = []byte{}
+			}
+			iNdEx = postIndex
+		case 3:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Timestamp", wireType)
+			}
+			m.Timestamp = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowStream
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.Timestamp |= (uint64(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		default:
+			iNdEx = preIndex
+			skippy, err := skipStream(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if skippy < 0 {
+				return ErrInvalidLengthStream
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *MembershipUpdate) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowStream
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= (uint64(b) & 0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: MembershipUpdate: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: MembershipUpdate: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Member", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowStream
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= (int(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthStream
+			}
+			postIndex := iNdEx + msglen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			if m.Member == nil {
+				m.Member = &ID{}
+			}
+			if err := m.Member.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		case 2:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field State", wireType)
+			}
+			m.State = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowStream
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.State |= (MembershipUpdate_State(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 3:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Incarnation", wireType)
+			}
+			m.Incarnation = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowStream
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.Incarnation |= (uint64(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 4:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Origin", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowStream
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= (int(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthStream
+			}
+			postIndex := iNdEx + msglen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			if m.Origin == nil {
+				m.Origin = &ID{}
+			}
+			if err := m.Origin.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		case 5:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Signature", wireType)
+			}
+			var byteLen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowStream
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				byteLen |= (int(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if byteLen < 0 {
+				return ErrInvalidLengthStream
+			}
+			postIndex := iNdEx + byteLen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Signature = append(m.Signature[:0], dAtA[iNdEx:postIndex]...)
+			if m.Signature == nil {
+				m.Signature = []byte{}
+			}
+			iNdEx = postIndex
+		default:
+			iNdEx = preIndex
+			skippy, err := skipStream(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if skippy < 0 {
+				return ErrInvalidLengthStream
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *SwimPing) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowStream
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= (uint64(b) & 0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: SwimPing: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: SwimPing: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Updates", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowStream
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= (int(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthStream
+			}
+			postIndex := iNdEx + msglen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Updates = append(m.Updates, &MembershipUpdate{})
+			if err := m.Updates[len(m.Updates)-1].Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		default:
+			iNdEx = preIndex
+			skippy, err := skipStream(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if skippy < 0 {
+				return ErrInvalidLengthStream
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *SwimPingRequest) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowStream
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= (uint64(b) & 0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: SwimPingRequest: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: SwimPingRequest: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Target", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowStream
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= (int(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthStream
+			}
+			postIndex := iNdEx + msglen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			if m.Target == nil {
+				m.Target = &ID{}
+			}
+			if err := m.Target.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		case 2:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Updates", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowStream
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= (int(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthStream
+			}
+			postIndex := iNdEx + msglen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Updates = append(m.Updates, &MembershipUpdate{})
+			if err := m.Updates[len(m.Updates)-1].Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		default:
+			iNdEx = preIndex
+			skippy, err := skipStream(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if skippy < 0 {
+				return ErrInvalidLengthStream
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *SwimAck) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowStream
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= (uint64(b) & 0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: SwimAck: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: SwimAck: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Updates", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowStream
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= (int(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthStream
+			}
+			postIndex := iNdEx + msglen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Updates = append(m.Updates, &MembershipUpdate{})
+			if err := m.Updates[len(m.Updates)-1].Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		default:
+			iNdEx = preIndex
+			skippy, err := skipStream(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if skippy < 0 {
+				return ErrInvalidLengthStream
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *BeaconPartial) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowStream
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= (uint64(b) & 0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: BeaconPartial: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: BeaconPartial: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Round", wireType)
+			}
+			m.Round = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowStream
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.Round |= (uint64(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 2:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Index", wireType)
+			}
+			m.Index = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowStream
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.Index |= (uint32(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 3:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Signature", wireType)
+			}
+			var byteLen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowStream
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				byteLen |= (int(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if byteLen < 0 {
+				return ErrInvalidLengthStream
+			}
+			postIndex := iNdEx + byteLen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Signature = append(m.Signature[:0], dAtA[iNdEx:postIndex]...)
+			if m.Signature == nil {
+				m.Signature = []byte{}
+			}
+			iNdEx = postIndex
+		default:
+			iNdEx = preIndex
+			skippy, err := skipStream(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if skippy < 0 {
+				return ErrInvalidLengthStream
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *ValueRecord) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowStream
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= (uint64(b) & 0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: ValueRecord: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: ValueRecord: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Key", wireType)
+			}
+			var byteLen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowStream
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				byteLen |= (int(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if byteLen < 0 {
+				return ErrInvalidLengthStream
+			}
+			postIndex := iNdEx + byteLen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Key = append(m.Key[:0], dAtA[iNdEx:postIndex]...)
+			if m.Key == nil {
+				m.Key = []byte{}
+			}
+			iNdEx = postIndex
+		case 2:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Value", wireType)
+			}
+			var byteLen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowStream
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				byteLen |= (int(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if byteLen < 0 {
+				return ErrInvalidLengthStream
+			}
+			postIndex := iNdEx + byteLen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Value = append(m.Value[:0], dAtA[iNdEx:postIndex]...)
+			if m.Value == nil {
+				m.Value = []byte{}
+			}
+			iNdEx = postIndex
+		case 3:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Publisher", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowStream
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= (int(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthStream
+			}
+			postIndex := iNdEx + msglen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			if m.Publisher == nil {
+				m.Publisher = &ID{}
+			}
+			if err := m.Publisher.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		case 4:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Expiry", wireType)
+			}
+			m.Expiry = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowStream
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.Expiry |= (int64(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 5:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Signature", wireType)
+			}
+			var byteLen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowStream
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				byteLen |= (int(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if byteLen < 0 {
+				return ErrInvalidLengthStream
+			}
+			postIndex := iNdEx + byteLen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Signature = append(m.Signature[:0], dAtA[iNdEx:postIndex]...)
+			if m.Signature == nil {
+				m.Signature = []byte{}
+			}
+			iNdEx = postIndex
+		default:
+			iNdEx = preIndex
+			skippy, err := skipStream(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if skippy < 0 {
+				return ErrInvalidLengthStream
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *StoreRequest) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowStream
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= (uint64(b) & 0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: StoreRequest: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: StoreRequest: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Record", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowStream
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= (int(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthStream
+			}
+			postIndex := iNdEx + msglen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			if m.Record == nil {
+				m.Record = &ValueRecord{}
+			}
+			if err := m.Record.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		default:
+			iNdEx = preIndex
+			skippy, err := skipStream(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if skippy < 0 {
+				return ErrInvalidLengthStream
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *StoreResponse) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowStream
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= (uint64(b) & 0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: StoreResponse: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: StoreResponse: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Stored", wireType)
+			}
+			var v int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowStream
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				v |= (int(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			m.Stored = bool(v != 0)
+		default:
+			iNdEx = preIndex
+			skippy, err := skipStream(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if skippy < 0 {
+				return ErrInvalidLengthStream
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *FindValueRequest) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowStream
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= (uint64(b) & 0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: FindValueRequest: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: FindValueRequest: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Key", wireType)
+			}
+			var byteLen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowStream
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				byteLen |= (int(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if byteLen < 0 {
+				return ErrInvalidLengthStream
+			}
+			postIndex := iNdEx + byteLen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Key = append(m.Key[:0], dAtA[iNdEx:postIndex]...)
+			if m.Key == nil {
+				m.Key = []byte{}
+			}
+			iNdEx = postIndex
+		default:
+			iNdEx = preIndex
+			skippy, err := skipStream(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if skippy < 0 {
+				return ErrInvalidLengthStream
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *FindValueResponse) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowStream
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= (uint64(b) & 0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: FindValueResponse: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: FindValueResponse: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Record", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowStream
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= (int(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthStream
+			}
+			postIndex := iNdEx + msglen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			if m.Record == nil {
+				m.Record = &ValueRecord{}
+			}
+			if err := m.Record.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		case 2:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Peers", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowStream
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= (int(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthStream
+			}
+			postIndex := iNdEx + msglen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Peers = append(m.Peers, &ID{})
+			if err := m.Peers[len(m.Peers)-1].Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		default:
+			iNdEx = preIndex
+			skippy, err := skipStream(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if skippy < 0 {
+				return ErrInvalidLengthStream
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *Subscribe) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowStream
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= (uint64(b) & 0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: Subscribe: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: Subscribe: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Topics", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowStream
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= (uint64(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthStream
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Topics = append(m.Topics, string(dAtA[iNdEx:postIndex]))
+			iNdEx = postIndex
+		default:
+			iNdEx = preIndex
+			skippy, err := skipStream(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if skippy < 0 {
+				return ErrInvalidLengthStream
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *Publish) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowStream
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= (uint64(b) & 0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: Publish: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: Publish: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Id", wireType)
+			}
+			var byteLen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowStream
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				byteLen |= (int(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if byteLen < 0 {
+				return ErrInvalidLengthStream
+			}
+			postIndex := iNdEx + byteLen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Id = append(m.Id[:0], dAtA[iNdEx:postIndex]...)
+			if m.Id == nil {
+				m.Id = []byte{}
+			}
+			iNdEx = postIndex
+		case 2:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Topic", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowStream
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= (uint64(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthStream
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Topic = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 3:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Data", wireType)
+			}
+			var byteLen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowStream
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				byteLen |= (int(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if byteLen < 0 {
+				return ErrInvalidLengthStream
+			}
+			postIndex := iNdEx + byteLen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Data = append(m.Data[:0], dAtA[iNdEx:postIndex]...)
+			if m.Data == nil {
+				m.Data = []byte{}
+			}
+			iNdEx = postIndex
+		default:
+			iNdEx = preIndex
+			skippy, err := skipStream(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if skippy < 0 {
+				return ErrInvalidLengthStream
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += skippy
+		}
 	}
-	s := strings.Join([]string{`&Bytes{`,
-		`Data:` + fmt.Sprintf("%v", this.Data) + `,`,
-		`}`,
-	}, "")
-	return s
-}
-func valueToStringStream(v interface{}) string {
-	rv := reflect.ValueOf(v)
-	if rv.IsNil() {
-		return "nil"
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
 	}
-	pv := reflect.Indirect(rv).Interface()
-	return fmt.Sprintf("*%v", pv)
+	return nil
 }
-func (m *ID) Unmarshal(dAtA []byte) error {
+func (m *BloomFilter) Unmarshal(dAtA []byte) error {
 	l := len(dAtA)
 	iNdEx := 0
 	for iNdEx < l {
@@ -1166,15 +7777,15 @@ func (m *ID) Unmarshal(dAtA []byte) error {
 		fieldNum := int32(wire >> 3)
 		wireType := int(wire & 0x7)
 		if wireType == 4 {
-			return fmt.Errorf("proto: ID: wiretype end group for non-group")
+			return fmt.Errorf("proto: BloomFilter: wiretype end group for non-group")
 		}
 		if fieldNum <= 0 {
-			return fmt.Errorf("proto: ID: illegal tag %d (wire type %d)", fieldNum, wire)
+			return fmt.Errorf("proto: BloomFilter: illegal tag %d (wire type %d)", fieldNum, wire)
 		}
 		switch fieldNum {
 		case 1:
 			if wireType != 2 {
-				return fmt.Errorf("proto: wrong wireType = %d for field PublicKey", wireType)
+				return fmt.Errorf("proto: wrong wireType = %d for field Bits", wireType)
 			}
 			var byteLen int
 			for shift := uint(0); ; shift += 7 {
@@ -1198,16 +7809,16 @@ func (m *ID) Unmarshal(dAtA []byte) error {
 			if postIndex > l {
 				return io.ErrUnexpectedEOF
 			}
-			m.PublicKey = append(m.PublicKey[:0], dAtA[iNdEx:postIndex]...)
-			if m.PublicKey == nil {
-				m.PublicKey = []byte{}
+			m.Bits = append(m.Bits[:0], dAtA[iNdEx:postIndex]...)
+			if m.Bits == nil {
+				m.Bits = []byte{}
 			}
 			iNdEx = postIndex
 		case 2:
-			if wireType != 2 {
-				return fmt.Errorf("proto: wrong wireType = %d for field Address", wireType)
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Hashes", wireType)
 			}
-			var stringLen uint64
+			m.Hashes = 0
 			for shift := uint(0); ; shift += 7 {
 				if shift >= 64 {
 					return ErrIntOverflowStream
@@ -1217,26 +7828,16 @@ func (m *ID) Unmarshal(dAtA []byte) error {
 				}
 				b := dAtA[iNdEx]
 				iNdEx++
-				stringLen |= (uint64(b) & 0x7F) << shift
+				m.Hashes |= (uint32(b) & 0x7F) << shift
 				if b < 0x80 {
 					break
 				}
 			}
-			intStringLen := int(stringLen)
-			if intStringLen < 0 {
-				return ErrInvalidLengthStream
-			}
-			postIndex := iNdEx + intStringLen
-			if postIndex > l {
-				return io.ErrUnexpectedEOF
-			}
-			m.Address = string(dAtA[iNdEx:postIndex])
-			iNdEx = postIndex
 		case 3:
-			if wireType != 2 {
-				return fmt.Errorf("proto: wrong wireType = %d for field Id", wireType)
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Seed", wireType)
 			}
-			var byteLen int
+			m.Seed = 0
 			for shift := uint(0); ; shift += 7 {
 				if shift >= 64 {
 					return ErrIntOverflowStream
@@ -1246,23 +7847,11 @@ func (m *ID) Unmarshal(dAtA []byte) error {
 				}
 				b := dAtA[iNdEx]
 				iNdEx++
-				byteLen |= (int(b) & 0x7F) << shift
+				m.Seed |= (uint64(b) & 0x7F) << shift
 				if b < 0x80 {
 					break
 				}
 			}
-			if byteLen < 0 {
-				return ErrInvalidLengthStream
-			}
-			postIndex := iNdEx + byteLen
-			if postIndex > l {
-				return io.ErrUnexpectedEOF
-			}
-			m.Id = append(m.Id[:0], dAtA[iNdEx:postIndex]...)
-			if m.Id == nil {
-				m.Id = []byte{}
-			}
-			iNdEx = postIndex
 		default:
 			iNdEx = preIndex
 			skippy, err := skipStream(dAtA[iNdEx:])
@@ -1284,7 +7873,7 @@ func (m *ID) Unmarshal(dAtA []byte) error {
 	}
 	return nil
 }
-func (m *Message) Unmarshal(dAtA []byte) error {
+func (m *Transactions) Unmarshal(dAtA []byte) error {
 	l := len(dAtA)
 	iNdEx := 0
 	for iNdEx < l {
@@ -1307,15 +7896,15 @@ func (m *Message) Unmarshal(dAtA []byte) error {
 		fieldNum := int32(wire >> 3)
 		wireType := int(wire & 0x7)
 		if wireType == 4 {
-			return fmt.Errorf("proto: Message: wiretype end group for non-group")
+			return fmt.Errorf("proto: Transactions: wiretype end group for non-group")
 		}
 		if fieldNum <= 0 {
-			return fmt.Errorf("proto: Message: illegal tag %d (wire type %d)", fieldNum, wire)
+			return fmt.Errorf("proto: Transactions: illegal tag %d (wire type %d)", fieldNum, wire)
 		}
 		switch fieldNum {
 		case 1:
 			if wireType != 2 {
-				return fmt.Errorf("proto: wrong wireType = %d for field Message", wireType)
+				return fmt.Errorf("proto: wrong wireType = %d for field Txs", wireType)
 			}
 			var byteLen int
 			for shift := uint(0); ; shift += 7 {
@@ -1339,14 +7928,62 @@ func (m *Message) Unmarshal(dAtA []byte) error {
 			if postIndex > l {
 				return io.ErrUnexpectedEOF
 			}
-			m.Message = append(m.Message[:0], dAtA[iNdEx:postIndex]...)
-			if m.Message == nil {
-				m.Message = []byte{}
-			}
+			m.Txs = append(m.Txs, make([]byte, postIndex-iNdEx))
+			copy(m.Txs[len(m.Txs)-1], dAtA[iNdEx:postIndex])
 			iNdEx = postIndex
-		case 2:
+		default:
+			iNdEx = preIndex
+			skippy, err := skipStream(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if skippy < 0 {
+				return ErrInvalidLengthStream
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *TxSyncRequest) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowStream
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= (uint64(b) & 0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: TxSyncRequest: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: TxSyncRequest: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
 			if wireType != 2 {
-				return fmt.Errorf("proto: wrong wireType = %d for field Sender", wireType)
+				return fmt.Errorf("proto: wrong wireType = %d for field Filter", wireType)
 			}
 			var msglen int
 			for shift := uint(0); ; shift += 7 {
@@ -1370,16 +8007,66 @@ func (m *Message) Unmarshal(dAtA []byte) error {
 			if postIndex > l {
 				return io.ErrUnexpectedEOF
 			}
-			if m.Sender == nil {
-				m.Sender = &ID{}
+			if m.Filter == nil {
+				m.Filter = &BloomFilter{}
 			}
-			if err := m.Sender.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+			if err := m.Filter.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
 				return err
 			}
 			iNdEx = postIndex
-		case 3:
+		default:
+			iNdEx = preIndex
+			skippy, err := skipStream(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if skippy < 0 {
+				return ErrInvalidLengthStream
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *TxSyncResponse) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowStream
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= (uint64(b) & 0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: TxSyncResponse: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: TxSyncResponse: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
 			if wireType != 2 {
-				return fmt.Errorf("proto: wrong wireType = %d for field Signature", wireType)
+				return fmt.Errorf("proto: wrong wireType = %d for field Txs", wireType)
 			}
 			var byteLen int
 			for shift := uint(0); ; shift += 7 {
@@ -1403,16 +8090,14 @@ func (m *Message) Unmarshal(dAtA []byte) error {
 			if postIndex > l {
 				return io.ErrUnexpectedEOF
 			}
-			m.Signature = append(m.Signature[:0], dAtA[iNdEx:postIndex]...)
-			if m.Signature == nil {
-				m.Signature = []byte{}
-			}
+			m.Txs = append(m.Txs, make([]byte, postIndex-iNdEx))
+			copy(m.Txs[len(m.Txs)-1], dAtA[iNdEx:postIndex])
 			iNdEx = postIndex
-		case 4:
-			if wireType != 0 {
-				return fmt.Errorf("proto: wrong wireType = %d for field RequestNonce", wireType)
+		case 2:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Filter", wireType)
 			}
-			m.RequestNonce = 0
+			var msglen int
 			for shift := uint(0); ; shift += 7 {
 				if shift >= 64 {
 					return ErrIntOverflowStream
@@ -1422,35 +8107,80 @@ func (m *Message) Unmarshal(dAtA []byte) error {
 				}
 				b := dAtA[iNdEx]
 				iNdEx++
-				m.RequestNonce |= (uint64(b) & 0x7F) << shift
+				msglen |= (int(b) & 0x7F) << shift
 				if b < 0x80 {
 					break
 				}
 			}
-		case 5:
-			if wireType != 0 {
-				return fmt.Errorf("proto: wrong wireType = %d for field MessageNonce", wireType)
+			if msglen < 0 {
+				return ErrInvalidLengthStream
 			}
-			m.MessageNonce = 0
-			for shift := uint(0); ; shift += 7 {
-				if shift >= 64 {
-					return ErrIntOverflowStream
-				}
-				if iNdEx >= l {
-					return io.ErrUnexpectedEOF
-				}
-				b := dAtA[iNdEx]
-				iNdEx++
-				m.MessageNonce |= (uint64(b) & 0x7F) << shift
-				if b < 0x80 {
-					break
-				}
+			postIndex := iNdEx + msglen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
 			}
-		case 6:
+			if m.Filter == nil {
+				m.Filter = &BloomFilter{}
+			}
+			if err := m.Filter.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		default:
+			iNdEx = preIndex
+			skippy, err := skipStream(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if skippy < 0 {
+				return ErrInvalidLengthStream
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *ReliableMessage) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowStream
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= (uint64(b) & 0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: ReliableMessage: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: ReliableMessage: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
 			if wireType != 0 {
-				return fmt.Errorf("proto: wrong wireType = %d for field ReplyFlag", wireType)
+				return fmt.Errorf("proto: wrong wireType = %d for field Sequence", wireType)
 			}
-			var v int
+			m.Sequence = 0
 			for shift := uint(0); ; shift += 7 {
 				if shift >= 64 {
 					return ErrIntOverflowStream
@@ -1460,17 +8190,16 @@ func (m *Message) Unmarshal(dAtA []byte) error {
 				}
 				b := dAtA[iNdEx]
 				iNdEx++
-				v |= (int(b) & 0x7F) << shift
+				m.Sequence |= (uint64(b) & 0x7F) << shift
 				if b < 0x80 {
 					break
 				}
 			}
-			m.ReplyFlag = bool(v != 0)
-		case 7:
-			if wireType != 0 {
-				return fmt.Errorf("proto: wrong wireType = %d for field Opcode", wireType)
+		case 2:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Data", wireType)
 			}
-			m.Opcode = 0
+			var byteLen int
 			for shift := uint(0); ; shift += 7 {
 				if shift >= 64 {
 					return ErrIntOverflowStream
@@ -1480,11 +8209,23 @@ func (m *Message) Unmarshal(dAtA []byte) error {
 				}
 				b := dAtA[iNdEx]
 				iNdEx++
-				m.Opcode |= (uint32(b) & 0x7F) << shift
+				byteLen |= (int(b) & 0x7F) << shift
 				if b < 0x80 {
 					break
 				}
 			}
+			if byteLen < 0 {
+				return ErrInvalidLengthStream
+			}
+			postIndex := iNdEx + byteLen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Data = append(m.Data[:0], dAtA[iNdEx:postIndex]...)
+			if m.Data == nil {
+				m.Data = []byte{}
+			}
+			iNdEx = postIndex
 		default:
 			iNdEx = preIndex
 			skippy, err := skipStream(dAtA[iNdEx:])
@@ -1506,7 +8247,7 @@ func (m *Message) Unmarshal(dAtA []byte) error {
 	}
 	return nil
 }
-func (m *Ping) Unmarshal(dAtA []byte) error {
+func (m *ReliableAck) Unmarshal(dAtA []byte) error {
 	l := len(dAtA)
 	iNdEx := 0
 	for iNdEx < l {
@@ -1529,12 +8270,31 @@ func (m *Ping) Unmarshal(dAtA []byte) error {
 		fieldNum := int32(wire >> 3)
 		wireType := int(wire & 0x7)
 		if wireType == 4 {
-			return fmt.Errorf("proto: Ping: wiretype end group for non-group")
+			return fmt.Errorf("proto: ReliableAck: wiretype end group for non-group")
 		}
 		if fieldNum <= 0 {
-			return fmt.Errorf("proto: Ping: illegal tag %d (wire type %d)", fieldNum, wire)
+			return fmt.Errorf("proto: ReliableAck: illegal tag %d (wire type %d)", fieldNum, wire)
 		}
 		switch fieldNum {
+		case 1:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Sequence", wireType)
+			}
+			m.Sequence = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowStream
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.Sequence |= (uint64(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
 		default:
 			iNdEx = preIndex
 			skippy, err := skipStream(dAtA[iNdEx:])
@@ -1556,7 +8316,7 @@ func (m *Ping) Unmarshal(dAtA []byte) error {
 	}
 	return nil
 }
-func (m *Pong) Unmarshal(dAtA []byte) error {
+func (m *BlockHeader) Unmarshal(dAtA []byte) error {
 	l := len(dAtA)
 	iNdEx := 0
 	for iNdEx < l {
@@ -1579,12 +8339,62 @@ func (m *Pong) Unmarshal(dAtA []byte) error {
 		fieldNum := int32(wire >> 3)
 		wireType := int(wire & 0x7)
 		if wireType == 4 {
-			return fmt.Errorf("proto: Pong: wiretype end group for non-group")
+			return fmt.Errorf("proto: BlockHeader: wiretype end group for non-group")
 		}
 		if fieldNum <= 0 {
-			return fmt.Errorf("proto: Pong: illegal tag %d (wire type %d)", fieldNum, wire)
+			return fmt.Errorf("proto: BlockHeader: illegal tag %d (wire type %d)", fieldNum, wire)
 		}
 		switch fieldNum {
+		case 1:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Hash", wireType)
+			}
+			var byteLen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowStream
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				byteLen |= (int(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if byteLen < 0 {
+				return ErrInvalidLengthStream
+			}
+			postIndex := iNdEx + byteLen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Hash = append(m.Hash[:0], dAtA[iNdEx:postIndex]...)
+			if m.Hash == nil {
+				m.Hash = []byte{}
+			}
+			iNdEx = postIndex
+		case 2:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Height", wireType)
+			}
+			m.Height = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowStream
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.Height |= (uint64(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
 		default:
 			iNdEx = preIndex
 			skippy, err := skipStream(dAtA[iNdEx:])
@@ -1606,7 +8416,7 @@ func (m *Pong) Unmarshal(dAtA []byte) error {
 	}
 	return nil
 }
-func (m *LookupNodeRequest) Unmarshal(dAtA []byte) error {
+func (m *GetBlock) Unmarshal(dAtA []byte) error {
 	l := len(dAtA)
 	iNdEx := 0
 	for iNdEx < l {
@@ -1629,17 +8439,17 @@ func (m *LookupNodeRequest) Unmarshal(dAtA []byte) error {
 		fieldNum := int32(wire >> 3)
 		wireType := int(wire & 0x7)
 		if wireType == 4 {
-			return fmt.Errorf("proto: LookupNodeRequest: wiretype end group for non-group")
+			return fmt.Errorf("proto: GetBlock: wiretype end group for non-group")
 		}
 		if fieldNum <= 0 {
-			return fmt.Errorf("proto: LookupNodeRequest: illegal tag %d (wire type %d)", fieldNum, wire)
+			return fmt.Errorf("proto: GetBlock: illegal tag %d (wire type %d)", fieldNum, wire)
 		}
 		switch fieldNum {
 		case 1:
 			if wireType != 2 {
-				return fmt.Errorf("proto: wrong wireType = %d for field Target", wireType)
+				return fmt.Errorf("proto: wrong wireType = %d for field Hash", wireType)
 			}
-			var msglen int
+			var byteLen int
 			for shift := uint(0); ; shift += 7 {
 				if shift >= 64 {
 					return ErrIntOverflowStream
@@ -1649,23 +8459,21 @@ func (m *LookupNodeRequest) Unmarshal(dAtA []byte) error {
 				}
 				b := dAtA[iNdEx]
 				iNdEx++
-				msglen |= (int(b) & 0x7F) << shift
+				byteLen |= (int(b) & 0x7F) << shift
 				if b < 0x80 {
 					break
 				}
 			}
-			if msglen < 0 {
+			if byteLen < 0 {
 				return ErrInvalidLengthStream
 			}
-			postIndex := iNdEx + msglen
+			postIndex := iNdEx + byteLen
 			if postIndex > l {
 				return io.ErrUnexpectedEOF
 			}
-			if m.Target == nil {
-				m.Target = &ID{}
-			}
-			if err := m.Target.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
-				return err
+			m.Hash = append(m.Hash[:0], dAtA[iNdEx:postIndex]...)
+			if m.Hash == nil {
+				m.Hash = []byte{}
 			}
 			iNdEx = postIndex
 		default:
@@ -1689,7 +8497,7 @@ func (m *LookupNodeRequest) Unmarshal(dAtA []byte) error {
 	}
 	return nil
 }
-func (m *LookupNodeResponse) Unmarshal(dAtA []byte) error {
+func (m *Block) Unmarshal(dAtA []byte) error {
 	l := len(dAtA)
 	iNdEx := 0
 	for iNdEx < l {
@@ -1712,17 +8520,36 @@ func (m *LookupNodeResponse) Unmarshal(dAtA []byte) error {
 		fieldNum := int32(wire >> 3)
 		wireType := int(wire & 0x7)
 		if wireType == 4 {
-			return fmt.Errorf("proto: LookupNodeResponse: wiretype end group for non-group")
+			return fmt.Errorf("proto: Block: wiretype end group for non-group")
 		}
 		if fieldNum <= 0 {
-			return fmt.Errorf("proto: LookupNodeResponse: illegal tag %d (wire type %d)", fieldNum, wire)
+			return fmt.Errorf("proto: Block: illegal tag %d (wire type %d)", fieldNum, wire)
 		}
 		switch fieldNum {
 		case 1:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Height", wireType)
+			}
+			m.Height = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowStream
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.Height |= (uint64(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 2:
 			if wireType != 2 {
-				return fmt.Errorf("proto: wrong wireType = %d for field Peers", wireType)
+				return fmt.Errorf("proto: wrong wireType = %d for field Data", wireType)
 			}
-			var msglen int
+			var byteLen int
 			for shift := uint(0); ; shift += 7 {
 				if shift >= 64 {
 					return ErrIntOverflowStream
@@ -1732,21 +8559,21 @@ func (m *LookupNodeResponse) Unmarshal(dAtA []byte) error {
 				}
 				b := dAtA[iNdEx]
 				iNdEx++
-				msglen |= (int(b) & 0x7F) << shift
+				byteLen |= (int(b) & 0x7F) << shift
 				if b < 0x80 {
 					break
 				}
 			}
-			if msglen < 0 {
+			if byteLen < 0 {
 				return ErrInvalidLengthStream
 			}
-			postIndex := iNdEx + msglen
+			postIndex := iNdEx + byteLen
 			if postIndex > l {
 				return io.ErrUnexpectedEOF
 			}
-			m.Peers = append(m.Peers, &ID{})
-			if err := m.Peers[len(m.Peers)-1].Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
-				return err
+			m.Data = append(m.Data[:0], dAtA[iNdEx:postIndex]...)
+			if m.Data == nil {
+				m.Data = []byte{}
 			}
 			iNdEx = postIndex
 		default:
@@ -1770,7 +8597,7 @@ func (m *LookupNodeResponse) Unmarshal(dAtA []byte) error {
 	}
 	return nil
 }
-func (m *Bytes) Unmarshal(dAtA []byte) error {
+func (m *KeyRevocation) Unmarshal(dAtA []byte) error {
 	l := len(dAtA)
 	iNdEx := 0
 	for iNdEx < l {
@@ -1793,15 +8620,15 @@ func (m *Bytes) Unmarshal(dAtA []byte) error {
 		fieldNum := int32(wire >> 3)
 		wireType := int(wire & 0x7)
 		if wireType == 4 {
-			return fmt.Errorf("proto: Bytes: wiretype end group for non-group")
+			return fmt.Errorf("proto: KeyRevocation: wiretype end group for non-group")
 		}
 		if fieldNum <= 0 {
-			return fmt.Errorf("proto: Bytes: illegal tag %d (wire type %d)", fieldNum, wire)
+			return fmt.Errorf("proto: KeyRevocation: illegal tag %d (wire type %d)", fieldNum, wire)
 		}
 		switch fieldNum {
 		case 1:
 			if wireType != 2 {
-				return fmt.Errorf("proto: wrong wireType = %d for field Data", wireType)
+				return fmt.Errorf("proto: wrong wireType = %d for field OldPublicKey", wireType)
 			}
 			var byteLen int
 			for shift := uint(0); ; shift += 7 {
@@ -1825,9 +8652,121 @@ func (m *Bytes) Unmarshal(dAtA []byte) error {
 			if postIndex > l {
 				return io.ErrUnexpectedEOF
 			}
-			m.Data = append(m.Data[:0], dAtA[iNdEx:postIndex]...)
-			if m.Data == nil {
-				m.Data = []byte{}
+			m.OldPublicKey = append(m.OldPublicKey[:0], dAtA[iNdEx:postIndex]...)
+			if m.OldPublicKey == nil {
+				m.OldPublicKey = []byte{}
+			}
+			iNdEx = postIndex
+		case 2:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field NewPublicKey", wireType)
+			}
+			var byteLen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowStream
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				byteLen |= (int(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if byteLen < 0 {
+				return ErrInvalidLengthStream
+			}
+			postIndex := iNdEx + byteLen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.NewPublicKey = append(m.NewPublicKey[:0], dAtA[iNdEx:postIndex]...)
+			if m.NewPublicKey == nil {
+				m.NewPublicKey = []byte{}
+			}
+			iNdEx = postIndex
+		case 3:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Signature", wireType)
+			}
+			var byteLen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowStream
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				byteLen |= (int(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if byteLen < 0 {
+				return ErrInvalidLengthStream
+			}
+			postIndex := iNdEx + byteLen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Signature = append(m.Signature[:0], dAtA[iNdEx:postIndex]...)
+			if m.Signature == nil {
+				m.Signature = []byte{}
+			}
+			iNdEx = postIndex
+		case 4:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Timestamp", wireType)
+			}
+			m.Timestamp = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowStream
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.Timestamp |= (uint64(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 5:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field NewSignature", wireType)
+			}
+			var byteLen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowStream
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				byteLen |= (int(b) & 0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if byteLen < 0 {
+				return ErrInvalidLengthStream
+			}
+			postIndex := iNdEx + byteLen
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.NewSignature = append(m.NewSignature[:0], dAtA[iNdEx:postIndex]...)
+			if m.NewSignature == nil {
+				m.NewSignature = []byte{}
 			}
 			iNdEx = postIndex
 		default:
@@ -1959,32 +8898,85 @@ var (
 func init() { proto.RegisterFile("internal/protobuf/stream.proto", fileDescriptorStream) }
 
 var fileDescriptorStream = []byte{
-	// 422 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x64, 0x90, 0xc1, 0x6e, 0x13, 0x31,
-	0x10, 0x86, 0xeb, 0x34, 0xd9, 0x34, 0x43, 0x8a, 0x84, 0x0f, 0xd5, 0x0a, 0xa8, 0xb5, 0x5a, 0x38,
-	0xec, 0x69, 0x2b, 0xc1, 0x05, 0xae, 0x51, 0x85, 0x54, 0xa0, 0x51, 0xb4, 0x2f, 0x10, 0x39, 0xd9,
-	0xa9, 0xb5, 0xea, 0xd6, 0x36, 0xb6, 0xf7, 0x90, 0x1b, 0x8f, 0xc0, 0x63, 0xf0, 0x28, 0x1c, 0x39,
-	0x72, 0x6c, 0x96, 0x0b, 0x47, 0x1e, 0x01, 0xad, 0xed, 0xa8, 0x48, 0x3d, 0x79, 0xfe, 0x6f, 0xfe,
-	0xdf, 0x63, 0x0f, 0xb0, 0x46, 0x3a, 0x34, 0x92, 0xb7, 0x17, 0xda, 0x28, 0xa7, 0x36, 0xdd, 0xcd,
-	0x85, 0x75, 0x06, 0xf9, 0x5d, 0xe9, 0x35, 0x3d, 0x39, 0xe0, 0xe7, 0xb9, 0x50, 0x42, 0x3d, 0xb8,
-	0x06, 0xe5, 0x85, 0xaf, 0x82, 0x3b, 0xbf, 0x86, 0xd1, 0xd5, 0x25, 0x3d, 0x07, 0xd0, 0xdd, 0xa6,
-	0x6d, 0xb6, 0xeb, 0x5b, 0xdc, 0xa5, 0x24, 0x23, 0xc5, 0xbc, 0x9a, 0x05, 0xf2, 0x09, 0x77, 0x34,
-	0x85, 0x29, 0xaf, 0x6b, 0x83, 0xd6, 0xa6, 0xa3, 0x8c, 0x14, 0xb3, 0xea, 0x20, 0xe9, 0x53, 0x18,
-	0x35, 0x75, 0x7a, 0xec, 0x03, 0xa3, 0xa6, 0xce, 0xff, 0x10, 0x98, 0x5e, 0xa3, 0xb5, 0x5c, 0xe0,
-	0x90, 0xba, 0x0b, 0x65, 0xbc, 0xf1, 0x20, 0xe9, 0x6b, 0x48, 0x2c, 0xca, 0x1a, 0x8d, 0xbf, 0xee,
-	0xc9, 0x9b, 0x79, 0x79, 0x78, 0x64, 0x79, 0x75, 0x59, 0xc5, 0x1e, 0x7d, 0x09, 0x33, 0xdb, 0x08,
-	0xc9, 0x5d, 0x67, 0x30, 0x8e, 0x78, 0x00, 0xf4, 0x15, 0x9c, 0x1a, 0xfc, 0xd2, 0xa1, 0x75, 0x6b,
-	0xa9, 0xe4, 0x16, 0xd3, 0x71, 0x46, 0x8a, 0x71, 0x35, 0x8f, 0x70, 0x39, 0xb0, 0xc1, 0x14, 0x67,
-	0x46, 0xd3, 0x24, 0x98, 0x22, 0x0c, 0xa6, 0x73, 0x00, 0x83, 0xba, 0xdd, 0xad, 0x6f, 0x5a, 0x2e,
-	0xd2, 0x24, 0x23, 0xc5, 0x49, 0x35, 0xf3, 0xe4, 0x43, 0xcb, 0x05, 0x3d, 0x83, 0x44, 0xe9, 0xad,
-	0xaa, 0x31, 0x9d, 0x66, 0xa4, 0x38, 0xad, 0xa2, 0xca, 0x13, 0x18, 0xaf, 0x1a, 0x29, 0xfc, 0xa9,
-	0xa4, 0xc8, 0xdf, 0xc3, 0xb3, 0xcf, 0x4a, 0xdd, 0x76, 0x7a, 0xa9, 0x6a, 0xac, 0xc2, 0x2b, 0x86,
-	0x9f, 0x3a, 0x6e, 0x04, 0x3a, 0xbf, 0x82, 0x47, 0x3f, 0x0d, 0xbd, 0xfc, 0x1d, 0xd0, 0xff, 0xa3,
-	0x56, 0x2b, 0x69, 0x91, 0xe6, 0x30, 0xd1, 0x88, 0xc6, 0xa6, 0x24, 0x3b, 0x7e, 0x14, 0x0d, 0xad,
-	0xfc, 0x05, 0x4c, 0x16, 0x3b, 0x87, 0x96, 0x52, 0x18, 0xd7, 0xdc, 0xf1, 0xb8, 0x69, 0x5f, 0x2f,
-	0x3e, 0xfe, 0xda, 0xb3, 0xa3, 0xfb, 0x3d, 0x23, 0x7f, 0xf7, 0x8c, 0x7c, 0xed, 0x19, 0xf9, 0xde,
-	0x33, 0xf2, 0xa3, 0x67, 0xe4, 0x67, 0xcf, 0xc8, 0x7d, 0xcf, 0xc8, 0xb7, 0xdf, 0xec, 0x08, 0xce,
-	0x94, 0x11, 0xa5, 0x46, 0xd3, 0x36, 0xb2, 0x94, 0xaa, 0xb1, 0x18, 0xe6, 0x2c, 0x60, 0x39, 0x88,
-	0xd5, 0x50, 0xaf, 0xc8, 0x26, 0xf1, 0xf0, 0xed, 0xbf, 0x00, 0x00, 0x00, 0xff, 0xff, 0x42, 0xe0,
-	0xee, 0xcc, 0x7e, 0x02, 0x00, 0x00,
+	// 1268 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x9c, 0x56, 0xcd, 0x6e, 0x1b, 0x47,
+	0x12, 0xf6, 0xf0, 0x47, 0x24, 0x8b, 0x43, 0x9a, 0x6e, 0x78, 0x8d, 0x59, 0xad, 0xcd, 0x25, 0xc6,
+	0x06, 0x2c, 0x1b, 0x58, 0x1a, 0xb0, 0x17, 0x81, 0x1d, 0x20, 0x71, 0x24, 0x4b, 0x8a, 0x15, 0x5b,
+	0x06, 0x33, 0x94, 0x0d, 0xe4, 0x44, 0x34, 0x67, 0x4a, 0x54, 0x43, 0xc3, 0xe9, 0x49, 0x4f, 0xd3,
+	0x12, 0x6f, 0xc9, 0x1b, 0xe4, 0x96, 0x57, 0xc8, 0x43, 0x04, 0x39, 0xe7, 0x98, 0x63, 0x8e, 0xb6,
+	0xf2, 0x02, 0x79, 0x84, 0xa0, 0x7f, 0x46, 0x1c, 0x49, 0xb4, 0x63, 0xe7, 0xd6, 0x55, 0xfd, 0x55,
+	0x7d, 0xd5, 0xd5, 0x55, 0xd5, 0x0d, 0x5d, 0x96, 0x48, 0x14, 0x09, 0x8d, 0xef, 0xa5, 0x82, 0x4b,
+	0x3e, 0x9e, 0xed, 0xdf, 0xcb, 0xa4, 0x40, 0x3a, 0xed, 0x6b, 0x99, 0xd4, 0x73, 0xf5, 0xaa, 0x3f,
+	0xe1, 0x13, 0xbe, 0x40, 0x29, 0x49, 0x0b, 0x7a, 0x65, 0xd0, 0xfe, 0x21, 0x94, 0x76, 0x36, 0xc9,
+	0x0d, 0x80, 0x74, 0x36, 0x8e, 0x59, 0x38, 0x3a, 0xc4, 0xb9, 0xe7, 0xf4, 0x9c, 0x35, 0x37, 0x68,
+	0x18, 0xcd, 0x33, 0x9c, 0x13, 0x0f, 0x6a, 0x34, 0x8a, 0x04, 0x66, 0x99, 0x57, 0xea, 0x39, 0x6b,
+	0x8d, 0x20, 0x17, 0x49, 0x1b, 0x4a, 0x2c, 0xf2, 0xca, 0xda, 0xa0, 0xc4, 0x22, 0x72, 0x1d, 0x1a,
+	0xd3, 0x59, 0x2c, 0x99, 0xda, 0xf7, 0x2a, 0x1a, 0xbb, 0x50, 0xf8, 0xbf, 0x54, 0xa0, 0xb6, 0x8b,
+	0x59, 0x46, 0x27, 0xa8, 0x7c, 0x4e, 0xcd, 0xd2, 0xf2, 0xe5, 0x22, 0xb9, 0x05, 0x2b, 0x19, 0x26,
+	0x11, 0x0a, 0x4d, 0xd6, 0xbc, 0xef, 0xf6, 0xf3, 0x23, 0xf4, 0x77, 0x36, 0x03, 0xbb, 0xa7, 0x98,
+	0x32, 0x36, 0x49, 0xa8, 0x9c, 0x09, 0xb4, 0x01, 0x2c, 0x14, 0xe4, 0x26, 0xb4, 0x04, 0x7e, 0x3b,
+	0xc3, 0x4c, 0x8e, 0x12, 0x9e, 0x84, 0xa8, 0x63, 0xa9, 0x04, 0xae, 0x55, 0xbe, 0x50, 0x3a, 0x05,
+	0xb2, 0x9c, 0x16, 0x54, 0x35, 0x20, 0xab, 0x34, 0xa0, 0x1b, 0x00, 0x02, 0xd3, 0x78, 0x3e, 0xda,
+	0x8f, 0xe9, 0xc4, 0x5b, 0xe9, 0x39, 0x6b, 0xf5, 0xa0, 0xa1, 0x35, 0xdb, 0x31, 0x9d, 0x90, 0x6b,
+	0xb0, 0xc2, 0xd3, 0x90, 0x47, 0xe8, 0xd5, 0x7a, 0xce, 0x5a, 0x2b, 0xb0, 0x12, 0xb9, 0x07, 0xae,
+	0xc0, 0x98, 0xce, 0x31, 0x1a, 0xed, 0x0b, 0x3e, 0xf5, 0xea, 0x4b, 0x8e, 0xd2, 0xb4, 0x88, 0x6d,
+	0xc1, 0xa7, 0x64, 0x15, 0xea, 0x99, 0x0a, 0x4e, 0xc5, 0xd1, 0xd0, 0x71, 0x9c, 0xca, 0xe4, 0x29,
+	0xb4, 0xa4, 0xa0, 0x21, 0x8e, 0x42, 0x9e, 0x48, 0x3c, 0x96, 0x1e, 0xf4, 0xca, 0x6b, 0xcd, 0xfb,
+	0x37, 0x17, 0xde, 0x6c, 0x56, 0xfb, 0x7b, 0x0a, 0xf6, 0xc4, 0xa0, 0xb6, 0x12, 0x29, 0xe6, 0x81,
+	0x2b, 0x0b, 0x2a, 0xf2, 0x6f, 0xa8, 0x4f, 0x04, 0x9f, 0xa5, 0x23, 0x16, 0x79, 0x4d, 0x93, 0x76,
+	0x2d, 0xef, 0x44, 0xa4, 0x0b, 0x10, 0xf2, 0x69, 0xaa, 0xae, 0x15, 0x23, 0xcf, 0xd5, 0x07, 0x2d,
+	0x68, 0xd4, 0x85, 0x49, 0x36, 0x45, 0x3e, 0x93, 0x5e, 0xab, 0xe7, 0xac, 0x95, 0x83, 0x5c, 0x24,
+	0xb7, 0xe1, 0xb2, 0x3e, 0xc9, 0x68, 0x71, 0x21, 0x6d, 0xed, 0xbb, 0xad, 0xd5, 0xc3, 0x5c, 0xbb,
+	0xfa, 0x18, 0xae, 0x5c, 0x08, 0x90, 0x74, 0xa0, 0x9c, 0x17, 0x5d, 0x23, 0x50, 0x4b, 0x72, 0x15,
+	0xaa, 0xaf, 0x69, 0x3c, 0x43, 0x5b, 0x6c, 0x46, 0xf8, 0xb4, 0xf4, 0xd0, 0xf1, 0xef, 0x42, 0x65,
+	0xc0, 0x92, 0x09, 0xf1, 0xc1, 0x0d, 0x69, 0x4a, 0xc7, 0x2c, 0x66, 0x92, 0x61, 0xa6, 0x8d, 0x2b,
+	0xc1, 0x19, 0x9d, 0xc6, 0xf2, 0x0f, 0xc4, 0x3e, 0x82, 0x2b, 0xcf, 0x39, 0x3f, 0x9c, 0xa5, 0x2f,
+	0x78, 0x84, 0x81, 0xa9, 0x11, 0x55, 0x87, 0x92, 0x8a, 0x09, 0x4a, 0xcf, 0x59, 0x72, 0x79, 0x76,
+	0xcf, 0x7f, 0x08, 0xa4, 0x68, 0x9a, 0xa5, 0x3c, 0xc9, 0x90, 0xf8, 0x50, 0x4d, 0x11, 0x85, 0x62,
+	0x2b, 0x5f, 0x30, 0x35, 0x5b, 0xfe, 0x7f, 0xa0, 0xba, 0x31, 0x97, 0x98, 0x11, 0x02, 0x95, 0x88,
+	0x4a, 0x6a, 0xfb, 0x40, 0xaf, 0x7d, 0x0e, 0x9d, 0x75, 0xd3, 0x63, 0xbb, 0x6c, 0x22, 0xa8, 0x64,
+	0x3c, 0x21, 0xff, 0x85, 0x66, 0x82, 0x47, 0xa3, 0xbc, 0x15, 0x4d, 0xc6, 0x20, 0xc1, 0x23, 0x8b,
+	0x3c, 0xdb, 0x13, 0xa5, 0xf3, 0x3d, 0x71, 0x1d, 0x1a, 0xea, 0xc6, 0x32, 0x49, 0xa7, 0xa9, 0xee,
+	0x98, 0x4a, 0xb0, 0x50, 0xf8, 0xdf, 0x97, 0xa0, 0xb3, 0x8b, 0xd3, 0x31, 0x8a, 0xec, 0x80, 0xa5,
+	0x2f, 0xd3, 0x88, 0x4a, 0xdd, 0x8a, 0x53, 0xad, 0x5b, 0x9e, 0x02, 0xb3, 0x47, 0x3e, 0x81, 0x6a,
+	0x26, 0xa9, 0x34, 0x94, 0xed, 0xfb, 0xbd, 0x62, 0x59, 0x9e, 0x75, 0xd8, 0x1f, 0x2a, 0x5c, 0x60,
+	0xe0, 0xa4, 0x07, 0x4d, 0x96, 0x84, 0x54, 0x24, 0xfa, 0x78, 0x36, 0xa4, 0xa2, 0x4a, 0xf1, 0x73,
+	0xc1, 0x26, 0x2c, 0xf1, 0x2a, 0xcb, 0xf8, 0xcd, 0xde, 0xd9, 0x63, 0x57, 0xcf, 0x1d, 0xdb, 0xbf,
+	0x03, 0x55, 0xcd, 0x4a, 0x1a, 0x50, 0x5d, 0x7f, 0xbe, 0xf3, 0x6a, 0xab, 0x73, 0x89, 0x34, 0xa1,
+	0x36, 0x7c, 0x39, 0x1c, 0x6c, 0x3d, 0xd9, 0xeb, 0x38, 0xa4, 0x0e, 0x95, 0xcd, 0xad, 0xf5, 0xcd,
+	0x4e, 0xc9, 0xff, 0x02, 0xea, 0xc3, 0x23, 0x36, 0xd5, 0x25, 0xf6, 0x7f, 0xa8, 0xcd, 0x74, 0xcc,
+	0xf9, 0x1d, 0xae, 0xbe, 0xfb, 0x58, 0x41, 0x0e, 0xf5, 0xa7, 0x70, 0x39, 0xf7, 0xf0, 0x51, 0x65,
+	0x54, 0xa4, 0x2b, 0x7d, 0x38, 0xdd, 0x63, 0xa8, 0x29, 0xba, 0xf5, 0xf0, 0xf0, 0x1f, 0xc6, 0xfb,
+	0x0d, 0xb4, 0x36, 0x90, 0x86, 0x3c, 0x19, 0x50, 0x21, 0x19, 0x8d, 0x55, 0xef, 0x09, 0x3e, 0x4b,
+	0x22, 0xdb, 0x26, 0x46, 0x50, 0x5a, 0x96, 0x44, 0x78, 0xac, 0x6f, 0xb8, 0x15, 0x18, 0xe1, 0xfd,
+	0x23, 0xd8, 0xff, 0xd1, 0x81, 0xe6, 0x2b, 0xd5, 0xb9, 0x01, 0x86, 0x5c, 0x44, 0xc5, 0x3e, 0x77,
+	0x97, 0xf4, 0xb9, 0x6b, 0xfb, 0x9c, 0xdc, 0x05, 0xf3, 0xf2, 0x64, 0x07, 0x28, 0xbc, 0xf2, 0x92,
+	0x94, 0x2d, 0xb6, 0xd5, 0xf4, 0xc5, 0xe3, 0x94, 0x89, 0xb9, 0xae, 0x8f, 0x72, 0x60, 0xa5, 0xbf,
+	0xa9, 0x88, 0xcf, 0xc0, 0x1d, 0x4a, 0x2e, 0x4e, 0x1b, 0xfd, 0x7f, 0xb0, 0x22, 0x74, 0x8c, 0xf6,
+	0x86, 0xfe, 0xb5, 0xa0, 0x2b, 0x1c, 0x20, 0xb0, 0x20, 0xff, 0x36, 0xb4, 0xac, 0xb9, 0x6d, 0xf6,
+	0x6b, 0xb0, 0x92, 0x29, 0x85, 0xb1, 0xaf, 0x07, 0x56, 0xf2, 0x6f, 0x41, 0x67, 0x9b, 0x25, 0x91,
+	0xf5, 0x61, 0xb8, 0x2e, 0x64, 0xc1, 0xdf, 0x87, 0x2b, 0x05, 0x94, 0x75, 0xf9, 0x71, 0x21, 0x2d,
+	0xc6, 0x4d, 0xe9, 0xdd, 0xe3, 0xe6, 0x26, 0x34, 0x86, 0xb3, 0x71, 0x16, 0x0a, 0x36, 0xd6, 0x21,
+	0x4b, 0x9e, 0xb2, 0xd0, 0x14, 0x4b, 0x23, 0xb0, 0x92, 0xff, 0x04, 0x6a, 0x03, 0x93, 0x5d, 0xfb,
+	0xb4, 0x3b, 0xa7, 0x4f, 0xfb, 0x55, 0xa8, 0x6a, 0x50, 0x3e, 0x95, 0xb5, 0x70, 0x3a, 0xbb, 0xca,
+	0x85, 0xd9, 0xb5, 0x0b, 0xcd, 0x8d, 0x98, 0xf3, 0xe9, 0x36, 0x8b, 0x25, 0x0a, 0x05, 0x19, 0x33,
+	0x99, 0xe5, 0xe3, 0x4d, 0xad, 0x15, 0xff, 0x01, 0xcd, 0x0e, 0x30, 0xb3, 0x15, 0x65, 0x25, 0x85,
+	0xcd, 0x10, 0x23, 0x3b, 0x0b, 0xf4, 0xda, 0xef, 0x81, 0xbb, 0x27, 0x68, 0x92, 0xd1, 0x50, 0xcd,
+	0x84, 0x4c, 0xa5, 0x50, 0x1e, 0x9b, 0xc0, 0xdd, 0x40, 0x2d, 0xfd, 0xcf, 0xa1, 0xb5, 0x77, 0x3c,
+	0x9c, 0x27, 0x61, 0xe1, 0x46, 0xf7, 0x35, 0xf9, 0xc5, 0xf4, 0x15, 0x22, 0x0b, 0x2c, 0xc8, 0xff,
+	0x1a, 0xda, 0xb9, 0xbd, 0xcd, 0xff, 0x05, 0x8e, 0x82, 0xcb, 0xd2, 0x87, 0xb8, 0x5c, 0x87, 0xcb,
+	0x01, 0xc6, 0x8c, 0x8e, 0x63, 0xcc, 0x7f, 0x3c, 0xc5, 0x17, 0xde, 0x39, 0xf7, 0xc2, 0xe7, 0x69,
+	0x2c, 0x15, 0xd2, 0x78, 0x07, 0x9a, 0xb9, 0x0b, 0xd5, 0xe0, 0xef, 0x31, 0xf7, 0x1f, 0xe9, 0x8c,
+	0x87, 0x87, 0x4f, 0x91, 0x46, 0x26, 0xe3, 0x2a, 0x9f, 0x79, 0xc6, 0xd5, 0x5a, 0x67, 0x1c, 0xd9,
+	0xe4, 0x40, 0x6a, 0x8e, 0x4a, 0x60, 0x25, 0xbf, 0x0b, 0xf5, 0x2f, 0x51, 0x6a, 0xeb, 0x65, 0x76,
+	0xfe, 0x03, 0xa8, 0x9a, 0xcd, 0x85, 0x03, 0xa7, 0xe8, 0x60, 0x69, 0xe8, 0x3f, 0x3b, 0xd0, 0x7a,
+	0x86, 0xf3, 0x00, 0x5f, 0xf3, 0x30, 0x9f, 0xe4, 0x6d, 0x1e, 0x47, 0xa3, 0x0b, 0xbf, 0x4c, 0x97,
+	0xc7, 0xd1, 0xe0, 0xf4, 0xa3, 0x79, 0x0b, 0xda, 0xea, 0x85, 0x2b, 0xa0, 0x8c, 0x57, 0x37, 0xc1,
+	0xa3, 0x05, 0xea, 0xfd, 0x5f, 0xbf, 0x33, 0xcf, 0x5c, 0xe5, 0xdc, 0x33, 0xa7, 0xfe, 0x7c, 0x8a,
+	0xe1, 0xfc, 0x74, 0x50, 0x04, 0xa7, 0xff, 0x94, 0x8d, 0xaf, 0x7e, 0x7f, 0xdb, 0xbd, 0xf4, 0xe6,
+	0x6d, 0xd7, 0xf9, 0xf3, 0x6d, 0xd7, 0xf9, 0xee, 0xa4, 0xeb, 0xfc, 0x74, 0xd2, 0x75, 0x7e, 0x3d,
+	0xe9, 0x3a, 0xbf, 0x9d, 0x74, 0x9d, 0x37, 0x27, 0x5d, 0xe7, 0x87, 0x3f, 0xba, 0x97, 0xe0, 0x1a,
+	0x17, 0x93, 0x7e, 0x8a, 0x22, 0x66, 0x49, 0x3f, 0xe1, 0x2c, 0x43, 0x53, 0x11, 0x1b, 0xf0, 0x42,
+	0x09, 0x03, 0xb5, 0x1e, 0x38, 0xe3, 0x15, 0xad, 0x7c, 0xf0, 0xd7, 0x00, 0xc5, 0xbd, 0x77, 0xc9,
+	0xb7, 0x0b, 0x00, 0x00,
 }
@@ -0,0 +1,82 @@
+package log
+
+import (
+	"io"
+	"sync"
+
+	"github.com/rs/zerolog"
+)
+
+var (
+	outputMutex sync.RWMutex
+	output      io.Writer
+
+	componentsMutex sync.RWMutex
+	componentLevels = make(map[string]zerolog.Level)
+)
+
+// SetOutput sets the output of the global logger and all component loggers.
+func SetOutput(w io.Writer) {
+	outputMutex.Lock()
+	output = w
+	outputMutex.Unlock()
+}
+
+// SetComponentLevel sets the minimum accepted level of logs of a component, e.g. "discovery".
+// The level applies to all loggers of the component, including ones already in use.
+func SetComponentLevel(component string, level zerolog.Level) {
+	componentsMutex.Lock()
+	componentLevels[component] = level
+	componentsMutex.Unlock()
+}
+
+// ComponentLogger returns a logger for a component whose logs carry a `component` field,
+// and are accepted based on the level set for the component through SetComponentLevel.
+// Components without a set level accept logs as per the global logger.
+func ComponentLogger(component string) zerolog.Logger {
+	return logger.Output(&componentWriter{component: component}).With().Str("component", component).Logger()
+}
+
+// componentLevel returns the minimum accepted level of logs of a component, should it be set.
+func componentLevel(component string) (zerolog.Level, bool) {
+	componentsMutex.RLock()
+	level, ok := componentLevels[component]
+	componentsMutex.RUnlock()
+
+	return level, ok
+}
+
+// outputWriter writes logs to the current output of the global logger, such that the
+// output may be replaced while loggers are in use.
+type outputWriter struct{}
+
+func (outputWriter) Write(p []byte) (int, error) {
+	outputMutex.RLock()
+	out := output
+	outputMutex.RUnlock()
+
+	if out == nil {
+		return len(p), nil
+	}
+
+	return out.Write(p)
+}
+
+// componentWriter drops logs of a component below its minimum accepted level, and writes
+// all other logs to the current output of the global logger.
+type componentWriter struct {
+	component string
+}
+
+func (w *componentWriter) Write(p []byte) (int, error) {
+	return outputWriter{}.Write(p)
+}
+
+// WriteLevel implements zerolog.LevelWriter.
+func (w *componentWriter) WriteLevel(level zerolog.Level, p []byte) (int, error) {
+	if min, ok := componentLevel(w.component); ok && level < min {
+		return len(p), nil
+	}
+
+	return w.Write(p)
+}
@@ -10,18 +10,24 @@ import (
 )
 
 var (
-	logger = zerolog.New(os.Stderr).With().Timestamp().Logger()
+	logger = zerolog.New(outputWriter{}).With().Timestamp().Logger()
 )
 
 func init() {
+	output = os.Stderr
+
 	// prettify if terminal is a console
 	if terminal.IsTerminal(int(os.Stdout.Fd())) {
-		logger = logger.Output(zerolog.ConsoleWriter{Out: os.Stderr})
+		SetOutput(zerolog.ConsoleWriter{Out: os.Stderr})
 	}
 }
 
 // Disable disables the noise logger
 func Disable() {
+	outputMutex.Lock()
+	output = nil
+	outputMutex.Unlock()
+
 	logger = zerolog.New(nil).Level(zerolog.Disabled)
 }
 
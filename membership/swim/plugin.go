@@ -0,0 +1,662 @@
+package swim
+
+import (
+	"bytes"
+	"context"
+	"math"
+	"math/rand"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/perlin-network/noise/dht"
+	"github.com/perlin-network/noise/internal/protobuf"
+	"github.com/perlin-network/noise/network"
+	"github.com/perlin-network/noise/network/discovery"
+	"github.com/perlin-network/noise/peer"
+)
+
+const (
+	// DefaultProbeInterval is the default protocol period, within which a random member is probed.
+	DefaultProbeInterval = 1 * time.Second
+	// DefaultProbeTimeout is how long an acknowledgement of a probe is waited for by default.
+	DefaultProbeTimeout = 500 * time.Millisecond
+	// DefaultIndirectProbes is the default number of members asked to probe a member which
+	// failed to acknowledge a direct probe.
+	DefaultIndirectProbes = 3
+	// DefaultSuspicionTimeout is how long a member is suspected of having failed by default
+	// before being declared dead.
+	DefaultSuspicionTimeout = 5 * time.Second
+	// DefaultMaxPiggyback is the default number of membership updates piggybacked onto a message.
+	DefaultMaxPiggyback = 8
+
+	// retransmitMultiplier scales the number of times a membership update is piggybacked,
+	// which is retransmitMultiplier * log2(members + 1).
+	retransmitMultiplier = 3
+)
+
+// State is the state of a member of the cluster.
+type State int
+
+const (
+	// StateAlive denotes a member which acknowledges probes.
+	StateAlive State = iota
+	// StateSuspect denotes a member which failed to acknowledge a probe, either directly or
+	// through any other member, and has yet to refute the suspicion.
+	StateSuspect
+	// StateDead denotes a member which has been suspected for longer than the suspicion timeout.
+	StateDead
+)
+
+func (s State) String() string {
+	switch s {
+	case StateAlive:
+		return "alive"
+	case StateSuspect:
+		return "suspect"
+	case StateDead:
+		return "dead"
+	default:
+		return "unknown"
+	}
+}
+
+// Member is a peer known to be part of the cluster.
+type Member struct {
+	ID          peer.ID
+	State       State
+	Incarnation uint64
+}
+
+type member struct {
+	Member
+
+	suspectedAt time.Time
+}
+
+type update struct {
+	msg       *protobuf.MembershipUpdate
+	transmits int
+}
+
+// Plugin implements the SWIM failure detector and membership protocol. Every protocol period
+// a random member is probed. Should it fail to acknowledge the probe, a number of other members
+// are asked to probe it on the nodes behalf, after which it is suspected of having failed. Members
+// which do not refute the suspicion within the suspicion timeout are declared dead.
+//
+// Membership updates are disseminated by piggybacking them onto probes and acknowledgements.
+// Every update is signed by its origin, being either the member it is about or the member
+// which probed it, and is disseminated further as signed. Members are added to and removed
+// from a routing table as they join and fail.
+type Plugin struct {
+	*network.Plugin
+
+	// ProbeInterval is the protocol period (default: DefaultProbeInterval).
+	ProbeInterval time.Duration
+	// ProbeTimeout is how long an acknowledgement of a probe is waited for (default: DefaultProbeTimeout).
+	ProbeTimeout time.Duration
+	// IndirectProbes is the number of members asked to probe a member which failed to
+	// acknowledge a direct probe (default: DefaultIndirectProbes).
+	IndirectProbes int
+	// SuspicionTimeout is how long a member is suspected before being declared dead
+	// (default: DefaultSuspicionTimeout).
+	SuspicionTimeout time.Duration
+	// MaxPiggyback is the maximum number of membership updates piggybacked onto a message
+	// (default: DefaultMaxPiggyback).
+	MaxPiggyback int
+
+	// Routes is the routing table members are seeded from, added to and removed from. The
+	// routing table of the discovery plugin is used should none be set.
+	Routes *dht.RoutingTable
+
+	net *network.Network
+
+	mutex       sync.Mutex
+	incarnation uint64
+	members     map[string]*member
+	updates     []*update
+
+	stop chan struct{}
+}
+
+var (
+	PluginID                         = (*Plugin)(nil)
+	_        network.PluginInterface = (*Plugin)(nil)
+)
+
+func (state *Plugin) Startup(net *network.Network) {
+	state.net = net
+	state.members = make(map[string]*member)
+
+	if state.Routes == nil {
+		if plugin, ok := net.Plugin(discovery.PluginID); ok {
+			state.Routes = plugin.(*discovery.Plugin).Routes
+		}
+	}
+
+	state.stop = make(chan struct{})
+	go state.probeLoop(state.stop)
+}
+
+func (state *Plugin) Cleanup(net *network.Network) {
+	if state.stop != nil {
+		close(state.stop)
+		state.stop = nil
+	}
+}
+
+func (state *Plugin) Receive(ctx *network.PluginContext) error {
+	// Peers which send messages are evidently alive.
+	state.join(ctx.Sender())
+
+	gCtx := network.WithSignMessage(context.Background(), true)
+
+	switch msg := ctx.Message().(type) {
+	case *protobuf.SwimPing:
+		state.apply(msg.Updates)
+
+		return ctx.Reply(gCtx, &protobuf.SwimAck{Updates: state.piggyback()})
+	case *protobuf.SwimPingRequest:
+		state.apply(msg.Updates)
+
+		if msg.Target == nil || !state.ping(peer.ID(*msg.Target)) {
+			// Only acknowledgements are relayed back, such that the sender times out otherwise.
+			return nil
+		}
+
+		return ctx.Reply(gCtx, &protobuf.SwimAck{Updates: state.piggyback()})
+	}
+
+	return nil
+}
+
+// Members returns every member known to the node, including those which have been
+// declared dead, sorted by address.
+func (state *Plugin) Members() []Member {
+	state.mutex.Lock()
+	members := make([]Member, 0, len(state.members))
+	for _, m := range state.members {
+		members = append(members, m.Member)
+	}
+	state.mutex.Unlock()
+
+	sort.Slice(members, func(i, j int) bool {
+		return members[i].ID.Address < members[j].ID.Address
+	})
+
+	return members
+}
+
+// Member returns the membership state of a peer.
+func (state *Plugin) Member(id peer.ID) (Member, bool) {
+	state.mutex.Lock()
+	defer state.mutex.Unlock()
+
+	m, ok := state.members[string(id.Id)]
+	if !ok {
+		return Member{}, false
+	}
+	return m.Member, true
+}
+
+// probeLoop probes a random member every protocol period, as timed by the networks clock.
+func (state *Plugin) probeLoop(stop chan struct{}) {
+	for {
+		select {
+		case <-stop:
+			return
+		case <-state.net.Clock().After(state.probeInterval()):
+		}
+
+		state.seed()
+		state.expireSuspects()
+
+		if target, ok := state.randomMember(nil); ok {
+			state.probe(target)
+		}
+	}
+}
+
+// probe probes a member directly, and indirectly through other members should it fail to
+// acknowledge the direct probe. The member is suspected should both fail.
+func (state *Plugin) probe(target peer.ID) {
+	if state.ping(target) {
+		return
+	}
+
+	acked := make(chan struct{}, 1)
+
+	var wg sync.WaitGroup
+
+	excluded := []peer.ID{target}
+	for i := 0; i < state.indirectProbes(); i++ {
+		witness, ok := state.randomMember(excluded)
+		if !ok {
+			break
+		}
+		excluded = append(excluded, witness)
+
+		wg.Add(1)
+		go func(witness peer.ID) {
+			defer wg.Done()
+
+			if state.pingRequest(witness, target) {
+				select {
+				case acked <- struct{}{}:
+				default:
+				}
+			}
+		}(witness)
+	}
+
+	wg.Wait()
+
+	select {
+	case <-acked:
+	default:
+		state.suspect(target)
+	}
+}
+
+// ping sends a probe to a member, and returns true if it was acknowledged in time.
+func (state *Plugin) ping(target peer.ID) bool {
+	client, err := state.net.Client(target.Address)
+	if err != nil {
+		return false
+	}
+
+	ctx, cancel := context.WithTimeout(network.WithSignMessage(context.Background(), true), state.probeTimeout())
+	defer cancel()
+
+	res, err := client.Request(ctx, &protobuf.SwimPing{Updates: state.piggyback()})
+	if err != nil {
+		return false
+	}
+
+	if ack, ok := res.(*protobuf.SwimAck); ok {
+		state.apply(ack.Updates)
+	}
+
+	return true
+}
+
+// pingRequest asks a witness to probe a member on the nodes behalf, and returns true if
+// the witness relayed an acknowledgement in time.
+func (state *Plugin) pingRequest(witness peer.ID, target peer.ID) bool {
+	client, err := state.net.Client(witness.Address)
+	if err != nil {
+		return false
+	}
+
+	// The witness waits up to a probe timeout for the target to acknowledge its probe.
+	ctx, cancel := context.WithTimeout(network.WithSignMessage(context.Background(), true), 2*state.probeTimeout())
+	defer cancel()
+
+	id := protobuf.ID(target)
+
+	res, err := client.Request(ctx, &protobuf.SwimPingRequest{Target: &id, Updates: state.piggyback()})
+	if err != nil {
+		return false
+	}
+
+	if ack, ok := res.(*protobuf.SwimAck); ok {
+		state.apply(ack.Updates)
+	}
+
+	return true
+}
+
+// seed adds the peers of the routing table which are not yet known as members.
+func (state *Plugin) seed() {
+	if state.Routes == nil {
+		return
+	}
+
+	for _, id := range state.Routes.GetPeers() {
+		state.join(id)
+	}
+}
+
+// join adds a peer as an alive member should it not be known yet.
+func (state *Plugin) join(id peer.ID) {
+	if id.Equals(state.net.SelfID()) {
+		return
+	}
+
+	state.mutex.Lock()
+	defer state.mutex.Unlock()
+
+	if _, exists := state.members[string(id.Id)]; exists {
+		return
+	}
+
+	state.members[string(id.Id)] = &member{Member: Member{ID: id, State: StateAlive}}
+	state.enqueue(id, StateAlive, 0)
+
+	if state.Routes != nil {
+		state.Routes.Update(id)
+	}
+}
+
+// suspect marks an alive member as suspected of having failed.
+func (state *Plugin) suspect(id peer.ID) {
+	state.mutex.Lock()
+	defer state.mutex.Unlock()
+
+	m, exists := state.members[string(id.Id)]
+	if !exists || m.State != StateAlive {
+		return
+	}
+
+	m.State = StateSuspect
+	m.suspectedAt = state.net.Clock().Now()
+	state.enqueue(m.ID, StateSuspect, m.Incarnation)
+
+	logger := state.net.PluginLogger(state)
+	logger.Debug().Str("peer_address", m.ID.Address).Msg("Suspecting member of having failed.")
+}
+
+// expireSuspects declares members which have been suspected for longer than the
+// suspicion timeout as dead.
+func (state *Plugin) expireSuspects() {
+	now := state.net.Clock().Now()
+
+	state.mutex.Lock()
+	defer state.mutex.Unlock()
+
+	for _, m := range state.members {
+		if m.State == StateSuspect && now.Sub(m.suspectedAt) >= state.suspicionTimeout() {
+			state.declareDead(m)
+			state.enqueue(m.ID, StateDead, m.Incarnation)
+		}
+	}
+}
+
+// declareDead marks a member as dead and removes it from the routing table. The mutex
+// must be held by the caller.
+func (state *Plugin) declareDead(m *member) {
+	m.State = StateDead
+
+	if state.Routes != nil {
+		state.Routes.RemovePeer(m.ID)
+	}
+
+	logger := state.net.PluginLogger(state)
+	logger.Info().Str("peer_address", m.ID.Address).Msg("Member has failed.")
+}
+
+// apply applies membership updates disseminated by another member, following the
+// precedence rules of SWIM. Updates which change the state of a member are disseminated further.
+func (state *Plugin) apply(updates []*protobuf.MembershipUpdate) {
+	state.mutex.Lock()
+	defer state.mutex.Unlock()
+
+	for _, u := range updates {
+		if u.Member == nil {
+			continue
+		}
+
+		id := peer.ID(*u.Member)
+		status := fromProto(u.State)
+
+		if !state.verify(u) {
+			logger := state.net.PluginLogger(state)
+			logger.Debug().Str("peer_address", id.Address).Msg("Dropping membership update which was not signed by the member or its prober.")
+			continue
+		}
+
+		// Refute suspicion of this node having failed with a greater incarnation.
+		if id.Equals(state.net.SelfID()) {
+			if status != StateAlive && u.Incarnation >= state.incarnation {
+				state.incarnation = u.Incarnation + 1
+				state.enqueue(state.net.SelfID(), StateAlive, state.incarnation)
+			}
+			continue
+		}
+
+		m, exists := state.members[string(id.Id)]
+		if !exists {
+			m = &member{Member: Member{ID: id, State: status, Incarnation: u.Incarnation}}
+			state.members[string(id.Id)] = m
+
+			switch status {
+			case StateAlive:
+				if state.Routes != nil {
+					state.Routes.Update(id)
+				}
+			case StateSuspect:
+				m.suspectedAt = state.net.Clock().Now()
+			case StateDead:
+				state.declareDead(m)
+			}
+
+			state.forward(u)
+			continue
+		}
+
+		changed := false
+
+		switch status {
+		case StateAlive:
+			// Dead members may only rejoin with a greater incarnation.
+			if u.Incarnation > m.Incarnation {
+				m.State, m.Incarnation, changed = StateAlive, u.Incarnation, true
+
+				if state.Routes != nil {
+					state.Routes.Update(m.ID)
+				}
+			}
+		case StateSuspect:
+			if (m.State == StateAlive && u.Incarnation >= m.Incarnation) || (m.State == StateSuspect && u.Incarnation > m.Incarnation) {
+				if m.State == StateAlive {
+					m.suspectedAt = state.net.Clock().Now()
+				}
+				m.State, m.Incarnation, changed = StateSuspect, u.Incarnation, true
+			}
+		case StateDead:
+			if m.State != StateDead {
+				m.Incarnation, changed = u.Incarnation, true
+				state.declareDead(m)
+			}
+		}
+
+		if changed {
+			state.forward(u)
+		}
+	}
+}
+
+// verify checks that a membership update was signed by its origin, and that its origin is
+// either the member the update is about, or a member not declared dead which may have
+// probed it. Only members themselves may refute suspicion with a greater incarnation. The
+// mutex must be held by the caller.
+func (state *Plugin) verify(u *protobuf.MembershipUpdate) bool {
+	if u.Origin == nil || !validID(u.Member) || !validID(u.Origin) {
+		return false
+	}
+
+	payload, err := updatePayload(u)
+	if err != nil || !state.net.Verify(u.Origin.PublicKey, payload, u.Signature) {
+		return false
+	}
+
+	if bytes.Equal(u.Origin.PublicKey, u.Member.PublicKey) {
+		return true
+	}
+
+	if fromProto(u.State) == StateAlive && u.Incarnation > 0 {
+		return false
+	}
+
+	prober, exists := state.members[string(u.Origin.Id)]
+	return exists && prober.State != StateDead
+}
+
+// validID returns true should the hash of an ID match its public key.
+func validID(id *protobuf.ID) bool {
+	return id != nil && bytes.Equal(id.Id, peer.CreateID(id.Address, id.PublicKey).Id)
+}
+
+// updatePayload returns the bytes a membership update is signed over, which is the update
+// serialized without its signature.
+func updatePayload(u *protobuf.MembershipUpdate) ([]byte, error) {
+	unsigned := *u
+	unsigned.Signature = nil
+
+	return unsigned.Marshal()
+}
+
+// enqueue signs a membership update originating from this node, and queues it to be
+// piggybacked onto outgoing messages. The mutex must be held by the caller.
+func (state *Plugin) enqueue(id peer.ID, status State, incarnation uint64) {
+	pid, origin := protobuf.ID(id), protobuf.ID(state.net.SelfID())
+	msg := &protobuf.MembershipUpdate{Member: &pid, State: toProto(status), Incarnation: incarnation, Origin: &origin}
+
+	payload, err := updatePayload(msg)
+	if err == nil {
+		msg.Signature, err = state.net.Sign(payload)
+	}
+	if err != nil {
+		logger := state.net.PluginLogger(state)
+		logger.Warn().Err(err).Msg("Failed to sign membership update.")
+		return
+	}
+
+	state.forward(msg)
+}
+
+// forward queues a signed membership update to be piggybacked onto outgoing messages,
+// replacing any queued update of the same member. The mutex must be held by the caller.
+func (state *Plugin) forward(msg *protobuf.MembershipUpdate) {
+	pid := msg.Member
+
+	for i, queued := range state.updates {
+		if bytes.Equal(queued.msg.Member.Id, pid.Id) {
+			state.updates[i] = &update{msg: msg}
+			return
+		}
+	}
+
+	state.updates = append(state.updates, &update{msg: msg})
+}
+
+// piggyback returns the membership updates to be piggybacked onto an outgoing message,
+// preferring updates which have been transmitted the least. Updates are dropped once
+// they have been transmitted enough times to have likely reached every member.
+func (state *Plugin) piggyback() []*protobuf.MembershipUpdate {
+	state.mutex.Lock()
+	defer state.mutex.Unlock()
+
+	sort.SliceStable(state.updates, func(i, j int) bool {
+		return state.updates[i].transmits < state.updates[j].transmits
+	})
+
+	limit := retransmitMultiplier * int(math.Ceil(math.Log2(float64(len(state.members)+1))))
+
+	var updates []*protobuf.MembershipUpdate
+
+	for _, u := range state.updates {
+		if len(updates) == state.maxPiggyback() {
+			break
+		}
+		updates = append(updates, u.msg)
+		u.transmits++
+	}
+
+	remaining := state.updates[:0]
+	for _, u := range state.updates {
+		if u.transmits < limit {
+			remaining = append(remaining, u)
+		}
+	}
+	state.updates = remaining
+
+	return updates
+}
+
+// randomMember returns a random member which has not been declared dead, excluding a
+// list of peers.
+func (state *Plugin) randomMember(excluded []peer.ID) (peer.ID, bool) {
+	state.mutex.Lock()
+	defer state.mutex.Unlock()
+
+	var candidates []peer.ID
+
+next:
+	for _, m := range state.members {
+		if m.State == StateDead {
+			continue
+		}
+
+		for _, id := range excluded {
+			if id.Equals(m.ID) {
+				continue next
+			}
+		}
+
+		candidates = append(candidates, m.ID)
+	}
+
+	if len(candidates) == 0 {
+		return peer.ID{}, false
+	}
+
+	return candidates[rand.Intn(len(candidates))], true
+}
+
+func (state *Plugin) probeInterval() time.Duration {
+	if state.ProbeInterval > 0 {
+		return state.ProbeInterval
+	}
+	return DefaultProbeInterval
+}
+
+func (state *Plugin) probeTimeout() time.Duration {
+	if state.ProbeTimeout > 0 {
+		return state.ProbeTimeout
+	}
+	return DefaultProbeTimeout
+}
+
+func (state *Plugin) indirectProbes() int {
+	if state.IndirectProbes > 0 {
+		return state.IndirectProbes
+	}
+	return DefaultIndirectProbes
+}
+
+func (state *Plugin) suspicionTimeout() time.Duration {
+	if state.SuspicionTimeout > 0 {
+		return state.SuspicionTimeout
+	}
+	return DefaultSuspicionTimeout
+}
+
+func (state *Plugin) maxPiggyback() int {
+	if state.MaxPiggyback > 0 {
+		return state.MaxPiggyback
+	}
+	return DefaultMaxPiggyback
+}
+
+func toProto(status State) protobuf.MembershipUpdate_State {
+	switch status {
+	case StateSuspect:
+		return protobuf.MembershipUpdate_SUSPECT
+	case StateDead:
+		return protobuf.MembershipUpdate_DEAD
+	default:
+		return protobuf.MembershipUpdate_ALIVE
+	}
+}
+
+func fromProto(status protobuf.MembershipUpdate_State) State {
+	switch status {
+	case protobuf.MembershipUpdate_SUSPECT:
+		return StateSuspect
+	case protobuf.MembershipUpdate_DEAD:
+		return StateDead
+	default:
+		return StateAlive
+	}
+}
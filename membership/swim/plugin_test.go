@@ -0,0 +1,172 @@
+package swim_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/perlin-network/noise/crypto"
+	"github.com/perlin-network/noise/crypto/blake2b"
+	"github.com/perlin-network/noise/crypto/ed25519"
+	"github.com/perlin-network/noise/internal/protobuf"
+	"github.com/perlin-network/noise/membership/swim"
+	"github.com/perlin-network/noise/network"
+	"github.com/perlin-network/noise/peer"
+	"github.com/perlin-network/noise/testutil"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFailureDetection(t *testing.T) {
+	t.Parallel()
+
+	const (
+		size             = 10
+		suspicionTimeout = 2 * time.Second
+	)
+
+	plugins := make([]*swim.Plugin, size)
+
+	// Writes are flushed promptly, such that probes are acknowledged well within the probe timeout.
+	opts := testutil.WithBuilderOptions(network.WriteFlushLatency(time.Millisecond))
+
+	cluster, err := testutil.NewCluster(size, opts, testutil.WithSetup(func(i int, builder *network.Builder) {
+		plugins[i] = &swim.Plugin{
+			ProbeInterval:    100 * time.Millisecond,
+			ProbeTimeout:     50 * time.Millisecond,
+			SuspicionTimeout: suspicionTimeout,
+		}
+		builder.AddPlugin(plugins[i])
+	}))
+	assert.Nil(t, err)
+	defer cluster.Stop()
+
+	// Every node learns of every other node through the routing table and dissemination.
+	deadline := time.Now().Add(10 * time.Second)
+	for i := 0; i < size; i++ {
+		for countAlive(plugins[i]) < size-1 {
+			if time.Now().After(deadline) {
+				t.Fatalf("expected node %d to know of %d alive members, got %d", i, size-1, countAlive(plugins[i]))
+			}
+			time.Sleep(50 * time.Millisecond)
+		}
+	}
+
+	failed := cluster.Node(size - 1).ID
+	cluster.Remove(size - 1)
+
+	state := func(i int) swim.State {
+		member, ok := plugins[i].Member(failed)
+		assert.True(t, ok)
+		return member.State
+	}
+
+	// Every remaining node suspects the failed node within the suspicion timeout.
+	deadline = time.Now().Add(suspicionTimeout)
+	for i := 0; i < size-1; i++ {
+		for state(i) == swim.StateAlive {
+			if time.Now().After(deadline) {
+				t.Fatalf("expected node %d to detect the failure within %s", i, suspicionTimeout)
+			}
+			time.Sleep(20 * time.Millisecond)
+		}
+	}
+
+	// The failed node is declared dead once the suspicion times out, as it never refutes it.
+	deadline = time.Now().Add(2 * suspicionTimeout)
+	for i := 0; i < size-1; i++ {
+		for state(i) != swim.StateDead {
+			if time.Now().After(deadline) {
+				t.Fatalf("expected node %d to declare the failed node dead, got %s", i, state(i))
+			}
+			time.Sleep(20 * time.Millisecond)
+		}
+
+		assert.False(t, cluster.Plugin(i).Routes.PeerExists(failed), "expected node %d to remove the failed node from its routing table", i)
+		assert.Equal(t, size-2, countAlive(plugins[i]))
+	}
+}
+
+func TestForgedMembershipUpdates(t *testing.T) {
+	t.Parallel()
+
+	const size = 3
+
+	plugins := make([]*swim.Plugin, size)
+
+	cluster, err := testutil.NewCluster(size, testutil.WithSetup(func(i int, builder *network.Builder) {
+		// Members are only probed by the test itself.
+		plugins[i] = &swim.Plugin{ProbeInterval: time.Hour}
+		builder.AddPlugin(plugins[i])
+	}))
+	assert.Nil(t, err)
+	defer cluster.Stop()
+
+	victim, attacker := cluster.Node(1).ID, cluster.Node(2)
+
+	sign := func(keys *crypto.KeyPair, origin peer.ID, state protobuf.MembershipUpdate_State, incarnation uint64) *protobuf.MembershipUpdate {
+		member, signer := protobuf.ID(victim), protobuf.ID(origin)
+		update := &protobuf.MembershipUpdate{Member: &member, State: state, Incarnation: incarnation, Origin: &signer}
+
+		payload, err := update.Marshal()
+		assert.Nil(t, err)
+
+		update.Signature, err = keys.Sign(ed25519.New(), blake2b.New(), payload)
+		assert.Nil(t, err)
+
+		return update
+	}
+
+	outsider := ed25519.RandomKeyPair()
+	outsiderID := peer.CreateID("tcp://127.0.0.1:1", outsider.PublicKey)
+
+	updates := []*protobuf.MembershipUpdate{
+		// Unsigned.
+		{Member: func() *protobuf.ID { id := protobuf.ID(victim); return &id }(), State: protobuf.MembershipUpdate_DEAD},
+		// Signed by a peer which is not a member.
+		sign(outsider, outsiderID, protobuf.MembershipUpdate_DEAD, 0),
+		// Signed by the attacker on behalf of the victim.
+		sign(attacker.GetKeys(), victim, protobuf.MembershipUpdate_DEAD, 0),
+	}
+
+	client, err := attacker.Client(cluster.Node(0).Address)
+	assert.Nil(t, err)
+
+	for _, update := range updates {
+		_, err := client.Request(context.Background(), &protobuf.SwimPing{Updates: []*protobuf.MembershipUpdate{update}})
+		assert.Nil(t, err)
+
+		member, ok := plugins[0].Member(victim)
+		if ok {
+			assert.Equal(t, swim.StateAlive, member.State, "expected forged membership updates to be dropped")
+		}
+	}
+
+	// Members which probed the victim may suspect it, but not refute the suspicion on its behalf.
+	_, err = client.Request(context.Background(), &protobuf.SwimPing{Updates: []*protobuf.MembershipUpdate{
+		sign(attacker.GetKeys(), attacker.ID, protobuf.MembershipUpdate_SUSPECT, 0),
+	}})
+	assert.Nil(t, err)
+
+	member, ok := plugins[0].Member(victim)
+	assert.True(t, ok)
+	assert.Equal(t, swim.StateSuspect, member.State)
+
+	_, err = client.Request(context.Background(), &protobuf.SwimPing{Updates: []*protobuf.MembershipUpdate{
+		sign(attacker.GetKeys(), attacker.ID, protobuf.MembershipUpdate_ALIVE, 1),
+	}})
+	assert.Nil(t, err)
+
+	member, _ = plugins[0].Member(victim)
+	assert.Equal(t, swim.StateSuspect, member.State, "expected only the member itself to refute suspicion")
+}
+
+func countAlive(plugin *swim.Plugin) int {
+	alive := 0
+	for _, member := range plugin.Members() {
+		if member.State == swim.StateAlive {
+			alive++
+		}
+	}
+	return alive
+}
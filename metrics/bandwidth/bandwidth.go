@@ -0,0 +1,146 @@
+// Package bandwidth reports the number of bytes transferred to and from each peer of a
+// network over a sliding window of time.
+package bandwidth
+
+import (
+	"sync"
+	"time"
+
+	"github.com/perlin-network/noise/network"
+)
+
+// slotsPerWindow is the number of slots a window is divided into. The window slides a
+// slot at a time, and peak rates are measured over a single slot.
+const slotsPerWindow = 10
+
+// BandwidthStats is the bandwidth used transferring messages to and from a peer.
+type BandwidthStats struct {
+	// BytesSentInWindow is the number of bytes written to the peer within the window.
+	BytesSentInWindow uint64
+
+	// BytesReceivedInWindow is the number of bytes read from the peer within the window.
+	BytesReceivedInWindow uint64
+
+	// PeakRateSent is the highest rate in bytes per second at which bytes were written
+	// to the peer within the window.
+	PeakRateSent float64
+
+	// PeakRateReceived is the highest rate in bytes per second at which bytes were read
+	// from the peer within the window.
+	PeakRateReceived float64
+}
+
+// slot counts bytes transferred within one slot of a window.
+type slot struct {
+	epoch    int64
+	sent     uint64
+	received uint64
+}
+
+// Monitor counts the bytes transferred to and from each peer of a network over a sliding window.
+type Monitor struct {
+	*network.Observer
+
+	// slotSize is the duration of a single slot of the window bandwidth is reported over.
+	slotSize time.Duration
+
+	mutex sync.Mutex
+	peers map[string][]slot
+
+	now func() time.Time
+}
+
+// NewMonitor creates a monitor reporting the bandwidth used over a window of time, and
+// registers it as an observer of a network.
+func NewMonitor(net *network.Network, windowSize time.Duration) *Monitor {
+	m := &Monitor{
+		slotSize: windowSize / slotsPerWindow,
+		peers:    make(map[string][]slot),
+		now:      time.Now,
+	}
+
+	if m.slotSize <= 0 {
+		m.slotSize = 1
+	}
+
+	net.AddObserver(m)
+
+	return m
+}
+
+// MessageSent records the bytes of a message written to a peer.
+func (m *Monitor) MessageSent(address string, size int) {
+	m.record(address, size, true)
+}
+
+// MessageReceived records the bytes of a message read from a peer.
+func (m *Monitor) MessageReceived(address string, size int) {
+	m.record(address, size, false)
+}
+
+func (m *Monitor) record(address string, size int, sent bool) {
+	epoch := m.now().UnixNano() / int64(m.slotSize)
+
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	slots, exists := m.peers[address]
+	if !exists {
+		slots = make([]slot, slotsPerWindow)
+		m.peers[address] = slots
+	}
+
+	s := &slots[epoch%slotsPerWindow]
+	if s.epoch != epoch {
+		*s = slot{epoch: epoch}
+	}
+
+	if sent {
+		s.sent += uint64(size)
+	} else {
+		s.received += uint64(size)
+	}
+}
+
+// Report returns the bandwidth used transferring messages to and from every peer within
+// the window, keyed by address. Peers which transferred no messages within the window are
+// omitted.
+func (m *Monitor) Report() map[string]BandwidthStats {
+	epoch := m.now().UnixNano() / int64(m.slotSize)
+	seconds := m.slotSize.Seconds()
+
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	report := make(map[string]BandwidthStats)
+
+	for address, slots := range m.peers {
+		var stats BandwidthStats
+
+		for _, s := range slots {
+			if epoch-s.epoch >= slotsPerWindow {
+				continue
+			}
+
+			stats.BytesSentInWindow += s.sent
+			stats.BytesReceivedInWindow += s.received
+
+			if rate := float64(s.sent) / seconds; rate > stats.PeakRateSent {
+				stats.PeakRateSent = rate
+			}
+			if rate := float64(s.received) / seconds; rate > stats.PeakRateReceived {
+				stats.PeakRateReceived = rate
+			}
+		}
+
+		if stats.BytesSentInWindow == 0 && stats.BytesReceivedInWindow == 0 {
+			// Forget peers which have been idle for the entire window.
+			delete(m.peers, address)
+			continue
+		}
+
+		report[address] = stats
+	}
+
+	return report
+}
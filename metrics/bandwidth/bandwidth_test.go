@@ -0,0 +1,100 @@
+package bandwidth
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/perlin-network/noise/internal/protobuf"
+	"github.com/perlin-network/noise/network"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestReport(t *testing.T) {
+	t.Parallel()
+
+	var nodes []*network.Network
+	var monitors []*Monitor
+
+	for i := 0; i < 2; i++ {
+		builder := network.NewBuilder()
+		builder.SetAddress(network.FormatAddress("tcp", "localhost", uint16(network.GetRandomUnusedPort())))
+
+		node, err := builder.Build()
+		assert.Nil(t, err)
+
+		go node.Listen()
+		defer node.Close()
+
+		node.BlockUntilListening()
+
+		nodes = append(nodes, node)
+		monitors = append(monitors, NewMonitor(node, time.Second))
+	}
+
+	sender, receiver := nodes[0], nodes[1]
+
+	_, err := sender.Client(receiver.Address)
+	assert.Nil(t, err)
+
+	expected := uint64(0)
+
+	for i := 0; i < 10; i++ {
+		msg, err := sender.PrepareMessage(context.Background(), &protobuf.Ping{})
+		assert.Nil(t, err)
+
+		assert.Nil(t, sender.Write(receiver.Address, msg))
+
+		// Messages are assigned a nonce upon being written.
+		expected += uint64(msg.Size() + 4)
+	}
+
+	deadline := time.Now().Add(500 * time.Millisecond)
+	for monitors[1].Report()[sender.Address].BytesReceivedInWindow < expected && time.Now().Before(deadline) {
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	sent := monitors[0].Report()[receiver.Address]
+	assert.Equal(t, expected, sent.BytesSentInWindow)
+	assert.Zero(t, sent.BytesReceivedInWindow)
+	assert.True(t, sent.PeakRateSent >= float64(expected), "expected peak rate of at least %d B/s, got %f", expected, sent.PeakRateSent)
+
+	received := monitors[1].Report()[sender.Address]
+	assert.Equal(t, expected, received.BytesReceivedInWindow)
+	assert.Zero(t, received.BytesSentInWindow)
+	assert.True(t, received.PeakRateReceived > 0)
+
+	// Bytes transferred should no longer be reported once the window has passed.
+	time.Sleep(time.Second)
+
+	assert.Empty(t, monitors[0].Report())
+	assert.Empty(t, monitors[1].Report())
+}
+
+func TestReportSlidingWindow(t *testing.T) {
+	t.Parallel()
+
+	now := time.Unix(0, 0)
+
+	m := &Monitor{
+		slotSize: time.Second / slotsPerWindow,
+		peers:    make(map[string][]slot),
+		now:      func() time.Time { return now },
+	}
+
+	m.MessageSent("tcp://127.0.0.1:3000", 100)
+
+	now = now.Add(500 * time.Millisecond)
+	m.MessageSent("tcp://127.0.0.1:3000", 50)
+
+	stats := m.Report()["tcp://127.0.0.1:3000"]
+	assert.Equal(t, uint64(150), stats.BytesSentInWindow)
+	assert.Equal(t, float64(1000), stats.PeakRateSent)
+
+	now = now.Add(700 * time.Millisecond)
+
+	stats = m.Report()["tcp://127.0.0.1:3000"]
+	assert.Equal(t, uint64(50), stats.BytesSentInWindow)
+	assert.Equal(t, float64(500), stats.PeakRateSent)
+}
@@ -0,0 +1,143 @@
+// Package prometheus exports statistics of a network and its routing table as Prometheus metrics.
+package prometheus
+
+import (
+	"context"
+	"strconv"
+	"time"
+
+	"github.com/perlin-network/noise/dht"
+	"github.com/perlin-network/noise/network"
+
+	"github.com/pkg/errors"
+	prom "github.com/prometheus/client_golang/prometheus"
+)
+
+const namespace = "noise"
+
+// RegisterMetrics registers metrics of a network and its routing table with a Prometheus registerer:
+//
+//	noise_routing_table_peers{bucket}  gauge of peers held within each non-empty bucket
+//	noise_messages_sent_total          counter of messages written to peers
+//	noise_messages_received_total      counter of messages read from peers
+//	noise_connections_active           gauge of connected peers
+//	noise_message_size_bytes           histogram of the size of messages sent and received
+//	noise_rpc_duration_seconds         histogram of the duration of requests sent to peers
+//
+// Metrics of the routing table are not registered should it be nil.
+func RegisterMetrics(reg prom.Registerer, rt *dht.RoutingTable, net *network.Network) error {
+	o := newObserver()
+
+	collectors := []prom.Collector{
+		o.messagesSent,
+		o.messagesReceived,
+		o.messageSize,
+		o.rpcDuration,
+		prom.NewGaugeFunc(prom.GaugeOpts{
+			Namespace: namespace,
+			Name:      "connections_active",
+			Help:      "Number of peers connected to the network.",
+		}, func() float64 {
+			return float64(connectedPeers(net))
+		}),
+	}
+
+	if rt != nil {
+		collectors = append(collectors, &routingTableCollector{table: rt})
+	}
+
+	for _, collector := range collectors {
+		if err := reg.Register(collector); err != nil {
+			return errors.Wrap(err, "prometheus: failed to register metrics")
+		}
+	}
+
+	net.AddObserver(o)
+
+	return nil
+}
+
+// observer records traffic flowing through a network as metrics.
+type observer struct {
+	*network.Observer
+
+	messagesSent     prom.Counter
+	messagesReceived prom.Counter
+	messageSize      *prom.HistogramVec
+	rpcDuration      prom.Histogram
+}
+
+func newObserver() *observer {
+	return &observer{
+		messagesSent: prom.NewCounter(prom.CounterOpts{
+			Namespace: namespace,
+			Name:      "messages_sent_total",
+			Help:      "Number of messages written to peers.",
+		}),
+		messagesReceived: prom.NewCounter(prom.CounterOpts{
+			Namespace: namespace,
+			Name:      "messages_received_total",
+			Help:      "Number of messages read from peers.",
+		}),
+		messageSize: prom.NewHistogramVec(prom.HistogramOpts{
+			Namespace: namespace,
+			Name:      "message_size_bytes",
+			Help:      "Size of messages sent to and received from peers in bytes.",
+			Buckets:   prom.ExponentialBuckets(64, 4, 8),
+		}, []string{"direction"}),
+		rpcDuration: prom.NewHistogram(prom.HistogramOpts{
+			Namespace: namespace,
+			Name:      "rpc_duration_seconds",
+			Help:      "Duration of requests sent to peers in seconds.",
+			Buckets:   prom.DefBuckets,
+		}),
+	}
+}
+
+func (o *observer) MessageSent(address string, size int) {
+	o.messagesSent.Inc()
+	o.messageSize.WithLabelValues("sent").Observe(float64(size))
+}
+
+func (o *observer) MessageReceived(address string, size int) {
+	o.messagesReceived.Inc()
+	o.messageSize.WithLabelValues("received").Observe(float64(size))
+}
+
+func (o *observer) RequestCompleted(address string, duration time.Duration, err error) {
+	o.rpcDuration.Observe(duration.Seconds())
+}
+
+// connectedPeers counts the peers a network is connected to upon being collected, such
+// that the count may not drift from connection events being missed or doubled up.
+func connectedPeers(net *network.Network) int {
+	count := 0
+	for range net.Peers(context.Background()) {
+		count++
+	}
+	return count
+}
+
+// routingTableCollector reports the number of peers held within each bucket of a routing table.
+type routingTableCollector struct {
+	table *dht.RoutingTable
+}
+
+var routingTablePeersDesc = prom.NewDesc(
+	prom.BuildFQName(namespace, "routing_table", "peers"),
+	"Number of peers held within a bucket of the routing table.",
+	[]string{"bucket"}, nil,
+)
+
+func (c *routingTableCollector) Describe(ch chan<- *prom.Desc) {
+	ch <- routingTablePeersDesc
+}
+
+func (c *routingTableCollector) Collect(ch chan<- prom.Metric) {
+	for bucket, count := range c.table.BucketDistribution() {
+		if count == 0 {
+			continue
+		}
+		ch <- prom.MustNewConstMetric(routingTablePeersDesc, prom.GaugeValue, float64(count), strconv.Itoa(bucket))
+	}
+}
@@ -0,0 +1,87 @@
+package prometheus
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/perlin-network/noise/internal/protobuf"
+	"github.com/perlin-network/noise/network"
+	"github.com/perlin-network/noise/network/discovery"
+
+	prom "github.com/prometheus/client_golang/prometheus"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRegisterMetrics(t *testing.T) {
+	t.Parallel()
+
+	var nodes []*network.Network
+
+	for i := 0; i < 2; i++ {
+		builder := network.NewBuilder()
+		builder.SetAddress(network.FormatAddress("tcp", "localhost", uint16(network.GetRandomUnusedPort())))
+		builder.AddPlugin(new(discovery.Plugin))
+
+		node, err := builder.Build()
+		assert.Nil(t, err)
+
+		go node.Listen()
+		defer node.Close()
+
+		node.BlockUntilListening()
+
+		nodes = append(nodes, node)
+	}
+
+	plugin, _ := nodes[0].Plugin(discovery.PluginID)
+	routes := plugin.(*discovery.Plugin).Routes
+
+	reg := prom.NewRegistry()
+	assert.Nil(t, RegisterMetrics(reg, routes, nodes[0]))
+
+	nodes[0].Bootstrap(nodes[1].Address)
+
+	// Bootstrapping sends a ping, which is replied to with a pong.
+	deadline := time.Now().Add(3 * time.Second)
+	for !routes.PeerExists(nodes[1].ID) {
+		if time.Now().After(deadline) {
+			t.Fatal("expected nodes to discover one another")
+		}
+		time.Sleep(50 * time.Millisecond)
+	}
+
+	client, err := nodes[0].Client(nodes[1].Address)
+	assert.Nil(t, err)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+
+	_, err = client.Request(ctx, &protobuf.Ping{})
+	assert.Nil(t, err)
+
+	families, err := reg.Gather()
+	assert.Nil(t, err)
+
+	gathered := make(map[string]bool)
+	for _, family := range families {
+		gathered[family.GetName()] = true
+	}
+
+	for _, family := range families {
+		if family.GetName() == "noise_connections_active" {
+			assert.Equal(t, float64(1), family.GetMetric()[0].GetGauge().GetValue(), "expected gauge of the peer connected to")
+		}
+	}
+
+	for _, name := range []string{
+		"noise_routing_table_peers",
+		"noise_messages_sent_total",
+		"noise_messages_received_total",
+		"noise_connections_active",
+		"noise_message_size_bytes",
+		"noise_rpc_duration_seconds",
+	} {
+		assert.True(t, gathered[name], "expected metric %s to be gathered", name)
+	}
+}
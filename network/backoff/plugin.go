@@ -6,7 +6,6 @@ import (
 	"time"
 
 	"github.com/perlin-network/noise/internal/protobuf"
-	"github.com/perlin-network/noise/log"
 	"github.com/perlin-network/noise/network"
 )
 
@@ -94,11 +93,13 @@ func (p *Plugin) PeerDisconnect(client *network.PeerClient) {
 
 // startBackoff uses an exponentially increasing timer to try to reconnect to a given address
 func (p *Plugin) startBackoff(addr string) {
+	logger := p.net.PluginLogger(p)
+
 	time.Sleep(p.initialDelay)
 
 	if _, exists := p.backoffs.Load(addr); exists {
 		// don't activate if backoff is already active
-		log.Info().
+		logger.Info().
 			Str("address", addr).
 			Msg("backoff skipped, already active")
 		return
@@ -114,7 +115,7 @@ func (p *Plugin) startBackoff(addr string) {
 		b := s.(*Backoff)
 		if b.TimeoutExceeded() {
 			// check if the backoff expired
-			log.Info().
+			logger.Info().
 				Str("address", addr).
 				Dur("timeout", time.Now().Sub(startTime)).
 				Msg("backoff ended, timed out")
@@ -122,7 +123,7 @@ func (p *Plugin) startBackoff(addr string) {
 		}
 		// sleep for a bit before connecting
 		d := b.NextDuration()
-		log.Info().
+		logger.Info().
 			Str("address", addr).
 			Int("iteration", i+1).
 			Msg("backoff reconnecting")
@@ -140,7 +141,7 @@ func (p *Plugin) startBackoff(addr string) {
 			// check if successfully connected
 			continue
 		}
-		if err := c.Tell(context.Background(), &protobuf.Ping{}); err != nil {
+		if err := c.Tell(context.Background(), &protobuf.Ping{Capabilities: uint64(p.net.Capabilities())}); err != nil {
 			// ping failed, not really connected
 			continue
 		}
@@ -0,0 +1,162 @@
+package network
+
+import (
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// ErrCircuitOpen is returned when dialing an address whose circuit breaker is open.
+var ErrCircuitOpen = errors.New("network: circuit breaker is open for the address")
+
+// circuitBreakerIdleTimeout is how long a closed circuit breaker is kept after its address
+// was last dialed. Breakers are pruned at most once per timeout, upon a breaker being
+// created for a new address.
+const circuitBreakerIdleTimeout = 10 * time.Minute
+
+// CircuitState is the state of the circuit breaker of an address.
+type CircuitState uint8
+
+const (
+	// CircuitClosed denotes an address which is dialed as usual.
+	CircuitClosed CircuitState = iota
+	// CircuitOpen denotes an address which failed to be dialed too many times in a row,
+	// and is not dialed until its recovery timeout elapses.
+	CircuitOpen
+	// CircuitHalfOpen denotes an address whose recovery timeout has elapsed, and which
+	// is being dialed once on trial.
+	CircuitHalfOpen
+)
+
+func (s CircuitState) String() string {
+	switch s {
+	case CircuitClosed:
+		return "closed"
+	case CircuitOpen:
+		return "open"
+	case CircuitHalfOpen:
+		return "half_open"
+	default:
+		return "unknown"
+	}
+}
+
+// CircuitBreakerPolicy decides when dialing an address which keeps failing to be dialed
+// is given up on, and when it is tried again.
+type CircuitBreakerPolicy struct {
+	// FailureThreshold is the number of consecutive dial failures after which the
+	// breaker of an address opens.
+	FailureThreshold int
+
+	// RecoveryTimeout is how long a breaker stays open before a single trial dial is
+	// allowed through.
+	RecoveryTimeout time.Duration
+}
+
+// circuitBreaker tracks consecutive dial failures of a single address.
+type circuitBreaker struct {
+	mutex sync.Mutex
+
+	state    CircuitState
+	failures int
+	openedAt time.Time
+
+	lastDialed time.Time
+}
+
+// allow returns ErrCircuitOpen should the address not be dialed. Should the recovery
+// timeout of an open breaker have elapsed, the breaker becomes half-open and the caller
+// is allowed the trial dial.
+func (b *circuitBreaker) allow(now time.Time, policy CircuitBreakerPolicy) error {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+
+	b.lastDialed = now
+
+	switch b.state {
+	case CircuitOpen:
+		if now.Sub(b.openedAt) < policy.RecoveryTimeout {
+			return ErrCircuitOpen
+		}
+		b.state = CircuitHalfOpen
+		return nil
+	case CircuitHalfOpen:
+		// Only one trial dial is allowed at a time.
+		return ErrCircuitOpen
+	default:
+		return nil
+	}
+}
+
+// record closes the breaker should a dial have succeeded, and counts the failure otherwise.
+func (b *circuitBreaker) record(now time.Time, policy CircuitBreakerPolicy, err error) {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+
+	b.lastDialed = now
+
+	if err == nil {
+		b.state, b.failures = CircuitClosed, 0
+		return
+	}
+
+	b.failures++
+
+	if b.state == CircuitHalfOpen || b.failures >= policy.FailureThreshold {
+		b.state, b.openedAt = CircuitOpen, now
+	}
+}
+
+// idle returns true should the breaker be closed, and its address not have been dialed
+// for circuitBreakerIdleTimeout.
+func (b *circuitBreaker) idle(now time.Time) bool {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+
+	return b.state == CircuitClosed && now.Sub(b.lastDialed) >= circuitBreakerIdleTimeout
+}
+
+// circuitBreaker returns the circuit breaker of an address, or nil should circuit
+// breakers be disabled. Closed breakers of addresses which have not been dialed for
+// circuitBreakerIdleTimeout are pruned.
+func (n *Network) circuitBreaker(address string, now time.Time) *circuitBreaker {
+	if n.opts.circuitBreaker.FailureThreshold <= 0 {
+		return nil
+	}
+
+	if breaker, exists := n.breakers.Load(address); exists {
+		return breaker.(*circuitBreaker)
+	}
+
+	n.breakersMutex.Lock()
+	if now.Sub(n.breakersPruned) >= circuitBreakerIdleTimeout {
+		n.breakers.Range(func(address, breaker interface{}) bool {
+			if breaker.(*circuitBreaker).idle(now) {
+				n.breakers.Delete(address)
+			}
+			return true
+		})
+		n.breakersPruned = now
+	}
+	n.breakersMutex.Unlock()
+
+	breaker, _ := n.breakers.LoadOrStore(address, &circuitBreaker{lastDialed: now})
+	return breaker.(*circuitBreaker)
+}
+
+// CircuitState returns the state of the circuit breaker of an address, which is
+// CircuitClosed should the address never have been dialed.
+func (n *Network) CircuitState(address string) CircuitState {
+	breaker, ok := n.breakers.Load(address)
+	if !ok {
+		return CircuitClosed
+	}
+
+	b := breaker.(*circuitBreaker)
+
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+
+	return b.state
+}
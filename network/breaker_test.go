@@ -0,0 +1,144 @@
+package network
+
+import (
+	"net"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/perlin-network/noise/clock"
+
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+)
+
+// flakyLayer fails to dial while failing is set, and counts all dials.
+type flakyLayer struct {
+	dials   int32
+	failing int32
+}
+
+func (l *flakyLayer) Listen(port int) (net.Listener, error) {
+	return nil, errors.New("flaky: listening is not supported")
+}
+
+func (l *flakyLayer) Dial(address string) (net.Conn, error) {
+	atomic.AddInt32(&l.dials, 1)
+
+	if atomic.LoadInt32(&l.failing) == 1 {
+		return nil, errors.New("flaky: connection refused")
+	}
+
+	conn, _ := net.Pipe()
+	return conn, nil
+}
+
+func TestCircuitBreaker(t *testing.T) {
+	t.Parallel()
+
+	fake := clock.NewFakeClock(time.Now())
+	layer := &flakyLayer{failing: 1}
+
+	builder := NewBuilderWithOptions(
+		WithClock(fake),
+		WithCircuitBreaker(CircuitBreakerPolicy{FailureThreshold: 5, RecoveryTimeout: time.Minute}),
+	)
+	builder.SetAddress("flaky://127.0.0.1:1")
+	builder.RegisterTransportLayer("flaky", layer)
+
+	n, err := builder.Build()
+	assert.Nil(t, err)
+	defer n.Close()
+
+	address := "flaky://127.0.0.1:2"
+
+	for i := 0; i < 5; i++ {
+		_, err := n.Dial(address)
+		assert.NotNil(t, err)
+		assert.NotEqual(t, ErrCircuitOpen, err)
+	}
+	assert.Equal(t, CircuitOpen, n.CircuitState(address))
+
+	_, err = n.Dial(address)
+	assert.Equal(t, ErrCircuitOpen, err)
+	assert.Equal(t, int32(5), atomic.LoadInt32(&layer.dials), "expected no dial while the circuit is open")
+
+	// A failed trial dial reopens the circuit.
+	fake.Advance(time.Minute)
+
+	_, err = n.Dial(address)
+	assert.NotEqual(t, ErrCircuitOpen, err)
+	assert.Equal(t, int32(6), atomic.LoadInt32(&layer.dials), "expected a trial dial once the recovery timeout elapsed")
+	assert.Equal(t, CircuitOpen, n.CircuitState(address))
+
+	_, err = n.Dial(address)
+	assert.Equal(t, ErrCircuitOpen, err)
+
+	// A successful trial dial closes the circuit.
+	fake.Advance(time.Minute)
+	atomic.StoreInt32(&layer.failing, 0)
+
+	conn, err := n.Dial(address)
+	assert.Nil(t, err)
+	conn.Close()
+
+	assert.Equal(t, int32(7), atomic.LoadInt32(&layer.dials))
+	assert.Equal(t, CircuitClosed, n.CircuitState(address))
+}
+
+func TestCircuitBreakerHalfOpen(t *testing.T) {
+	t.Parallel()
+
+	breaker := new(circuitBreaker)
+	policy := CircuitBreakerPolicy{FailureThreshold: 1, RecoveryTimeout: time.Second}
+
+	now := time.Now()
+	breaker.record(now, policy, errors.New("refused"))
+
+	assert.Equal(t, ErrCircuitOpen, breaker.allow(now, policy))
+	assert.Nil(t, breaker.allow(now.Add(time.Second), policy))
+	assert.Equal(t, CircuitHalfOpen, breaker.state)
+	assert.Equal(t, ErrCircuitOpen, breaker.allow(now.Add(time.Second), policy), "expected a single trial dial at a time")
+}
+
+func TestCircuitBreakerPrune(t *testing.T) {
+	t.Parallel()
+
+	fake := clock.NewFakeClock(time.Now())
+	layer := &flakyLayer{failing: 1}
+
+	builder := NewBuilderWithOptions(
+		WithClock(fake),
+		WithCircuitBreaker(CircuitBreakerPolicy{FailureThreshold: 1, RecoveryTimeout: time.Minute}),
+	)
+	builder.SetAddress("flaky://127.0.0.1:1")
+	builder.RegisterTransportLayer("flaky", layer)
+
+	n, err := builder.Build()
+	assert.Nil(t, err)
+	defer n.Close()
+
+	_, err = n.Dial("flaky://127.0.0.1:2")
+	assert.NotNil(t, err)
+
+	atomic.StoreInt32(&layer.failing, 0)
+
+	conn, err := n.Dial("flaky://127.0.0.1:3")
+	assert.Nil(t, err)
+	conn.Close()
+
+	fake.Advance(circuitBreakerIdleTimeout)
+
+	conn, err = n.Dial("flaky://127.0.0.1:4")
+	assert.Nil(t, err)
+	conn.Close()
+
+	_, open := n.breakers.Load("flaky://127.0.0.1:2")
+	assert.True(t, open, "expected open breakers to be kept")
+
+	_, closed := n.breakers.Load("flaky://127.0.0.1:3")
+	assert.False(t, closed, "expected idle closed breakers to be pruned")
+
+	_, dialed := n.breakers.Load("flaky://127.0.0.1:4")
+	assert.True(t, dialed)
+}
@@ -1,16 +1,21 @@
 package network
 
 import (
+	"path"
 	"reflect"
 	"sync"
 	"time"
 
+	"github.com/perlin-network/noise/clock"
 	"github.com/perlin-network/noise/crypto"
 	"github.com/perlin-network/noise/crypto/blake2b"
 	"github.com/perlin-network/noise/crypto/ed25519"
 	"github.com/perlin-network/noise/network/transport"
 	"github.com/perlin-network/noise/peer"
+
 	"github.com/pkg/errors"
+	"github.com/rs/zerolog"
+	"go.opentelemetry.io/otel/trace"
 )
 
 const (
@@ -25,18 +30,26 @@ var (
 	ErrStrNoAddress = "builder: network requires public server IP for peers to connect to"
 	// ErrStrNoKeyPair returns if no keypair was given to the builder
 	ErrStrNoKeyPair = "builder: cryptography keys not provided to Network; cannot create node ID"
+	// ErrStrInvalidPluginConfig returns if a plugins config section is not an object
+	ErrStrInvalidPluginConfig = "builder: config section for plugin %s must be an object"
+	// ErrStrConfigurePlugin returns if a plugin failed to apply its config section
+	ErrStrConfigurePlugin = "builder: failed to configure plugin %s"
 )
 
 // Builder is a Address->processors struct
 type Builder struct {
 	opts options
 
-	keys    *crypto.KeyPair
-	address string
+	keys      *crypto.KeyPair
+	address   string
+	multiaddr string
 
 	plugins     *PluginList
 	pluginCount int
 
+	// config holds each Configurable plugins config section keyed by the plugins package name.
+	config map[string]interface{}
+
 	transports *sync.Map
 }
 
@@ -49,6 +62,11 @@ var defaultBuilderOptions = options{
 	writeBufferSize:   defaultWriteBufferSize,
 	writeFlushLatency: defaultWriteFlushLatency,
 	writeTimeout:      defaultWriteTimeout,
+	requestTimeout:    defaultRequestTimeout,
+	sendQueueDepth:    defaultSendQueueDepth,
+	sendQueuePolicy:   DropOldest,
+	historySize:       defaultConnectionHistorySize,
+	clock:             clock.RealClock{},
 }
 
 // A BuilderOption sets options such as connection timeout and cryptographic // policies for the network
@@ -118,6 +136,241 @@ func WriteTimeout(d time.Duration) BuilderOption {
 	}
 }
 
+// RequestTimeout returns a BuilderOption that sets how long RequestReply awaits a reply
+// (default: 10s).
+func RequestTimeout(d time.Duration) BuilderOption {
+	return func(o *options) {
+		o.requestTimeout = d
+	}
+}
+
+// SendQueueDepth returns a BuilderOption that sets the number of messages which may be
+// queued to be sent to each peer, beyond which the send queue policy applies (default: 256).
+func SendQueueDepth(depth int) BuilderOption {
+	return func(o *options) {
+		o.sendQueueDepth = depth
+	}
+}
+
+// WithSendQueuePolicy returns a BuilderOption that sets what happens to messages sent to
+// a peer whose send queue is full (default: DropOldest).
+func WithSendQueuePolicy(policy SendQueuePolicy) BuilderOption {
+	return func(o *options) {
+		o.sendQueuePolicy = policy
+	}
+}
+
+// BatchWindow returns a BuilderOption that sets how long messages queued to a peer are
+// buffered for, such that all messages queued within the window are sent in a single
+// write (default: 0, where messages are written as soon as they are queued).
+func BatchWindow(d time.Duration) BuilderOption {
+	return func(o *options) {
+		o.batchWindow = d
+	}
+}
+
+// PluginTimeout returns a BuilderOption that sets how long a plugin may take to receive a
+// message before it is abandoned, and the message dispatched to the next plugin
+// (default: 0, where plugins are never abandoned).
+func PluginTimeout(d time.Duration) BuilderOption {
+	return func(o *options) {
+		o.pluginTimeout = d
+	}
+}
+
+// ConnectionHistorySize returns a BuilderOption that sets the number of connection
+// events kept for GetConnectionHistory (default: 256).
+func ConnectionHistorySize(size int) BuilderOption {
+	return func(o *options) {
+		o.historySize = size
+	}
+}
+
+// WithClock returns a BuilderOption that sets the clock the network and its plugins time
+// connection events, probe timeouts, plugin restarts and periodic tasks with
+// (default: clock.RealClock).
+func WithClock(c clock.Clock) BuilderOption {
+	return func(o *options) {
+		o.clock = c
+	}
+}
+
+// WithLogger returns a BuilderOption that sets the logger a plugin logs to
+// (default: the global logger). The plugin may be given as its plugin ID.
+//
+// Example: network.WithLogger(discovery.PluginID, logger)
+func WithLogger(plugin interface{}, logger zerolog.Logger) BuilderOption {
+	return func(o *options) {
+		loggers := make(map[reflect.Type]zerolog.Logger, len(o.pluginLoggers)+1)
+		for ty, l := range o.pluginLoggers {
+			loggers[ty] = l
+		}
+		loggers[reflect.TypeOf(plugin)] = logger
+
+		o.pluginLoggers = loggers
+	}
+}
+
+// WithZerologLogger returns a BuilderOption that sets the logger the network, its
+// connections and its plugins log to (default: the global logger), such that several
+// networks within a process may log independently. Loggers set for a plugin through
+// WithLogger take precedence.
+func WithZerologLogger(logger zerolog.Logger) BuilderOption {
+	return func(o *options) {
+		o.logger = &logger
+	}
+}
+
+// WithStateDir returns a BuilderOption that sets the directory the state of plugins
+// implementing StatefulPlugin is persisted to (default: none, where state is not
+// persisted).
+func WithStateDir(dir string) BuilderOption {
+	return func(o *options) {
+		o.stateDir = dir
+	}
+}
+
+// WithRestartPolicy returns a BuilderOption that sets whether a plugin is restarted
+// after failing to handle an incoming message (default: never). The plugin may be
+// given as its plugin ID.
+func WithRestartPolicy(plugin interface{}, policy RestartPolicy) BuilderOption {
+	return func(o *options) {
+		policies := make(map[reflect.Type]RestartPolicy, len(o.pluginRestartPolicies)+1)
+		for ty, p := range o.pluginRestartPolicies {
+			policies[ty] = p
+		}
+		policies[reflect.TypeOf(plugin)] = policy
+
+		o.pluginRestartPolicies = policies
+	}
+}
+
+// WithTrafficShaper returns a BuilderOption that limits the rate of messages received from
+// and sent to all peers combined (default: unlimited).
+func WithTrafficShaper(shaper TrafficShaper) BuilderOption {
+	return func(o *options) {
+		o.trafficShaper = shaper
+	}
+}
+
+// WithFloodControl returns a BuilderOption that drops all messages from a sender for the
+// rest of a window once it exceeds a threshold of messages per second (default: disabled).
+// Senders are not notified of their messages being dropped.
+func WithFloodControl(floodControl FloodControl) BuilderOption {
+	return func(o *options) {
+		o.floodControl = floodControl
+	}
+}
+
+// WithReconnectPolicy returns a BuilderOption that has peers whose connection dropped be
+// dialed again as per the policy, rather than having their client closed (default: disabled).
+func WithReconnectPolicy(policy ReconnectPolicy) BuilderOption {
+	return func(o *options) {
+		o.reconnectPolicy = policy
+	}
+}
+
+// WithCircuitBreaker returns a BuilderOption that stops dialing addresses which failed to
+// be dialed FailureThreshold times in a row, until their RecoveryTimeout elapses
+// (default: disabled).
+func WithCircuitBreaker(policy CircuitBreakerPolicy) BuilderOption {
+	return func(o *options) {
+		o.circuitBreaker = policy
+	}
+}
+
+// WithForwardSecrecy returns a BuilderOption that encrypts all connections with session
+// keys derived from an ephemeral X25519 key exchange, which is authenticated by having
+// either side sign its ephemeral key with its long-term key pair (default: disabled).
+// The key pair should be the same as the one the network is built with.
+func WithForwardSecrecy(keys *crypto.KeyPair) BuilderOption {
+	return func(o *options) {
+		o.sessionKeys = keys
+	}
+}
+
+// WithAESGCM returns a BuilderOption that has connections encrypted with forward secrecy
+// use AES-256-GCM rather than ChaCha20-Poly1305, by advertising FeatureAESGCM (default:
+// disabled). Without feature negotiation, peers must also enable it to connect to the
+// network.
+func WithAESGCM() BuilderOption {
+	return func(o *options) {
+		o.aesGCM = true
+	}
+}
+
+// WithFeatureNegotiation returns a BuilderOption that has either side of every connection
+// exchange the transport features it supports upon connecting, such that connections are
+// built from the features both sides support (default: disabled). Peers must also enable
+// negotiation to connect to the network.
+//
+// Example: WithFeatureNegotiation(AllFeatures &^ FeatureCompression)
+func WithFeatureNegotiation(features Features) BuilderOption {
+	return func(o *options) {
+		o.negotiateFeatures = true
+		o.features = features
+	}
+}
+
+// WithCapabilities returns a BuilderOption that sets the capabilities the network advertises
+// to its peers within pings and pongs (default: none).
+//
+// Example: WithCapabilities(CapabilityCompression | CapabilityOrdering)
+func WithCapabilities(capabilities Capabilities) BuilderOption {
+	return func(o *options) {
+		o.capabilities = capabilities
+	}
+}
+
+// WithResolver returns a BuilderOption that sets the resolver peer IDs are resolved to
+// the addresses peers may be dialed at with (default: a StaticResolver populated with the
+// address every peer identifies itself with).
+func WithResolver(resolver Resolver) BuilderOption {
+	return func(o *options) {
+		o.resolver = resolver
+	}
+}
+
+// WithAddressDetection returns a BuilderOption that detects the address of the network
+// every interval once it starts listening (default: disabled). Should the detected address
+// differ from the address of the network, the network migrates to it with MigrateAddress.
+func WithAddressDetection(interval time.Duration, detector AddressDetector) BuilderOption {
+	return func(o *options) {
+		o.addressDetector = detector
+		o.addressDetectionInterval = interval
+	}
+}
+
+// WithPROXYProtocol returns a BuilderOption that has every accepted connection begin with
+// a PROXY protocol v2 header, such that the address of peers behind a load balancer is
+// known (default: disabled). Connections without a header are dropped.
+func WithPROXYProtocol(enabled bool) BuilderOption {
+	return func(o *options) {
+		o.proxyProtocol = enabled
+	}
+}
+
+// WithAddressVerification returns a BuilderOption that has every peer which connects to
+// the network prove it listens at the address it claims within its ID, by dialing the
+// address back and having the node listening there sign a random nonce with the peers
+// key (default: disabled). Connections from peers which fail to prove so, or which claim
+// an address on a host other than the one they connected from, are dropped.
+func WithAddressVerification(enabled bool) BuilderOption {
+	return func(o *options) {
+		o.verifyAddresses = enabled
+	}
+}
+
+// WithTracing returns a BuilderOption that records spans of messages sent and received with
+// a tracer provider (default: disabled). Spans are propagated to peers through message
+// headers in the W3C Trace Context format, and every plugin receives messages within a child
+// span of the span the message was sent within.
+func WithTracing(tp trace.TracerProvider) BuilderOption {
+	return func(o *options) {
+		o.tracerProvider = tp
+	}
+}
+
 // NewBuilder returns a new builder with default options.
 func NewBuilder() *Builder {
 	builder := &Builder{
@@ -155,6 +408,22 @@ func (builder *Builder) SetAddress(address string) {
 	builder.address = address
 }
 
+// SetMultiaddr sets the comma-separated list of addresses the network may be reached at
+// in preference order, which is advertised to peers alongside the networks ID.
+//
+// Example: builder.SetMultiaddr("/ip4/1.2.3.4/tcp/8000,/ip4/1.2.3.4/udp/8000/quic")
+func (builder *Builder) SetMultiaddr(multiaddr string) {
+	builder.multiaddr = multiaddr
+}
+
+// SetConfig sets the config which Configurable plugins are configured with upon
+// building the network. Each plugin receives the section keyed by its package name.
+//
+// Example: builder.SetConfig(map[string]interface{}{"discovery": map[string]interface{}{"alpha": 3}})
+func (builder *Builder) SetConfig(config map[string]interface{}) {
+	builder.config = config
+}
+
 // AddPluginWithPriority registers a new plugin onto the network with a set priority.
 func (builder *Builder) AddPluginWithPriority(priority int, plugin PluginInterface) error {
 	// Initialize plugin list if not exist.
@@ -213,9 +482,27 @@ func (builder *Builder) Build() (*Network, error) {
 		return nil, err
 	}
 
+	if err := builder.configurePlugins(); err != nil {
+		return nil, err
+	}
+
+	if len(builder.multiaddr) > 0 {
+		if _, err := ParseMultiaddr(builder.multiaddr); err != nil {
+			return nil, err
+		}
+	}
+
 	id := peer.CreateID(unifiedAddress, builder.keys.PublicKey)
+	id.Multiaddr = builder.multiaddr
+
+	// Start sequence numbers off of the current time such that peers do not drop
+	// messages as replays should this node restart.
+	sequence := uint64(time.Now().UnixNano())
 
 	net := &Network{
+		sequence:       sequence,
+		maxMessageSize: defaultMaxMessageSize,
+
 		opts:    builder.opts,
 		ID:      id,
 		keys:    builder.keys,
@@ -224,14 +511,77 @@ func (builder *Builder) Build() (*Network, error) {
 		plugins:    builder.plugins,
 		transports: builder.transports,
 
-		peers:       new(sync.Map),
-		connections: new(sync.Map),
+		peers:         new(sync.Map),
+		connections:   new(sync.Map),
+		replayWindows: newReplayWindows(),
+		multiaddrs:    new(sync.Map),
+		migrations:    make(map[string]uint64),
+
+		history: newConnectionHistory(builder.opts.historySize),
 
 		listeningCh: make(chan struct{}),
 		kill:        make(chan struct{}),
+
+		createdAt: builder.opts.clock.Now(),
+	}
+
+	for _, info := range net.plugins.values {
+		if _, ok := info.Plugin.(AsyncStartup); ok {
+			info.hold()
+		}
+	}
+
+	net.connLog = net.componentLogger("connection")
+	net.protocolLog = net.componentLogger("protocol")
+
+	net.inboundLimiter, net.outboundLimiter = builder.opts.trafficShaper.limiters()
+
+	if net.opts.resolver == nil {
+		net.opts.resolver = NewStaticResolver()
 	}
 
 	net.Init()
 
 	return net, nil
 }
+
+// configurePlugins passes every Configurable plugin its respective config section.
+func (builder *Builder) configurePlugins() error {
+	var err error
+
+	builder.plugins.Each(func(plugin PluginInterface) {
+		configurable, ok := plugin.(Configurable)
+		if !ok || err != nil {
+			return
+		}
+
+		key := pluginConfigKey(plugin)
+
+		section := make(map[string]interface{})
+		if raw, exists := builder.config[key]; exists {
+			if section, ok = raw.(map[string]interface{}); !ok {
+				err = errors.Errorf(ErrStrInvalidPluginConfig, key)
+				return
+			}
+		}
+
+		if e := configurable.Configure(section); e != nil {
+			err = errors.Wrapf(e, ErrStrConfigurePlugin, key)
+		}
+	})
+
+	return err
+}
+
+// pluginConfigKey returns the name of the package a plugin is declared in.
+func pluginConfigKey(plugin interface{}) string {
+	ty := reflect.TypeOf(plugin)
+	if ty == nil {
+		return ""
+	}
+
+	for ty.Kind() == reflect.Ptr {
+		ty = ty.Elem()
+	}
+	return path.Base(ty.PkgPath())
+}
@@ -2,6 +2,7 @@ package network
 
 import (
 	"bytes"
+	"context"
 	"fmt"
 	"testing"
 	"time"
@@ -10,6 +11,7 @@ import (
 	"github.com/perlin-network/noise/crypto/ed25519"
 	"github.com/pkg/errors"
 	"github.com/stretchr/testify/assert"
+	"go.uber.org/goleak"
 )
 
 var (
@@ -213,4 +215,40 @@ func TestPeers(t *testing.T) {
 	}
 }
 
+// TestPeersEarlyBreak is not run in parallel, as it checks for leaked goroutines.
+func TestPeersEarlyBreak(t *testing.T) {
+	net, err := NewBuilder().Build()
+	assert.Nil(t, err)
+	defer net.Close()
+
+	for i := 0; i < 100; i++ {
+		client, err := createPeerClient(net, FormatAddress("tcp", "127.0.0.1", uint16(20000+i)))
+		assert.Nil(t, err)
+
+		net.peers.Store(client.Address, client)
+	}
+
+	// Connections accepted by networks of prior tests may still be winding down.
+	defer goleak.VerifyNone(t, goleak.IgnoreCurrent(), goleak.IgnoreAnyFunction("github.com/perlin-network/noise/network.(*Network).Accept"))
+
+	seen := make(map[string]struct{})
+	for client := range net.Peers(context.Background()) {
+		seen[client.Address] = struct{}{}
+	}
+	assert.Len(t, seen, 100, "expected every peer to be yielded")
+
+	ctx, cancel := context.WithCancel(context.Background())
+
+	count := 0
+	for range net.Peers(ctx) {
+		if count++; count == 10 {
+			break
+		}
+	}
+
+	cancel()
+
+	assert.Equal(t, 10, count)
+}
+
 // Broadcast functions are tested through examples.
@@ -0,0 +1,134 @@
+package network
+
+import (
+	"bytes"
+	"compress/flate"
+	"io"
+	"strings"
+	"sync/atomic"
+
+	"github.com/perlin-network/noise/internal/protobuf"
+
+	"github.com/pkg/errors"
+)
+
+// Capabilities is a bitmask of features a node advertises to its peers within pings and
+// pongs, such that peers may select the protocols they communicate with the node by.
+type Capabilities uint64
+
+const (
+	// CapabilityCompression denotes a node accepting messages whose payloads are compressed
+	// with DEFLATE. Messages sent to peers advertising it are compressed.
+	CapabilityCompression Capabilities = 1 << iota
+
+	// CapabilityOrdering denotes a node dispatching messages in the order they were sent.
+	CapabilityOrdering
+
+	// CapabilityForwardSecrecy denotes a node supporting connections encrypted with
+	// ephemeral session keys.
+	CapabilityForwardSecrecy
+)
+
+// Has returns true if all of the specified capabilities are set.
+func (c Capabilities) Has(capabilities Capabilities) bool {
+	return c&capabilities == capabilities
+}
+
+// String returns the names of all set capabilities, separated by `|`.
+func (c Capabilities) String() string {
+	var names []string
+
+	if c.Has(CapabilityCompression) {
+		names = append(names, "compression")
+	}
+	if c.Has(CapabilityOrdering) {
+		names = append(names, "ordering")
+	}
+	if c.Has(CapabilityForwardSecrecy) {
+		names = append(names, "forward_secrecy")
+	}
+
+	if len(names) == 0 {
+		return "none"
+	}
+
+	return strings.Join(names, "|")
+}
+
+// Capabilities returns the capabilities the network advertises to its peers.
+func (n *Network) Capabilities() Capabilities {
+	return n.opts.capabilities
+}
+
+// Capabilities returns the capabilities the peer advertised, which are none should the
+// peer not have advertised any yet.
+func (c *PeerClient) Capabilities() Capabilities {
+	return Capabilities(atomic.LoadUint64(&c.capabilities))
+}
+
+// SetCapabilities sets the capabilities the peer advertised.
+func (c *PeerClient) SetCapabilities(capabilities Capabilities) {
+	atomic.StoreUint64(&c.capabilities, uint64(capabilities))
+}
+
+// encodeMessage returns a message as it should be sent to the peer at an address, which
+// is compressed should the peer have advertised accepting compressed messages.
+func (n *Network) encodeMessage(address string, message *protobuf.Message) (*protobuf.Message, error) {
+	if len(message.Message) == 0 {
+		return message, nil
+	}
+
+	client, ok := n.peers.Load(address)
+	if !ok || !client.(*PeerClient).Capabilities().Has(CapabilityCompression) {
+		return message, nil
+	}
+
+	var buffer bytes.Buffer
+
+	w := flateWriters.Get().(*flate.Writer)
+	w.Reset(&buffer)
+
+	_, err := w.Write(message.Message)
+	if err == nil {
+		err = w.Close()
+	}
+
+	flateWriters.Put(w)
+
+	if err != nil {
+		return nil, errors.Wrap(err, "compression: failed to compress message")
+	}
+
+	// Messages may be broadcasted to several peers, and thus are copied rather than modified.
+	compressed := *message
+	compressed.Message = buffer.Bytes()
+	compressed.Compressed = true
+
+	return &compressed, nil
+}
+
+// decodeMessage decompresses the payload of a message should it be compressed.
+func (n *Network) decodeMessage(msg *protobuf.Message) error {
+	if !msg.Compressed {
+		return nil
+	}
+
+	r := flate.NewReader(bytes.NewReader(msg.Message))
+	defer r.Close()
+
+	limit := int64(n.MaxMessageSizeBytes())
+
+	data, err := io.ReadAll(io.LimitReader(r, limit+1))
+	if err != nil {
+		return errors.Wrap(err, "compression: failed to decompress message")
+	}
+
+	if int64(len(data)) > limit {
+		return errors.Errorf("message decompresses to more than %d bytes", limit)
+	}
+
+	msg.Message = data
+	msg.Compressed = false
+
+	return nil
+}
@@ -0,0 +1,60 @@
+package network
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/perlin-network/noise/internal/protobuf"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCapabilitiesString(t *testing.T) {
+	t.Parallel()
+
+	assert.Equal(t, "none", Capabilities(0).String())
+	assert.Equal(t, "compression|forward_secrecy", (CapabilityCompression | CapabilityForwardSecrecy).String())
+	assert.Equal(t, "ordering", CapabilityOrdering.String())
+}
+
+func TestMessageCompression(t *testing.T) {
+	t.Parallel()
+
+	builder := NewBuilderWithOptions(WithCapabilities(CapabilityCompression))
+	builder.SetAddress(FormatAddress("tcp", "localhost", uint16(GetRandomUnusedPort())))
+
+	node, err := builder.Build()
+	assert.Nil(t, err)
+
+	address := "tcp://127.0.0.1:3000"
+
+	client, err := createPeerClient(node, address)
+	assert.Nil(t, err)
+	node.peers.Store(address, client)
+
+	message := &protobuf.Message{Message: bytes.Repeat([]byte("noise"), 1000), Opcode: 1}
+
+	// Messages are only compressed once the peer advertises accepting compressed messages.
+	encoded, err := node.encodeMessage(address, message)
+	assert.Nil(t, err)
+	assert.False(t, encoded.Compressed)
+
+	client.SetCapabilities(CapabilityCompression | CapabilityOrdering)
+
+	encoded, err = node.encodeMessage(address, message)
+	assert.Nil(t, err)
+	assert.True(t, encoded.Compressed)
+	assert.True(t, len(encoded.Message) < len(message.Message))
+	assert.False(t, message.Compressed, "expected the original message to be left untouched")
+
+	assert.Nil(t, node.decodeMessage(encoded))
+	assert.False(t, encoded.Compressed)
+	assert.Equal(t, message.Message, encoded.Message)
+
+	// Peers may not have messages decompress to more than the maximum message size.
+	encoded, err = node.encodeMessage(address, message)
+	assert.Nil(t, err)
+
+	node.SetMaxMessageSizeBytes(len(message.Message) - 1)
+	assert.NotNil(t, node.decodeMessage(encoded))
+}
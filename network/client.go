@@ -2,6 +2,8 @@ package network
 
 import (
 	"context"
+	"crypto/tls"
+	"crypto/x509"
 	"net"
 	"sync"
 	"sync/atomic"
@@ -20,21 +22,53 @@ type PeerClient struct {
 
 	Network *Network
 
+	// ID is the ID the peer identified itself with, which is nil until the peer has sent
+	// a message. Use PeerID to read it should the peer possibly not be identified yet.
 	ID      *peer.ID
 	Address string
 
+	idMutex sync.RWMutex
+
 	Requests     sync.Map // uint64 -> *RequestState
 	RequestNonce uint64
 
 	stream StreamState
 
+	// peerCertificate is the leaf certificate presented by the peer over TLS.
+	peerCertificate *x509.Certificate
+
+	// features are the transport features negotiated with the peer.
+	features uint32 // for atomic ops
+
+	// capabilities are the capabilities the peer advertised.
+	capabilities uint64 // for atomic ops
+
+	// sourceAddr is the address the peers incoming connection originated from.
+	sourceAddr net.Addr
+
+	// probes tracks ping probes awaiting an echo from the peer.
+	probeOnce sync.Once
+	probes    sync.Map // uint64 -> chan struct{}
+
+	rttMutex    sync.Mutex
+	rtt, jitter time.Duration
+
 	outgoingReady chan struct{}
 	incomingReady chan struct{}
 
 	jobs chan func()
 
+	// sendQueue holds messages told to the peer which await being written.
+	sendQueue *sendQueue
+
+	// inflight counts messages being sent to or handled from the peer.
+	inflight int32 // for atomic ops
+
 	closed      uint32 // for atomic ops
 	closeSignal chan struct{}
+
+	// connectedAt is when the connection to the peer was established.
+	connectedAt time.Time
 }
 
 // StreamState represents a stream.
@@ -74,7 +108,10 @@ func createPeerClient(network *Network, address string) (*PeerClient, error) {
 		},
 
 		jobs:        make(chan func(), 128),
+		sendQueue:   newSendQueue(network.opts.sendQueueDepth, network.opts.sendQueuePolicy),
 		closeSignal: make(chan struct{}),
+
+		connectedAt: network.opts.clock.Now(),
 	}
 
 	return client, nil
@@ -82,12 +119,35 @@ func createPeerClient(network *Network, address string) (*PeerClient, error) {
 
 // Init initialize a client's pluging and starts executing a jobs.
 func (c *PeerClient) Init() {
+	// Start writing queued messages first, as plugins may send messages upon the peer connecting.
+	go c.writeQueued()
+
 	c.Network.plugins.Each(func(plugin PluginInterface) {
 		plugin.PeerConnect(c)
 	})
+	c.Network.eachObserver(func(observer ObserverInterface) {
+		observer.PeerConnect(c.Address)
+	})
+	c.Network.recordConnectionEvent(EventConnected, c.PeerID(), c.Address, nil)
 	go c.executeJobs()
 }
 
+// PeerID returns the ID the peer identified itself with, or nil should it not have sent a
+// message yet. Unlike reading ID, it may be called while the peer is identifying itself.
+func (c *PeerClient) PeerID() *peer.ID {
+	c.idMutex.RLock()
+	defer c.idMutex.RUnlock()
+
+	return c.ID
+}
+
+// setID sets the ID the peer identified itself with.
+func (c *PeerClient) setID(id *peer.ID) {
+	c.idMutex.Lock()
+	c.ID = id
+	c.idMutex.Unlock()
+}
+
 func (c *PeerClient) executeJobs() {
 	for {
 		select {
@@ -99,6 +159,60 @@ func (c *PeerClient) executeJobs() {
 	}
 }
 
+// writeQueued writes messages queued to be sent to the peer, in the order they were queued.
+func (c *PeerClient) writeQueued() {
+	for {
+		req, ok := c.sendQueue.pop()
+		if !ok {
+			return
+		}
+
+		batch := []*sendRequest{req}
+
+		// Coalesce messages queued within the batch window into a single write.
+		if window := c.Network.opts.batchWindow; window > 0 {
+			c.Network.opts.clock.Sleep(window)
+			batch = append(batch, c.sendQueue.drain()...)
+		}
+
+		messages := req.messages
+		if len(batch) > 1 {
+			messages = nil
+			for _, req := range batch {
+				messages = append(messages, req.messages...)
+			}
+		}
+
+		err := c.Network.write(c.Address, messages)
+
+		logged := false
+		for _, req := range batch {
+			// Errors are logged once should any sender not wait for the result.
+			if err != nil && req.result == nil && !logged {
+				c.Network.protocolLog.Warn().
+					Err(err).
+					Str("address", c.Address).
+					Msg("failed to send queued message to peer")
+				logged = true
+			}
+			req.complete(err)
+		}
+
+		c.sendQueue.done()
+	}
+}
+
+// SendQueueLen returns the number of messages queued to be sent to the peer.
+func (c *PeerClient) SendQueueLen() int {
+	return c.sendQueue.len()
+}
+
+// SendQueueDropped returns the number of messages to the peer which were dropped due to
+// its send queue being full.
+func (c *PeerClient) SendQueueDropped() uint64 {
+	return c.sendQueue.droppedCount()
+}
+
 // Submit adds a job to the execution queue.
 func (c *PeerClient) Submit(job func()) {
 	select {
@@ -107,6 +221,22 @@ func (c *PeerClient) Submit(job func()) {
 	}
 }
 
+// beginWork marks a message as being sent to or handled from the peer.
+func (c *PeerClient) beginWork() {
+	atomic.AddInt32(&c.inflight, 1)
+}
+
+// endWork marks a message as having been sent to or handled from the peer.
+func (c *PeerClient) endWork() {
+	atomic.AddInt32(&c.inflight, -1)
+}
+
+// isIdle returns true if no messages are queued to be sent to, being sent to, or being
+// handled from the peer.
+func (c *PeerClient) isIdle() bool {
+	return atomic.LoadInt32(&c.inflight) == 0 && c.sendQueue.idle()
+}
+
 // Close stops all sessions/streams and cleans up the nodes in routing table.
 func (c *PeerClient) Close() error {
 	if atomic.SwapUint32(&c.closed, 1) == 1 {
@@ -114,6 +244,7 @@ func (c *PeerClient) Close() error {
 	}
 
 	close(c.closeSignal)
+	c.sendQueue.close()
 
 	c.stream.Lock()
 	c.stream.isClosed = true
@@ -122,29 +253,36 @@ func (c *PeerClient) Close() error {
 	c.Network.plugins.Each(func(plugin PluginInterface) {
 		plugin.PeerDisconnect(c)
 	})
+	c.Network.eachObserver(func(observer ObserverInterface) {
+		observer.PeerDisconnect(c.Address)
+	})
+	id := c.PeerID()
+
+	c.Network.recordConnectionEvent(EventDisconnected, id, c.Address, nil)
 
 	// Remove entries from node's network.
-	if c.ID != nil {
+	if id != nil {
 		// close out connections
-		if state, ok := c.Network.ConnectionState(c.ID.Address); ok {
+		if state, ok := c.Network.ConnectionState(id.Address); ok {
 			state.conn.Close()
 		}
 
-		c.Network.peers.Delete(c.ID.Address)
-		c.Network.connections.Delete(c.ID.Address)
+		c.Network.peers.Delete(id.Address)
+		c.Network.connections.Delete(id.Address)
 	}
 
 	return nil
 }
 
-// Tell will asynchronously emit a message to a given peer.
+// Tell will asynchronously emit a message to a given peer. The message is queued into the
+// peers send queue, which is handled as per the networks send queue policy should it be full.
 func (c *PeerClient) Tell(ctx context.Context, message proto.Message) error {
 	signed, err := c.Network.PrepareMessage(ctx, message)
 	if err != nil {
 		return errors.Wrap(err, "failed to sign message")
 	}
 
-	err = c.Network.Write(c.Address, signed)
+	err = c.Network.enqueue(ctx, c.Address, []*protobuf.Message{signed}, false)
 	if err != nil {
 		return errors.Wrapf(err, "failed to send message to %s", c.Address)
 	}
@@ -152,8 +290,35 @@ func (c *PeerClient) Tell(ctx context.Context, message proto.Message) error {
 	return nil
 }
 
+// TellBatch will asynchronously emit a batch of messages to a given peer with a single write.
+func (c *PeerClient) TellBatch(ctx context.Context, messages []proto.Message) error {
+	batch := make([]*protobuf.Message, 0, len(messages))
+
+	for _, message := range messages {
+		signed, err := c.Network.PrepareMessage(ctx, message)
+		if err != nil {
+			return errors.Wrap(err, "failed to sign message")
+		}
+		batch = append(batch, signed)
+	}
+
+	err := c.Network.WriteBatch(c.Address, batch)
+	if err != nil {
+		return errors.Wrapf(err, "failed to send messages to %s", c.Address)
+	}
+
+	return nil
+}
+
 // Request requests for a response for a request sent to a given peer.
-func (c *PeerClient) Request(ctx context.Context, req proto.Message) (proto.Message, error) {
+func (c *PeerClient) Request(ctx context.Context, req proto.Message) (res proto.Message, err error) {
+	start := time.Now()
+	defer func() {
+		c.Network.eachObserver(func(observer ObserverInterface) {
+			observer.RequestCompleted(c.Address, time.Since(start), err)
+		})
+	}()
+
 	if ctx == nil {
 		return nil, errors.New("network: invalid context")
 	}
@@ -316,6 +481,53 @@ func (c *PeerClient) SetWriteDeadline(t time.Time) error {
 	return nil
 }
 
+// PeerCertificate returns the leaf certificate the peer presented over its incoming
+// TLS connection. Returns nil should the peer not have presented a certificate.
+func (c *PeerClient) PeerCertificate() *x509.Certificate {
+	return c.peerCertificate
+}
+
+// SourceAddr returns the address the peers incoming connection originated from, which
+// is the address of the peer behind a load balancer should the network expect PROXY
+// protocol headers. Returns nil should the peer not have connected to the network.
+func (c *PeerClient) SourceAddr() net.Addr {
+	return c.sourceAddr
+}
+
+// Features returns the transport features negotiated with the peer.
+func (c *PeerClient) Features() Features {
+	return Features(atomic.LoadUint32(&c.features))
+}
+
+// setFeatures sets the transport features negotiated with the peer.
+func (c *PeerClient) setFeatures(features Features) {
+	atomic.StoreUint32(&c.features, uint32(features))
+}
+
+// ConnectedAt returns when the connection to the peer was established. Reconnecting to
+// the peer does not reset it.
+func (c *PeerClient) ConnectedAt() time.Time {
+	return c.connectedAt
+}
+
+// setPeerCertificate stores the leaf certificate presented by the peer, should the
+// peer be connected over TLS.
+func (c *PeerClient) setPeerCertificate(conn net.Conn) {
+	conn, ok := findConn(conn, func(conn net.Conn) bool {
+		_, ok := conn.(*tls.Conn)
+		return ok
+	})
+	if !ok {
+		return
+	}
+
+	secured := conn.(*tls.Conn)
+
+	if certs := secured.ConnectionState().PeerCertificates; len(certs) > 0 {
+		c.peerCertificate = certs[0]
+	}
+}
+
 // setIncomingReady sets a client state to ready for the incomming requests.
 func (c *PeerClient) setIncomingReady() {
 	close(c.incomingReady)
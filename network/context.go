@@ -2,14 +2,24 @@ package network
 
 import (
 	"context"
+	"time"
 )
 
 type (
-	signMessageCtxKeyType string
+	signMessageCtxKeyType     string
+	broadcastFanoutCtxKeyType string
+	groupIDCtxKeyType         string
+	deadlineCtxKeyType        string
 )
 
 const (
-	signMessageCtxKey signMessageCtxKeyType = "signMessage"
+	signMessageCtxKey     signMessageCtxKeyType     = "signMessage"
+	broadcastFanoutCtxKey broadcastFanoutCtxKeyType = "broadcastFanout"
+	groupIDCtxKey         groupIDCtxKeyType         = "groupID"
+	deadlineCtxKey        deadlineCtxKeyType        = "deadline"
+
+	// defaultBroadcastFanout is the number of peers a weighted broadcast is sent to by default.
+	defaultBroadcastFanout = 3
 )
 
 // WithSignMessage sets whether the request should be signed
@@ -25,3 +35,45 @@ func GetSignMessage(ctx context.Context) bool {
 	}
 	return sign
 }
+
+// WithBroadcastFanout sets the number of peers a weighted broadcast is sent to
+func WithBroadcastFanout(ctx context.Context, fanout int) context.Context {
+	return context.WithValue(ctx, broadcastFanoutCtxKey, fanout)
+}
+
+// GetBroadcastFanout returns the number of peers a weighted broadcast is sent to (default: 3)
+func GetBroadcastFanout(ctx context.Context) int {
+	fanout, ok := ctx.Value(broadcastFanoutCtxKey).(int)
+	if !ok || fanout <= 0 {
+		return defaultBroadcastFanout
+	}
+	return fanout
+}
+
+// withGroupID sets the ID of the group a message is sent within
+func withGroupID(ctx context.Context, id []byte) context.Context {
+	return context.WithValue(ctx, groupIDCtxKey, id)
+}
+
+// getGroupID returns the ID of the group a message is sent within, or nil if none
+func getGroupID(ctx context.Context) []byte {
+	id, _ := ctx.Value(groupIDCtxKey).([]byte)
+	return id
+}
+
+// WithTimeout returns a context which is cancelled after a timeout, like context.WithTimeout.
+// Messages sent with the context carry its deadline, such that peers drop requests which
+// arrive after the deadline, and refuse to reply once it has passed.
+func WithTimeout(ctx context.Context, d time.Duration) (context.Context, context.CancelFunc) {
+	ctx, cancel := context.WithTimeout(ctx, d)
+	deadline, _ := ctx.Deadline()
+
+	return context.WithValue(ctx, deadlineCtxKey, deadline), cancel
+}
+
+// getDeadline returns the deadline messages sent with a context carry, should it have
+// been created by WithTimeout.
+func getDeadline(ctx context.Context) (time.Time, bool) {
+	deadline, ok := ctx.Value(deadlineCtxKey).(time.Time)
+	return deadline, ok
+}
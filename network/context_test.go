@@ -0,0 +1,127 @@
+package network_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/perlin-network/noise/clock"
+	"github.com/perlin-network/noise/internal/test/protobuf"
+	"github.com/perlin-network/noise/network"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// slowReplyPlugin replies to test messages after a delay, and reports the deadline of
+// each request and the result of replying to it.
+type slowReplyPlugin struct {
+	*network.Plugin
+
+	delay     time.Duration
+	deadlines chan bool
+	replies   chan error
+}
+
+func (p *slowReplyPlugin) Receive(ctx *network.PluginContext) error {
+	if _, ok := ctx.Message().(*protobuf.TestMessage); !ok {
+		return nil
+	}
+
+	_, ok := ctx.Deadline()
+	p.deadlines <- ok
+
+	time.Sleep(p.delay)
+
+	p.replies <- ctx.Reply(context.Background(), &protobuf.TestMessage{Message: "reply"})
+	return nil
+}
+
+func TestRequestReplyTimeout(t *testing.T) {
+	t.Parallel()
+
+	server := &slowReplyPlugin{
+		delay:     200 * time.Millisecond,
+		deadlines: make(chan bool, 2),
+		replies:   make(chan error, 2),
+	}
+
+	var nodes []*network.Network
+
+	for i := 0; i < 2; i++ {
+		builder := network.NewBuilderWithOptions(network.RequestTimeout(100 * time.Millisecond))
+		builder.SetAddress(network.FormatAddress("tcp", "localhost", uint16(network.GetRandomUnusedPort())))
+
+		if i == 0 {
+			builder.AddPlugin(server)
+		}
+
+		node, err := builder.Build()
+		assert.Nil(t, err)
+
+		go node.Listen()
+		defer node.Close()
+
+		node.BlockUntilListening()
+		nodes = append(nodes, node)
+	}
+
+	_, err := nodes[1].RequestReply(context.Background(), nodes[0].Address, &protobuf.TestMessage{})
+	assert.Equal(t, context.DeadlineExceeded, err)
+
+	// The deadline is carried by the request, and the server refuses to reply once it has passed.
+	assert.True(t, <-server.deadlines)
+	assert.Equal(t, context.DeadlineExceeded, <-server.replies)
+
+	// Requests sent without a deadline are replied to however long the reply takes.
+	client, err := nodes[1].Client(nodes[0].Address)
+	assert.Nil(t, err)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+
+	response, err := client.Request(ctx, &protobuf.TestMessage{})
+	assert.Nil(t, err)
+	assert.Equal(t, "reply", response.(*protobuf.TestMessage).Message)
+
+	assert.False(t, <-server.deadlines)
+	assert.Nil(t, <-server.replies)
+}
+
+func TestRequestReplyClockSkew(t *testing.T) {
+	t.Parallel()
+
+	server := &slowReplyPlugin{
+		deadlines: make(chan bool, 1),
+		replies:   make(chan error, 1),
+	}
+
+	// The server's clock is an hour ahead of the client's clock.
+	skewed := clock.NewFakeClock(time.Now().Add(time.Hour))
+
+	var nodes []*network.Network
+
+	for i := 0; i < 2; i++ {
+		builder := network.NewBuilder()
+		if i == 0 {
+			builder = network.NewBuilderWithOptions(network.WithClock(skewed))
+			builder.AddPlugin(server)
+		}
+		builder.SetAddress(network.FormatAddress("tcp", "localhost", uint16(network.GetRandomUnusedPort())))
+
+		node, err := builder.Build()
+		assert.Nil(t, err)
+
+		go node.Listen()
+		defer node.Close()
+
+		node.BlockUntilListening()
+		nodes = append(nodes, node)
+	}
+
+	response, err := nodes[1].RequestReply(context.Background(), nodes[0].Address, &protobuf.TestMessage{})
+	assert.Nil(t, err, "expected deadlines to not depend on the clocks of peers being in sync")
+	assert.Equal(t, "reply", response.(*protobuf.TestMessage).Message)
+
+	assert.True(t, <-server.deadlines)
+	assert.Nil(t, <-server.replies)
+}
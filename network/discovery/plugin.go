@@ -2,12 +2,31 @@ package discovery
 
 import (
 	"context"
+	"encoding/json"
+	"sync"
+	"time"
 
 	"github.com/perlin-network/noise/dht"
 	"github.com/perlin-network/noise/internal/protobuf"
-	"github.com/perlin-network/noise/log"
 	"github.com/perlin-network/noise/network"
 	"github.com/perlin-network/noise/peer"
+	"github.com/pkg/errors"
+)
+
+const (
+	defaultDisjointPaths = 8
+
+	// DefaultMaxFailuresBeforeBan is the default number of malformed or invalid messages
+	// tolerated from a peer before it is banned.
+	DefaultMaxFailuresBeforeBan = 5
+
+	// DefaultMaxRevocationAge is the default age past which key revocations are no longer
+	// accepted.
+	DefaultMaxRevocationAge = 24 * time.Hour
+
+	// maxRevocationClockSkew is how far ahead of the clock of the node key revocations
+	// may be timestamped.
+	maxRevocationClockSkew = time.Minute
 )
 
 type Plugin struct {
@@ -17,20 +36,185 @@ type Plugin struct {
 	DisablePong   bool
 	DisableLookup bool
 
+	// DisableBootstrap prevents pongs and refreshes from populating the routing table
+	// with lookups, making the node a passive bootstrap seed.
+	DisableBootstrap bool
+
+	// Alpha is the number of peers queried at a time per lookup (default: dht.BucketSize).
+	Alpha int
+	// BucketSize is the maximum number of peers held per routing table bucket (default: dht.BucketSize).
+	BucketSize int
+	// RefreshInterval is how often the routing table is refreshed with a lookup
+	// of this nodes own ID. Disabled if zero.
+	RefreshInterval time.Duration
+
+	// EnforceSkademliaNodeIDs ignores peers whose public keys do not solve the
+	// S/Kademlia static cryptographic puzzle.
+	EnforceSkademliaNodeIDs bool
+	// CryptopuzzleDifficulty is the number of leading zero bits peers must solve the
+	// static cryptographic puzzle with (default: peer.DefaultCryptopuzzleDifficulty).
+	CryptopuzzleDifficulty int
+
+	// MaxFailuresBeforeBan is the number of malformed or invalid messages tolerated from a
+	// peer before all further messages from it are dropped (default: DefaultMaxFailuresBeforeBan).
+	MaxFailuresBeforeBan int
+
+	// MaxRevocationAge is the age past which key revocations are no longer accepted
+	// (default: DefaultMaxRevocationAge).
+	MaxRevocationAge time.Duration
+
 	Routes *dht.RoutingTable
+
+	// failures counts malformed or invalid messages received per peer public key hex.
+	failures      map[string]int
+	failuresMutex sync.Mutex
+
+	// banned holds the public key hex of banned peers.
+	banned sync.Map
+
+	// revoked holds the public key hex of revoked keys.
+	revoked sync.Map
+
+	// restored holds the peers restored through Deserialize, which are added to the
+	// routing table upon startup.
+	restored []peer.ID
+
+	// startMutex guards the routing table being replaced against the plugin starting up.
+	startMutex sync.Mutex
+	started    bool
+
+	stop chan struct{}
 }
 
+var (
+	// ErrRoutesAlreadyStarted returns if the routing table is replaced after the plugin has started up.
+	ErrRoutesAlreadyStarted = errors.New("discovery: cannot set routing table after startup")
+
+	// ErrNoRoute returns if the routing table holds no peers to route a request through.
+	ErrNoRoute = errors.New("discovery: no known peers to route request through")
+)
+
 var (
 	PluginID                         = (*Plugin)(nil)
 	_        network.PluginInterface = (*Plugin)(nil)
+	_        network.Configurable    = (*Plugin)(nil)
+	_        network.PeerLookup      = (*Plugin)(nil)
+	_        network.BucketReporter  = (*Plugin)(nil)
+	_        network.StatefulPlugin  = (*Plugin)(nil)
 )
 
+// Configure reads the `alpha`, `bucket_size` and `refresh_interval` options.
+//
+// `refresh_interval` may either be a duration string (e.g. "30s") or a number of seconds.
+func (state *Plugin) Configure(cfg map[string]interface{}) error {
+	if raw, ok := cfg["alpha"]; ok {
+		alpha, ok := number(raw)
+		if !ok || alpha <= 0 {
+			return errors.Errorf("discovery: alpha must be a positive number, got %v", raw)
+		}
+		state.Alpha = int(alpha)
+	}
+
+	if raw, ok := cfg["bucket_size"]; ok {
+		bucketSize, ok := number(raw)
+		if !ok || bucketSize <= 0 {
+			return errors.Errorf("discovery: bucket_size must be a positive number, got %v", raw)
+		}
+		state.BucketSize = int(bucketSize)
+	}
+
+	if raw, ok := cfg["refresh_interval"]; ok {
+		if seconds, ok := number(raw); ok {
+			raw = seconds
+		}
+
+		switch interval := raw.(type) {
+		case float64:
+			state.RefreshInterval = time.Duration(interval * float64(time.Second))
+		case string:
+			d, err := time.ParseDuration(interval)
+			if err != nil {
+				return errors.Wrap(err, "discovery: invalid refresh_interval")
+			}
+			state.RefreshInterval = d
+		default:
+			return errors.Errorf("discovery: refresh_interval must be a duration, got %v", raw)
+		}
+	}
+
+	return nil
+}
+
+// number converts a config value to a float64, accepting both numbers decoded from JSON
+// and integers set in code.
+func number(raw interface{}) (float64, bool) {
+	switch n := raw.(type) {
+	case float64:
+		return n, true
+	case int:
+		return float64(n), true
+	case int64:
+		return float64(n), true
+	default:
+		return 0, false
+	}
+}
+
+// SetRoutes replaces the routing table the plugin starts up with, e.g. with a
+// pre-populated table loaded from disk. Errors should the plugin have already
+// started up, as the routing table may be concurrently read while handling messages.
+func (state *Plugin) SetRoutes(rt *dht.RoutingTable) error {
+	state.startMutex.Lock()
+	defer state.startMutex.Unlock()
+
+	if state.started {
+		return ErrRoutesAlreadyStarted
+	}
+
+	state.Routes = rt
+	return nil
+}
+
 func (state *Plugin) Startup(net *network.Network) {
-	// Create routing table.
-	state.Routes = dht.CreateRoutingTable(net.ID)
+	state.startMutex.Lock()
+	defer state.startMutex.Unlock()
+
+	state.started = true
+
+	// Create routing table should one not have been set.
+	if state.Routes == nil {
+		state.Routes = dht.CreateRoutingTableWithBucketSize(net.SelfID(), state.BucketSize)
+		state.Routes.SetLogger(net.PluginLogger(state))
+	}
+
+	if len(state.restored) > 0 {
+		state.Routes.UpdateMany(state.restored)
+		state.restored = nil
+	}
+
+	if state.RefreshInterval > 0 && !state.DisableBootstrap {
+		state.stop = make(chan struct{})
+		go state.refreshLoop(net, state.stop)
+	}
 }
 
 func (state *Plugin) Receive(ctx *network.PluginContext) error {
+	// Drop messages from banned peers.
+	if state.IsBanned(ctx.Sender().PublicKeyHex()) {
+		return nil
+	}
+
+	// Drop messages from peers whose key has been revoked.
+	if state.IsRevoked(ctx.Sender().PublicKeyHex()) {
+		return nil
+	}
+
+	// Ignore peers which have not solved the cryptographic puzzle.
+	if !state.isValidPeer(ctx.Sender()) {
+		state.recordFailure(ctx.Network(), ctx.Sender())
+		return nil
+	}
+
 	// Update routing for every incoming message.
 	state.Routes.Update(ctx.Sender())
 	gCtx := network.WithSignMessage(context.Background(), true)
@@ -38,29 +222,32 @@ func (state *Plugin) Receive(ctx *network.PluginContext) error {
 	// Handle RPC.
 	switch msg := ctx.Message().(type) {
 	case *protobuf.Ping:
+		ctx.Client().SetCapabilities(network.Capabilities(msg.Capabilities))
+
 		if state.DisablePing {
 			break
 		}
 
 		// Send pong to peer.
-		err := ctx.Reply(gCtx, &protobuf.Pong{})
+		err := ctx.Reply(gCtx, &protobuf.Pong{Capabilities: uint64(ctx.Network().Capabilities())})
 
 		if err != nil {
 			return err
 		}
 	case *protobuf.Pong:
-		if state.DisablePong {
+		ctx.Client().SetCapabilities(network.Capabilities(msg.Capabilities))
+
+		if state.DisablePong || state.DisableBootstrap {
 			break
 		}
 
-		peers := FindNode(ctx.Network(), ctx.Sender(), dht.BucketSize, 8)
+		peers := findNode(ctx.Network(), state.Routes, ctx.Sender(), state.alpha(), defaultDisjointPaths)
 
 		// Update routing table w/ closest peers to self.
-		for _, peerID := range peers {
-			state.Routes.Update(peerID)
-		}
+		state.Routes.UpdateMany(state.filterValidPeers(peers))
 
-		log.Info().
+		logger := ctx.Logger()
+		logger.Info().
 			Strs("peers", state.Routes.GetPeerAddresses()).
 			Msg("Bootstrapped w/ peer(s).")
 	case *protobuf.LookupNodeRequest:
@@ -68,11 +255,16 @@ func (state *Plugin) Receive(ctx *network.PluginContext) error {
 			break
 		}
 
+		if msg.Target == nil {
+			state.recordFailure(ctx.Network(), ctx.Sender())
+			break
+		}
+
 		// Prepare response.
 		response := &protobuf.LookupNodeResponse{}
 
 		// Respond back with closest peers to a provided target.
-		for _, peerID := range state.Routes.FindClosestPeers(peer.ID(*msg.Target), dht.BucketSize) {
+		for _, peerID := range state.Routes.FindClosestPeers(peer.ID(*msg.Target), state.Routes.BucketSize()) {
 			id := protobuf.ID(peerID)
 			response.Peers = append(response.Peers, &id)
 		}
@@ -82,28 +274,231 @@ func (state *Plugin) Receive(ctx *network.PluginContext) error {
 			return err
 		}
 
-		log.Info().
+		logger := ctx.Logger()
+		logger.Info().
 			Strs("peers", state.Routes.GetPeerAddresses()).
 			Msg("Connected to peer(s).")
+	case *protobuf.KeyRevocation:
+		return state.handleRevocation(ctx, msg)
 	}
 
 	return nil
 }
 
 func (state *Plugin) Cleanup(net *network.Network) {
-	// TODO: Save routing table?
+	if state.stop != nil {
+		close(state.stop)
+		state.stop = nil
+	}
+}
+
+// Serialize encodes the peers of the routing table, such that they may be restored
+// through Deserialize.
+func (state *Plugin) Serialize() ([]byte, error) {
+	peers := []peer.ID{}
+	if state.Routes != nil {
+		peers = append(peers, state.Routes.GetPeers()...)
+	}
+
+	return json.Marshal(peers)
+}
+
+// Deserialize restores peers encoded by Serialize, which are added to the routing table
+// upon startup. Errors should the plugin have already started up.
+func (state *Plugin) Deserialize(data []byte) error {
+	state.startMutex.Lock()
+	defer state.startMutex.Unlock()
+
+	if state.started {
+		return ErrRoutesAlreadyStarted
+	}
+
+	var peers []peer.ID
+	if err := json.Unmarshal(data, &peers); err != nil {
+		return errors.Wrap(err, "discovery: failed to decode routing table")
+	}
+
+	state.restored = peers
+	return nil
 }
 
 func (state *Plugin) PeerDisconnect(client *network.PeerClient) {
 	// Delete peer if in routing table.
-	if client.ID != nil {
-		if state.Routes.PeerExists(*client.ID) {
-			state.Routes.RemovePeer(*client.ID)
+	if id := client.PeerID(); id != nil {
+		if state.Routes.PeerExists(*id) {
+			state.Routes.RemovePeer(*id)
 
-			log.Debug().
-				Str("address", client.Network.ID.Address).
-				Str("peer_address", client.ID.Address).
+			logger := client.Network.PluginLogger(state)
+			logger.Debug().
+				Str("address", client.Network.SelfAddress()).
+				Str("peer_address", id.Address).
 				Msg("Peer has disconnected.")
 		}
 	}
 }
+
+// Lookup performs an iterative lookup of the peers closest to a target ID.
+func (state *Plugin) Lookup(net *network.Network, target peer.ID, count int) []peer.ID {
+	var results []peer.ID
+
+	visited := make(map[string]struct{})
+
+	for _, id := range findNode(net, state.Routes, target, state.alpha(), defaultDisjointPaths) {
+		if len(results) == count {
+			break
+		}
+
+		if _, seen := visited[id.PublicKeyHex()]; !seen {
+			visited[id.PublicKeyHex()] = struct{}{}
+			results = append(results, id)
+		}
+	}
+
+	logger := net.PluginLogger(state)
+	logger.Debug().
+		Str("target", target.Address).
+		Int("results", len(results)).
+		Msg("Looked up peers.")
+
+	return results
+}
+
+// BucketDistribution returns the number of peers held within each bucket of the routing
+// table. Returns nil should the plugin not have started up yet.
+func (state *Plugin) BucketDistribution() []int {
+	if state.Routes == nil {
+		return nil
+	}
+	return state.Routes.BucketDistribution()
+}
+
+// RouteRequest returns the closest known peer to a target ID from the routing table,
+// without performing a lookup. Errors should the routing table hold no peers.
+func (state *Plugin) RouteRequest(target peer.ID) (peer.ID, error) {
+	if state.Routes == nil {
+		return peer.ID{}, ErrNoRoute
+	}
+
+	self := state.Routes.Self()
+
+	// The routing table holds this node, which is never a next hop.
+	for _, id := range state.Routes.FindClosestPeers(target, 2) {
+		if !id.Equals(self) {
+			return id, nil
+		}
+	}
+
+	return peer.ID{}, ErrNoRoute
+}
+
+// refreshLoop periodically looks up this nodes own ID to populate the routing table,
+// as timed by the networks clock.
+func (state *Plugin) refreshLoop(net *network.Network, stop chan struct{}) {
+	ticker := net.Clock().NewTicker(state.RefreshInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-stop:
+			return
+		case <-ticker.C():
+			state.Routes.UpdateMany(state.filterValidPeers(findNode(net, state.Routes, net.SelfID(), state.alpha(), defaultDisjointPaths)))
+		}
+	}
+}
+
+func (state *Plugin) alpha() int {
+	if state.Alpha > 0 {
+		return state.Alpha
+	}
+	return dht.BucketSize
+}
+
+func (state *Plugin) difficulty() int {
+	if state.CryptopuzzleDifficulty > 0 {
+		return state.CryptopuzzleDifficulty
+	}
+	return peer.DefaultCryptopuzzleDifficulty
+}
+
+// isValidPeer checks whether a peers public key solves the static cryptographic puzzle,
+// should S/Kademlia node IDs be enforced.
+func (state *Plugin) isValidPeer(id peer.ID) bool {
+	return !state.EnforceSkademliaNodeIDs || peer.IsValidKeyPair(id.PublicKey, state.difficulty())
+}
+
+// filterValidPeers drops peers which are banned, whose keys have been revoked, or whose
+// public keys do not solve the static cryptographic puzzle.
+func (state *Plugin) filterValidPeers(peers []peer.ID) []peer.ID {
+	valid := peers[:0]
+	for _, id := range peers {
+		if state.isValidPeer(id) && !state.IsBanned(id.PublicKeyHex()) && !state.IsRevoked(id.PublicKeyHex()) {
+			valid = append(valid, id)
+		}
+	}
+	return valid
+}
+
+func (state *Plugin) maxRevocationAge() time.Duration {
+	if state.MaxRevocationAge > 0 {
+		return state.MaxRevocationAge
+	}
+	return DefaultMaxRevocationAge
+}
+
+func (state *Plugin) maxFailuresBeforeBan() int {
+	if state.MaxFailuresBeforeBan > 0 {
+		return state.MaxFailuresBeforeBan
+	}
+	return DefaultMaxFailuresBeforeBan
+}
+
+// recordFailure counts a malformed or invalid message received from a peer, banning
+// and removing the peer from the routing table should it exceed the maximum number
+// of failures tolerated.
+func (state *Plugin) recordFailure(net *network.Network, id peer.ID) {
+	key := id.PublicKeyHex()
+
+	state.failuresMutex.Lock()
+	if state.failures == nil {
+		state.failures = make(map[string]int)
+	}
+	state.failures[key]++
+	failures := state.failures[key]
+	if failures > state.maxFailuresBeforeBan() {
+		delete(state.failures, key)
+	}
+	state.failuresMutex.Unlock()
+
+	if failures <= state.maxFailuresBeforeBan() {
+		return
+	}
+
+	state.banned.Store(key, struct{}{})
+
+	if state.Routes.PeerExists(id) {
+		state.Routes.RemovePeer(id)
+	}
+
+	logger := net.PluginLogger(state)
+	logger.Warn().
+		Str("peer_address", id.Address).
+		Str("peer_public_key", key).
+		Int("failures", failures).
+		Msg("Banned peer for sending malformed or invalid messages.")
+}
+
+// IsBanned returns true if a peer denoted by its public key hex has been banned.
+func (state *Plugin) IsBanned(pubKeyHex string) bool {
+	_, banned := state.banned.Load(pubKeyHex)
+	return banned
+}
+
+// UnbanPeer pardons a banned peer denoted by its public key hex, and resets its failures.
+func (state *Plugin) UnbanPeer(pubKeyHex string) {
+	state.banned.Delete(pubKeyHex)
+
+	state.failuresMutex.Lock()
+	delete(state.failures, pubKeyHex)
+	state.failuresMutex.Unlock()
+}
@@ -0,0 +1,918 @@
+package discovery_test
+
+import (
+	"bytes"
+	"compress/flate"
+	"context"
+	"crypto/rand"
+	"encoding/json"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/perlin-network/noise/clock"
+	"github.com/perlin-network/noise/crypto"
+	"github.com/perlin-network/noise/crypto/ed25519"
+	"github.com/perlin-network/noise/dht"
+	"github.com/perlin-network/noise/internal/protobuf"
+	"github.com/perlin-network/noise/log"
+	"github.com/perlin-network/noise/network"
+	"github.com/perlin-network/noise/network/discovery"
+	"github.com/perlin-network/noise/peer"
+	"github.com/perlin-network/noise/testutil"
+	"github.com/perlin-network/noise/types/opcode"
+
+	"github.com/gogo/protobuf/proto"
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestConfigure(t *testing.T) {
+	t.Parallel()
+
+	var config map[string]interface{}
+	err := json.Unmarshal([]byte(`{"discovery": {"alpha": 3, "bucket_size": 20, "refresh_interval": "1m"}}`), &config)
+	assert.Nil(t, err)
+
+	cluster, err := testutil.NewCluster(1, testutil.WithSetup(func(_ int, builder *network.Builder) {
+		builder.SetConfig(config)
+	}))
+	assert.Nil(t, err)
+	defer cluster.Stop()
+
+	plugin := cluster.Plugin(0)
+
+	assert.Equal(t, 3, plugin.Alpha)
+	assert.Equal(t, time.Minute, plugin.RefreshInterval)
+	assert.Equal(t, 20, plugin.Routes.BucketSize())
+}
+
+func TestConfigureInts(t *testing.T) {
+	t.Parallel()
+
+	plugin := new(discovery.Plugin)
+	assert.Nil(t, plugin.Configure(map[string]interface{}{"alpha": 3, "bucket_size": 20, "refresh_interval": 60}))
+
+	assert.Equal(t, 3, plugin.Alpha)
+	assert.Equal(t, 20, plugin.BucketSize)
+	assert.Equal(t, time.Minute, plugin.RefreshInterval)
+}
+
+func TestConfigureInvalid(t *testing.T) {
+	t.Parallel()
+
+	builder := network.NewBuilder()
+	builder.SetConfig(map[string]interface{}{
+		"discovery": map[string]interface{}{"bucket_size": "twenty"},
+	})
+	builder.AddPlugin(new(discovery.Plugin))
+
+	_, err := builder.Build()
+	assert.NotNil(t, err)
+}
+
+func TestSetRoutes(t *testing.T) {
+	t.Parallel()
+
+	keys := ed25519.RandomKeyPair()
+	pipes := testutil.NewPipeTransport()
+	address := pipes.NewAddress()
+
+	routes := dht.CreateRoutingTable(peer.CreateID(address, keys.PublicKey))
+	other := peer.CreateID("tcp://127.0.0.1:1", ed25519.RandomKeyPair().PublicKey)
+	routes.Update(other)
+
+	plugin := new(discovery.Plugin)
+	assert.Nil(t, plugin.SetRoutes(routes))
+
+	builder := network.NewBuilder()
+	builder.SetKeys(keys)
+	builder.SetAddress(address)
+	builder.RegisterTransportLayer(testutil.PipeProtocol, pipes)
+	builder.AddPlugin(plugin)
+
+	node, err := builder.Build()
+	assert.Nil(t, err)
+
+	go node.Listen()
+	defer node.Close()
+
+	node.BlockUntilListening()
+
+	assert.True(t, routes == plugin.Routes, "expected injected routing table to be kept on startup")
+	assert.True(t, plugin.Routes.PeerExists(other))
+
+	assert.Equal(t, discovery.ErrRoutesAlreadyStarted, plugin.SetRoutes(dht.CreateRoutingTable(node.ID)))
+}
+
+func TestStatePersistence(t *testing.T) {
+	t.Parallel()
+
+	dir, err := ioutil.TempDir("", "noise-state")
+	assert.Nil(t, err)
+	defer os.RemoveAll(dir)
+
+	pipes := testutil.NewPipeTransport()
+
+	newNode := func(keys *crypto.KeyPair, address string, opts ...network.BuilderOption) (*network.Network, *discovery.Plugin) {
+		plugin := new(discovery.Plugin)
+
+		builder := network.NewBuilderWithOptions(opts...)
+		builder.SetKeys(keys)
+		builder.SetAddress(address)
+		builder.RegisterTransportLayer(testutil.PipeProtocol, pipes)
+		builder.AddPlugin(plugin)
+
+		node, err := builder.Build()
+		assert.Nil(t, err)
+
+		go node.Listen()
+		node.BlockUntilListening()
+
+		return node, plugin
+	}
+
+	keys, address := ed25519.RandomKeyPair(), pipes.NewAddress()
+
+	alice, plugin := newNode(keys, address, network.WithStateDir(dir))
+
+	bob, bobPlugin := newNode(ed25519.RandomKeyPair(), pipes.NewAddress())
+	defer bob.Close()
+
+	bob.Bootstrap(alice.Address)
+
+	deadline := time.Now().Add(3 * time.Second)
+	for !plugin.Routes.PeerExists(bob.ID) || !bobPlugin.Routes.PeerExists(alice.ID) {
+		if time.Now().After(deadline) {
+			t.Fatal("timed out waiting for alice and bob to route one another")
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	assert.Nil(t, alice.CloseWithDrain(time.Second))
+
+	_, err = os.Stat(filepath.Join(dir, "discovery.state"))
+	assert.Nil(t, err, "expected routing table to be persisted")
+
+	// Restarting has the routing table restored before any peer is bootstrapped off of.
+	restarted, plugin := newNode(keys, pipes.NewAddress(), network.WithStateDir(dir))
+	defer restarted.Close()
+
+	assert.True(t, plugin.Routes.PeerExists(bob.ID), "expected bob to be restored into the routing table")
+	assert.Equal(t, discovery.ErrRoutesAlreadyStarted, plugin.Deserialize([]byte("[]")))
+}
+
+func TestDisableBootstrap(t *testing.T) {
+	t.Parallel()
+
+	cluster, err := testutil.NewCluster(3, testutil.WithoutBootstrap(), testutil.WithDiscovery(func(i int) *discovery.Plugin {
+		return &discovery.Plugin{DisableBootstrap: i == 0}
+	}))
+	assert.Nil(t, err)
+	defer cluster.Stop()
+
+	seed, bootstrap, other := cluster.Node(0), cluster.Node(1), cluster.Node(2)
+
+	other.Bootstrap(bootstrap.Address)
+
+	deadline := time.Now().Add(3 * time.Second)
+	for !cluster.Plugin(2).Routes.PeerExists(bootstrap.ID) {
+		if time.Now().After(deadline) {
+			t.Fatal("expected node to be bootstrapped")
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	// Wait for the bootstrapped nodes lookup to complete such that it does not
+	// contact the seed.
+	time.Sleep(250 * time.Millisecond)
+	assert.True(t, cluster.Plugin(1).Routes.PeerExists(other.ID))
+
+	seed.Bootstrap(bootstrap.Address)
+
+	for !cluster.Plugin(0).Routes.PeerExists(bootstrap.ID) {
+		if time.Now().After(deadline) {
+			t.Fatal("expected seed to track the peer it pinged")
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	// Give the seed time to handle the pong.
+	time.Sleep(250 * time.Millisecond)
+
+	assert.False(t, cluster.Plugin(0).Routes.PeerExists(other.ID), "expected seed to not look up peers off of a pong")
+	assert.Equal(t, 1, len(cluster.Plugin(0).Routes.GetPeers()))
+}
+
+// lookupCounter counts the lookup requests a network receives.
+type lookupCounter struct {
+	*network.Plugin
+
+	lookups int64
+}
+
+func (p *lookupCounter) Receive(ctx *network.PluginContext) error {
+	if _, ok := ctx.Message().(*protobuf.LookupNodeRequest); ok {
+		atomic.AddInt64(&p.lookups, 1)
+	}
+	return nil
+}
+
+func TestRefreshInterval(t *testing.T) {
+	t.Parallel()
+
+	fake := clock.NewFakeClock(time.Now())
+	counter := new(lookupCounter)
+
+	cluster, err := testutil.NewCluster(3,
+		testutil.WithBuilderOptions(network.WithClock(fake)),
+		testutil.WithDiscovery(func(int) *discovery.Plugin {
+			return &discovery.Plugin{RefreshInterval: time.Hour}
+		}),
+		testutil.WithSetup(func(i int, builder *network.Builder) {
+			if i == 0 {
+				builder.AddPlugin(counter)
+			}
+		}),
+	)
+	assert.Nil(t, err)
+	defer cluster.Stop()
+
+	// Wait for lookups made upon bootstrapping to complete.
+	time.Sleep(250 * time.Millisecond)
+	bootstrapped := atomic.LoadInt64(&counter.lookups)
+
+	fake.BlockUntil(3)
+
+	time.Sleep(250 * time.Millisecond)
+	assert.Equal(t, bootstrapped, atomic.LoadInt64(&counter.lookups), "expected no refresh before the clock is advanced")
+
+	fake.Advance(time.Hour)
+
+	deadline := time.Now().Add(3 * time.Second)
+	for atomic.LoadInt64(&counter.lookups) < bootstrapped+2 {
+		if time.Now().After(deadline) {
+			t.Fatal("expected every peer to refresh its routing table off of the seed")
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	assert.Equal(t, 3, fake.Waiters(), "expected refreshes to not leak waiters on the clock")
+}
+
+func TestEnforceSkademliaNodeIDs(t *testing.T) {
+	t.Parallel()
+
+	const difficulty = 4
+
+	validKeys, _ := peer.GenerateKeyPairAndID("", difficulty)
+
+	invalidKeys := ed25519.RandomKeyPair()
+	for peer.IsValidKeyPair(invalidKeys.PublicKey, difficulty) {
+		invalidKeys = ed25519.RandomKeyPair()
+	}
+
+	keys := []*crypto.KeyPair{validKeys, validKeys, invalidKeys}
+
+	cluster, err := testutil.NewCluster(3,
+		testutil.WithoutBootstrap(),
+		testutil.WithSetup(func(i int, builder *network.Builder) { builder.SetKeys(keys[i]) }),
+		testutil.WithDiscovery(func(i int) *discovery.Plugin {
+			return &discovery.Plugin{EnforceSkademliaNodeIDs: i == 0, CryptopuzzleDifficulty: difficulty}
+		}),
+	)
+	assert.Nil(t, err)
+	defer cluster.Stop()
+
+	cluster.Node(1).Bootstrap(cluster.Node(0).Address)
+	cluster.Node(2).Bootstrap(cluster.Node(0).Address)
+
+	plugin := cluster.Plugin(0)
+
+	deadline := time.Now().Add(3 * time.Second)
+	for !plugin.Routes.PeerExists(cluster.Node(1).ID) {
+		if time.Now().After(deadline) {
+			t.Fatal("expected peer which solved the cryptopuzzle to be routed")
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	// Give the enforcing node time to handle the invalid peers ping.
+	time.Sleep(250 * time.Millisecond)
+
+	assert.False(t, plugin.Routes.PeerExists(cluster.Node(2).ID), "expected peer which did not solve the cryptopuzzle to be ignored")
+}
+
+func TestBanPeer(t *testing.T) {
+	t.Parallel()
+
+	// Only the receiver tracks peers, such that the sender does not reply to anything.
+	cluster, err := testutil.NewCluster(2, testutil.WithoutBootstrap(), testutil.WithDiscovery(func(i int) *discovery.Plugin {
+		if i == 0 {
+			return new(discovery.Plugin)
+		}
+		return nil
+	}))
+	assert.Nil(t, err)
+	defer cluster.Stop()
+
+	plugin := cluster.Plugin(0)
+	receiver, sender := cluster.Node(0), cluster.Node(1)
+
+	client, err := sender.Client(receiver.Address)
+	assert.Nil(t, err)
+
+	tell := func(message proto.Message) {
+		assert.Nil(t, client.Tell(context.Background(), message))
+		time.Sleep(50 * time.Millisecond)
+	}
+
+	waitUntil := func(condition func() bool, msg string) {
+		t.Helper()
+
+		deadline := time.Now().Add(3 * time.Second)
+		for !condition() {
+			if time.Now().After(deadline) {
+				t.Fatal(msg)
+			}
+			time.Sleep(10 * time.Millisecond)
+		}
+	}
+
+	tell(&protobuf.Ping{})
+	waitUntil(func() bool { return plugin.Routes.PeerExists(sender.ID) }, "expected peer to be routed")
+
+	// Lookup requests without a target are malformed.
+	for i := 0; i < discovery.DefaultMaxFailuresBeforeBan; i++ {
+		tell(&protobuf.LookupNodeRequest{})
+	}
+
+	time.Sleep(250 * time.Millisecond)
+	assert.False(t, plugin.IsBanned(sender.ID.PublicKeyHex()), "expected peer to not be banned before exceeding the maximum failures")
+
+	tell(&protobuf.LookupNodeRequest{})
+
+	waitUntil(func() bool { return plugin.IsBanned(sender.ID.PublicKeyHex()) }, "expected peer to be banned")
+	assert.False(t, plugin.Routes.PeerExists(sender.ID), "expected banned peer to be removed from the routing table")
+
+	// Messages from banned peers are dropped.
+	tell(&protobuf.Ping{})
+	time.Sleep(250 * time.Millisecond)
+	assert.False(t, plugin.Routes.PeerExists(sender.ID), "expected messages from banned peer to be dropped")
+
+	plugin.UnbanPeer(sender.ID.PublicKeyHex())
+	assert.False(t, plugin.IsBanned(sender.ID.PublicKeyHex()))
+
+	tell(&protobuf.Ping{})
+	waitUntil(func() bool { return plugin.Routes.PeerExists(sender.ID) }, "expected pardoned peer to be routed")
+}
+
+func TestDenySender(t *testing.T) {
+	t.Parallel()
+
+	// Only the receiver tracks peers, such that the senders do not reply to anything.
+	cluster, err := testutil.NewCluster(3, testutil.WithoutBootstrap(), testutil.WithDiscovery(func(i int) *discovery.Plugin {
+		if i == 0 {
+			return new(discovery.Plugin)
+		}
+		return nil
+	}))
+	assert.Nil(t, err)
+	defer cluster.Stop()
+
+	plugin := cluster.Plugin(0)
+	receiver, denied, allowed := cluster.Node(0), cluster.Node(1), cluster.Node(2)
+
+	assert.Nil(t, receiver.DenySender(discovery.PluginID, denied.ID.PublicKey))
+
+	deniedClient, err := denied.Client(receiver.Address)
+	assert.Nil(t, err)
+
+	allowedClient, err := allowed.Client(receiver.Address)
+	assert.Nil(t, err)
+
+	assert.Nil(t, deniedClient.Tell(context.Background(), &protobuf.Ping{}))
+	assert.Nil(t, allowedClient.Tell(context.Background(), &protobuf.Ping{}))
+
+	// The allowed peer is routed, and replied to.
+	replied := func() bool {
+		_, ok := allowed.GetPeer(receiver.ID.Id)
+		return ok
+	}
+
+	deadline := time.Now().Add(3 * time.Second)
+	for !plugin.Routes.PeerExists(allowed.ID) || !replied() {
+		if time.Now().After(deadline) {
+			t.Fatal("expected allowed peer to be routed")
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	assert.Nil(t, deniedClient.Tell(context.Background(), &protobuf.Ping{}))
+	time.Sleep(250 * time.Millisecond)
+
+	assert.False(t, plugin.Routes.PeerExists(denied.ID), "expected pings from denied peer to not be dispatched to the plugin")
+}
+
+func TestRouteRequest(t *testing.T) {
+	t.Parallel()
+
+	ids, tables := dht.FullyConnectedGraph(32, rand.Reader)
+
+	plugin := new(discovery.Plugin)
+
+	_, err := plugin.RouteRequest(ids[1])
+	assert.Equal(t, discovery.ErrNoRoute, err)
+
+	assert.Nil(t, plugin.SetRoutes(tables[0]))
+
+	// Known peers are routed to directly.
+	for _, id := range ids[1:] {
+		nextHop, err := plugin.RouteRequest(id)
+		assert.Nil(t, err)
+		assert.True(t, nextHop.Equals(id), "expected known peer to be its own next hop")
+	}
+
+	// Unknown peers are routed through the closest known peer.
+	target := peer.CreateID("target", ed25519.RandomKeyPair().PublicKey)
+
+	nextHop, err := plugin.RouteRequest(target)
+	assert.Nil(t, err)
+
+	closest := ids[1]
+	for _, id := range ids[1:] {
+		if id.XorID(target).Less(closest.XorID(target)) {
+			closest = id
+		}
+	}
+	assert.True(t, nextHop.Equals(closest), "expected next hop to be the closest known peer")
+
+	// Routing tables holding only this node have no route.
+	empty := new(discovery.Plugin)
+	assert.Nil(t, empty.SetRoutes(dht.CreateRoutingTable(ids[0])))
+
+	_, err = empty.RouteRequest(target)
+	assert.Equal(t, discovery.ErrNoRoute, err)
+}
+
+// syncBuffer is a bytes.Buffer safe for concurrent use.
+type syncBuffer struct {
+	sync.Mutex
+	buf bytes.Buffer
+}
+
+func (b *syncBuffer) Write(p []byte) (int, error) {
+	b.Lock()
+	defer b.Unlock()
+	return b.buf.Write(p)
+}
+
+func (b *syncBuffer) String() string {
+	b.Lock()
+	defer b.Unlock()
+	return b.buf.String()
+}
+
+func TestPluginLogger(t *testing.T) {
+	t.Parallel()
+
+	output := new(syncBuffer)
+
+	// Bootstrapping has the bootstrapped node reply with a pong, which is logged.
+	cluster, err := testutil.NewCluster(2, testutil.WithBuilderOptions(network.WithLogger(discovery.PluginID, zerolog.New(output))))
+	assert.Nil(t, err)
+	defer cluster.Stop()
+
+	deadline := time.Now().Add(3 * time.Second)
+	for !strings.Contains(output.String(), "Bootstrapped w/ peer(s).") {
+		if time.Now().After(deadline) {
+			t.Fatalf("expected pong to be logged to the plugins logger, got %q", output.String())
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
+
+func TestNetworkLoggers(t *testing.T) {
+	t.Parallel()
+
+	cluster := testutil.NewPartitionableNetwork()
+	defer cluster.Close()
+
+	outputs := []*syncBuffer{new(syncBuffer), new(syncBuffer)}
+
+	for _, output := range outputs {
+		builder := cluster.NewBuilder(network.WithZerologLogger(zerolog.New(output)))
+		assert.Nil(t, builder.AddPlugin(new(discovery.Plugin)))
+
+		_, err := cluster.Add(builder)
+		assert.Nil(t, err)
+	}
+
+	cluster.Node(1).Bootstrap(cluster.Node(0).Address)
+
+	// Bootstrapping has the bootstrapped node reply with a pong, which is logged.
+	deadline := time.Now().Add(3 * time.Second)
+	for !strings.Contains(outputs[1].String(), "Bootstrapped w/ peer(s).") {
+		if time.Now().After(deadline) {
+			t.Fatalf("expected pong to be logged to the networks logger, got %q", outputs[1].String())
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	// Every network logs the addresses it listens on upon starting up.
+	for i, output := range outputs {
+		var listening [][]string
+
+		for _, line := range strings.Split(strings.TrimSpace(output.String()), "\n") {
+			var entry struct {
+				Message   string   `json:"message"`
+				Addresses []string `json:"addresses"`
+			}
+			assert.Nil(t, json.Unmarshal([]byte(line), &entry))
+
+			if entry.Message == "Listening for peers." {
+				listening = append(listening, entry.Addresses)
+			}
+		}
+
+		assert.Equal(t, [][]string{{cluster.Node(i).Address}}, listening, "expected network %d to only log its own startup", i)
+	}
+
+	assert.NotContains(t, outputs[0].String(), "Bootstrapped w/ peer(s).", "expected the pong to only be logged by the network receiving it")
+}
+
+// TestComponentLevels is not run in parallel, as it captures the output of the global logger.
+func TestComponentLevels(t *testing.T) {
+	output := new(syncBuffer)
+
+	log.SetOutput(output)
+	defer log.SetOutput(os.Stderr)
+
+	log.SetComponentLevel("discovery", zerolog.DebugLevel)
+	log.SetComponentLevel("connection", zerolog.ErrorLevel)
+
+	cluster, err := testutil.NewCluster(2)
+	assert.Nil(t, err)
+	defer cluster.Stop()
+
+	cluster.Plugin(1).Lookup(cluster.Node(1), cluster.Node(0).ID, 1)
+
+	levels := make(map[string]map[string]int)
+
+	for _, line := range strings.Split(strings.TrimSpace(output.String()), "\n") {
+		var entry struct {
+			Level     string `json:"level"`
+			Component string `json:"component"`
+		}
+
+		if err := json.Unmarshal([]byte(line), &entry); err != nil {
+			continue
+		}
+
+		if levels[entry.Component] == nil {
+			levels[entry.Component] = make(map[string]int)
+		}
+		levels[entry.Component][entry.Level]++
+	}
+
+	assert.True(t, levels["discovery"]["debug"] > 0, "expected debug logs of discovery, got %q", output.String())
+	assert.Equal(t, 0, levels["connection"]["debug"], "expected no debug logs of connections")
+	assert.Equal(t, 0, levels["connection"]["info"], "expected no info logs of connections")
+}
+
+func TestTopologySnapshot(t *testing.T) {
+	t.Parallel()
+
+	cluster, err := testutil.NewCluster(5)
+	assert.Nil(t, err)
+	defer cluster.Stop()
+
+	bootstrap, nodes := cluster.Node(0), cluster.Nodes()
+
+	client, err := bootstrap.Client(nodes[1].Address)
+	assert.Nil(t, err)
+
+	_, err = client.SendPingProbe()
+	assert.Nil(t, err)
+
+	snapshot := bootstrap.TopologySnapshot()
+
+	assert.Equal(t, 4, snapshot.PeerCount)
+	assert.Len(t, snapshot.PeerLatencies, 4)
+	assert.True(t, snapshot.PeerLatencies[nodes[1].Address] > 0, "expected probed peer to have a latency")
+
+	routed := 0
+	for _, count := range snapshot.BucketDistribution {
+		routed += count
+	}
+	assert.Equal(t, 5, routed, "expected routing table to hold all peers and the node itself")
+
+	encoded, err := json.Marshal(snapshot)
+	assert.Nil(t, err)
+
+	var decoded network.TopologySnapshot
+	assert.Nil(t, json.Unmarshal(encoded, &decoded))
+	assert.Equal(t, snapshot, decoded)
+}
+
+func TestPeerCount(t *testing.T) {
+	t.Parallel()
+
+	cluster, err := testutil.NewCluster(6)
+	assert.Nil(t, err)
+	defer cluster.Stop()
+
+	bootstrap, nodes := cluster.Node(0), cluster.Nodes()
+
+	assert.Equal(t, 5, bootstrap.PeerCount())
+	assert.Equal(t, 5, bootstrap.ConnectedPeerCount())
+
+	client, ok := bootstrap.GetPeer(nodes[1].ID.Id)
+	if !assert.True(t, ok) {
+		return
+	}
+	assert.Nil(t, client.Close())
+
+	deadline := time.Now().Add(100 * time.Millisecond)
+	for bootstrap.ConnectedPeerCount() != 4 {
+		if time.Now().After(deadline) {
+			t.Fatalf("expected 4 connected peers, got %d", bootstrap.ConnectedPeerCount())
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+}
+
+func TestDialPeer(t *testing.T) {
+	t.Parallel()
+
+	var nodes []*network.Network
+
+	for i := 0; i < 3; i++ {
+		builder := network.NewBuilderWithOptions(network.WithCapabilities(network.CapabilityOrdering))
+		builder.SetAddress(network.FormatAddress("tcp", "localhost", uint16(network.GetRandomUnusedPort())))
+
+		// The last node does not reply to pings.
+		if i < 2 {
+			builder.AddPlugin(&discovery.Plugin{DisableBootstrap: true})
+		}
+
+		node, err := builder.Build()
+		assert.Nil(t, err)
+
+		go node.Listen()
+		node.BlockUntilListening()
+		defer node.Close()
+
+		nodes = append(nodes, node)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+
+	client, err := nodes[0].DialPeer(ctx, nodes[1].Address)
+	if assert.Nil(t, err) {
+		assert.True(t, client.ID.Equals(nodes[1].ID), "expected dialed peer to have identified itself")
+		assert.True(t, client.Capabilities().Has(network.CapabilityOrdering))
+	}
+
+	ctx, cancel = context.WithTimeout(context.Background(), 200*time.Millisecond)
+	defer cancel()
+
+	_, err = nodes[0].DialPeer(ctx, nodes[2].Address)
+	if assert.NotNil(t, err) {
+		assert.Contains(t, err.Error(), context.DeadlineExceeded.Error())
+	}
+
+	// Wait for the ping to have been received before closing.
+	deadline := time.Now().Add(3 * time.Second)
+	for {
+		if _, ok := nodes[2].GetPeer(nodes[0].ID.Id); ok {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatal("expected ping to be received")
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
+
+func TestReceivePingRepliesWithPong(t *testing.T) {
+	t.Parallel()
+
+	layer := testutil.NewMockLayer()
+
+	builder := layer.NewBuilder()
+	builder.AddPlugin(new(discovery.Plugin))
+
+	node, err := builder.Build()
+	assert.Nil(t, err)
+
+	go node.Listen()
+	defer node.Close()
+
+	node.BlockUntilListening()
+
+	// The simulated peer is never listened on; it is only used to sign messages.
+	builder = network.NewBuilder()
+	builder.SetAddress(network.FormatAddress(testutil.MockProtocol, "127.0.0.1", 2))
+
+	sender, err := builder.Build()
+	assert.Nil(t, err)
+
+	ping, err := sender.PrepareMessage(context.Background(), &protobuf.Ping{})
+	assert.Nil(t, err)
+
+	incoming, err := layer.NewIncoming("127.0.0.1:2")
+	assert.Nil(t, err)
+	assert.Nil(t, testutil.InjectMessage(incoming, ping))
+
+	replies := layer.Conn("127.0.0.1:2")
+
+	deadline := time.Now().Add(3 * time.Second)
+	for len(replies.Sent()) == 0 {
+		if time.Now().After(deadline) {
+			t.Fatal("expected the ping to be replied to")
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	reply := replies.Sent()[0]
+	assert.Equal(t, uint32(opcode.PongCode), reply.Opcode)
+	assert.True(t, reply.ReplyFlag)
+	assert.Equal(t, node.ID.PublicKey, reply.Sender.PublicKey)
+
+	msg, err := opcode.GetMessageType(opcode.PongCode)
+	assert.Nil(t, err)
+	assert.Nil(t, proto.Unmarshal(reply.Message, msg))
+	assert.IsType(t, &protobuf.Pong{}, msg)
+}
+
+func TestPingCapabilities(t *testing.T) {
+	t.Parallel()
+
+	layer := testutil.NewMockLayer()
+
+	builder := layer.NewBuilder(network.WithCapabilities(network.CapabilityOrdering))
+	builder.AddPlugin(new(discovery.Plugin))
+
+	node, err := builder.Build()
+	assert.Nil(t, err)
+
+	go node.Listen()
+	defer node.Close()
+
+	node.BlockUntilListening()
+
+	// The simulated peer declares it accepts compressed messages.
+	builder = network.NewBuilderWithOptions(network.WithCapabilities(network.CapabilityCompression))
+	builder.SetAddress(network.FormatAddress(testutil.MockProtocol, "127.0.0.1", 2))
+
+	sender, err := builder.Build()
+	assert.Nil(t, err)
+
+	ping, err := sender.PrepareMessage(context.Background(), &protobuf.Ping{Capabilities: uint64(sender.Capabilities())})
+	assert.Nil(t, err)
+
+	incoming, err := layer.NewIncoming("127.0.0.1:2")
+	assert.Nil(t, err)
+	assert.Nil(t, testutil.InjectMessage(incoming, ping))
+
+	replies := layer.Conn("127.0.0.1:2")
+
+	deadline := time.Now().Add(3 * time.Second)
+	for len(replies.Sent()) == 0 {
+		if time.Now().After(deadline) {
+			t.Fatal("expected the ping to be replied to")
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	decompress := func(msg *protobuf.Message) []byte {
+		assert.True(t, msg.Compressed, "expected message to be compressed")
+
+		raw, err := io.ReadAll(flate.NewReader(bytes.NewReader(msg.Message)))
+		assert.Nil(t, err)
+
+		return raw
+	}
+
+	// Messages are compressed from the moment the peers capabilities are known.
+	pong := new(protobuf.Pong)
+	assert.Nil(t, proto.Unmarshal(decompress(replies.Sent()[0]), pong))
+	assert.Equal(t, network.CapabilityOrdering, network.Capabilities(pong.Capabilities))
+
+	client, err := node.Client(sender.Address)
+	assert.Nil(t, err)
+	assert.True(t, client.Capabilities().Has(network.CapabilityCompression))
+
+	data := bytes.Repeat([]byte("noise"), 1000)
+	assert.Nil(t, client.Tell(context.Background(), &protobuf.Bytes{Data: data}))
+
+	deadline = time.Now().Add(3 * time.Second)
+	for len(replies.Sent()) < 2 {
+		if time.Now().After(deadline) {
+			t.Fatal("expected the message to be sent")
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	sent := replies.Sent()[1]
+	raw := decompress(sent)
+
+	msg := new(protobuf.Bytes)
+	assert.Nil(t, proto.Unmarshal(raw, msg))
+	assert.Equal(t, data, msg.Data)
+	assert.True(t, len(sent.Message) < len(raw), "expected compressed message to be smaller")
+}
+
+func TestRevokeKey(t *testing.T) {
+	t.Parallel()
+
+	cluster, err := testutil.NewCluster(4)
+	assert.Nil(t, err)
+	defer cluster.Stop()
+
+	revoked := cluster.Node(1)
+	newKeys := ed25519.RandomKeyPair()
+	replacement := peer.CreateID(revoked.Address, newKeys.PublicKey)
+
+	// Revocations not signed by the revoked key are rejected.
+	forged := &protobuf.KeyRevocation{OldPublicKey: cluster.Node(3).ID.PublicKey, NewPublicKey: newKeys.PublicKey}
+	forged.Signature, err = cluster.Node(2).Sign([]byte("forged"))
+	assert.Nil(t, err)
+
+	client, err := cluster.Node(2).Client(cluster.Node(0).Address)
+	assert.Nil(t, err)
+	assert.Nil(t, client.Tell(context.Background(), forged))
+
+	sign := func(node *network.Network, revocation *protobuf.KeyRevocation) *protobuf.KeyRevocation {
+		payload, err := revocation.Marshal()
+		assert.Nil(t, err)
+
+		revocation.Signature, err = node.Sign(payload)
+		assert.Nil(t, err)
+
+		return revocation
+	}
+
+	// Revocations replacing the revoked key must be countersigned by the new key.
+	uncountersigned := sign(cluster.Node(3), &protobuf.KeyRevocation{
+		OldPublicKey: cluster.Node(3).ID.PublicKey,
+		NewPublicKey: ed25519.RandomKeyPair().PublicKey,
+		Timestamp:    uint64(time.Now().Unix()),
+	})
+	assert.Nil(t, client.Tell(context.Background(), uncountersigned))
+
+	// Revocations older than the maximum revocation age are rejected.
+	stale := sign(cluster.Node(2), &protobuf.KeyRevocation{
+		OldPublicKey: cluster.Node(2).ID.PublicKey,
+		Timestamp:    uint64(time.Now().Add(-2 * discovery.DefaultMaxRevocationAge).Unix()),
+	})
+	assert.Nil(t, client.Tell(context.Background(), stale))
+
+	assert.Nil(t, cluster.Plugin(1).RevokeKey(revoked, newKeys))
+
+	revokedEverywhere := func() bool {
+		for i := 0; i < 4; i++ {
+			if i == 1 {
+				continue
+			}
+
+			plugin := cluster.Plugin(i)
+			if !plugin.IsRevoked(revoked.ID.PublicKeyHex()) || plugin.Routes.PeerExists(revoked.ID) {
+				return false
+			}
+		}
+		return true
+	}
+
+	deadline := time.Now().Add(3 * time.Second)
+	for !revokedEverywhere() {
+		if time.Now().After(deadline) {
+			t.Fatal("expected revoked key to be removed from all routing tables")
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	assert.True(t, cluster.Plugin(0).Routes.PeerExists(replacement), "expected peer to be routed under its new key")
+
+	// Messages from the revoked key no longer have it be routed.
+	client, err = revoked.Client(cluster.Node(0).Address)
+	assert.Nil(t, err)
+	assert.Nil(t, client.Tell(context.Background(), &protobuf.Ping{}))
+
+	time.Sleep(250 * time.Millisecond)
+	assert.False(t, cluster.Plugin(0).Routes.PeerExists(revoked.ID), "expected revoked key to not be re-added")
+
+	assert.False(t, cluster.Plugin(0).IsRevoked(cluster.Node(3).ID.PublicKeyHex()), "expected forged and uncountersigned revocations to be rejected")
+	assert.True(t, cluster.Plugin(0).Routes.PeerExists(cluster.Node(3).ID))
+
+	assert.False(t, cluster.Plugin(0).IsRevoked(cluster.Node(2).ID.PublicKeyHex()), "expected stale revocation to be rejected")
+	assert.True(t, cluster.Plugin(0).Routes.PeerExists(cluster.Node(2).ID))
+}
@@ -0,0 +1,151 @@
+package discovery
+
+import (
+	"bytes"
+	"context"
+	"encoding/hex"
+	"time"
+
+	"github.com/perlin-network/noise/crypto"
+	"github.com/perlin-network/noise/internal/protobuf"
+	"github.com/perlin-network/noise/network"
+	"github.com/perlin-network/noise/peer"
+	"github.com/pkg/errors"
+)
+
+var (
+	// ErrInvalidRevocation returns if a key revocation is not signed by the key it revokes,
+	// or by the key it replaces the revoked key with.
+	ErrInvalidRevocation = errors.New("discovery: key revocation is not signed by the revoked and new key")
+
+	// ErrStaleRevocation returns if a key revocation is older than the maximum revocation
+	// age, or is timestamped in the future.
+	ErrStaleRevocation = errors.New("discovery: key revocation is stale or timestamped in the future")
+)
+
+// revocationPayload returns the bytes a key revocation is signed over, which is the
+// revocation serialized without its signatures.
+func revocationPayload(revocation *protobuf.KeyRevocation) ([]byte, error) {
+	unsigned := *revocation
+	unsigned.Signature = nil
+	unsigned.NewSignature = nil
+
+	return unsigned.Marshal()
+}
+
+// RevokeKey revokes the networks own key should it have been compromised, optionally
+// replacing it with a new key pair. The revocation is signed by the revoked key, and
+// countersigned by the new key, and gossiped to all peers of the routing table, which
+// remove the revoked key from their routing tables and refuse to route it thereafter.
+// Peers under the revoked key are routed under the new key in its place, should one be
+// given.
+func (state *Plugin) RevokeKey(net *network.Network, newKeys *crypto.KeyPair) error {
+	revocation := &protobuf.KeyRevocation{
+		OldPublicKey: net.SelfID().PublicKey,
+		Timestamp:    uint64(net.Clock().Now().Unix()),
+	}
+
+	if newKeys != nil {
+		revocation.NewPublicKey = newKeys.PublicKey
+	}
+
+	payload, err := revocationPayload(revocation)
+	if err != nil {
+		return errors.Wrap(err, "discovery: failed to serialize key revocation")
+	}
+
+	if revocation.Signature, err = net.Sign(payload); err != nil {
+		return errors.Wrap(err, "discovery: failed to sign key revocation")
+	}
+
+	if newKeys != nil {
+		if revocation.NewSignature, err = net.SignWith(newKeys, payload); err != nil {
+			return errors.Wrap(err, "discovery: failed to countersign key revocation")
+		}
+	}
+
+	state.revoked.Store(hex.EncodeToString(revocation.OldPublicKey), struct{}{})
+	state.gossipRevocation(net, revocation)
+
+	return nil
+}
+
+// IsRevoked returns true if a key denoted by its public key hex has been revoked.
+func (state *Plugin) IsRevoked(pubKeyHex string) bool {
+	_, revoked := state.revoked.Load(pubKeyHex)
+	return revoked
+}
+
+// handleRevocation verifies a key revocation gossiped by a peer, and applies and gossips
+// it further should the key not have been revoked already. Peers under the revoked key
+// are only routed under the new key should the new key be a valid peer key.
+func (state *Plugin) handleRevocation(ctx *network.PluginContext, revocation *protobuf.KeyRevocation) error {
+	payload, err := revocationPayload(revocation)
+	if err != nil {
+		return errors.Wrap(err, "discovery: failed to serialize key revocation")
+	}
+
+	net := ctx.Network()
+
+	if len(revocation.OldPublicKey) == 0 || !net.Verify(revocation.OldPublicKey, payload, revocation.Signature) {
+		state.recordFailure(net, ctx.Sender())
+		return ErrInvalidRevocation
+	}
+
+	if len(revocation.NewPublicKey) > 0 && !net.Verify(revocation.NewPublicKey, payload, revocation.NewSignature) {
+		state.recordFailure(net, ctx.Sender())
+		return ErrInvalidRevocation
+	}
+
+	now := net.Clock().Now()
+	issued := time.Unix(int64(revocation.Timestamp), 0)
+
+	if issued.After(now.Add(maxRevocationClockSkew)) || now.Sub(issued) > state.maxRevocationAge() {
+		return ErrStaleRevocation
+	}
+
+	key := hex.EncodeToString(revocation.OldPublicKey)
+
+	// Revocations are only gossiped the first time they are seen.
+	if _, revoked := state.revoked.LoadOrStore(key, struct{}{}); revoked {
+		return nil
+	}
+
+	for _, id := range state.Routes.GetPeers() {
+		if !bytes.Equal(id.PublicKey, revocation.OldPublicKey) {
+			continue
+		}
+
+		state.Routes.RemovePeer(id)
+
+		if len(revocation.NewPublicKey) > 0 {
+			for _, replacement := range state.filterValidPeers([]peer.ID{peer.CreateID(id.Address, revocation.NewPublicKey)}) {
+				state.Routes.Update(replacement)
+			}
+		}
+	}
+
+	logger := ctx.Logger()
+	logger.Warn().
+		Str("revoked_public_key", key).
+		Str("new_public_key", hex.EncodeToString(revocation.NewPublicKey)).
+		Msg("Revoked peer key.")
+
+	state.gossipRevocation(ctx.Network(), revocation)
+
+	return nil
+}
+
+// gossipRevocation sends a key revocation to all peers of the routing table, other than
+// the peer under the revoked key.
+func (state *Plugin) gossipRevocation(net *network.Network, revocation *protobuf.KeyRevocation) {
+	var peers []peer.ID
+
+	for _, id := range state.Routes.GetPeers() {
+		if !id.Equals(net.SelfID()) && !bytes.Equal(id.PublicKey, revocation.OldPublicKey) {
+			peers = append(peers, id)
+		}
+	}
+
+	net.BroadcastByIDs(network.WithSignMessage(context.Background(), true), revocation, peers...)
+}
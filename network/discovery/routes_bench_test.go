@@ -0,0 +1,89 @@
+package discovery_test
+
+import (
+	"crypto/rand"
+	"fmt"
+	"testing"
+
+	"github.com/perlin-network/noise/dht"
+	"github.com/perlin-network/noise/peer"
+)
+
+// randomID creates a peer ID off of a random public key.
+func randomID(b *testing.B, address string) peer.ID {
+	publicKey := make([]byte, 32)
+	if _, err := rand.Read(publicKey); err != nil {
+		b.Fatal(err)
+	}
+	return peer.CreateID(address, publicKey)
+}
+
+// newBenchmarkTable creates a routing table populated with a given number of random peers.
+// Buckets are sized such that no peer is dropped.
+func newBenchmarkTable(b *testing.B, count int) (*dht.RoutingTable, []peer.ID) {
+	table := dht.CreateRoutingTableWithBucketSize(randomID(b, "tcp://127.0.0.1:3000"), count)
+	table.MaxPeersPerSubnet = 0
+
+	peers := make([]peer.ID, count)
+	for i := range peers {
+		peers[i] = randomID(b, fmt.Sprintf("tcp://10.%d.%d.1:3000", i/256%256, i%256))
+	}
+	table.UpdateMany(peers)
+
+	return table, peers
+}
+
+func BenchmarkRoutingTableUpdate1000Peers(b *testing.B) {
+	table, peers := newBenchmarkTable(b, 1000)
+
+	b.ReportAllocs()
+	b.ResetTimer()
+
+	for i := 0; i < b.N; i++ {
+		table.Update(peers[i%len(peers)])
+	}
+}
+
+func BenchmarkFindClosestPeers(b *testing.B) {
+	for _, count := range []int{100, 1000, 10000} {
+		b.Run(fmt.Sprintf("%dPeers", count), func(b *testing.B) {
+			table, _ := newBenchmarkTable(b, count)
+
+			targets := make([]peer.ID, 256)
+			for i := range targets {
+				targets[i] = randomID(b, "tcp://127.0.0.1:3001")
+			}
+
+			b.ReportAllocs()
+			b.ResetTimer()
+
+			for i := 0; i < b.N; i++ {
+				table.FindClosestPeers(targets[i%len(targets)], dht.BucketSize)
+			}
+		})
+	}
+}
+
+func BenchmarkXorDistance(b *testing.B) {
+	_, peers := newBenchmarkTable(b, 1000)
+	target := randomID(b, "tcp://127.0.0.1:3001")
+
+	b.ReportAllocs()
+	b.ResetTimer()
+
+	for i := 0; i < b.N; i++ {
+		peers[i%len(peers)].XorID(target)
+	}
+}
+
+func BenchmarkBucketAssignment(b *testing.B) {
+	table, peers := newBenchmarkTable(b, 1000)
+	self := table.Self()
+
+	b.ReportAllocs()
+	b.ResetTimer()
+
+	for i := 0; i < b.N; i++ {
+		table.Bucket(peers[i%len(peers)].XorID(self).PrefixLen())
+	}
+}
@@ -103,13 +103,19 @@ func FindNode(net *network.Network, targetID peer.ID, alpha int, disjointPaths i
 		return
 	}
 
+	return findNode(net, plugin.(*Plugin).Routes, targetID, alpha, disjointPaths)
+}
+
+// findNode performs FindNode starting from the peers of a routing table, such that plugins
+// embedding the discovery plugin may perform lookups without being registered as it.
+func findNode(net *network.Network, routes *dht.RoutingTable, targetID peer.ID, alpha int, disjointPaths int) (results []peer.ID) {
 	visited := new(sync.Map)
 
 	var lookups []*lookupBucket
 
 	// Start searching for target from #ALPHA peers closest to target by queuing
 	// them up and marking them as visited.
-	for i, peerID := range plugin.(*Plugin).Routes.FindClosestPeers(targetID, alpha) {
+	for i, peerID := range routes.FindClosestPeers(targetID, alpha) {
 		visited.Store(peerID.PublicKeyHex(), struct{}{})
 
 		if len(lookups) < disjointPaths {
@@ -141,15 +147,15 @@ func FindNode(net *network.Network, targetID peer.ID, alpha int, disjointPaths i
 
 	// Sort resulting peers by XOR distance.
 	sort.Slice(results, func(i, j int) bool {
-		left := results[i].Xor(targetID)
-		right := results[j].Xor(targetID)
+		left := results[i].XorID(targetID)
+		right := results[j].XorID(targetID)
 		return left.Less(right)
 	})
 
 	// Cut off list of results to only have the routing table focus on the
-	// #dht.BucketSize closest peers to the current node.
-	if len(results) > dht.BucketSize {
-		results = results[:dht.BucketSize]
+	// #BucketSize closest peers to the current node.
+	if len(results) > routes.BucketSize() {
+		results = results[:routes.BucketSize()]
 	}
 
 	return
@@ -0,0 +1,427 @@
+package network
+
+import (
+	"bytes"
+	"compress/flate"
+	"encoding/binary"
+	"io"
+	"net"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/perlin-network/noise/handshake"
+
+	"github.com/pkg/errors"
+)
+
+// Features is a bitmask of transport features a node supports.
+type Features uint32
+
+const (
+	// FeatureForwardSecrecy encrypts connections with ephemeral session keys. It is only
+	// advertised should the network have session keys.
+	FeatureForwardSecrecy Features = 1 << iota
+
+	// FeatureCompression compresses every write to a connection with DEFLATE.
+	FeatureCompression
+
+	// FeatureOrderedDelivery dispatches messages from a connection in the order they
+	// were sent, rather than in the order they were received.
+	FeatureOrderedDelivery
+
+	// FeatureLZ4Compression compresses every write to a connection with LZ4. It is
+	// preferred over FeatureCompression should both sides support it, as LZ4 is faster at
+	// both compressing and decompressing.
+	FeatureLZ4Compression
+
+	// FeatureAESGCM encrypts connections with AES-256-GCM rather than ChaCha20-Poly1305,
+	// should they be encrypted with FeatureForwardSecrecy.
+	FeatureAESGCM
+
+	// AllFeatures is the set of all features supported by this version of noise.
+	AllFeatures = FeatureForwardSecrecy | FeatureCompression | FeatureOrderedDelivery | FeatureLZ4Compression | FeatureAESGCM
+)
+
+var (
+	errCompressedFrameTooLarge = errors.New("compression: frame is too large")
+)
+
+// Has returns true if all of the specified features are set.
+func (f Features) Has(features Features) bool {
+	return f&features == features
+}
+
+// String returns the names of all set features, separated by `|`.
+func (f Features) String() string {
+	var names []string
+
+	if f.Has(FeatureForwardSecrecy) {
+		names = append(names, "forward_secrecy")
+	}
+	if f.Has(FeatureCompression) {
+		names = append(names, "compression")
+	}
+	if f.Has(FeatureOrderedDelivery) {
+		names = append(names, "ordered_delivery")
+	}
+	if f.Has(FeatureLZ4Compression) {
+		names = append(names, "lz4_compression")
+	}
+	if f.Has(FeatureAESGCM) {
+		names = append(names, "aes_gcm")
+	}
+
+	if len(names) == 0 {
+		return "none"
+	}
+
+	return strings.Join(names, "|")
+}
+
+// supportedFeatures returns the features this node advertises to its peers. Without
+// negotiation, connections are encrypted should the network have session keys, with
+// AES-256-GCM should it be enabled, and messages are always delivered in order.
+func (n *Network) supportedFeatures() Features {
+	features := FeatureOrderedDelivery | FeatureForwardSecrecy
+	if n.opts.negotiateFeatures {
+		features = n.opts.features
+	}
+
+	if n.opts.aesGCM {
+		features |= FeatureAESGCM
+	}
+
+	if n.sessionKeys() == nil {
+		features &^= FeatureForwardSecrecy | FeatureAESGCM
+	}
+
+	return features
+}
+
+// upgradeConn builds the pipeline of a newly established connection. Should negotiation
+// be enabled, either side first exchanges the features it supports, and the pipeline is
+// built from the intersection of both sides features.
+//
+// Feature negotiation is not authenticated, and thus should not be relied upon to
+// enforce encryption.
+func (n *Network) upgradeConn(conn net.Conn, isClient bool) (net.Conn, error) {
+	features := n.supportedFeatures()
+
+	if n.opts.negotiateFeatures {
+		conn.SetDeadline(time.Now().Add(n.opts.connectionTimeout))
+
+		remote, err := exchangeFeatures(conn, features, isClient)
+		if err != nil {
+			return nil, errors.Wrap(err, "network: failed to negotiate features")
+		}
+
+		conn.SetDeadline(time.Time{})
+
+		features &= remote
+	}
+
+	// Writes are only ever compressed once.
+	if features.Has(FeatureLZ4Compression) {
+		features &^= FeatureCompression
+	}
+
+	var session *sessionConn
+
+	if features.Has(FeatureForwardSecrecy) {
+		var err error
+		if session, err = n.newSessionConn(conn, isClient, features.Has(FeatureAESGCM)); err != nil {
+			return nil, err
+		}
+		conn = session
+	}
+
+	if !n.opts.negotiateFeatures {
+		return conn, nil
+	}
+
+	switch {
+	case features.Has(FeatureLZ4Compression):
+		conn = newCompressedConn(conn, lz4Codec{}, n.MaxMessageSizeBytes)
+	case features.Has(FeatureCompression):
+		conn = newCompressedConn(conn, flateCodec{}, n.MaxMessageSizeBytes)
+	}
+
+	return &featureConn{Conn: conn, features: features, session: session}, nil
+}
+
+// exchangeFeatures sends the features this node supports over a connection, and reads
+// back the features its peer supports. Clients send first, and servers read first.
+func exchangeFeatures(conn net.Conn, features Features, isClient bool) (Features, error) {
+	var local, remote [4]byte
+	binary.BigEndian.PutUint32(local[:], uint32(features))
+
+	if isClient {
+		if _, err := conn.Write(local[:]); err != nil {
+			return 0, err
+		}
+	}
+
+	if _, err := io.ReadFull(conn, remote[:]); err != nil {
+		return 0, err
+	}
+
+	if !isClient {
+		if _, err := conn.Write(local[:]); err != nil {
+			return 0, err
+		}
+	}
+
+	return Features(binary.BigEndian.Uint32(remote[:])), nil
+}
+
+// connFeatures returns the features negotiated for a connection.
+func (n *Network) connFeatures(conn net.Conn) Features {
+	if c, ok := conn.(*featureConn); ok {
+		return c.features
+	}
+	return n.supportedFeatures()
+}
+
+// connSession returns the encrypted session of a connection, should it have one.
+func connSession(conn net.Conn) (*sessionConn, bool) {
+	if c, ok := conn.(*featureConn); ok {
+		return c.session, c.session != nil
+	}
+
+	session, ok := conn.(*sessionConn)
+	return session, ok
+}
+
+// matchesStaticKey returns false should a connection secured by a Noise handshake have
+// been established with a static key other than that of a public key.
+func matchesStaticKey(conn net.Conn, publicKey []byte) bool {
+	secured, ok := findConn(conn, func(conn net.Conn) bool {
+		_, ok := conn.(*handshake.Conn)
+		return ok
+	})
+	if !ok {
+		return true
+	}
+
+	static, err := handshake.StaticPublicKey(publicKey)
+	if err != nil {
+		return false
+	}
+
+	return bytes.Equal(secured.(*handshake.Conn).RemoteStatic(), static)
+}
+
+// bindsIdentity returns true should a connection be secured by a session or Noise handshake
+// which binds the key of the peer to it.
+func bindsIdentity(conn net.Conn) bool {
+	if _, ok := connSession(conn); ok {
+		return true
+	}
+
+	_, ok := findConn(conn, func(conn net.Conn) bool {
+		_, ok := conn.(*handshake.Conn)
+		return ok
+	})
+	return ok
+}
+
+// wrappedConn is implemented by connections layered over another connection, such as
+// those of transports and feature pipelines.
+type wrappedConn interface {
+	Unwrap() net.Conn
+}
+
+// findConn unwraps a connection layer by layer until a layer satisfies match.
+func findConn(conn net.Conn, match func(net.Conn) bool) (net.Conn, bool) {
+	for conn != nil {
+		if match(conn) {
+			return conn, true
+		}
+
+		wrapped, ok := conn.(wrappedConn)
+		if !ok {
+			break
+		}
+		conn = wrapped.Unwrap()
+	}
+
+	return nil, false
+}
+
+// featureConn is a connection whose pipeline was built from negotiated features.
+type featureConn struct {
+	net.Conn
+
+	features Features
+	session  *sessionConn
+}
+
+// Unwrap returns the underlying connection.
+func (c *featureConn) Unwrap() net.Conn {
+	return c.Conn
+}
+
+// frameCodec compresses and decompresses the frames of a compressedConn.
+type frameCodec interface {
+	// compress appends data compressed into a frame to a buffer.
+	compress(buffer *bytes.Buffer, data []byte) error
+
+	// decompress returns the data of a frame, which may not expand past maxSize bytes.
+	decompress(frame []byte, maxSize int) ([]byte, error)
+
+	// bound returns the largest size a frame of size bytes of data may be compressed to.
+	bound(size int) int
+}
+
+// flateWriters pools DEFLATE compressors, which are expensive to allocate.
+var flateWriters = sync.Pool{
+	New: func() interface{} {
+		w, _ := flate.NewWriter(nil, flate.DefaultCompression)
+		return w
+	},
+}
+
+// flateCodec compresses frames with DEFLATE.
+type flateCodec struct{}
+
+func (flateCodec) compress(buffer *bytes.Buffer, data []byte) error {
+	w := flateWriters.Get().(*flate.Writer)
+	w.Reset(buffer)
+
+	_, err := w.Write(data)
+	if err == nil {
+		err = w.Close()
+	}
+
+	flateWriters.Put(w)
+
+	return err
+}
+
+func (flateCodec) decompress(frame []byte, maxSize int) ([]byte, error) {
+	r := flate.NewReader(bytes.NewReader(frame))
+	defer r.Close()
+
+	data, err := io.ReadAll(io.LimitReader(r, int64(maxSize)+1))
+	if err != nil {
+		return nil, err
+	}
+
+	if len(data) > maxSize {
+		return nil, errCompressedFrameTooLarge
+	}
+
+	return data, nil
+}
+
+// bound accounts for data which does not compress being stored in blocks of at most
+// 65535 bytes, each with a 5 byte header.
+func (flateCodec) bound(size int) int {
+	return size + (size/65535+1)*5 + 16
+}
+
+// compressedConn is a connection whose writes are compressed into length-prefixed frames,
+// each of which holds at most the max message size of data.
+type compressedConn struct {
+	net.Conn
+
+	codec        frameCodec
+	maxFrameSize func() int
+
+	readMutex sync.Mutex
+	buffer    []byte
+
+	writeMutex sync.Mutex
+}
+
+func newCompressedConn(conn net.Conn, codec frameCodec, maxFrameSize func() int) *compressedConn {
+	return &compressedConn{Conn: conn, codec: codec, maxFrameSize: maxFrameSize}
+}
+
+// Unwrap returns the underlying connection.
+func (c *compressedConn) Unwrap() net.Conn {
+	return c.Conn
+}
+
+// Read reads decompressed data, reading the next frame once all buffered data has been read.
+func (c *compressedConn) Read(out []byte) (int, error) {
+	c.readMutex.Lock()
+	defer c.readMutex.Unlock()
+
+	for len(c.buffer) == 0 {
+		var size [4]byte
+		if _, err := io.ReadFull(c.Conn, size[:]); err != nil {
+			return 0, err
+		}
+
+		maxSize := c.maxFrameSize()
+
+		// Frames are limited in size to prevent peers from exhausting memory.
+		frameSize := binary.BigEndian.Uint32(size[:])
+		if int64(frameSize) > int64(c.codec.bound(maxSize)) {
+			return 0, errCompressedFrameTooLarge
+		}
+
+		frame := make([]byte, frameSize)
+		if _, err := io.ReadFull(c.Conn, frame); err != nil {
+			return 0, err
+		}
+
+		data, err := c.codec.decompress(frame, maxSize)
+		if err == errCompressedFrameTooLarge {
+			return 0, err
+		}
+		if err != nil {
+			return 0, errors.Wrap(err, "compression: failed to decompress frame")
+		}
+
+		c.buffer = data
+	}
+
+	n := copy(out, c.buffer)
+	c.buffer = c.buffer[n:]
+
+	return n, nil
+}
+
+// Write compresses data into frames of at most the max message size of data each.
+func (c *compressedConn) Write(data []byte) (int, error) {
+	maxSize := c.maxFrameSize()
+
+	written := 0
+	for len(data) > 0 {
+		chunk := data
+		if len(chunk) > maxSize {
+			chunk = chunk[:maxSize]
+		}
+
+		if err := c.writeFrame(chunk); err != nil {
+			return written, err
+		}
+
+		written += len(chunk)
+		data = data[len(chunk):]
+	}
+
+	return written, nil
+}
+
+// writeFrame compresses data into a single frame.
+func (c *compressedConn) writeFrame(data []byte) error {
+	var buffer bytes.Buffer
+	buffer.Write(make([]byte, 4))
+
+	if err := c.codec.compress(&buffer, data); err != nil {
+		return errors.Wrap(err, "compression: failed to compress frame")
+	}
+
+	frame := buffer.Bytes()
+	binary.BigEndian.PutUint32(frame, uint32(len(frame)-4))
+
+	c.writeMutex.Lock()
+	defer c.writeMutex.Unlock()
+
+	_, err := c.Conn.Write(frame)
+	return err
+}
@@ -0,0 +1,153 @@
+package network
+
+import (
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// defaultFloodWindow is the length of the sliding window messages are counted within.
+const defaultFloodWindow = time.Second
+
+var errFloodControl = errors.New("network: peer exceeded the flood control threshold")
+
+// FloodControl decides when messages from a single peer are dropped for being sent at
+// too high a rate, such that a botnet may not overwhelm the network. Messages are counted
+// per peer the connection they are received over identified as, rather than per sender
+// claimed within messages.
+type FloodControl struct {
+	// FloodThreshold is the number of messages per second a single sender may send.
+	// Disabled if zero.
+	FloodThreshold float64
+
+	// Window is the length of the sliding window messages are counted within
+	// (default: 1 second).
+	Window time.Duration
+}
+
+func (f FloodControl) window() time.Duration {
+	if f.Window > 0 {
+		return f.Window
+	}
+	return defaultFloodWindow
+}
+
+// floodCounter counts the messages received from a single sender within a sliding window,
+// which is approximated by weighing the count of the previous fixed window by how much of
+// it the sliding window still overlaps.
+type floodCounter struct {
+	mutex sync.Mutex
+
+	windowStart       time.Time
+	previous, current int
+
+	// flooded is true should the sender have exceeded the threshold within the current window.
+	flooded bool
+}
+
+// allow returns true should a message be let through. Once the sender exceeds the
+// threshold, all of its messages are dropped until the current window ends. triggered
+// is true for the message which exceeded the threshold.
+func (c *floodCounter) allow(now time.Time, policy FloodControl) (allowed bool, triggered bool) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	window := policy.window()
+
+	if elapsed := now.Sub(c.windowStart); elapsed >= window {
+		// The previous window no longer overlaps should more than one window have elapsed.
+		if elapsed < 2*window {
+			c.previous = c.current
+		} else {
+			c.previous = 0
+		}
+
+		c.windowStart = now.Truncate(window)
+		c.current, c.flooded = 0, false
+	}
+
+	if c.flooded {
+		return false, false
+	}
+
+	overlap := 1 - float64(now.Sub(c.windowStart))/float64(window)
+	estimate := float64(c.previous)*overlap + float64(c.current)
+
+	if estimate >= policy.FloodThreshold*window.Seconds() {
+		c.flooded = true
+		return false, true
+	}
+
+	c.current++
+
+	return true, false
+}
+
+// idle returns true should no message have been counted within the last two windows, in
+// which case the counter no longer weighs against its sender.
+func (c *floodCounter) idle(now time.Time, policy FloodControl) bool {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	return now.Sub(c.windowStart) >= 2*policy.window()
+}
+
+// floodCounter returns the counter of the messages received from a peer, pruning counters
+// which are idle at most once every two windows, upon a counter being created for a new
+// peer.
+func (n *Network) floodCounter(key string, now time.Time) *floodCounter {
+	if counter, exists := n.floods.Load(key); exists {
+		return counter.(*floodCounter)
+	}
+
+	policy := n.opts.floodControl
+
+	n.floodsMutex.Lock()
+	if now.Sub(n.floodsPruned) >= 2*policy.window() {
+		n.floods.Range(func(key, counter interface{}) bool {
+			if counter.(*floodCounter).idle(now, policy) {
+				n.floods.Delete(key)
+			}
+			return true
+		})
+		n.floodsPruned = now
+	}
+	n.floodsMutex.Unlock()
+
+	counter, _ := n.floods.LoadOrStore(key, new(floodCounter))
+	return counter.(*floodCounter)
+}
+
+// allowMessage returns true should a message received from a peer pass flood control.
+// Messages are counted per the ID the peer identified itself with, or per its address
+// should it not have identified itself yet. The first message dropped from a peer within
+// a window is recorded as EventFloodControlTriggered. Peers are not notified of their
+// messages being dropped, such that flooding a node may not be used to amplify traffic.
+func (n *Network) allowMessage(client *PeerClient) bool {
+	if n.opts.floodControl.FloodThreshold <= 0 {
+		return true
+	}
+
+	id := client.PeerID()
+
+	key := client.Address
+	if id != nil {
+		key = id.PublicKeyHex()
+	}
+
+	now := n.opts.clock.Now()
+
+	allowed, triggered := n.floodCounter(key, now).allow(now, n.opts.floodControl)
+
+	if triggered {
+		n.recordConnectionEvent(EventFloodControlTriggered, id, client.Address, errFloodControl)
+
+		n.protocolLog.Warn().
+			Str("address", client.Address).
+			Str("peer", key).
+			Msg("Dropping messages from peer exceeding the flood control threshold.")
+	}
+
+	return allowed
+}
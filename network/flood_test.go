@@ -0,0 +1,155 @@
+package network
+
+import (
+	"context"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/perlin-network/noise/clock"
+	"github.com/perlin-network/noise/internal/protobuf"
+	"github.com/perlin-network/noise/peer"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFloodCounter(t *testing.T) {
+	t.Parallel()
+
+	policy := FloodControl{FloodThreshold: 100}
+	counter := new(floodCounter)
+
+	// flood sends 500 messages within 100ms, and returns how many were let through and
+	// how many triggered flood control.
+	flood := func(now time.Time) (allowed int, triggered int) {
+		for i := 0; i < 500; i++ {
+			ok, trigger := counter.allow(now, policy)
+			if ok {
+				allowed++
+			}
+			if trigger {
+				triggered++
+			}
+			now = now.Add(200 * time.Microsecond)
+		}
+		return allowed, triggered
+	}
+
+	allowed, triggered := flood(time.Unix(1000, 0))
+	assert.True(t, allowed < 110, "expected fewer than 110 messages to pass flood control, got %d", allowed)
+	assert.Equal(t, 1, triggered)
+
+	// The previous window still weighs against the sender within the next window.
+	allowed, triggered = flood(time.Unix(1001, 0))
+	assert.True(t, allowed < 20, "expected the previous window to weigh against the sender, got %d", allowed)
+	assert.Equal(t, 1, triggered)
+
+	ok, _ := counter.allow(time.Unix(1003, 0), policy)
+	assert.True(t, ok, "expected sender to be let through once its flood slid out of the window")
+}
+
+func TestFloodCounterPrune(t *testing.T) {
+	t.Parallel()
+
+	fake := clock.NewFakeClock(time.Now())
+
+	policy := FloodControl{FloodThreshold: 100}
+
+	n, err := NewBuilderWithOptions(WithClock(fake), WithFloodControl(policy)).Build()
+	assert.Nil(t, err)
+
+	id := peer.CreateID("tcp://127.0.0.1:3000", []byte("identified"))
+
+	// Messages are counted per the ID a peer identified itself with, or its address.
+	assert.True(t, n.allowMessage(&PeerClient{Network: n, ID: &id, Address: id.Address}))
+	assert.True(t, n.allowMessage(&PeerClient{Network: n, Address: "tcp://127.0.0.1:3001"}))
+
+	_, identified := n.floods.Load(id.PublicKeyHex())
+	assert.True(t, identified, "expected messages from identified peers to be counted per their ID")
+
+	_, unidentified := n.floods.Load("tcp://127.0.0.1:3001")
+	assert.True(t, unidentified, "expected messages from unidentified peers to be counted per their address")
+
+	fake.Advance(policy.window())
+	n.floodCounter("tcp://127.0.0.1:3002", fake.Now())
+
+	_, exists := n.floods.Load(id.PublicKeyHex())
+	assert.True(t, exists, "expected counters to be kept within two windows of their last message")
+
+	fake.Advance(policy.window())
+	n.floodCounter("tcp://127.0.0.1:3003", fake.Now())
+
+	_, exists = n.floods.Load(id.PublicKeyHex())
+	assert.False(t, exists, "expected idle counters to be pruned")
+}
+
+// countingPlugin counts the pings it receives.
+type countingPlugin struct {
+	*Plugin
+	pings int32
+}
+
+func (p *countingPlugin) Receive(ctx *PluginContext) error {
+	if _, ok := ctx.Message().(*protobuf.Ping); ok {
+		atomic.AddInt32(&p.pings, 1)
+	}
+	return nil
+}
+
+func TestFloodControl(t *testing.T) {
+	t.Parallel()
+
+	plugin := new(countingPlugin)
+
+	builder := NewBuilderWithOptions(WithFloodControl(FloodControl{FloodThreshold: 100}))
+	builder.SetAddress(FormatAddress("tcp", "localhost", uint16(GetRandomUnusedPort())))
+	builder.AddPlugin(plugin)
+
+	receiver, err := builder.Build()
+	assert.Nil(t, err)
+
+	builder = NewBuilderWithOptions(SendQueueDepth(1024))
+	builder.SetAddress(FormatAddress("tcp", "localhost", uint16(GetRandomUnusedPort())))
+
+	sender, err := builder.Build()
+	assert.Nil(t, err)
+
+	for _, node := range []*Network{receiver, sender} {
+		go node.Listen()
+		defer node.Close()
+
+		node.BlockUntilListening()
+	}
+
+	client, err := sender.Client(receiver.Address)
+	assert.Nil(t, err)
+
+	start := time.Now()
+	for i := 0; i < 500; i++ {
+		assert.Nil(t, client.Tell(context.Background(), &protobuf.Ping{}))
+		time.Sleep(time.Until(start.Add(time.Duration(i) * 100 * time.Millisecond / 500)))
+	}
+
+	triggered := func() bool {
+		for _, event := range receiver.GetConnectionHistory(defaultConnectionHistorySize) {
+			if event.EventType == EventFloodControlTriggered && event.PeerID.Equals(sender.ID) {
+				return true
+			}
+		}
+		return false
+	}
+
+	deadline := time.Now().Add(3 * time.Second)
+	for !triggered() {
+		if time.Now().After(deadline) {
+			t.Fatal("expected flood control to be triggered")
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	time.Sleep(250 * time.Millisecond)
+
+	pings := atomic.LoadInt32(&plugin.pings)
+	assert.True(t, pings > 0, "expected messages to pass flood control before the threshold is exceeded")
+	assert.True(t, pings < 110, "expected fewer than 110 messages to pass flood control, got %d", pings)
+}
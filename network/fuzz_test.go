@@ -0,0 +1,133 @@
+package network
+
+import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/perlin-network/noise/internal/protobuf"
+
+	"github.com/gogo/protobuf/proto"
+)
+
+// fuzzConn is a connection which reads off of a fixed buffer, and discards all writes.
+type fuzzConn struct {
+	net.Conn
+	r *bytes.Reader
+}
+
+func (c *fuzzConn) Read(b []byte) (int, error)         { return c.r.Read(b) }
+func (c *fuzzConn) Write(b []byte) (int, error)        { return len(b), nil }
+func (c *fuzzConn) Close() error                       { return nil }
+func (c *fuzzConn) SetDeadline(t time.Time) error      { return nil }
+func (c *fuzzConn) SetReadDeadline(t time.Time) error  { return nil }
+func (c *fuzzConn) SetWriteDeadline(t time.Time) error { return nil }
+
+// fuzzMessages returns messages of every built-in type, as prepared by a network.
+func fuzzMessages(f *testing.F, n *Network) []*protobuf.Message {
+	target := protobuf.ID(n.ID)
+
+	var messages []*protobuf.Message
+
+	for _, msg := range []proto.Message{
+		&protobuf.Ping{},
+		&protobuf.Pong{},
+		&protobuf.LookupNodeRequest{Target: &target},
+		&protobuf.LookupNodeResponse{Peers: []*protobuf.ID{&target, &target}},
+		&protobuf.Bytes{Data: []byte("noise")},
+	} {
+		prepared, err := n.PrepareMessage(context.Background(), msg)
+		if err != nil {
+			f.Fatal(err)
+		}
+		messages = append(messages, prepared)
+	}
+
+	// A reply to a request, which is not signed.
+	reply, err := n.PrepareMessage(WithSignMessage(context.Background(), false), &protobuf.Pong{})
+	if err != nil {
+		f.Fatal(err)
+	}
+	reply.RequestNonce, reply.ReplyFlag = 1, true
+
+	return append(messages, reply)
+}
+
+// FuzzReceiveMessage feeds arbitrary bytes through the read path of a connection. A
+// panic while reading messages off of a connection fails the fuzz target.
+func FuzzReceiveMessage(f *testing.F) {
+	n, err := NewBuilder().Build()
+	if err != nil {
+		f.Fatal(err)
+	}
+	defer n.Close()
+
+	var stream bytes.Buffer
+
+	for _, msg := range fuzzMessages(f, n) {
+		raw, err := proto.Marshal(msg)
+		if err != nil {
+			f.Fatal(err)
+		}
+
+		frame := make([]byte, 4+len(raw))
+		binary.BigEndian.PutUint32(frame[0:4], uint32(len(raw)))
+		copy(frame[4:], raw)
+
+		f.Add(frame)
+		stream.Write(frame)
+	}
+
+	f.Add(stream.Bytes())
+	f.Add(encodeProbeFrame(probePing, uint64(time.Now().UnixNano())))
+	f.Add([]byte{0, 0, 0, 0})
+	f.Add([]byte{0xFF, 0xFF, 0xFF, 0xFE})
+
+	f.Fuzz(func(t *testing.T, data []byte) {
+		conn := &fuzzConn{r: bytes.NewReader(data)}
+
+		for {
+			_, err := n.receiveMessage(conn)
+			if err != nil && err != errProbeFrame && err != errReplayedMsg {
+				return
+			}
+		}
+	})
+}
+
+// FuzzDispatchMessage feeds arbitrary messages through the dispatcher, which decodes
+// messages based on their opcode and hands them to plugins. A panic while dispatching
+// a message fails the fuzz target.
+func FuzzDispatchMessage(f *testing.F) {
+	n, err := NewBuilder().Build()
+	if err != nil {
+		f.Fatal(err)
+	}
+	defer n.Close()
+
+	for _, msg := range fuzzMessages(f, n) {
+		raw, err := proto.Marshal(msg)
+		if err != nil {
+			f.Fatal(err)
+		}
+		f.Add(raw)
+	}
+
+	client, err := createPeerClient(n, "tcp://localhost:1")
+	if err != nil {
+		f.Fatal(err)
+	}
+	client.setIncomingReady()
+
+	f.Fuzz(func(t *testing.T, data []byte) {
+		msg := new(protobuf.Message)
+		if err := proto.Unmarshal(data, msg); err != nil {
+			return
+		}
+
+		n.dispatchMessage(client, msg, n.opts.clock.Now())
+	})
+}
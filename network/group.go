@@ -0,0 +1,168 @@
+package network
+
+import (
+	"context"
+	"sort"
+	"sync"
+
+	"github.com/gogo/protobuf/proto"
+	"github.com/pkg/errors"
+)
+
+var (
+	// ErrGroupExists is returned when creating a group with the ID of an existing group.
+	ErrGroupExists = errors.New("network: group already exists")
+
+	// ErrNotGroupMember is returned when sending a message to, or receiving a message from,
+	// a peer which is not a member of a group.
+	ErrNotGroupMember = errors.New("network: peer is not a member of the group")
+)
+
+// PeerGroup associates a subset of peers with a subset of plugins under a group ID.
+//
+// Messages sent within a group are tagged with its ID, and are only dispatched to the
+// plugins registered to the group by peers which consider the sender a member of the
+// group. Plugins registered to a group are not notified of the network starting or
+// stopping listening, nor of peers connecting or disconnecting.
+type PeerGroup struct {
+	net *Network
+	id  []byte
+
+	plugins *PluginList
+
+	membersMutex sync.RWMutex
+	members      map[string]struct{}
+}
+
+// CreateGroup creates a group under an ID with the plugins messages sent within the group
+// are dispatched to. Peers must be added to the group with AddMember.
+func (n *Network) CreateGroup(id []byte, plugins ...PluginInterface) (*PeerGroup, error) {
+	if len(id) == 0 {
+		return nil, errors.New("network: group ID must not be empty")
+	}
+
+	group := &PeerGroup{
+		net:     n,
+		id:      append([]byte(nil), id...),
+		plugins: NewPluginList(),
+		members: make(map[string]struct{}),
+	}
+
+	for _, plugin := range plugins {
+		if !group.plugins.Put(0, plugin) {
+			return nil, errors.Errorf("network: plugin %T is registered to the group more than once", plugin)
+		}
+	}
+
+	if _, exists := n.groups.LoadOrStore(string(id), group); exists {
+		return nil, ErrGroupExists
+	}
+
+	return group, nil
+}
+
+// Group returns the group created under an ID.
+func (n *Network) Group(id []byte) (*PeerGroup, bool) {
+	group, ok := n.groups.Load(string(id))
+	if !ok {
+		return nil, false
+	}
+	return group.(*PeerGroup), true
+}
+
+// RemoveGroup removes the group created under an ID. Messages sent within the group are
+// dropped thereafter.
+func (n *Network) RemoveGroup(id []byte) {
+	n.groups.Delete(string(id))
+}
+
+// ID returns the ID of the group.
+func (g *PeerGroup) ID() []byte {
+	return g.id
+}
+
+// AddMember adds the peer at an address to the group.
+func (g *PeerGroup) AddMember(address string) {
+	g.membersMutex.Lock()
+	g.members[address] = struct{}{}
+	g.membersMutex.Unlock()
+}
+
+// RemoveMember removes the peer at an address from the group.
+func (g *PeerGroup) RemoveMember(address string) {
+	g.membersMutex.Lock()
+	delete(g.members, address)
+	g.membersMutex.Unlock()
+}
+
+// IsMember returns true if the peer at an address is a member of the group.
+func (g *PeerGroup) IsMember(address string) bool {
+	g.membersMutex.RLock()
+	_, ok := g.members[address]
+	g.membersMutex.RUnlock()
+	return ok
+}
+
+// Members returns the sorted addresses of all members of the group.
+func (g *PeerGroup) Members() []string {
+	g.membersMutex.RLock()
+	members := make([]string, 0, len(g.members))
+	for address := range g.members {
+		members = append(members, address)
+	}
+	g.membersMutex.RUnlock()
+
+	sort.Strings(members)
+	return members
+}
+
+// Plugin returns a plugin registered to the group.
+func (g *PeerGroup) Plugin(key interface{}) (PluginInterface, bool) {
+	return g.plugins.Get(key)
+}
+
+// Send asynchronously sends a message within the group to the member at an address.
+func (g *PeerGroup) Send(ctx context.Context, address string, message proto.Message) error {
+	if !g.IsMember(address) {
+		return ErrNotGroupMember
+	}
+
+	client, err := g.net.Client(address)
+	if err != nil {
+		return err
+	}
+
+	return client.Tell(withGroupID(ctx, g.id), message)
+}
+
+// Broadcast asynchronously broadcasts a message within the group to all of its members.
+func (g *PeerGroup) Broadcast(ctx context.Context, message proto.Message) {
+	signed, err := g.net.PrepareMessage(withGroupID(ctx, g.id), message)
+	if err != nil {
+		g.net.protocolLog.Error().Err(err).Msg("network: failed to broadcast message within group")
+		return
+	}
+
+	for _, address := range g.Members() {
+		if _, err := g.net.Client(address); err != nil {
+			g.net.protocolLog.Warn().Err(err).Str("address", address).Msg("failed to connect to group member")
+			continue
+		}
+
+		if err := g.net.Write(address, signed); err != nil {
+			g.net.protocolLog.Warn().Err(err).Str("address", address).Msg("failed to send message to group member")
+		}
+	}
+}
+
+// Receive dispatches an incoming message sent within the group to the plugins registered
+// to the group. Errors should the sender not be a member of the group.
+func (g *PeerGroup) Receive(ctx *PluginContext) error {
+	if !g.IsMember(ctx.Client().Address) {
+		return ErrNotGroupMember
+	}
+
+	g.net.dispatch(g.plugins.receivers, ctx)
+
+	return nil
+}
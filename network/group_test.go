@@ -0,0 +1,83 @@
+package network_test
+
+import (
+	"context"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/perlin-network/noise/internal/test/protobuf"
+	"github.com/perlin-network/noise/network"
+	"github.com/perlin-network/noise/testutil"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPeerGroups(t *testing.T) {
+	t.Parallel()
+
+	global := make([]*testMessageCounter, 4)
+
+	cluster, err := testutil.NewCluster(len(global), testutil.WithSetup(func(i int, builder *network.Builder) {
+		global[i] = new(testMessageCounter)
+		builder.AddPlugin(global[i])
+	}))
+	assert.Nil(t, err)
+	defer cluster.Stop()
+
+	// Nodes 0 and 1 are members of group A, and nodes 2 and 3 are members of group B.
+	members := map[string][]int{"a": {0, 1}, "b": {2, 3}}
+
+	groups := make(map[string][]*network.PeerGroup)
+	counters := make(map[string][]*testMessageCounter)
+
+	for id, indices := range members {
+		for i := 0; i < len(global); i++ {
+			counter := new(testMessageCounter)
+
+			group, err := cluster.Node(i).CreateGroup([]byte(id), counter)
+			assert.Nil(t, err)
+
+			for _, j := range indices {
+				if j != i {
+					group.AddMember(cluster.Node(j).Address)
+				}
+			}
+
+			groups[id] = append(groups[id], group)
+			counters[id] = append(counters[id], counter)
+		}
+	}
+
+	_, err = cluster.Node(0).CreateGroup([]byte("a"))
+	assert.Equal(t, network.ErrGroupExists, err)
+
+	groups["a"][0].Broadcast(context.Background(), &protobuf.TestMessage{Message: "group a"})
+
+	deadline := time.Now().Add(3 * time.Second)
+	for atomic.LoadInt64(&counters["a"][1].received) < 1 {
+		if time.Now().After(deadline) {
+			t.Fatal("expected member of group A to receive the broadcast")
+		}
+		time.Sleep(50 * time.Millisecond)
+	}
+
+	// A peer which is not a member of group A on the receiving end may not send within it.
+	groups["a"][2].AddMember(cluster.Node(1).Address)
+	assert.Nil(t, groups["a"][2].Send(context.Background(), cluster.Node(1).Address, &protobuf.TestMessage{Message: "intruder"}))
+
+	assert.Equal(t, network.ErrNotGroupMember, groups["b"][2].Send(context.Background(), cluster.Node(0).Address, &protobuf.TestMessage{}))
+
+	time.Sleep(200 * time.Millisecond)
+
+	assert.EqualValues(t, 1, atomic.LoadInt64(&counters["a"][1].received))
+
+	for i := range global {
+		assert.EqualValues(t, 0, atomic.LoadInt64(&counters["b"][i].received), "expected group B plugin of node %d to receive nothing", i)
+		assert.EqualValues(t, 0, atomic.LoadInt64(&global[i].received), "expected plugin of node %d outside of any group to receive nothing", i)
+	}
+
+	for _, i := range []int{0, 2, 3} {
+		assert.EqualValues(t, 0, atomic.LoadInt64(&counters["a"][i].received), "expected group A plugin of node %d to receive nothing", i)
+	}
+}
@@ -0,0 +1,152 @@
+package network
+
+import (
+	"sync"
+	"time"
+
+	"github.com/perlin-network/noise/peer"
+)
+
+// defaultConnectionHistorySize is the number of connection events a network keeps by default.
+const defaultConnectionHistorySize = 256
+
+// ConnectionEventType denotes what happened to a connection.
+type ConnectionEventType uint8
+
+const (
+	// EventConnected denotes a peer client having been initialized.
+	EventConnected ConnectionEventType = iota + 1
+	// EventDisconnected denotes a peer client having been closed.
+	EventDisconnected
+	// EventDialFailed denotes a peer having failed to be dialed at all of its addresses.
+	EventDialFailed
+	// EventAcceptFailed denotes an incoming connection having been dropped before its peer
+	// was identified.
+	EventAcceptFailed
+	// EventMigrated denotes a peer having migrated to a new address, which the event carries.
+	EventMigrated
+	// EventReconnected denotes a peer having been dialed again after its connection dropped.
+	EventReconnected
+	// EventFloodControlTriggered denotes a peer having exceeded the flood control threshold,
+	// such that its messages are dropped for the rest of the current window.
+	EventFloodControlTriggered
+)
+
+func (t ConnectionEventType) String() string {
+	switch t {
+	case EventConnected:
+		return "connected"
+	case EventDisconnected:
+		return "disconnected"
+	case EventDialFailed:
+		return "dial_failed"
+	case EventAcceptFailed:
+		return "accept_failed"
+	case EventMigrated:
+		return "migrated"
+	case EventReconnected:
+		return "reconnected"
+	case EventFloodControlTriggered:
+		return "flood_control_triggered"
+	default:
+		return "unknown"
+	}
+}
+
+// ConnectionEvent records something having happened to a connection.
+type ConnectionEvent struct {
+	Time time.Time
+
+	// PeerID is the ID of the peer, which is empty should the peer not have identified itself yet.
+	PeerID peer.ID
+
+	EventType ConnectionEventType
+
+	// RemoteAddr is the address of the peer. Events of incoming connections which failed
+	// to be accepted carry the address the connection originates from instead.
+	RemoteAddr string
+
+	// Error is why the connection failed to be dialed or accepted, or why messages from the
+	// peer were dropped, and nil otherwise.
+	Error error
+}
+
+// connectionHistory is a fixed-size ring buffer of connection events.
+type connectionHistory struct {
+	sync.Mutex
+
+	events []ConnectionEvent
+	next   int
+	full   bool
+}
+
+func newConnectionHistory(size int) *connectionHistory {
+	if size <= 0 {
+		size = defaultConnectionHistorySize
+	}
+	return &connectionHistory{events: make([]ConnectionEvent, size)}
+}
+
+// push records an event, overwriting the oldest event should the buffer be full.
+func (h *connectionHistory) push(event ConnectionEvent) {
+	h.Lock()
+	defer h.Unlock()
+
+	h.events[h.next] = event
+	h.next = (h.next + 1) % len(h.events)
+
+	if h.next == 0 {
+		h.full = true
+	}
+}
+
+// last returns at most the n most recent events, oldest first.
+func (h *connectionHistory) last(n int) []ConnectionEvent {
+	h.Lock()
+	defer h.Unlock()
+
+	size := h.next
+	if h.full {
+		size = len(h.events)
+	}
+
+	if n > size {
+		n = size
+	}
+
+	if n <= 0 {
+		return nil
+	}
+
+	events := make([]ConnectionEvent, n)
+	start := h.next - n + len(h.events)
+
+	for i := range events {
+		events[i] = h.events[(start+i)%len(h.events)]
+	}
+
+	return events
+}
+
+// recordConnectionEvent adds an event to the networks connection history.
+func (n *Network) recordConnectionEvent(eventType ConnectionEventType, id *peer.ID, remoteAddr string, err error) {
+	event := ConnectionEvent{
+		Time:       n.opts.clock.Now(),
+		EventType:  eventType,
+		RemoteAddr: remoteAddr,
+		Error:      err,
+	}
+
+	if id != nil {
+		event.PeerID = *id
+	}
+
+	n.history.push(event)
+}
+
+// GetConnectionHistory returns at most the n most recent connection events in chronological
+// order. The network keeps the 256 most recent events, unless set otherwise through the
+// ConnectionHistorySize option.
+func (n *Network) GetConnectionHistory(count int) []ConnectionEvent {
+	return n.history.last(count)
+}
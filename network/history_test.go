@@ -0,0 +1,31 @@
+package network
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestConnectionHistoryWraps(t *testing.T) {
+	t.Parallel()
+
+	history := newConnectionHistory(4)
+
+	assert.Empty(t, history.last(4))
+
+	for i := 0; i < 6; i++ {
+		history.push(ConnectionEvent{RemoteAddr: string(rune('a' + i))})
+	}
+
+	var addrs []string
+	for _, event := range history.last(10) {
+		addrs = append(addrs, event.RemoteAddr)
+	}
+	assert.Equal(t, []string{"c", "d", "e", "f"}, addrs)
+
+	events := history.last(2)
+	if assert.Len(t, events, 2) {
+		assert.Equal(t, "e", events[0].RemoteAddr)
+		assert.Equal(t, "f", events[1].RemoteAddr)
+	}
+}
@@ -0,0 +1,103 @@
+package network
+
+import (
+	"bytes"
+	"encoding/binary"
+	"sync"
+
+	"github.com/pierrec/lz4/v4"
+	"github.com/pkg/errors"
+)
+
+const (
+	// lz4Stored marks the size prefixing a LZ4 frame whose data was stored as is, as it
+	// could not be compressed.
+	lz4Stored = 1 << 31
+)
+
+var (
+	errLZ4FrameCorrupted = errors.New("lz4: frame is corrupted")
+)
+
+// lz4Compressors pools LZ4 compressors, whose hash tables are expensive to allocate.
+var lz4Compressors = sync.Pool{
+	New: func() interface{} {
+		return new(lz4.Compressor)
+	},
+}
+
+// lz4Codec compresses frames into LZ4 blocks, each prefixed with the size of its data.
+type lz4Codec struct{}
+
+func (lz4Codec) compress(buffer *bytes.Buffer, data []byte) error {
+	var size [4]byte
+	binary.BigEndian.PutUint32(size[:], uint32(len(data)))
+
+	start := buffer.Len()
+	buffer.Write(size[:])
+
+	bound := lz4.CompressBlockBound(len(data))
+	buffer.Grow(bound)
+
+	block := buffer.AvailableBuffer()[:bound]
+
+	c := lz4Compressors.Get().(*lz4.Compressor)
+	n, err := c.CompressBlock(data, block)
+	lz4Compressors.Put(c)
+
+	if err != nil {
+		return err
+	}
+
+	if n == 0 {
+		frame := buffer.Bytes()[start:]
+		binary.BigEndian.PutUint32(frame, uint32(len(data))|lz4Stored)
+
+		buffer.Write(data)
+		return nil
+	}
+
+	buffer.Write(block[:n])
+	return nil
+}
+
+func (lz4Codec) decompress(frame []byte, maxSize int) ([]byte, error) {
+	if len(frame) < 4 {
+		return nil, errLZ4FrameCorrupted
+	}
+
+	size := binary.BigEndian.Uint32(frame)
+	block := frame[4:]
+
+	if size&lz4Stored != 0 {
+		if int64(size&^lz4Stored) > int64(maxSize) {
+			return nil, errCompressedFrameTooLarge
+		}
+		if int(size&^lz4Stored) != len(block) {
+			return nil, errLZ4FrameCorrupted
+		}
+		return block, nil
+	}
+
+	if int64(size) > int64(maxSize) {
+		return nil, errCompressedFrameTooLarge
+	}
+
+	data := make([]byte, size)
+
+	n, err := lz4.UncompressBlock(block, data)
+	if err != nil {
+		return nil, err
+	}
+
+	if n != len(data) {
+		return nil, errLZ4FrameCorrupted
+	}
+
+	return data, nil
+}
+
+// bound accounts for the size prefixing the block of a frame.
+func (lz4Codec) bound(size int) int {
+	return 4 + lz4.CompressBlockBound(size)
+}
@@ -2,9 +2,14 @@ package network
 
 import (
 	"context"
+	"sync/atomic"
+	"time"
 
 	"github.com/gogo/protobuf/proto"
 	"github.com/perlin-network/noise/peer"
+	"github.com/pkg/errors"
+	"github.com/rs/zerolog"
+	"go.opentelemetry.io/otel/trace"
 )
 
 // PluginContext provides parameters and helper functions to a Plugin
@@ -13,13 +18,101 @@ type PluginContext struct {
 	client  *PeerClient
 	message proto.Message
 	nonce   uint64
+
+	// deadline is the time as of the networks clock after which the sender no longer
+	// awaits a reply. Zero if none.
+	deadline time.Time
+
+	// relayedFrom is the ID of the peer a relayed message originated from.
+	relayedFrom *peer.ID
+
+	// plugin is the plugin the message is currently being dispatched to.
+	plugin PluginInterface
+
+	// traceContext carries the span the message was sent within, and ctx the span of
+	// the plugin currently receiving the message.
+	traceContext context.Context
+	ctx          context.Context
+
+	// remaining are the plugins the message is yet to be dispatched to, and forwarded is
+	// true once the message has been forwarded to them by a plugin.
+	remaining []*PluginInfo
+	forwarded bool
+
+	// outcome is the outcome of the plugin receiving the message within the plugin
+	// timeout. Nil should plugins not time out.
+	outcome *uint32
 }
 
-// Reply sends back a message to an incoming message's incoming stream.
+// Reply sends back a message to an incoming message's incoming stream. Errors with
+// context.DeadlineExceeded should the deadline of the incoming message have passed.
 func (pctx *PluginContext) Reply(ctx context.Context, message proto.Message) error {
+	if deadline, ok := pctx.Deadline(); ok && pctx.client.Network.opts.clock.Now().After(deadline) {
+		return context.DeadlineExceeded
+	}
 	return pctx.client.Reply(ctx, pctx.nonce, message)
 }
 
+// Forward replaces the message with msg, and dispatches it to the plugins the message is
+// yet to be dispatched to, returning once they have handled it. Once forwarded, the
+// message is no longer dispatched to them after the current plugin returns, such that a
+// plugin may act as middleware which transforms messages before further plugins see them.
+//
+// Plugins which timed out receiving the message do not forward it, as it was already
+// dispatched to the remaining plugins in their stead.
+func (pctx *PluginContext) Forward(msg proto.Message) error {
+	if msg == nil {
+		return errors.New("network: cannot forward a nil message")
+	}
+
+	if pctx.outcome != nil {
+		atomic.CompareAndSwapUint32(pctx.outcome, 0, receiveForwarded)
+
+		if atomic.LoadUint32(pctx.outcome) == receiveAbandoned {
+			return nil
+		}
+	}
+
+	plugin, ctx := pctx.plugin, pctx.ctx
+
+	pctx.message = msg
+	pctx.forwarded = true
+
+	pctx.client.Network.dispatch(pctx.remaining, pctx)
+
+	pctx.plugin, pctx.ctx = plugin, ctx
+
+	return nil
+}
+
+// Deadline returns the time as of the networks clock after which the sender of the message
+// no longer awaits a reply, should the message have been sent with a context created by
+// WithTimeout. The deadline is counted from when the message was received.
+func (pctx *PluginContext) Deadline() (time.Time, bool) {
+	return pctx.deadline, !pctx.deadline.IsZero()
+}
+
+// SendSignedResponse sends back a signed message to an incoming message's incoming stream,
+// regardless of whether the context of the reply asks for messages to be signed.
+func (pctx *PluginContext) SendSignedResponse(message proto.Message) error {
+	return pctx.Reply(WithSignMessage(context.Background(), true), message)
+}
+
+// Context returns a context carrying the span the plugin is receiving the message within,
+// which is a child of the span the message was sent within should tracing be enabled.
+func (pctx *PluginContext) Context() context.Context {
+	if pctx.ctx == nil {
+		return context.Background()
+	}
+	return pctx.ctx
+}
+
+// Span returns the span the plugin is receiving the message within. The span is a no-op
+// should tracing be disabled.
+func (pctx *PluginContext) Span() trace.Span {
+	return trace.SpanFromContext(pctx.Context())
+}
+
 // Message returns the decoded protobuf message.
 func (pctx *PluginContext) Message() proto.Message {
 	return pctx.message
@@ -44,3 +137,22 @@ func (pctx *PluginContext) Self() peer.ID {
 func (pctx *PluginContext) Sender() peer.ID {
 	return *pctx.client.ID
 }
+
+// Logger returns the logger of the plugin handling the message.
+func (pctx *PluginContext) Logger() zerolog.Logger {
+	return pctx.Network().PluginLogger(pctx.plugin)
+}
+
+// IsRelayed returns true if the message was relayed on behalf of another peer.
+func (pctx *PluginContext) IsRelayed() bool {
+	return pctx.relayedFrom != nil
+}
+
+// RelayedFrom returns the ID of the peer a relayed message originated from. Returns
+// the sender's ID should the message not have been relayed.
+func (pctx *PluginContext) RelayedFrom() peer.ID {
+	if pctx.relayedFrom == nil {
+		return pctx.Sender()
+	}
+	return *pctx.relayedFrom
+}
@@ -0,0 +1,105 @@
+package network
+
+import (
+	"context"
+	"encoding/binary"
+	"io"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/perlin-network/noise/crypto"
+	"github.com/perlin-network/noise/internal/protobuf"
+
+	"github.com/gogo/protobuf/proto"
+	"github.com/stretchr/testify/assert"
+)
+
+// signedResponsePlugin replies to pings with signed pongs.
+type signedResponsePlugin struct {
+	*Plugin
+}
+
+func (p *signedResponsePlugin) Receive(ctx *PluginContext) error {
+	if _, ok := ctx.Message().(*protobuf.Ping); ok {
+		return ctx.SendSignedResponse(&protobuf.Pong{})
+	}
+	return nil
+}
+
+func TestSendSignedResponse(t *testing.T) {
+	t.Parallel()
+
+	builder := NewBuilder()
+	builder.SetAddress(FormatAddress("tcp", "localhost", uint16(GetRandomUnusedPort())))
+	builder.AddPlugin(new(signedResponsePlugin))
+
+	responder, err := builder.Build()
+	assert.Nil(t, err)
+
+	go responder.Listen()
+	defer responder.Close()
+
+	responder.BlockUntilListening()
+
+	// Stand in for a requesting node to inspect the raw response.
+	listener, err := net.Listen("tcp", "localhost:0")
+	assert.Nil(t, err)
+	defer listener.Close()
+
+	builder = NewBuilder()
+	builder.SetAddress(FormatAddress("tcp", "localhost", uint16(listener.Addr().(*net.TCPAddr).Port)))
+
+	requester, err := builder.Build()
+	assert.Nil(t, err)
+
+	// The request itself is not signed.
+	request, err := requester.PrepareMessage(context.Background(), &protobuf.Ping{})
+	assert.Nil(t, err)
+	assert.Nil(t, request.Signature)
+	request.RequestNonce = 1
+
+	raw, err := proto.Marshal(request)
+	assert.Nil(t, err)
+
+	frame := make([]byte, 4+len(raw))
+	binary.BigEndian.PutUint32(frame, uint32(len(raw)))
+	copy(frame[4:], raw)
+
+	info, err := ParseAddress(responder.Address)
+	assert.Nil(t, err)
+
+	conn, err := net.Dial("tcp", info.HostPort())
+	assert.Nil(t, err)
+	defer conn.Close()
+
+	_, err = conn.Write(frame)
+	assert.Nil(t, err)
+
+	incoming, err := listener.Accept()
+	assert.Nil(t, err)
+	defer incoming.Close()
+
+	incoming.SetReadDeadline(time.Now().Add(3 * time.Second))
+
+	_, err = io.ReadFull(incoming, frame[:4])
+	assert.Nil(t, err)
+
+	raw = make([]byte, binary.BigEndian.Uint32(frame[:4]))
+	_, err = io.ReadFull(incoming, raw)
+	assert.Nil(t, err)
+
+	response := new(protobuf.Message)
+	assert.Nil(t, proto.Unmarshal(raw, response))
+
+	assert.True(t, response.ReplyFlag)
+	assert.Equal(t, uint64(1), response.RequestNonce)
+	assert.NotNil(t, response.Signature, "expected response to be signed")
+	assert.True(t, crypto.Verify(
+		responder.opts.signaturePolicy,
+		responder.opts.hashPolicy,
+		response.Sender.PublicKey,
+		serializeSignedMessage(response),
+		response.Signature,
+	), "expected response to have a valid signature")
+}
@@ -0,0 +1,290 @@
+package network
+
+import (
+	"context"
+	"encoding/hex"
+	"net"
+	"time"
+
+	"github.com/perlin-network/noise/crypto"
+	"github.com/perlin-network/noise/internal/protobuf"
+	"github.com/perlin-network/noise/peer"
+
+	"github.com/gogo/protobuf/proto"
+	"github.com/pkg/errors"
+)
+
+const (
+	// migrationDomain prefixes the payload address migrations are signed over, such that their
+	// signatures may not be mistaken for signatures of other payloads.
+	migrationDomain = "noise-migration"
+
+	// maxMigrationAge is the age past which address migrations are no longer accepted.
+	maxMigrationAge = 5 * time.Minute
+
+	// maxMigrationClockSkew is how far ahead of the clock of the network address migrations
+	// may be timestamped.
+	maxMigrationClockSkew = time.Minute
+)
+
+var (
+	errInvalidMigration = errors.New("network: address migration was not signed by the peer")
+	errStaleMigration   = errors.New("network: address migration is stale, replayed or timestamped in the future")
+)
+
+// migrationPayload returns the bytes an address migration is signed over, which is the
+// migration serialized without its signature, prefixed by migrationDomain.
+func migrationPayload(migration *protobuf.AddressMigration) ([]byte, error) {
+	unsigned := *migration
+	unsigned.Signature = nil
+
+	payload, err := unsigned.Marshal()
+	if err != nil {
+		return nil, err
+	}
+
+	return append([]byte(migrationDomain), payload...), nil
+}
+
+// MigrateAddress updates the publicly visible address of the network, e.g. after the
+// address of a mobile node has changed, and notifies all active peers of the new address.
+//
+// Peers re-establish their connection to the network at the new address upon verifying
+// the notification was signed with the networks static private key, and is newer than
+// any other migration of the network they accepted.
+func (n *Network) MigrateAddress(ctx context.Context, address string) error {
+	address, err := ToUnifiedAddress(address)
+	if err != nil {
+		return err
+	}
+
+	migration := &protobuf.AddressMigration{NewAddress: address, Timestamp: n.nextMigrationTimestamp()}
+
+	payload, err := migrationPayload(migration)
+	if err != nil {
+		return errors.Wrap(err, "failed to serialize address migration")
+	}
+
+	migration.Signature, err = n.keys.Sign(n.opts.signaturePolicy, n.opts.hashPolicy, payload)
+	if err != nil {
+		return errors.Wrap(err, "failed to sign new address")
+	}
+
+	n.SetAddress(address)
+
+	n.Broadcast(ctx, migration)
+
+	return nil
+}
+
+// nextMigrationTimestamp returns the timestamp of a new migration of the network, which is
+// the current time, or is just past the timestamp of the prior migration should the clock
+// not have advanced since.
+func (n *Network) nextMigrationTimestamp() uint64 {
+	n.migrationsMutex.Lock()
+	defer n.migrationsMutex.Unlock()
+
+	timestamp := uint64(n.opts.clock.Now().UnixNano())
+	if timestamp <= n.lastMigration {
+		timestamp = n.lastMigration + 1
+	}
+	n.lastMigration = timestamp
+
+	return timestamp
+}
+
+// acceptMigrationTimestamp returns false should an address migration of the peer with a
+// public key be stale, be timestamped in the future, or not be newer than the latest
+// migration of the peer accepted. Timestamps of migrations are pruned once they are stale,
+// at most once every maximum migration age, upon a migration being accepted.
+func (n *Network) acceptMigrationTimestamp(publicKey []byte, timestamp uint64) bool {
+	now := n.opts.clock.Now()
+	issued := time.Unix(0, int64(timestamp))
+
+	if issued.After(now.Add(maxMigrationClockSkew)) || now.Sub(issued) > maxMigrationAge {
+		return false
+	}
+
+	n.migrationsMutex.Lock()
+	defer n.migrationsMutex.Unlock()
+
+	key := hex.EncodeToString(publicKey)
+
+	if latest, exists := n.migrations[key]; exists && timestamp <= latest {
+		return false
+	}
+
+	if now.Sub(n.migrationsPruned) >= maxMigrationAge {
+		for key, latest := range n.migrations {
+			if now.Sub(time.Unix(0, int64(latest))) > maxMigrationAge {
+				delete(n.migrations, key)
+			}
+		}
+		n.migrationsPruned = now
+	}
+
+	n.migrations[key] = timestamp
+
+	return true
+}
+
+// SetAddress updates the publicly visible address of the network, and the ID it identifies
+// itself to peers with, without notifying peers of the new address.
+func (n *Network) SetAddress(address string) {
+	n.selfMutex.Lock()
+	defer n.selfMutex.Unlock()
+
+	id := peer.CreateID(address, n.keys.PublicKey)
+	id.Multiaddr = n.ID.Multiaddr
+
+	n.Address = address
+	n.ID = id
+}
+
+// SelfAddress returns the publicly visible address of the network. Unlike reading Address,
+// it may be called while the address of the network is being migrated.
+func (n *Network) SelfAddress() string {
+	n.selfMutex.RLock()
+	defer n.selfMutex.RUnlock()
+
+	return n.Address
+}
+
+// SelfID returns the ID the network identifies itself to peers with. Unlike reading ID, it
+// may be called while the address of the network is being migrated.
+func (n *Network) SelfID() peer.ID {
+	n.selfMutex.RLock()
+	defer n.selfMutex.RUnlock()
+
+	return n.ID
+}
+
+// AddressDetector returns the address a network is publicly visible at given the address
+// it is currently known by, such that a network may notice its address has changed.
+type AddressDetector func(current string) (string, error)
+
+// InterfaceAddressDetector is an AddressDetector which replaces the host of the current
+// address with the first non-loopback IPv4 address of the machines network interfaces.
+func InterfaceAddressDetector(current string) (string, error) {
+	info, err := ParseAddress(current)
+	if err != nil {
+		return "", err
+	}
+
+	addrs, err := net.InterfaceAddrs()
+	if err != nil {
+		return "", errors.Wrap(err, "failed to list network interface addresses")
+	}
+
+	for _, addr := range addrs {
+		ipNet, ok := addr.(*net.IPNet)
+		if !ok || ipNet.IP.IsLoopback() || ipNet.IP.To4() == nil {
+			continue
+		}
+
+		info.Host = ipNet.IP.String()
+		return info.String(), nil
+	}
+
+	return "", errors.New("network: no non-loopback network interface address found")
+}
+
+// detectAddressLoop periodically detects the address of the network, and migrates the
+// network to its new address should it have changed.
+func (n *Network) detectAddressLoop() {
+	ticker := n.opts.clock.NewTicker(n.opts.addressDetectionInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-n.kill:
+			return
+		case <-ticker.C():
+			n.detectAddress()
+		}
+	}
+}
+
+func (n *Network) detectAddress() {
+	current := n.SelfAddress()
+
+	address, err := n.opts.addressDetector(current)
+	if err == nil {
+		address, err = ToUnifiedAddress(address)
+	}
+	if err != nil {
+		n.connLog.Warn().Err(err).Msg("Failed to detect the address of the network.")
+		return
+	}
+
+	if address == current {
+		return
+	}
+
+	n.connLog.Info().
+		Str("old_address", current).
+		Str("new_address", address).
+		Msg("Detected a change of address, migrating to the new address.")
+
+	if err := n.MigrateAddress(context.Background(), address); err != nil {
+		n.connLog.Warn().Err(err).Msg("Failed to migrate to the detected address.")
+	}
+}
+
+// MapIDToAddress re-establishes the connection to a peer at a new address, closing the
+// peer client of the address the peer was previously known by.
+func (n *Network) MapIDToAddress(id peer.ID, address string) (*PeerClient, error) {
+	client, err := n.Client(address)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to connect to peer at %s", address)
+	}
+
+	if previous, ok := n.peers.Load(id.Address); ok && previous != client {
+		previous.(*PeerClient).Close()
+	}
+
+	return client, nil
+}
+
+// acceptAddressMigration verifies an address migration message sent by the peer of a
+// client, and returns the client of the peer at its new address.
+func (n *Network) acceptAddressMigration(client *PeerClient, msg *protobuf.Message) (*PeerClient, error) {
+	if client.PeerID() == nil {
+		return nil, errors.New("network: peer migrated before identifying itself")
+	}
+
+	migration := new(protobuf.AddressMigration)
+	if err := proto.Unmarshal(msg.Message, migration); err != nil {
+		return nil, errors.Wrap(err, "failed to unmarshal address migration")
+	}
+
+	if migration.NewAddress != msg.Sender.Address {
+		return nil, errors.New("network: migrated address does not match the senders address")
+	}
+
+	payload, err := migrationPayload(migration)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to serialize address migration")
+	}
+
+	if !crypto.Verify(
+		n.opts.signaturePolicy,
+		n.opts.hashPolicy,
+		client.ID.PublicKey,
+		payload,
+		migration.Signature,
+	) {
+		return nil, errInvalidMigration
+	}
+
+	if !n.acceptMigrationTimestamp(client.ID.PublicKey, migration.Timestamp) {
+		return nil, errStaleMigration
+	}
+
+	n.recordConnectionEvent(EventMigrated, client.ID, migration.NewAddress, nil)
+
+	migrated := peer.CreateID(migration.NewAddress, client.ID.PublicKey)
+	n.learnAddress(&migrated)
+
+	return n.MapIDToAddress(*client.ID, migration.NewAddress)
+}
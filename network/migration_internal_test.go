@@ -0,0 +1,73 @@
+package network
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"github.com/perlin-network/noise/clock"
+	"github.com/perlin-network/noise/internal/protobuf"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMigrationPayload(t *testing.T) {
+	t.Parallel()
+
+	migration := &protobuf.AddressMigration{NewAddress: "tcp://127.0.0.1:3000", Timestamp: 1, Signature: []byte("signature")}
+
+	payload, err := migrationPayload(migration)
+	assert.Nil(t, err)
+
+	// Migrations are not signed over the bare address, such that signatures of the address
+	// made for other purposes may not be replayed as a migration.
+	assert.True(t, bytes.HasPrefix(payload, []byte(migrationDomain)))
+	assert.NotEqual(t, []byte(migration.NewAddress), payload)
+	assert.Equal(t, []byte("signature"), migration.Signature)
+
+	later := *migration
+	later.Timestamp = 2
+
+	laterPayload, err := migrationPayload(&later)
+	assert.Nil(t, err)
+	assert.NotEqual(t, payload, laterPayload)
+}
+
+func TestAcceptMigrationTimestamp(t *testing.T) {
+	t.Parallel()
+
+	fake := clock.NewFakeClock(time.Unix(1000, 0))
+	n := &Network{opts: options{clock: fake}, migrations: make(map[string]uint64)}
+
+	timestamp := func(offset time.Duration) uint64 {
+		return uint64(fake.Now().Add(offset).UnixNano())
+	}
+
+	first, second := timestamp(0), timestamp(time.Second)
+
+	assert.True(t, n.acceptMigrationTimestamp([]byte("peer"), first))
+	assert.False(t, n.acceptMigrationTimestamp([]byte("peer"), first), "expected replayed migration to be rejected")
+	assert.True(t, n.acceptMigrationTimestamp([]byte("peer"), second))
+	assert.False(t, n.acceptMigrationTimestamp([]byte("peer"), first), "expected older migration to be rejected")
+	assert.True(t, n.acceptMigrationTimestamp([]byte("other"), first))
+
+	assert.False(t, n.acceptMigrationTimestamp([]byte("peer"), timestamp(2*maxMigrationClockSkew)), "expected migration timestamped in the future to be rejected")
+	assert.False(t, n.acceptMigrationTimestamp([]byte("new"), timestamp(-2*maxMigrationAge)), "expected stale migration to be rejected")
+
+	// Timestamps of stale migrations are pruned upon a later migration being accepted.
+	fake.Advance(2 * maxMigrationAge)
+	assert.True(t, n.acceptMigrationTimestamp([]byte("new"), timestamp(0)))
+
+	_, exists := n.migrations["70656572"]
+	assert.False(t, exists, "expected timestamp of stale migration to be pruned")
+}
+
+func TestNextMigrationTimestamp(t *testing.T) {
+	t.Parallel()
+
+	n := &Network{opts: options{clock: clock.NewFakeClock(time.Unix(1000, 0))}}
+
+	// Migrations made while the clock does not advance are still ordered.
+	first := n.nextMigrationTimestamp()
+	assert.True(t, n.nextMigrationTimestamp() > first)
+}
@@ -0,0 +1,266 @@
+package network_test
+
+import (
+	"context"
+	"io"
+	"net"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/perlin-network/noise/clock"
+	"github.com/perlin-network/noise/internal/test/protobuf"
+	"github.com/perlin-network/noise/network"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// senderRecorder records the addresses of the peer clients test messages are received from.
+type senderRecorder struct {
+	*network.Plugin
+
+	mutex     sync.Mutex
+	addresses []string
+}
+
+func (p *senderRecorder) Receive(ctx *network.PluginContext) error {
+	if _, ok := ctx.Message().(*protobuf.TestMessage); ok {
+		p.mutex.Lock()
+		p.addresses = append(p.addresses, ctx.Client().Address)
+		p.mutex.Unlock()
+	}
+	return nil
+}
+
+func (p *senderRecorder) count() int {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+
+	return len(p.addresses)
+}
+
+func (p *senderRecorder) last() string {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+
+	if len(p.addresses) == 0 {
+		return ""
+	}
+	return p.addresses[len(p.addresses)-1]
+}
+
+// forward accepts connections on a new port and forwards them to a target address, such
+// that a network may be reached at a new address. Returns the number of forwarded connections.
+func forward(t *testing.T, target string) (net.Listener, *int64) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	assert.Nil(t, err)
+
+	forwarded := new(int64)
+
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+
+			upstream, err := net.Dial("tcp", target)
+			if err != nil {
+				conn.Close()
+				continue
+			}
+
+			atomic.AddInt64(forwarded, 1)
+
+			go func() {
+				io.Copy(upstream, conn)
+				upstream.Close()
+			}()
+			go func() {
+				io.Copy(conn, upstream)
+				conn.Close()
+			}()
+		}
+	}()
+
+	return listener, forwarded
+}
+
+func TestMigrateAddress(t *testing.T) {
+	t.Parallel()
+
+	var nodes []*network.Network
+	var recorders []*senderRecorder
+
+	for i := 0; i < 2; i++ {
+		recorder := new(senderRecorder)
+
+		builder := network.NewBuilder()
+		builder.SetAddress(network.FormatAddress("tcp", "localhost", uint16(network.GetRandomUnusedPort())))
+		builder.AddPlugin(recorder)
+
+		node, err := builder.Build()
+		assert.Nil(t, err)
+
+		go node.Listen()
+		defer node.Close()
+
+		node.BlockUntilListening()
+
+		nodes = append(nodes, node)
+		recorders = append(recorders, recorder)
+	}
+
+	mobile, peer := nodes[0], nodes[1]
+	oldAddress := mobile.Address
+
+	// tell sends a message, and waits for it to be received from an address.
+	tell := func(from *network.Network, to string, recorder *senderRecorder, expected string) {
+		t.Helper()
+
+		received := recorder.count()
+
+		client, err := from.Client(to)
+		if assert.Nil(t, err) {
+			assert.Nil(t, client.Tell(context.Background(), &protobuf.TestMessage{Message: "migration"}))
+		}
+
+		deadline := time.Now().Add(3 * time.Second)
+		for recorder.count() == received {
+			if time.Now().After(deadline) {
+				t.Fatalf("expected a message to be received from %s", expected)
+			}
+			time.Sleep(10 * time.Millisecond)
+		}
+
+		assert.Equal(t, expected, recorder.last())
+	}
+
+	// Have the nodes connect to one another.
+	tell(peer, oldAddress, recorders[0], peer.Address)
+	tell(mobile, peer.Address, recorders[1], oldAddress)
+
+	info, err := network.ParseAddress(oldAddress)
+	assert.Nil(t, err)
+
+	listener, forwarded := forward(t, info.HostPort())
+	defer listener.Close()
+
+	newAddress, err := network.ToUnifiedAddress("tcp://" + listener.Addr().String())
+	assert.Nil(t, err)
+
+	assert.Nil(t, mobile.MigrateAddress(context.Background(), newAddress))
+	assert.Equal(t, newAddress, mobile.Address)
+	assert.Equal(t, newAddress, mobile.ID.Address)
+
+	deadline := time.Now().Add(3 * time.Second)
+	for !peer.ConnectionStateExists(newAddress) {
+		if time.Now().After(deadline) {
+			t.Fatal("expected peer to connect to the new address")
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	assert.False(t, peer.ConnectionStateExists(oldAddress), "expected connection to the old address to be closed")
+	assert.True(t, atomic.LoadInt64(forwarded) > 0, "expected peer to dial the new address")
+
+	migrated := false
+	for _, event := range peer.GetConnectionHistory(100) {
+		if event.EventType == network.EventMigrated && event.RemoteAddr == newAddress {
+			migrated = true
+		}
+	}
+	assert.True(t, migrated, "expected migration to be recorded in the peers connection history")
+
+	// Messages flow both ways with the mobile node being known by its new address.
+	tell(mobile, peer.Address, recorders[1], newAddress)
+	tell(peer, newAddress, recorders[0], peer.Address)
+}
+
+func TestAddressDetection(t *testing.T) {
+	t.Parallel()
+
+	fake := clock.NewFakeClock(time.Now())
+
+	var detected atomic.Value
+
+	detector := func(current string) (string, error) {
+		if address, ok := detected.Load().(string); ok {
+			return address, nil
+		}
+		return current, nil
+	}
+
+	var nodes []*network.Network
+	var recorders []*senderRecorder
+
+	for i := 0; i < 2; i++ {
+		recorder := new(senderRecorder)
+
+		builder := network.NewBuilderWithOptions(network.WithClock(fake))
+		if i == 0 {
+			builder = network.NewBuilderWithOptions(network.WithClock(fake), network.WithAddressDetection(time.Minute, detector))
+		}
+		builder.SetAddress(network.FormatAddress("tcp", "localhost", uint16(network.GetRandomUnusedPort())))
+		builder.AddPlugin(recorder)
+
+		node, err := builder.Build()
+		assert.Nil(t, err)
+
+		go node.Listen()
+		defer node.Close()
+
+		node.BlockUntilListening()
+
+		nodes = append(nodes, node)
+		recorders = append(recorders, recorder)
+	}
+
+	mobile, peer := nodes[0], nodes[1]
+
+	// Have the nodes connect to one another.
+	for _, pair := range [][2]int{{1, 0}, {0, 1}} {
+		from, to := nodes[pair[0]], nodes[pair[1]]
+
+		deadline := time.Now().Add(3 * time.Second)
+		for recorders[pair[1]].last() != from.Address {
+			if time.Now().After(deadline) {
+				t.Fatalf("expected a message to be received from %s", from.Address)
+			}
+
+			client, err := from.Client(to.Address)
+			assert.Nil(t, err)
+			assert.Nil(t, client.Tell(context.Background(), &protobuf.TestMessage{Message: "detection"}))
+
+			time.Sleep(50 * time.Millisecond)
+		}
+	}
+
+	info, err := network.ParseAddress(mobile.SelfAddress())
+	assert.Nil(t, err)
+
+	listener, _ := forward(t, info.HostPort())
+	defer listener.Close()
+
+	newAddress, err := network.ToUnifiedAddress("tcp://" + listener.Addr().String())
+	assert.Nil(t, err)
+
+	detected.Store(newAddress)
+
+	deadline := time.Now().Add(3 * time.Second)
+	for !peer.ConnectionStateExists(newAddress) {
+		if time.Now().After(deadline) {
+			t.Fatal("expected peer to connect to the detected address")
+		}
+		fake.Advance(time.Minute)
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	assert.Equal(t, newAddress, mobile.SelfAddress())
+	assert.Equal(t, newAddress, mobile.SelfID().Address)
+
+	address, err := peer.Resolver().Resolve(mobile.SelfID().Id)
+	assert.Nil(t, err)
+	assert.Equal(t, newAddress, address, "expected the resolver to resolve the mobile node to its new address")
+}
@@ -0,0 +1,183 @@
+package network
+
+import (
+	"bytes"
+	"net"
+	"strconv"
+	"strings"
+
+	"github.com/perlin-network/noise/peer"
+
+	"github.com/pkg/errors"
+)
+
+const (
+	// ErrStrInvalidMultiaddr returns if a multiaddr is malformed.
+	ErrStrInvalidMultiaddr = "multiaddr: invalid address %q"
+)
+
+// udpProtocols are transport protocols which run over UDP rather than TCP.
+var udpProtocols = map[string]struct{}{
+	"udp":  {},
+	"kcp":  {},
+	"quic": {},
+}
+
+// ParseMultiaddr parses a comma-separated list of addresses, e.g.
+// `/ip4/1.2.3.4/tcp/8000,/ip4/1.2.3.4/udp/8000/quic`, into network addresses in
+// preference order, e.g. `tcp://1.2.3.4:8000` and `quic://1.2.3.4:8000`.
+//
+// The transport protocol of an address is its trailing component should it be
+// layered over TCP or UDP, and TCP or UDP itself otherwise.
+func ParseMultiaddr(multiaddr string) ([]string, error) {
+	var addresses []string
+
+	for _, raw := range strings.Split(multiaddr, ",") {
+		raw = strings.TrimSpace(raw)
+		if len(raw) == 0 {
+			continue
+		}
+
+		parts := strings.Split(strings.TrimPrefix(raw, "/"), "/")
+		if !strings.HasPrefix(raw, "/") || len(parts) < 4 || len(parts) > 5 {
+			return nil, errors.Errorf(ErrStrInvalidMultiaddr, raw)
+		}
+
+		switch parts[0] {
+		case "ip4", "ip6", "dns", "dns4", "dns6":
+		default:
+			return nil, errors.Errorf(ErrStrInvalidMultiaddr, raw)
+		}
+
+		if parts[2] != "tcp" && parts[2] != "udp" {
+			return nil, errors.Errorf(ErrStrInvalidMultiaddr, raw)
+		}
+
+		port, err := strconv.ParseUint(parts[3], 10, 16)
+		if err != nil {
+			return nil, errors.Wrapf(err, ErrStrInvalidMultiaddr, raw)
+		}
+
+		protocol := parts[2]
+		if len(parts) == 5 {
+			protocol = parts[4]
+		}
+
+		addresses = append(addresses, FormatAddress(protocol, parts[1], uint16(port)))
+	}
+
+	if len(addresses) == 0 {
+		return nil, errors.Errorf(ErrStrInvalidMultiaddr, multiaddr)
+	}
+
+	return addresses, nil
+}
+
+// FormatMultiaddr formats network addresses in preference order into a comma-separated
+// multiaddr. It is the inverse of ParseMultiaddr.
+func FormatMultiaddr(addresses ...string) (string, error) {
+	var formatted []string
+
+	for _, address := range addresses {
+		info, err := ParseAddress(address)
+		if err != nil {
+			return "", err
+		}
+
+		host := "/dns/" + info.Host
+		if ip := net.ParseIP(info.Host); ip != nil {
+			if ip.To4() != nil {
+				host = "/ip4/" + info.Host
+			} else {
+				host = "/ip6/" + info.Host
+			}
+		}
+
+		base := "tcp"
+		if _, ok := udpProtocols[info.Protocol]; ok {
+			base = "udp"
+		}
+
+		multiaddr := host + "/" + base + "/" + strconv.Itoa(int(info.Port))
+		if info.Protocol != base {
+			multiaddr += "/" + info.Protocol
+		}
+
+		formatted = append(formatted, multiaddr)
+	}
+
+	return strings.Join(formatted, ","), nil
+}
+
+// dialAddresses returns the addresses a peer may be dialed at in preference order, should
+// it have advertised a multiaddr. Addresses over transports which are not registered are
+// skipped, and the peers own address is always dialed last should it not be advertised.
+func (n *Network) dialAddresses(address string) []string {
+	value, ok := n.multiaddrs.Load(address)
+	if !ok {
+		return []string{address}
+	}
+
+	advertised, err := ParseMultiaddr(value.(learnedMultiaddr).multiaddr)
+	if err != nil {
+		return []string{address}
+	}
+
+	var addresses []string
+	included := false
+
+	for _, candidate := range advertised {
+		info, err := ParseAddress(candidate)
+		if err != nil {
+			continue
+		}
+
+		if _, exists := n.transports.Load(info.Protocol); !exists {
+			continue
+		}
+
+		if unified, err := ToUnifiedAddress(candidate); err == nil && unified == address {
+			included = true
+		}
+
+		addresses = append(addresses, candidate)
+	}
+
+	if !included {
+		addresses = append(addresses, address)
+	}
+
+	return addresses
+}
+
+// learnedMultiaddr is a multiaddr advertised by the peer with a public key.
+type learnedMultiaddr struct {
+	publicKey []byte
+	multiaddr string
+}
+
+// learnMultiaddr records the multiaddr a peer advertised alongside its address. The
+// multiaddr recorded for an address is not replaced by other peers while its advertiser
+// remains connected.
+func (n *Network) learnMultiaddr(id *peer.ID) {
+	if len(id.Multiaddr) == 0 {
+		return
+	}
+
+	address, err := ToUnifiedAddress(id.Address)
+	if err != nil {
+		return
+	}
+
+	n.multiaddrsMutex.Lock()
+	defer n.multiaddrsMutex.Unlock()
+
+	if value, ok := n.multiaddrs.Load(address); ok {
+		existing := value.(learnedMultiaddr)
+		if !bytes.Equal(existing.publicKey, id.PublicKey) && n.isConnected(existing.publicKey) {
+			return
+		}
+	}
+
+	n.multiaddrs.Store(address, learnedMultiaddr{publicKey: id.PublicKey, multiaddr: id.Multiaddr})
+}
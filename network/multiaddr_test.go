@@ -0,0 +1,78 @@
+package network
+
+import (
+	"reflect"
+	"sync"
+	"testing"
+
+	"github.com/perlin-network/noise/peer"
+)
+
+func TestParseMultiaddr(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		multiaddr string
+		expected  []string
+	}{
+		{"/ip4/1.2.3.4/tcp/8000", []string{"tcp://1.2.3.4:8000"}},
+		{"/ip4/1.2.3.4/tcp/8000,/ip4/1.2.3.4/udp/8000/quic", []string{"tcp://1.2.3.4:8000", "quic://1.2.3.4:8000"}},
+		{"/dns/example.com/tcp/443/ws", []string{"ws://example.com:443"}},
+		{"/ip6/::1/udp/9000/kcp", []string{"kcp://[::1]:9000"}},
+	}
+	for _, tt := range testCases {
+		addresses, err := ParseMultiaddr(tt.multiaddr)
+		if err != nil {
+			t.Errorf("ParseMultiaddr(%q) = expected no error, got %v", tt.multiaddr, err)
+			continue
+		}
+		if !reflect.DeepEqual(addresses, tt.expected) {
+			t.Errorf("ParseMultiaddr(%q) = %v, expected %v", tt.multiaddr, addresses, tt.expected)
+		}
+
+		formatted, err := FormatMultiaddr(addresses...)
+		if err != nil {
+			t.Errorf("FormatMultiaddr(%v) = expected no error, got %v", addresses, err)
+		}
+		if formatted != tt.multiaddr {
+			t.Errorf("FormatMultiaddr(%v) = %s, expected %s", addresses, formatted, tt.multiaddr)
+		}
+	}
+
+	invalid := []string{
+		"",
+		"ip4/1.2.3.4/tcp/8000",
+		"/ip4/1.2.3.4/sctp/8000",
+		"/ip4/1.2.3.4/tcp/port",
+		"/unix/tmp/tcp/8000",
+		"/ip4/1.2.3.4/udp/8000/quic/extra",
+	}
+	for _, multiaddr := range invalid {
+		if _, err := ParseMultiaddr(multiaddr); err == nil {
+			t.Errorf("ParseMultiaddr(%q) = <nil>, expected an error", multiaddr)
+		}
+	}
+}
+
+func TestLearnMultiaddrKeepsConnectedAdvertiser(t *testing.T) {
+	n := &Network{peers: new(sync.Map), multiaddrs: new(sync.Map)}
+
+	owner := &peer.ID{Address: "tcp://127.0.0.1:3000", PublicKey: []byte("owner"), Multiaddr: "127.0.0.1/tcp/3000"}
+	other := &peer.ID{Address: owner.Address, PublicKey: []byte("other"), Multiaddr: "10.0.0.1/tcp/3000"}
+
+	n.learnMultiaddr(owner)
+	n.peers.Store(owner.Address, &PeerClient{ID: owner})
+
+	// Other peers may not replace the multiaddr of a connected peer.
+	n.learnMultiaddr(other)
+	if value, _ := n.multiaddrs.Load(owner.Address); value.(learnedMultiaddr).multiaddr != owner.Multiaddr {
+		t.Errorf("learnMultiaddr(%v) = expected multiaddr %q to be kept", other, owner.Multiaddr)
+	}
+
+	// Their multiaddr is taken in once the advertiser disconnects.
+	n.peers.Delete(owner.Address)
+	n.learnMultiaddr(other)
+	if value, _ := n.multiaddrs.Load(owner.Address); value.(learnedMultiaddr).multiaddr != other.Multiaddr {
+		t.Errorf("learnMultiaddr(%v) = expected multiaddr %q to be learned", other, other.Multiaddr)
+	}
+}
@@ -4,9 +4,7 @@ import (
 	"net"
 	"time"
 
-	"github.com/perlin-network/noise/log"
 	"github.com/perlin-network/noise/network"
-	"github.com/perlin-network/noise/peer"
 
 	"github.com/fd/go-nat"
 )
@@ -30,11 +28,13 @@ var (
 )
 
 func (p *plugin) Startup(n *network.Network) {
-	log.Info().
-		Str("address", n.Address).
+	logger := n.PluginLogger(p)
+
+	logger.Info().
+		Str("address", n.SelfAddress()).
 		Msg("setting up NAT traversal")
 
-	info, err := network.ParseAddress(n.Address)
+	info, err := network.ParseAddress(n.SelfAddress())
 	if err != nil {
 		return
 	}
@@ -43,7 +43,7 @@ func (p *plugin) Startup(n *network.Network) {
 
 	gateway, err := nat.DiscoverGateway()
 	if err != nil {
-		log.Warn().
+		logger.Warn().
 			Err(err).
 			Msg("unable to discover gateway")
 		return
@@ -51,7 +51,7 @@ func (p *plugin) Startup(n *network.Network) {
 
 	p.internalIP, err = gateway.GetInternalAddress()
 	if err != nil {
-		log.Warn().
+		logger.Warn().
 			Err(err).
 			Msg("unable to fetch internal IP")
 		return
@@ -59,17 +59,17 @@ func (p *plugin) Startup(n *network.Network) {
 
 	p.externalIP, err = gateway.GetExternalAddress()
 	if err != nil {
-		log.Warn().
+		logger.Warn().
 			Err(err).
 			Msg("unable to fetch external IP")
 		return
 	}
 
-	log.Info().
+	logger.Info().
 		Str("protocol", gateway.Type()).
 		Msg("discovered gateway")
 
-	log.Info().
+	logger.Info().
 		Str("internal_ip", p.internalIP.String()).
 		Str("external_ip", p.externalIP.String()).
 		Msg("")
@@ -77,13 +77,13 @@ func (p *plugin) Startup(n *network.Network) {
 	p.externalPort, err = gateway.AddPortMapping("tcp", p.internalPort, "noise", 1*time.Second)
 
 	if err != nil {
-		log.Warn().
+		logger.Warn().
 			Err(err).
 			Msg("cannot setup port mapping")
 		return
 	}
 
-	log.Info().
+	logger.Info().
 		Int("internal_port", p.internalPort).
 		Int("external_port", p.externalPort).
 		Msgf("external port now forwards to your local port")
@@ -94,19 +94,20 @@ func (p *plugin) Startup(n *network.Network) {
 	info.Port = uint16(p.externalPort)
 
 	// Set peer information based off of port mapping info.
-	n.Address = info.String()
-	n.ID = peer.CreateID(n.Address, n.GetKeys().PublicKey)
+	n.SetAddress(info.String())
 
-	log.Info().Msgf("other peers may connect to you through the address %s.", n.Address)
+	logger.Info().Msgf("other peers may connect to you through the address %s.", n.SelfAddress())
 }
 
 func (p *plugin) Cleanup(n *network.Network) {
 	if p.gateway != nil {
-		log.Info().Msg("removing port binding...")
+		logger := n.PluginLogger(p)
+
+		logger.Info().Msg("removing port binding...")
 
 		err := p.gateway.DeletePortMapping("tcp", p.internalPort)
 		if err != nil {
-			log.Error().Err(err).Msg("")
+			logger.Error().Err(err).Msg("")
 		}
 	}
 }
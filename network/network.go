@@ -2,13 +2,20 @@ package network
 
 import (
 	"bufio"
+	"bytes"
 	"context"
+	"math"
 	"math/rand"
 	"net"
+	"reflect"
+	"runtime/debug"
+	"sort"
+	"strings"
 	"sync"
 	"sync/atomic"
 	"time"
 
+	"github.com/perlin-network/noise/clock"
 	"github.com/perlin-network/noise/crypto"
 	"github.com/perlin-network/noise/internal/protobuf"
 	"github.com/perlin-network/noise/log"
@@ -18,6 +25,9 @@ import (
 
 	"github.com/gogo/protobuf/proto"
 	"github.com/pkg/errors"
+	"github.com/rs/zerolog"
+	"go.opentelemetry.io/otel/trace"
+	"golang.org/x/time/rate"
 )
 
 const (
@@ -27,6 +37,18 @@ const (
 	defaultWriteBufferSize   = 4096
 	defaultWriteFlushLatency = 50 * time.Millisecond
 	defaultWriteTimeout      = 3 * time.Second
+	defaultRequestTimeout    = 10 * time.Second
+
+	// defaultMaxMessageSize limits incoming messages to 4MB. If a big message need be
+	// sent, consider partitioning the message into chunks.
+	defaultMaxMessageSize = 4000000
+
+	// drainPollInterval is how often connections are checked for having drained upon close.
+	drainPollInterval = 10 * time.Millisecond
+
+	// maxHeldMessages is the number of messages held back from a plugin which is not yet
+	// ready, beyond which further messages are dropped.
+	maxHeldMessages = 1024
 )
 
 var contextPool = sync.Pool{
@@ -41,12 +63,31 @@ var (
 
 // Network represents the current networking state for this node.
 type Network struct {
+	// sequence is the last sequence number assigned to an outgoing message. Kept first
+	// for 64-bit alignment of atomic operations.
+	sequence uint64
+
+	// messagesDropped counts incoming messages dropped as replays.
+	messagesDropped uint64
+
+	// maxMessageSize is the largest size in bytes an incoming message may have.
+	maxMessageSize int64
+
 	opts options
 
+	// connLog logs the lifecycle of connections to peers.
+	connLog zerolog.Logger
+
+	// protocolLog logs the dispatching of messages to plugins.
+	protocolLog zerolog.Logger
+
 	// Node's keypair.
 	keys *crypto.KeyPair
 
 	// Full address to listen on. `protocol://host:port`
+	//
+	// The address changes should the network migrate to a new address. Use SelfAddress to
+	// read it while the network is running.
 	Address string
 
 	// Map of plugins registered to the network.
@@ -54,8 +95,14 @@ type Network struct {
 	plugins *PluginList
 
 	// Node's cryptographic ID.
+	//
+	// The ID changes should the network migrate to a new address. Use SelfID to read it
+	// while the network is running.
 	ID peer.ID
 
+	// selfMutex guards the address and ID of the network.
+	selfMutex sync.RWMutex
+
 	// Map of connection addresses (string) <-> *network.PeerClient
 	// so that the Network doesn't dial multiple times to the same ip
 	peers *sync.Map
@@ -68,11 +115,75 @@ type Network struct {
 	// Map of protocol addresses (string) <-> *transport.Layer
 	transports *sync.Map
 
+	// Map of peer public keys (string) <-> *replayWindow
+	replayWindows *replayWindows
+
+	// Map of peer addresses (string) <-> the learnedMultiaddr they advertised
+	multiaddrs      *sync.Map
+	multiaddrsMutex sync.Mutex
+
+	// observers are notified of traffic flowing through the network.
+	observersMutex sync.RWMutex
+	observers      []ObserverInterface
+
+	// sessionMutex guards the session keys of the network, which may be set after the
+	// network has started listening.
+	sessionMutex sync.RWMutex
+
+	// history records the most recent connection events.
+	history *connectionHistory
+
+	// Map of dialed addresses (string) <-> *circuitBreaker
+	breakers sync.Map
+
+	// breakersMutex guards when circuit breakers were last pruned.
+	breakersMutex  sync.Mutex
+	breakersPruned time.Time
+
+	// Map of addresses claimed by peers (string) <-> *claimedAddress
+	claims sync.Map
+
+	// claimsMutex guards when claimed addresses were last pruned.
+	claimsMutex  sync.Mutex
+	claimsPruned time.Time
+
+	// Map of peer public keys hex (string) <-> timestamp of the latest address migration
+	// of the peer accepted (uint64).
+	migrations map[string]uint64
+
+	// migrationsMutex guards the timestamps of migrations accepted from peers, when they
+	// were last pruned, and the timestamp of the latest migration of the network.
+	migrationsMutex  sync.Mutex
+	migrationsPruned time.Time
+	lastMigration    uint64
+
+	// Map of group IDs (string) <-> *PeerGroup
+	groups sync.Map
+
+	// Map of peer public keys hex, or addresses of unidentified peers (string) <-> *floodCounter
+	floods sync.Map
+
+	// floodsMutex guards when flood counters were last pruned.
+	floodsMutex  sync.Mutex
+	floodsPruned time.Time
+
+	// inboundLimiter and outboundLimiter shape the traffic of all peers combined as per
+	// the networks TrafficShaper. Either is nil should its rate be unlimited.
+	inboundLimiter, outboundLimiter *rate.Limiter
+
+	// createdAt is when the network was built.
+	createdAt time.Time
+
+	// startedAt is when the network first started listening for peers.
+	startMutex sync.Mutex
+	startedAt  time.Time
+
 	// listeningCh will block a goroutine until this node is listening for peers.
 	listeningCh chan struct{}
 
 	// <-kill will begin the server shutdown process
-	kill chan struct{}
+	kill     chan struct{}
+	killOnce sync.Once
 }
 
 // options for network struct
@@ -85,6 +196,67 @@ type options struct {
 	writeBufferSize   int
 	writeFlushLatency time.Duration
 	writeTimeout      time.Duration
+	requestTimeout    time.Duration
+	sendQueueDepth    int
+	sendQueuePolicy   SendQueuePolicy
+	batchWindow       time.Duration
+	pluginTimeout     time.Duration
+	stateDir          string
+	historySize       int
+	clock             clock.Clock
+	pluginLoggers     map[reflect.Type]zerolog.Logger
+
+	// logger is the logger the network and its plugins log to, in place of the global
+	// logger should it be set.
+	logger *zerolog.Logger
+
+	pluginRestartPolicies map[reflect.Type]RestartPolicy
+
+	// sessionKeys signs ephemeral keys exchanged to encrypt connections. Connections
+	// are not encrypted should it be nil.
+	sessionKeys *crypto.KeyPair
+
+	// aesGCM has sessions be encrypted with AES-256-GCM rather than ChaCha20-Poly1305.
+	aesGCM bool
+
+	// negotiateFeatures has connections negotiate which of features to enable.
+	negotiateFeatures bool
+	features          Features
+
+	// capabilities are advertised to peers within pings and pongs.
+	capabilities Capabilities
+
+	// resolver resolves peer IDs to the addresses peers may be dialed at.
+	resolver Resolver
+
+	// proxyProtocol has accepted connections begin with a PROXY protocol v2 header.
+	proxyProtocol bool
+
+	// verifyAddresses has peers prove they listen at the address they claim upon connecting.
+	verifyAddresses bool
+
+	// circuitBreaker stops addresses which keep failing to be dialed from being dialed.
+	// Disabled should its failure threshold be zero.
+	circuitBreaker CircuitBreakerPolicy
+
+	// tracerProvider records spans of messages sent and received. Tracing is disabled
+	// should it be nil.
+	tracerProvider trace.TracerProvider
+
+	// trafficShaper limits the rate of messages received from and sent to all peers.
+	trafficShaper TrafficShaper
+
+	// floodControl drops messages from senders which send them at too high a rate.
+	floodControl FloodControl
+
+	// reconnectPolicy decides whether peers whose connection dropped are dialed again.
+	reconnectPolicy ReconnectPolicy
+
+	// addressDetector detects the address of the network every addressDetectionInterval,
+	// such that the network migrates to its new address should it change. Disabled should
+	// it be nil.
+	addressDetector          AddressDetector
+	addressDetectionInterval time.Duration
 }
 
 // ConnState represents a connection.
@@ -113,7 +285,7 @@ func (n *Network) flushLoop() {
 				if state, ok := value.(*ConnState); ok {
 					state.writerMutex.Lock()
 					if err := state.writer.Flush(); err != nil {
-						log.Warn().Err(err).Msg("")
+						n.connLog.Warn().Err(err).Msg("")
 					}
 					state.writerMutex.Unlock()
 				}
@@ -123,15 +295,96 @@ func (n *Network) flushLoop() {
 	}
 }
 
+// Stats represents statistics of a network.
+type Stats struct {
+	// MessagesDropped is the number of incoming messages dropped as replays.
+	MessagesDropped uint64
+}
+
+// Stats returns statistics of the network.
+func (n *Network) Stats() Stats {
+	return Stats{
+		MessagesDropped: atomic.LoadUint64(&n.messagesDropped),
+	}
+}
+
+// StartTime returns when the network first started listening for peers. Zero should it
+// not have started listening yet.
+func (n *Network) StartTime() time.Time {
+	n.startMutex.Lock()
+	defer n.startMutex.Unlock()
+
+	return n.startedAt
+}
+
+// Clock returns the clock the network times events with, as set through WithClock.
+func (n *Network) Clock() clock.Clock {
+	return n.opts.clock
+}
+
+// MaxMessageSizeBytes returns the largest size in bytes an incoming message may have
+// before its connection is dropped.
+func (n *Network) MaxMessageSizeBytes() int {
+	return int(atomic.LoadInt64(&n.maxMessageSize))
+}
+
+// SetMaxMessageSizeBytes sets the largest size in bytes an incoming message may have,
+// which applies to messages read from both new and existing connections. Sizes which are
+// not positive reset the limit to its default of 4MB, such that messages are never unlimited.
+func (n *Network) SetMaxMessageSizeBytes(size int) {
+	if size <= 0 {
+		size = defaultMaxMessageSize
+	}
+	atomic.StoreInt64(&n.maxMessageSize, int64(size))
+}
+
 // GetKeys returns the keypair for this network
 func (n *Network) GetKeys() *crypto.KeyPair {
 	return n.keys
 }
 
-func (n *Network) dispatchMessage(client *PeerClient, msg *protobuf.Message) {
+// Sign signs a message with the networks static private key, under the networks
+// signature and hash policies.
+func (n *Network) Sign(message []byte) ([]byte, error) {
+	return n.keys.Sign(n.opts.signaturePolicy, n.opts.hashPolicy, message)
+}
+
+// SignWith signs a message with a key pair other than the networks, under the networks
+// signature and hash policies.
+func (n *Network) SignWith(keys *crypto.KeyPair, message []byte) ([]byte, error) {
+	return keys.Sign(n.opts.signaturePolicy, n.opts.hashPolicy, message)
+}
+
+// Verify verifies the signature of a message against a peers public key, under the
+// networks signature and hash policies.
+func (n *Network) Verify(publicKey []byte, message []byte, signature []byte) bool {
+	return crypto.Verify(n.opts.signaturePolicy, n.opts.hashPolicy, publicKey, message, signature)
+}
+
+// pendingMessage is a message awaiting dispatch, along with the time it was read off of its
+// connection as timed by the networks clock.
+type pendingMessage struct {
+	msg        *protobuf.Message
+	receivedAt time.Time
+}
+
+func (n *Network) dispatchMessage(client *PeerClient, msg *protobuf.Message, receivedAt time.Time) {
 	if !client.IsIncomingReady() {
 		return
 	}
+
+	if !n.allowMessage(client) {
+		return
+	}
+
+	// Drop relayed messages which did not originate from the peer they claim to.
+	if msg.RelayedFrom != nil && !n.verifyRelay(msg) {
+		n.protocolLog.Warn().
+			Str("address", client.Address).
+			Msg("network: dropped relayed message with a malformed relay signature")
+		return
+	}
+
 	var ptr proto.Message
 	// unmarshal message based on specified opcode
 	code := opcode.Opcode(msg.Opcode)
@@ -146,21 +399,23 @@ func (n *Network) dispatchMessage(client *PeerClient, msg *protobuf.Message) {
 		ptr = new(protobuf.LookupNodeRequest)
 	case opcode.LookupNodeResponseCode:
 		ptr = new(protobuf.LookupNodeResponse)
+	case opcode.AddressMigrationCode:
+		ptr = new(protobuf.AddressMigration)
 	case opcode.UnregisteredCode:
-		log.Error().Msg("network: message received had no opcode")
+		n.protocolLog.Error().Msg("network: message received had no opcode")
 		return
 	default:
 		var err error
 		ptr, err = opcode.GetMessageType(code)
 		if err != nil {
-			log.Error().Err(err).Msg("network: received message opcode is not registered")
+			n.protocolLog.Error().Err(err).Msg("network: received message opcode is not registered")
 			return
 		}
 	}
 
 	if len(msg.Message) > 0 {
 		if err := proto.Unmarshal(msg.Message, ptr); err != nil {
-			log.Error().Msgf("%v", err)
+			n.protocolLog.Error().Msgf("%v", err)
 			return
 		}
 	}
@@ -176,34 +431,382 @@ func (n *Network) dispatchMessage(client *PeerClient, msg *protobuf.Message) {
 		}
 	}
 
+	// Drop messages whose sender no longer awaits a reply, counting from when they were received.
+	var deadline time.Time
+	if msg.Timeout > 0 {
+		deadline = receivedAt.Add(time.Duration(msg.Timeout))
+
+		if n.opts.clock.Now().After(deadline) {
+			n.protocolLog.Debug().
+				Str("address", client.Address).
+				Msg("network: dropped message dispatched after its deadline")
+			return
+		}
+	}
+
 	switch msgRaw := ptr.(type) {
 	case *protobuf.Bytes:
 		client.handleBytes(msgRaw.Data)
 	default:
+		var group *PeerGroup
+		if len(msg.GroupId) > 0 {
+			var ok bool
+			if group, ok = n.Group(msg.GroupId); !ok {
+				n.protocolLog.Warn().
+					Str("address", client.Address).
+					Msg("network: received message sent within an unknown group")
+				return
+			}
+		}
+
 		ctx := contextPool.Get().(*PluginContext)
 		ctx.client = client
 		ctx.message = msgRaw
 		ctx.nonce = msg.RequestNonce
+		ctx.deadline = deadline
+		ctx.relayedFrom = (*peer.ID)(msg.RelayedFrom)
+		ctx.traceContext = n.extractTraceContext(msg)
+		ctx.ctx = ctx.traceContext
+		ctx.forwarded = false
 
+		client.beginWork()
 		go func() {
-			// Execute 'on receive message' callback for all plugins.
-			n.plugins.Each(func(plugin PluginInterface) {
-				if err := plugin.Receive(ctx); err != nil {
-					log.Error().Err(err).Msg("")
+			defer client.endWork()
+
+			if group != nil {
+				// Messages sent within a group are only dispatched to the groups plugins.
+				if err := group.Receive(ctx); err != nil {
+					n.protocolLog.Warn().Err(err).Str("address", client.Address).Msg("network: dropped group message")
 				}
-			})
+			} else {
+				// Execute 'on receive message' callback for all plugins.
+				n.dispatch(n.plugins.receivers, ctx)
+			}
 
 			contextPool.Put(ctx)
 		}()
 	}
 }
 
+// dispatch executes the 'on receive message' callback of every enabled plugin of a list
+// of receivers in order, until either a plugin stops dispatch or forwards the message.
+//
+// Dispatches nested within a plugin, such as a plugin having a group receive the message,
+// track whether the message was forwarded on their own, such that forwarding the message
+// within the nested dispatch does not stop the dispatch it is nested in.
+func (n *Network) dispatch(receivers []*PluginInfo, ctx *PluginContext) {
+	forwarded, remaining := ctx.forwarded, ctx.remaining
+	defer func() {
+		ctx.forwarded, ctx.remaining = forwarded, remaining
+	}()
+
+	ctx.forwarded = false
+
+	for i, info := range receivers {
+		// Messages are held back from plugins which have yet to start up.
+		if !n.awaitReady(info) {
+			return
+		}
+
+		if !info.Enabled() || !info.acceptsMessage(ctx) {
+			continue
+		}
+
+		ctx.remaining = receivers[i+1:]
+
+		if !n.receive(info, ctx) || ctx.forwarded {
+			return
+		}
+	}
+}
+
+// receive executes a plugins 'on receive message' callback, recovering from and
+// restarting the plugin upon panics as per its restart policy. Returns false if
+// the message should not be dispatched to any further plugins.
+func (n *Network) receive(info *PluginInfo, ctx *PluginContext) bool {
+	ctx.plugin = info.Plugin
+
+	endSpan := n.startReceiveSpan(info, ctx)
+
+	// Restarts of the plugin wait for the messages it is receiving to be handled.
+	info.receiveMutex.RLock()
+
+	var err error
+	if n.opts.pluginTimeout > 0 {
+		err = n.receiveWithTimeout(info, ctx)
+	} else {
+		err = n.invokeReceive(info, ctx)
+	}
+
+	info.receiveMutex.RUnlock()
+
+	endSpan(err)
+
+	switch err {
+	case nil, errPluginTimeout:
+		return true
+	case ErrStopDispatch:
+		return false
+	case errPluginPanicked:
+		n.restartPlugin(info, true)
+		return true
+	}
+
+	n.protocolLog.Error().Err(err).Msg("")
+
+	n.restartPlugin(info, false)
+
+	return true
+}
+
+// invokeReceive executes a plugins 'on receive message' callback, recovering from panics.
+func (n *Network) invokeReceive(info *PluginInfo, ctx *PluginContext) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			n.protocolLog.Error().
+				Str("plugin", reflect.TypeOf(info.Plugin).String()).
+				Interface("panic", r).
+				Str("stack", string(debug.Stack())).
+				Msg("network: plugin panicked while receiving message")
+
+			err = errPluginPanicked
+		}
+	}()
+
+	return info.Plugin.Receive(ctx)
+}
+
+// Outcomes of a plugin receiving a message within the plugin timeout. Plugins which
+// forward the message before timing out are waited on to return.
+const (
+	receiveReturned uint32 = iota + 1
+	receiveAbandoned
+	receiveForwarded
+)
+
+// receiveWithTimeout executes a plugins 'on receive message' callback within a goroutine,
+// abandoning it and canceling its context should it not return within the networks
+// plugin timeout. The plugin receives a copy of the plugin context, such that the message
+// may be dispatched onwards while an abandoned plugin still holds on to it. Abandoned
+// plugins which panic are restarted once they do, and no longer forward the message.
+func (n *Network) receiveWithTimeout(info *PluginInfo, ctx *PluginContext) error {
+	c, cancel := context.WithCancel(ctx.Context())
+	defer cancel()
+
+	// outcome is set to receiveReturned or receiveForwarded by the plugin, or
+	// receiveAbandoned once it times out, whichever happens first.
+	var outcome uint32
+
+	pctx := *ctx
+	pctx.ctx = c
+	pctx.outcome = &outcome
+
+	done := make(chan error, 1)
+
+	go func() {
+		err := n.invokeReceive(info, &pctx)
+
+		if atomic.CompareAndSwapUint32(&outcome, 0, receiveReturned) || atomic.CompareAndSwapUint32(&outcome, receiveForwarded, receiveReturned) {
+			done <- err
+			return
+		}
+
+		if err == errPluginPanicked {
+			n.restartPlugin(info, true)
+		}
+	}()
+
+	select {
+	case err := <-done:
+		ctx.forwarded = pctx.forwarded
+		return err
+	case <-n.opts.clock.After(n.opts.pluginTimeout):
+		if !atomic.CompareAndSwapUint32(&outcome, 0, receiveAbandoned) {
+			err := <-done
+			ctx.forwarded = pctx.forwarded
+			return err
+		}
+
+		event := n.protocolLog.Error().
+			Str("plugin", reflect.TypeOf(info.Plugin).String()).
+			Str("address", ctx.client.Address).
+			Dur("timeout", n.opts.pluginTimeout)
+		if id := ctx.client.PeerID(); id != nil {
+			event = event.Str("sender_id", id.PublicKeyHex())
+		}
+		event.Msg("network: plugin timed out while receiving message")
+
+		return errPluginTimeout
+	}
+}
+
+// restartPlugin invokes a plugins Cleanup and Startup callbacks should its restart policy
+// permit it. Messages are held back from the plugin while it restarts, and the plugin is
+// only restarted once the messages it is receiving have been handled. Failures of the
+// plugin while it is already restarting do not restart it again.
+func (n *Network) restartPlugin(info *PluginInfo, panicked bool) {
+	policy := n.opts.pluginRestartPolicies[reflect.TypeOf(info.Plugin)]
+
+	if policy.Mode == RestartNever || (policy.Mode == RestartOnPanic && !panicked) {
+		return
+	}
+
+	info.restartMutex.Lock()
+
+	if info.restarting {
+		info.restartMutex.Unlock()
+		return
+	}
+
+	if policy.MaxRestarts > 0 && info.restarts >= policy.MaxRestarts {
+		info.restartMutex.Unlock()
+
+		n.protocolLog.Warn().
+			Str("plugin", reflect.TypeOf(info.Plugin).String()).
+			Int("restarts", policy.MaxRestarts).
+			Msg("network: plugin exceeded max restarts")
+		return
+	}
+
+	wait := policy.RestartBackoff - n.opts.clock.Now().Sub(info.lastRestart)
+
+	info.restarting = true
+	info.restarts++
+	restarts := info.restarts
+
+	info.restartMutex.Unlock()
+
+	info.hold()
+
+	defer func() {
+		info.restartMutex.Lock()
+		info.restarting = false
+		info.lastRestart = n.opts.clock.Now()
+		info.restartMutex.Unlock()
+
+		info.release()
+	}()
+
+	if wait > 0 {
+		select {
+		case <-n.opts.clock.After(wait):
+		case <-n.kill:
+			return
+		}
+	}
+
+	info.receiveMutex.Lock()
+	defer info.receiveMutex.Unlock()
+
+	n.protocolLog.Info().
+		Str("plugin", reflect.TypeOf(info.Plugin).String()).
+		Int("restarts", restarts).
+		Msg("network: restarting plugin")
+
+	info.Plugin.Cleanup(n)
+
+	if async, ok := info.Plugin.(AsyncStartup); ok {
+		// Messages are to be held back until the plugin is ready, even should the plugin
+		// have been marked ready by its previous startup meanwhile.
+		info.hold()
+
+		if ready := async.StartupAsync(n); ready != nil {
+			select {
+			case err := <-ready:
+				if err != nil {
+					n.protocolLog.Error().Err(err).
+						Str("plugin", reflect.TypeOf(info.Plugin).String()).
+						Msg("network: plugin failed to restart")
+				}
+			case <-n.kill:
+			}
+		}
+		return
+	}
+
+	info.Plugin.Startup(n)
+}
+
+// startPlugin restores a plugins persisted state, and invokes its Startup callback, or
+// fires its StartupAsync callback should it implement AsyncStartup, marking it ready once
+// it signals so.
+func (n *Network) startPlugin(info *PluginInfo) {
+	if err := n.loadState(info.Plugin); err != nil {
+		n.protocolLog.Error().Err(err).Msg("")
+	}
+
+	async, ok := info.Plugin.(AsyncStartup)
+	if !ok {
+		info.Plugin.Startup(n)
+		return
+	}
+
+	ready := async.StartupAsync(n)
+
+	go func() {
+		var err error
+
+		if ready != nil {
+			select {
+			case err = <-ready:
+			case <-n.kill:
+				return
+			}
+		}
+
+		if err != nil {
+			n.protocolLog.Error().Err(err).
+				Str("plugin", reflect.TypeOf(info.Plugin).String()).
+				Msg("network: plugin failed to start up; disabling it")
+
+			info.SetEnabled(false)
+		}
+
+		info.release()
+	}()
+}
+
+// awaitReady blocks until a plugin is ready to receive messages, should it be starting
+// up or restarting. Returns false should the network be closed beforehand, or should
+// maxHeldMessages messages already be held back from the plugin, in which case the
+// message is dropped.
+func (n *Network) awaitReady(info *PluginInfo) bool {
+	ready := info.readySignal()
+	if ready == nil {
+		return true
+	}
+
+	if atomic.AddInt32(&info.held, 1) > maxHeldMessages {
+		atomic.AddInt32(&info.held, -1)
+
+		n.protocolLog.Warn().
+			Str("plugin", reflect.TypeOf(info.Plugin).String()).
+			Int("held", maxHeldMessages).
+			Msg("network: dropped message held back from plugin which is not ready")
+		return false
+	}
+	defer atomic.AddInt32(&info.held, -1)
+
+	select {
+	case <-ready:
+		return true
+	case <-n.kill:
+		return false
+	}
+}
+
 // Listen starts listening for peers on a port.
 func (n *Network) Listen() {
+	n.startMutex.Lock()
+	if n.startedAt.IsZero() {
+		n.startedAt = n.opts.clock.Now()
+	}
+	n.startMutex.Unlock()
+
 	// Handle 'network starts listening' callback for plugins.
-	n.plugins.Each(func(plugin PluginInterface) {
-		plugin.Startup(n)
-	})
+	for _, info := range n.plugins.values {
+		n.startPlugin(info)
+	}
 
 	// Handle 'network stops listening' callback for plugins.
 	defer func() {
@@ -212,27 +815,52 @@ func (n *Network) Listen() {
 		})
 	}()
 
-	addrInfo, err := ParseAddress(n.Address)
-	if err != nil {
-		log.Fatal().Err(err).Msg("")
+	self, multiaddr := n.SelfAddress(), n.SelfID().Multiaddr
+	addresses := []string{self}
+
+	if len(multiaddr) > 0 {
+		advertised, err := ParseMultiaddr(multiaddr)
+		if err != nil {
+			n.connLog.Fatal().Err(err).Msg("")
+		}
+
+		for _, address := range advertised {
+			if address, err := ToUnifiedAddress(address); err == nil && address != self {
+				addresses = append(addresses, address)
+			}
+		}
 	}
 
-	var listener net.Listener
+	listeners := make([]net.Listener, 0, len(addresses))
 
-	if t, exists := n.transports.Load(addrInfo.Protocol); exists {
-		listener, err = t.(transport.Layer).Listen(int(addrInfo.Port))
+	for _, address := range addresses {
+		addrInfo, err := ParseAddress(address)
 		if err != nil {
-			log.Fatal().Err(err).Msg("")
+			n.connLog.Fatal().Err(err).Msg("")
 		}
-	} else {
-		err := errors.New("network: invalid protocol " + addrInfo.Protocol)
-		log.Fatal().Err(err).Msg("")
+
+		t, exists := n.transports.Load(addrInfo.Protocol)
+		if !exists {
+			err := errors.New("network: invalid protocol " + addrInfo.Protocol)
+			n.connLog.Fatal().Err(err).Msg("")
+		}
+
+		listener, err := t.(transport.Layer).Listen(int(addrInfo.Port))
+		if err != nil {
+			n.connLog.Fatal().Err(err).Msg("")
+		}
+
+		listeners = append(listeners, listener)
 	}
 
 	n.startListening()
 
-	log.Info().
-		Str("address", n.Address).
+	if n.opts.addressDetector != nil && n.opts.addressDetectionInterval > 0 {
+		go n.detectAddressLoop()
+	}
+
+	n.connLog.Info().
+		Strs("addresses", addresses).
 		Msg("Listening for peers.")
 
 	// handle server shutdowns
@@ -240,22 +868,63 @@ func (n *Network) Listen() {
 		select {
 		case <-n.kill:
 			// cause listener.Accept() to stop blocking so it can continue the loop
-			listener.Close()
+			for _, listener := range listeners {
+				listener.Close()
+			}
 		}
 	}()
 
-	// Handle new clients.
+	var wg sync.WaitGroup
+	wg.Add(len(listeners))
+
+	for _, listener := range listeners {
+		go func(listener net.Listener) {
+			defer wg.Done()
+			n.acceptLoop(listener)
+		}(listener)
+	}
+
+	wg.Wait()
+
+	n.connLog.Info().Msgf("Shutting down server %s.", n.SelfAddress())
+}
+
+// acceptLoop handles new clients connecting through a listener until the network is killed.
+func (n *Network) acceptLoop(listener net.Listener) {
 	for {
 		if conn, err := listener.Accept(); err == nil {
-			go n.Accept(conn)
+			go func(conn net.Conn) {
+				if n.opts.proxyProtocol {
+					conn.SetReadDeadline(time.Now().Add(n.opts.connectionTimeout))
+
+					proxied, err := transport.ReadPROXYHeader(conn)
+					if err != nil {
+						n.recordConnectionEvent(EventAcceptFailed, nil, conn.RemoteAddr().String(), err)
+						n.connLog.Error().Err(err).Msg("")
+						conn.Close()
+						return
+					}
+
+					conn = proxied
+				}
+
+				upgraded, err := n.upgradeConn(conn, false)
+				if err != nil {
+					n.recordConnectionEvent(EventAcceptFailed, nil, conn.RemoteAddr().String(), err)
+					n.connLog.Error().Err(err).Msg("")
+					conn.Close()
+					return
+				}
+
+				n.Accept(upgraded)
+			}(conn)
 		} else {
 			// if the Shutdown flag is set, no need to continue with the for loop
 			select {
 			case <-n.kill:
-				log.Info().Msgf("Shutting down server %s.", n.Address)
 				return
 			default:
-				log.Error().Msgf("%v", err)
+				n.connLog.Error().Msgf("%v", err)
 			}
 		}
 	}
@@ -268,7 +937,7 @@ func (n *Network) Client(address string) (*PeerClient, error) {
 		return nil, err
 	}
 
-	if address == n.Address {
+	if address == n.SelfAddress() {
 		return nil, errors.New("network: peer should not dial itself")
 	}
 
@@ -299,6 +968,8 @@ func (n *Network) Client(address string) (*PeerClient, error) {
 		return nil, err
 	}
 
+	client.setFeatures(n.connFeatures(conn))
+
 	n.connections.Store(address, &ConnState{
 		conn:        conn,
 		writer:      bufio.NewWriterSize(conn, n.opts.writeBufferSize),
@@ -325,7 +996,59 @@ func (n *Network) ConnectionState(address string) (*ConnState, bool) {
 	return conn.(*ConnState), true
 }
 
-// startListening will start node for listening for new peers.
+// GetPeer returns the client of a connected peer by its public key hash. Peers are only
+// found once they have identified themselves by sending a message.
+func (n *Network) GetPeer(id []byte) (*PeerClient, bool) {
+	var found *PeerClient
+
+	n.eachPeer(func(client *PeerClient) bool {
+		if client.IsIncomingReady() && bytes.Equal(client.ID.Id, id) {
+			found = client
+			return false
+		}
+		return true
+	})
+
+	return found, found != nil
+}
+
+// GetPeerByAddress returns the client of a connected peer by the address it is listening on.
+func (n *Network) GetPeerByAddress(addr string) (*PeerClient, bool) {
+	var found *PeerClient
+
+	n.eachPeer(func(client *PeerClient) bool {
+		if client.IsIncomingReady() && client.ID.Address == addr {
+			found = client
+			return false
+		}
+		return true
+	})
+
+	return found, found != nil
+}
+
+// GetPeerByHost returns the client of the first connected peer found listening on a host,
+// regardless of the protocol and port it is listening on.
+func (n *Network) GetPeerByHost(host string) (*PeerClient, bool) {
+	var found *PeerClient
+
+	n.eachPeer(func(client *PeerClient) bool {
+		if !client.IsIncomingReady() {
+			return true
+		}
+
+		info, err := ParseAddress(client.ID.Address)
+		if err == nil && info.Host == host {
+			found = client
+			return false
+		}
+		return true
+	})
+
+	return found, found != nil
+}
+
+// startListening will start node for listening for new peers.
 func (n *Network) startListening() {
 	close(n.listeningCh)
 }
@@ -339,32 +1062,156 @@ func (n *Network) BlockUntilListening() {
 func (n *Network) Bootstrap(addresses ...string) {
 	n.BlockUntilListening()
 
-	addresses = FilterPeers(n.Address, addresses)
+	addresses = FilterPeers(n.SelfAddress(), addresses)
 
 	for _, address := range addresses {
 		client, err := n.Client(address)
 
 		if err != nil {
-			log.Error().Err(err).Msg("")
+			n.connLog.Error().Err(err).Msg("")
 			continue
 		}
 
-		err = client.Tell(context.Background(), &protobuf.Ping{})
+		err = client.Tell(context.Background(), &protobuf.Ping{Capabilities: uint64(n.Capabilities())})
 		if err != nil {
 			continue
 		}
 	}
 }
 
+// DialPeer explicitly connects to the peer at an address, regardless of the routing table,
+// and commences a handshake which has the peer reveal its ID. The peer is expected to
+// reply to pings, as peers running the discovery plugin do. Errors should the peer not
+// complete the handshake before ctx is done.
+func (n *Network) DialPeer(ctx context.Context, address string) (*PeerClient, error) {
+	client, err := n.Client(address)
+	if err != nil {
+		return nil, err
+	}
+
+	res, err := client.Request(ctx, &protobuf.Ping{Capabilities: uint64(n.Capabilities())})
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to handshake with peer at %s", address)
+	}
+
+	pong, ok := res.(*protobuf.Pong)
+	if !ok {
+		return nil, errors.Errorf("network: peer at %s replied to handshake with %T", address, res)
+	}
+	client.SetCapabilities(Capabilities(pong.Capabilities))
+
+	// The reply was received over the peers own connection, which identified the peer.
+	if !client.IsIncomingReady() {
+		return nil, errors.Errorf("network: peer at %s did not identify itself", address)
+	}
+
+	return client, nil
+}
+
+// ConnectAll connects to a list of peers, dialing up to concurrency peers at a time, and
+// waits for all attempts to complete. Peers are no longer dialed once ctx is done.
+// Peers are dialed at the address the networks Resolver resolves their ID to.
+// Returns an error for every peer which could not be connected to.
+func (n *Network) ConnectAll(ctx context.Context, peers []peer.ID, concurrency int) []ConnectError {
+	if concurrency <= 0 {
+		concurrency = len(peers)
+	}
+
+	var errs []ConnectError
+	var mutex sync.Mutex
+	var wg sync.WaitGroup
+
+	slots := make(chan struct{}, concurrency)
+
+	for _, id := range peers {
+		if id.Equals(n.SelfID()) {
+			continue
+		}
+
+		if ctx.Err() == nil {
+			select {
+			case <-ctx.Done():
+			case slots <- struct{}{}:
+				wg.Add(1)
+
+				go func(id peer.ID) {
+					defer func() {
+						<-slots
+						wg.Done()
+					}()
+
+					address, err := n.lookupAddressByID(id)
+					if err == nil {
+						_, err = n.Client(address)
+					}
+
+					if err != nil {
+						mutex.Lock()
+						errs = append(errs, ConnectError{Peer: id, Err: err})
+						mutex.Unlock()
+					}
+				}(id)
+
+				continue
+			}
+		}
+
+		mutex.Lock()
+		errs = append(errs, ConnectError{Peer: id, Err: ctx.Err()})
+		mutex.Unlock()
+	}
+
+	wg.Wait()
+
+	return errs
+}
+
 // Dial establishes a bidirectional connection to an address, and additionally handshakes with said address.
+// Should the peer at the address have advertised a multiaddr, each of its addresses are dialed in preference
+// order until one succeeds.
+//
+// Should a circuit breaker policy be set and the address have failed to be dialed too
+// many times in a row, ErrCircuitOpen is returned without dialing.
 func (n *Network) Dial(address string) (net.Conn, error) {
+	breaker := n.circuitBreaker(address, n.opts.clock.Now())
+	if breaker != nil {
+		if err := breaker.allow(n.opts.clock.Now(), n.opts.circuitBreaker); err != nil {
+			return nil, err
+		}
+	}
+
+	var (
+		conn net.Conn
+		err  error
+	)
+
+	for _, candidate := range n.dialAddresses(address) {
+		if conn, err = n.dialAddress(candidate); err == nil {
+			break
+		}
+	}
+
+	if breaker != nil {
+		breaker.record(n.opts.clock.Now(), n.opts.circuitBreaker, err)
+	}
+
+	if err != nil {
+		n.recordConnectionEvent(EventDialFailed, nil, address, err)
+		return nil, err
+	}
+
+	return conn, nil
+}
+
+// dialAddress establishes a bidirectional connection to a single address.
+func (n *Network) dialAddress(address string) (net.Conn, error) {
 	addrInfo, err := ParseAddress(address)
 	if err != nil {
 		return nil, err
 	}
 
 	if addrInfo.Host != "127.0.0.1" {
-		host, err := ParseAddress(n.Address)
+		host, err := ParseAddress(n.SelfAddress())
 		if err != nil {
 			return nil, err
 		}
@@ -378,7 +1225,7 @@ func (n *Network) Dial(address string) (net.Conn, error) {
 	t, exists := n.transports.Load(addrInfo.Protocol)
 	if !exists {
 		err := errors.New("network: invalid protocol " + addrInfo.Protocol)
-		log.Fatal().Err(err).Msg("")
+		n.connLog.Fatal().Err(err).Msg("")
 	}
 
 	var conn net.Conn
@@ -387,7 +1234,17 @@ func (n *Network) Dial(address string) (net.Conn, error) {
 		return nil, err
 	}
 
-	return conn, nil
+	upgraded, err := n.upgradeConn(conn, true)
+	if err != nil {
+		conn.Close()
+		return nil, err
+	}
+
+	n.connLog.Debug().
+		Str("address", address).
+		Msg("Dialed peer.")
+
+	return upgraded, nil
 }
 
 // Accept handles peer registration and processes incoming message streams.
@@ -395,12 +1252,14 @@ func (n *Network) Accept(incoming net.Conn) {
 	var client *PeerClient
 
 	recvWindow := NewRecvWindow(n.opts.recvWindowSize)
+	ordered := n.connFeatures(incoming).Has(FeatureOrderedDelivery)
+	multiaddrLearned := false
 
 	// Cleanup connections when we are done with them.
 	defer func() {
 		time.Sleep(1 * time.Second)
 
-		if client != nil {
+		if client != nil && !n.reconnect(client) {
 			client.Close()
 		}
 
@@ -409,26 +1268,78 @@ func (n *Network) Accept(incoming net.Conn) {
 		}
 	}()
 
+	// Disconnect peers which do not identify themselves by sending a message
+	// within the connection timeout.
+	incoming.SetReadDeadline(time.Now().Add(n.opts.connectionTimeout))
+
 	for {
 		msg, err := n.receiveMessage(incoming)
+		if err == errReplayedMsg || err == errProbeFrame || err == errChallengeFrame {
+			continue
+		}
+
 		if err != nil {
 			if err != errEmptyMsg {
-				log.Error().Msgf("%v", err)
+				n.connLog.Error().Msgf("%v", err)
 			}
 			break
 		}
 
+		// Stop reading from the peer until the inbound rate limit allows for the message.
+		n.shape(n.inboundLimiter, 1)
+
 		// Initialize client if not exists.
 		if client == nil {
+			// Peers must sign their ephemeral session key with the key they identify with.
+			if session, ok := connSession(incoming); ok && !bytes.Equal(session.RemotePublicKey(), msg.Sender.PublicKey) {
+				n.recordConnectionEvent(EventAcceptFailed, (*peer.ID)(msg.Sender), incoming.RemoteAddr().String(), errSessionKeyMismatch)
+				n.connLog.Error().Err(errSessionKeyMismatch).Msg("")
+				return
+			}
+
+			// Peers must identify with the key they performed their Noise handshake with.
+			if !matchesStaticKey(incoming, msg.Sender.PublicKey) {
+				n.recordConnectionEvent(EventAcceptFailed, (*peer.ID)(msg.Sender), incoming.RemoteAddr().String(), errStaticKeyMismatch)
+				n.connLog.Error().Err(errStaticKeyMismatch).Msg("")
+				return
+			}
+
+			if n.opts.verifyAddresses {
+				if err := n.verifyAddressOwnership(peer.ID(*msg.Sender), incoming.RemoteAddr()); err != nil {
+					n.recordConnectionEvent(EventAcceptFailed, (*peer.ID)(msg.Sender), incoming.RemoteAddr().String(), err)
+					n.connLog.Error().Err(err).Msg("")
+					return
+				}
+			}
+
 			client, err = n.Client(msg.Sender.Address)
 
 			if err != nil {
 				return
 			}
+
+			n.connLog.Debug().
+				Str("address", msg.Sender.Address).
+				Msg("Accepted connection from peer.")
+
+			incoming.SetReadDeadline(time.Time{})
+		}
+
+		// Peers which migrated to a new address keep sending over their existing connection.
+		if msg.Opcode == uint32(opcode.AddressMigrationCode) && msg.Sender.Address != client.Address {
+			migrated, err := n.acceptAddressMigration(client, msg)
+			if err != nil {
+				n.connLog.Error().Err(err).Str("address", client.Address).Msg("network: failed to migrate peer to a new address")
+				return
+			}
+			client = migrated
 		}
 
 		client.Do(func() {
-			client.ID = (*peer.ID)(msg.Sender)
+			client.setID((*peer.ID)(msg.Sender))
+			n.learnAddress(client.ID)
+			client.setPeerCertificate(incoming)
+			client.sourceAddr = incoming.RemoteAddr()
 
 			if !n.ConnectionStateExists(client.ID.Address) {
 				err = errors.New("network: failed to load session")
@@ -438,27 +1349,50 @@ func (n *Network) Accept(incoming net.Conn) {
 		})
 
 		if err != nil {
-			log.Error().Err(err).Msg("network: error initializing client")
+			n.connLog.Error().Err(err).Msg("network: error initializing client")
 			return
 		}
 
+		n.eachObserver(func(observer ObserverInterface) {
+			observer.MessageReceived(client.Address, frameSize(msg))
+		})
+
+		// Peer sent message with a completely different ID. Disconnect.
+		if !client.ID.Equals(peer.ID(*msg.Sender)) {
+			n.connLog.Error().
+				Str("peer_id", peer.ID(*msg.Sender).ShortString()).
+				Str("client_id", client.ID.ShortString()).
+				Msg("Message signed by peer does not match client ID.")
+			continue
+		}
+
+		// Multiaddrs are only learned from peers which proved the key or address they identify with.
+		if !multiaddrLearned && (n.opts.verifyAddresses || bindsIdentity(incoming)) {
+			n.learnMultiaddr((*peer.ID)(msg.Sender))
+			multiaddrLearned = true
+		}
+
+		pending := pendingMessage{msg: msg, receivedAt: n.opts.clock.Now()}
+
+		// Messages are pushed in the order they are read, as the receive window starts
+		// off from the nonce of the first message pushed.
+		if ordered {
+			recvWindow.Push(msg.MessageNonce, pending)
+		}
+
 		go func() {
-			// Peer sent message with a completely different ID. Disconnect.
-			if !client.ID.Equals(peer.ID(*msg.Sender)) {
-				log.Error().
-					Interface("peer_id", peer.ID(*msg.Sender)).
-					Interface("client_id", client.ID).
-					Msg("Message signed by peer does not match client ID.")
-				return
-			}
+			ready := []interface{}{pending}
 
-			recvWindow.Push(msg.MessageNonce, msg)
+			if ordered {
+				ready = recvWindow.Pop()
+			}
 
-			ready := recvWindow.Pop()
-			for _, msg := range ready {
-				msg := msg
+			for _, pending := range ready {
+				pending := pending.(pendingMessage)
+				client.beginWork()
 				client.Submit(func() {
-					n.dispatchMessage(client, msg.(*protobuf.Message))
+					defer client.endWork()
+					n.dispatchMessage(client, pending.msg, pending.receivedAt)
 				})
 			}
 		}()
@@ -473,6 +1407,96 @@ func (n *Network) Plugin(key interface{}) (PluginInterface, bool) {
 	return n.plugins.Get(key)
 }
 
+// SendToPlugin synchronously delivers an in-process message to a registered
+// plugin implementing InternalReceiver, returning the error the plugin returns.
+//
+// Example: network.SendToPlugin(discovery.PluginID, msg)
+func (n *Network) SendToPlugin(key interface{}, msg interface{}) error {
+	plugin, ok := n.plugins.Get(key)
+	if !ok {
+		return errors.Errorf("network: plugin %T is not registered", key)
+	}
+
+	receiver, ok := plugin.(InternalReceiver)
+	if !ok {
+		return errors.Errorf("network: plugin %T does not receive internal messages", key)
+	}
+
+	return receiver.ReceiveInternal(msg)
+}
+
+// PluginLogger returns the logger registered for a plugin through WithLogger, or
+// the component logger named after the plugins package otherwise (e.g. "discovery").
+func (n *Network) PluginLogger(key interface{}) zerolog.Logger {
+	if logger, ok := n.opts.pluginLoggers[reflect.TypeOf(key)]; ok {
+		return logger
+	}
+	return n.componentLogger(pluginConfigKey(key))
+}
+
+// componentLogger returns a logger for a component of the network whose logs carry a
+// `component` field. Logs go to the logger set through WithZerologLogger should there be
+// one, or to the global logger otherwise.
+func (n *Network) componentLogger(component string) zerolog.Logger {
+	if n.opts.logger != nil {
+		return n.opts.logger.With().Str("component", component).Logger()
+	}
+	return log.ComponentLogger(component)
+}
+
+// DisablePlugin stops incoming messages from being dispatched to a registered plugin.
+// The plugins Cleanup callback is not invoked.
+//
+// Example: network.DisablePlugin(discovery.PluginID)
+func (n *Network) DisablePlugin(key interface{}) error {
+	return n.setPluginEnabled(key, false)
+}
+
+// EnablePlugin resumes dispatching incoming messages to a previously disabled plugin.
+// The plugins Startup callback is not invoked.
+func (n *Network) EnablePlugin(key interface{}) error {
+	return n.setPluginEnabled(key, true)
+}
+
+// AllowSender allows messages from a sender to be dispatched to a registered plugin. Once
+// any sender is allowed, messages from all other senders are no longer dispatched to it,
+// e.g. such that an admin plugin only accepts messages from trusted peers.
+//
+// Example: network.AllowSender(admin.PluginID, trusted.PublicKey)
+func (n *Network) AllowSender(key interface{}, publicKey []byte) error {
+	info, ok := n.plugins.GetInfo(key)
+	if !ok {
+		return errors.Errorf("network: plugin %T is not registered", key)
+	}
+
+	info.AllowSender(publicKey)
+	return nil
+}
+
+// DenySender stops messages from a sender from being dispatched to a registered plugin.
+// Denying a sender takes precedence over allowing it.
+//
+// Example: network.DenySender(discovery.PluginID, peer.PublicKey)
+func (n *Network) DenySender(key interface{}, publicKey []byte) error {
+	info, ok := n.plugins.GetInfo(key)
+	if !ok {
+		return errors.Errorf("network: plugin %T is not registered", key)
+	}
+
+	info.DenySender(publicKey)
+	return nil
+}
+
+func (n *Network) setPluginEnabled(key interface{}, enabled bool) error {
+	info, ok := n.plugins.GetInfo(key)
+	if !ok {
+		return errors.Errorf("network: plugin %T is not registered", key)
+	}
+
+	info.SetEnabled(enabled)
+	return nil
+}
+
 // PrepareMessage marshals a message into a *protobuf.Message and signs it with this
 // nodes private key. Errors if the message is null.
 func (n *Network) PrepareMessage(ctx context.Context, message proto.Message) (*protobuf.Message, error) {
@@ -490,19 +1514,33 @@ func (n *Network) PrepareMessage(ctx context.Context, message proto.Message) (*p
 		return nil, err
 	}
 
-	id := protobuf.ID(n.ID)
+	id := protobuf.ID(n.SelfID())
 
 	msg := &protobuf.Message{
-		Message: raw,
-		Opcode:  uint32(opcode),
-		Sender:  &id,
+		Message:  raw,
+		Opcode:   uint32(opcode),
+		Sender:   &id,
+		Sequence: atomic.AddUint64(&n.sequence, 1),
+	}
+
+	n.injectTraceContext(ctx, msg)
+
+	if group := getGroupID(ctx); len(group) > 0 {
+		msg.GroupId = group
+	}
+
+	if deadline, ok := getDeadline(ctx); ok {
+		// A timeout of zero denotes no deadline, such that expired deadlines are rounded up.
+		if msg.Timeout = int64(time.Until(deadline)); msg.Timeout <= 0 {
+			msg.Timeout = 1
+		}
 	}
 
 	if GetSignMessage(ctx) {
 		signature, err := n.keys.Sign(
 			n.opts.signaturePolicy,
 			n.opts.hashPolicy,
-			SerializeMessage(&id, raw),
+			serializeSignedMessage(msg),
 		)
 		if err != nil {
 			return nil, err
@@ -513,21 +1551,106 @@ func (n *Network) PrepareMessage(ctx context.Context, message proto.Message) (*p
 	return msg, nil
 }
 
-// Write asynchronously sends a message to a denoted target address.
+// RequestReply sends a request to the peer at an address, and awaits its reply for at most
+// the request timeout. The deadline is carried by the request, such that the peer does
+// not reply once the deadline has passed. Errors with context.DeadlineExceeded should
+// the peer fail to reply in time.
+func (n *Network) RequestReply(ctx context.Context, address string, req proto.Message) (proto.Message, error) {
+	client, err := n.Client(address)
+	if err != nil {
+		return nil, err
+	}
+
+	ctx, cancel := WithTimeout(ctx, n.opts.requestTimeout)
+	defer cancel()
+
+	return client.Request(ctx, req)
+}
+
+// Write sends a message to a denoted target address, and waits for it to be written. The
+// message is written in turn with messages queued to the peer at the address.
 func (n *Network) Write(address string, message *protobuf.Message) error {
+	return n.enqueue(context.Background(), address, []*protobuf.Message{message}, true)
+}
+
+// WriteBatch sends a batch of messages to a denoted target address with a single write,
+// such that sending many messages does not incur a write per message, and waits for the
+// batch to be written. The batch takes up a single slot of the peers send queue.
+func (n *Network) WriteBatch(address string, messages []*protobuf.Message) error {
+	return n.enqueue(context.Background(), address, messages, true)
+}
+
+// enqueue queues a batch of messages into the send queue of the peer at an address, such
+// that messages are written to the peer in the order they were sent, optionally waiting
+// for the batch to be written. Messages are written directly should the peer not have a
+// client.
+func (n *Network) enqueue(ctx context.Context, address string, messages []*protobuf.Message, wait bool) error {
+	if _, ok := n.ConnectionState(address); !ok {
+		return errors.New("network: connection does not exist")
+	}
+
+	client, ok := n.peers.Load(address)
+	if !ok {
+		return n.write(address, messages)
+	}
+
+	req := &sendRequest{messages: messages}
+	if wait {
+		req.result = make(chan error, 1)
+	}
+
+	if err := client.(*PeerClient).sendQueue.push(ctx, req); err != nil || !wait {
+		return err
+	}
+
+	return <-req.result
+}
+
+// write encodes and writes a batch of messages to the connection of a denoted target address.
+func (n *Network) write(address string, messages []*protobuf.Message) error {
 	state, ok := n.ConnectionState(address)
 	if !ok {
 		return errors.New("network: connection does not exist")
 	}
 
-	message.MessageNonce = atomic.AddUint64(&state.messageNonce, 1)
+	if client, ok := n.peers.Load(address); ok {
+		client.(*PeerClient).beginWork()
+		defer client.(*PeerClient).endWork()
+	}
+
+	encoded := make([]*protobuf.Message, 0, len(messages))
+
+	for _, message := range messages {
+		message.MessageNonce = atomic.AddUint64(&state.messageNonce, 1)
+
+		message, err := n.encodeMessage(address, message)
+		if err != nil {
+			return err
+		}
+		encoded = append(encoded, message)
+	}
+	messages = encoded
+
+	n.shape(n.outboundLimiter, len(messages))
 
 	state.conn.SetWriteDeadline(time.Now().Add(n.opts.writeTimeout))
 
-	err := n.sendMessage(state.writer, message, state.writerMutex)
+	var err error
+	if len(messages) == 1 {
+		err = n.sendMessage(state.writer, messages[0], state.writerMutex)
+	} else {
+		err = n.sendMessages(state.writer, messages, state.writerMutex)
+	}
 	if err != nil {
 		return err
 	}
+
+	n.eachObserver(func(observer ObserverInterface) {
+		for _, message := range messages {
+			observer.MessageSent(address, frameSize(message))
+		}
+	})
+
 	return nil
 }
 
@@ -535,16 +1658,16 @@ func (n *Network) Write(address string, message *protobuf.Message) error {
 func (n *Network) Broadcast(ctx context.Context, message proto.Message) {
 	signed, err := n.PrepareMessage(ctx, message)
 	if err != nil {
-		log.Error().Err(err).Msg("network: failed to broadcast message")
+		n.protocolLog.Error().Err(err).Msg("network: failed to broadcast message")
 		return
 	}
 
 	n.eachPeer(func(client *PeerClient) bool {
 		err := n.Write(client.Address, signed)
 		if err != nil {
-			log.Warn().
+			n.protocolLog.Warn().
 				Err(err).
-				Interface("peer_id", client.ID).
+				Interface("peer_id", client.PeerID()).
 				Msg("failed to send message to peer")
 		}
 		return true
@@ -599,9 +1722,182 @@ func (n *Network) BroadcastRandomly(ctx context.Context, message proto.Message,
 	n.BroadcastByAddresses(ctx, message, addresses[:K]...)
 }
 
-// Close shuts down the entire network.
+// WeightedBroadcast asynchronously broadcasts a message to a subset of peers, sampled
+// without replacement with probability proportional to the weight weightFn assigns to
+// each peers public key. Peers with a weight of zero or less are never selected. The
+// number of peers sampled is set with WithBroadcastFanout (default: 3).
+//
+// Errors should no peer have a positive weight, or should writing to a sampled peer fail.
+func (n *Network) WeightedBroadcast(ctx context.Context, message proto.Message, weightFn func([]byte) float64) error {
+	type candidate struct {
+		address string
+		key     float64
+	}
+
+	var candidates []candidate
+
+	n.eachPeer(func(client *PeerClient) bool {
+		id := client.PeerID()
+		if id == nil {
+			return true
+		}
+
+		if weight := weightFn(id.PublicKey); weight > 0 {
+			// Efraimidis-Spirakis: the peers with the largest keys u^(1/w) form a
+			// weighted sample without replacement.
+			candidates = append(candidates, candidate{
+				address: client.Address,
+				key:     math.Pow(rand.Float64(), 1/weight),
+			})
+		}
+		return true
+	})
+
+	if len(candidates) == 0 {
+		return errors.New("network: no peers with a positive weight to broadcast to")
+	}
+
+	sort.Slice(candidates, func(i, j int) bool {
+		return candidates[i].key > candidates[j].key
+	})
+
+	if fanout := GetBroadcastFanout(ctx); len(candidates) > fanout {
+		candidates = candidates[:fanout]
+	}
+
+	signed, err := n.PrepareMessage(ctx, message)
+	if err != nil {
+		return err
+	}
+
+	for _, c := range candidates {
+		if writeErr := n.Write(c.address, signed); writeErr != nil && err == nil {
+			err = errors.Wrapf(writeErr, "failed to send message to peer %s", c.address)
+		}
+	}
+
+	return err
+}
+
+// SignRelay signs a message on behalf of this node for another peer to relay to peer to.
+// The signature is to be handed to the relaying peer alongside the message.
+func (n *Network) SignRelay(to peer.ID, message proto.Message) ([]byte, error) {
+	opcode, err := opcode.GetOpcode(message)
+	if err != nil {
+		return nil, err
+	}
+
+	raw, err := proto.Marshal(message)
+	if err != nil {
+		return nil, err
+	}
+
+	id := protobuf.ID(n.SelfID())
+
+	return n.keys.Sign(
+		n.opts.signaturePolicy,
+		n.opts.hashPolicy,
+		serializeRelayedMessage(&id, to.PublicKey, uint32(opcode), raw),
+	)
+}
+
+// verifyRelay verifies a relayed message was signed for this node by the peer it was
+// relayed from.
+func (n *Network) verifyRelay(msg *protobuf.Message) bool {
+	if msg.RelayedFrom.PublicKey == nil {
+		return false
+	}
+
+	return crypto.Verify(
+		n.opts.signaturePolicy,
+		n.opts.hashPolicy,
+		msg.RelayedFrom.PublicKey,
+		serializeRelayedMessage(msg.RelayedFrom, n.SelfID().PublicKey, msg.Opcode, msg.Message),
+		msg.RelaySignature,
+	)
+}
+
+// Relay asynchronously sends a message to peer to on behalf of peer from, preserving
+// from's ID in the messages relay header. The signature is that of peer from over the
+// message and its recipient, as produced by SignRelay, such that the recipient may
+// verify the message originated from peer from.
+func (n *Network) Relay(from, to peer.ID, message proto.Message, signature []byte) error {
+	signed, err := n.PrepareMessage(WithSignMessage(context.Background(), true), message)
+	if err != nil {
+		return err
+	}
+
+	relayedFrom := protobuf.ID(from)
+	signed.RelayedFrom = &relayedFrom
+	signed.RelaySignature = signature
+
+	address, err := n.lookupAddressByID(to)
+	if err != nil {
+		return err
+	}
+
+	client, err := n.Client(address)
+	if err != nil {
+		return errors.Wrapf(err, "failed to connect to %s", address)
+	}
+
+	return n.Write(client.Address, signed)
+}
+
+// SendToClosest looks up the n peers closest to a target ID throughout the network
+// and sends them a signed message. Requires a plugin implementing PeerLookup to be
+// registered. Returns a MultiError should sending to any of the peers fail.
+func (n *Network) SendToClosest(target peer.ID, count int, message proto.Message) error {
+	var lookup PeerLookup
+
+	n.plugins.Each(func(plugin PluginInterface) {
+		if l, ok := plugin.(PeerLookup); ok && lookup == nil {
+			lookup = l
+		}
+	})
+
+	if lookup == nil {
+		return errors.New("network: no plugin able to look up peers is registered")
+	}
+
+	ctx := WithSignMessage(context.Background(), true)
+
+	var errs MultiError
+
+	sent := 0
+	for _, id := range lookup.Lookup(n, target, count+1) {
+		if sent == count {
+			break
+		}
+
+		if id.Equals(n.SelfID()) {
+			continue
+		}
+
+		sent++
+
+		client, err := n.Client(id.Address)
+		if err != nil {
+			errs = append(errs, errors.Wrapf(err, "failed to connect to %s", id.Address))
+			continue
+		}
+
+		if err := client.Tell(ctx, message); err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	if len(errs) > 0 {
+		return errs
+	}
+
+	return nil
+}
+
+// Close shuts down the entire network. Closing an already closed network only closes
+// connections to peers.
 func (n *Network) Close() {
-	close(n.kill)
+	n.shutdown()
 
 	n.eachPeer(func(client *PeerClient) bool {
 		client.Close()
@@ -609,6 +1905,95 @@ func (n *Network) Close() {
 	})
 }
 
+// shutdown begins the server shutdown process should it not have begun already.
+func (n *Network) shutdown() {
+	n.killOnce.Do(func() {
+		close(n.kill)
+	})
+}
+
+// CloseWithDrain shuts down the entire network, waiting up to drainTimeout for messages
+// being sent to or handled from peers to complete before forcefully closing all
+// connections. Errors with the addresses of peers which did not drain in time. The state
+// of plugins implementing StatefulPlugin is persisted should a state directory be set.
+func (n *Network) CloseWithDrain(drainTimeout time.Duration) error {
+	// Stop accepting new connections.
+	n.shutdown()
+
+	deadline := time.Now().Add(drainTimeout)
+
+	var busy []string
+	for {
+		busy = busy[:0]
+
+		n.eachPeer(func(client *PeerClient) bool {
+			if !client.isIdle() {
+				busy = append(busy, client.Address)
+			}
+			return true
+		})
+
+		if len(busy) == 0 || !time.Now().Before(deadline) {
+			break
+		}
+
+		time.Sleep(drainPollInterval)
+	}
+
+	// Flush out messages which have yet to be written.
+	n.connections.Range(func(key, value interface{}) bool {
+		if state, ok := value.(*ConnState); ok {
+			state.writerMutex.Lock()
+			state.writer.Flush()
+			state.writerMutex.Unlock()
+		}
+		return true
+	})
+
+	// Persist the state of plugins before peers disconnecting alters it.
+	stateErr := n.saveState()
+	if stateErr != nil {
+		n.protocolLog.Error().Err(stateErr).Msg("")
+	}
+
+	n.eachPeer(func(client *PeerClient) bool {
+		client.Close()
+		return true
+	})
+
+	if len(busy) > 0 {
+		sort.Strings(busy)
+		return errors.Errorf("network: %d connection(s) did not drain within %s: %s", len(busy), drainTimeout, strings.Join(busy, ", "))
+	}
+
+	return stateErr
+}
+
+// Peers streams the clients of all peers the network is connected to, without copying
+// them all upfront. The channel is closed once every peer has been yielded, the context
+// is canceled or the network is closed. Callers which stop receiving before the channel
+// is closed must cancel the context to release it.
+func (n *Network) Peers(ctx context.Context) <-chan *PeerClient {
+	peers := make(chan *PeerClient)
+
+	go func() {
+		defer close(peers)
+
+		n.eachPeer(func(client *PeerClient) bool {
+			select {
+			case peers <- client:
+				return true
+			case <-ctx.Done():
+				return false
+			case <-n.kill:
+				return false
+			}
+		})
+	}()
+
+	return peers
+}
+
 func (n *Network) eachPeer(fn func(client *PeerClient) bool) {
 	n.peers.Range(func(_, value interface{}) bool {
 		client := value.(*PeerClient)
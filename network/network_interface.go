@@ -3,6 +3,7 @@ package network
 import (
 	"context"
 	"net"
+	"time"
 
 	"github.com/perlin-network/noise/crypto"
 	"github.com/perlin-network/noise/internal/protobuf"
@@ -20,6 +21,12 @@ type NetworkInterface interface {
 	// GetKeys() returns the keypair for this network
 	GetKeys() *crypto.KeyPair
 
+	// MaxMessageSizeBytes returns the largest size in bytes an incoming message may have.
+	MaxMessageSizeBytes() int
+
+	// SetMaxMessageSizeBytes sets the largest size in bytes an incoming message may have.
+	SetMaxMessageSizeBytes(size int)
+
 	// Listen starts listening for peers on a port.
 	Listen()
 
@@ -51,6 +58,9 @@ type NetworkInterface interface {
 	// Write asynchronously sends a message to a denoted target address.
 	Write(address string, message *protobuf.Message) error
 
+	// WriteBatch asynchronously sends a batch of messages to a denoted target address with a single write.
+	WriteBatch(address string, messages []*protobuf.Message) error
+
 	// Broadcast asynchronously broadcasts a message to all peer clients.
 	Broadcast(ctx context.Context, message proto.Message)
 
@@ -64,6 +74,29 @@ type NetworkInterface interface {
 	// Does not guarantee broadcasting to exactly K peers.
 	BroadcastRandomly(ctx context.Context, message proto.Message, K int)
 
+	// SendToClosest looks up the n peers closest to a target ID throughout the network
+	// and sends them a signed message. Returns a MultiError should sending to any of the peers fail.
+	SendToClosest(target peer.ID, count int, message proto.Message) error
+
+	// ConnectAll connects to a list of peers, dialing up to concurrency peers at a time, and
+	// returns an error for every peer which could not be connected to.
+	ConnectAll(ctx context.Context, peers []peer.ID, concurrency int) []ConnectError
+
+	// SignRelay signs a message on behalf of this node for another peer to relay to peer to.
+	SignRelay(to peer.ID, message proto.Message) ([]byte, error)
+
+	// Relay asynchronously sends a message to peer to on behalf of peer from, preserving
+	// from's ID and signature, as produced by SignRelay, in the messages relay header.
+	Relay(from, to peer.ID, message proto.Message, signature []byte) error
+
+	// EnableForwardSecrecy encrypts all subsequent connections with session keys derived
+	// from an ephemeral key exchange, and reconnects to all connected peers.
+	EnableForwardSecrecy(keys *crypto.KeyPair) error
+
 	// Close shuts down the entire network.
 	Close()
+
+	// CloseWithDrain shuts down the entire network, waiting up to drainTimeout for messages
+	// being sent to or handled from peers to complete before closing all connections.
+	CloseWithDrain(drainTimeout time.Duration) error
 }
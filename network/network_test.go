@@ -1,14 +1,35 @@
 package network_test
 
 import (
+	"bytes"
 	"context"
+	"crypto/rand"
+	"crypto/tls"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"net"
+	"os"
+	"os/exec"
+	"runtime"
+	"sync/atomic"
 	"testing"
 	"time"
 
+	"github.com/perlin-network/noise/clock"
+	"github.com/perlin-network/noise/crypto/ed25519"
+	"github.com/perlin-network/noise/dht"
+	pb "github.com/perlin-network/noise/internal/protobuf"
 	"github.com/perlin-network/noise/internal/test/protobuf"
 	"github.com/perlin-network/noise/network"
+	"github.com/perlin-network/noise/network/discovery"
+	"github.com/perlin-network/noise/network/transport"
+	"github.com/perlin-network/noise/peer"
+	"github.com/perlin-network/noise/testutil"
 	"github.com/perlin-network/noise/types/opcode"
 
+	"github.com/gogo/protobuf/proto"
+	"github.com/pkg/errors"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -184,3 +205,1313 @@ func testClientRequest(t *testing.T, e env, numNodes int) {
 	}(ctx)
 	cancel()
 }
+
+func TestSendToClosest(t *testing.T) {
+	if testing.Short() {
+		t.Skipf("skipping %s in short mode", t.Name())
+	}
+
+	numNodes, numClosest := 5, 2
+
+	te := newTest(t, tcpEnv, network.WriteTimeout(1*time.Second))
+	te.startBoostrap(numNodes)
+	defer te.tearDown()
+
+	expected := "test message"
+	target := te.nodes[0].ID
+
+	err := te.bootstrapNode.SendToClosest(target, numClosest, &protobuf.TestMessage{Message: expected})
+	assert.Nil(t, err)
+
+	time.Sleep(250 * time.Millisecond)
+
+	var received []string
+	for _, node := range te.nodes {
+		select {
+		case msg := <-te.getMailbox(node).RecvMailbox:
+			assert.Equal(t, expected, msg.Message)
+			received = append(received, node.Address)
+		default:
+		}
+	}
+
+	assert.Equal(t, numClosest, len(received))
+
+	// The target itself is the closest peer to its own ID.
+	assert.True(t, isInAddress(target.Address, received...), "expected target %s to receive message", target.Address)
+}
+
+// testMessageCounter counts the test messages a network receives.
+type testMessageCounter struct {
+	*network.Plugin
+
+	received int64
+}
+
+func (p *testMessageCounter) Receive(ctx *network.PluginContext) error {
+	if _, ok := ctx.Message().(*protobuf.TestMessage); ok {
+		atomic.AddInt64(&p.received, 1)
+	}
+	return nil
+}
+
+func TestWeightedBroadcast(t *testing.T) {
+	t.Parallel()
+
+	counters := make([]*testMessageCounter, 5)
+
+	cluster, err := testutil.NewCluster(len(counters), testutil.WithSetup(func(i int, builder *network.Builder) {
+		counters[i] = new(testMessageCounter)
+		builder.AddPlugin(counters[i])
+	}))
+	assert.Nil(t, err)
+	defer cluster.Stop()
+
+	broadcaster, heavy := cluster.Node(0), cluster.Node(1)
+
+	weight := func(publicKey []byte) float64 {
+		if bytes.Equal(publicKey, heavy.ID.PublicKey) {
+			return 10
+		}
+		return 1
+	}
+
+	ctx := network.WithBroadcastFanout(context.Background(), 1)
+
+	const broadcasts = 1000
+
+	for i := 0; i < broadcasts; i++ {
+		assert.Nil(t, broadcaster.WeightedBroadcast(ctx, &protobuf.TestMessage{Message: "weighted"}, weight))
+	}
+
+	total := func() int64 {
+		sum := int64(0)
+		for _, counter := range counters[1:] {
+			sum += atomic.LoadInt64(&counter.received)
+		}
+		return sum
+	}
+
+	deadline := time.Now().Add(5 * time.Second)
+	for total() < broadcasts {
+		if time.Now().After(deadline) {
+			t.Fatalf("expected %d messages to be received, got %d", broadcasts, total())
+		}
+		time.Sleep(50 * time.Millisecond)
+	}
+
+	light := float64(total()-atomic.LoadInt64(&counters[1].received)) / 3
+	ratio := float64(atomic.LoadInt64(&counters[1].received)) / light
+
+	assert.True(t, ratio > 7 && ratio < 14, "expected heavy peer to receive about 10x more messages, got %.2fx", ratio)
+}
+
+func TestWeightedBroadcastNoPeers(t *testing.T) {
+	t.Parallel()
+
+	cluster, err := testutil.NewCluster(2)
+	assert.Nil(t, err)
+	defer cluster.Stop()
+
+	err = cluster.Node(0).WeightedBroadcast(context.Background(), &protobuf.TestMessage{}, func([]byte) float64 { return 0 })
+	assert.NotNil(t, err, "expected broadcast to fail without any peer having a positive weight")
+}
+
+func TestRelay(t *testing.T) {
+	t.Parallel()
+
+	te := newTest(t, tcpEnv, network.WriteTimeout(1*time.Second))
+	plugin := &relayTestPlugin{received: make(chan relayedMessage, 16)}
+	te.startBoostrap(3, plugin)
+	defer te.tearDown()
+
+	relayer, target, origin := te.bootstrapNode, te.nodes[0], te.nodes[1].ID
+
+	message := &protobuf.TestMessage{Message: "relayed"}
+
+	signature, err := te.nodes[1].SignRelay(target.ID, message)
+	assert.Nil(t, err)
+
+	err = relayer.Relay(origin, target.ID, message, signature)
+	assert.Nil(t, err)
+
+	select {
+	case ctx := <-plugin.received:
+		assert.True(t, ctx.relayed, "expected message to be relayed")
+		assert.True(t, ctx.from.Equals(origin), "expected relayed from to be %v, got %v", origin, ctx.from)
+		assert.True(t, ctx.sender.Equals(relayer.ID), "expected sender to be %v, got %v", relayer.ID, ctx.sender)
+	case <-time.After(1 * time.Second):
+		t.Fatal("timed out waiting for relayed message")
+	}
+
+	// Messages the relayer claims to relay on behalf of peers which did not sign them are dropped.
+	err = relayer.Relay(origin, target.ID, &protobuf.TestMessage{Message: "forged"}, signature)
+	assert.Nil(t, err)
+
+	client, err := relayer.Client(target.Address)
+	assert.Nil(t, err)
+	assert.Nil(t, client.Tell(context.Background(), &protobuf.TestMessage{Message: "direct"}))
+
+	select {
+	case ctx := <-plugin.received:
+		assert.False(t, ctx.relayed, "expected message to not be relayed")
+		assert.True(t, ctx.from.Equals(relayer.ID), "expected relayed from to be %v, got %v", relayer.ID, ctx.from)
+	case <-time.After(1 * time.Second):
+		t.Fatal("timed out waiting for direct message")
+	}
+}
+
+func TestTLS(t *testing.T) {
+	t.Parallel()
+
+	var nodes []*network.Network
+	var certs []tls.Certificate
+
+	for i := 0; i < 2; i++ {
+		cert := generateCertificate(t)
+
+		serverConfig := &tls.Config{Certificates: []tls.Certificate{cert}, ClientAuth: tls.RequireAnyClientCert}
+		clientConfig := &tls.Config{Certificates: []tls.Certificate{cert}, InsecureSkipVerify: true}
+
+		builder := network.NewBuilder()
+		builder.RegisterTransportLayer("tls", transport.NewTLS(transport.NewTCP(), serverConfig, clientConfig))
+		builder.SetAddress(network.FormatAddress("tls", "localhost", uint16(network.GetRandomUnusedPort())))
+		builder.AddPlugin(new(discovery.Plugin))
+
+		node, err := builder.Build()
+		assert.Nil(t, err)
+
+		go node.Listen()
+		defer node.Close()
+
+		node.BlockUntilListening()
+
+		nodes = append(nodes, node)
+		certs = append(certs, cert)
+	}
+
+	nodes[1].Bootstrap(nodes[0].Address)
+
+	// Wait for both nodes to ping/pong one another.
+	deadline := time.Now().Add(3 * time.Second)
+	for i, node := range nodes {
+		plugin, _ := node.Plugin(discovery.PluginID)
+		routes := plugin.(*discovery.Plugin).Routes
+
+		for !routes.PeerExists(nodes[1-i].ID) {
+			if time.Now().After(deadline) {
+				t.Fatalf("node %d did not discover its peer over TLS", i)
+			}
+			time.Sleep(50 * time.Millisecond)
+		}
+	}
+
+	for i, node := range nodes {
+		client, err := node.Client(nodes[1-i].Address)
+		assert.Nil(t, err)
+
+		cert := client.PeerCertificate()
+		if assert.NotNil(t, cert, "expected node %d to have stored its peer's certificate", i) {
+			assert.Equal(t, certs[1-i].Certificate[0], cert.Raw)
+		}
+	}
+}
+
+func TestQUIC(t *testing.T) {
+	t.Parallel()
+
+	var nodes []*network.Network
+
+	for i := 0; i < 2; i++ {
+		cert := generateCertificate(t)
+
+		serverConfig := &tls.Config{Certificates: []tls.Certificate{cert}}
+		clientConfig := &tls.Config{InsecureSkipVerify: true}
+
+		builder := network.NewBuilder()
+		builder.RegisterTransportLayer("quic", transport.NewQUIC(serverConfig, clientConfig))
+		builder.SetAddress(network.FormatAddress("quic", "localhost", uint16(network.GetRandomUnusedPort())))
+		builder.AddPlugin(new(discovery.Plugin))
+		builder.AddPlugin(new(MailBoxPlugin))
+
+		node, err := builder.Build()
+		assert.Nil(t, err)
+
+		go node.Listen()
+		defer node.Close()
+
+		node.BlockUntilListening()
+
+		nodes = append(nodes, node)
+	}
+
+	nodes[1].Bootstrap(nodes[0].Address)
+
+	// Wait for both nodes to ping/pong one another.
+	deadline := time.Now().Add(3 * time.Second)
+	for i, node := range nodes {
+		plugin, _ := node.Plugin(discovery.PluginID)
+		routes := plugin.(*discovery.Plugin).Routes
+
+		for !routes.PeerExists(nodes[1-i].ID) {
+			if time.Now().After(deadline) {
+				t.Fatalf("node %d did not discover its peer over QUIC", i)
+			}
+			time.Sleep(50 * time.Millisecond)
+		}
+	}
+
+	nodes[0].Broadcast(context.Background(), &protobuf.TestMessage{Message: "quic"})
+
+	plugin, _ := nodes[1].Plugin(mailboxPluginID)
+	select {
+	case received := <-plugin.(*MailBoxPlugin).RecvMailbox:
+		assert.Equal(t, "quic", received.Message)
+	case <-time.After(3 * time.Second):
+		t.Fatal("expected message to be delivered over QUIC")
+	}
+}
+
+func TestMultiaddrFallback(t *testing.T) {
+	t.Parallel()
+
+	var nodes []*network.Network
+	var layers []*recordingLayer
+
+	for i := 0; i < 2; i++ {
+		cert := generateCertificate(t)
+
+		serverConfig := &tls.Config{Certificates: []tls.Certificate{cert}}
+		clientConfig := &tls.Config{InsecureSkipVerify: true}
+
+		tcp := &recordingLayer{Layer: transport.NewTCP()}
+
+		// Multiaddrs are only learned from peers which proved the address they claim.
+		builder := network.NewBuilderWithOptions(network.WithAddressVerification(true))
+		builder.RegisterTransportLayer("tcp", tcp)
+		builder.RegisterTransportLayer("quic", transport.NewQUIC(serverConfig, clientConfig))
+
+		port := uint16(network.GetRandomUnusedPort())
+		builder.SetAddress(network.FormatAddress("tcp", "127.0.0.1", port))
+
+		if i == 1 {
+			multiaddr, err := network.FormatMultiaddr(
+				network.FormatAddress("tcp", "127.0.0.1", port),
+				network.FormatAddress("quic", "127.0.0.1", uint16(network.GetRandomUnusedPort())),
+			)
+			assert.Nil(t, err)
+			builder.SetMultiaddr(multiaddr)
+		}
+
+		builder.AddPlugin(new(discovery.Plugin))
+		builder.AddPlugin(new(MailBoxPlugin))
+
+		node, err := builder.Build()
+		assert.Nil(t, err)
+
+		go node.Listen()
+		defer node.Close()
+
+		node.BlockUntilListening()
+
+		nodes = append(nodes, node)
+		layers = append(layers, tcp)
+	}
+
+	nodes[0].Bootstrap(nodes[1].Address)
+
+	// Wait for both nodes to ping/pong one another, such that nodes[0] learns of the multiaddr of nodes[1].
+	deadline := time.Now().Add(3 * time.Second)
+	for i, node := range nodes {
+		plugin, _ := node.Plugin(discovery.PluginID)
+		routes := plugin.(*discovery.Plugin).Routes
+
+		for !routes.PeerExists(nodes[1-i].ID) {
+			if time.Now().After(deadline) {
+				t.Fatalf("node %d did not discover its peer", i)
+			}
+			time.Sleep(50 * time.Millisecond)
+		}
+	}
+
+	// Take down TCP for nodes[1], and drop the existing connection to it.
+	layers[1].CloseListeners()
+
+	client, err := nodes[0].Client(nodes[1].Address)
+	assert.Nil(t, err)
+	assert.Nil(t, client.Close())
+
+	plugin, _ := nodes[1].Plugin(mailboxPluginID)
+	mailbox := plugin.(*MailBoxPlugin).RecvMailbox
+
+	deadline = time.Now().Add(5 * time.Second)
+	for {
+		client, err := nodes[0].Client(nodes[1].Address)
+		if err == nil {
+			client.Tell(context.Background(), &protobuf.TestMessage{Message: "fallback"})
+		}
+
+		select {
+		case received := <-mailbox:
+			assert.Equal(t, "fallback", received.Message)
+			return
+		case <-time.After(100 * time.Millisecond):
+		}
+
+		if time.Now().After(deadline) {
+			t.Fatal("expected message to be delivered over QUIC once TCP was unavailable")
+		}
+	}
+}
+
+func TestPROXYProtocol(t *testing.T) {
+	t.Parallel()
+
+	source := &net.TCPAddr{IP: net.ParseIP("203.0.113.7"), Port: 8080}
+
+	builder := network.NewBuilderWithOptions(network.WithPROXYProtocol(true))
+	builder.SetAddress(network.FormatAddress("tcp", "127.0.0.1", uint16(network.GetRandomUnusedPort())))
+	builder.AddPlugin(new(MailBoxPlugin))
+
+	server, err := builder.Build()
+	assert.Nil(t, err)
+
+	go server.Listen()
+	defer server.Close()
+
+	server.BlockUntilListening()
+
+	// Connections from the server back to the client are not proxied.
+	builder = network.NewBuilder()
+	builder.RegisterTransportLayer("tcp", &proxyLayer{Layer: transport.NewTCP(), source: source})
+	builder.SetAddress(network.FormatAddress("tcp", "127.0.0.1", uint16(network.GetRandomUnusedPort())))
+
+	client, err := builder.Build()
+	assert.Nil(t, err)
+
+	go client.Listen()
+	defer client.Close()
+
+	client.BlockUntilListening()
+
+	plugin, _ := server.Plugin(mailboxPluginID)
+	mailbox := plugin.(*MailBoxPlugin).RecvMailbox
+
+	outgoing, err := client.Client(server.Address)
+	assert.Nil(t, err)
+
+	deadline := time.Now().Add(3 * time.Second)
+	for received := false; !received; {
+		outgoing.Tell(context.Background(), &protobuf.TestMessage{Message: "proxied"})
+
+		select {
+		case <-mailbox:
+			received = true
+		case <-time.After(100 * time.Millisecond):
+			if time.Now().After(deadline) {
+				t.Fatal("expected message to be delivered over a proxied connection")
+			}
+		}
+	}
+
+	incoming, err := server.Client(client.Address)
+	assert.Nil(t, err)
+	assert.Equal(t, source.String(), incoming.SourceAddr().String())
+}
+
+func TestWebSocket(t *testing.T) {
+	t.Parallel()
+
+	var nodes []*network.Network
+
+	for i := 0; i < 2; i++ {
+		builder := network.NewBuilder()
+		builder.RegisterTransportLayer("ws", &transport.WebSocket{Path: "/noise"})
+		builder.SetAddress(network.FormatAddress("ws", "localhost", uint16(network.GetRandomUnusedPort())))
+		builder.AddPlugin(new(discovery.Plugin))
+
+		node, err := builder.Build()
+		assert.Nil(t, err)
+
+		go node.Listen()
+		defer node.Close()
+
+		node.BlockUntilListening()
+
+		nodes = append(nodes, node)
+	}
+
+	// Bootstrapping sends a ping, which is replied to with a pong.
+	nodes[1].Bootstrap(nodes[0].Address)
+
+	deadline := time.Now().Add(3 * time.Second)
+	for i, node := range nodes {
+		plugin, _ := node.Plugin(discovery.PluginID)
+		routes := plugin.(*discovery.Plugin).Routes
+
+		for !routes.PeerExists(nodes[1-i].ID) {
+			if time.Now().After(deadline) {
+				t.Fatalf("node %d did not discover its peer over WebSocket", i)
+			}
+			time.Sleep(50 * time.Millisecond)
+		}
+	}
+
+	// Pongs are replies, and may be requested for directly.
+	client, err := nodes[1].Client(nodes[0].Address)
+	assert.Nil(t, err)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+
+	response, err := client.Request(ctx, &pb.Ping{})
+	assert.Nil(t, err)
+	assert.IsType(t, &pb.Pong{}, response)
+}
+
+func TestUnixTransport(t *testing.T) {
+	t.Parallel()
+
+	layer := &transport.Unix{Dir: t.TempDir()}
+
+	var nodes []*network.Network
+
+	for i := 0; i < 2; i++ {
+		builder := network.NewBuilder()
+		builder.RegisterTransportLayer("unix", layer)
+		builder.SetAddress(network.FormatAddress("unix", "localhost", uint16(3000+i)))
+		builder.AddPlugin(new(MailBoxPlugin))
+
+		node, err := builder.Build()
+		assert.Nil(t, err)
+
+		go node.Listen()
+		defer node.Close()
+
+		node.BlockUntilListening()
+
+		nodes = append(nodes, node)
+	}
+
+	plugin, _ := nodes[1].Plugin(mailboxPluginID)
+	mailbox := plugin.(*MailBoxPlugin)
+
+	client, err := nodes[0].Client(nodes[1].Address)
+	assert.Nil(t, err)
+
+	assert.Nil(t, client.Tell(context.Background(), &protobuf.TestMessage{Message: "unix"}))
+
+	select {
+	case received := <-mailbox.RecvMailbox:
+		assert.Equal(t, "unix", received.Message)
+	case <-time.After(3 * time.Second):
+		t.Fatal("expected message to be delivered over a Unix domain socket")
+	}
+}
+
+func TestGRPC(t *testing.T) {
+	t.Parallel()
+
+	var nodes []*network.Network
+	var routes []*dht.RoutingTable
+
+	for i := 0; i < 3; i++ {
+		plugin := new(discovery.Plugin)
+
+		builder := network.NewBuilder()
+		builder.RegisterTransportLayer("grpc", transport.NewGRPC())
+		builder.SetAddress(network.FormatAddress("grpc", "localhost", uint16(network.GetRandomUnusedPort())))
+		builder.AddPlugin(plugin)
+
+		node, err := builder.Build()
+		assert.Nil(t, err)
+
+		go node.Listen()
+		defer node.Close()
+
+		node.BlockUntilListening()
+
+		nodes = append(nodes, node)
+		routes = append(routes, plugin.Routes)
+	}
+
+	// Pings are replied to with pongs, upon which peers are looked up.
+	nodes[1].Bootstrap(nodes[0].Address)
+	nodes[2].Bootstrap(nodes[0].Address)
+
+	deadline := time.Now().Add(5 * time.Second)
+	for i := range nodes {
+		for j := range nodes {
+			for i != j && !routes[i].PeerExists(nodes[j].ID) {
+				if time.Now().After(deadline) {
+					t.Fatalf("node %d did not discover node %d over gRPC", i, j)
+				}
+				time.Sleep(50 * time.Millisecond)
+			}
+		}
+	}
+}
+
+// Environment variables which have the test binary act as the subprocess of
+// TestUnixTransportSubprocess, dialing the node listening at an address.
+const (
+	unixSubprocessAddressEnv = "NOISE_TEST_UNIX_ADDRESS"
+	unixSubprocessDirEnv     = "NOISE_TEST_UNIX_DIR"
+)
+
+// startUnixNode starts a node listening on a Unix domain socket within a directory.
+func startUnixNode(t *testing.T, dir string, port uint16) (*network.Network, *MailBoxPlugin) {
+	builder := network.NewBuilder()
+	builder.RegisterTransportLayer("unix", &transport.Unix{Dir: dir})
+	builder.SetAddress(network.FormatAddress("unix", "localhost", port))
+	builder.AddPlugin(new(MailBoxPlugin))
+
+	node, err := builder.Build()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	go node.Listen()
+	node.BlockUntilListening()
+
+	plugin, _ := node.Plugin(mailboxPluginID)
+
+	return node, plugin.(*MailBoxPlugin)
+}
+
+// tellAndReceive sends a message to the peer at an address, and waits for a mailbox to
+// receive a message in return.
+func tellAndReceive(node *network.Network, address string, message string, mailbox *MailBoxPlugin, timeout time.Duration) (*protobuf.TestMessage, error) {
+	client, err := node.Client(address)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := client.Tell(context.Background(), &protobuf.TestMessage{Message: message}); err != nil {
+		return nil, err
+	}
+
+	select {
+	case received := <-mailbox.RecvMailbox:
+		return received, nil
+	case <-time.After(timeout):
+		return nil, errors.Errorf("no message was received within %s", timeout)
+	}
+}
+
+func TestUnixTransportSubprocess(t *testing.T) {
+	// Within the subprocess, exchange messages with the node of the parent process.
+	if address := os.Getenv(unixSubprocessAddressEnv); len(address) > 0 {
+		node, mailbox := startUnixNode(t, os.Getenv(unixSubprocessDirEnv), 3101)
+		defer node.Close()
+
+		received, err := tellAndReceive(node, address, "from subprocess", mailbox, 5*time.Second)
+		if err != nil {
+			t.Fatal(err)
+		}
+		assert.Equal(t, "from parent", received.Message)
+		return
+	}
+
+	t.Parallel()
+
+	dir := t.TempDir()
+
+	node, mailbox := startUnixNode(t, dir, 3100)
+	defer node.Close()
+
+	var output bytes.Buffer
+
+	cmd := exec.Command(os.Args[0], "-test.run=^TestUnixTransportSubprocess$")
+	cmd.Env = append(os.Environ(), unixSubprocessAddressEnv+"="+node.Address, unixSubprocessDirEnv+"="+dir)
+	cmd.Stdout = &output
+	cmd.Stderr = &output
+
+	assert.Nil(t, cmd.Start())
+
+	exited := make(chan error, 1)
+	go func() {
+		exited <- cmd.Wait()
+	}()
+
+	timeout := time.After(10 * time.Second)
+	received := false
+
+	// Reply to the message of the subprocess, and wait for it to exit.
+	for {
+		select {
+		case msg := <-mailbox.RecvMailbox:
+			assert.Equal(t, "from subprocess", msg.Message)
+			assert.False(t, received, "expected a single message from the subprocess")
+			received = true
+
+			// The subprocess listens before sending messages.
+			client, err := node.Client(network.FormatAddress("unix", "localhost", 3101))
+			if assert.Nil(t, err) {
+				assert.Nil(t, client.Tell(context.Background(), &protobuf.TestMessage{Message: "from parent"}))
+			}
+		case err := <-exited:
+			if err != nil {
+				t.Fatalf("subprocess failed: %v, output:\n%s", err, output.String())
+			}
+			assert.True(t, received, "expected message from subprocess over a Unix domain socket")
+			return
+		case <-timeout:
+			cmd.Process.Kill()
+			<-exited
+			t.Fatalf("timed out exchanging messages with subprocess, output:\n%s", output.String())
+		}
+	}
+}
+
+func TestNoiseTransport(t *testing.T) {
+	t.Parallel()
+
+	var nodes []*network.Network
+
+	for i := 0; i < 2; i++ {
+		keys := ed25519.RandomKeyPair()
+
+		builder := network.NewBuilder()
+		builder.SetKeys(keys)
+		builder.RegisterTransportLayer("noise", transport.NewNoise(transport.NewTCP(), keys))
+		builder.SetAddress(network.FormatAddress("noise", "localhost", uint16(network.GetRandomUnusedPort())))
+		builder.AddPlugin(new(discovery.Plugin))
+
+		node, err := builder.Build()
+		assert.Nil(t, err)
+
+		go node.Listen()
+		defer node.Close()
+
+		node.BlockUntilListening()
+
+		nodes = append(nodes, node)
+	}
+
+	nodes[1].Bootstrap(nodes[0].Address)
+
+	// Wait for both nodes to ping/pong one another.
+	deadline := time.Now().Add(3 * time.Second)
+	for i, node := range nodes {
+		plugin, _ := node.Plugin(discovery.PluginID)
+		routes := plugin.(*discovery.Plugin).Routes
+
+		for !routes.PeerExists(nodes[1-i].ID) {
+			if time.Now().After(deadline) {
+				t.Fatalf("node %d did not discover its peer over a Noise handshake", i)
+			}
+			time.Sleep(50 * time.Millisecond)
+		}
+	}
+}
+
+func TestForwardSecrecy(t *testing.T) {
+	t.Parallel()
+
+	var nodes []*network.Network
+
+	for i := 0; i < 2; i++ {
+		keys := ed25519.RandomKeyPair()
+
+		builder := network.NewBuilderWithOptions(network.WithForwardSecrecy(keys))
+		builder.SetKeys(keys)
+		builder.SetAddress(network.FormatAddress("tcp", "localhost", uint16(network.GetRandomUnusedPort())))
+		builder.AddPlugin(new(discovery.Plugin))
+
+		node, err := builder.Build()
+		assert.Nil(t, err)
+
+		go node.Listen()
+		defer node.Close()
+
+		node.BlockUntilListening()
+
+		nodes = append(nodes, node)
+	}
+
+	nodes[1].Bootstrap(nodes[0].Address)
+
+	// Wait for both nodes to ping/pong one another.
+	deadline := time.Now().Add(3 * time.Second)
+	for i, node := range nodes {
+		plugin, _ := node.Plugin(discovery.PluginID)
+		routes := plugin.(*discovery.Plugin).Routes
+
+		for !routes.PeerExists(nodes[1-i].ID) {
+			if time.Now().After(deadline) {
+				t.Fatalf("node %d did not discover its peer over an encrypted session", i)
+			}
+			time.Sleep(50 * time.Millisecond)
+		}
+	}
+}
+
+func TestConnectAll(t *testing.T) {
+	t.Parallel()
+
+	var nodes []*network.Network
+
+	for i := 0; i < 3; i++ {
+		builder := network.NewBuilder()
+		builder.SetAddress(network.FormatAddress("tcp", "localhost", uint16(network.GetRandomUnusedPort())))
+
+		node, err := builder.Build()
+		assert.Nil(t, err)
+
+		go node.Listen()
+		defer node.Close()
+
+		node.BlockUntilListening()
+		nodes = append(nodes, node)
+	}
+
+	unreachable := peer.CreateID(network.FormatAddress("tcp", "localhost", uint16(network.GetRandomUnusedPort())), []byte("unreachable"))
+	peers := []peer.ID{nodes[0].ID, nodes[1].ID, unreachable, nodes[2].ID}
+
+	errs := nodes[0].ConnectAll(context.Background(), peers, 2)
+	if assert.Equal(t, 1, len(errs)) {
+		assert.True(t, errs[0].Peer.Equals(unreachable))
+		assert.NotNil(t, errs[0].Err)
+	}
+
+	for _, node := range nodes[1:] {
+		assert.True(t, nodes[0].ConnectionStateExists(node.Address), "expected %s to be connected to", node.Address)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	errs = nodes[1].ConnectAll(ctx, []peer.ID{nodes[2].ID}, 1)
+	if assert.Equal(t, 1, len(errs)) {
+		assert.Equal(t, context.Canceled, errs[0].Err)
+	}
+	assert.False(t, nodes[1].ConnectionStateExists(nodes[2].Address))
+}
+
+func TestCloseWithDrain(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		name         string
+		delay        time.Duration
+		drainTimeout time.Duration
+		drained      bool
+	}{
+		{"drained", 250 * time.Millisecond, 3 * time.Second, true},
+		{"timed out", 3 * time.Second, 50 * time.Millisecond, false},
+	}
+
+	for _, tt := range testCases {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			plugin := &slowTestPlugin{delay: tt.delay}
+
+			var nodes []*network.Network
+
+			for i := 0; i < 2; i++ {
+				builder := network.NewBuilder()
+				builder.SetAddress(network.FormatAddress("tcp", "localhost", uint16(network.GetRandomUnusedPort())))
+				if i == 0 {
+					builder.AddPlugin(plugin)
+				}
+
+				node, err := builder.Build()
+				assert.Nil(t, err)
+
+				go node.Listen()
+
+				node.BlockUntilListening()
+				nodes = append(nodes, node)
+			}
+
+			receiver, sender := nodes[0], nodes[1]
+			defer sender.Close()
+
+			sender.Bootstrap(receiver.Address)
+			sender.Broadcast(context.Background(), &protobuf.TestMessage{Message: "drain"})
+
+			select {
+			case <-plugin.started:
+			case <-time.After(3 * time.Second):
+				t.Fatal("expected message to be received")
+			}
+
+			err := receiver.CloseWithDrain(tt.drainTimeout)
+
+			if tt.drained {
+				assert.Nil(t, err)
+				assert.Equal(t, 1, len(plugin.finished), "expected message to be handled before closing")
+			} else if assert.NotNil(t, err) {
+				assert.Contains(t, err.Error(), sender.Address)
+				assert.Equal(t, 0, len(plugin.finished))
+			}
+
+			assert.False(t, receiver.ConnectionStateExists(sender.Address), "expected connections to be closed")
+
+			// Closing a drained network again must not panic.
+			receiver.Close()
+		})
+	}
+}
+
+func TestTellBatch(t *testing.T) {
+	t.Parallel()
+
+	var nodes []*network.Network
+
+	for i := 0; i < 2; i++ {
+		builder := network.NewBuilder()
+		builder.SetAddress(network.FormatAddress("tcp", "localhost", uint16(network.GetRandomUnusedPort())))
+		builder.AddPlugin(new(MailBoxPlugin))
+
+		node, err := builder.Build()
+		assert.Nil(t, err)
+
+		go node.Listen()
+		defer node.Close()
+
+		node.BlockUntilListening()
+		nodes = append(nodes, node)
+	}
+
+	plugin, _ := nodes[1].Plugin(mailboxPluginID)
+	mailbox := plugin.(*MailBoxPlugin)
+
+	client, err := nodes[0].Client(nodes[1].Address)
+	assert.Nil(t, err)
+
+	var batch []proto.Message
+	var expected []string
+
+	for i := 0; i < 10; i++ {
+		batch = append(batch, &protobuf.TestMessage{Message: fmt.Sprintf("batch %d", i)})
+		expected = append(expected, fmt.Sprintf("batch %d", i))
+	}
+
+	assert.Nil(t, client.TellBatch(context.Background(), batch))
+
+	// Plugins handle messages concurrently, so messages may be received in any order.
+	var received []string
+	for len(received) < len(batch) {
+		select {
+		case msg := <-mailbox.RecvMailbox:
+			received = append(received, msg.Message)
+		case <-time.After(3 * time.Second):
+			t.Fatalf("expected all messages of the batch to be delivered, got %v", received)
+		}
+	}
+
+	assert.ElementsMatch(t, expected, received)
+
+	select {
+	case msg := <-mailbox.RecvMailbox:
+		t.Fatalf("expected messages of the batch to be delivered once, got %q again", msg.Message)
+	case <-time.After(250 * time.Millisecond):
+	}
+}
+
+func TestReplayProtection(t *testing.T) {
+	t.Parallel()
+
+	var nodes []*network.Network
+
+	for i := 0; i < 2; i++ {
+		builder := network.NewBuilder()
+		builder.SetAddress(network.FormatAddress("tcp", "localhost", uint16(network.GetRandomUnusedPort())))
+		builder.AddPlugin(new(MailBoxPlugin))
+
+		node, err := builder.Build()
+		assert.Nil(t, err)
+
+		go node.Listen()
+		defer node.Close()
+
+		node.BlockUntilListening()
+		nodes = append(nodes, node)
+	}
+
+	sender, receiver := nodes[0], nodes[1]
+
+	msg, err := sender.PrepareMessage(network.WithSignMessage(context.Background(), true), &protobuf.TestMessage{Message: "replayed"})
+	assert.Nil(t, err)
+
+	raw, err := proto.Marshal(msg)
+	assert.Nil(t, err)
+
+	// Capture the frame as it would be sent over the wire.
+	frame := make([]byte, 4+len(raw))
+	binary.BigEndian.PutUint32(frame, uint32(len(raw)))
+	copy(frame[4:], raw)
+
+	info, err := network.ParseAddress(receiver.Address)
+	assert.Nil(t, err)
+
+	mailbox, _ := receiver.Plugin(mailboxPluginID)
+
+	// Send the frame, and replay it over a fresh connection.
+	for i := 0; i < 2; i++ {
+		conn, err := net.Dial("tcp", info.HostPort())
+		assert.Nil(t, err)
+		defer conn.Close()
+
+		_, err = conn.Write(frame)
+		assert.Nil(t, err)
+
+		if i == 0 {
+			select {
+			case received := <-mailbox.(*MailBoxPlugin).RecvMailbox:
+				assert.Equal(t, "replayed", received.Message)
+			case <-time.After(3 * time.Second):
+				t.Fatal("timed out waiting for message")
+			}
+		}
+	}
+
+	deadline := time.Now().Add(3 * time.Second)
+	for receiver.Stats().MessagesDropped != 1 {
+		if time.Now().After(deadline) {
+			t.Fatalf("MessagesDropped = %d, expected 1", receiver.Stats().MessagesDropped)
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	select {
+	case <-mailbox.(*MailBoxPlugin).RecvMailbox:
+		t.Fatal("expected replayed message to be dropped")
+	case <-time.After(250 * time.Millisecond):
+	}
+}
+
+func TestDisablePlugin(t *testing.T) {
+	t.Parallel()
+
+	var nodes []*network.Network
+
+	for i := 0; i < 2; i++ {
+		builder := network.NewBuilder()
+		builder.SetAddress(network.FormatAddress("tcp", "localhost", uint16(network.GetRandomUnusedPort())))
+		builder.AddPlugin(new(discovery.Plugin))
+
+		node, err := builder.Build()
+		assert.Nil(t, err)
+
+		go node.Listen()
+		defer node.Close()
+
+		node.BlockUntilListening()
+		nodes = append(nodes, node)
+	}
+
+	target, sender := nodes[0], nodes[1]
+
+	assert.Nil(t, target.DisablePlugin(discovery.PluginID))
+
+	client, err := sender.Client(target.Address)
+	assert.Nil(t, err)
+
+	targetID := pb.ID(target.ID)
+	request := &pb.LookupNodeRequest{Target: &targetID}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 500*time.Millisecond)
+	_, err = client.Request(ctx, request)
+	cancel()
+
+	assert.Equal(t, context.DeadlineExceeded, err)
+
+	plugin, _ := target.Plugin(discovery.PluginID)
+	routes := plugin.(*discovery.Plugin).Routes
+
+	assert.False(t, routes.PeerExists(sender.ID), "expected routing table to not be updated while disabled")
+
+	assert.Nil(t, target.EnablePlugin(discovery.PluginID))
+
+	ctx, cancel = context.WithTimeout(context.Background(), 3*time.Second)
+	response, err := client.Request(ctx, request)
+	cancel()
+
+	assert.Nil(t, err)
+	assert.IsType(t, &pb.LookupNodeResponse{}, response)
+	assert.True(t, routes.PeerExists(sender.ID), "expected routing table to be updated once enabled")
+
+	assert.NotNil(t, target.DisablePlugin((*MailBoxPlugin)(nil)))
+}
+
+func TestHandshakeTimeout(t *testing.T) {
+	const timeout = 100 * time.Millisecond
+
+	builder := network.NewBuilderWithOptions(network.ConnectionTimeout(timeout))
+	builder.SetAddress(network.FormatAddress("tcp", "localhost", uint16(network.GetRandomUnusedPort())))
+
+	node, err := builder.Build()
+	assert.Nil(t, err)
+
+	go node.Listen()
+	defer node.Close()
+
+	node.BlockUntilListening()
+
+	info, err := network.ParseAddress(node.Address)
+	assert.Nil(t, err)
+
+	baseline := runtime.NumGoroutine()
+
+	// Connect without ever sending a message.
+	conn, err := net.Dial("tcp", info.HostPort())
+	assert.Nil(t, err)
+	defer conn.Close()
+
+	// The stalled connection should be closed by the node once the timeout
+	// elapses. Connections are cleaned up a second after being closed.
+	conn.SetReadDeadline(time.Now().Add(timeout + 3*time.Second))
+	_, err = conn.Read(make([]byte, 1))
+	assert.Equal(t, io.EOF, err)
+
+	deadline := time.Now().Add(time.Second)
+	for runtime.NumGoroutine() > baseline {
+		if time.Now().After(deadline) {
+			t.Fatalf("goroutines = %d, expected stalled connection to be cleaned up to %d", runtime.NumGoroutine(), baseline)
+		}
+		time.Sleep(50 * time.Millisecond)
+	}
+}
+
+func TestWithClock(t *testing.T) {
+	t.Parallel()
+
+	fake := clock.NewFakeClock(time.Date(2018, 1, 1, 0, 0, 0, 0, time.UTC))
+
+	builder := network.NewBuilderWithOptions(network.WithClock(fake))
+	builder.SetAddress(network.FormatAddress("tcp", "localhost", uint16(network.GetRandomUnusedPort())))
+
+	node, err := builder.Build()
+	assert.Nil(t, err)
+	defer node.Close()
+
+	assert.Equal(t, fake, node.Clock())
+	assert.Equal(t, int64(0), node.TopologySnapshot().UptimeSeconds)
+
+	fake.Advance(90 * time.Second)
+	assert.Equal(t, int64(90), node.TopologySnapshot().UptimeSeconds)
+
+	_, err = node.Client(network.FormatAddress("tcp", "localhost", uint16(network.GetRandomUnusedPort())))
+	assert.NotNil(t, err)
+
+	history := node.GetConnectionHistory(1)
+	assert.Len(t, history, 1)
+	assert.Equal(t, network.EventDialFailed, history[0].EventType)
+	assert.Equal(t, fake.Now(), history[0].Time, "expected event to be timed by the clock")
+}
+
+func TestStartTime(t *testing.T) {
+	t.Parallel()
+
+	builder := network.NewBuilder()
+	builder.SetAddress(network.FormatAddress("tcp", "localhost", uint16(network.GetRandomUnusedPort())))
+
+	node, err := builder.Build()
+	assert.Nil(t, err)
+	defer node.Close()
+
+	assert.True(t, node.StartTime().IsZero(), "expected no start time before listening")
+
+	before := time.Now()
+
+	go node.Listen()
+	node.BlockUntilListening()
+
+	started := node.StartTime()
+	assert.False(t, started.Before(before))
+	assert.True(t, started.Sub(before) < 10*time.Millisecond, "expected start time to be when Listen was called, got %s after", started.Sub(before))
+	assert.True(t, started.Equal(node.TopologySnapshot().StartTime))
+}
+
+func TestConnectedAt(t *testing.T) {
+	t.Parallel()
+
+	newNode := func() *network.Network {
+		builder := network.NewBuilder()
+		builder.SetAddress(network.FormatAddress("tcp", "localhost", uint16(network.GetRandomUnusedPort())))
+
+		node, err := builder.Build()
+		assert.Nil(t, err)
+
+		go node.Listen()
+		node.BlockUntilListening()
+
+		return node
+	}
+
+	alice, bob := newNode(), newNode()
+	defer alice.Close()
+	defer bob.Close()
+
+	before := time.Now()
+
+	client, err := alice.Client(bob.Address)
+	assert.Nil(t, err)
+
+	connectedAt := client.ConnectedAt()
+	assert.False(t, connectedAt.Before(before))
+	assert.False(t, connectedAt.After(time.Now()))
+	assert.True(t, connectedAt.Equal(alice.TopologySnapshot().PeerConnectedAt[bob.Address]))
+}
+
+func TestGetConnectionHistory(t *testing.T) {
+	t.Parallel()
+
+	newNode := func() *network.Network {
+		builder := network.NewBuilder()
+		builder.SetAddress(network.FormatAddress("tcp", "localhost", uint16(network.GetRandomUnusedPort())))
+		builder.AddPlugin(new(discovery.Plugin))
+
+		node, err := builder.Build()
+		assert.Nil(t, err)
+
+		go node.Listen()
+		node.BlockUntilListening()
+
+		return node
+	}
+
+	countEvents := func(node *network.Network, eventType network.ConnectionEventType) int {
+		count := 0
+		for _, event := range node.GetConnectionHistory(100) {
+			if event.EventType == eventType {
+				count++
+			}
+		}
+		return count
+	}
+
+	hub := newNode()
+	defer hub.Close()
+
+	var peers []*network.Network
+
+	for i := 0; i < 10; i++ {
+		node := newNode()
+		peers = append(peers, node)
+
+		plugin, _ := node.Plugin(discovery.PluginID)
+		routes := plugin.(*discovery.Plugin).Routes
+
+		// Bootstrapping has the peer identify itself to the hub, and the hub reply with a pong.
+		deadline := time.Now().Add(3 * time.Second)
+		for countEvents(hub, network.EventConnected) <= i || !routes.PeerExists(hub.ID) {
+			if time.Now().After(deadline) {
+				t.Fatalf("expected hub to accept a connection from peer %d", i)
+			}
+
+			node.Bootstrap(hub.Address)
+			time.Sleep(50 * time.Millisecond)
+		}
+	}
+
+	for _, node := range peers {
+		node.Close()
+	}
+
+	deadline := time.Now().Add(5 * time.Second)
+	for countEvents(hub, network.EventDisconnected) < 10 {
+		if time.Now().After(deadline) {
+			t.Fatal("expected hub to record all peers disconnecting")
+		}
+		time.Sleep(50 * time.Millisecond)
+	}
+
+	history := hub.GetConnectionHistory(20)
+	assert.Len(t, history, 20)
+
+	disconnected := make(map[string]bool)
+
+	for i, event := range history {
+		if i > 0 {
+			assert.False(t, event.Time.Before(history[i-1].Time), "expected events to be in chronological order")
+		}
+
+		if i < 10 {
+			assert.Equal(t, network.EventConnected, event.EventType)
+			assert.Equal(t, peers[i].Address, event.RemoteAddr)
+		} else {
+			assert.Equal(t, network.EventDisconnected, event.EventType)
+			disconnected[event.PeerID.Address] = true
+		}
+
+		assert.Nil(t, event.Error)
+	}
+
+	for _, node := range peers {
+		assert.True(t, disconnected[node.Address], "expected %s to be recorded as disconnected", node.Address)
+	}
+
+	unreachable, err := network.ToUnifiedAddress(network.FormatAddress("tcp", "localhost", uint16(network.GetRandomUnusedPort())))
+	assert.Nil(t, err)
+
+	_, err = hub.Client(unreachable)
+	assert.NotNil(t, err)
+
+	history = hub.GetConnectionHistory(1)
+	if assert.Len(t, history, 1) {
+		assert.Equal(t, network.EventDialFailed, history[0].EventType)
+		assert.Equal(t, unreachable, history[0].RemoteAddr)
+		assert.NotNil(t, history[0].Error)
+	}
+}
+
+func TestGetPeer(t *testing.T) {
+	t.Parallel()
+
+	// Networks of a cluster are identified by one another once they route one another.
+	cluster, err := testutil.NewCluster(2)
+	assert.Nil(t, err)
+	defer cluster.Stop()
+
+	nodes := cluster.Nodes()
+
+	found, ok := nodes[0].GetPeer(nodes[1].ID.Id)
+	if assert.True(t, ok, "expected remote peer to be registered") && assert.NotNil(t, found.ID) {
+		assert.Equal(t, nodes[1].ID.Id, found.ID.Id)
+		assert.Equal(t, nodes[1].ID.Address, found.ID.Address)
+	}
+
+	unknown := make([]byte, len(nodes[1].ID.Id))
+	_, err = rand.Read(unknown)
+	assert.Nil(t, err)
+
+	_, ok = nodes[0].GetPeer(unknown)
+	assert.False(t, ok)
+}
+
+func TestGetPeerByAddress(t *testing.T) {
+	t.Parallel()
+
+	cluster, err := testutil.NewCluster(2)
+	assert.Nil(t, err)
+	defer cluster.Stop()
+
+	nodes := cluster.Nodes()
+
+	found, ok := nodes[0].GetPeerByAddress(nodes[1].Address)
+	if assert.True(t, ok, "expected remote peer to be registered") {
+		assert.Equal(t, nodes[1].ID.Id, found.ID.Id)
+	}
+
+	info, err := network.ParseAddress(nodes[1].Address)
+	assert.Nil(t, err)
+
+	byHost, ok := nodes[0].GetPeerByHost(info.Host)
+	if assert.True(t, ok) {
+		assert.Equal(t, nodes[1].ID.Id, byHost.ID.Id)
+	}
+
+	unknown := network.FormatAddress(info.Protocol, info.Host, uint16(network.GetRandomUnusedPort()))
+
+	_, ok = nodes[0].GetPeerByAddress(unknown)
+	assert.False(t, ok)
+
+	_, ok = nodes[0].GetPeerByHost("192.0.2.1")
+	assert.False(t, ok)
+}
@@ -2,6 +2,15 @@ package network_test
 
 import (
 	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"math/big"
+	"net"
+	"sync"
 	"testing"
 	"time"
 
@@ -11,6 +20,7 @@ import (
 	"github.com/perlin-network/noise/internal/test/protobuf"
 	"github.com/perlin-network/noise/network"
 	"github.com/perlin-network/noise/network/discovery"
+	"github.com/perlin-network/noise/network/transport"
 	"github.com/perlin-network/noise/peer"
 	"github.com/perlin-network/noise/types/opcode"
 )
@@ -168,6 +178,28 @@ func (state *MailBoxPlugin) Receive(ctx *network.PluginContext) error {
 	return nil
 }
 
+// generateCertificate generates a self-signed TLS certificate.
+func generateCertificate(t *testing.T) tls.Certificate {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatalf("GenerateKey() = expected no error, got %v", err)
+	}
+
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: "noise"},
+		NotBefore:    time.Now().Add(-1 * time.Hour),
+		NotAfter:     time.Now().Add(1 * time.Hour),
+	}
+
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	if err != nil {
+		t.Fatalf("CreateCertificate() = expected no error, got %v", err)
+	}
+
+	return tls.Certificate{Certificate: [][]byte{der}, PrivateKey: key}
+}
+
 func isIn(address string, ids ...peer.ID) bool {
 	for _, a := range ids {
 		if a.Address == address {
@@ -202,3 +234,104 @@ func (p *clientTestPlugin) Receive(ctx *network.PluginContext) error {
 
 	return nil
 }
+
+type relayedMessage struct {
+	relayed bool
+	from    peer.ID
+	sender  peer.ID
+}
+
+// Plugin for relay test
+type relayTestPlugin struct {
+	*network.Plugin
+	received chan relayedMessage
+}
+
+// Receive records whether or not a *protobuf.TestMessage was relayed
+func (p *relayTestPlugin) Receive(ctx *network.PluginContext) error {
+	switch ctx.Message().(type) {
+	case *protobuf.TestMessage:
+		p.received <- relayedMessage{
+			relayed: ctx.IsRelayed(),
+			from:    ctx.RelayedFrom(),
+			sender:  ctx.Sender(),
+		}
+	}
+
+	return nil
+}
+
+// Plugin for drain test
+type slowTestPlugin struct {
+	*network.Plugin
+	delay    time.Duration
+	started  chan struct{}
+	finished chan struct{}
+}
+
+func (p *slowTestPlugin) Startup(net *network.Network) {
+	p.started = make(chan struct{}, 16)
+	p.finished = make(chan struct{}, 16)
+}
+
+// Receive takes time to handle a *protobuf.TestMessage
+func (p *slowTestPlugin) Receive(ctx *network.PluginContext) error {
+	switch ctx.Message().(type) {
+	case *protobuf.TestMessage:
+		p.started <- struct{}{}
+		time.Sleep(p.delay)
+		p.finished <- struct{}{}
+	}
+
+	return nil
+}
+
+// recordingLayer records listeners opened by a transport layer such that tests may close them.
+type recordingLayer struct {
+	transport.Layer
+
+	mutex     sync.Mutex
+	listeners []net.Listener
+}
+
+func (l *recordingLayer) Listen(port int) (net.Listener, error) {
+	listener, err := l.Layer.Listen(port)
+	if err == nil {
+		l.mutex.Lock()
+		l.listeners = append(l.listeners, listener)
+		l.mutex.Unlock()
+	}
+	return listener, err
+}
+
+// CloseListeners closes all listeners opened by the transport layer.
+func (l *recordingLayer) CloseListeners() {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+
+	for _, listener := range l.listeners {
+		listener.Close()
+	}
+}
+
+// proxyLayer prefixes dialed connections with a PROXY protocol v2 header, standing in
+// for a load balancer in front of the dialed node.
+type proxyLayer struct {
+	transport.Layer
+
+	source *net.TCPAddr
+}
+
+func (l *proxyLayer) Dial(address string) (net.Conn, error) {
+	conn, err := l.Layer.Dial(address)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := transport.WritePROXYHeader(conn, l.source, conn.RemoteAddr().(*net.TCPAddr)); err != nil {
+		conn.Close()
+		return nil, err
+	}
+
+	return conn, nil
+}
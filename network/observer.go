@@ -0,0 +1,67 @@
+package network
+
+import (
+	"time"
+
+	"github.com/perlin-network/noise/internal/protobuf"
+)
+
+// ObserverInterface is notified of traffic flowing through the network, such that it may
+// be exported as metrics. Unlike plugins, observers may be added once the network is built.
+type ObserverInterface interface {
+	// Callback for when a message of a size in bytes is written to a peer.
+	MessageSent(address string, size int)
+
+	// Callback for when a message of a size in bytes is read from a peer.
+	MessageReceived(address string, size int)
+
+	// Callback for when a request sent to a peer completes or fails.
+	RequestCompleted(address string, duration time.Duration, err error)
+
+	// Callback for when a peer connects to the network.
+	PeerConnect(address string)
+
+	// Callback for when a peer disconnects from the network.
+	PeerDisconnect(address string)
+}
+
+// Observer is an abstract class which all observers extend.
+type Observer struct{}
+
+// MessageSent is called every time a message is written to a peer
+func (*Observer) MessageSent(address string, size int) {}
+
+// MessageReceived is called every time a message is read from a peer
+func (*Observer) MessageReceived(address string, size int) {}
+
+// RequestCompleted is called every time a request completes or fails
+func (*Observer) RequestCompleted(address string, duration time.Duration, err error) {}
+
+// PeerConnect is called every time a PeerClient is initialized and connected
+func (*Observer) PeerConnect(address string) {}
+
+// PeerDisconnect is called every time a PeerClient connection is closed
+func (*Observer) PeerDisconnect(address string) {}
+
+// AddObserver registers an observer to be notified of traffic flowing through the network.
+func (n *Network) AddObserver(observer ObserverInterface) {
+	n.observersMutex.Lock()
+	n.observers = append(n.observers, observer)
+	n.observersMutex.Unlock()
+}
+
+// eachObserver calls fn for every registered observer.
+func (n *Network) eachObserver(fn func(observer ObserverInterface)) {
+	n.observersMutex.RLock()
+	observers := n.observers
+	n.observersMutex.RUnlock()
+
+	for _, observer := range observers {
+		fn(observer)
+	}
+}
+
+// frameSize returns the size of a message as written to the wire, including its length prefix.
+func frameSize(message *protobuf.Message) int {
+	return 4 + message.Size()
+}
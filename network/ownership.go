@@ -0,0 +1,245 @@
+package network
+
+import (
+	"bytes"
+	"crypto/rand"
+	"encoding/binary"
+	"io"
+	"net"
+	"sync"
+	"time"
+
+	"github.com/perlin-network/noise/peer"
+	"github.com/pkg/errors"
+	"golang.org/x/time/rate"
+)
+
+const (
+	// challengeFrameMarker is sent in place of a message size to denote an out-of-band
+	// address ownership challenge, which consists of a random nonce. The challenged node
+	// replies with a frame holding the size of its signature, followed by the signature.
+	challengeFrameMarker = ProbeFrameMarker - 1
+	challengeNonceSize   = 32
+
+	// challengeSignaturePrefix binds signatures of challenge nonces to their use within
+	// noise, such that they may not be replayed as signatures of messages.
+	challengeSignaturePrefix = "noise address ownership"
+
+	// maxChallengeSignatureSize bounds the size of signatures challenged nodes reply with.
+	maxChallengeSignatureSize = 1024
+
+	defaultChallengeTimeout = 3 * time.Second
+
+	// verifiedOwnershipTTL is how long a peer which proved it owns an address is not
+	// challenged again for it. Records of claimed addresses are pruned at most once per
+	// TTL, upon an address being claimed for the first time.
+	verifiedOwnershipTTL = 10 * time.Minute
+
+	// challengeBurst challenges may be sent to a claimed address at once, after which
+	// one more may be sent every challengeInterval.
+	challengeBurst    = 3
+	challengeInterval = time.Second
+)
+
+var errChallengeFrame = errors.New("received an address ownership challenge from a peer")
+
+// claimedAddress tracks the challenges sent to an address claimed by peers.
+type claimedAddress struct {
+	mutex sync.Mutex
+
+	// limiter bounds how often the address is challenged.
+	limiter *rate.Limiter
+
+	// owner is the public key of the peer which last proved it owns the address.
+	owner      []byte
+	verifiedAt time.Time
+
+	lastClaimed time.Time
+}
+
+// claimedAddress returns the record of the challenges sent to an address, pruning records
+// of addresses which have not been claimed within verifiedOwnershipTTL.
+func (n *Network) claimedAddress(address string, now time.Time) *claimedAddress {
+	if claimed, exists := n.claims.Load(address); exists {
+		return claimed.(*claimedAddress)
+	}
+
+	n.claimsMutex.Lock()
+	if now.Sub(n.claimsPruned) >= verifiedOwnershipTTL {
+		n.claims.Range(func(address, claimed interface{}) bool {
+			c := claimed.(*claimedAddress)
+
+			c.mutex.Lock()
+			idle := now.Sub(c.lastClaimed) >= verifiedOwnershipTTL
+			c.mutex.Unlock()
+
+			if idle {
+				n.claims.Delete(address)
+			}
+			return true
+		})
+		n.claimsPruned = now
+	}
+	n.claimsMutex.Unlock()
+
+	claimed, _ := n.claims.LoadOrStore(address, &claimedAddress{
+		limiter:     rate.NewLimiter(rate.Every(challengeInterval), challengeBurst),
+		lastClaimed: now,
+	})
+	return claimed.(*claimedAddress)
+}
+
+// challengePayload returns the bytes a node signs to prove it listens at an address.
+func challengePayload(nonce []byte, address string) []byte {
+	payload := make([]byte, 0, len(challengeSignaturePrefix)+len(nonce)+len(address))
+	payload = append(payload, challengeSignaturePrefix...)
+	payload = append(payload, nonce...)
+	return append(payload, address...)
+}
+
+// handleChallengeFrame reads the nonce of an address ownership challenge off of a
+// connection, and replies over the same connection with the nonce signed by the networks
+// static private key.
+func (n *Network) handleChallengeFrame(conn net.Conn) error {
+	nonce := make([]byte, challengeNonceSize)
+	if _, err := io.ReadFull(conn, nonce); err != nil {
+		return err
+	}
+
+	signature, err := n.Sign(challengePayload(nonce, n.SelfAddress()))
+	if err != nil {
+		return errors.Wrap(err, "failed to sign address ownership challenge")
+	}
+
+	frame := make([]byte, 4+2+len(signature))
+	binary.BigEndian.PutUint32(frame[0:4], challengeFrameMarker)
+	binary.BigEndian.PutUint16(frame[4:6], uint16(len(signature)))
+	copy(frame[6:], signature)
+
+	if _, err := conn.Write(frame); err != nil {
+		return errors.Wrap(err, "failed to reply to address ownership challenge")
+	}
+
+	return errChallengeFrame
+}
+
+// verifyAddressOwnership dials the address a peer claims within its ID, and challenges
+// the node listening at the address to sign a random nonce. Errors should the node not
+// sign the nonce with the private key of the peer, such that peers may not claim the
+// addresses of other nodes.
+//
+// Peers may only claim addresses on the host they connected from, such that the network
+// may not be made to dial other hosts. Each address is challenged at most challengeBurst
+// times at once, and peers which proved they own an address are not challenged for it
+// again within verifiedOwnershipTTL.
+func (n *Network) verifyAddressOwnership(id peer.ID, remote net.Addr) error {
+	if err := matchesRemoteHost(id.Address, remote); err != nil {
+		return errors.Wrapf(err, "network: peer %s may not claim address %s", id.ShortString(), id.Address)
+	}
+
+	now := n.opts.clock.Now()
+
+	claimed := n.claimedAddress(id.Address, now)
+
+	claimed.mutex.Lock()
+	defer claimed.mutex.Unlock()
+
+	claimed.lastClaimed = now
+
+	if bytes.Equal(claimed.owner, id.PublicKey) && now.Sub(claimed.verifiedAt) < verifiedOwnershipTTL {
+		return nil
+	}
+
+	if !claimed.limiter.AllowN(now, 1) {
+		return errors.Errorf("network: address %s claimed by peer %s was challenged too often; try again later", id.Address, id.ShortString())
+	}
+
+	if err := n.challengeAddress(id); err != nil {
+		return err
+	}
+
+	claimed.owner, claimed.verifiedAt = id.PublicKey, now
+
+	return nil
+}
+
+// matchesRemoteHost errors should an address not be on the host a connection originated
+// from. Connections from loopback addresses, or over transports which are not addressed
+// by IP, may claim any address.
+func matchesRemoteHost(address string, remote net.Addr) error {
+	var ip net.IP
+
+	switch addr := remote.(type) {
+	case *net.TCPAddr:
+		ip = addr.IP
+	case *net.UDPAddr:
+		ip = addr.IP
+	default:
+		return nil
+	}
+
+	if ip.IsLoopback() {
+		return nil
+	}
+
+	unified, err := ToUnifiedAddress(address)
+	if err != nil {
+		return err
+	}
+
+	info, err := ParseAddress(unified)
+	if err != nil {
+		return err
+	}
+
+	if !ip.Equal(net.ParseIP(info.Host)) {
+		return errors.Errorf("address is not on host %s the peer connected from", ip)
+	}
+
+	return nil
+}
+
+// challengeAddress dials the address a peer claims, and challenges the node listening at
+// the address to sign a random nonce with the private key of the peer.
+func (n *Network) challengeAddress(id peer.ID) error {
+	conn, err := n.Dial(id.Address)
+	if err != nil {
+		return errors.Wrapf(err, "network: failed to dial address %s claimed by peer %s", id.Address, id.ShortString())
+	}
+	defer conn.Close()
+
+	conn.SetDeadline(n.opts.clock.Now().Add(defaultChallengeTimeout))
+
+	challenge := make([]byte, 4+challengeNonceSize)
+	binary.BigEndian.PutUint32(challenge[0:4], challengeFrameMarker)
+
+	nonce := challenge[4:]
+	if _, err := rand.Read(nonce); err != nil {
+		return errors.Wrap(err, "failed to generate address ownership challenge")
+	}
+
+	if _, err := conn.Write(challenge); err != nil {
+		return errors.Wrapf(err, "network: failed to challenge address %s claimed by peer %s", id.Address, id.ShortString())
+	}
+
+	header := make([]byte, 4+2)
+	if _, err := io.ReadFull(conn, header); err != nil {
+		return errors.Wrapf(err, "network: node at address %s claimed by peer %s did not answer challenge", id.Address, id.ShortString())
+	}
+
+	size := binary.BigEndian.Uint16(header[4:6])
+	if binary.BigEndian.Uint32(header[0:4]) != challengeFrameMarker || size > maxChallengeSignatureSize {
+		return errors.Errorf("network: node at address %s claimed by peer %s sent a malformed challenge reply", id.Address, id.ShortString())
+	}
+
+	signature := make([]byte, size)
+	if _, err := io.ReadFull(conn, signature); err != nil {
+		return errors.Wrapf(err, "network: node at address %s claimed by peer %s did not answer challenge", id.Address, id.ShortString())
+	}
+
+	if !n.Verify(id.PublicKey, challengePayload(nonce, id.Address), signature) {
+		return errors.Errorf("network: peer %s does not own address %s it claims; challenge was not signed by its key", id.ShortString(), id.Address)
+	}
+
+	return nil
+}
@@ -0,0 +1,145 @@
+package network
+
+import (
+	"context"
+	"net"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/perlin-network/noise/clock"
+	"github.com/perlin-network/noise/crypto/ed25519"
+	"github.com/perlin-network/noise/internal/protobuf"
+	"github.com/perlin-network/noise/peer"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAddressVerification(t *testing.T) {
+	t.Parallel()
+
+	var nodes []*Network
+	var plugins []*pingRecorderPlugin
+
+	for i := 0; i < 3; i++ {
+		plugin := new(pingRecorderPlugin)
+
+		builder := NewBuilderWithOptions(WithAddressVerification(i == 0))
+		builder.SetAddress(FormatAddress("tcp", "localhost", uint16(GetRandomUnusedPort())))
+		builder.AddPlugin(plugin)
+
+		node, err := builder.Build()
+		assert.Nil(t, err)
+
+		nodes = append(nodes, node)
+		plugins = append(plugins, plugin)
+	}
+
+	verifier, honest, attacker := nodes[0], nodes[1], nodes[2]
+
+	// The attacker listens at its own address, yet claims the address of the honest node.
+	attacker.ID.Address = honest.Address
+
+	for _, node := range nodes {
+		go node.Listen()
+		defer node.Close()
+
+		node.BlockUntilListening()
+	}
+
+	sendPing(t, honest, plugins[0], verifier.Address)
+
+	client, err := attacker.Client(verifier.Address)
+	assert.Nil(t, err)
+	assert.Nil(t, client.Tell(context.Background(), &protobuf.Ping{}))
+
+	deadline := time.Now().Add(3 * time.Second)
+
+	for {
+		var rejection error
+
+		for _, event := range verifier.GetConnectionHistory(defaultConnectionHistorySize) {
+			if event.EventType == EventAcceptFailed && event.PeerID.Equals(attacker.ID) {
+				rejection = event.Error
+			}
+		}
+
+		if rejection != nil {
+			assert.True(t, strings.Contains(rejection.Error(), "does not own address"), rejection.Error())
+			break
+		}
+
+		if time.Now().After(deadline) {
+			t.Fatal("expected connection from peer claiming an address it does not own to be rejected")
+		}
+
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	_, ok := verifier.GetPeer(attacker.ID.Id)
+	assert.False(t, ok, "expected peer claiming an address it does not own to not be identified")
+}
+
+func TestAddressVerificationLimits(t *testing.T) {
+	t.Parallel()
+
+	fake := clock.NewFakeClock(time.Now())
+
+	var nodes []*Network
+
+	for i := 0; i < 2; i++ {
+		builder := NewBuilderWithOptions(WithClock(fake))
+		builder.SetAddress(FormatAddress("tcp", "localhost", uint16(GetRandomUnusedPort())))
+
+		node, err := builder.Build()
+		assert.Nil(t, err)
+
+		go node.Listen()
+		defer node.Close()
+
+		node.BlockUntilListening()
+
+		nodes = append(nodes, node)
+	}
+
+	verifier, honest := nodes[0], nodes[1]
+
+	remote := &net.TCPAddr{IP: net.IPv4(127, 0, 0, 1), Port: 3000}
+
+	assert.Nil(t, verifier.verifyAddressOwnership(honest.ID, remote))
+
+	// Peers claiming the address of the honest node exhaust the challenges of the address.
+	attacker := peer.CreateID(honest.ID.Address, ed25519.RandomKeyPair().PublicKey)
+
+	for i := 1; i < challengeBurst; i++ {
+		err := verifier.verifyAddressOwnership(attacker, remote)
+		if assert.NotNil(t, err) {
+			assert.True(t, strings.Contains(err.Error(), "does not own address"), err.Error())
+		}
+	}
+
+	err := verifier.verifyAddressOwnership(attacker, remote)
+	if assert.NotNil(t, err) {
+		assert.True(t, strings.Contains(err.Error(), "challenged too often"), err.Error())
+	}
+
+	// The honest node is not challenged again for the address it proved it owns.
+	assert.Nil(t, verifier.verifyAddressOwnership(honest.ID, remote))
+
+	honest.Close()
+	fake.Advance(verifiedOwnershipTTL)
+
+	assert.NotNil(t, verifier.verifyAddressOwnership(honest.ID, remote), "expected verified addresses to be challenged again after the ttl")
+}
+
+func TestMatchesRemoteHost(t *testing.T) {
+	t.Parallel()
+
+	remote := &net.TCPAddr{IP: net.IPv4(10, 0, 0, 1), Port: 3000}
+
+	assert.Nil(t, matchesRemoteHost("tcp://10.0.0.1:3000", remote))
+	assert.NotNil(t, matchesRemoteHost("tcp://10.0.0.2:3000", remote), "expected addresses on other hosts to be rejected")
+
+	assert.Nil(t, matchesRemoteHost("tcp://10.0.0.2:3000", &net.TCPAddr{IP: net.IPv4(127, 0, 0, 1), Port: 3000}))
+	assert.Nil(t, matchesRemoteHost("tcp://10.0.0.2:3000", &net.UnixAddr{Name: "/tmp/noise.sock", Net: "unix"}))
+}
@@ -1,11 +1,30 @@
 package network
 
+import (
+	"time"
+
+	"github.com/perlin-network/noise/peer"
+
+	"github.com/pkg/errors"
+)
+
+// ErrStopDispatch may be returned by a plugins Receive callback to stop the
+// incoming message from being dispatched to any further plugins.
+var ErrStopDispatch = errors.New("network: stop dispatching message")
+
+// errPluginTimeout is recorded for plugins abandoned for taking longer than the plugin
+// timeout to receive a message.
+var errPluginTimeout = errors.New("network: plugin timed out while receiving message")
+
+// errPluginPanicked is recorded for plugins which panicked while receiving a message.
+var errPluginPanicked = errors.New("network: plugin panicked while receiving message")
+
 // PluginInterface is used to proxy callbacks to a particular Plugin instance.
 type PluginInterface interface {
 	// Callback for when the network starts listening for peers.
 	Startup(net *Network)
 
-	// Callback for when an incoming message is received. Return true
+	// Callback for when an incoming message is received. Return ErrStopDispatch
 	// if the plugin will intercept messages to be processed.
 	Receive(ctx *PluginContext) error
 
@@ -19,6 +38,91 @@ type PluginInterface interface {
 	PeerDisconnect(client *PeerClient)
 }
 
+// Configurable is implemented by plugins which accept structured configuration.
+//
+// Should a plugin implement Configurable, the network passes it the section of
+// the builders config keyed by the plugins package name (e.g. "discovery")
+// before Startup is called.
+type Configurable interface {
+	Configure(cfg map[string]interface{}) error
+}
+
+// Prioritized is implemented by plugins which care about the order in which
+// incoming messages are dispatched to them.
+//
+// Plugins with a higher priority have Receive called earlier. Plugins which do
+// not implement Prioritized have a priority of 0.
+type Prioritized interface {
+	Priority() int
+}
+
+// PeerLookup is implemented by plugins which are able to look up the peers
+// closest to a target ID throughout the network (e.g. discovery.Plugin).
+type PeerLookup interface {
+	// Lookup returns at most count peers closest to target, sorted by distance.
+	Lookup(net *Network, target peer.ID, count int) []peer.ID
+}
+
+// BucketReporter is implemented by plugins which maintain a routing table of peers
+// split into buckets (e.g. discovery.Plugin).
+type BucketReporter interface {
+	// BucketDistribution returns the number of peers held within each bucket.
+	BucketDistribution() []int
+}
+
+// AsyncStartup is implemented by plugins whose startup is too expensive to hold up the
+// network from listening for peers (e.g. loading a large snapshot from disk).
+//
+// Should a plugin implement AsyncStartup, StartupAsync is called in place of Startup, and
+// the network listens for peers without waiting for it. The plugin signals it is ready by
+// closing the returned channel or sending nil on it. Incoming messages are held back from
+// the plugin, and the plugins after it, until it is ready. Should it send an error
+// instead, the plugin is disabled.
+type AsyncStartup interface {
+	StartupAsync(net *Network) <-chan error
+}
+
+// StatefulPlugin is implemented by plugins whose state should survive the node being
+// restarted (e.g. upon being upgraded).
+//
+// Should a state directory be set through WithStateDir, the network persists the
+// serialized state of the plugin to it upon CloseWithDrain, and has the plugin
+// deserialize it before Startup once the network listens for peers again.
+type StatefulPlugin interface {
+	Serialize() ([]byte, error)
+	Deserialize(data []byte) error
+}
+
+// InternalReceiver is implemented by plugins which accept in-process messages
+// from other plugins sent through Network.SendToPlugin.
+type InternalReceiver interface {
+	ReceiveInternal(msg interface{}) error
+}
+
+// RestartMode denotes when a plugin is restarted after failing to handle an incoming message.
+type RestartMode int
+
+const (
+	// RestartNever never restarts a plugin. Panics are still recovered from.
+	RestartNever RestartMode = iota
+	// RestartOnPanic restarts a plugin should its Receive callback panic.
+	RestartOnPanic
+	// RestartAlways restarts a plugin should its Receive callback either panic or return an error.
+	RestartAlways
+)
+
+// RestartPolicy decides whether a plugin has its Cleanup and Startup callbacks
+// invoked again after failing to handle an incoming message.
+type RestartPolicy struct {
+	Mode RestartMode
+
+	// MaxRestarts is the maximum number of times a plugin is restarted. Unlimited if zero.
+	MaxRestarts int
+
+	// RestartBackoff is the minimum amount of time in between two restarts of a plugin.
+	RestartBackoff time.Duration
+}
+
 // Plugin is an abstract class which all plugins extend.
 type Plugin struct{}
 
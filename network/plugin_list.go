@@ -3,12 +3,142 @@ package network
 import (
 	"reflect"
 	"sort"
+	"sync"
+	"sync/atomic"
+	"time"
 )
 
 // PluginInfo wraps a priority level with a plugin interface.
 type PluginInfo struct {
 	Priority int
 	Plugin   PluginInterface
+
+	disabled uint32 // for atomic ops
+
+	restartMutex sync.Mutex
+	restarts     int
+	lastRestart  time.Time
+	restarting   bool
+
+	// receiveMutex is read locked while the plugin receives a message, such that the
+	// plugin is only restarted once the messages it is receiving have been handled.
+	receiveMutex sync.RWMutex
+
+	// ready is closed once the plugin is ready to receive messages, and is nil should
+	// the plugin be neither starting up nor restarting.
+	readyMutex sync.Mutex
+	ready      chan struct{}
+
+	// held counts the messages held back from the plugin until it is ready.
+	held int32 // for atomic ops
+
+	// allowed and denied hold the public keys (string) of the senders whose messages
+	// are and are not dispatched to the plugin.
+	sendersMutex sync.RWMutex
+	allowed      map[string]struct{}
+	denied       map[string]struct{}
+}
+
+// SetEnabled sets whether or not incoming messages are dispatched to the plugin.
+func (info *PluginInfo) SetEnabled(enabled bool) {
+	if enabled {
+		atomic.StoreUint32(&info.disabled, 0)
+	} else {
+		atomic.StoreUint32(&info.disabled, 1)
+	}
+}
+
+// Enabled returns true if incoming messages are dispatched to the plugin.
+func (info *PluginInfo) Enabled() bool {
+	return atomic.LoadUint32(&info.disabled) == 0
+}
+
+// hold holds back messages from the plugin until release is called.
+func (info *PluginInfo) hold() {
+	info.readyMutex.Lock()
+	if info.ready == nil {
+		info.ready = make(chan struct{})
+	}
+	info.readyMutex.Unlock()
+}
+
+// release dispatches messages held back from the plugin to it.
+func (info *PluginInfo) release() {
+	info.readyMutex.Lock()
+	if info.ready != nil {
+		close(info.ready)
+		info.ready = nil
+	}
+	info.readyMutex.Unlock()
+}
+
+// readySignal returns a channel which is closed once the plugin is ready to receive
+// messages, or nil should it be ready already.
+func (info *PluginInfo) readySignal() chan struct{} {
+	info.readyMutex.Lock()
+	defer info.readyMutex.Unlock()
+
+	return info.ready
+}
+
+// AllowSender allows messages from a sender to be dispatched to the plugin. Once any
+// sender is allowed, messages from all other senders are no longer dispatched to it.
+func (info *PluginInfo) AllowSender(publicKey []byte) {
+	info.sendersMutex.Lock()
+	defer info.sendersMutex.Unlock()
+
+	if info.allowed == nil {
+		info.allowed = make(map[string]struct{})
+	}
+	info.allowed[string(publicKey)] = struct{}{}
+}
+
+// DenySender stops messages from a sender from being dispatched to the plugin, even
+// should the sender be allowed.
+func (info *PluginInfo) DenySender(publicKey []byte) {
+	info.sendersMutex.Lock()
+	defer info.sendersMutex.Unlock()
+
+	if info.denied == nil {
+		info.denied = make(map[string]struct{})
+	}
+	info.denied[string(publicKey)] = struct{}{}
+}
+
+// AcceptsSender returns true if messages from a sender are dispatched to the plugin.
+func (info *PluginInfo) AcceptsSender(publicKey []byte) bool {
+	info.sendersMutex.RLock()
+	defer info.sendersMutex.RUnlock()
+
+	if _, denied := info.denied[string(publicKey)]; denied {
+		return false
+	}
+
+	if info.allowed == nil {
+		return true
+	}
+
+	_, allowed := info.allowed[string(publicKey)]
+	return allowed
+}
+
+// acceptsMessage returns true if a message is dispatched to the plugin. Relayed messages
+// are checked against the peer they originated from, and are not dispatched should the
+// peer which relayed them be denied either.
+func (info *PluginInfo) acceptsMessage(ctx *PluginContext) bool {
+	if !info.AcceptsSender(ctx.RelayedFrom().PublicKey) {
+		return false
+	}
+
+	if !ctx.IsRelayed() {
+		return true
+	}
+
+	info.sendersMutex.RLock()
+	defer info.sendersMutex.RUnlock()
+
+	_, denied := info.denied[string(ctx.client.ID.PublicKey)]
+	return !denied
 }
 
 // PluginList holds a statically-typed sorted map of plugins
@@ -16,6 +146,9 @@ type PluginInfo struct {
 type PluginList struct {
 	keys   map[reflect.Type]*PluginInfo
 	values []*PluginInfo
+
+	// receivers holds values ordered by the dispatch priority of each plugin.
+	receivers []*PluginInfo
 }
 
 // NewPluginList creates a new instance of a sorted plugin list.
@@ -31,6 +164,24 @@ func (m *PluginList) SortByPriority() {
 	sort.SliceStable(m.values, func(i, j int) bool {
 		return m.values[i].Priority < m.values[j].Priority
 	})
+	m.sortReceivers()
+}
+
+// sortReceivers orders the plugins incoming messages are dispatched to by
+// descending dispatch priority, preserving list order amongst equal priorities.
+func (m *PluginList) sortReceivers() {
+	m.receivers = append(m.receivers[:0], m.values...)
+
+	sort.SliceStable(m.receivers, func(i, j int) bool {
+		return receivePriority(m.receivers[i].Plugin) > receivePriority(m.receivers[j].Plugin)
+	})
+}
+
+func receivePriority(plugin PluginInterface) int {
+	if prioritized, ok := plugin.(Prioritized); ok {
+		return prioritized.Priority()
+	}
+	return 0
 }
 
 // PutInfo places a new plugins info onto the list.
@@ -41,6 +192,7 @@ func (m *PluginList) PutInfo(plugin *PluginInfo) bool {
 	}
 	m.keys[ty] = plugin
 	m.values = append(m.values, plugin)
+	m.sortReceivers()
 	return true
 }
 
@@ -78,3 +230,22 @@ func (m *PluginList) Each(f func(value PluginInterface)) {
 		f(item.Plugin)
 	}
 }
+
+// EachReceiver goes through every enabled plugin in descending order of dispatch priority.
+// Plugins not implementing Prioritized are ordered as they are in the plugin list.
+func (m *PluginList) EachReceiver(f func(value PluginInterface)) {
+	m.eachReceiverInfo(func(info *PluginInfo) bool {
+		f(info.Plugin)
+		return true
+	})
+}
+
+// eachReceiverInfo goes through every enabled plugin in descending order of
+// dispatch priority while f returns true.
+func (m *PluginList) eachReceiverInfo(f func(info *PluginInfo) bool) {
+	for _, item := range m.receivers {
+		if item.Enabled() && !f(item) {
+			return
+		}
+	}
+}
@@ -1,10 +1,14 @@
 package network
 
 import (
+	"context"
 	"fmt"
 	"testing"
+	"time"
 
 	"github.com/perlin-network/noise/crypto/ed25519"
+	"github.com/perlin-network/noise/internal/protobuf"
+	"github.com/perlin-network/noise/peer"
 	"github.com/stretchr/testify/assert"
 
 	"github.com/uber-go/atomic"
@@ -118,3 +122,725 @@ func TestRegisterPlugin(t *testing.T) {
 	plugin := p.(*Plugin)
 	assert.NotEqual(t, nil, plugin)
 }
+
+type orderedPlugin struct {
+	*Plugin
+
+	priority int
+	received chan int
+}
+
+func (p *orderedPlugin) Priority() int {
+	return p.priority
+}
+
+func (p *orderedPlugin) Receive(ctx *PluginContext) error {
+	if _, ok := ctx.Message().(*protobuf.Ping); ok {
+		p.received <- p.priority
+	}
+	return nil
+}
+
+// lowPriorityPlugin is a distinct type so that it may be registered alongside orderedPlugin.
+type lowPriorityPlugin struct {
+	orderedPlugin
+}
+
+func TestReceivePriority(t *testing.T) {
+	t.Parallel()
+
+	received := make(chan int, 2)
+
+	builder := NewBuilder()
+	builder.SetAddress(FormatAddress("tcp", "localhost", uint16(GetRandomUnusedPort())))
+	builder.AddPlugin(&lowPriorityPlugin{orderedPlugin{priority: 10, received: received}})
+	builder.AddPlugin(&orderedPlugin{priority: 20, received: received})
+
+	receiver, err := builder.Build()
+	assert.Nil(t, err)
+
+	builder = NewBuilder()
+	builder.SetAddress(FormatAddress("tcp", "localhost", uint16(GetRandomUnusedPort())))
+
+	sender, err := builder.Build()
+	assert.Nil(t, err)
+
+	go receiver.Listen()
+	defer receiver.Close()
+
+	go sender.Listen()
+	defer sender.Close()
+
+	receiver.BlockUntilListening()
+	sender.Bootstrap(receiver.Address)
+
+	for _, expected := range []int{20, 10} {
+		select {
+		case priority := <-received:
+			assert.Equal(t, expected, priority)
+		case <-time.After(3 * time.Second):
+			t.Fatalf("timed out waiting for plugin with priority %d to receive ping", expected)
+		}
+	}
+}
+
+type panickingPlugin struct {
+	*Plugin
+
+	startup  atomic.Int32
+	cleanup  atomic.Int32
+	receive  atomic.Int32
+	received chan struct{}
+}
+
+func (p *panickingPlugin) Startup(net *Network) {
+	p.startup.Inc()
+}
+
+func (p *panickingPlugin) Cleanup(net *Network) {
+	p.cleanup.Inc()
+}
+
+func (p *panickingPlugin) Receive(ctx *PluginContext) error {
+	if p.receive.Inc() == 1 {
+		panic("first message")
+	}
+	p.received <- struct{}{}
+	return nil
+}
+
+func TestPluginRestart(t *testing.T) {
+	t.Parallel()
+
+	plugin := &panickingPlugin{received: make(chan struct{}, 1)}
+
+	builder := NewBuilderWithOptions(WithRestartPolicy(plugin, RestartPolicy{Mode: RestartOnPanic, MaxRestarts: 1}))
+	builder.SetAddress(FormatAddress("tcp", "localhost", uint16(GetRandomUnusedPort())))
+	builder.AddPlugin(plugin)
+
+	receiver, err := builder.Build()
+	assert.Nil(t, err)
+
+	builder = NewBuilder()
+	builder.SetAddress(FormatAddress("tcp", "localhost", uint16(GetRandomUnusedPort())))
+
+	sender, err := builder.Build()
+	assert.Nil(t, err)
+
+	go receiver.Listen()
+	defer receiver.Close()
+
+	go sender.Listen()
+	defer sender.Close()
+
+	receiver.BlockUntilListening()
+
+	client, err := sender.Client(receiver.Address)
+	assert.Nil(t, err)
+
+	assert.Nil(t, client.Tell(context.Background(), &protobuf.Ping{}))
+
+	for plugin.receive.Load() == 0 {
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	assert.Nil(t, client.Tell(context.Background(), &protobuf.Ping{}))
+
+	select {
+	case <-plugin.received:
+	case <-time.After(3 * time.Second):
+		t.Fatal("timed out waiting for restarted plugin to receive message")
+	}
+
+	assert.EqualValues(t, 2, plugin.startup.Load())
+	assert.EqualValues(t, 1, plugin.cleanup.Load())
+}
+
+func TestRestartWaitsForReceives(t *testing.T) {
+	t.Parallel()
+
+	plugin := &panickingPlugin{received: make(chan struct{}, 1)}
+
+	builder := NewBuilderWithOptions(WithRestartPolicy(plugin, RestartPolicy{Mode: RestartAlways}))
+	builder.SetAddress(FormatAddress("tcp", "localhost", uint16(GetRandomUnusedPort())))
+
+	n, err := builder.Build()
+	assert.Nil(t, err)
+
+	info := &PluginInfo{Plugin: plugin}
+
+	// Another message is still being received by the plugin.
+	info.receiveMutex.RLock()
+
+	restarted := make(chan struct{})
+	go func() {
+		n.restartPlugin(info, true)
+		close(restarted)
+	}()
+
+	time.Sleep(100 * time.Millisecond)
+
+	assert.EqualValues(t, 0, plugin.cleanup.Load(), "expected plugin to not be restarted while it is receiving a message")
+	assert.NotNil(t, info.readySignal(), "expected messages to be held back from a restarting plugin")
+
+	// Failures while the plugin is restarting do not restart it again.
+	n.restartPlugin(info, true)
+
+	info.receiveMutex.RUnlock()
+
+	select {
+	case <-restarted:
+	case <-time.After(3 * time.Second):
+		t.Fatal("timed out waiting for plugin to restart")
+	}
+
+	assert.EqualValues(t, 1, plugin.cleanup.Load())
+	assert.EqualValues(t, 1, plugin.startup.Load())
+	assert.Nil(t, info.readySignal(), "expected messages to be dispatched to the plugin once it restarted")
+}
+
+type gatingPlugin struct {
+	*Plugin
+
+	gated chan struct{}
+}
+
+func (p *gatingPlugin) Receive(ctx *PluginContext) error {
+	if _, ok := ctx.Message().(*protobuf.Ping); ok {
+		p.gated <- struct{}{}
+		return ErrStopDispatch
+	}
+	return nil
+}
+
+func TestStopDispatch(t *testing.T) {
+	t.Parallel()
+
+	gating := &gatingPlugin{gated: make(chan struct{}, 1)}
+	gated := new(MockPlugin)
+
+	builder := NewBuilder()
+	builder.SetAddress(FormatAddress("tcp", "localhost", uint16(GetRandomUnusedPort())))
+	builder.AddPlugin(gating)
+	builder.AddPlugin(gated)
+
+	receiver, err := builder.Build()
+	assert.Nil(t, err)
+
+	builder = NewBuilder()
+	builder.SetAddress(FormatAddress("tcp", "localhost", uint16(GetRandomUnusedPort())))
+
+	sender, err := builder.Build()
+	assert.Nil(t, err)
+
+	go receiver.Listen()
+	defer receiver.Close()
+
+	go sender.Listen()
+	defer sender.Close()
+
+	receiver.BlockUntilListening()
+	sender.Bootstrap(receiver.Address)
+
+	select {
+	case <-gating.gated:
+	case <-time.After(3 * time.Second):
+		t.Fatal("timed out waiting for gating plugin to receive ping")
+	}
+
+	time.Sleep(50 * time.Millisecond)
+
+	assert.EqualValues(t, 0, gated.receive.Load(), "expected ping to not be dispatched past the gating plugin")
+}
+
+func TestAcceptsSender(t *testing.T) {
+	t.Parallel()
+
+	alice, bob := []byte("alice"), []byte("bob")
+
+	info := &PluginInfo{Plugin: new(MockPlugin)}
+	assert.True(t, info.AcceptsSender(alice), "expected all senders to be accepted by default")
+
+	info.DenySender(alice)
+	assert.False(t, info.AcceptsSender(alice))
+	assert.True(t, info.AcceptsSender(bob))
+
+	info.AllowSender(bob)
+	assert.True(t, info.AcceptsSender(bob))
+	assert.False(t, info.AcceptsSender([]byte("carol")), "expected senders not allowed to be rejected once any sender is allowed")
+
+	info.AllowSender(alice)
+	assert.False(t, info.AcceptsSender(alice), "expected denying a sender to take precedence over allowing it")
+}
+
+func TestAcceptsRelayedMessage(t *testing.T) {
+	t.Parallel()
+
+	alice, bob, carol := peer.ID{PublicKey: []byte("alice")}, peer.ID{PublicKey: []byte("bob")}, peer.ID{PublicKey: []byte("carol")}
+
+	// Messages from alice relayed by bob.
+	ctx := &PluginContext{client: &PeerClient{ID: &bob}, relayedFrom: &alice}
+
+	info := &PluginInfo{Plugin: new(MockPlugin)}
+	assert.True(t, info.acceptsMessage(ctx))
+
+	info.DenySender(alice.PublicKey)
+	assert.False(t, info.acceptsMessage(ctx), "expected relayed messages to be checked against the peer they originated from")
+
+	info = &PluginInfo{Plugin: new(MockPlugin)}
+	info.AllowSender(alice.PublicKey)
+	assert.True(t, info.acceptsMessage(ctx), "expected relayed messages from allowed peers to be accepted from any relayer")
+	assert.False(t, info.acceptsMessage(&PluginContext{client: &PeerClient{ID: &alice}, relayedFrom: &carol}))
+
+	info.DenySender(bob.PublicKey)
+	assert.False(t, info.acceptsMessage(ctx), "expected messages relayed by denied peers to be rejected")
+}
+
+// slowPlugin sleeps for a second upon receiving a ping, and reports whether its context
+// was canceled meanwhile.
+type slowPlugin struct {
+	*Plugin
+
+	started  chan time.Time
+	canceled chan bool
+}
+
+func (p *slowPlugin) Receive(ctx *PluginContext) error {
+	if _, ok := ctx.Message().(*protobuf.Ping); ok {
+		p.started <- time.Now()
+		time.Sleep(1 * time.Second)
+		p.canceled <- ctx.Context().Err() != nil
+	}
+	return nil
+}
+
+func TestPluginTimeout(t *testing.T) {
+	t.Parallel()
+
+	slow := &slowPlugin{started: make(chan time.Time, 4), canceled: make(chan bool, 4)}
+	processing := &processingPlugin{seen: make(chan uint64, 4)}
+
+	builder := NewBuilderWithOptions(PluginTimeout(50 * time.Millisecond))
+	builder.SetAddress(FormatAddress("tcp", "localhost", uint16(GetRandomUnusedPort())))
+	builder.AddPluginWithPriority(1, slow)
+	builder.AddPluginWithPriority(2, processing)
+
+	receiver, err := builder.Build()
+	assert.Nil(t, err)
+
+	builder = NewBuilder()
+	builder.SetAddress(FormatAddress("tcp", "localhost", uint16(GetRandomUnusedPort())))
+
+	sender, err := builder.Build()
+	assert.Nil(t, err)
+
+	go receiver.Listen()
+	defer receiver.Close()
+
+	go sender.Listen()
+	defer sender.Close()
+
+	receiver.BlockUntilListening()
+	sender.Bootstrap(receiver.Address)
+
+	var started time.Time
+	select {
+	case started = <-slow.started:
+	case <-time.After(3 * time.Second):
+		t.Fatal("timed out waiting for slow plugin to receive ping")
+	}
+
+	select {
+	case <-processing.seen:
+		assert.True(t, time.Since(started) < 100*time.Millisecond, "expected ping to be dispatched past the slow plugin within 100ms, took %s", time.Since(started))
+	case <-time.After(time.Second):
+		t.Fatal("expected ping to be dispatched past the slow plugin once it timed out")
+	}
+
+	select {
+	case canceled := <-slow.canceled:
+		assert.True(t, canceled, "expected the context of the abandoned plugin to be canceled")
+	case <-time.After(3 * time.Second):
+		t.Fatal("timed out waiting for slow plugin to return")
+	}
+}
+
+// lateForwardingPlugin forwards pings once it times out receiving them.
+type lateForwardingPlugin struct {
+	*Plugin
+
+	forwarded chan error
+}
+
+func (p *lateForwardingPlugin) Receive(ctx *PluginContext) error {
+	if _, ok := ctx.Message().(*protobuf.Ping); ok {
+		<-ctx.Context().Done()
+		p.forwarded <- ctx.Forward(ctx.Message())
+	}
+	return nil
+}
+
+func TestPluginTimeoutForward(t *testing.T) {
+	t.Parallel()
+
+	late := &lateForwardingPlugin{forwarded: make(chan error, 4)}
+	processing := &processingPlugin{seen: make(chan uint64, 4)}
+
+	builder := NewBuilderWithOptions(PluginTimeout(50 * time.Millisecond))
+	builder.SetAddress(FormatAddress("tcp", "localhost", uint16(GetRandomUnusedPort())))
+	builder.AddPluginWithPriority(1, late)
+	builder.AddPluginWithPriority(2, processing)
+
+	receiver, err := builder.Build()
+	assert.Nil(t, err)
+
+	builder = NewBuilder()
+	builder.SetAddress(FormatAddress("tcp", "localhost", uint16(GetRandomUnusedPort())))
+
+	sender, err := builder.Build()
+	assert.Nil(t, err)
+
+	go receiver.Listen()
+	defer receiver.Close()
+
+	go sender.Listen()
+	defer sender.Close()
+
+	receiver.BlockUntilListening()
+	sender.Bootstrap(receiver.Address)
+
+	select {
+	case <-processing.seen:
+	case <-time.After(3 * time.Second):
+		t.Fatal("expected ping to be dispatched past the plugin once it timed out")
+	}
+
+	select {
+	case err := <-late.forwarded:
+		assert.Nil(t, err)
+	case <-time.After(3 * time.Second):
+		t.Fatal("timed out waiting for the abandoned plugin to forward the ping")
+	}
+
+	select {
+	case <-processing.seen:
+		t.Fatal("expected an abandoned plugin forwarding the ping to not dispatch it again")
+	case <-time.After(200 * time.Millisecond):
+	}
+}
+
+// asyncPlugin takes startupDelay to start up, and records when it receives pings.
+type asyncPlugin struct {
+	*Plugin
+
+	startupDelay time.Duration
+
+	readyAt  atomic.Int64
+	received chan time.Time
+}
+
+func (p *asyncPlugin) StartupAsync(net *Network) <-chan error {
+	ready := make(chan error)
+
+	go func() {
+		time.Sleep(p.startupDelay)
+
+		p.readyAt.Store(time.Now().UnixNano())
+		close(ready)
+	}()
+
+	return ready
+}
+
+func (p *asyncPlugin) Receive(ctx *PluginContext) error {
+	if _, ok := ctx.Message().(*protobuf.Ping); ok {
+		p.received <- time.Now()
+	}
+	return nil
+}
+
+func TestStartupAsync(t *testing.T) {
+	t.Parallel()
+
+	async := &asyncPlugin{startupDelay: 200 * time.Millisecond, received: make(chan time.Time, 4)}
+
+	builder := NewBuilder()
+	builder.SetAddress(FormatAddress("tcp", "localhost", uint16(GetRandomUnusedPort())))
+	builder.AddPlugin(async)
+
+	receiver, err := builder.Build()
+	assert.Nil(t, err)
+
+	builder = NewBuilder()
+	builder.SetAddress(FormatAddress("tcp", "localhost", uint16(GetRandomUnusedPort())))
+
+	sender, err := builder.Build()
+	assert.Nil(t, err)
+
+	start := time.Now()
+
+	go receiver.Listen()
+	defer receiver.Close()
+
+	receiver.BlockUntilListening()
+
+	go sender.Listen()
+	defer sender.Close()
+
+	sender.BlockUntilListening()
+
+	_, err = sender.Client(receiver.Address)
+	assert.Nil(t, err)
+	assert.True(t, time.Since(start) < 100*time.Millisecond, "expected connection to be accepted before the plugin started up, took %s", time.Since(start))
+	assert.EqualValues(t, 0, async.readyAt.Load(), "expected plugin to still be starting up")
+
+	sender.Bootstrap(receiver.Address)
+
+	select {
+	case received := <-async.received:
+		assert.True(t, received.UnixNano() >= async.readyAt.Load(), "expected ping to be held back until the plugin was ready")
+	case <-time.After(3 * time.Second):
+		t.Fatal("timed out waiting for ping to be dispatched to the plugin")
+	}
+}
+
+// stuckPlugin never signals being ready upon starting up.
+type stuckPlugin struct {
+	*Plugin
+}
+
+func (p *stuckPlugin) StartupAsync(net *Network) <-chan error {
+	return make(chan error)
+}
+
+func TestRestartAsyncPlugin(t *testing.T) {
+	t.Parallel()
+
+	plugin := new(stuckPlugin)
+
+	builder := NewBuilderWithOptions(WithRestartPolicy(plugin, RestartPolicy{Mode: RestartAlways}))
+	builder.SetAddress(FormatAddress("tcp", "localhost", uint16(GetRandomUnusedPort())))
+	builder.AddPlugin(plugin)
+
+	n, err := builder.Build()
+	assert.Nil(t, err)
+
+	info := n.plugins.values[0]
+
+	restarted := make(chan struct{})
+	go func() {
+		n.restartPlugin(info, false)
+		close(restarted)
+	}()
+
+	for i := 0; i < 100 && info.readySignal() == nil; i++ {
+		time.Sleep(10 * time.Millisecond)
+	}
+	assert.NotNil(t, info.readySignal(), "expected messages to be held back from the restarting plugin")
+
+	// Messages past the backlog of messages held back from the plugin are dropped.
+	info.held = maxHeldMessages
+	assert.False(t, n.awaitReady(info), "expected messages past the held back backlog to be dropped")
+	assert.EqualValues(t, maxHeldMessages, info.held)
+
+	n.Close()
+
+	select {
+	case <-restarted:
+	case <-time.After(3 * time.Second):
+		t.Fatal("expected restarting a plugin which never becomes ready to stop once the network closes")
+	}
+}
+
+// middlewarePlugin records the pings it receives, and forwards them with their
+// capabilities replaced.
+type middlewarePlugin struct {
+	*Plugin
+
+	seen chan uint64
+}
+
+func (p *middlewarePlugin) Receive(ctx *PluginContext) error {
+	if ping, ok := ctx.Message().(*protobuf.Ping); ok {
+		p.seen <- ping.Capabilities
+		return ctx.Forward(&protobuf.Ping{Capabilities: 42})
+	}
+	return nil
+}
+
+// processingPlugin records the pings it receives.
+type processingPlugin struct {
+	*Plugin
+
+	seen chan uint64
+}
+
+func (p *processingPlugin) Receive(ctx *PluginContext) error {
+	if ping, ok := ctx.Message().(*protobuf.Ping); ok {
+		p.seen <- ping.Capabilities
+	}
+	return nil
+}
+
+func TestForward(t *testing.T) {
+	t.Parallel()
+
+	middleware := &middlewarePlugin{seen: make(chan uint64, 4)}
+	processing := &processingPlugin{seen: make(chan uint64, 4)}
+
+	builder := NewBuilder()
+	builder.SetAddress(FormatAddress("tcp", "localhost", uint16(GetRandomUnusedPort())))
+	builder.AddPlugin(middleware)
+	builder.AddPlugin(processing)
+
+	receiver, err := builder.Build()
+	assert.Nil(t, err)
+
+	builder = NewBuilderWithOptions(WithCapabilities(CapabilityCompression))
+	builder.SetAddress(FormatAddress("tcp", "localhost", uint16(GetRandomUnusedPort())))
+
+	sender, err := builder.Build()
+	assert.Nil(t, err)
+
+	go receiver.Listen()
+	defer receiver.Close()
+
+	go sender.Listen()
+	defer sender.Close()
+
+	receiver.BlockUntilListening()
+	sender.Bootstrap(receiver.Address)
+
+	select {
+	case capabilities := <-middleware.seen:
+		assert.EqualValues(t, CapabilityCompression, capabilities, "expected middleware to see the original ping")
+	case <-time.After(3 * time.Second):
+		t.Fatal("timed out waiting for middleware plugin to receive ping")
+	}
+
+	select {
+	case capabilities := <-processing.seen:
+		assert.EqualValues(t, 42, capabilities, "expected processing plugin to see the forwarded ping")
+	case <-time.After(3 * time.Second):
+		t.Fatal("timed out waiting for processing plugin to receive ping")
+	}
+
+	time.Sleep(50 * time.Millisecond)
+
+	assert.Len(t, processing.seen, 0, "expected forwarded ping to be dispatched once")
+}
+
+// groupingPlugin has a group receive every ping before dispatch carries on.
+type groupingPlugin struct {
+	*Plugin
+
+	group *PeerGroup
+}
+
+func (p *groupingPlugin) Receive(ctx *PluginContext) error {
+	if _, ok := ctx.Message().(*protobuf.Ping); ok {
+		return p.group.Receive(ctx)
+	}
+	return nil
+}
+
+func TestForwardWithinGroup(t *testing.T) {
+	t.Parallel()
+
+	middleware := &middlewarePlugin{seen: make(chan uint64, 4)}
+	grouped := &processingPlugin{seen: make(chan uint64, 4)}
+	processing := &processingPlugin{seen: make(chan uint64, 4)}
+
+	grouping := new(groupingPlugin)
+
+	builder := NewBuilder()
+	builder.SetAddress(FormatAddress("tcp", "localhost", uint16(GetRandomUnusedPort())))
+	builder.AddPlugin(grouping)
+	builder.AddPlugin(processing)
+
+	receiver, err := builder.Build()
+	assert.Nil(t, err)
+
+	grouping.group, err = receiver.CreateGroup([]byte("group"), middleware, grouped)
+	assert.Nil(t, err)
+
+	builder = NewBuilder()
+	builder.SetAddress(FormatAddress("tcp", "localhost", uint16(GetRandomUnusedPort())))
+
+	sender, err := builder.Build()
+	assert.Nil(t, err)
+
+	grouping.group.AddMember(sender.Address)
+
+	go receiver.Listen()
+	defer receiver.Close()
+
+	go sender.Listen()
+	defer sender.Close()
+
+	receiver.BlockUntilListening()
+	sender.Bootstrap(receiver.Address)
+
+	for _, seen := range []chan uint64{middleware.seen, grouped.seen, processing.seen} {
+		select {
+		case <-seen:
+		case <-time.After(3 * time.Second):
+			t.Fatal("expected forwarding within the group to not stop dispatch to plugins outside of the group")
+		}
+	}
+}
+
+type syncRequest struct {
+	from string
+}
+
+type senderPlugin struct {
+	*Plugin
+
+	err error
+}
+
+func (p *senderPlugin) Startup(net *Network) {
+	p.err = net.SendToPlugin((*receiverPlugin)(nil), &syncRequest{from: "sender"})
+}
+
+type receiverPlugin struct {
+	*Plugin
+
+	requests []string
+}
+
+func (p *receiverPlugin) ReceiveInternal(msg interface{}) error {
+	switch msg := msg.(type) {
+	case *syncRequest:
+		p.requests = append(p.requests, msg.from)
+		return nil
+	}
+	return fmt.Errorf("unexpected message %T", msg)
+}
+
+func TestSendToPlugin(t *testing.T) {
+	t.Parallel()
+
+	sender, receiver := new(senderPlugin), new(receiverPlugin)
+
+	builder := NewBuilder()
+	builder.SetAddress(FormatAddress("tcp", "localhost", uint16(GetRandomUnusedPort())))
+	builder.AddPlugin(sender)
+	builder.AddPlugin(receiver)
+
+	n, err := builder.Build()
+	assert.Nil(t, err)
+
+	go n.Listen()
+	defer n.Close()
+
+	n.BlockUntilListening()
+
+	assert.Nil(t, sender.err)
+	assert.Equal(t, []string{"sender"}, receiver.requests)
+
+	assert.NotNil(t, n.SendToPlugin((*receiverPlugin)(nil), "unexpected"))
+	assert.NotNil(t, n.SendToPlugin((*senderPlugin)(nil), &syncRequest{}), "expected plugin without ReceiveInternal to error")
+	assert.NotNil(t, n.SendToPlugin((*MockPlugin)(nil), &syncRequest{}), "expected unregistered plugin to error")
+}
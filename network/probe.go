@@ -0,0 +1,155 @@
+package network
+
+import (
+	"encoding/binary"
+	"io"
+	"math"
+	"net"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+const (
+	// ProbeFrameMarker is sent in place of a message size to denote an out-of-band probe frame,
+	// which consists of a 1 byte type and an 8 byte timestamp to be echoed back.
+	ProbeFrameMarker = math.MaxUint32
+
+	// ProbeFrameSize is the size of a probe frame following its marker.
+	ProbeFrameSize = 9
+
+	probePing byte = 1
+	probePong byte = 2
+
+	defaultProbeTimeout = 3 * time.Second
+)
+
+var (
+	errProbeFrame   = errors.New("received a probe frame from a peer")
+	errProbeTimeout = errors.New("network: probe was not echoed back in time")
+)
+
+// encodeProbeFrame encodes a probe frame of a given type carrying a timestamp.
+func encodeProbeFrame(kind byte, timestamp uint64) []byte {
+	frame := make([]byte, 4+ProbeFrameSize)
+	binary.BigEndian.PutUint32(frame[0:4], ProbeFrameMarker)
+	frame[4] = kind
+	binary.BigEndian.PutUint64(frame[5:], timestamp)
+	return frame
+}
+
+// handleProbeFrame reads the remainder of a probe frame off of a connection, and echoes
+// pings back over the same connection.
+func handleProbeFrame(conn net.Conn) error {
+	frame := make([]byte, ProbeFrameSize)
+	if _, err := io.ReadFull(conn, frame); err != nil {
+		return err
+	}
+
+	if frame[0] == probePing {
+		if _, err := conn.Write(encodeProbeFrame(probePong, binary.BigEndian.Uint64(frame[1:]))); err != nil {
+			return errors.Wrap(err, "failed to echo probe")
+		}
+	}
+
+	return errProbeFrame
+}
+
+// SendPingProbe sends a ping frame to the peer outside of the message stream, and measures
+// the round-trip time it takes for the peer to echo it back. Errors should the peer not
+// echo the ping back within 3 seconds.
+func (c *PeerClient) SendPingProbe() (time.Duration, error) {
+	state, ok := c.Network.ConnectionState(c.Address)
+	if !ok {
+		return 0, errors.New("network: connection does not exist")
+	}
+
+	c.probeOnce.Do(func() {
+		go c.readProbes(state.conn)
+	})
+
+	start := time.Now()
+	timestamp := uint64(start.UnixNano())
+
+	echoed := make(chan struct{})
+	c.probes.Store(timestamp, echoed)
+	defer c.probes.Delete(timestamp)
+
+	state.writerMutex.Lock()
+	state.conn.SetWriteDeadline(time.Now().Add(c.Network.opts.writeTimeout))
+
+	_, err := state.writer.Write(encodeProbeFrame(probePing, timestamp))
+	if err == nil {
+		err = state.writer.Flush()
+	}
+
+	state.writerMutex.Unlock()
+
+	if err != nil {
+		return 0, errors.Wrap(err, "failed to send probe")
+	}
+
+	select {
+	case <-echoed:
+		rtt := time.Since(start)
+		c.recordRTT(rtt)
+
+		return rtt, nil
+	case <-c.closeSignal:
+		return 0, errors.New("network: peer client closed")
+	case <-c.Network.opts.clock.After(defaultProbeTimeout):
+		return 0, errProbeTimeout
+	}
+}
+
+// readProbes reads probe frames echoed back by the peer over its outgoing connection.
+func (c *PeerClient) readProbes(conn net.Conn) {
+	header := make([]byte, 4+ProbeFrameSize)
+
+	for {
+		if _, err := io.ReadFull(conn, header); err != nil {
+			return
+		}
+
+		if binary.BigEndian.Uint32(header[0:4]) != ProbeFrameMarker {
+			c.Network.connLog.Error().Msgf("network: peer %s sent unexpected data over an outgoing connection", c.Address)
+			return
+		}
+
+		if header[4] != probePong {
+			continue
+		}
+
+		if echoed, ok := c.probes.LoadAndDelete(binary.BigEndian.Uint64(header[5:])); ok {
+			close(echoed.(chan struct{}))
+		}
+	}
+}
+
+// recordRTT updates the smoothed round-trip time and jitter to the peer, per RFC 6298.
+func (c *PeerClient) recordRTT(rtt time.Duration) {
+	c.rttMutex.Lock()
+	defer c.rttMutex.Unlock()
+
+	if c.rtt == 0 {
+		c.rtt, c.jitter = rtt, rtt/2
+		return
+	}
+
+	deviation := c.rtt - rtt
+	if deviation < 0 {
+		deviation = -deviation
+	}
+
+	c.jitter = (3*c.jitter + deviation) / 4
+	c.rtt = (7*c.rtt + rtt) / 8
+}
+
+// RTT returns the smoothed round-trip time and jitter to the peer, as measured by ping probes.
+// Both are zero should no probe have been echoed back yet.
+func (c *PeerClient) RTT() (rtt time.Duration, jitter time.Duration) {
+	c.rttMutex.Lock()
+	defer c.rttMutex.Unlock()
+
+	return c.rtt, c.jitter
+}
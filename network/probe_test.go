@@ -0,0 +1,162 @@
+package network
+
+import (
+	"io"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/perlin-network/noise/clock"
+	"github.com/perlin-network/noise/network/transport"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// slowConn delays every write to a connection.
+type slowConn struct {
+	net.Conn
+	delay time.Duration
+}
+
+func (c *slowConn) Write(data []byte) (int, error) {
+	time.Sleep(c.delay)
+	return c.Conn.Write(data)
+}
+
+// slowLayer wraps dialed connections with a slowConn.
+type slowLayer struct {
+	transport.Layer
+	delay time.Duration
+}
+
+func (l *slowLayer) Dial(address string) (net.Conn, error) {
+	conn, err := l.Layer.Dial(address)
+	if err != nil {
+		return nil, err
+	}
+	return &slowConn{Conn: conn, delay: l.delay}, nil
+}
+
+func newProbeNetworks(t *testing.T, delay time.Duration) (*Network, *Network) {
+	var nodes []*Network
+
+	for i := 0; i < 2; i++ {
+		builder := NewBuilder()
+		builder.SetAddress(FormatAddress("tcp", "localhost", uint16(GetRandomUnusedPort())))
+
+		if i == 0 && delay > 0 {
+			builder.RegisterTransportLayer("tcp", &slowLayer{Layer: transport.NewTCP(), delay: delay})
+		}
+
+		node, err := builder.Build()
+		assert.Nil(t, err)
+
+		go node.Listen()
+		node.BlockUntilListening()
+
+		nodes = append(nodes, node)
+	}
+
+	return nodes[0], nodes[1]
+}
+
+// TestSendPingProbe is not run in parallel, such that other tests do not skew measurements.
+func TestSendPingProbe(t *testing.T) {
+	prober, peer := newProbeNetworks(t, 0)
+	defer prober.Close()
+	defer peer.Close()
+
+	client, err := prober.Client(peer.Address)
+	assert.Nil(t, err)
+
+	var total time.Duration
+
+	for i := 0; i < 100; i++ {
+		rtt, err := client.SendPingProbe()
+		assert.Nil(t, err)
+		assert.True(t, rtt > 0)
+
+		total += rtt
+	}
+
+	mean := total / 100
+	assert.True(t, mean < time.Millisecond, "expected mean RTT over loopback to be under 1ms, got %s", mean)
+
+	rtt, jitter := client.RTT()
+	assert.True(t, rtt > 0 && rtt < time.Millisecond, "expected smoothed RTT to be under 1ms, got %s", rtt)
+	assert.True(t, jitter < time.Millisecond, "expected jitter to be under 1ms, got %s", jitter)
+}
+
+func TestSendPingProbeDelayed(t *testing.T) {
+	t.Parallel()
+
+	delay := 20 * time.Millisecond
+
+	prober, peer := newProbeNetworks(t, delay)
+	defer prober.Close()
+	defer peer.Close()
+
+	client, err := prober.Client(peer.Address)
+	assert.Nil(t, err)
+
+	for i := 0; i < 5; i++ {
+		rtt, err := client.SendPingProbe()
+		assert.Nil(t, err)
+		assert.True(t, rtt >= delay, "expected RTT to include the injected delay of %s, got %s", delay, rtt)
+		assert.True(t, rtt < delay+50*time.Millisecond, "expected RTT to be close to the injected delay of %s, got %s", delay, rtt)
+	}
+}
+
+func TestSendPingProbeTimeout(t *testing.T) {
+	t.Parallel()
+
+	// The peer accepts connections, though never echoes probes back.
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	assert.Nil(t, err)
+	defer listener.Close()
+
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			go io.Copy(io.Discard, conn)
+		}
+	}()
+
+	fake := clock.NewFakeClock(time.Now())
+
+	builder := NewBuilderWithOptions(WithClock(fake))
+	builder.SetAddress(FormatAddress("tcp", "localhost", uint16(GetRandomUnusedPort())))
+
+	prober, err := builder.Build()
+	assert.Nil(t, err)
+	defer prober.Close()
+
+	client, err := prober.Client("tcp://" + listener.Addr().String())
+	assert.Nil(t, err)
+
+	result := make(chan error, 1)
+	go func() {
+		_, err := client.SendPingProbe()
+		result <- err
+	}()
+
+	fake.BlockUntil(1)
+
+	select {
+	case err := <-result:
+		t.Fatalf("expected probe to wait for the clock to be advanced, got %v", err)
+	default:
+	}
+
+	fake.Advance(defaultProbeTimeout)
+
+	select {
+	case err := <-result:
+		assert.Equal(t, errProbeTimeout, err)
+	case <-time.After(time.Second):
+		t.Fatal("expected probe to time out once the clock was advanced")
+	}
+}
@@ -0,0 +1,120 @@
+package network
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"net"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/perlin-network/noise/internal/protobuf"
+)
+
+// ReconnectPolicy decides whether a peer whose connection dropped is dialed again, rather
+// than having its client closed.
+type ReconnectPolicy struct {
+	// MaxReconnectAttempts is the number of times a peer is dialed again before its
+	// client is closed. Peers are never reconnected to if zero.
+	MaxReconnectAttempts int
+
+	// ReconnectInterval is how long to wait before each attempt.
+	ReconnectInterval time.Duration
+}
+
+// reconnect dials the peer of a client whose connection dropped again as per the networks
+// reconnect policy, replacing the connection messages are written to the peer over. The
+// peer is then pinged, such that it dials back and messages flow both ways. Messages
+// queued to the peer are kept. Returns false should the peer not be reconnected to, in
+// which case its client ought to be closed.
+//
+// Features, and with them the session and compression of the connection, are negotiated
+// anew upon dialing the peer, as the peer may have restarted with other features.
+func (n *Network) reconnect(client *PeerClient) bool {
+	policy := n.opts.reconnectPolicy
+
+	for attempt := 1; attempt <= policy.MaxReconnectAttempts; attempt++ {
+		select {
+		case <-n.kill:
+			return false
+		case <-n.opts.clock.After(policy.ReconnectInterval):
+		}
+
+		if atomic.LoadUint32(&client.closed) == 1 {
+			return false
+		}
+
+		conn, err := n.Dial(client.Address)
+		if err != nil {
+			n.connLog.Warn().
+				Err(err).
+				Str("address", client.Address).
+				Int("attempt", attempt).
+				Msg("Failed to reconnect to peer.")
+			continue
+		}
+
+		if err := verifyReconnected(client, conn); err != nil {
+			conn.Close()
+
+			n.recordConnectionEvent(EventDialFailed, client.PeerID(), client.Address, err)
+			n.connLog.Warn().
+				Err(err).
+				Str("address", client.Address).
+				Int("attempt", attempt).
+				Msg("Failed to reconnect to peer.")
+			continue
+		}
+
+		client.setFeatures(n.connFeatures(conn))
+
+		previous, ok := n.ConnectionState(client.Address)
+
+		n.connections.Store(client.Address, &ConnState{
+			conn:        conn,
+			writer:      bufio.NewWriterSize(conn, n.opts.writeBufferSize),
+			writerMutex: new(sync.Mutex),
+		})
+
+		if ok {
+			previous.writerMutex.Lock()
+			previous.conn.Close()
+			previous.writerMutex.Unlock()
+		}
+
+		n.recordConnectionEvent(EventReconnected, client.PeerID(), client.Address, nil)
+
+		n.connLog.Debug().
+			Str("address", client.Address).
+			Int("attempt", attempt).
+			Msg("Reconnected to peer.")
+
+		if err := client.Tell(context.Background(), &protobuf.Ping{Capabilities: uint64(n.Capabilities())}); err != nil {
+			n.connLog.Warn().Err(err).Str("address", client.Address).Msg("Failed to ping reconnected peer.")
+		}
+
+		return true
+	}
+
+	return false
+}
+
+// verifyReconnected checks that a peer reconnected to established its session, or
+// performed its Noise handshake, with the key it identified itself with beforehand.
+func verifyReconnected(client *PeerClient, conn net.Conn) error {
+	id := client.PeerID()
+	if id == nil {
+		return nil
+	}
+
+	if session, ok := connSession(conn); ok && !bytes.Equal(session.RemotePublicKey(), id.PublicKey) {
+		return errSessionKeyMismatch
+	}
+
+	if !matchesStaticKey(conn, id.PublicKey) {
+		return errStaticKeyMismatch
+	}
+
+	return nil
+}
@@ -0,0 +1,102 @@
+package network_test
+
+import (
+	"context"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/perlin-network/noise/internal/test/protobuf"
+	"github.com/perlin-network/noise/network"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestReconnectPolicy(t *testing.T) {
+	t.Parallel()
+
+	policy := network.ReconnectPolicy{MaxReconnectAttempts: 3, ReconnectInterval: 100 * time.Millisecond}
+
+	plugin := new(countingPlugin)
+
+	var nodes []*network.Network
+
+	for i := 0; i < 2; i++ {
+		builder := network.NewBuilderWithOptions(network.WithReconnectPolicy(policy))
+		builder.SetAddress(network.FormatAddress("tcp", "localhost", uint16(network.GetRandomUnusedPort())))
+		if i == 1 {
+			builder.AddPlugin(plugin)
+		}
+
+		node, err := builder.Build()
+		assert.Nil(t, err)
+
+		go node.Listen()
+		node.BlockUntilListening()
+		defer node.Close()
+
+		nodes = append(nodes, node)
+	}
+
+	sender, receiver := nodes[0], nodes[1]
+
+	client, err := sender.Client(receiver.Address)
+	assert.Nil(t, err)
+
+	assert.Nil(t, client.Tell(context.Background(), &protobuf.TestMessage{Message: "before"}))
+
+	identified := func(node *network.Network, id []byte) *network.PeerClient {
+		for i := 0; i < 100; i++ {
+			if client, ok := node.GetPeer(id); ok {
+				return client
+			}
+			time.Sleep(50 * time.Millisecond)
+		}
+		return nil
+	}
+
+	delivered := func(count uint32) bool {
+		for i := 0; i < 100 && atomic.LoadUint32(&plugin.count) < count; i++ {
+			time.Sleep(10 * time.Millisecond)
+		}
+		return atomic.LoadUint32(&plugin.count) == count
+	}
+
+	remote := identified(receiver, sender.ID.Id)
+	if !assert.NotNil(t, remote, "expected receiver to identify sender") {
+		return
+	}
+	assert.True(t, delivered(1))
+
+	// Have the receiver identify itself to the sender over the connection it dialed back.
+	assert.Nil(t, remote.Tell(context.Background(), &protobuf.TestMessage{Message: "reply"}))
+	assert.NotNil(t, identified(sender, receiver.ID.Id), "expected sender to identify receiver")
+
+	// Drop the connection from the side of the receiver.
+	assert.Nil(t, remote.Close())
+
+	// The sender notices the drop once its accept loop has wound down, and then waits a
+	// reconnect interval before dialing the receiver again.
+	time.Sleep(time.Second + policy.ReconnectInterval)
+
+	remote = identified(receiver, sender.ID.Id)
+	if !assert.NotNil(t, remote, "expected receiver to identify sender after reconnecting") {
+		return
+	}
+
+	active, ok := sender.GetPeer(receiver.ID.Id)
+	assert.True(t, ok, "expected sender to still have a client for the receiver")
+	assert.Equal(t, client, active, "expected the same client to be reused")
+
+	assert.Nil(t, client.Tell(context.Background(), &protobuf.TestMessage{Message: "after"}))
+
+	assert.True(t, delivered(2), "expected messages to flow after reconnecting")
+
+	reconnected := false
+	for _, event := range sender.GetConnectionHistory(256) {
+		if event.EventType == network.EventReconnected {
+			reconnected = true
+		}
+	}
+	assert.True(t, reconnected, "expected reconnect to be recorded in connection history")
+}
@@ -0,0 +1,278 @@
+package reliable
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/perlin-network/noise/internal/protobuf"
+	"github.com/perlin-network/noise/network"
+	"github.com/perlin-network/noise/peer"
+
+	"github.com/pkg/errors"
+)
+
+const (
+	// DefaultAckTimeout is the default time after which an unacknowledged message is retransmitted.
+	DefaultAckTimeout = 500 * time.Millisecond
+	// DefaultWindowSize is the default number of unacknowledged messages in flight per peer.
+	DefaultWindowSize = 64
+)
+
+// ErrPeerDisconnected returns if a peer disconnects before acknowledging a message.
+var ErrPeerDisconnected = errors.New("reliable: peer disconnected")
+
+type inflight struct {
+	msg      *protobuf.ReliableMessage
+	lastSent time.Time
+}
+
+// window tracks the messages sent to a peer which it has yet to acknowledge.
+type window struct {
+	client *network.PeerClient
+
+	// slots holds a token for every message in flight, such that senders block once the
+	// window is full.
+	slots chan struct{}
+
+	nextSequence uint64
+	inflight     map[uint64]*inflight
+
+	closed chan struct{}
+}
+
+// Plugin implements at-least-once delivery of messages to peers. Every message is stamped
+// with a sequence number, which its receiver acknowledges. Messages which are not
+// acknowledged within AckTimeout are retransmitted until they are, or the peer
+// disconnects. Handlers may thus be called more than once for the same message should an
+// acknowledgement be lost.
+type Plugin struct {
+	*network.Plugin
+
+	// AckTimeout is the time after which an unacknowledged message is retransmitted (default: DefaultAckTimeout).
+	AckTimeout time.Duration
+	// WindowSize is the number of unacknowledged messages in flight per peer (default: DefaultWindowSize).
+	WindowSize int
+
+	net *network.Network
+
+	mutex    sync.Mutex
+	windows  map[string]*window // address -> window
+	handlers []func(sender peer.ID, data []byte)
+
+	retransmissions uint64
+
+	stop chan struct{}
+}
+
+var (
+	PluginID                         = (*Plugin)(nil)
+	_        network.PluginInterface = (*Plugin)(nil)
+)
+
+func (state *Plugin) Startup(net *network.Network) {
+	state.net = net
+
+	state.mutex.Lock()
+	state.windows = make(map[string]*window)
+	state.mutex.Unlock()
+
+	state.stop = make(chan struct{})
+	go state.retransmitLoop(state.stop)
+}
+
+func (state *Plugin) Cleanup(net *network.Network) {
+	if state.stop != nil {
+		close(state.stop)
+		state.stop = nil
+	}
+}
+
+func (state *Plugin) Receive(ctx *network.PluginContext) error {
+	switch msg := ctx.Message().(type) {
+	case *protobuf.ReliableMessage:
+		if err := ctx.Client().Tell(ctx.Context(), &protobuf.ReliableAck{Sequence: msg.Sequence}); err != nil {
+			return errors.Wrap(err, "reliable: failed to acknowledge message")
+		}
+
+		state.mutex.Lock()
+		handlers := state.handlers
+		state.mutex.Unlock()
+
+		for _, handler := range handlers {
+			handler(ctx.Sender(), msg.Data)
+		}
+	case *protobuf.ReliableAck:
+		state.acknowledge(ctx.Client().Address, msg.Sequence)
+	}
+
+	return nil
+}
+
+func (state *Plugin) PeerDisconnect(client *network.PeerClient) {
+	state.mutex.Lock()
+	w, ok := state.windows[client.Address]
+	if ok && w.client == client {
+		delete(state.windows, client.Address)
+		close(w.closed)
+	}
+	state.mutex.Unlock()
+}
+
+// Handle registers a handler which is called with the data of every message received.
+// Handlers are called synchronously as messages are received, and thus should not block.
+func (state *Plugin) Handle(handler func(sender peer.ID, data []byte)) {
+	state.mutex.Lock()
+	state.handlers = append(state.handlers, handler)
+	state.mutex.Unlock()
+}
+
+// Send stamps data with the next sequence number of a peer and sends it, retransmitting it
+// until the peer acknowledges it. Blocks while the window of unacknowledged messages to
+// the peer is full.
+func (state *Plugin) Send(ctx context.Context, client *network.PeerClient, data []byte) error {
+	w := state.window(client)
+
+	select {
+	case w.slots <- struct{}{}:
+	case <-w.closed:
+		return ErrPeerDisconnected
+	case <-ctx.Done():
+		return errors.Wrap(ctx.Err(), "reliable: window is full")
+	}
+
+	state.mutex.Lock()
+	w.nextSequence++
+	msg := &protobuf.ReliableMessage{Sequence: w.nextSequence, Data: data}
+	w.inflight[msg.Sequence] = &inflight{msg: msg, lastSent: state.net.Clock().Now()}
+	state.mutex.Unlock()
+
+	// Failed transmissions are retransmitted as any other unacknowledged message.
+	if err := client.Tell(ctx, msg); err != nil {
+		logger := state.net.PluginLogger(state)
+		logger.Warn().Err(err).Str("address", client.Address).Uint64("sequence", msg.Sequence).Msg("reliable: failed to send message")
+	}
+
+	return nil
+}
+
+// Inflight returns the number of messages sent to a peer which it has yet to acknowledge.
+func (state *Plugin) Inflight(address string) int {
+	state.mutex.Lock()
+	defer state.mutex.Unlock()
+
+	if w, ok := state.windows[address]; ok {
+		return len(w.inflight)
+	}
+	return 0
+}
+
+// Retransmissions returns the number of messages which were retransmitted to peers.
+func (state *Plugin) Retransmissions() uint64 {
+	return atomic.LoadUint64(&state.retransmissions)
+}
+
+func (state *Plugin) window(client *network.PeerClient) *window {
+	state.mutex.Lock()
+	defer state.mutex.Unlock()
+
+	w, ok := state.windows[client.Address]
+	if !ok || w.client != client {
+		// Senders blocked on the window of a previous client of the peer would otherwise
+		// never be woken up.
+		if ok {
+			close(w.closed)
+		}
+
+		w = &window{
+			client:   client,
+			slots:    make(chan struct{}, state.windowSize()),
+			inflight: make(map[uint64]*inflight),
+			closed:   make(chan struct{}),
+		}
+		state.windows[client.Address] = w
+	}
+
+	return w
+}
+
+// acknowledge removes a message from the window of a peer, freeing up its slot.
+func (state *Plugin) acknowledge(address string, sequence uint64) {
+	state.mutex.Lock()
+	defer state.mutex.Unlock()
+
+	w, ok := state.windows[address]
+	if !ok {
+		return
+	}
+
+	// Acknowledgements of retransmitted messages may arrive more than once.
+	if _, ok := w.inflight[sequence]; !ok {
+		return
+	}
+
+	delete(w.inflight, sequence)
+	<-w.slots
+}
+
+func (state *Plugin) retransmitLoop(stop chan struct{}) {
+	ticker := state.net.Clock().NewTicker(state.ackTimeout() / 2)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-stop:
+			return
+		case <-ticker.C():
+			state.retransmit()
+		}
+	}
+}
+
+// retransmit resends all messages which have not been acknowledged within AckTimeout.
+func (state *Plugin) retransmit() {
+	type pending struct {
+		client *network.PeerClient
+		msg    *protobuf.ReliableMessage
+	}
+
+	var expired []pending
+
+	now := state.net.Clock().Now()
+
+	state.mutex.Lock()
+	for _, w := range state.windows {
+		for _, m := range w.inflight {
+			if now.Sub(m.lastSent) >= state.ackTimeout() {
+				m.lastSent = now
+				expired = append(expired, pending{client: w.client, msg: m.msg})
+			}
+		}
+	}
+	state.mutex.Unlock()
+
+	logger := state.net.PluginLogger(state)
+
+	for _, p := range expired {
+		atomic.AddUint64(&state.retransmissions, 1)
+
+		if err := p.client.Tell(context.Background(), p.msg); err != nil {
+			logger.Warn().Err(err).Str("address", p.client.Address).Uint64("sequence", p.msg.Sequence).Msg("reliable: failed to retransmit message")
+		}
+	}
+}
+
+func (state *Plugin) ackTimeout() time.Duration {
+	if state.AckTimeout > 0 {
+		return state.AckTimeout
+	}
+	return DefaultAckTimeout
+}
+
+func (state *Plugin) windowSize() int {
+	if state.WindowSize > 0 {
+		return state.WindowSize
+	}
+	return DefaultWindowSize
+}
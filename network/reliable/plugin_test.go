@@ -0,0 +1,190 @@
+package reliable
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/perlin-network/noise/clock"
+	"github.com/perlin-network/noise/network"
+	"github.com/perlin-network/noise/peer"
+	"github.com/perlin-network/noise/testutil"
+	"github.com/perlin-network/noise/types/opcode"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// newReliableNetworks builds a sender and a receiver connected over pipes, where the first
+// dropped transmissions of reliable messages written by the sender are lost. Connections
+// negotiate no features, such that lost messages do not stall ordered delivery.
+func newReliableNetworks(t *testing.T, dropped int, plugins ...*Plugin) ([]*network.Network, func()) {
+	return newReliableNetworksWithOptions(t, dropped, nil, plugins...)
+}
+
+func newReliableNetworksWithOptions(t *testing.T, dropped int, opts []network.BuilderOption, plugins ...*Plugin) ([]*network.Network, func()) {
+	pipes := testutil.NewPipeTransport()
+
+	var nodes []*network.Network
+	var stops []func()
+
+	opts = append([]network.BuilderOption{network.WriteBufferSize(1), network.WithFeatureNegotiation(0)}, opts...)
+
+	for i, plugin := range plugins {
+		builder := pipes.NewBuilder(opts...)
+		if i == 0 {
+			builder.RegisterTransportLayer(testutil.PipeProtocol, testutil.FaultInjectionLayer(pipes, testutil.DropMessages(opcode.ReliableMessageCode, dropped)))
+		}
+		builder.AddPlugin(plugin)
+
+		node, err := builder.Build()
+		assert.Nil(t, err)
+
+		stops = append(stops, testutil.Start(node))
+		nodes = append(nodes, node)
+	}
+
+	return nodes, func() {
+		for _, stop := range stops {
+			stop()
+		}
+	}
+}
+
+func TestRetransmission(t *testing.T) {
+	t.Parallel()
+
+	sender, receiver := &Plugin{AckTimeout: 50 * time.Millisecond}, &Plugin{AckTimeout: 50 * time.Millisecond}
+
+	nodes, stop := newReliableNetworks(t, 3, sender, receiver)
+	defer stop()
+
+	delivered := make(chan []byte, 16)
+	receiver.Handle(func(from peer.ID, data []byte) {
+		assert.Equal(t, nodes[0].ID.Address, from.Address)
+		delivered <- data
+	})
+
+	client, err := nodes[0].Client(nodes[1].Address)
+	assert.Nil(t, err)
+
+	assert.Nil(t, sender.Send(context.Background(), client, []byte("reliable")))
+
+	select {
+	case data := <-delivered:
+		assert.Equal(t, []byte("reliable"), data)
+	case <-time.After(3 * time.Second):
+		t.Fatal("expected message to be delivered after being retransmitted")
+	}
+
+	assert.True(t, sender.Retransmissions() >= 3, "expected at least 3 retransmissions, got %d", sender.Retransmissions())
+
+	for i := 0; i < 100 && sender.Inflight(client.Address) > 0; i++ {
+		time.Sleep(10 * time.Millisecond)
+	}
+	assert.Equal(t, 0, sender.Inflight(client.Address), "expected message to be acknowledged")
+}
+
+func TestWindow(t *testing.T) {
+	t.Parallel()
+
+	const size = 2
+
+	sender := &Plugin{AckTimeout: 50 * time.Millisecond, WindowSize: size}
+
+	// No message is ever acknowledged, as every transmission is lost.
+	nodes, stop := newReliableNetworks(t, 1<<30, sender, new(Plugin))
+	defer stop()
+
+	client, err := nodes[0].Client(nodes[1].Address)
+	assert.Nil(t, err)
+
+	for i := 0; i < size; i++ {
+		assert.Nil(t, sender.Send(context.Background(), client, []byte("unacknowledged")))
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+
+	assert.NotNil(t, sender.Send(ctx, client, []byte("blocked")), "expected send to block while the window is full")
+	assert.Equal(t, size, sender.Inflight(client.Address))
+}
+
+func TestPeerDisconnect(t *testing.T) {
+	t.Parallel()
+
+	sender := &Plugin{WindowSize: 1}
+
+	nodes, stop := newReliableNetworks(t, 1<<30, sender, new(Plugin))
+	defer stop()
+
+	client, err := nodes[0].Client(nodes[1].Address)
+	assert.Nil(t, err)
+
+	assert.Nil(t, sender.Send(context.Background(), client, []byte("unacknowledged")))
+
+	var wg sync.WaitGroup
+	wg.Add(1)
+
+	go func() {
+		defer wg.Done()
+		assert.Equal(t, ErrPeerDisconnected, sender.Send(context.Background(), client, []byte("blocked")))
+	}()
+
+	time.Sleep(50 * time.Millisecond)
+	client.Close()
+
+	wg.Wait()
+}
+
+func TestWindowReplaced(t *testing.T) {
+	t.Parallel()
+
+	state := &Plugin{WindowSize: 1, windows: make(map[string]*window)}
+
+	previous := &network.PeerClient{Address: "tcp://localhost:3000"}
+
+	// Fill up the window of the previous client of the peer.
+	state.window(previous).slots <- struct{}{}
+
+	blocked := make(chan error, 1)
+	go func() {
+		blocked <- state.Send(context.Background(), previous, []byte("blocked"))
+	}()
+
+	time.Sleep(50 * time.Millisecond)
+	state.window(&network.PeerClient{Address: previous.Address})
+
+	select {
+	case err := <-blocked:
+		assert.Equal(t, ErrPeerDisconnected, err)
+	case <-time.After(3 * time.Second):
+		t.Fatal("expected senders blocked on a replaced window to be woken up")
+	}
+}
+
+func TestRetransmissionClock(t *testing.T) {
+	t.Parallel()
+
+	fake := clock.NewFakeClock(time.Now())
+
+	// Retransmissions are only ever due once the fake clock is advanced.
+	sender := &Plugin{AckTimeout: time.Hour}
+
+	nodes, stop := newReliableNetworksWithOptions(t, 1<<30, []network.BuilderOption{network.WithClock(fake)}, sender, new(Plugin))
+	defer stop()
+
+	client, err := nodes[0].Client(nodes[1].Address)
+	assert.Nil(t, err)
+
+	assert.Nil(t, sender.Send(context.Background(), client, []byte("unacknowledged")))
+
+	time.Sleep(50 * time.Millisecond)
+	assert.Equal(t, uint64(0), sender.Retransmissions())
+
+	for i := 0; i < 100 && sender.Retransmissions() == 0; i++ {
+		fake.Advance(sender.AckTimeout)
+		time.Sleep(10 * time.Millisecond)
+	}
+	assert.True(t, sender.Retransmissions() > 0, "expected message to be retransmitted once the clock advanced past the ack timeout")
+}
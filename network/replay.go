@@ -0,0 +1,193 @@
+package network
+
+import (
+	"bytes"
+	"encoding/binary"
+	"sync"
+	"time"
+
+	"github.com/perlin-network/noise/internal/protobuf"
+)
+
+// replayWindowSize is the number of sequence numbers behind the highest sequence number
+// received from a peer which may still be accepted out of order.
+const replayWindowSize = 64
+
+// replayWindowIdleTimeout is how long the replay window of a peer is kept after the last
+// message received from it, should the peer no longer be connected. Replay windows are
+// pruned at most once per timeout, upon a window being created for a new peer.
+const replayWindowIdleTimeout = 10 * time.Minute
+
+// replayWindow tracks the sequence numbers of messages received from a peer as a sliding
+// bitmask, such that replayed messages may be detected.
+type replayWindow struct {
+	sync.Mutex
+
+	highest uint64
+	bitmap  uint64
+
+	lastSeen time.Time
+}
+
+// accept marks a sequence number as received at a given time. Returns false should the
+// sequence number have already been received, or fall behind the window.
+func (w *replayWindow) accept(sequence uint64, now time.Time) bool {
+	w.Lock()
+	defer w.Unlock()
+
+	w.lastSeen = now
+
+	if sequence > w.highest {
+		if shift := sequence - w.highest; shift < replayWindowSize {
+			w.bitmap = w.bitmap<<shift | 1
+		} else {
+			w.bitmap = 1
+		}
+		w.highest = sequence
+
+		return true
+	}
+
+	offset := w.highest - sequence
+	if offset >= replayWindowSize {
+		return false
+	}
+
+	mask := uint64(1) << offset
+	if w.bitmap&mask != 0 {
+		return false
+	}
+	w.bitmap |= mask
+
+	return true
+}
+
+// acceptSequence checks that a message has not been replayed by its sender. Messages
+// without a sequence number are always accepted. Unsigned messages are accepted without
+// being recorded, as any peer may claim the public key and sequence numbers of another.
+func (n *Network) acceptSequence(msg *protobuf.Message) bool {
+	if msg.Sequence == 0 || msg.Signature == nil {
+		return true
+	}
+
+	now := n.opts.clock.Now()
+
+	return n.replayWindows.get(msg.Sender.PublicKey, now, n.isConnected).accept(msg.Sequence, now)
+}
+
+// isConnected returns true should a peer identified by a public key be connected.
+func (n *Network) isConnected(publicKey []byte) bool {
+	connected := false
+
+	n.eachPeer(func(client *PeerClient) bool {
+		if id := client.PeerID(); id != nil && bytes.Equal(id.PublicKey, publicKey) {
+			connected = true
+			return false
+		}
+		return true
+	})
+
+	return connected
+}
+
+// replayWindows maps the public keys of peers to their replay windows. Lookups do not
+// allocate, as they are made for every message received.
+type replayWindows struct {
+	sync.RWMutex
+
+	windows    map[string]*replayWindow
+	lastPruned time.Time
+}
+
+func newReplayWindows() *replayWindows {
+	return &replayWindows{windows: make(map[string]*replayWindow)}
+}
+
+// get returns the replay window of a peer, creating it should it not exist. Windows of
+// peers which are no longer connected are pruned once idle for replayWindowIdleTimeout.
+func (r *replayWindows) get(publicKey []byte, now time.Time, connected func(publicKey []byte) bool) *replayWindow {
+	r.RLock()
+	window, exists := r.windows[string(publicKey)]
+	r.RUnlock()
+
+	if exists {
+		return window
+	}
+
+	r.Lock()
+	defer r.Unlock()
+
+	if window, exists = r.windows[string(publicKey)]; !exists {
+		if now.Sub(r.lastPruned) >= replayWindowIdleTimeout {
+			r.prune(now, connected)
+			r.lastPruned = now
+		}
+
+		window = new(replayWindow)
+		r.windows[string(publicKey)] = window
+	}
+
+	return window
+}
+
+// prune removes the replay windows of peers which are no longer connected, and which
+// have been idle for replayWindowIdleTimeout. Must be called with the lock held.
+func (r *replayWindows) prune(now time.Time, connected func(publicKey []byte) bool) {
+	for publicKey, window := range r.windows {
+		window.Lock()
+		idle := now.Sub(window.lastSeen) >= replayWindowIdleTimeout
+		window.Unlock()
+
+		if idle && !connected([]byte(publicKey)) {
+			delete(r.windows, publicKey)
+		}
+	}
+}
+
+// serializeSignedMessage packs all signed contents of a message together, including
+// its sequence number, the senders multiaddr, the group it was sent within and its
+// deadline. Every field is written, and every variable-length field is prefixed with its
+// length, such that the contents of one field may not be passed off as those of another.
+func serializeSignedMessage(msg *protobuf.Message) []byte {
+	serialized := SerializeMessage(msg.Sender, nil)
+	serialized = appendField(serialized, msg.Message)
+
+	var multiaddr []byte
+	if msg.Sender != nil {
+		multiaddr = []byte(msg.Sender.Multiaddr)
+	}
+	serialized = appendField(serialized, multiaddr)
+
+	var sequence [8]byte
+	binary.BigEndian.PutUint64(sequence[:], msg.Sequence)
+	serialized = append(serialized, sequence[:]...)
+
+	serialized = appendField(serialized, msg.GroupId)
+
+	var timeout [8]byte
+	binary.BigEndian.PutUint64(timeout[:], uint64(msg.Timeout))
+	serialized = append(serialized, timeout[:]...)
+
+	return serialized
+}
+
+// appendField appends a variable-length field prefixed with its length to a buffer.
+func appendField(buffer []byte, field []byte) []byte {
+	var size [4]byte
+	binary.LittleEndian.PutUint32(size[:], uint32(len(field)))
+
+	return append(append(buffer, size[:]...), field...)
+}
+
+// serializeRelayedMessage packs the contents of a relayed message signed by the peer it
+// originated from together, binding the message to its recipient.
+func serializeRelayedMessage(from *protobuf.ID, to []byte, opcode uint32, message []byte) []byte {
+	serialized := SerializeMessage(from, nil)
+	serialized = appendField(serialized, message)
+	serialized = appendField(serialized, to)
+
+	var code [4]byte
+	binary.BigEndian.PutUint32(code[:], opcode)
+
+	return append(serialized, code[:]...)
+}
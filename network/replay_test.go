@@ -0,0 +1,117 @@
+package network
+
+import (
+	"testing"
+	"time"
+
+	"github.com/perlin-network/noise/clock"
+	"github.com/perlin-network/noise/internal/protobuf"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestReplayWindow(t *testing.T) {
+	t.Parallel()
+
+	window := new(replayWindow)
+
+	testCases := []struct {
+		sequence uint64
+		expected bool
+	}{
+		{100, true},
+		{100, false},
+		{102, true},
+		{101, true},
+		{101, false},
+		{100 + replayWindowSize, true},
+		{100, false}, // fell behind the window
+		{101, false},
+		{103, true},
+		{103, false},
+		{1000, true},
+		{999, true},
+		{1000 - replayWindowSize, false},
+	}
+
+	for _, tt := range testCases {
+		assert.Equal(t, tt.expected, window.accept(tt.sequence, time.Unix(0, 0)), "accept(%d)", tt.sequence)
+	}
+}
+
+func TestReplayWindowsPrune(t *testing.T) {
+	t.Parallel()
+
+	windows := newReplayWindows()
+	now := time.Unix(1000, 0)
+
+	connected := map[string]bool{"connected": true}
+	isConnected := func(publicKey []byte) bool {
+		return connected[string(publicKey)]
+	}
+
+	for _, key := range []string{"connected", "disconnected", "active"} {
+		assert.True(t, windows.get([]byte(key), now, isConnected).accept(1, now))
+	}
+
+	now = now.Add(replayWindowIdleTimeout / 2)
+	windows.get([]byte("active"), now, isConnected).accept(2, now)
+
+	// Windows are pruned upon a window being created for a new peer.
+	now = now.Add(replayWindowIdleTimeout / 2)
+	windows.get([]byte("new"), now, isConnected)
+
+	_, exists := windows.windows["disconnected"]
+	assert.False(t, exists, "expected window of idle disconnected peer to be pruned")
+
+	for _, key := range []string{"connected", "active", "new"} {
+		_, exists := windows.windows[key]
+		assert.True(t, exists, "expected window of %s peer to be kept", key)
+	}
+
+	// Connected peers may not replay messages after idling.
+	assert.False(t, windows.get([]byte("connected"), now, isConnected).accept(1, now))
+}
+
+func TestAcceptSequenceIgnoresUnsignedMessages(t *testing.T) {
+	t.Parallel()
+
+	n := &Network{opts: options{clock: clock.RealClock{}}, replayWindows: newReplayWindows()}
+
+	message := func(sequence uint64, signature []byte) *protobuf.Message {
+		return &protobuf.Message{
+			Sender:    &protobuf.ID{PublicKey: []byte("victim")},
+			Sequence:  sequence,
+			Signature: signature,
+		}
+	}
+
+	// Unsigned messages claiming the key of another peer must not advance its window.
+	assert.True(t, n.acceptSequence(message(100+replayWindowSize, nil)))
+	assert.True(t, n.acceptSequence(message(100+replayWindowSize, nil)))
+	assert.True(t, n.acceptSequence(message(100, []byte("signature"))))
+	assert.False(t, n.acceptSequence(message(100, []byte("signature"))))
+}
+
+func TestSerializeSignedMessageFields(t *testing.T) {
+	t.Parallel()
+
+	message := func(contents string, multiaddr string, group string) *protobuf.Message {
+		return &protobuf.Message{
+			Message: []byte(contents),
+			Sender:  &protobuf.ID{Address: "tcp://localhost:3000", Id: []byte("id"), Multiaddr: multiaddr},
+			GroupId: []byte(group),
+		}
+	}
+
+	// Shifting bytes from one field into a neighbouring field must change what is signed.
+	pairs := [][2]*protobuf.Message{
+		{message("contents", "ab", ""), message("contents", "a", "b")},
+		{message("contentsa", "b", ""), message("contents", "ab", "")},
+		{message("contents", "", "group"), message("contents", "group", "")},
+	}
+
+	for _, pair := range pairs {
+		assert.NotEqual(t, serializeSignedMessage(pair[0]), serializeSignedMessage(pair[1]))
+	}
+}
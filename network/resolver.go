@@ -0,0 +1,72 @@
+package network
+
+import (
+	"encoding/hex"
+	"sync"
+
+	"github.com/perlin-network/noise/peer"
+
+	"github.com/pkg/errors"
+)
+
+// Resolver resolves the public key hash of a peer ID to the address the peer may be
+// dialed at, e.g. through DNS or a DHT.
+type Resolver interface {
+	Resolve(id []byte) (string, error)
+}
+
+// StaticResolver is a Resolver holding the addresses of peers in memory. It is the
+// default resolver of a network, and is populated with the address every peer which
+// connects to the network identifies itself with.
+type StaticResolver struct {
+	addresses sync.Map // string -> string
+}
+
+var _ Resolver = (*StaticResolver)(nil)
+
+// NewStaticResolver instantiates a new resolver without any addresses.
+func NewStaticResolver() *StaticResolver {
+	return &StaticResolver{}
+}
+
+// Store sets the address the peer with a public key hash resolves to.
+func (r *StaticResolver) Store(id []byte, address string) {
+	r.addresses.Store(string(id), address)
+}
+
+// Resolve returns the address the peer with a public key hash resolves to.
+func (r *StaticResolver) Resolve(id []byte) (string, error) {
+	address, ok := r.addresses.Load(string(id))
+	if !ok {
+		return "", errors.Errorf("network: no address is known for peer %s", hex.EncodeToString(id))
+	}
+	return address.(string), nil
+}
+
+// Resolver returns the resolver the network resolves peer IDs to addresses with.
+func (n *Network) Resolver() Resolver {
+	return n.opts.resolver
+}
+
+// lookupAddressByID resolves a peer ID to the address the peer may be dialed at. The
+// address within the ID is used should the resolver fail to resolve it.
+func (n *Network) lookupAddressByID(id peer.ID) (string, error) {
+	address, err := n.opts.resolver.Resolve(id.Id)
+	if err == nil {
+		return address, nil
+	}
+
+	if len(id.Address) > 0 {
+		return id.Address, nil
+	}
+
+	return "", err
+}
+
+// learnAddress records the address a peer identified itself with, should the network
+// resolve peer IDs with a StaticResolver.
+func (n *Network) learnAddress(id *peer.ID) {
+	if resolver, ok := n.opts.resolver.(*StaticResolver); ok {
+		resolver.Store(id.Id, id.Address)
+	}
+}
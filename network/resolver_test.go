@@ -0,0 +1,135 @@
+package network_test
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/perlin-network/noise/internal/test/protobuf"
+	"github.com/perlin-network/noise/network"
+	"github.com/perlin-network/noise/peer"
+
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+)
+
+// mockResolver resolves peer IDs to addresses held within a map, and records every ID
+// it was asked to resolve.
+type mockResolver struct {
+	mutex     sync.Mutex
+	addresses map[string]string
+	resolved  [][]byte
+}
+
+func (r *mockResolver) Resolve(id []byte) (string, error) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	r.resolved = append(r.resolved, id)
+
+	address, ok := r.addresses[string(id)]
+	if !ok {
+		return "", errors.New("mock: unknown peer")
+	}
+	return address, nil
+}
+
+func TestResolver(t *testing.T) {
+	t.Parallel()
+
+	resolver := &mockResolver{addresses: make(map[string]string)}
+
+	var nodes []*network.Network
+
+	for i := 0; i < 2; i++ {
+		var opts []network.BuilderOption
+		if i == 0 {
+			opts = append(opts, network.WithResolver(resolver))
+		}
+
+		builder := network.NewBuilderWithOptions(opts...)
+		builder.SetAddress(network.FormatAddress("tcp", "localhost", uint16(network.GetRandomUnusedPort())))
+
+		node, err := builder.Build()
+		assert.Nil(t, err)
+
+		go node.Listen()
+		defer node.Close()
+
+		node.BlockUntilListening()
+		nodes = append(nodes, node)
+	}
+
+	assert.Equal(t, resolver, nodes[0].Resolver())
+
+	resolver.addresses[string(nodes[1].ID.Id)] = nodes[1].Address
+
+	// The ID of the peer does not carry its address, and thus must be resolved.
+	target := peer.ID{PublicKey: nodes[1].ID.PublicKey, Id: nodes[1].ID.Id}
+
+	errs := nodes[0].ConnectAll(context.Background(), []peer.ID{target}, 1)
+	assert.Equal(t, 0, len(errs))
+	assert.True(t, nodes[0].ConnectionStateExists(nodes[1].Address))
+
+	resolver.mutex.Lock()
+	assert.Equal(t, [][]byte{nodes[1].ID.Id}, resolver.resolved)
+	resolver.mutex.Unlock()
+
+	unknown := peer.ID{Id: []byte("unknown")}
+
+	errs = nodes[0].ConnectAll(context.Background(), []peer.ID{unknown}, 1)
+	if assert.Equal(t, 1, len(errs)) {
+		assert.True(t, errs[0].Peer.Equals(unknown))
+	}
+}
+
+func TestStaticResolver(t *testing.T) {
+	t.Parallel()
+
+	resolver := network.NewStaticResolver()
+
+	_, err := resolver.Resolve([]byte("id"))
+	assert.NotNil(t, err)
+
+	resolver.Store([]byte("id"), "tcp://127.0.0.1:3000")
+
+	address, err := resolver.Resolve([]byte("id"))
+	assert.Nil(t, err)
+	assert.Equal(t, "tcp://127.0.0.1:3000", address)
+
+	// Networks learn the address every peer identifies itself with by default.
+	var nodes []*network.Network
+
+	for i := 0; i < 2; i++ {
+		builder := network.NewBuilder()
+		builder.SetAddress(network.FormatAddress("tcp", "localhost", uint16(network.GetRandomUnusedPort())))
+
+		node, err := builder.Build()
+		assert.Nil(t, err)
+
+		go node.Listen()
+		defer node.Close()
+
+		node.BlockUntilListening()
+		nodes = append(nodes, node)
+	}
+
+	client, err := nodes[0].Client(nodes[1].Address)
+	assert.Nil(t, err)
+
+	deadline := time.Now().Add(3 * time.Second)
+	for {
+		assert.Nil(t, client.Tell(context.Background(), &protobuf.TestMessage{}))
+
+		if address, err := nodes[1].Resolver().Resolve(nodes[0].ID.Id); err == nil {
+			assert.Equal(t, nodes[0].Address, address)
+			break
+		}
+
+		if time.Now().After(deadline) {
+			t.Fatal("expected peer to learn the address of the network")
+		}
+		time.Sleep(50 * time.Millisecond)
+	}
+}
@@ -0,0 +1,238 @@
+package network
+
+import (
+	"context"
+	"sync"
+
+	"github.com/perlin-network/noise/internal/protobuf"
+
+	"github.com/pkg/errors"
+)
+
+const defaultSendQueueDepth = 256
+
+// ErrSendQueueFull is returned when sending a message to a peer whose send queue is full,
+// under the DropNewest send queue policy.
+var ErrSendQueueFull = errors.New("network: send queue is full")
+
+// SendQueuePolicy decides what happens to a message sent to a peer whose send queue is full.
+type SendQueuePolicy uint8
+
+const (
+	// DropOldest drops the oldest queued message to make room for the message being sent,
+	// preferring messages whose sender does not await them being written. Senders awaiting
+	// a dropped message are failed with ErrSendQueueFull.
+	DropOldest SendQueuePolicy = iota
+	// DropNewest drops the message being sent.
+	DropNewest
+	// Block blocks the sender until the queue has room for the message being sent.
+	Block
+)
+
+func (p SendQueuePolicy) String() string {
+	switch p {
+	case DropOldest:
+		return "drop_oldest"
+	case DropNewest:
+		return "drop_newest"
+	case Block:
+		return "block"
+	default:
+		return "unknown"
+	}
+}
+
+// errPeerClosed is returned when sending a message to a peer whose client is closed.
+var errPeerClosed = errors.New("network: peer client is closed")
+
+// sendRequest is a batch of messages queued to be written to a peer. A single message is
+// queued as a batch of one.
+type sendRequest struct {
+	messages []*protobuf.Message
+
+	// result receives the outcome of writing the batch, should the sender await it.
+	result chan error
+}
+
+func (r *sendRequest) complete(err error) {
+	if r.result != nil {
+		r.result <- err
+	}
+}
+
+// sendQueue is a bounded queue of batches of messages awaiting being written to a peer,
+// such that a slow peer does not have messages pile up without bound.
+type sendQueue struct {
+	mutex    sync.Mutex
+	notEmpty *sync.Cond
+	notFull  *sync.Cond
+
+	requests []*sendRequest
+	depth    int
+	policy   SendQueuePolicy
+
+	dropped uint64
+	closed  bool
+
+	// writing is true while a dequeued batch is being written.
+	writing bool
+}
+
+func newSendQueue(depth int, policy SendQueuePolicy) *sendQueue {
+	if depth <= 0 {
+		depth = defaultSendQueueDepth
+	}
+
+	q := &sendQueue{depth: depth, policy: policy}
+	q.notEmpty = sync.NewCond(&q.mutex)
+	q.notFull = sync.NewCond(&q.mutex)
+
+	return q
+}
+
+// push queues a batch, applying the queues policy should it be full. Under the Block
+// policy, push waits until either the queue has room, the queue is closed or ctx is done.
+func (q *sendQueue) push(ctx context.Context, req *sendRequest) error {
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+
+	if q.policy == Block && len(q.requests) >= q.depth && !q.closed {
+		// Wake the sender up should ctx be done before the queue has room.
+		stop := make(chan struct{})
+		defer close(stop)
+
+		go func() {
+			select {
+			case <-ctx.Done():
+				q.mutex.Lock()
+				q.notFull.Broadcast()
+				q.mutex.Unlock()
+			case <-stop:
+			}
+		}()
+
+		for len(q.requests) >= q.depth && !q.closed {
+			if err := ctx.Err(); err != nil {
+				return err
+			}
+			q.notFull.Wait()
+		}
+	}
+
+	if q.closed {
+		return errPeerClosed
+	}
+
+	if len(q.requests) >= q.depth {
+		q.dropped++
+
+		if q.policy == DropNewest {
+			return ErrSendQueueFull
+		}
+
+		// The oldest batch whose sender does not await its outcome is dropped, such that
+		// senders awaiting their batches to be written are only failed should every queued
+		// batch be awaited.
+		evicted := 0
+		for i, queued := range q.requests {
+			if queued.result == nil {
+				evicted = i
+				break
+			}
+		}
+
+		q.requests[evicted].complete(ErrSendQueueFull)
+
+		copy(q.requests[evicted:], q.requests[evicted+1:])
+		q.requests[len(q.requests)-1] = nil
+		q.requests = q.requests[:len(q.requests)-1]
+	}
+
+	q.requests = append(q.requests, req)
+	q.notEmpty.Signal()
+
+	return nil
+}
+
+// pop waits for a batch to be queued, and dequeues it. Returns false once the queue is
+// closed. done must be called once the batch is written.
+func (q *sendQueue) pop() (*sendRequest, bool) {
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+
+	for len(q.requests) == 0 && !q.closed {
+		q.notEmpty.Wait()
+	}
+
+	if q.closed {
+		return nil, false
+	}
+
+	req := q.requests[0]
+	q.requests[0] = nil
+	q.requests = q.requests[1:]
+	q.writing = true
+
+	q.notFull.Signal()
+
+	return req, true
+}
+
+// drain dequeues all queued batches without waiting, such that they are written along
+// with the batch last dequeued.
+func (q *sendQueue) drain() []*sendRequest {
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+
+	if q.closed || len(q.requests) == 0 {
+		return nil
+	}
+
+	drained := q.requests
+	q.requests = make([]*sendRequest, 0, q.depth)
+
+	q.notFull.Broadcast()
+
+	return drained
+}
+
+// close discards all queued batches, and wakes up all senders and the writer.
+func (q *sendQueue) close() {
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+
+	for _, req := range q.requests {
+		req.complete(errPeerClosed)
+	}
+
+	q.closed = true
+	q.requests = nil
+	q.notEmpty.Broadcast()
+	q.notFull.Broadcast()
+}
+
+// done marks the batch last dequeued as written.
+func (q *sendQueue) done() {
+	q.mutex.Lock()
+	q.writing = false
+	q.mutex.Unlock()
+}
+
+// idle returns true if no batches are queued or being written.
+func (q *sendQueue) idle() bool {
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+	return len(q.requests) == 0 && !q.writing
+}
+
+func (q *sendQueue) len() int {
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+	return len(q.requests)
+}
+
+func (q *sendQueue) droppedCount() uint64 {
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+	return q.dropped
+}
@@ -0,0 +1,46 @@
+package network
+
+import (
+	"context"
+	"testing"
+
+	"github.com/perlin-network/noise/internal/protobuf"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSendQueueDropOldest(t *testing.T) {
+	t.Parallel()
+
+	q := newSendQueue(2, DropOldest)
+
+	batch := func(wait bool) *sendRequest {
+		req := &sendRequest{messages: []*protobuf.Message{{}}}
+		if wait {
+			req.result = make(chan error, 1)
+		}
+		return req
+	}
+
+	awaited, forgotten := batch(true), batch(false)
+
+	assert.Nil(t, q.push(context.Background(), awaited))
+	assert.Nil(t, q.push(context.Background(), forgotten))
+
+	// Fire-and-forget batches are dropped in preference to awaited batches.
+	newest := batch(true)
+	assert.Nil(t, q.push(context.Background(), newest))
+	assert.Equal(t, []*sendRequest{awaited, newest}, q.requests)
+
+	select {
+	case err := <-awaited.result:
+		t.Fatalf("expected awaited batch to not be dropped, got %v", err)
+	default:
+	}
+
+	// The oldest awaited batch is dropped should every queued batch be awaited, failing its sender.
+	assert.Nil(t, q.push(context.Background(), batch(false)))
+	assert.Equal(t, ErrSendQueueFull, <-awaited.result)
+	assert.Len(t, q.requests, 2)
+	assert.Equal(t, uint64(2), q.droppedCount())
+}
@@ -0,0 +1,186 @@
+package network_test
+
+import (
+	"context"
+	"net"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/perlin-network/noise/internal/test/protobuf"
+	"github.com/perlin-network/noise/network"
+	"github.com/perlin-network/noise/network/transport"
+	"github.com/perlin-network/noise/testutil"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSendQueuePolicy(t *testing.T) {
+	t.Parallel()
+
+	const depth = 8
+
+	testCases := []struct {
+		policy network.SendQueuePolicy
+	}{
+		{network.DropOldest},
+		{network.DropNewest},
+		{network.Block},
+	}
+
+	for _, tt := range testCases {
+		tt := tt
+		t.Run(tt.policy.String(), func(t *testing.T) {
+			t.Parallel()
+
+			var nodes []*network.Network
+
+			for i := 0; i < 2; i++ {
+				builder := network.NewBuilderWithOptions(
+					network.SendQueueDepth(depth),
+					network.WithSendQueuePolicy(tt.policy),
+					network.WriteBufferSize(16),
+				)
+				builder.SetAddress(network.FormatAddress("tcp", "localhost", uint16(network.GetRandomUnusedPort())))
+
+				// Every write of the sender is slowed down, such that its send queue fills up.
+				if i == 0 {
+					builder.RegisterTransportLayer("tcp", testutil.LatencyLayer(transport.NewTCP(), 20*time.Millisecond, 0))
+				}
+
+				node, err := builder.Build()
+				assert.Nil(t, err)
+
+				go node.Listen()
+				node.BlockUntilListening()
+				defer node.Close()
+
+				nodes = append(nodes, node)
+			}
+
+			client, err := nodes[0].Client(nodes[1].Address)
+			assert.Nil(t, err)
+
+			for i := 0; i < 100; i++ {
+				ctx, cancel := context.WithTimeout(context.Background(), 5*time.Millisecond)
+				err := client.Tell(ctx, &protobuf.TestMessage{Message: "saturate"})
+				cancel()
+
+				switch tt.policy {
+				case network.DropOldest:
+					assert.Nil(t, err)
+				case network.DropNewest:
+					if err != nil {
+						assert.Contains(t, err.Error(), network.ErrSendQueueFull.Error())
+					}
+				case network.Block:
+					if err != nil {
+						assert.Contains(t, err.Error(), context.DeadlineExceeded.Error())
+					}
+				}
+
+				assert.True(t, client.SendQueueLen() <= depth, "expected send queue to be bounded, got %d", client.SendQueueLen())
+			}
+
+			if tt.policy == network.Block {
+				assert.Zero(t, client.SendQueueDropped())
+			} else {
+				assert.True(t, client.SendQueueDropped() > 0, "expected messages to be dropped")
+			}
+
+			// Queued messages keep being delivered.
+			deadline := time.Now().Add(3 * time.Second)
+			for {
+				if _, ok := nodes[1].GetPeer(nodes[0].ID.Id); ok {
+					break
+				}
+				if time.Now().After(deadline) {
+					t.Fatal("expected queued messages to be received")
+				}
+				time.Sleep(10 * time.Millisecond)
+			}
+		})
+	}
+}
+
+// writeCountingLayer counts the writes to every connection it dials.
+type writeCountingLayer struct {
+	transport.Layer
+	writes uint32
+}
+
+func (l *writeCountingLayer) Dial(address string) (net.Conn, error) {
+	conn, err := l.Layer.Dial(address)
+	if err != nil {
+		return nil, err
+	}
+	return &writeCountingConn{Conn: conn, writes: &l.writes}, nil
+}
+
+type writeCountingConn struct {
+	net.Conn
+	writes *uint32
+}
+
+func (c *writeCountingConn) Write(b []byte) (int, error) {
+	atomic.AddUint32(c.writes, 1)
+	return c.Conn.Write(b)
+}
+
+func TestBatchWindow(t *testing.T) {
+	t.Parallel()
+
+	layer := &writeCountingLayer{Layer: transport.NewTCP()}
+	plugin := new(countingPlugin)
+
+	var nodes []*network.Network
+
+	for i := 0; i < 2; i++ {
+		// Writes bypass the write buffer of the sender, such that every write reaches the connection.
+		builder := network.NewBuilderWithOptions(
+			network.BatchWindow(50*time.Millisecond),
+			network.WriteBufferSize(16),
+		)
+		builder.SetAddress(network.FormatAddress("tcp", "localhost", uint16(network.GetRandomUnusedPort())))
+
+		if i == 0 {
+			builder.RegisterTransportLayer("tcp", layer)
+		} else {
+			builder.AddPlugin(plugin)
+		}
+
+		node, err := builder.Build()
+		assert.Nil(t, err)
+
+		go node.Listen()
+		node.BlockUntilListening()
+		defer node.Close()
+
+		nodes = append(nodes, node)
+	}
+
+	client, err := nodes[0].Client(nodes[1].Address)
+	assert.Nil(t, err)
+
+	before := atomic.LoadUint32(&layer.writes)
+
+	for i := 0; i < 100; i++ {
+		assert.Nil(t, client.Tell(context.Background(), &protobuf.TestMessage{Message: "batched"}))
+	}
+
+	for i := 0; i < 100 && atomic.LoadUint32(&plugin.count) < 100; i++ {
+		time.Sleep(20 * time.Millisecond)
+	}
+	assert.Equal(t, uint32(100), atomic.LoadUint32(&plugin.count), "expected every batched message to be dispatched")
+
+	writes := atomic.LoadUint32(&layer.writes) - before
+	assert.True(t, writes < 10, "expected fewer than 10 writes, got %d", writes)
+
+	// Wait for the receiver to identify the sender before closing.
+	for i := 0; i < 100; i++ {
+		if _, ok := nodes[1].GetPeer(nodes[0].ID.Id); ok {
+			break
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
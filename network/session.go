@@ -0,0 +1,404 @@
+package network
+
+import (
+	"bytes"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/binary"
+	"io"
+	"net"
+	"sync"
+
+	"github.com/perlin-network/noise/crypto"
+
+	"github.com/pkg/errors"
+	"golang.org/x/crypto/chacha20poly1305"
+	"golang.org/x/crypto/curve25519"
+	"golang.org/x/crypto/hkdf"
+)
+
+const (
+	// sessionKeyInfo binds derived session keys to their use within noise.
+	sessionKeyInfo = "noise session keys"
+
+	// maxSessionFrameSize is the maximum size of plaintext sealed within a session frame.
+	maxSessionFrameSize = 1 << 16
+
+	// sessionTagSize is the size of a ChaCha20-Poly1305 or AES-GCM authentication tag.
+	sessionTagSize = 16
+
+	// sessionNonceSize is the size of the nonce prepended to AES-GCM frames.
+	sessionNonceSize = 12
+)
+
+var (
+	errSessionHandshake   = errors.New("session: peer sent a malformed or unauthenticated ephemeral key")
+	errSessionDecrypt     = errors.New("session: failed to decrypt frame")
+	errSessionKeyMismatch = errors.New("network: peer identified with a different key than its session was established with")
+	errStaticKeyMismatch  = errors.New("network: peer identified with a different key than it performed its handshake with")
+)
+
+// sessionConn is a connection whose frames are encrypted with ChaCha20-Poly1305, or
+// AES-256-GCM, under session keys derived from an ephemeral X25519 key exchange, such that
+// compromising a nodes long-term keys does not compromise past sessions.
+//
+// The first frame sent by either side carries its ephemeral public key alongside a
+// signature of it under the sides long-term key pair.
+type sessionConn struct {
+	net.Conn
+
+	keys            *crypto.KeyPair
+	signaturePolicy crypto.SignaturePolicy
+	hashPolicy      crypto.HashPolicy
+
+	isClient bool
+
+	// aesGCM has frames be encrypted with AES-256-GCM rather than ChaCha20-Poly1305.
+	aesGCM bool
+
+	handshakeOnce sync.Once
+	handshakeErr  error
+
+	// remotePublicKey is the long-term public key the peer signed its ephemeral key with.
+	remotePublicKey []byte
+
+	sendKey, recvKey     []byte
+	sendNonce, recvNonce uint64
+
+	readMutex  sync.Mutex
+	writeMutex sync.Mutex
+
+	// buffer holds decrypted plaintext which has yet to be read.
+	buffer []byte
+}
+
+// newSessionConn wraps a connection with an encrypted session. Clients perform the key
+// exchange upon being wrapped, whereas servers perform it upon their first read.
+func (n *Network) newSessionConn(conn net.Conn, isClient, aesGCM bool) (*sessionConn, error) {
+	session := &sessionConn{
+		Conn:            conn,
+		keys:            n.sessionKeys(),
+		signaturePolicy: n.opts.signaturePolicy,
+		hashPolicy:      n.opts.hashPolicy,
+		isClient:        isClient,
+		aesGCM:          aesGCM,
+	}
+
+	if isClient {
+		if err := session.handshake(); err != nil {
+			return nil, err
+		}
+	}
+
+	return session, nil
+}
+
+// sessionKeys returns the key pair ephemeral keys are signed with. Returns nil should
+// forward secrecy be disabled.
+func (n *Network) sessionKeys() *crypto.KeyPair {
+	n.sessionMutex.RLock()
+	defer n.sessionMutex.RUnlock()
+
+	return n.opts.sessionKeys
+}
+
+// EnableForwardSecrecy encrypts all subsequent connections with session keys derived
+// from an ephemeral X25519 key exchange signed by a key pair, and reconnects to all
+// connected peers such that connections to them are upgraded. Messages previously sent
+// remain protected only by the connections they were sent over.
+//
+// Peers should have enabled forward secrecy before connections to them are upgraded.
+// Errors with the peers which could not be reconnected to.
+func (n *Network) EnableForwardSecrecy(keys *crypto.KeyPair) error {
+	n.sessionMutex.Lock()
+	n.opts.sessionKeys = keys
+	n.sessionMutex.Unlock()
+
+	var addresses []string
+
+	n.eachPeer(func(client *PeerClient) bool {
+		addresses = append(addresses, client.Address)
+		return true
+	})
+
+	var errs MultiError
+
+	for _, address := range addresses {
+		if value, ok := n.peers.Load(address); ok {
+			value.(*PeerClient).Close()
+		}
+
+		// Clients which have yet to identify their peer do not clean up their connection.
+		if state, ok := n.ConnectionState(address); ok {
+			state.conn.Close()
+		}
+		n.peers.Delete(address)
+		n.connections.Delete(address)
+
+		if _, err := n.Client(address); err != nil {
+			errs = append(errs, errors.Wrapf(err, "failed to upgrade connection to %s", address))
+		}
+	}
+
+	if len(errs) > 0 {
+		return errs
+	}
+	return nil
+}
+
+// Unwrap returns the underlying connection.
+func (c *sessionConn) Unwrap() net.Conn {
+	return c.Conn
+}
+
+// handshake exchanges signed ephemeral public keys with the peer, and derives the
+// session keys used to encrypt and decrypt frames.
+func (c *sessionConn) handshake() error {
+	c.handshakeOnce.Do(func() {
+		var private, public, remote [32]byte
+
+		if _, err := io.ReadFull(rand.Reader, private[:]); err != nil {
+			c.handshakeErr = err
+			return
+		}
+		curve25519.ScalarBaseMult(&public, &private)
+
+		signature, err := c.keys.Sign(c.signaturePolicy, c.hashPolicy, public[:])
+		if err != nil {
+			c.handshakeErr = err
+			return
+		}
+
+		frame := append(append(public[:], c.keys.PublicKey...), signature...)
+
+		// Clients send their ephemeral key first.
+		if c.isClient {
+			if c.handshakeErr = c.writeFrame(frame); c.handshakeErr != nil {
+				return
+			}
+		}
+
+		remoteFrame, err := c.readFrame()
+		if err != nil {
+			c.handshakeErr = err
+			return
+		}
+
+		keySize := c.signaturePolicy.PublicKeySize()
+		if len(remoteFrame) <= len(remote)+keySize {
+			c.handshakeErr = errSessionHandshake
+			return
+		}
+
+		copy(remote[:], remoteFrame)
+		remotePublicKey := remoteFrame[len(remote) : len(remote)+keySize]
+
+		if !crypto.Verify(c.signaturePolicy, c.hashPolicy, remotePublicKey, remote[:], remoteFrame[len(remote)+keySize:]) {
+			c.handshakeErr = errSessionHandshake
+			return
+		}
+
+		if !c.isClient {
+			if c.handshakeErr = c.writeFrame(frame); c.handshakeErr != nil {
+				return
+			}
+		}
+
+		var shared [32]byte
+		curve25519.ScalarMult(&shared, &private, &remote)
+
+		c.remotePublicKey = remotePublicKey
+		c.sendKey, c.recvKey, c.handshakeErr = deriveSessionKeys(shared[:], public[:], remote[:])
+	})
+
+	return c.handshakeErr
+}
+
+// deriveSessionKeys derives a pair of directional keys off of a shared secret via HKDF.
+// The side with the lesser ephemeral public key sends with the first key.
+func deriveSessionKeys(shared, local, remote []byte) ([]byte, []byte, error) {
+	lesser, greater := local, remote
+	if bytes.Compare(local, remote) > 0 {
+		lesser, greater = remote, local
+	}
+
+	salt := append(append([]byte(nil), lesser...), greater...)
+
+	keys := make([]byte, 2*chacha20poly1305.KeySize)
+	if _, err := io.ReadFull(hkdf.New(sha256.New, shared, salt, []byte(sessionKeyInfo)), keys); err != nil {
+		return nil, nil, err
+	}
+
+	first, second := keys[:chacha20poly1305.KeySize], keys[chacha20poly1305.KeySize:]
+	if bytes.Equal(lesser, local) {
+		return first, second, nil
+	}
+	return second, first, nil
+}
+
+// sealFrame encrypts a frame under a key and nonce.
+func sealFrame(key []byte, nonce uint64, plaintext []byte) []byte {
+	aead, _ := chacha20poly1305.New(key)
+	return aead.Seal(nil, frameNonce(nonce), plaintext, nil)
+}
+
+// openFrame decrypts a frame under a key and nonce.
+func openFrame(key []byte, nonce uint64, ciphertext []byte) ([]byte, error) {
+	aead, _ := chacha20poly1305.New(key)
+
+	plaintext, err := aead.Open(nil, frameNonce(nonce), ciphertext, nil)
+	if err != nil {
+		return nil, errSessionDecrypt
+	}
+	return plaintext, nil
+}
+
+func frameNonce(nonce uint64) []byte {
+	buffer := make([]byte, chacha20poly1305.NonceSize)
+	binary.BigEndian.PutUint64(buffer[4:], nonce)
+	return buffer
+}
+
+// sealAESGCMFrame encrypts a frame under a key and nonce with AES-256-GCM. The nonce is
+// prepended to the frame.
+func sealAESGCMFrame(key []byte, nonce uint64, plaintext []byte) []byte {
+	block, _ := aes.NewCipher(key)
+	aead, _ := cipher.NewGCM(block)
+
+	explicit := frameNonce(nonce)
+
+	frame := make([]byte, sessionNonceSize, sessionNonceSize+len(plaintext)+sessionTagSize)
+	copy(frame, explicit)
+
+	return aead.Seal(frame, explicit, plaintext, nil)
+}
+
+// openAESGCMFrame decrypts a frame under a key with AES-256-GCM, should the nonce it is
+// prepended with be the one expected.
+func openAESGCMFrame(key []byte, nonce uint64, frame []byte) ([]byte, error) {
+	if len(frame) < sessionNonceSize || !bytes.Equal(frame[:sessionNonceSize], frameNonce(nonce)) {
+		return nil, errSessionDecrypt
+	}
+
+	block, _ := aes.NewCipher(key)
+	aead, _ := cipher.NewGCM(block)
+
+	plaintext, err := aead.Open(nil, frame[:sessionNonceSize], frame[sessionNonceSize:], nil)
+	if err != nil {
+		return nil, errSessionDecrypt
+	}
+	return plaintext, nil
+}
+
+// seal encrypts a frame with the cipher of the session.
+func (c *sessionConn) seal(nonce uint64, plaintext []byte) []byte {
+	if c.aesGCM {
+		return sealAESGCMFrame(c.sendKey, nonce, plaintext)
+	}
+	return sealFrame(c.sendKey, nonce, plaintext)
+}
+
+// open decrypts a frame with the cipher of the session.
+func (c *sessionConn) open(nonce uint64, frame []byte) ([]byte, error) {
+	if c.aesGCM {
+		return openAESGCMFrame(c.recvKey, nonce, frame)
+	}
+	return openFrame(c.recvKey, nonce, frame)
+}
+
+// writeFrame writes a length-prefixed frame to the underlying connection.
+func (c *sessionConn) writeFrame(frame []byte) error {
+	buffer := make([]byte, 4+len(frame))
+	binary.BigEndian.PutUint32(buffer, uint32(len(frame)))
+	copy(buffer[4:], frame)
+
+	_, err := c.Conn.Write(buffer)
+	return err
+}
+
+// readFrame reads a length-prefixed frame from the underlying connection.
+func (c *sessionConn) readFrame() ([]byte, error) {
+	var size [4]byte
+	if _, err := io.ReadFull(c.Conn, size[:]); err != nil {
+		return nil, err
+	}
+
+	length := binary.BigEndian.Uint32(size[:])
+	if length > maxSessionFrameSize+sessionNonceSize+sessionTagSize {
+		return nil, errors.Errorf("session: frame has length of %d which is too large", length)
+	}
+
+	frame := make([]byte, length)
+	if _, err := io.ReadFull(c.Conn, frame); err != nil {
+		return nil, err
+	}
+
+	return frame, nil
+}
+
+// RemotePublicKey returns the long-term public key of the peer. Returns nil should
+// the key exchange not have completed.
+func (c *sessionConn) RemotePublicKey() []byte {
+	if err := c.handshake(); err != nil {
+		return nil
+	}
+	return c.remotePublicKey
+}
+
+// Read reads and decrypts frames from the connection.
+func (c *sessionConn) Read(out []byte) (int, error) {
+	if err := c.handshake(); err != nil {
+		return 0, err
+	}
+
+	c.readMutex.Lock()
+	defer c.readMutex.Unlock()
+
+	for len(c.buffer) == 0 {
+		frame, err := c.readFrame()
+		if err != nil {
+			return 0, err
+		}
+
+		c.buffer, err = c.open(c.recvNonce, frame)
+		if err != nil {
+			return 0, err
+		}
+		c.recvNonce++
+	}
+
+	n := copy(out, c.buffer)
+	c.buffer = c.buffer[n:]
+
+	return n, nil
+}
+
+// Write encrypts and writes data to the connection in frames.
+func (c *sessionConn) Write(data []byte) (int, error) {
+	if err := c.handshake(); err != nil {
+		return 0, err
+	}
+
+	c.writeMutex.Lock()
+	defer c.writeMutex.Unlock()
+
+	written := 0
+	for len(data) > 0 {
+		chunk := data
+		if len(chunk) > maxSessionFrameSize {
+			chunk = chunk[:maxSessionFrameSize]
+		}
+
+		if err := c.writeFrame(c.seal(c.sendNonce, chunk)); err != nil {
+			return written, err
+		}
+		c.sendNonce++
+
+		written += len(chunk)
+		data = data[len(chunk):]
+	}
+
+	return written, nil
+}
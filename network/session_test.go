@@ -0,0 +1,296 @@
+package network
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"net"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/perlin-network/noise/crypto/blake2b"
+	"github.com/perlin-network/noise/crypto/ed25519"
+	"github.com/perlin-network/noise/internal/protobuf"
+	"github.com/perlin-network/noise/types/opcode"
+
+	"github.com/gogo/protobuf/proto"
+	"github.com/stretchr/testify/assert"
+)
+
+// sessionPipe establishes an encrypted session between two nodes over a pipe.
+func sessionPipe(t *testing.T, client, server *Network) (*sessionConn, *sessionConn) {
+	clientConn, serverConn := net.Pipe()
+
+	serverSession, err := server.newSessionConn(serverConn, false, false)
+	assert.Nil(t, err)
+
+	// Servers perform the key exchange upon their first read.
+	go serverSession.handshake()
+
+	clientSession, err := client.newSessionConn(clientConn, true, false)
+	assert.Nil(t, err)
+
+	assert.Nil(t, serverSession.handshake())
+
+	return clientSession, serverSession
+}
+
+func newSessionNetwork() *Network {
+	keys := ed25519.RandomKeyPair()
+
+	return &Network{
+		keys: keys,
+		opts: options{
+			signaturePolicy: ed25519.New(),
+			hashPolicy:      blake2b.New(),
+			sessionKeys:     keys,
+		},
+	}
+}
+
+func TestSession(t *testing.T) {
+	t.Parallel()
+
+	client, server := newSessionNetwork(), newSessionNetwork()
+
+	clientSession, serverSession := sessionPipe(t, client, server)
+	defer clientSession.Close()
+	defer serverSession.Close()
+
+	assert.Equal(t, server.keys.PublicKey, clientSession.RemotePublicKey())
+	assert.Equal(t, client.keys.PublicKey, serverSession.RemotePublicKey())
+
+	assert.Equal(t, clientSession.sendKey, serverSession.recvKey)
+	assert.Equal(t, clientSession.recvKey, serverSession.sendKey)
+
+	payload := bytes.Repeat([]byte("payload"), maxSessionFrameSize)
+
+	go clientSession.Write(payload)
+
+	received := make([]byte, len(payload))
+	_, err := io.ReadFull(serverSession, received)
+	assert.Nil(t, err)
+	assert.Equal(t, payload, received)
+}
+
+func TestSessionForwardSecrecy(t *testing.T) {
+	t.Parallel()
+
+	client, server := newSessionNetwork(), newSessionNetwork()
+
+	first, _ := sessionPipe(t, client, server)
+	defer first.Close()
+
+	secondClient, secondServer := sessionPipe(t, client, server)
+	defer secondClient.Close()
+
+	// Sessions between the same peers should be keyed independently.
+	assert.NotEqual(t, first.sendKey, secondClient.sendKey)
+	assert.NotEqual(t, first.recvKey, secondClient.recvKey)
+
+	// Capture a frame off of the wire of the second session.
+	clientConn, serverConn := net.Pipe()
+	secondClient.Conn = clientConn
+
+	go secondClient.Write([]byte("secret payload"))
+
+	secondServer.Conn = serverConn
+	frame, err := secondServer.readFrame()
+	assert.Nil(t, err)
+
+	assert.False(t, bytes.Contains(frame, []byte("secret payload")), "expected frame to be encrypted")
+
+	// Keys of the first session should not decrypt frames of the second session.
+	for _, key := range [][]byte{first.sendKey, first.recvKey} {
+		_, err := openFrame(key, 0, frame)
+		assert.Equal(t, errSessionDecrypt, err)
+	}
+
+	plaintext, err := openFrame(secondServer.recvKey, 0, frame)
+	assert.Nil(t, err)
+	assert.Equal(t, []byte("secret payload"), plaintext)
+}
+
+// recordingConn records everything written to a connection.
+type recordingConn struct {
+	net.Conn
+
+	mutex   sync.Mutex
+	written bytes.Buffer
+}
+
+func (c *recordingConn) Write(b []byte) (int, error) {
+	c.mutex.Lock()
+	c.written.Write(b)
+	c.mutex.Unlock()
+
+	return c.Conn.Write(b)
+}
+
+func (c *recordingConn) Written() []byte {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	return append([]byte(nil), c.written.Bytes()...)
+}
+
+func TestSessionAESGCM(t *testing.T) {
+	t.Parallel()
+
+	client, server := newSessionNetwork(), newSessionNetwork()
+
+	clientConn, serverConn := net.Pipe()
+	recorder := &recordingConn{Conn: clientConn}
+
+	serverSession, err := server.newSessionConn(serverConn, false, true)
+	assert.Nil(t, err)
+	defer serverSession.Close()
+
+	go serverSession.handshake()
+
+	clientSession, err := client.newSessionConn(recorder, true, true)
+	assert.Nil(t, err)
+	defer clientSession.Close()
+
+	assert.Nil(t, serverSession.handshake())
+
+	handshake := len(recorder.Written())
+
+	payload, err := proto.Marshal(&protobuf.Message{
+		Opcode:  uint32(opcode.BytesCode),
+		Message: []byte("secret payload"),
+	})
+	assert.Nil(t, err)
+
+	go clientSession.Write(payload)
+
+	received := make([]byte, len(payload))
+	_, err = io.ReadFull(serverSession, received)
+	assert.Nil(t, err)
+	assert.Equal(t, payload, received)
+
+	// A frame holds its length, a counter-based nonce, the ciphertext, and a GCM tag.
+	frame := recorder.Written()[handshake:]
+	assert.Equal(t, 4+sessionNonceSize+len(payload)+sessionTagSize, len(frame))
+	assert.Equal(t, frameNonce(0), frame[4:4+sessionNonceSize])
+
+	ciphertext := frame[4+sessionNonceSize:]
+	assert.False(t, bytes.Contains(ciphertext, payload), "expected frame to be encrypted")
+	assert.False(t, bytes.Contains(ciphertext, []byte("secret payload")), "expected frame to be encrypted")
+	assert.NotNil(t, proto.Unmarshal(ciphertext, new(protobuf.Message)), "expected frame not to be a protobuf message")
+
+	// Frames are sealed with AES-GCM rather than ChaCha20-Poly1305, and bound to their nonce.
+	_, err = openFrame(serverSession.recvKey, 0, frame[4:])
+	assert.Equal(t, errSessionDecrypt, err)
+
+	_, err = openAESGCMFrame(serverSession.recvKey, 1, frame[4:])
+	assert.Equal(t, errSessionDecrypt, err)
+}
+
+func TestSessionUnauthenticated(t *testing.T) {
+	t.Parallel()
+
+	client, server := newSessionNetwork(), newSessionNetwork()
+
+	// Sign ephemeral keys with a key pair other than the one advertised.
+	client.opts.sessionKeys = ed25519.RandomKeyPair()
+	client.opts.sessionKeys.PublicKey = client.keys.PublicKey
+
+	clientConn, serverConn := net.Pipe()
+	defer clientConn.Close()
+
+	serverSession, err := server.newSessionConn(serverConn, false, false)
+	assert.Nil(t, err)
+
+	go client.newSessionConn(clientConn, true, false)
+
+	assert.Equal(t, errSessionHandshake, serverSession.handshake())
+	serverConn.Close()
+}
+
+// pingRecorderPlugin records the senders of received pings.
+type pingRecorderPlugin struct {
+	*Plugin
+	senders chan string
+}
+
+func (p *pingRecorderPlugin) Startup(net *Network) {
+	p.senders = make(chan string, 64)
+}
+
+func (p *pingRecorderPlugin) Receive(ctx *PluginContext) error {
+	if _, ok := ctx.Message().(*protobuf.Ping); ok {
+		p.senders <- ctx.Sender().Address
+	}
+	return nil
+}
+
+// sendPing pings a peer, and waits for it to receive the ping.
+func sendPing(t *testing.T, sender *Network, receiver *pingRecorderPlugin, address string) {
+	t.Helper()
+
+	client, err := sender.Client(address)
+	if assert.Nil(t, err) {
+		assert.Nil(t, client.Tell(context.Background(), &protobuf.Ping{}))
+	}
+
+	select {
+	case from := <-receiver.senders:
+		assert.Equal(t, sender.Address, from)
+	case <-time.After(3 * time.Second):
+		t.Fatalf("expected %s to receive a ping from %s", address, sender.Address)
+	}
+}
+
+func TestEnableForwardSecrecy(t *testing.T) {
+	t.Parallel()
+
+	var nodes []*Network
+	var plugins []*pingRecorderPlugin
+
+	for i := 0; i < 2; i++ {
+		plugin := new(pingRecorderPlugin)
+
+		builder := NewBuilder()
+		builder.SetAddress(FormatAddress("tcp", "localhost", uint16(GetRandomUnusedPort())))
+		builder.AddPlugin(plugin)
+
+		node, err := builder.Build()
+		assert.Nil(t, err)
+
+		go node.Listen()
+		defer node.Close()
+
+		node.BlockUntilListening()
+
+		nodes = append(nodes, node)
+		plugins = append(plugins, plugin)
+	}
+
+	a, b := nodes[0], nodes[1]
+
+	sendPing(t, a, plugins[1], b.Address)
+
+	state, ok := a.ConnectionState(b.Address)
+	if assert.True(t, ok) {
+		assert.IsType(t, &net.TCPConn{}, state.conn)
+	}
+
+	// Connections may not be upgraded until the peer has enabled forward secrecy.
+	assert.NotNil(t, a.EnableForwardSecrecy(a.keys))
+	assert.False(t, a.ConnectionStateExists(b.Address))
+
+	assert.Nil(t, b.EnableForwardSecrecy(b.keys))
+
+	for _, pair := range [][2]int{{1, 0}, {0, 1}} {
+		sender, receiver := nodes[pair[0]], nodes[pair[1]]
+
+		sendPing(t, sender, plugins[pair[1]], receiver.Address)
+
+		state, ok := sender.ConnectionState(receiver.Address)
+		if assert.True(t, ok) {
+			assert.IsType(t, &sessionConn{}, state.conn, "expected connection to be upgraded")
+		}
+	}
+}
@@ -0,0 +1,68 @@
+package network
+
+import (
+	"golang.org/x/time/rate"
+)
+
+// TrafficShaper limits the rate at which messages are received from and sent to all peers
+// combined, such that a node which receives more messages than it can process applies
+// backpressure to its peers.
+type TrafficShaper struct {
+	// GlobalInboundRateLimit is the number of messages per second read from all peers
+	// combined. Unlimited if zero.
+	GlobalInboundRateLimit float64
+
+	// GlobalOutboundRateLimit is the number of messages per second written to all peers
+	// combined. Unlimited if zero.
+	GlobalOutboundRateLimit float64
+
+	// Burst is the number of messages which may be read or written at once in excess of
+	// the rate limits. Defaults to a single message if zero.
+	Burst int
+}
+
+// limiters returns the token buckets limiting inbound and outbound messages, either of
+// which is nil should its rate be unlimited.
+func (s TrafficShaper) limiters() (inbound, outbound *rate.Limiter) {
+	burst := s.Burst
+	if burst <= 0 {
+		burst = 1
+	}
+
+	if s.GlobalInboundRateLimit > 0 {
+		inbound = rate.NewLimiter(rate.Limit(s.GlobalInboundRateLimit), burst)
+	}
+
+	if s.GlobalOutboundRateLimit > 0 {
+		outbound = rate.NewLimiter(rate.Limit(s.GlobalOutboundRateLimit), burst)
+	}
+
+	return inbound, outbound
+}
+
+// shape blocks until a limiter allows a number of messages through as timed by the networks
+// clock, or the network is closed. Messages are always allowed through should the limiter
+// be nil.
+func (n *Network) shape(limiter *rate.Limiter, messages int) {
+	if limiter == nil {
+		return
+	}
+
+	// Messages are reserved one by one, as a batch may exceed the burst of the limiter.
+	for i := 0; i < messages; i++ {
+		now := n.opts.clock.Now()
+		reservation := limiter.ReserveN(now, 1)
+
+		delay := reservation.DelayFrom(now)
+		if delay == 0 {
+			continue
+		}
+
+		select {
+		case <-n.opts.clock.After(delay):
+		case <-n.kill:
+			reservation.CancelAt(n.opts.clock.Now())
+			return
+		}
+	}
+}
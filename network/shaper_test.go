@@ -0,0 +1,140 @@
+package network_test
+
+import (
+	"context"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/perlin-network/noise/clock"
+	"github.com/perlin-network/noise/internal/test/protobuf"
+	"github.com/perlin-network/noise/network"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// countingPlugin counts the number of *protobuf.TestMessage it receives.
+type countingPlugin struct {
+	*network.Plugin
+	count uint32
+}
+
+func (p *countingPlugin) Receive(ctx *network.PluginContext) error {
+	if _, ok := ctx.Message().(*protobuf.TestMessage); ok {
+		atomic.AddUint32(&p.count, 1)
+	}
+	return nil
+}
+
+func TestTrafficShaper(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		name     string
+		sender   network.TrafficShaper
+		receiver network.TrafficShaper
+	}{
+		{"inbound", network.TrafficShaper{}, network.TrafficShaper{GlobalInboundRateLimit: 100}},
+		{"outbound", network.TrafficShaper{GlobalOutboundRateLimit: 100}, network.TrafficShaper{}},
+	}
+
+	for _, tt := range testCases {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			plugin := new(countingPlugin)
+
+			var nodes []*network.Network
+
+			for i, shaper := range []network.TrafficShaper{tt.sender, tt.receiver} {
+				builder := network.NewBuilderWithOptions(
+					network.WithTrafficShaper(shaper),
+					network.SendQueueDepth(1000),
+				)
+				builder.SetAddress(network.FormatAddress("tcp", "localhost", uint16(network.GetRandomUnusedPort())))
+				if i == 1 {
+					builder.AddPlugin(plugin)
+				}
+
+				node, err := builder.Build()
+				assert.Nil(t, err)
+
+				go node.Listen()
+				node.BlockUntilListening()
+				defer node.Close()
+
+				nodes = append(nodes, node)
+			}
+
+			sender, receiver := nodes[0], nodes[1]
+
+			client, err := sender.Client(receiver.Address)
+			assert.Nil(t, err)
+
+			start := time.Now()
+
+			for i := 0; i < 1000; i++ {
+				assert.Nil(t, client.Tell(context.Background(), &protobuf.TestMessage{Message: "shaped"}))
+			}
+
+			assert.True(t, time.Since(start) < time.Second, "expected messages to be sent in under a second")
+
+			time.Sleep(time.Second - time.Since(start))
+
+			processed := atomic.LoadUint32(&plugin.count)
+			assert.True(t, processed > 0, "expected messages to be processed")
+			assert.True(t, processed < 150, "expected fewer than 150 messages to be processed, got %d", processed)
+		})
+	}
+}
+
+func TestTrafficShaperClock(t *testing.T) {
+	t.Parallel()
+
+	fake := clock.NewFakeClock(time.Now())
+	plugin := new(countingPlugin)
+
+	var nodes []*network.Network
+
+	for i := 0; i < 2; i++ {
+		builder := network.NewBuilder()
+		if i == 0 {
+			builder = network.NewBuilderWithOptions(
+				network.WithClock(fake),
+				network.WithTrafficShaper(network.TrafficShaper{GlobalOutboundRateLimit: 1}),
+			)
+		} else {
+			builder.AddPlugin(plugin)
+		}
+		builder.SetAddress(network.FormatAddress("tcp", "localhost", uint16(network.GetRandomUnusedPort())))
+
+		node, err := builder.Build()
+		assert.Nil(t, err)
+
+		go node.Listen()
+		node.BlockUntilListening()
+		defer node.Close()
+
+		nodes = append(nodes, node)
+	}
+
+	client, err := nodes[0].Client(nodes[1].Address)
+	assert.Nil(t, err)
+
+	for i := 0; i < 3; i++ {
+		assert.Nil(t, client.Tell(context.Background(), &protobuf.TestMessage{Message: "shaped"}))
+	}
+
+	time.Sleep(300 * time.Millisecond)
+	assert.Equal(t, uint32(1), atomic.LoadUint32(&plugin.count), "expected messages beyond the burst to wait for the clock to advance")
+
+	deadline := time.Now().Add(3 * time.Second)
+	for atomic.LoadUint32(&plugin.count) < 3 {
+		if time.Now().After(deadline) {
+			t.Fatalf("expected every message to be sent as the clock advances, got %d", atomic.LoadUint32(&plugin.count))
+		}
+		fake.Advance(time.Second)
+		time.Sleep(50 * time.Millisecond)
+	}
+}
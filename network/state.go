@@ -0,0 +1,79 @@
+package network
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	"github.com/pkg/errors"
+)
+
+// statePath returns the path of the file the state of a plugin is persisted to, named
+// after the plugins package (e.g. "discovery.state").
+func (n *Network) statePath(plugin PluginInterface) string {
+	return filepath.Join(n.opts.stateDir, pluginConfigKey(plugin)+".state")
+}
+
+// saveState serializes the state of every StatefulPlugin into the state directory, should
+// one be set. State files are replaced atomically, such that a node crashing mid-write
+// does not lose its prior state.
+func (n *Network) saveState() error {
+	if n.opts.stateDir == "" {
+		return nil
+	}
+
+	if err := os.MkdirAll(n.opts.stateDir, 0700); err != nil {
+		return errors.Wrap(err, "network: failed to create state directory")
+	}
+
+	var err error
+
+	n.plugins.Each(func(plugin PluginInterface) {
+		stateful, ok := plugin.(StatefulPlugin)
+		if !ok || err != nil {
+			return
+		}
+
+		data, e := stateful.Serialize()
+		if e != nil {
+			err = errors.Wrapf(e, "network: failed to serialize state of plugin %T", plugin)
+			return
+		}
+
+		path := n.statePath(plugin)
+
+		if e := ioutil.WriteFile(path+".tmp", data, 0600); e != nil {
+			err = errors.Wrapf(e, "network: failed to write state of plugin %T", plugin)
+			return
+		}
+
+		if e := os.Rename(path+".tmp", path); e != nil {
+			err = errors.Wrapf(e, "network: failed to write state of plugin %T", plugin)
+		}
+	})
+
+	return err
+}
+
+// loadState restores the state of a StatefulPlugin from the state directory, should a
+// state directory be set and hold a state file for the plugin.
+func (n *Network) loadState(plugin PluginInterface) error {
+	stateful, ok := plugin.(StatefulPlugin)
+	if !ok || n.opts.stateDir == "" {
+		return nil
+	}
+
+	data, err := ioutil.ReadFile(n.statePath(plugin))
+	if os.IsNotExist(err) {
+		return nil
+	}
+	if err != nil {
+		return errors.Wrapf(err, "network: failed to read state of plugin %T", plugin)
+	}
+
+	if err := stateful.Deserialize(data); err != nil {
+		return errors.Wrapf(err, "network: failed to deserialize state of plugin %T", plugin)
+	}
+
+	return nil
+}
@@ -6,32 +6,77 @@ import (
 	"io"
 	"net"
 	"sync"
+	"sync/atomic"
 
 	"github.com/perlin-network/noise/crypto"
 	"github.com/perlin-network/noise/internal/protobuf"
-	"github.com/perlin-network/noise/log"
 
-	"github.com/gogo/protobuf/proto"
 	"github.com/pkg/errors"
 )
 
-var errEmptyMsg = errors.New("received an empty message from a peer")
+var (
+	errEmptyMsg    = errors.New("received an empty message from a peer")
+	errReplayedMsg = errors.New("received a replayed message from a peer")
+)
 
 // sendMessage marshals, signs and sends a message over a stream.
 func (n *Network) sendMessage(w io.Writer, message *protobuf.Message, writerMutex *sync.Mutex) error {
-	bytes, err := proto.Marshal(message)
-	if err != nil {
-		return errors.Wrap(err, "failed to marshal message")
+	return n.sendMessages(w, []*protobuf.Message{message}, writerMutex)
+}
+
+// maxPooledWriteSize is the size beyond which serialization buffers are not returned to
+// the pool.
+const maxPooledWriteSize = 64 * 1024
+
+// writeBuffers pools the buffers outgoing messages are serialized into. Buffers are
+// returned to the pool once written, as writers may not retain them.
+var writeBuffers = sync.Pool{
+	New: func() interface{} {
+		return new(writeBuffer)
+	},
+}
+
+type writeBuffer struct {
+	data []byte
+}
+
+// sendMessages marshals and sends a batch of messages over a stream with a single write.
+// Each message is framed individually, such that the receiver reads them one at a time.
+func (n *Network) sendMessages(w io.Writer, messages []*protobuf.Message, writerMutex *sync.Mutex) error {
+	wb := writeBuffers.Get().(*writeBuffer)
+	defer func() {
+		if cap(wb.data) <= maxPooledWriteSize {
+			writeBuffers.Put(wb)
+		}
+	}()
+
+	buffer := wb.data[:0]
+
+	for _, message := range messages {
+		size := message.Size()
+
+		offset := len(buffer)
+		if cap(buffer)-offset < 4+size {
+			grown := make([]byte, offset, 2*cap(buffer)+4+size)
+			copy(grown, buffer)
+			buffer = grown
+		}
+		buffer = buffer[:offset+4+size]
+
+		// Serialize size.
+		binary.BigEndian.PutUint32(buffer[offset:], uint32(size))
+
+		if _, err := message.MarshalTo(buffer[offset+4:]); err != nil {
+			return errors.Wrap(err, "failed to marshal message")
+		}
 	}
 
-	// Serialize size.
-	buffer := make([]byte, 4)
-	binary.BigEndian.PutUint32(buffer, uint32(len(bytes)))
+	wb.data = buffer
 
-	buffer = append(buffer, bytes...)
 	totalSize := len(buffer)
 
 	// Write until all bytes have been written.
+	var err error
 	bytesWritten, totalBytesWritten := 0, 0
 
 	writerMutex.Lock()
@@ -47,7 +92,7 @@ func (n *Network) sendMessage(w io.Writer, message *protobuf.Message, writerMute
 	for totalBytesWritten < len(buffer) && err == nil {
 		bytesWritten, err = w.Write(buffer[totalBytesWritten:])
 		if err != nil {
-			log.Error().Err(err).Msg("stream: failed to write entire buffer")
+			n.connLog.Error().Err(err).Msg("stream: failed to write entire buffer")
 		}
 		totalBytesWritten += bytesWritten
 	}
@@ -61,35 +106,83 @@ func (n *Network) sendMessage(w io.Writer, message *protobuf.Message, writerMute
 	return nil
 }
 
-// receiveMessage reads, unmarshals and verifies a message from a net.Conn.
+// maxPooledFrameSize is the size beyond which frame buffers are not returned to the pool,
+// such that a few large messages do not pin memory.
+const maxPooledFrameSize = 64 * 1024
+
+// frameBuffer holds the bytes of a single frame read off of a connection.
+type frameBuffer struct {
+	header [4]byte
+	data   []byte
+}
+
+var frameBuffers = sync.Pool{
+	New: func() interface{} {
+		return new(frameBuffer)
+	},
+}
+
+// receivedMessage allocates a message together with its sender.
+type receivedMessage struct {
+	msg    protobuf.Message
+	sender protobuf.ID
+}
+
+// receiveMessage reads, unmarshals and verifies a message from a net.Conn. Frames are read
+// into buffers borrowed from a pool, as unmarshaling copies out every byte it retains.
 func (n *Network) receiveMessage(conn net.Conn) (*protobuf.Message, error) {
 	var err error
 
-	// Read until all header bytes have been read.
-	buffer := make([]byte, 4)
+	frame := frameBuffers.Get().(*frameBuffer)
+	defer func() {
+		if cap(frame.data) <= maxPooledFrameSize {
+			frameBuffers.Put(frame)
+		}
+	}()
 
+	// Read until all header bytes have been read.
 	bytesRead, totalBytesRead := 0, 0
 
 	for totalBytesRead < 4 && err == nil {
-		bytesRead, err = conn.Read(buffer[totalBytesRead:])
+		bytesRead, err = conn.Read(frame.header[totalBytesRead:])
 		totalBytesRead += bytesRead
 	}
 
+	// Pooled headers hold the size of prior frames. Connections closed in between frames
+	// are not mistaken for failed reads.
+	if totalBytesRead == 0 {
+		return nil, errEmptyMsg
+	}
+
+	if totalBytesRead < 4 {
+		return nil, errors.Wrap(err, "failed to read message header")
+	}
+
 	// Decode message size.
-	size := binary.BigEndian.Uint32(buffer)
+	size := binary.BigEndian.Uint32(frame.header[:])
 
 	if size == 0 {
 		return nil, errEmptyMsg
 	}
 
-	// Message size at most is limited to 4MB. If a big message need be sent,
-	// consider partitioning to message into chunks of 4MB.
-	if size > 4e+6 {
+	if size == ProbeFrameMarker && err == nil {
+		return nil, handleProbeFrame(conn)
+	}
+
+	if size == challengeFrameMarker && err == nil {
+		return nil, n.handleChallengeFrame(conn)
+	}
+
+	// Message size is limited to prevent peers from exhausting memory.
+	if int64(size) > int64(n.MaxMessageSizeBytes()) {
 		return nil, errors.Errorf("message has length of %d which is either broken or too large", size)
 	}
 
 	// Read until all message bytes have been read.
-	buffer = make([]byte, size)
+	if cap(frame.data) < int(size) {
+		frame.data = make([]byte, size)
+	}
+	buffer := frame.data[:size]
 
 	bytesRead, totalBytesRead = 0, 0
 
@@ -98,10 +191,18 @@ func (n *Network) receiveMessage(conn net.Conn) (*protobuf.Message, error) {
 		totalBytesRead += bytesRead
 	}
 
+	// Pooled buffers hold the bytes of prior frames, which must not be mistaken for the
+	// remainder of a frame which was cut short.
+	if totalBytesRead < int(size) {
+		return nil, errors.Wrap(err, "failed to read message")
+	}
+
 	// Deserialize message.
-	msg := new(protobuf.Message)
+	received := new(receivedMessage)
+	msg := &received.msg
+	msg.Sender = &received.sender
 
-	err = proto.Unmarshal(buffer, msg)
+	err = msg.Unmarshal(buffer)
 	if err != nil {
 		return nil, errors.Wrap(err, "failed to unmarshal message")
 	}
@@ -111,16 +212,28 @@ func (n *Network) receiveMessage(conn net.Conn) (*protobuf.Message, error) {
 		return nil, errors.New("received an invalid message (either no opcode, no sender, or no signature) from a peer")
 	}
 
+	// Messages are signed prior to being compressed.
+	if err := n.decodeMessage(msg); err != nil {
+		return nil, err
+	}
+
 	// Verify signature of message.
 	if msg.Signature != nil && !crypto.Verify(
 		n.opts.signaturePolicy,
 		n.opts.hashPolicy,
 		msg.Sender.PublicKey,
-		SerializeMessage(msg.Sender, msg.Message),
+		serializeSignedMessage(msg),
 		msg.Signature,
 	) {
 		return nil, errors.New("received message had an malformed signature")
 	}
 
+	// Drop messages which have already been received from the sender. Only messages with
+	// a verified signature are recorded in the replay window of the sender.
+	if !n.acceptSequence(msg) {
+		atomic.AddUint64(&n.messagesDropped, 1)
+		return nil, errReplayedMsg
+	}
+
 	return msg, nil
 }
@@ -0,0 +1,252 @@
+package network
+
+import (
+	"bytes"
+	"context"
+	"io/ioutil"
+	"net"
+	"sync"
+	"testing"
+
+	"github.com/perlin-network/noise/internal/protobuf"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// countingWriter counts the writes made to a buffer.
+type countingWriter struct {
+	bytes.Buffer
+	writes int
+}
+
+func (w *countingWriter) Write(p []byte) (int, error) {
+	w.writes++
+	return w.Buffer.Write(p)
+}
+
+func TestSendMessages(t *testing.T) {
+	t.Parallel()
+
+	n, err := NewBuilder().Build()
+	assert.Nil(t, err)
+
+	var messages []*protobuf.Message
+	for i := 0; i < 10; i++ {
+		message, err := n.PrepareMessage(context.Background(), &protobuf.Ping{})
+		assert.Nil(t, err)
+		message.MessageNonce = uint64(i + 1)
+
+		messages = append(messages, message)
+	}
+
+	w := new(countingWriter)
+	assert.Nil(t, n.sendMessages(w, messages, new(sync.Mutex)))
+	assert.Equal(t, 1, w.writes, "expected batch to be sent with a single write")
+
+	// Messages of a batch are read back one at a time.
+	client, server := net.Pipe()
+	defer client.Close()
+	defer server.Close()
+
+	go client.Write(w.Bytes())
+
+	for _, expected := range messages {
+		received, err := n.receiveMessage(server)
+		assert.Nil(t, err)
+		assert.Equal(t, expected.MessageNonce, received.MessageNonce)
+	}
+}
+
+func TestMaxMessageSizeBytes(t *testing.T) {
+	t.Parallel()
+
+	n, err := NewBuilder().Build()
+	assert.Nil(t, err)
+	assert.Equal(t, defaultMaxMessageSize, n.MaxMessageSizeBytes())
+
+	message, err := n.PrepareMessage(context.Background(), &protobuf.Bytes{Data: make([]byte, 1024)})
+	assert.Nil(t, err)
+
+	w := new(bytes.Buffer)
+	assert.Nil(t, n.sendMessage(w, message, new(sync.Mutex)))
+
+	receive := func() error {
+		client, server := net.Pipe()
+		defer client.Close()
+		defer server.Close()
+
+		go client.Write(w.Bytes())
+
+		_, err := n.receiveMessage(server)
+		return err
+	}
+
+	assert.Nil(t, receive())
+
+	n.SetMaxMessageSizeBytes(512)
+	assert.Equal(t, 512, n.MaxMessageSizeBytes())
+	assert.NotNil(t, receive(), "expected message larger than the limit to be rejected")
+
+	n.SetMaxMessageSizeBytes(0)
+	assert.Equal(t, defaultMaxMessageSize, n.MaxMessageSizeBytes(), "expected limit to reset to its default")
+}
+
+// replayingConn serves the same frame over and over.
+type replayingConn struct {
+	net.Conn
+
+	frame  []byte
+	reader *bytes.Reader
+}
+
+func (c *replayingConn) Read(b []byte) (int, error) {
+	if c.reader.Len() == 0 {
+		c.reader.Reset(c.frame)
+	}
+	return c.reader.Read(b)
+}
+
+func TestReceiveMessageAllocs(t *testing.T) {
+	n, err := NewBuilder().Build()
+	assert.Nil(t, err)
+
+	message, err := n.PrepareMessage(context.Background(), &protobuf.Bytes{Data: make([]byte, 1024)})
+	assert.Nil(t, err)
+
+	w := new(bytes.Buffer)
+	assert.Nil(t, n.sendMessage(w, message, new(sync.Mutex)))
+
+	conn := &replayingConn{frame: w.Bytes(), reader: bytes.NewReader(w.Bytes())}
+
+	_, err = n.receiveMessage(conn)
+	assert.Nil(t, err)
+
+	allocs := testing.AllocsPerRun(100, func() {
+		n.receiveMessage(conn)
+	})
+
+	// Frames were read into freshly allocated buffers, and replay windows were looked up
+	// through a sync.Map, for 11 allocations per message received.
+	assert.True(t, allocs <= 11/2, "expected at most %d allocations per message, got %v", 11/2, allocs)
+}
+
+func TestReceiveTruncatedMessage(t *testing.T) {
+	t.Parallel()
+
+	n, err := NewBuilder().Build()
+	assert.Nil(t, err)
+
+	message, err := n.PrepareMessage(context.Background(), &protobuf.Bytes{Data: make([]byte, 1024)})
+	assert.Nil(t, err)
+
+	w := new(bytes.Buffer)
+	assert.Nil(t, n.sendMessage(w, message, new(sync.Mutex)))
+
+	// Fill a pooled buffer with a complete frame.
+	_, err = n.receiveMessage(&replayingConn{frame: w.Bytes(), reader: bytes.NewReader(w.Bytes())})
+	assert.Nil(t, err)
+
+	client, server := net.Pipe()
+	defer server.Close()
+
+	go func() {
+		client.Write(w.Bytes()[:w.Len()/2])
+		client.Close()
+	}()
+
+	_, err = n.receiveMessage(server)
+	assert.NotNil(t, err, "expected a message cut short to be rejected")
+}
+
+func TestReceiveMessageAfterClose(t *testing.T) {
+	t.Parallel()
+
+	n, err := NewBuilder().Build()
+	assert.Nil(t, err)
+
+	message, err := n.PrepareMessage(context.Background(), &protobuf.Bytes{Data: make([]byte, 1024)})
+	assert.Nil(t, err)
+
+	w := new(bytes.Buffer)
+	assert.Nil(t, n.sendMessage(w, message, new(sync.Mutex)))
+
+	// Fill a pooled header with the size of a complete frame.
+	_, err = n.receiveMessage(&replayingConn{frame: w.Bytes(), reader: bytes.NewReader(w.Bytes())})
+	assert.Nil(t, err)
+
+	for _, written := range []int{0, 1, 3} {
+		client, server := net.Pipe()
+
+		go func(written int) {
+			client.Write(w.Bytes()[:written])
+			client.Close()
+		}(written)
+
+		_, err = n.receiveMessage(server)
+		server.Close()
+
+		if written == 0 {
+			assert.Equal(t, errEmptyMsg, err, "expected a connection closed in between frames to be reported as such")
+		} else {
+			assert.NotNil(t, err, "expected a header cut short to be rejected")
+			assert.NotEqual(t, errEmptyMsg, err)
+		}
+	}
+}
+
+func BenchmarkReceiveMessage(b *testing.B) {
+	n, err := NewBuilder().Build()
+	assert.Nil(b, err)
+
+	message, err := n.PrepareMessage(context.Background(), &protobuf.Bytes{Data: make([]byte, 1024)})
+	assert.Nil(b, err)
+
+	w := new(bytes.Buffer)
+	assert.Nil(b, n.sendMessage(w, message, new(sync.Mutex)))
+
+	conn := &replayingConn{frame: w.Bytes(), reader: bytes.NewReader(w.Bytes())}
+
+	b.ReportAllocs()
+	b.ResetTimer()
+
+	for i := 0; i < b.N; i++ {
+		n.receiveMessage(conn)
+	}
+}
+
+func TestSendMessageAllocs(t *testing.T) {
+	n, err := NewBuilder().Build()
+	assert.Nil(t, err)
+
+	message, err := n.PrepareMessage(context.Background(), &protobuf.Bytes{Data: make([]byte, 1024)})
+	assert.Nil(t, err)
+
+	writerMutex := new(sync.Mutex)
+
+	allocs := testing.AllocsPerRun(100, func() {
+		n.sendMessage(ioutil.Discard, message, writerMutex)
+	})
+
+	// Messages were marshaled into a freshly allocated buffer before being copied into
+	// their frame, for 3 allocations per message sent.
+	assert.True(t, allocs < 3, "expected fewer than 3 allocations per message, got %v", allocs)
+}
+
+func BenchmarkSendMessage(b *testing.B) {
+	n, err := NewBuilder().Build()
+	assert.Nil(b, err)
+
+	message, err := n.PrepareMessage(context.Background(), &protobuf.Bytes{Data: make([]byte, 1024)})
+	assert.Nil(b, err)
+
+	b.ReportAllocs()
+	b.ResetTimer()
+
+	b.RunParallel(func(pb *testing.PB) {
+		writerMutex := new(sync.Mutex)
+
+		for pb.Next() {
+			n.sendMessage(ioutil.Discard, message, writerMutex)
+		}
+	})
+}
@@ -0,0 +1,100 @@
+package network
+
+import (
+	"sync/atomic"
+	"time"
+)
+
+// TopologySnapshot is a point-in-time view of the peers a network is connected to.
+// It is safe to marshal to JSON. Times are in UTC.
+type TopologySnapshot struct {
+	// PeerCount is the number of peers connected to the network.
+	PeerCount int `json:"peer_count"`
+
+	// BucketDistribution is the number of peers held within each bucket of the routing
+	// table, indexed by bucket ID, including the node itself. Empty should no plugin implement BucketReporter.
+	BucketDistribution []int `json:"bucket_distribution"`
+
+	// PeerLatencies is the smoothed round-trip time to each connected peer by address,
+	// as measured by ping probes. Zero for peers which have not been probed yet.
+	PeerLatencies map[string]time.Duration `json:"peer_latencies"`
+
+	// PeerConnectedAt is when the connection to each connected peer was established, by address.
+	PeerConnectedAt map[string]time.Time `json:"peer_connected_at"`
+
+	// UptimeSeconds is how long ago the network was built.
+	UptimeSeconds int64 `json:"uptime_seconds"`
+
+	// StartTime is when the network first started listening for peers.
+	StartTime time.Time `json:"start_time"`
+}
+
+// TopologySnapshot returns a snapshot of the peers the network is connected to.
+func (n *Network) TopologySnapshot() TopologySnapshot {
+	snapshot := TopologySnapshot{
+		BucketDistribution: []int{},
+		PeerLatencies:      make(map[string]time.Duration),
+		PeerConnectedAt:    make(map[string]time.Time),
+		UptimeSeconds:      int64(n.opts.clock.Now().Sub(n.createdAt) / time.Second),
+		StartTime:          n.StartTime().UTC(),
+	}
+
+	n.eachPeer(func(client *PeerClient) bool {
+		rtt, _ := client.RTT()
+
+		snapshot.PeerCount++
+		snapshot.PeerLatencies[client.Address] = rtt
+		snapshot.PeerConnectedAt[client.Address] = client.ConnectedAt().UTC()
+
+		return true
+	})
+
+	n.plugins.Each(func(plugin PluginInterface) {
+		if reporter, ok := plugin.(BucketReporter); ok && len(snapshot.BucketDistribution) == 0 {
+			if distribution := reporter.BucketDistribution(); distribution != nil {
+				snapshot.BucketDistribution = distribution
+			}
+		}
+	})
+
+	return snapshot
+}
+
+// PeerCount returns the number of peers held within the routing table of the first plugin
+// implementing BucketReporter, excluding the node itself. Zero should no plugin implement
+// BucketReporter.
+func (n *Network) PeerCount() int {
+	var distribution []int
+
+	n.plugins.Each(func(plugin PluginInterface) {
+		if reporter, ok := plugin.(BucketReporter); ok && distribution == nil {
+			distribution = reporter.BucketDistribution()
+		}
+	})
+
+	count := 0
+	for _, size := range distribution {
+		count += size
+	}
+
+	// The routing table holds the node itself.
+	if count > 0 {
+		count--
+	}
+
+	return count
+}
+
+// ConnectedPeerCount returns the number of peers the network has an open client for.
+func (n *Network) ConnectedPeerCount() int {
+	count := 0
+
+	n.eachPeer(func(client *PeerClient) bool {
+		if atomic.LoadUint32(&client.closed) == 0 {
+			count++
+		}
+		return true
+	})
+
+	return count
+}
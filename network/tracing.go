@@ -0,0 +1,87 @@
+package network
+
+import (
+	"context"
+	"reflect"
+
+	"github.com/perlin-network/noise/internal/protobuf"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const (
+	// tracerName is the instrumentation name spans of the network are recorded under.
+	tracerName = "github.com/perlin-network/noise/network"
+
+	spanSend          = "noise.message.send"
+	spanPluginReceive = "noise.plugin.receive"
+)
+
+// traceContextPropagator propagates spans through message headers in the W3C Trace Context format.
+var traceContextPropagator = propagation.TraceContext{}
+
+// tracer returns the tracer spans of the network are recorded with, or nil should tracing be disabled.
+func (n *Network) tracer() trace.Tracer {
+	if n.opts.tracerProvider == nil {
+		return nil
+	}
+	return n.opts.tracerProvider.Tracer(tracerName)
+}
+
+// injectTraceContext records a span for a message being sent as a child of the span within ctx,
+// and injects the context of said span into the messages headers.
+func (n *Network) injectTraceContext(ctx context.Context, msg *protobuf.Message) {
+	tracer := n.tracer()
+	if tracer == nil {
+		return
+	}
+
+	ctx, span := tracer.Start(ctx, spanSend,
+		trace.WithSpanKind(trace.SpanKindProducer),
+		trace.WithAttributes(attribute.Int64("noise.opcode", int64(msg.Opcode))),
+	)
+	defer span.End()
+
+	carrier := propagation.MapCarrier{}
+	traceContextPropagator.Inject(ctx, carrier)
+
+	if len(carrier) > 0 {
+		msg.TraceContext = carrier
+	}
+}
+
+// extractTraceContext returns a context carrying the span a message was sent within.
+func (n *Network) extractTraceContext(msg *protobuf.Message) context.Context {
+	ctx := context.Background()
+	if n.tracer() == nil || len(msg.TraceContext) == 0 {
+		return ctx
+	}
+
+	return traceContextPropagator.Extract(ctx, propagation.MapCarrier(msg.TraceContext))
+}
+
+// startReceiveSpan records a span for a plugin receiving a message as a child of the span
+// the message was sent within. The returned function ends the span.
+func (n *Network) startReceiveSpan(info *PluginInfo, pctx *PluginContext) func(err error) {
+	tracer := n.tracer()
+	if tracer == nil {
+		return func(error) {}
+	}
+
+	ctx, span := tracer.Start(pctx.traceContext, spanPluginReceive,
+		trace.WithSpanKind(trace.SpanKindConsumer),
+		trace.WithAttributes(attribute.String("noise.plugin", reflect.TypeOf(info.Plugin).String())),
+	)
+	pctx.ctx = ctx
+
+	return func(err error) {
+		if err != nil && err != ErrStopDispatch {
+			span.RecordError(err)
+			span.SetStatus(codes.Error, err.Error())
+		}
+		span.End()
+	}
+}
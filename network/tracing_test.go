@@ -0,0 +1,85 @@
+package network_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/perlin-network/noise/internal/test/protobuf"
+	"github.com/perlin-network/noise/network"
+
+	"github.com/stretchr/testify/assert"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+func TestTracing(t *testing.T) {
+	t.Parallel()
+
+	exporter := tracetest.NewInMemoryExporter()
+	tp := sdktrace.NewTracerProvider(sdktrace.WithSyncer(exporter))
+	defer tp.Shutdown(context.Background())
+
+	var nodes []*network.Network
+
+	for i := 0; i < 2; i++ {
+		builder := network.NewBuilderWithOptions(network.WithTracing(tp))
+		builder.SetAddress(network.FormatAddress("tcp", "localhost", uint16(network.GetRandomUnusedPort())))
+		builder.AddPlugin(new(MailBoxPlugin))
+
+		node, err := builder.Build()
+		assert.Nil(t, err)
+
+		go node.Listen()
+		defer node.Close()
+
+		node.BlockUntilListening()
+
+		nodes = append(nodes, node)
+	}
+
+	client, err := nodes[0].Client(nodes[1].Address)
+	assert.Nil(t, err)
+
+	plugin, _ := nodes[1].Plugin(mailboxPluginID)
+	mailbox := plugin.(*MailBoxPlugin).RecvMailbox
+
+	deadline := time.Now().Add(3 * time.Second)
+	for received := false; !received; {
+		client.Tell(context.Background(), &protobuf.TestMessage{Message: "traced"})
+
+		select {
+		case <-mailbox:
+			received = true
+		case <-time.After(100 * time.Millisecond):
+			if time.Now().After(deadline) {
+				t.Fatal("expected message to be received")
+			}
+		}
+	}
+
+	// The plugin may still be within its span upon the message being received.
+	var sent, received tracetest.SpanStub
+
+	for time.Now().Before(deadline) && !received.SpanContext.IsValid() {
+		spans := exporter.GetSpans()
+
+		for _, span := range spans {
+			if span.Name != "noise.plugin.receive" {
+				continue
+			}
+
+			for _, parent := range spans {
+				if parent.Name == "noise.message.send" && parent.SpanContext.SpanID() == span.Parent.SpanID() {
+					sent, received = parent, span
+				}
+			}
+		}
+
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	assert.True(t, received.SpanContext.IsValid(), "expected a receive span whose parent is a send span")
+	assert.Equal(t, sent.SpanContext.TraceID(), received.SpanContext.TraceID())
+	assert.True(t, received.Parent.IsRemote())
+}
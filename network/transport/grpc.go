@@ -0,0 +1,357 @@
+package transport
+
+import (
+	"context"
+	"net"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/peer"
+)
+
+const (
+	// grpcServiceName is the name of the gRPC service connections are streamed over.
+	grpcServiceName = "noise.Noise"
+
+	// grpcStreamMethod is the full name of the bidirectional streaming RPC connections
+	// are streamed over.
+	grpcStreamMethod = "/" + grpcServiceName + "/Stream"
+)
+
+var (
+	errGRPCListenerClosed = errors.New("grpc: listener closed")
+	errGRPCTimeout        = grpcTimeoutError{}
+)
+
+// GRPC represents a transport protocol which streams connections over a single
+// bidirectional streaming gRPC call. Every write to a connection is sent as a single
+// message over the stream.
+type GRPC struct {
+	// ServerOptions configure the gRPC server of the listener.
+	ServerOptions []grpc.ServerOption
+
+	// DialOptions configure dialed gRPC client connections. Connections are dialed
+	// without transport security should no transport credentials be provided.
+	DialOptions []grpc.DialOption
+}
+
+// NewGRPC instantiates a new instance of the gRPC transport protocol.
+func NewGRPC(serverOptions ...grpc.ServerOption) *GRPC {
+	return &GRPC{
+		ServerOptions: serverOptions,
+		DialOptions:   []grpc.DialOption{grpc.WithTransportCredentials(insecure.NewCredentials())},
+	}
+}
+
+// grpcServiceDesc describes a gRPC service with a single bidirectional streaming RPC.
+var grpcServiceDesc = grpc.ServiceDesc{
+	ServiceName: grpcServiceName,
+	HandlerType: (*interface{})(nil),
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "Stream",
+			Handler:       grpcStreamHandler,
+			ServerStreams: true,
+			ClientStreams: true,
+		},
+	},
+}
+
+func grpcStreamHandler(srv interface{}, stream grpc.ServerStream) error {
+	return srv.(*grpcListener).Stream(stream)
+}
+
+// Listen starts a gRPC server on a specified port, accepting connections streamed over
+// its bidirectional streaming RPC.
+func (t *GRPC) Listen(port int) (net.Listener, error) {
+	listener, err := net.Listen("tcp", ":"+strconv.Itoa(port))
+	if err != nil {
+		return nil, err
+	}
+
+	options := append([]grpc.ServerOption{grpc.ForceServerCodec(rawCodec{})}, t.ServerOptions...)
+
+	l := &grpcListener{
+		listener: listener,
+		server:   grpc.NewServer(options...),
+		conns:    make(chan net.Conn),
+		closed:   make(chan struct{}),
+	}
+
+	l.server.RegisterService(&grpcServiceDesc, l)
+	go l.server.Serve(listener)
+
+	return l, nil
+}
+
+// Dial dials an address via. gRPC, and opens a bidirectional stream to it.
+func (t *GRPC) Dial(address string) (net.Conn, error) {
+	options := append([]grpc.DialOption{grpc.WithDefaultCallOptions(grpc.ForceCodec(rawCodec{}))}, t.DialOptions...)
+
+	client, err := grpc.NewClient(address, options...)
+	if err != nil {
+		return nil, err
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+
+	stream, err := client.NewStream(ctx, &grpcServiceDesc.Streams[0], grpcStreamMethod)
+	if err != nil {
+		cancel()
+		client.Close()
+		return nil, err
+	}
+
+	return newGRPCConn(stream, func() error {
+		stream.CloseSend()
+		cancel()
+		return client.Close()
+	}, client.Target()), nil
+}
+
+// grpcListener hands off streams of its bidirectional streaming RPC as connections.
+type grpcListener struct {
+	listener net.Listener
+	server   *grpc.Server
+
+	conns chan net.Conn
+
+	closeOnce sync.Once
+	closed    chan struct{}
+}
+
+// Stream implements the bidirectional streaming RPC, handing off the stream to be
+// accepted. The stream is held open until its connection is closed.
+func (l *grpcListener) Stream(stream grpc.ServerStream) error {
+	done := make(chan struct{})
+
+	remoteAddress := ""
+	if p, ok := peer.FromContext(stream.Context()); ok && p.Addr != nil {
+		remoteAddress = p.Addr.String()
+	}
+
+	conn := newGRPCConn(stream, func() error {
+		close(done)
+		return nil
+	}, remoteAddress)
+
+	select {
+	case l.conns <- conn:
+	case <-l.closed:
+		return errGRPCListenerClosed
+	}
+
+	select {
+	case <-done:
+	case <-stream.Context().Done():
+	case <-l.closed:
+	}
+
+	return nil
+}
+
+// Accept waits for and returns the next connection streamed to the listener.
+func (l *grpcListener) Accept() (net.Conn, error) {
+	select {
+	case conn := <-l.conns:
+		return conn, nil
+	case <-l.closed:
+		return nil, errGRPCListenerClosed
+	}
+}
+
+// Close stops the gRPC server.
+func (l *grpcListener) Close() error {
+	l.closeOnce.Do(func() {
+		close(l.closed)
+		l.server.Stop()
+	})
+
+	return nil
+}
+
+// Addr returns the address the listener is bound to.
+func (l *grpcListener) Addr() net.Addr {
+	return l.listener.Addr()
+}
+
+// grpcStream is the subset of gRPC client and server streams connections are built on.
+type grpcStream interface {
+	Context() context.Context
+	SendMsg(m interface{}) error
+	RecvMsg(m interface{}) error
+}
+
+// grpcResult is the result of receiving a message off of a stream.
+type grpcResult struct {
+	data []byte
+	err  error
+}
+
+// grpcConn adapts a gRPC stream to a net.Conn. Reads receive messages off of the
+// stream, and writes each send a single message over the stream.
+type grpcConn struct {
+	stream grpcStream
+	close  func() error
+
+	remoteAddress string
+
+	readMutex sync.Mutex
+	buffer    []byte
+	pending   chan grpcResult
+
+	writeMutex sync.Mutex
+
+	deadlineMutex sync.Mutex
+	readDeadline  time.Time
+
+	closeOnce sync.Once
+	closeErr  error
+}
+
+func newGRPCConn(stream grpcStream, close func() error, remoteAddress string) *grpcConn {
+	return &grpcConn{
+		stream:        stream,
+		close:         close,
+		remoteAddress: remoteAddress,
+	}
+}
+
+// Read reads data off of the stream. As gRPC streams do not support deadlines, messages
+// are received in the background such that reads may time out.
+func (c *grpcConn) Read(out []byte) (int, error) {
+	c.readMutex.Lock()
+	defer c.readMutex.Unlock()
+
+	for len(c.buffer) == 0 {
+		if c.pending == nil {
+			c.pending = make(chan grpcResult, 1)
+
+			go func(pending chan grpcResult) {
+				var data []byte
+				err := c.stream.RecvMsg(&data)
+				pending <- grpcResult{data: data, err: err}
+			}(c.pending)
+		}
+
+		c.deadlineMutex.Lock()
+		deadline := c.readDeadline
+		c.deadlineMutex.Unlock()
+
+		var timeout <-chan time.Time
+		if !deadline.IsZero() {
+			timer := time.NewTimer(time.Until(deadline))
+			defer timer.Stop()
+			timeout = timer.C
+		}
+
+		select {
+		case result := <-c.pending:
+			c.pending = nil
+
+			if result.err != nil {
+				return 0, result.err
+			}
+			c.buffer = result.data
+		case <-timeout:
+			return 0, errGRPCTimeout
+		}
+	}
+
+	n := copy(out, c.buffer)
+	c.buffer = c.buffer[n:]
+
+	return n, nil
+}
+
+// Write sends data as a single message over the stream.
+func (c *grpcConn) Write(data []byte) (int, error) {
+	c.writeMutex.Lock()
+	defer c.writeMutex.Unlock()
+
+	// Messages may be retained by gRPC after being sent.
+	message := append([]byte(nil), data...)
+	if err := c.stream.SendMsg(&message); err != nil {
+		return 0, err
+	}
+
+	return len(data), nil
+}
+
+// Close closes the stream.
+func (c *grpcConn) Close() error {
+	c.closeOnce.Do(func() {
+		c.closeErr = c.close()
+	})
+	return c.closeErr
+}
+
+// LocalAddr returns the local address of the connection, which is unknown for streams.
+func (c *grpcConn) LocalAddr() net.Addr {
+	return grpcAddr("")
+}
+
+// RemoteAddr returns the remote address of the connection.
+func (c *grpcConn) RemoteAddr() net.Addr {
+	return grpcAddr(c.remoteAddress)
+}
+
+// SetDeadline sets the read deadline of the connection. Write deadlines are not supported.
+func (c *grpcConn) SetDeadline(t time.Time) error {
+	return c.SetReadDeadline(t)
+}
+
+// SetReadDeadline sets the read deadline of the connection.
+func (c *grpcConn) SetReadDeadline(t time.Time) error {
+	c.deadlineMutex.Lock()
+	c.readDeadline = t
+	c.deadlineMutex.Unlock()
+
+	return nil
+}
+
+// SetWriteDeadline is a no-op, as gRPC streams do not support write deadlines.
+func (c *grpcConn) SetWriteDeadline(t time.Time) error {
+	return nil
+}
+
+// grpcAddr is the address of a gRPC stream.
+type grpcAddr string
+
+func (a grpcAddr) Network() string { return "grpc" }
+func (a grpcAddr) String() string  { return string(a) }
+
+// grpcTimeoutError is returned by reads which exceed their deadline.
+type grpcTimeoutError struct{}
+
+func (grpcTimeoutError) Error() string   { return "grpc: i/o timeout" }
+func (grpcTimeoutError) Timeout() bool   { return true }
+func (grpcTimeoutError) Temporary() bool { return true }
+
+// rawCodec passes raw bytes through gRPC without serialization.
+type rawCodec struct{}
+
+func (rawCodec) Marshal(v interface{}) ([]byte, error) {
+	data, ok := v.(*[]byte)
+	if !ok {
+		return nil, errors.Errorf("grpc: cannot marshal %T", v)
+	}
+	return *data, nil
+}
+
+func (rawCodec) Unmarshal(data []byte, v interface{}) error {
+	out, ok := v.(*[]byte)
+	if !ok {
+		return errors.Errorf("grpc: cannot unmarshal into %T", v)
+	}
+	*out = append([]byte(nil), data...)
+	return nil
+}
+
+func (rawCodec) Name() string {
+	return "noise-raw"
+}
@@ -0,0 +1,61 @@
+package transport
+
+import (
+	"net"
+	"strconv"
+	"testing"
+)
+
+func TestGRPCRemoteAddr(t *testing.T) {
+	t.Parallel()
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	port := listener.Addr().(*net.TCPAddr).Port
+	listener.Close()
+
+	layer := NewGRPC()
+
+	grpcListener, err := layer.Listen(port)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer grpcListener.Close()
+
+	accepted := make(chan net.Conn, 1)
+
+	go func() {
+		conn, err := grpcListener.Accept()
+		if err != nil {
+			return
+		}
+		accepted <- conn
+	}()
+
+	address := net.JoinHostPort("127.0.0.1", strconv.Itoa(port))
+
+	conn, err := layer.Dial(address)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+
+	// Send over the stream, such that it reaches the listener.
+	if _, err := conn.Write([]byte("ping")); err != nil {
+		t.Fatal(err)
+	}
+
+	inbound := <-accepted
+	defer inbound.Close()
+
+	if remote := conn.RemoteAddr().String(); remote != address {
+		t.Errorf("expected dialed connection to have remote address %s, got %q", address, remote)
+	}
+
+	host, _, err := net.SplitHostPort(inbound.RemoteAddr().String())
+	if err != nil || host != "127.0.0.1" {
+		t.Errorf("expected accepted connection to have the remote address of the dialer, got %q", inbound.RemoteAddr().String())
+	}
+}
@@ -3,6 +3,7 @@ package transport
 import (
 	"net"
 	"strconv"
+	"time"
 
 	"github.com/xtaci/kcp-go"
 )
@@ -13,6 +14,16 @@ type KCP struct {
 	ParityShards   int
 	SendWindowSize int
 	RecvWindowSize int
+
+	// NoDelay retransmits lost packets more aggressively, trading bandwidth for latency.
+	NoDelay bool
+	// Interval is how often sessions flush and check for packets to retransmit.
+	Interval time.Duration
+	// Resend fast retransmits a packet once this many later packets have been
+	// acknowledged before it. Disabled if zero.
+	Resend int
+	// NoCongestionControl disables congestion control of sessions.
+	NoCongestionControl bool
 }
 
 // NewKCP instantiates a new instance of the KCP protocol.
@@ -22,6 +33,7 @@ func NewKCP() *KCP {
 		ParityShards:   0,
 		SendWindowSize: 10000,
 		RecvWindowSize: 10000,
+		Interval:       100 * time.Millisecond,
 	}
 }
 
@@ -33,7 +45,7 @@ func (t *KCP) Listen(port int) (net.Listener, error) {
 		return nil, err
 	}
 
-	return listener, nil
+	return &kcpListener{Listener: listener, layer: t}, nil
 }
 
 // Dial dials an address via. the KCP protocol, with optional Reed-Solomon message sharding.
@@ -44,7 +56,38 @@ func (t *KCP) Dial(address string) (net.Conn, error) {
 		return nil, err
 	}
 
-	conn.SetWindowSize(t.SendWindowSize, t.RecvWindowSize)
+	t.configure(conn)
 
 	return conn, nil
 }
+
+// configure applies the window sizes and retransmission options of the protocol to a session.
+func (t *KCP) configure(session *kcp.UDPSession) {
+	session.SetWindowSize(t.SendWindowSize, t.RecvWindowSize)
+	session.SetNoDelay(boolToInt(t.NoDelay), int(t.Interval/time.Millisecond), t.Resend, boolToInt(t.NoCongestionControl))
+}
+
+func boolToInt(b bool) int {
+	if b {
+		return 1
+	}
+	return 0
+}
+
+// kcpListener configures accepted KCP sessions.
+type kcpListener struct {
+	*kcp.Listener
+	layer *KCP
+}
+
+// Accept waits for and returns the next incoming KCP session.
+func (l *kcpListener) Accept() (net.Conn, error) {
+	session, err := l.AcceptKCP()
+	if err != nil {
+		return nil, err
+	}
+
+	l.layer.configure(session)
+
+	return session, nil
+}
@@ -0,0 +1,115 @@
+package transport
+
+import (
+	"bytes"
+	"encoding/binary"
+	"io"
+	"math/rand"
+	"net"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/xtaci/kcp-go"
+)
+
+// lossyPacketConn randomly drops outgoing packets.
+type lossyPacketConn struct {
+	net.PacketConn
+
+	mutex    sync.Mutex
+	rng      *rand.Rand
+	lossRate float64
+	dropped  int
+}
+
+func (c *lossyPacketConn) WriteTo(b []byte, addr net.Addr) (int, error) {
+	c.mutex.Lock()
+	drop := c.rng.Float64() < c.lossRate
+	if drop {
+		c.dropped++
+	}
+	c.mutex.Unlock()
+
+	if drop {
+		return len(b), nil
+	}
+	return c.PacketConn.WriteTo(b, addr)
+}
+
+func newLossyPacketConn(t *testing.T, seed int64) *lossyPacketConn {
+	conn, err := net.ListenPacket("udp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return &lossyPacketConn{PacketConn: conn, rng: rand.New(rand.NewSource(seed)), lossRate: 0.2}
+}
+
+func TestKCPPacketLoss(t *testing.T) {
+	t.Parallel()
+
+	const numMessages = 200
+
+	layer := NewKCP()
+	layer.NoDelay = true
+	layer.Interval = 10 * time.Millisecond
+	layer.Resend = 2
+	layer.NoCongestionControl = true
+
+	serverConn, clientConn := newLossyPacketConn(t, 1), newLossyPacketConn(t, 2)
+
+	listener, err := kcp.ServeConn(nil, layer.DataShards, layer.ParityShards, serverConn)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer listener.Close()
+
+	session, err := kcp.NewConn(serverConn.LocalAddr().String(), nil, layer.DataShards, layer.ParityShards, clientConn)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer session.Close()
+	layer.configure(session)
+
+	go func() {
+		for i := 0; i < numMessages; i++ {
+			message := make([]byte, 1024)
+			binary.BigEndian.PutUint32(message, uint32(i))
+
+			if _, err := session.Write(message); err != nil {
+				return
+			}
+		}
+	}()
+
+	conn, err := (&kcpListener{Listener: listener, layer: layer}).Accept()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+
+	conn.SetReadDeadline(time.Now().Add(10 * time.Second))
+
+	for i := 0; i < numMessages; i++ {
+		message := make([]byte, 1024)
+		if _, err := io.ReadFull(conn, message); err != nil {
+			t.Fatalf("failed to read message %d: %v", i, err)
+		}
+
+		expected := make([]byte, 1024)
+		binary.BigEndian.PutUint32(expected, uint32(i))
+
+		if !bytes.Equal(expected, message) {
+			t.Fatalf("expected message %d, got message %d", i, binary.BigEndian.Uint32(message))
+		}
+	}
+
+	clientConn.mutex.Lock()
+	dropped := clientConn.dropped
+	clientConn.mutex.Unlock()
+
+	if dropped == 0 {
+		t.Error("expected packets to have been dropped")
+	}
+}
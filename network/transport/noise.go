@@ -0,0 +1,84 @@
+package transport
+
+import (
+	"net"
+	"time"
+
+	"github.com/perlin-network/noise/crypto"
+	"github.com/perlin-network/noise/handshake"
+)
+
+// Noise represents a transport protocol layer which secures connections of an
+// underlying transport protocol layer with a Noise XX handshake.
+type Noise struct {
+	Layer Layer
+	Keys  *crypto.KeyPair
+
+	// HandshakeTimeout bounds how long handshakes may take, such that peers may not hold
+	// connections open by stalling them. Defaults to defaultHandshakeTimeout.
+	HandshakeTimeout time.Duration
+}
+
+// NewNoise instantiates a new instance of the Noise transport protocol wrapping an
+// underlying transport protocol layer, authenticating connections with a key pair.
+func NewNoise(layer Layer, keys *crypto.KeyPair) *Noise {
+	return &Noise{
+		Layer: layer,
+		Keys:  keys,
+	}
+}
+
+// Listen listens for incoming connections on a specified port. The handshake of
+// an accepted connection is performed upon its first read or write.
+func (t *Noise) Listen(port int) (net.Listener, error) {
+	listener, err := t.Layer.Listen(port)
+	if err != nil {
+		return nil, err
+	}
+
+	return &noiseListener{Listener: listener, keys: t.Keys, timeout: t.handshakeTimeout()}, nil
+}
+
+// Dial dials an address via. the underlying transport protocol, and performs a
+// Noise XX handshake over the established connection.
+func (t *Noise) Dial(address string) (net.Conn, error) {
+	conn, err := t.Layer.Dial(address)
+	if err != nil {
+		return nil, err
+	}
+
+	secured := handshake.Client(conn, t.Keys)
+	secured.SetHandshakeTimeout(t.handshakeTimeout())
+
+	if err := secured.Handshake(); err != nil {
+		conn.Close()
+		return nil, err
+	}
+
+	return secured, nil
+}
+
+func (t *Noise) handshakeTimeout() time.Duration {
+	if t.HandshakeTimeout > 0 {
+		return t.HandshakeTimeout
+	}
+	return defaultHandshakeTimeout
+}
+
+type noiseListener struct {
+	net.Listener
+	keys    *crypto.KeyPair
+	timeout time.Duration
+}
+
+func (l *noiseListener) Accept() (net.Conn, error) {
+	conn, err := l.Listener.Accept()
+	if err != nil {
+		return nil, err
+	}
+
+	secured := handshake.Server(conn, l.keys)
+	secured.SetHandshakeTimeout(l.timeout)
+
+	return secured, nil
+}
@@ -0,0 +1,145 @@
+package transport
+
+import (
+	"bytes"
+	"encoding/binary"
+	"io"
+	"net"
+
+	"github.com/pkg/errors"
+)
+
+const (
+	// proxyHeaderSize is the size of the fixed portion of a PROXY protocol v2 header.
+	proxyHeaderSize = 16
+
+	proxyVersion2     = 0x20
+	proxyCommandLocal = 0x0
+	proxyCommandProxy = 0x1
+
+	proxyFamilyUnspec = 0x0
+	proxyFamilyINET   = 0x1
+	proxyFamilyINET6  = 0x2
+
+	proxyTransportStream = 0x1
+
+	proxyINETAddressSize  = 12
+	proxyINET6AddressSize = 36
+)
+
+var (
+	// proxySignature prefixes every PROXY protocol v2 header.
+	proxySignature = []byte{0x0D, 0x0A, 0x0D, 0x0A, 0x00, 0x0D, 0x0A, 0x51, 0x55, 0x49, 0x54, 0x0A}
+
+	errPROXYSignature = errors.New("proxy: connection did not begin with a PROXY protocol v2 header")
+	errPROXYVersion   = errors.New("proxy: unsupported PROXY protocol version")
+	errPROXYCommand   = errors.New("proxy: unsupported PROXY protocol command")
+	errPROXYAddress   = errors.New("proxy: PROXY protocol header has a truncated address block")
+)
+
+// ReadPROXYHeader reads a PROXY protocol v2 header, as sent by load balancers such as
+// HAProxy or AWS NLB, off of the start of a connection. The returned connection reports
+// the source address carried within the header as its remote address.
+//
+// Headers sent for health checks (LOCAL), or which carry no supported address family,
+// keep the connections own remote address. TLVs are skipped.
+func ReadPROXYHeader(conn net.Conn) (net.Conn, error) {
+	header := make([]byte, proxyHeaderSize)
+	if _, err := io.ReadFull(conn, header); err != nil {
+		return nil, err
+	}
+
+	if !bytes.Equal(header[:len(proxySignature)], proxySignature) {
+		return nil, errPROXYSignature
+	}
+
+	if header[12]&0xF0 != proxyVersion2 {
+		return nil, errPROXYVersion
+	}
+
+	command := header[12] & 0x0F
+	if command != proxyCommandLocal && command != proxyCommandProxy {
+		return nil, errPROXYCommand
+	}
+
+	block := make([]byte, binary.BigEndian.Uint16(header[14:16]))
+	if _, err := io.ReadFull(conn, block); err != nil {
+		return nil, err
+	}
+
+	if command == proxyCommandLocal {
+		return conn, nil
+	}
+
+	var source net.Addr
+
+	switch header[13] >> 4 {
+	case proxyFamilyINET:
+		if len(block) < proxyINETAddressSize {
+			return nil, errPROXYAddress
+		}
+		source = &net.TCPAddr{
+			IP:   net.IP(append([]byte(nil), block[0:4]...)),
+			Port: int(binary.BigEndian.Uint16(block[8:10])),
+		}
+	case proxyFamilyINET6:
+		if len(block) < proxyINET6AddressSize {
+			return nil, errPROXYAddress
+		}
+		source = &net.TCPAddr{
+			IP:   net.IP(append([]byte(nil), block[0:16]...)),
+			Port: int(binary.BigEndian.Uint16(block[32:34])),
+		}
+	default:
+		return conn, nil
+	}
+
+	return &proxyConn{Conn: conn, source: source}, nil
+}
+
+// WritePROXYHeader writes a PROXY protocol v2 header for a proxied TCP connection from a
+// source address to a destination address.
+func WritePROXYHeader(w io.Writer, source, destination *net.TCPAddr) error {
+	header := append([]byte(nil), proxySignature...)
+	header = append(header, proxyVersion2|proxyCommandProxy)
+
+	var block []byte
+
+	if src4, dst4 := source.IP.To4(), destination.IP.To4(); src4 != nil && dst4 != nil {
+		header = append(header, proxyFamilyINET<<4|proxyTransportStream)
+		block = append(append(block, src4...), dst4...)
+	} else {
+		header = append(header, proxyFamilyINET6<<4|proxyTransportStream)
+		block = append(append(block, source.IP.To16()...), destination.IP.To16()...)
+	}
+
+	var ports [4]byte
+	binary.BigEndian.PutUint16(ports[0:2], uint16(source.Port))
+	binary.BigEndian.PutUint16(ports[2:4], uint16(destination.Port))
+	block = append(block, ports[:]...)
+
+	var size [2]byte
+	binary.BigEndian.PutUint16(size[:], uint16(len(block)))
+	header = append(append(header, size[:]...), block...)
+
+	_, err := w.Write(header)
+	return err
+}
+
+// proxyConn is a connection whose remote address is that of the client a proxy
+// accepted the connection from.
+type proxyConn struct {
+	net.Conn
+
+	source net.Addr
+}
+
+// RemoteAddr returns the address of the proxied client.
+func (c *proxyConn) RemoteAddr() net.Addr {
+	return c.source
+}
+
+// Unwrap returns the underlying connection.
+func (c *proxyConn) Unwrap() net.Conn {
+	return c.Conn
+}
@@ -0,0 +1,108 @@
+package transport
+
+import (
+	"io"
+	"net"
+	"testing"
+)
+
+func TestReadPROXYHeader(t *testing.T) {
+	t.Parallel()
+
+	client, server := net.Pipe()
+	defer client.Close()
+	defer server.Close()
+
+	header := []byte{
+		0x0D, 0x0A, 0x0D, 0x0A, 0x00, 0x0D, 0x0A, 0x51, 0x55, 0x49, 0x54, 0x0A, // signature
+		0x21,       // version 2, PROXY
+		0x11,       // INET, STREAM
+		0x00, 0x0F, // 12 address bytes, and a 3 byte TLV
+		203, 0, 113, 7, // source address
+		10, 0, 0, 1, // destination address
+		0x1F, 0x90, // source port 8080
+		0x0B, 0xB8, // destination port 3000
+		0x04, 0x00, 0x00, // PP2_TYPE_NOOP
+	}
+
+	go func() {
+		client.Write(header)
+		client.Write([]byte("noise"))
+	}()
+
+	conn, err := ReadPROXYHeader(server)
+	if err != nil {
+		t.Fatalf("ReadPROXYHeader() = expected no error, got %v", err)
+	}
+
+	if expected := "203.0.113.7:8080"; conn.RemoteAddr().String() != expected {
+		t.Errorf("RemoteAddr() = %s, expected %s", conn.RemoteAddr(), expected)
+	}
+
+	buf := make([]byte, 5)
+	if _, err := io.ReadFull(conn, buf); err != nil || string(buf) != "noise" {
+		t.Errorf("expected data following the header to be readable, got %q (%v)", buf, err)
+	}
+}
+
+func TestWritePROXYHeader(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		source      *net.TCPAddr
+		destination *net.TCPAddr
+	}{
+		{&net.TCPAddr{IP: net.ParseIP("198.51.100.20"), Port: 4000}, &net.TCPAddr{IP: net.ParseIP("10.0.0.1"), Port: 3000}},
+		{&net.TCPAddr{IP: net.ParseIP("2001:db8::7"), Port: 4000}, &net.TCPAddr{IP: net.ParseIP("2001:db8::1"), Port: 3000}},
+	}
+	for _, tt := range testCases {
+		client, server := net.Pipe()
+
+		go WritePROXYHeader(client, tt.source, tt.destination)
+
+		conn, err := ReadPROXYHeader(server)
+		if err != nil {
+			t.Fatalf("ReadPROXYHeader() = expected no error, got %v", err)
+		}
+
+		if conn.RemoteAddr().String() != tt.source.String() {
+			t.Errorf("RemoteAddr() = %s, expected %s", conn.RemoteAddr(), tt.source)
+		}
+
+		client.Close()
+		server.Close()
+	}
+}
+
+func TestReadPROXYHeaderLocal(t *testing.T) {
+	t.Parallel()
+
+	client, server := net.Pipe()
+	defer client.Close()
+	defer server.Close()
+
+	go client.Write(append(append([]byte(nil), proxySignature...), 0x20, 0x00, 0x00, 0x00))
+
+	conn, err := ReadPROXYHeader(server)
+	if err != nil {
+		t.Fatalf("ReadPROXYHeader() = expected no error, got %v", err)
+	}
+
+	if conn.RemoteAddr() != server.RemoteAddr() {
+		t.Errorf("RemoteAddr() = %s, expected the connections own address", conn.RemoteAddr())
+	}
+}
+
+func TestReadPROXYHeaderInvalid(t *testing.T) {
+	t.Parallel()
+
+	client, server := net.Pipe()
+	defer client.Close()
+	defer server.Close()
+
+	go client.Write([]byte("GET / HTTP/1.1\r\n\r\n"))
+
+	if _, err := ReadPROXYHeader(server); err != errPROXYSignature {
+		t.Errorf("ReadPROXYHeader() = %v, expected %v", err, errPROXYSignature)
+	}
+}
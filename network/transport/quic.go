@@ -0,0 +1,238 @@
+package transport
+
+import (
+	"context"
+	"crypto/tls"
+	"io"
+	"net"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/quic-go/quic-go"
+)
+
+const (
+	// defaultQUICNextProto is the ALPN protocol negotiated by QUIC connections should
+	// none be configured.
+	defaultQUICNextProto = "noise"
+
+	// defaultQUICKeepAlivePeriod keeps idle connections from timing out.
+	defaultQUICKeepAlivePeriod = 15 * time.Second
+)
+
+// QUIC represents the QUIC transport protocol over UDP. Every write to a connection is
+// sent over its own unidirectional stream.
+type QUIC struct {
+	// ServerConfig configures connections accepted by the listener.
+	ServerConfig *tls.Config
+
+	// ClientConfig configures dialed connections.
+	ClientConfig *tls.Config
+
+	// Config configures QUIC connections.
+	Config *quic.Config
+}
+
+// NewQUIC instantiates a new instance of the QUIC transport protocol.
+func NewQUIC(serverConfig *tls.Config, clientConfig *tls.Config) *QUIC {
+	return &QUIC{
+		ServerConfig: serverConfig,
+		ClientConfig: clientConfig,
+		Config: &quic.Config{
+			KeepAlivePeriod: defaultQUICKeepAlivePeriod,
+		},
+	}
+}
+
+// Listen listens for incoming QUIC connections on a specified port.
+func (t *QUIC) Listen(port int) (net.Listener, error) {
+	listener, err := quic.ListenAddr(":"+strconv.Itoa(port), withNextProto(t.ServerConfig), t.Config)
+	if err != nil {
+		return nil, err
+	}
+
+	return &quicListener{Listener: listener}, nil
+}
+
+// Dial dials an address via. the QUIC protocol.
+func (t *QUIC) Dial(address string) (net.Conn, error) {
+	config := withNextProto(t.ClientConfig)
+
+	// Mirror tls.Dial, which verifies certificates against the dialed host name.
+	if len(config.ServerName) == 0 {
+		host, _, err := net.SplitHostPort(address)
+		if err != nil {
+			return nil, err
+		}
+		config.ServerName = host
+	}
+
+	conn, err := quic.DialAddr(context.Background(), address, config, t.Config)
+	if err != nil {
+		return nil, err
+	}
+
+	return newQUICConn(conn), nil
+}
+
+// withNextProto returns a copy of a TLS config which defaults to negotiating the
+// noise ALPN protocol, as is required by QUIC.
+func withNextProto(config *tls.Config) *tls.Config {
+	config = withMinVersion(config)
+	if len(config.NextProtos) == 0 {
+		config.NextProtos = []string{defaultQUICNextProto}
+	}
+
+	return config
+}
+
+// quicListener adapts a QUIC listener to a net.Listener.
+type quicListener struct {
+	*quic.Listener
+}
+
+// Accept waits for and returns the next incoming QUIC connection.
+func (l *quicListener) Accept() (net.Conn, error) {
+	conn, err := l.Listener.Accept(context.Background())
+	if err != nil {
+		return nil, err
+	}
+
+	return newQUICConn(conn), nil
+}
+
+// quicConn adapts a QUIC connection to a net.Conn. Writes are each sent over a new
+// unidirectional stream, and reads drain incoming streams in the order they were opened.
+type quicConn struct {
+	conn *quic.Conn
+
+	readMutex sync.Mutex
+	stream    *quic.ReceiveStream
+
+	writeMutex sync.Mutex
+
+	deadlineMutex sync.Mutex
+	readDeadline  time.Time
+	writeDeadline time.Time
+}
+
+func newQUICConn(conn *quic.Conn) *quicConn {
+	return &quicConn{conn: conn}
+}
+
+// deadlineContext returns a context which expires at a deadline, should it be set.
+func deadlineContext(deadline time.Time) (context.Context, context.CancelFunc) {
+	if deadline.IsZero() {
+		return context.WithCancel(context.Background())
+	}
+	return context.WithDeadline(context.Background(), deadline)
+}
+
+// Read reads data off of the current incoming stream, accepting the next stream once
+// the current stream has been fully read.
+func (c *quicConn) Read(out []byte) (int, error) {
+	c.readMutex.Lock()
+	defer c.readMutex.Unlock()
+
+	c.deadlineMutex.Lock()
+	deadline := c.readDeadline
+	c.deadlineMutex.Unlock()
+
+	for {
+		if c.stream == nil {
+			ctx, cancel := deadlineContext(deadline)
+			stream, err := c.conn.AcceptUniStream(ctx)
+			cancel()
+
+			if err != nil {
+				return 0, err
+			}
+			c.stream = stream
+		}
+
+		c.stream.SetReadDeadline(deadline)
+
+		n, err := c.stream.Read(out)
+		if err == io.EOF {
+			c.stream = nil
+
+			if n == 0 {
+				continue
+			}
+			err = nil
+		}
+
+		return n, err
+	}
+}
+
+// Write sends data over a new unidirectional stream.
+func (c *quicConn) Write(data []byte) (int, error) {
+	c.writeMutex.Lock()
+	defer c.writeMutex.Unlock()
+
+	c.deadlineMutex.Lock()
+	deadline := c.writeDeadline
+	c.deadlineMutex.Unlock()
+
+	ctx, cancel := deadlineContext(deadline)
+	stream, err := c.conn.OpenUniStreamSync(ctx)
+	cancel()
+
+	if err != nil {
+		return 0, err
+	}
+
+	stream.SetWriteDeadline(deadline)
+
+	n, err := stream.Write(data)
+	if err != nil {
+		stream.CancelWrite(0)
+		return n, err
+	}
+
+	return n, stream.Close()
+}
+
+// Close closes the QUIC connection.
+func (c *quicConn) Close() error {
+	return c.conn.CloseWithError(0, "")
+}
+
+// LocalAddr returns the local address of the connection.
+func (c *quicConn) LocalAddr() net.Addr {
+	return c.conn.LocalAddr()
+}
+
+// RemoteAddr returns the remote address of the connection.
+func (c *quicConn) RemoteAddr() net.Addr {
+	return c.conn.RemoteAddr()
+}
+
+// SetDeadline sets the read and write deadlines of the connection.
+func (c *quicConn) SetDeadline(t time.Time) error {
+	c.deadlineMutex.Lock()
+	c.readDeadline, c.writeDeadline = t, t
+	c.deadlineMutex.Unlock()
+
+	return nil
+}
+
+// SetReadDeadline sets the read deadline of the connection.
+func (c *quicConn) SetReadDeadline(t time.Time) error {
+	c.deadlineMutex.Lock()
+	c.readDeadline = t
+	c.deadlineMutex.Unlock()
+
+	return nil
+}
+
+// SetWriteDeadline sets the write deadline of the connection.
+func (c *quicConn) SetWriteDeadline(t time.Time) error {
+	c.deadlineMutex.Lock()
+	c.writeDeadline = t
+	c.deadlineMutex.Unlock()
+
+	return nil
+}
@@ -0,0 +1,204 @@
+package transport
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"net"
+	"sync/atomic"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// TLS represents a transport protocol layer which secures connections of an
+// underlying transport protocol layer with TLS 1.3.
+type TLS struct {
+	Layer Layer
+
+	// ServerConfig configures connections accepted by the listener. Setting
+	// ClientAuth to tls.RequireAnyClientCert makes peers' certificates available
+	// for identity binding.
+	ServerConfig *tls.Config
+
+	// ClientConfig configures dialed connections.
+	ClientConfig *tls.Config
+
+	// PinnedCertificates maps addresses dialed to the DER encoded certificates the peers
+	// at them may present, such that a compromised certificate authority may not be used
+	// to impersonate them. Handshakes with pinned peers fail should their certificate not
+	// be pinned, including handshakes resuming a session. Pins are checked in addition to
+	// the verification of ClientConfig.
+	PinnedCertificates map[string][][]byte
+
+	// HandshakeTimeout bounds how long handshakes may take, such that peers may not hold
+	// connections open by stalling them. Defaults to defaultHandshakeTimeout.
+	HandshakeTimeout time.Duration
+}
+
+// NewTLS instantiates a new instance of the TLS transport protocol wrapping an
+// underlying transport protocol layer.
+func NewTLS(layer Layer, serverConfig *tls.Config, clientConfig *tls.Config) *TLS {
+	return &TLS{
+		Layer:        layer,
+		ServerConfig: serverConfig,
+		ClientConfig: clientConfig,
+	}
+}
+
+// Listen listens for incoming TLS connections on a specified port. The handshake of an
+// accepted connection is performed upon its first read or write.
+func (t *TLS) Listen(port int) (net.Listener, error) {
+	listener, err := t.Layer.Listen(port)
+	if err != nil {
+		return nil, err
+	}
+
+	return &tlsListener{Listener: listener, config: withMinVersion(t.ServerConfig), timeout: t.handshakeTimeout()}, nil
+}
+
+// Dial dials an address via. the underlying transport protocol, and performs a
+// TLS handshake over the established connection.
+func (t *TLS) Dial(address string) (net.Conn, error) {
+	config := withMinVersion(t.ClientConfig)
+
+	// Mirror tls.Dial, which verifies certificates against the dialed host name.
+	if len(config.ServerName) == 0 {
+		host, _, err := net.SplitHostPort(address)
+		if err != nil {
+			return nil, err
+		}
+		config.ServerName = host
+	}
+
+	// Pins are verified upon every handshake, including those resuming a session.
+	if pinned, ok := t.PinnedCertificates[address]; ok {
+		config.VerifyConnection = verifyPinned(address, pinned, config.VerifyConnection)
+	}
+
+	conn, err := t.Layer.Dial(address)
+	if err != nil {
+		return nil, err
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), t.handshakeTimeout())
+	defer cancel()
+
+	secured := tls.Client(conn, config)
+	if err := secured.HandshakeContext(ctx); err != nil {
+		conn.Close()
+		return nil, err
+	}
+
+	return secured, nil
+}
+
+func (t *TLS) handshakeTimeout() time.Duration {
+	if t.HandshakeTimeout > 0 {
+		return t.HandshakeTimeout
+	}
+	return defaultHandshakeTimeout
+}
+
+type tlsListener struct {
+	net.Listener
+
+	config  *tls.Config
+	timeout time.Duration
+}
+
+func (l *tlsListener) Accept() (net.Conn, error) {
+	conn, err := l.Listener.Accept()
+	if err != nil {
+		return nil, err
+	}
+
+	return &tlsConn{Conn: tls.Server(conn, l.config), timeout: l.timeout}, nil
+}
+
+// tlsConn is an accepted TLS connection whose handshake must complete within a timeout.
+type tlsConn struct {
+	*tls.Conn
+
+	timeout    time.Duration
+	handshaken uint32
+}
+
+// Handshake performs the TLS handshake should it not have been performed yet, failing
+// should it not complete within the handshake timeout.
+func (c *tlsConn) Handshake() error {
+	if atomic.LoadUint32(&c.handshaken) == 1 {
+		return nil
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), c.timeout)
+	defer cancel()
+
+	if err := c.Conn.HandshakeContext(ctx); err != nil {
+		return err
+	}
+
+	atomic.StoreUint32(&c.handshaken, 1)
+	return nil
+}
+
+func (c *tlsConn) Read(b []byte) (int, error) {
+	if err := c.Handshake(); err != nil {
+		return 0, err
+	}
+	return c.Conn.Read(b)
+}
+
+func (c *tlsConn) Write(b []byte) (int, error) {
+	if err := c.Handshake(); err != nil {
+		return 0, err
+	}
+	return c.Conn.Write(b)
+}
+
+// Unwrap returns the underlying TLS connection.
+func (c *tlsConn) Unwrap() net.Conn {
+	return c.Conn
+}
+
+// withMinVersion returns a copy of a TLS config which defaults to requiring TLS 1.3.
+func withMinVersion(config *tls.Config) *tls.Config {
+	if config == nil {
+		config = new(tls.Config)
+	}
+
+	config = config.Clone()
+	if config.MinVersion == 0 {
+		config.MinVersion = tls.VersionTLS13
+	}
+
+	return config
+}
+
+// verifyPinned returns a callback which verifies the certificate presented by the peer at
+// an address is pinned, before calling the callback set by the users TLS config.
+func verifyPinned(address string, pinned [][]byte, next func(tls.ConnectionState) error) func(tls.ConnectionState) error {
+	return func(state tls.ConnectionState) error {
+		if len(state.PeerCertificates) == 0 {
+			return errors.Errorf("tls: peer at %s presented no certificate", address)
+		}
+
+		found := false
+		for _, cert := range pinned {
+			if bytes.Equal(state.PeerCertificates[0].Raw, cert) {
+				found = true
+				break
+			}
+		}
+
+		if !found {
+			return errors.Errorf("tls: certificate presented by peer at %s is not pinned", address)
+		}
+
+		if next != nil {
+			return next(state)
+		}
+
+		return nil
+	}
+}
@@ -0,0 +1,183 @@
+package transport
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"math/big"
+	"net"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+)
+
+// generateCertificate generates a self-signed TLS certificate.
+func generateCertificate(t *testing.T) tls.Certificate {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: "noise"},
+		NotBefore:    time.Now().Add(-1 * time.Hour),
+		NotAfter:     time.Now().Add(1 * time.Hour),
+	}
+
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return tls.Certificate{Certificate: [][]byte{der}, PrivateKey: key}
+}
+
+func TestTLSPinnedCertificates(t *testing.T) {
+	t.Parallel()
+
+	served, pinned := generateCertificate(t), generateCertificate(t)
+
+	server := NewTLS(NewTCP(), &tls.Config{Certificates: []tls.Certificate{served}}, nil)
+
+	listener, err := server.Listen(0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer listener.Close()
+
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+
+			conn.(*tlsConn).Handshake()
+			conn.Close()
+		}
+	}()
+
+	address := net.JoinHostPort("localhost", strconv.Itoa(listener.Addr().(*net.TCPAddr).Port))
+
+	// Certificates are self-signed, so only pins authenticate the server.
+	client := NewTLS(NewTCP(), nil, &tls.Config{InsecureSkipVerify: true})
+	client.PinnedCertificates = map[string][][]byte{address: {pinned.Certificate[0]}}
+
+	if _, err := client.Dial(address); err == nil || !strings.Contains(err.Error(), "not pinned") {
+		t.Fatalf("expected dialing a peer serving an unpinned certificate to fail, got %v", err)
+	}
+
+	client.PinnedCertificates[address] = append(client.PinnedCertificates[address], served.Certificate[0])
+
+	conn, err := client.Dial(address)
+	if err != nil {
+		t.Fatalf("expected dialing a peer serving a pinned certificate to succeed, got %v", err)
+	}
+	conn.Close()
+}
+
+func TestTLSPinnedCertificatesResumedSession(t *testing.T) {
+	t.Parallel()
+
+	served, pinned := generateCertificate(t), generateCertificate(t)
+
+	server := NewTLS(NewTCP(), &tls.Config{Certificates: []tls.Certificate{served}}, nil)
+
+	listener, err := server.Listen(0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer listener.Close()
+
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+
+			conn.(*tlsConn).Handshake()
+			conn.Close()
+		}
+	}()
+
+	address := net.JoinHostPort("localhost", strconv.Itoa(listener.Addr().(*net.TCPAddr).Port))
+
+	client := NewTLS(NewTCP(), nil, &tls.Config{InsecureSkipVerify: true, ClientSessionCache: tls.NewLRUClientSessionCache(1)})
+	client.PinnedCertificates = map[string][][]byte{address: {served.Certificate[0]}}
+
+	// Reading processes the session ticket sent by the server.
+	dial := func() (*tls.Conn, error) {
+		conn, err := client.Dial(address)
+		if err != nil {
+			return nil, err
+		}
+		conn.Read(make([]byte, 1))
+		conn.Close()
+		return conn.(*tls.Conn), nil
+	}
+
+	if _, err := dial(); err != nil {
+		t.Fatal(err)
+	}
+
+	conn, err := dial()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !conn.ConnectionState().DidResume {
+		t.Fatal("expected session to be resumed")
+	}
+
+	client.PinnedCertificates[address] = [][]byte{pinned.Certificate[0]}
+
+	if _, err := dial(); err == nil || !strings.Contains(err.Error(), "not pinned") {
+		t.Fatalf("expected resuming a session with a peer whose certificate is no longer pinned to fail, got %v", err)
+	}
+}
+
+func TestTLSHandshakeTimeout(t *testing.T) {
+	t.Parallel()
+
+	server := NewTLS(NewTCP(), &tls.Config{Certificates: []tls.Certificate{generateCertificate(t)}}, nil)
+	server.HandshakeTimeout = 100 * time.Millisecond
+
+	listener, err := server.Listen(0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer listener.Close()
+
+	// The peer never starts its handshake.
+	stalled, err := net.Dial("tcp", listener.Addr().String())
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer stalled.Close()
+
+	conn, err := listener.Accept()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+
+	failed := make(chan error, 1)
+	go func() {
+		_, err := conn.Read(make([]byte, 1))
+		failed <- err
+	}()
+
+	select {
+	case err := <-failed:
+		if err == nil {
+			t.Fatal("expected stalled handshake to fail")
+		}
+	case <-time.After(3 * time.Second):
+		t.Fatal("expected stalled handshake to time out")
+	}
+}
@@ -1,6 +1,12 @@
 package transport
 
-import "net"
+import (
+	"net"
+	"time"
+)
+
+// defaultHandshakeTimeout bounds the handshakes of secure transports, should no timeout be set.
+const defaultHandshakeTimeout = 10 * time.Second
 
 // Layer represents a transport protocol layer.
 type Layer interface {
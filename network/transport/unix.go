@@ -0,0 +1,70 @@
+package transport
+
+import (
+	"net"
+	"os"
+	"path/filepath"
+	"strconv"
+)
+
+// Unix represents a transport protocol over Unix domain sockets for peers on the same
+// host. Ports map to socket files within a directory, such that peers may be addressed
+// as e.g. unix://localhost:3000.
+type Unix struct {
+	// Dir is the directory socket files are created in.
+	Dir string
+}
+
+// NewUnix instantiates a new instance of the Unix domain socket transport protocol,
+// creating socket files within the systems temporary directory.
+func NewUnix() *Unix {
+	return &Unix{
+		Dir: os.TempDir(),
+	}
+}
+
+// Listen listens for incoming connections on the socket file of a specified port.
+func (t *Unix) Listen(port int) (net.Listener, error) {
+	return NewUnixListener(t.path(port))
+}
+
+// Dial dials the socket file of the port of an address.
+func (t *Unix) Dial(address string) (net.Conn, error) {
+	_, rawPort, err := net.SplitHostPort(address)
+	if err != nil {
+		return nil, err
+	}
+
+	port, err := strconv.Atoi(rawPort)
+	if err != nil {
+		return nil, err
+	}
+
+	return DialUnix(t.path(port))
+}
+
+// path returns the path of the socket file of a port.
+func (t *Unix) path(port int) string {
+	return filepath.Join(t.Dir, "noise-"+strconv.Itoa(port)+".sock")
+}
+
+// NewUnixListener listens for incoming connections on a Unix domain socket file. Stale
+// socket files are removed, and the socket file is removed once the listener is closed.
+func NewUnixListener(path string) (net.Listener, error) {
+	if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
+		return nil, err
+	}
+
+	listener, err := net.ListenUnix("unix", &net.UnixAddr{Name: path, Net: "unix"})
+	if err != nil {
+		return nil, err
+	}
+	listener.SetUnlinkOnClose(true)
+
+	return listener, nil
+}
+
+// DialUnix dials a Unix domain socket file.
+func DialUnix(path string) (net.Conn, error) {
+	return net.DialUnix("unix", nil, &net.UnixAddr{Name: path, Net: "unix"})
+}
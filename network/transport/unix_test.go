@@ -0,0 +1,137 @@
+package transport
+
+import (
+	"io"
+	"net"
+	"os"
+	"path/filepath"
+	"strconv"
+	"testing"
+)
+
+func TestUnix(t *testing.T) {
+	t.Parallel()
+
+	dir, err := os.MkdirTemp("", "noise-unix")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	layer := &Unix{Dir: dir}
+
+	listener, err := layer.Listen(3000)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	path := filepath.Join(dir, "noise-3000.sock")
+	if _, err := os.Stat(path); err != nil {
+		t.Fatalf("expected socket file to exist: %v", err)
+	}
+
+	go func() {
+		conn, err := listener.Accept()
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+
+		io.Copy(conn, conn)
+	}()
+
+	conn, err := layer.Dial("localhost:3000")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+
+	if _, err := conn.Write([]byte("ping")); err != nil {
+		t.Fatal(err)
+	}
+
+	echoed := make([]byte, 4)
+	if _, err := io.ReadFull(conn, echoed); err != nil {
+		t.Fatal(err)
+	}
+	if string(echoed) != "ping" {
+		t.Errorf("expected echo of ping, got %q", echoed)
+	}
+
+	listener.Close()
+
+	if _, err := os.Stat(path); !os.IsNotExist(err) {
+		t.Errorf("expected socket file to be removed once the listener is closed, got %v", err)
+	}
+
+	// Stale socket files are replaced.
+	if err := os.WriteFile(path, nil, 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	listener, err = layer.Listen(3000)
+	if err != nil {
+		t.Fatalf("expected stale socket file to be replaced: %v", err)
+	}
+	listener.Close()
+}
+
+// benchmarkPingPong measures the round trip of a message over connections of a transport layer.
+func benchmarkPingPong(b *testing.B, layer Layer, port int, address string) {
+	listener, err := layer.Listen(port)
+	if err != nil {
+		b.Fatal(err)
+	}
+	defer listener.Close()
+
+	go func() {
+		conn, err := listener.Accept()
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+
+		io.Copy(conn, conn)
+	}()
+
+	conn, err := layer.Dial(address)
+	if err != nil {
+		b.Fatal(err)
+	}
+	defer conn.Close()
+
+	message := make([]byte, 1024)
+
+	b.SetBytes(int64(len(message)))
+	b.ResetTimer()
+
+	for i := 0; i < b.N; i++ {
+		if _, err := conn.Write(message); err != nil {
+			b.Fatal(err)
+		}
+		if _, err := io.ReadFull(conn, message); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkUnix(b *testing.B) {
+	dir, err := os.MkdirTemp("", "noise-unix")
+	if err != nil {
+		b.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	benchmarkPingPong(b, &Unix{Dir: dir}, 3000, "localhost:3000")
+}
+
+func BenchmarkTCP(b *testing.B) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		b.Fatal(err)
+	}
+	port := listener.Addr().(*net.TCPAddr).Port
+	listener.Close()
+
+	benchmarkPingPong(b, NewTCP(), port, net.JoinHostPort("127.0.0.1", strconv.Itoa(port)))
+}
@@ -0,0 +1,226 @@
+package transport
+
+import (
+	"io"
+	"net"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/gorilla/websocket"
+	"github.com/pkg/errors"
+)
+
+// defaultWebSocketPath is the HTTP path WebSocket upgrades are served at should none
+// be configured.
+const defaultWebSocketPath = "/"
+
+// WebSocket represents the WebSocket transport protocol. Every write to a connection is
+// sent as a single binary message.
+type WebSocket struct {
+	// Path is the HTTP path WebSocket upgrades are served and dialed at.
+	Path string
+}
+
+// NewWebSocket instantiates a new instance of the WebSocket transport protocol.
+func NewWebSocket() *WebSocket {
+	return &WebSocket{
+		Path: defaultWebSocketPath,
+	}
+}
+
+// Listen listens for incoming WebSocket connections on a specified port.
+func (t *WebSocket) Listen(port int) (net.Listener, error) {
+	return NewWebSocketListener(":"+strconv.Itoa(port), t.path())
+}
+
+// Dial dials an address via. the WebSocket protocol.
+func (t *WebSocket) Dial(address string) (net.Conn, error) {
+	conn, _, err := websocket.DefaultDialer.Dial("ws://"+address+t.path(), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	return &webSocketConn{conn: conn}, nil
+}
+
+func (t *WebSocket) path() string {
+	if len(t.Path) > 0 {
+		return t.Path
+	}
+	return defaultWebSocketPath
+}
+
+var (
+	errWebSocketListenerClosed = errors.New("websocket: listener closed")
+)
+
+// webSocketListener serves WebSocket upgrades over HTTP, and adapts upgraded
+// connections to a net.Listener.
+type webSocketListener struct {
+	listener net.Listener
+	server   *http.Server
+	upgrader websocket.Upgrader
+
+	conns chan net.Conn
+
+	closeOnce sync.Once
+	closed    chan struct{}
+}
+
+// NewWebSocketListener listens for incoming WebSocket connections on an address,
+// serving upgrades at a specified HTTP path.
+func NewWebSocketListener(address, path string) (net.Listener, error) {
+	listener, err := net.Listen("tcp", address)
+	if err != nil {
+		return nil, err
+	}
+
+	l := &webSocketListener{
+		listener: listener,
+		conns:    make(chan net.Conn),
+		closed:   make(chan struct{}),
+	}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc(path, l.upgrade)
+
+	l.server = &http.Server{Handler: mux}
+	go l.server.Serve(listener)
+
+	return l, nil
+}
+
+// upgrade upgrades an HTTP request to a WebSocket connection, and hands it off to be accepted.
+func (l *webSocketListener) upgrade(w http.ResponseWriter, r *http.Request) {
+	conn, err := l.upgrader.Upgrade(w, r, nil)
+	if err != nil {
+		return
+	}
+
+	select {
+	case l.conns <- &webSocketConn{conn: conn}:
+	case <-l.closed:
+		conn.Close()
+	}
+}
+
+// Accept waits for and returns the next upgraded WebSocket connection.
+func (l *webSocketListener) Accept() (net.Conn, error) {
+	select {
+	case conn := <-l.conns:
+		return conn, nil
+	case <-l.closed:
+		return nil, errWebSocketListenerClosed
+	}
+}
+
+// Close stops serving WebSocket upgrades.
+func (l *webSocketListener) Close() error {
+	var err error
+
+	l.closeOnce.Do(func() {
+		close(l.closed)
+		err = l.server.Close()
+	})
+
+	return err
+}
+
+// Addr returns the address the listener is bound to.
+func (l *webSocketListener) Addr() net.Addr {
+	return l.listener.Addr()
+}
+
+// webSocketConn adapts a WebSocket connection to a net.Conn.
+type webSocketConn struct {
+	conn *websocket.Conn
+
+	readMutex sync.Mutex
+	reader    io.Reader
+
+	writeMutex sync.Mutex
+}
+
+// Read reads data off of the current incoming message, waiting for the next message once
+// the current message has been fully read.
+func (c *webSocketConn) Read(out []byte) (int, error) {
+	c.readMutex.Lock()
+	defer c.readMutex.Unlock()
+
+	for {
+		if c.reader == nil {
+			messageType, reader, err := c.conn.NextReader()
+			if err != nil {
+				return 0, err
+			}
+
+			if messageType != websocket.BinaryMessage {
+				continue
+			}
+			c.reader = reader
+		}
+
+		n, err := c.reader.Read(out)
+		if err == io.EOF {
+			c.reader = nil
+
+			if n == 0 {
+				continue
+			}
+			err = nil
+		}
+
+		return n, err
+	}
+}
+
+// Write sends data as a single binary message.
+func (c *webSocketConn) Write(data []byte) (int, error) {
+	c.writeMutex.Lock()
+	defer c.writeMutex.Unlock()
+
+	if err := c.conn.WriteMessage(websocket.BinaryMessage, data); err != nil {
+		return 0, err
+	}
+
+	return len(data), nil
+}
+
+// Close closes the WebSocket connection.
+func (c *webSocketConn) Close() error {
+	return c.conn.Close()
+}
+
+// LocalAddr returns the local address of the connection.
+func (c *webSocketConn) LocalAddr() net.Addr {
+	return c.conn.LocalAddr()
+}
+
+// RemoteAddr returns the remote address of the connection.
+func (c *webSocketConn) RemoteAddr() net.Addr {
+	return c.conn.RemoteAddr()
+}
+
+// SetDeadline sets the read and write deadlines of the connection.
+func (c *webSocketConn) SetDeadline(t time.Time) error {
+	if err := c.conn.SetReadDeadline(t); err != nil {
+		return err
+	}
+	return c.SetWriteDeadline(t)
+}
+
+// SetReadDeadline sets the read deadline of the connection.
+func (c *webSocketConn) SetReadDeadline(t time.Time) error {
+	return c.conn.SetReadDeadline(t)
+}
+
+// SetWriteDeadline sets the write deadline of the connection. WebSocket connections only
+// support one concurrent writer, which setting the write deadline counts as.
+func (c *webSocketConn) SetWriteDeadline(t time.Time) error {
+	c.writeMutex.Lock()
+	defer c.writeMutex.Unlock()
+
+	return c.conn.SetWriteDeadline(t)
+}
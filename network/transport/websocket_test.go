@@ -0,0 +1,75 @@
+package transport
+
+import (
+	"io"
+	"net"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestWebSocketConcurrentWriteDeadlines(t *testing.T) {
+	t.Parallel()
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	port := listener.Addr().(*net.TCPAddr).Port
+	listener.Close()
+
+	layer := NewWebSocket()
+
+	wsListener, err := layer.Listen(port)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer wsListener.Close()
+
+	go func() {
+		conn, err := wsListener.Accept()
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+
+		io.Copy(io.Discard, conn)
+	}()
+
+	conn, err := layer.Dial(net.JoinHostPort("127.0.0.1", strconv.Itoa(port)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+
+	// Write deadlines are set while messages are being written, as peers do upon sending.
+	var wg sync.WaitGroup
+	wg.Add(2)
+
+	go func() {
+		defer wg.Done()
+		for i := 0; i < 100; i++ {
+			if _, err := conn.Write([]byte("ping")); err != nil {
+				t.Error(err)
+				return
+			}
+		}
+	}()
+
+	go func() {
+		defer wg.Done()
+		for i := 0; i < 100; i++ {
+			if err := conn.SetDeadline(time.Now().Add(time.Second)); err != nil {
+				t.Error(err)
+				return
+			}
+			if err := conn.SetWriteDeadline(time.Time{}); err != nil {
+				t.Error(err)
+				return
+			}
+		}
+	}()
+
+	wg.Wait()
+}
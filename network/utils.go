@@ -3,8 +3,10 @@ package network
 import (
 	"encoding/binary"
 	"net"
+	"strings"
 
 	"github.com/perlin-network/noise/internal/protobuf"
+	"github.com/perlin-network/noise/peer"
 )
 
 // SerializeMessage compactly packs all bytes of a message together for cryptographic signing purposes.
@@ -64,3 +66,26 @@ func GetRandomUnusedPort() int {
 	defer listener.Close()
 	return listener.Addr().(*net.TCPAddr).Port
 }
+
+// MultiError aggregates the errors of an operation performed against multiple peers.
+type MultiError []error
+
+// Error joins the messages of all aggregated errors.
+func (e MultiError) Error() string {
+	messages := make([]string, len(e))
+	for i, err := range e {
+		messages[i] = err.Error()
+	}
+	return strings.Join(messages, "; ")
+}
+
+// ConnectError is the error of a failed attempt to connect to a peer.
+type ConnectError struct {
+	Peer peer.ID
+	Err  error
+}
+
+// Error returns the address of the peer alongside the reason for failing to connect.
+func (e ConnectError) Error() string {
+	return e.Peer.Address + ": " + e.Err.Error()
+}
@@ -0,0 +1,42 @@
+package peer
+
+import (
+	"testing"
+
+	"github.com/perlin-network/noise/internal/protobuf"
+
+	"github.com/gogo/protobuf/proto"
+)
+
+// FuzzID feeds arbitrary bytes into the decoding of peer IDs off of the wire, and computes
+// the distance between decoded IDs and a valid ID as routing tables do.
+func FuzzID(f *testing.F) {
+	for _, id := range []ID{id1, id2, {Address: address}, {}} {
+		raw, err := proto.Marshal((*protobuf.ID)(&id))
+		if err != nil {
+			f.Fatal(err)
+		}
+		f.Add(raw)
+	}
+
+	f.Fuzz(func(t *testing.T, data []byte) {
+		decoded := new(protobuf.ID)
+		if err := proto.Unmarshal(data, decoded); err != nil {
+			return
+		}
+
+		id := ID(*decoded)
+
+		_ = id.String()
+		_ = id.ShortString()
+		_ = id.PublicKeyHex()
+		_ = id.Less(id1)
+
+		for _, distance := range []ID{id.XorID(id1), id1.XorID(id), id.Xor(id1), id1.Xor(id)} {
+			if prefixLen := distance.PrefixLen(); prefixLen >= len(distance.Id)*8 && len(distance.Id) > 0 {
+				t.Fatalf("prefix length %d exceeds the bit length of %x", prefixLen, distance.Id)
+			}
+			_ = distance.XorLeadingZeros()
+		}
+	})
+}
@@ -23,11 +23,29 @@ func (id ID) String() string {
 	return fmt.Sprintf("ID{Address: %v, Id: %v}", id.Address, id.Id)
 }
 
+// ShortString returns the first 8 characters of the hex-encoded public key hash
+// of this peer ID, for use in logs and displays.
+func (id ID) ShortString() string {
+	const shortLen = 8
+
+	encoded := hex.EncodeToString(id.Id)
+	if len(encoded) > shortLen {
+		encoded = encoded[:shortLen]
+	}
+	return encoded
+}
+
 // Equals determines if two peer IDs are equal to each other based on the contents of their public keys.
 func (id ID) Equals(other ID) bool {
 	return bytes.Equal(id.Id, other.Id)
 }
 
+// EqualBytes determines if the public key hash of this peer ID equals a raw public key
+// hash, without constructing a peer ID out of it.
+func (id ID) EqualBytes(b []byte) bool {
+	return bytes.Equal(id.Id, b)
+}
+
 // Less determines if this peer ID's public key is less than other ID's public key.
 func (id ID) Less(other interface{}) bool {
 	if other, is := other.(ID); is {
@@ -70,3 +88,15 @@ func (id ID) PrefixLen() int {
 	}
 	return len(id.Id)*8 - 1
 }
+
+// XorLeadingZeros returns the number of leading zero bits in a peer ID's public key
+// hash, for use in verifying the difficulty of a S/Kademlia cryptographic puzzle.
+// Unlike PrefixLen, an ID consisting entirely of zeros yields its full bit length.
+func (id ID) XorLeadingZeros() int {
+	for i, b := range id.Id {
+		if b != 0 {
+			return i*8 + bits.LeadingZeros8(uint8(b))
+		}
+	}
+	return len(id.Id) * 8
+}
@@ -2,9 +2,12 @@ package peer
 
 import (
 	"bytes"
+	"crypto/rand"
+	"crypto/sha256"
 	"encoding/binary"
 	"testing"
 
+	"github.com/perlin-network/noise/crypto"
 	"github.com/perlin-network/noise/crypto/blake2b"
 )
 
@@ -40,6 +43,25 @@ func TestString(t *testing.T) {
 	}
 }
 
+func TestShortString(t *testing.T) {
+	t.Parallel()
+
+	want := "492c7f5c"
+
+	if id1.ShortString() != want {
+		t.Errorf("ShortString() = %s, want %s", id1.ShortString(), want)
+	}
+
+	ids := []ID{id1, id2, id3}
+	for i := range ids {
+		for j := i + 1; j < len(ids); j++ {
+			if ids[i].ShortString() == ids[j].ShortString() {
+				t.Errorf("ShortString() of %v and %v should not be equal", ids[i], ids[j])
+			}
+		}
+	}
+}
+
 func TestEquals(t *testing.T) {
 	t.Parallel()
 
@@ -48,6 +70,18 @@ func TestEquals(t *testing.T) {
 	}
 }
 
+func TestEqualBytes(t *testing.T) {
+	t.Parallel()
+
+	if !id1.EqualBytes(CreateID(address, publicKey1).Id) {
+		t.Errorf("EqualBytes() = false, expected true")
+	}
+
+	if id1.EqualBytes(id2.Id) {
+		t.Errorf("EqualBytes() = true, expected false")
+	}
+}
+
 func TestLess(t *testing.T) {
 	t.Parallel()
 
@@ -134,3 +168,96 @@ func TestPrefixLen(t *testing.T) {
 		}
 	}
 }
+
+func TestXorLeadingZeros(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		publicKeyHash []byte
+		expected      int
+	}{
+		{[]byte{0x80, 0x00}, 0},
+		{[]byte{0x01, 0x00}, 7},
+		{[]byte{0x00, 0x40}, 9},
+		{[]byte{0x00, 0x01}, 15},
+		{[]byte{0x00, 0x00}, 16},
+		{[]byte{}, 0},
+	}
+	for _, tt := range testCases {
+		id := ID{Address: address, Id: tt.publicKeyHash}
+		if id.XorLeadingZeros() != tt.expected {
+			t.Errorf("XorLeadingZeros(%v) expected: %d, value: %d", tt.publicKeyHash, tt.expected, id.XorLeadingZeros())
+		}
+	}
+}
+
+// sha256Policy is a SHA-256 hash policy for comparing the cost of creating IDs.
+type sha256Policy struct{}
+
+func (sha256Policy) HashBytes(b []byte) []byte {
+	hash := sha256.Sum256(b)
+	return hash[:]
+}
+
+// benchmarkCreateID measures the cost of creating IDs whose public keys are hashed
+// under a given hash policy.
+func benchmarkCreateID(b *testing.B, hp crypto.HashPolicy) {
+	publicKey := make([]byte, 32)
+	if _, err := rand.Read(publicKey); err != nil {
+		panic(err)
+	}
+
+	b.ReportAllocs()
+	b.ResetTimer()
+
+	for i := 0; i < b.N; i++ {
+		id := ID{Address: address, PublicKey: publicKey, Id: hp.HashBytes(publicKey)}
+		if len(id.Id) == 0 {
+			panic("hashing failed")
+		}
+	}
+}
+
+func BenchmarkCreateID(b *testing.B) {
+	publicKey := make([]byte, 32)
+	if _, err := rand.Read(publicKey); err != nil {
+		panic(err)
+	}
+
+	b.ReportAllocs()
+	b.ResetTimer()
+
+	for i := 0; i < b.N; i++ {
+		CreateID(address, publicKey)
+	}
+}
+
+func BenchmarkCreateIDBlake2b(b *testing.B) {
+	benchmarkCreateID(b, blake2b.New())
+}
+
+func BenchmarkCreateIDSHA256(b *testing.B) {
+	benchmarkCreateID(b, sha256Policy{})
+}
+
+func BenchmarkEquals(b *testing.B) {
+	raw := append([]byte(nil), id1.Id...)
+
+	b.ReportAllocs()
+	b.ResetTimer()
+
+	for i := 0; i < b.N; i++ {
+		id1.Equals(ID{Id: raw})
+	}
+}
+
+func BenchmarkEqualBytes(b *testing.B) {
+	raw := append([]byte(nil), id1.Id...)
+
+	b.ReportAllocs()
+	b.ResetTimer()
+
+	for i := 0; i < b.N; i++ {
+		id1.EqualBytes(raw)
+	}
+}
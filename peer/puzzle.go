@@ -0,0 +1,32 @@
+package peer
+
+import (
+	"github.com/perlin-network/noise/crypto"
+	"github.com/perlin-network/noise/crypto/blake2b"
+	"github.com/perlin-network/noise/crypto/ed25519"
+)
+
+// DefaultCryptopuzzleDifficulty is the default number of leading zero bits required
+// by the S/Kademlia static cryptographic puzzle.
+const DefaultCryptopuzzleDifficulty = 8
+
+// IsValidKeyPair checks whether a public key solves the S/Kademlia static cryptographic
+// puzzle, in which the hash of a peers ID must have at least c1 leading zero bits.
+func IsValidKeyPair(publicKey []byte, c1 int) bool {
+	hp := blake2b.New()
+	puzzle := ID{Id: hp.HashBytes(hp.HashBytes(publicKey))}
+
+	return puzzle.XorLeadingZeros() >= c1
+}
+
+// GenerateKeyPairAndID mines ed25519 key pairs until one solves the S/Kademlia static
+// cryptographic puzzle at a given difficulty, and returns it alongside its peer ID.
+func GenerateKeyPairAndID(address string, difficulty int) (*crypto.KeyPair, ID) {
+	for {
+		keys := ed25519.RandomKeyPair()
+
+		if IsValidKeyPair(keys.PublicKey, difficulty) {
+			return keys, CreateID(address, keys.PublicKey)
+		}
+	}
+}
@@ -0,0 +1,46 @@
+package peer
+
+import (
+	"testing"
+
+	"github.com/perlin-network/noise/crypto/ed25519"
+)
+
+func TestGenerateKeyPairAndID(t *testing.T) {
+	t.Parallel()
+
+	const difficulty = 4
+
+	for i := 0; i < 10; i++ {
+		keys, id := GenerateKeyPairAndID(address, difficulty)
+
+		if !IsValidKeyPair(keys.PublicKey, difficulty) {
+			t.Errorf("IsValidKeyPair(%x, %d) = false, expected true", keys.PublicKey, difficulty)
+		}
+
+		if !id.Equals(CreateID(address, keys.PublicKey)) {
+			t.Errorf("GenerateKeyPairAndID() returned ID %v which does not belong to its key pair", id)
+		}
+	}
+}
+
+func TestIsValidKeyPair(t *testing.T) {
+	t.Parallel()
+
+	// Any public key solves a puzzle of no difficulty.
+	if !IsValidKeyPair(ed25519.RandomKeyPair().PublicKey, 0) {
+		t.Error("IsValidKeyPair() = false, expected true at difficulty 0")
+	}
+
+	// A key pair mined at a difficulty solves all easier puzzles.
+	keys, _ := GenerateKeyPairAndID(address, 6)
+	for c1 := 0; c1 <= 6; c1++ {
+		if !IsValidKeyPair(keys.PublicKey, c1) {
+			t.Errorf("IsValidKeyPair(%x, %d) = false, expected true", keys.PublicKey, c1)
+		}
+	}
+
+	if IsValidKeyPair(keys.PublicKey, 257) {
+		t.Error("IsValidKeyPair() = true, expected false at a difficulty exceeding the hash size")
+	}
+}
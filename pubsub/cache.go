@@ -0,0 +1,42 @@
+package pubsub
+
+import "sync"
+
+// seenCache is a fixed-size ring buffer of the IDs of messages which have been seen, such
+// that duplicate messages may be dropped. The oldest ID is evicted once the buffer is full.
+type seenCache struct {
+	sync.Mutex
+
+	ids  []string
+	set  map[string]struct{}
+	next int
+}
+
+func newSeenCache(size int) *seenCache {
+	return &seenCache{
+		ids: make([]string, size),
+		set: make(map[string]struct{}, size),
+	}
+}
+
+// add marks an ID as seen. Returns false should the ID have already been seen.
+func (c *seenCache) add(id []byte) bool {
+	c.Lock()
+	defer c.Unlock()
+
+	key := string(id)
+
+	if _, seen := c.set[key]; seen {
+		return false
+	}
+
+	if evicted := c.ids[c.next]; len(evicted) > 0 {
+		delete(c.set, evicted)
+	}
+
+	c.ids[c.next] = key
+	c.set[key] = struct{}{}
+	c.next = (c.next + 1) % len(c.ids)
+
+	return true
+}
@@ -0,0 +1,364 @@
+package pubsub
+
+import (
+	"context"
+	"crypto/rand"
+	mrand "math/rand"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/perlin-network/noise/internal/protobuf"
+	"github.com/perlin-network/noise/network"
+	"github.com/perlin-network/noise/peer"
+
+	"github.com/pkg/errors"
+)
+
+const (
+	// DefaultGossipFactor is the default number of subscribers a message is gossiped to per node.
+	DefaultGossipFactor = 6
+	// DefaultGossipInterval is the default interval in between gossip rounds.
+	DefaultGossipInterval = 100 * time.Millisecond
+	// DefaultHeartbeatInterval is the default interval in between subscription heartbeats.
+	DefaultHeartbeatInterval = 1 * time.Second
+	// DefaultSeenCacheSize is the default number of message IDs remembered for deduplication.
+	DefaultSeenCacheSize = 4096
+
+	// subscriptionTimeoutFactor is the number of heartbeat intervals after which a peer is no
+	// longer considered subscribed to a topic, should it not have sent a heartbeat.
+	subscriptionTimeoutFactor = 3
+
+	messageIDSize = 16
+)
+
+// ErrEmptyTopic returns if a message is published to, or a handler subscribed to, an empty topic.
+var ErrEmptyTopic = errors.New("pubsub: topic must not be empty")
+
+type subscriber struct {
+	id       peer.ID
+	lastSeen time.Time
+}
+
+type pending struct {
+	msg  *protobuf.Publish
+	from string // the public key hex of the peer the message was received from
+}
+
+// GossipPlugin implements topic-based publish/subscribe messaging through gossip. Nodes
+// periodically send a heartbeat listing the topics they are subscribed to, from which
+// every node tracks the subscribers of each topic. Every gossip round, messages which were
+// published or received in the previous round are pushed to a random subset of the
+// subscribers of their topic. Messages are delivered to, and gossiped by, a node once.
+type GossipPlugin struct {
+	*network.Plugin
+
+	// GossipFactor is the number of subscribers a message is gossiped to per node (default: DefaultGossipFactor).
+	GossipFactor int
+	// GossipInterval is the interval in between gossip rounds (default: DefaultGossipInterval).
+	GossipInterval time.Duration
+	// HeartbeatInterval is the interval in between subscription heartbeats (default: DefaultHeartbeatInterval).
+	HeartbeatInterval time.Duration
+	// SeenCacheSize is the number of message IDs remembered for deduplication (default: DefaultSeenCacheSize).
+	SeenCacheSize int
+
+	net  *network.Network
+	seen *seenCache
+
+	mutex       sync.Mutex
+	handlers    map[string][]func([]byte)
+	subscribers map[string]map[string]*subscriber // topic -> public key hex -> subscriber
+	outbox      []pending
+
+	stop chan struct{}
+}
+
+var (
+	PluginID                         = (*GossipPlugin)(nil)
+	_        network.PluginInterface = (*GossipPlugin)(nil)
+)
+
+func (state *GossipPlugin) Startup(net *network.Network) {
+	state.net = net
+	state.seen = newSeenCache(state.seenCacheSize())
+
+	state.mutex.Lock()
+	if state.handlers == nil {
+		state.handlers = make(map[string][]func([]byte))
+	}
+	state.subscribers = make(map[string]map[string]*subscriber)
+	state.mutex.Unlock()
+
+	state.stop = make(chan struct{})
+	go state.gossipLoop(state.stop)
+	go state.heartbeatLoop(state.stop)
+}
+
+func (state *GossipPlugin) Cleanup(net *network.Network) {
+	if state.stop != nil {
+		close(state.stop)
+		state.stop = nil
+	}
+}
+
+func (state *GossipPlugin) Receive(ctx *network.PluginContext) error {
+	switch msg := ctx.Message().(type) {
+	case *protobuf.Subscribe:
+		state.updateSubscriptions(ctx.Sender(), msg.Topics)
+	case *protobuf.Publish:
+		if len(msg.Id) == 0 || len(msg.Topic) == 0 || !state.seen.add(msg.Id) {
+			return nil
+		}
+
+		state.deliver(msg)
+		state.enqueue(msg, ctx.Sender().PublicKeyHex())
+	}
+
+	return nil
+}
+
+// Subscribe registers a handler which is called with the data of every message published
+// to a topic, and announces the subscription to all peers. Handlers are called
+// synchronously as messages are received, and thus should not block.
+func (state *GossipPlugin) Subscribe(topic string, handler func([]byte)) error {
+	if len(topic) == 0 {
+		return ErrEmptyTopic
+	}
+
+	state.mutex.Lock()
+	if state.handlers == nil {
+		state.handlers = make(map[string][]func([]byte))
+	}
+	state.handlers[topic] = append(state.handlers[topic], handler)
+	state.mutex.Unlock()
+
+	if state.net != nil {
+		state.heartbeat()
+	}
+
+	return nil
+}
+
+// Unsubscribe removes all handlers of a topic. Peers stop gossiping messages of the topic
+// to the node once its subscription times out.
+func (state *GossipPlugin) Unsubscribe(topic string) {
+	state.mutex.Lock()
+	delete(state.handlers, topic)
+	state.mutex.Unlock()
+}
+
+// Publish gossips data to all subscribers of a topic, starting from the next gossip round.
+// Data published to a topic the node is subscribed to is delivered to the node as well.
+func (state *GossipPlugin) Publish(topic string, data []byte) error {
+	if len(topic) == 0 {
+		return ErrEmptyTopic
+	}
+
+	id := make([]byte, messageIDSize)
+	if _, err := rand.Read(id); err != nil {
+		return errors.Wrap(err, "pubsub: failed to generate message id")
+	}
+
+	msg := &protobuf.Publish{Id: id, Topic: topic, Data: data}
+
+	state.seen.add(id)
+	state.deliver(msg)
+	state.enqueue(msg, "")
+
+	return nil
+}
+
+// Subscribers returns the peers known to be subscribed to a topic, sorted by address.
+func (state *GossipPlugin) Subscribers(topic string) []peer.ID {
+	state.mutex.Lock()
+	var peers []peer.ID
+	for _, sub := range state.subscribers[topic] {
+		if !state.expired(sub) {
+			peers = append(peers, sub.id)
+		}
+	}
+	state.mutex.Unlock()
+
+	sort.Slice(peers, func(i, j int) bool {
+		return peers[i].Address < peers[j].Address
+	})
+
+	return peers
+}
+
+// deliver calls the handlers of the topic of a message.
+func (state *GossipPlugin) deliver(msg *protobuf.Publish) {
+	state.mutex.Lock()
+	handlers := state.handlers[msg.Topic]
+	state.mutex.Unlock()
+
+	for _, handler := range handlers {
+		handler(msg.Data)
+	}
+}
+
+// enqueue queues a message to be gossiped in the next gossip round.
+func (state *GossipPlugin) enqueue(msg *protobuf.Publish, from string) {
+	state.mutex.Lock()
+	state.outbox = append(state.outbox, pending{msg: msg, from: from})
+	state.mutex.Unlock()
+}
+
+// gossipLoop gossips queued messages every gossip round, as timed by the networks clock.
+func (state *GossipPlugin) gossipLoop(stop chan struct{}) {
+	for {
+		select {
+		case <-stop:
+			return
+		case <-state.net.Clock().After(state.gossipInterval()):
+		}
+
+		state.mutex.Lock()
+		outbox := state.outbox
+		state.outbox = nil
+		state.mutex.Unlock()
+
+		for _, p := range outbox {
+			for _, target := range state.gossipTargets(p.msg.Topic, p.from) {
+				client, err := state.net.Client(target.Address)
+				if err != nil {
+					continue
+				}
+				client.Tell(context.Background(), p.msg)
+			}
+		}
+	}
+}
+
+// gossipTargets returns up to GossipFactor random subscribers of a topic, excluding the
+// peer a message was received from.
+func (state *GossipPlugin) gossipTargets(topic string, from string) []peer.ID {
+	state.mutex.Lock()
+	var candidates []peer.ID
+	for key, sub := range state.subscribers[topic] {
+		if key != from && !state.expired(sub) {
+			candidates = append(candidates, sub.id)
+		}
+	}
+	state.mutex.Unlock()
+
+	mrand.Shuffle(len(candidates), func(i, j int) {
+		candidates[i], candidates[j] = candidates[j], candidates[i]
+	})
+
+	if factor := state.gossipFactor(); len(candidates) > factor {
+		candidates = candidates[:factor]
+	}
+
+	return candidates
+}
+
+// heartbeatLoop announces the topics the node is subscribed to every heartbeat interval,
+// as timed by the networks clock.
+func (state *GossipPlugin) heartbeatLoop(stop chan struct{}) {
+	for {
+		select {
+		case <-stop:
+			return
+		case <-state.net.Clock().After(state.heartbeatInterval()):
+		}
+
+		state.heartbeat()
+		state.pruneSubscribers()
+	}
+}
+
+// heartbeat announces the topics the node is subscribed to to all peers.
+func (state *GossipPlugin) heartbeat() {
+	state.mutex.Lock()
+	topics := make([]string, 0, len(state.handlers))
+	for topic := range state.handlers {
+		topics = append(topics, topic)
+	}
+	state.mutex.Unlock()
+
+	sort.Strings(topics)
+
+	state.net.Broadcast(context.Background(), &protobuf.Subscribe{Topics: topics})
+}
+
+// updateSubscriptions records the topics a peer announced being subscribed to, and removes
+// the peer as a subscriber of all other topics.
+func (state *GossipPlugin) updateSubscriptions(id peer.ID, topics []string) {
+	key := id.PublicKeyHex()
+	now := state.net.Clock().Now()
+
+	announced := make(map[string]struct{}, len(topics))
+
+	state.mutex.Lock()
+	defer state.mutex.Unlock()
+
+	for _, topic := range topics {
+		announced[topic] = struct{}{}
+
+		subs, ok := state.subscribers[topic]
+		if !ok {
+			subs = make(map[string]*subscriber)
+			state.subscribers[topic] = subs
+		}
+		subs[key] = &subscriber{id: id, lastSeen: now}
+	}
+
+	for topic, subs := range state.subscribers {
+		if _, ok := announced[topic]; !ok {
+			delete(subs, key)
+		}
+	}
+}
+
+// pruneSubscribers removes subscribers whose subscriptions timed out.
+func (state *GossipPlugin) pruneSubscribers() {
+	state.mutex.Lock()
+	defer state.mutex.Unlock()
+
+	for topic, subs := range state.subscribers {
+		for key, sub := range subs {
+			if state.expired(sub) {
+				delete(subs, key)
+			}
+		}
+
+		if len(subs) == 0 {
+			delete(state.subscribers, topic)
+		}
+	}
+}
+
+// expired returns true if a subscriber has not sent a heartbeat for too long.
+func (state *GossipPlugin) expired(sub *subscriber) bool {
+	timeout := time.Duration(subscriptionTimeoutFactor) * state.heartbeatInterval()
+	return state.net.Clock().Now().Sub(sub.lastSeen) > timeout
+}
+
+func (state *GossipPlugin) gossipFactor() int {
+	if state.GossipFactor > 0 {
+		return state.GossipFactor
+	}
+	return DefaultGossipFactor
+}
+
+func (state *GossipPlugin) gossipInterval() time.Duration {
+	if state.GossipInterval > 0 {
+		return state.GossipInterval
+	}
+	return DefaultGossipInterval
+}
+
+func (state *GossipPlugin) heartbeatInterval() time.Duration {
+	if state.HeartbeatInterval > 0 {
+		return state.HeartbeatInterval
+	}
+	return DefaultHeartbeatInterval
+}
+
+func (state *GossipPlugin) seenCacheSize() int {
+	if state.SeenCacheSize > 0 {
+		return state.SeenCacheSize
+	}
+	return DefaultSeenCacheSize
+}
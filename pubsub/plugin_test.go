@@ -0,0 +1,84 @@
+package pubsub_test
+
+import (
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/perlin-network/noise/network"
+	"github.com/perlin-network/noise/pubsub"
+	"github.com/perlin-network/noise/testutil"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGossip(t *testing.T) {
+	t.Parallel()
+
+	const (
+		size           = 10
+		subscribers    = 8
+		gossipInterval = 200 * time.Millisecond
+	)
+
+	plugins := make([]*pubsub.GossipPlugin, size)
+
+	opts := testutil.WithBuilderOptions(network.WriteFlushLatency(time.Millisecond))
+
+	cluster, err := testutil.NewCluster(size, opts, testutil.WithSetup(func(i int, builder *network.Builder) {
+		plugins[i] = &pubsub.GossipPlugin{
+			GossipInterval:    gossipInterval,
+			HeartbeatInterval: 100 * time.Millisecond,
+		}
+		builder.AddPlugin(plugins[i])
+	}))
+	assert.Nil(t, err)
+	defer cluster.Stop()
+
+	var mutex sync.Mutex
+	received := make(map[int]int)
+
+	// The last nodes of the cluster are not subscribed to the topic.
+	for i := 0; i < subscribers; i++ {
+		i := i
+
+		assert.Nil(t, plugins[i].Subscribe("topic", func(data []byte) {
+			assert.Equal(t, []byte("data"), data)
+
+			mutex.Lock()
+			received[i]++
+			mutex.Unlock()
+		}))
+	}
+
+	// Every node learns of the subscribers of the topic through heartbeats.
+	deadline := time.Now().Add(5 * time.Second)
+	for i := 0; i < size; i++ {
+		expected := subscribers
+		if i < subscribers {
+			expected--
+		}
+
+		for len(plugins[i].Subscribers("topic")) < expected {
+			if time.Now().After(deadline) {
+				t.Fatalf("expected node %d to know of %d subscribers, got %d", i, expected, len(plugins[i].Subscribers("topic")))
+			}
+			time.Sleep(50 * time.Millisecond)
+		}
+	}
+
+	assert.Equal(t, pubsub.ErrEmptyTopic, plugins[size-1].Publish("", []byte("data")))
+	assert.Nil(t, plugins[size-1].Publish("topic", []byte("data")))
+
+	// Each gossip round starts within a gossip interval of one another, and every
+	// subscriber receives the message within 3 rounds.
+	time.Sleep(3*gossipInterval + gossipInterval/2)
+
+	mutex.Lock()
+	defer mutex.Unlock()
+
+	for i := 0; i < subscribers; i++ {
+		assert.Equal(t, 1, received[i], "expected subscriber %d to receive the message exactly once", i)
+	}
+	assert.Len(t, received, subscribers)
+}
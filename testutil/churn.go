@@ -0,0 +1,162 @@
+package testutil
+
+import (
+	"math/rand"
+	"sync"
+	"time"
+
+	"github.com/perlin-network/noise/network"
+	"github.com/perlin-network/noise/network/discovery"
+)
+
+// churnBootstrapPeers is the number of running networks joining networks bootstrap off of.
+const churnBootstrapPeers = 3
+
+// ChurnStats aggregates the churn a ChurnSimulator simulated.
+type ChurnStats struct {
+	// MaxConcurrentPeers is the largest number of networks running at once.
+	MaxConcurrentPeers int
+
+	// TotalJoins is the number of networks which joined.
+	TotalJoins int
+
+	// TotalLeaves is the number of networks which left.
+	TotalLeaves int
+}
+
+// ChurnSimulator simulates networks continuously joining and leaving a PartitionableNetwork.
+// Joins and leaves are spaced apart by exponentially distributed intervals, such that they
+// occur at their given rates on average.
+type ChurnSimulator struct {
+	Network *PartitionableNetwork
+
+	// JoinRate is the average number of networks which join per second.
+	JoinRate float64
+
+	// LeaveRate is the average number of networks which leave per second. At least one
+	// network is always kept running.
+	LeaveRate float64
+
+	// NewBuilder returns a builder of a joining network, which should be created with
+	// Network.NewBuilder (default: builders of networks with a discovery plugin).
+	NewBuilder func() *network.Builder
+
+	mutex sync.Mutex
+	stats ChurnStats
+}
+
+// NewChurnSimulator instantiates a simulator of networks joining and leaving a network at
+// given rates per second.
+func NewChurnSimulator(net *PartitionableNetwork, joinRate, leaveRate float64) *ChurnSimulator {
+	return &ChurnSimulator{
+		Network:   net,
+		JoinRate:  joinRate,
+		LeaveRate: leaveRate,
+	}
+}
+
+// Run simulates churn for a duration of time, and returns the churn simulated. Joining
+// networks bootstrap off of random running networks. Errors should a joining network
+// fail to be built.
+func (s *ChurnSimulator) Run(duration time.Duration) (ChurnStats, error) {
+	s.mutex.Lock()
+	s.stats = ChurnStats{MaxConcurrentPeers: len(s.Network.Running())}
+	s.mutex.Unlock()
+
+	stop := time.After(duration)
+	done := make(chan struct{})
+
+	var wg sync.WaitGroup
+	var joinErr error
+
+	simulate := func(rate float64, event func() error) {
+		defer wg.Done()
+
+		if rate <= 0 {
+			return
+		}
+
+		for {
+			select {
+			case <-done:
+				return
+			case <-time.After(time.Duration(rand.ExpFloat64() / rate * float64(time.Second))):
+			}
+
+			if err := event(); err != nil {
+				joinErr = err
+				return
+			}
+		}
+	}
+
+	wg.Add(2)
+	go simulate(s.JoinRate, s.join)
+	go simulate(s.LeaveRate, s.leave)
+
+	<-stop
+	close(done)
+	wg.Wait()
+
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	return s.stats, joinErr
+}
+
+// join builds a network, and bootstraps it off of random running networks.
+func (s *ChurnSimulator) join() error {
+	var builder *network.Builder
+
+	if s.NewBuilder != nil {
+		builder = s.NewBuilder()
+	} else {
+		builder = s.Network.NewBuilder()
+		builder.AddPlugin(new(discovery.Plugin))
+	}
+
+	running := s.Network.Running()
+
+	index, err := s.Network.Add(builder)
+	if err != nil {
+		return err
+	}
+
+	// Bootstrap off of several networks, should one leave before replying.
+	var addresses []string
+	for _, i := range rand.Perm(len(running)) {
+		if len(addresses) == churnBootstrapPeers {
+			break
+		}
+		addresses = append(addresses, s.Network.Node(running[i]).Address)
+	}
+
+	s.Network.Node(index).Bootstrap(addresses...)
+
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	s.stats.TotalJoins++
+
+	if concurrent := len(s.Network.Running()); concurrent > s.stats.MaxConcurrentPeers {
+		s.stats.MaxConcurrentPeers = concurrent
+	}
+
+	return nil
+}
+
+// leave removes a random running network, should more than one network be running.
+func (s *ChurnSimulator) leave() error {
+	running := s.Network.Running()
+	if len(running) <= 1 {
+		return nil
+	}
+
+	s.Network.Remove(running[rand.Intn(len(running))])
+
+	s.mutex.Lock()
+	s.stats.TotalLeaves++
+	s.mutex.Unlock()
+
+	return nil
+}
@@ -0,0 +1,82 @@
+package testutil
+
+import (
+	"testing"
+	"time"
+
+	"github.com/perlin-network/noise/dht"
+	"github.com/perlin-network/noise/network"
+	"github.com/perlin-network/noise/network/discovery"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestChurnSimulator(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping churn simulation in short mode")
+	}
+
+	t.Parallel()
+
+	net := NewPartitionableNetwork()
+	defer net.Close()
+
+	newBuilder := func() *network.Builder {
+		builder := net.NewBuilder()
+		builder.AddPlugin(&discovery.Plugin{RefreshInterval: 250 * time.Millisecond})
+		return builder
+	}
+
+	for i := 0; i < 20; i++ {
+		index, err := net.Add(newBuilder())
+		assert.Nil(t, err)
+
+		if index > 0 {
+			net.Node(index).Bootstrap(net.Node(0).Address)
+		}
+	}
+
+	simulator := NewChurnSimulator(net, 1, 1)
+	simulator.NewBuilder = newBuilder
+
+	stats, err := simulator.Run(30 * time.Second)
+	assert.Nil(t, err)
+
+	assert.True(t, stats.TotalJoins > 0, "expected networks to join")
+	assert.True(t, stats.TotalLeaves > 0, "expected networks to leave")
+	assert.True(t, stats.MaxConcurrentPeers >= 20, "expected at least the initial networks to run at once")
+	assert.Equal(t, 20+stats.TotalJoins-stats.TotalLeaves, len(net.Running()))
+
+	// Every running network should eventually either route every other running network, or
+	// be able to look it up through the networks it routes.
+	converged := func(i, j int) bool {
+		plugin, _ := net.Node(i).Plugin(discovery.PluginID)
+		target := net.Node(j).ID
+
+		if plugin.(*discovery.Plugin).Routes.PeerExists(target) {
+			return true
+		}
+
+		for _, id := range discovery.FindNode(net.Node(i), target, dht.BucketSize, 8) {
+			if id.Equals(target) {
+				return true
+			}
+		}
+
+		return false
+	}
+
+	deadline := time.Now().Add(15 * time.Second)
+
+	running := net.Running()
+	for _, i := range running {
+		for _, j := range running {
+			for i != j && !converged(i, j) {
+				if time.Now().After(deadline) {
+					t.Fatalf("expected routing tables to converge, node %d is unable to find node %d", i, j)
+				}
+				time.Sleep(250 * time.Millisecond)
+			}
+		}
+	}
+}
@@ -0,0 +1,171 @@
+package testutil
+
+import (
+	"time"
+
+	"github.com/perlin-network/noise/network"
+	"github.com/perlin-network/noise/network/discovery"
+
+	"github.com/pkg/errors"
+)
+
+// defaultClusterBootstrapTimeout is how long a cluster waits for its networks to bootstrap.
+const defaultClusterBootstrapTimeout = 5 * time.Second
+
+// ClusterOption sets options such as the plugins and builder options of networks of a cluster.
+type ClusterOption func(*clusterOptions)
+
+type clusterOptions struct {
+	builderOptions []network.BuilderOption
+	setup          func(i int, builder *network.Builder)
+	discovery      func(i int) *discovery.Plugin
+
+	bootstrap        bool
+	bootstrapTimeout time.Duration
+}
+
+// WithBuilderOptions returns a ClusterOption that builds every network with builder options.
+func WithBuilderOptions(opts ...network.BuilderOption) ClusterOption {
+	return func(o *clusterOptions) {
+		o.builderOptions = append(o.builderOptions, opts...)
+	}
+}
+
+// WithSetup returns a ClusterOption that has the builder of the i'th network be set up
+// before it is built, e.g. with keys or additional plugins.
+func WithSetup(setup func(i int, builder *network.Builder)) ClusterOption {
+	return func(o *clusterOptions) {
+		o.setup = setup
+	}
+}
+
+// WithDiscovery returns a ClusterOption that registers the discovery plugin returned for
+// the i'th network (default: a discovery plugin with default options). No discovery plugin
+// is registered should nil be returned.
+func WithDiscovery(plugin func(i int) *discovery.Plugin) ClusterOption {
+	return func(o *clusterOptions) {
+		o.discovery = plugin
+	}
+}
+
+// WithoutBootstrap returns a ClusterOption that leaves the networks of a cluster unconnected.
+func WithoutBootstrap() ClusterOption {
+	return func(o *clusterOptions) {
+		o.bootstrap = false
+	}
+}
+
+// Cluster is a set of in-process networks sharing a PartitionableNetwork, each of which
+// has a discovery plugin registered.
+type Cluster struct {
+	*PartitionableNetwork
+
+	plugins []*discovery.Plugin
+}
+
+// NewCluster creates and starts n networks sharing a PartitionableNetwork, and has every
+// network bootstrap off of the first network. Blocks until the first network routes every
+// other network and vice versa, unless WithoutBootstrap is given or a network has no
+// discovery plugin registered.
+func NewCluster(n int, opts ...ClusterOption) (*Cluster, error) {
+	o := clusterOptions{
+		discovery: func(int) *discovery.Plugin {
+			return new(discovery.Plugin)
+		},
+		bootstrap:        true,
+		bootstrapTimeout: defaultClusterBootstrapTimeout,
+	}
+
+	for _, opt := range opts {
+		opt(&o)
+	}
+
+	c := &Cluster{PartitionableNetwork: NewPartitionableNetwork()}
+
+	for i := 0; i < n; i++ {
+		builder := c.NewBuilder(o.builderOptions...)
+
+		plugin := o.discovery(i)
+		if plugin != nil {
+			if err := builder.AddPlugin(plugin); err != nil {
+				c.Stop()
+				return nil, err
+			}
+		}
+
+		if o.setup != nil {
+			o.setup(i, builder)
+		}
+
+		if _, err := c.Add(builder); err != nil {
+			c.Stop()
+			return nil, errors.Wrapf(err, "cluster: failed to build network %d", i)
+		}
+
+		c.plugins = append(c.plugins, plugin)
+	}
+
+	if o.bootstrap {
+		if err := c.bootstrap(o.bootstrapTimeout); err != nil {
+			c.Stop()
+			return nil, err
+		}
+	}
+
+	return c, nil
+}
+
+// bootstrap has every network bootstrap off of the first network, and waits until they route one another.
+func (c *Cluster) bootstrap(timeout time.Duration) error {
+	deadline := time.Now().Add(timeout)
+
+	for i := 1; i < len(c.plugins); i++ {
+		if err := c.waitUntilRouted(0, i, deadline); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// Connect restores the link between the networks at two indices should it be cut, and
+// blocks until they route one another should they both have a discovery plugin.
+func (c *Cluster) Connect(a, b int) error {
+	if err := c.PartitionableNetwork.Connect(a, b); err != nil {
+		return err
+	}
+
+	return c.waitUntilRouted(a, b, time.Now().Add(defaultClusterBootstrapTimeout))
+}
+
+// waitUntilRouted has the b'th network bootstrap off of the a'th network, and waits until
+// they route one another. Networks without a discovery plugin are not waited on.
+func (c *Cluster) waitUntilRouted(a, b int, deadline time.Time) error {
+	if c.plugins[a] == nil || c.plugins[b] == nil {
+		return nil
+	}
+
+	nodeA, nodeB := c.Node(a), c.Node(b)
+
+	nodeB.Bootstrap(nodeA.Address)
+
+	for !c.plugins[a].Routes.PeerExists(nodeB.ID) || !c.plugins[b].Routes.PeerExists(nodeA.ID) {
+		if time.Now().After(deadline) {
+			return errors.Errorf("cluster: networks %d and %d failed to route one another", a, b)
+		}
+
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	return nil
+}
+
+// Plugin returns the discovery plugin of the i'th network, or nil should it have none.
+func (c *Cluster) Plugin(i int) *discovery.Plugin {
+	return c.plugins[i]
+}
+
+// Stop closes all networks of the cluster.
+func (c *Cluster) Stop() {
+	c.Close()
+}
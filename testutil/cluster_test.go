@@ -0,0 +1,37 @@
+package testutil
+
+import (
+	"testing"
+
+	"github.com/perlin-network/noise/network"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewCluster(t *testing.T) {
+	t.Parallel()
+
+	cluster, err := NewCluster(4)
+	assert.Nil(t, err)
+
+	seed := cluster.Node(0)
+
+	for i, node := range cluster.Nodes()[1:] {
+		assert.True(t, cluster.Plugin(0).Routes.PeerExists(node.ID), "expected seed to route node %d", i+1)
+		assert.True(t, cluster.Plugin(i+1).Routes.PeerExists(seed.ID), "expected node %d to route the seed", i+1)
+	}
+
+	cluster.Stop()
+	assert.Empty(t, cluster.Running())
+}
+
+func TestNewClusterInvalid(t *testing.T) {
+	t.Parallel()
+
+	_, err := NewCluster(2, WithSetup(func(i int, builder *network.Builder) {
+		builder.SetConfig(map[string]interface{}{
+			"discovery": map[string]interface{}{"bucket_size": "twenty"},
+		})
+	}))
+	assert.NotNil(t, err)
+}
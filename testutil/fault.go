@@ -0,0 +1,97 @@
+package testutil
+
+import (
+	"encoding/binary"
+	"net"
+	"sync/atomic"
+
+	"github.com/perlin-network/noise/internal/protobuf"
+	"github.com/perlin-network/noise/network/transport"
+	"github.com/perlin-network/noise/types/opcode"
+
+	"github.com/gogo/protobuf/proto"
+)
+
+// FaultInjectionConn wraps a connection such that every write for which drop returns true
+// is discarded, as though it were lost on the wire. Discarded writes are reported as
+// successful.
+func FaultInjectionConn(conn net.Conn, drop func(b []byte) bool) net.Conn {
+	return &faultConn{Conn: conn, drop: drop}
+}
+
+// FaultInjectionLayer wraps a transport layer such that every connection it dials or
+// accepts is a FaultInjectionConn.
+func FaultInjectionLayer(layer transport.Layer, drop func(b []byte) bool) transport.Layer {
+	return &faultLayer{Layer: layer, drop: drop}
+}
+
+// DropMessages returns a drop func for FaultInjectionConn which discards the first count
+// writes of a message with an opcode. Only writes holding a single message are matched,
+// and thus nodes should be built with a write buffer too small to coalesce messages (e.g.
+// WriteBufferSize(1)), and without compression.
+func DropMessages(code opcode.Opcode, count int) func(b []byte) bool {
+	var dropped int64
+
+	return func(b []byte) bool {
+		if len(b) < 4 || int(binary.BigEndian.Uint32(b)) != len(b)-4 {
+			return false
+		}
+
+		var msg protobuf.Message
+		if err := proto.Unmarshal(b[4:], &msg); err != nil || msg.Opcode != uint32(code) {
+			return false
+		}
+
+		return atomic.AddInt64(&dropped, 1) <= int64(count)
+	}
+}
+
+type faultConn struct {
+	net.Conn
+
+	drop func(b []byte) bool
+}
+
+// Write discards b should the connections drop func return true for it.
+func (c *faultConn) Write(b []byte) (int, error) {
+	if c.drop(b) {
+		return len(b), nil
+	}
+	return c.Conn.Write(b)
+}
+
+type faultListener struct {
+	net.Listener
+
+	drop func(b []byte) bool
+}
+
+func (l *faultListener) Accept() (net.Conn, error) {
+	conn, err := l.Listener.Accept()
+	if err != nil {
+		return nil, err
+	}
+	return FaultInjectionConn(conn, l.drop), nil
+}
+
+type faultLayer struct {
+	transport.Layer
+
+	drop func(b []byte) bool
+}
+
+func (l *faultLayer) Listen(port int) (net.Listener, error) {
+	listener, err := l.Layer.Listen(port)
+	if err != nil {
+		return nil, err
+	}
+	return &faultListener{Listener: listener, drop: l.drop}, nil
+}
+
+func (l *faultLayer) Dial(address string) (net.Conn, error) {
+	conn, err := l.Layer.Dial(address)
+	if err != nil {
+		return nil, err
+	}
+	return FaultInjectionConn(conn, l.drop), nil
+}
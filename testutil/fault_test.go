@@ -0,0 +1,38 @@
+package testutil
+
+import (
+	"encoding/binary"
+	"testing"
+
+	"github.com/perlin-network/noise/internal/protobuf"
+	"github.com/perlin-network/noise/types/opcode"
+
+	"github.com/gogo/protobuf/proto"
+	"github.com/stretchr/testify/assert"
+)
+
+func frame(t *testing.T, code opcode.Opcode) []byte {
+	bytes, err := proto.Marshal(&protobuf.Message{Opcode: uint32(code)})
+	assert.Nil(t, err)
+
+	size := make([]byte, 4)
+	binary.BigEndian.PutUint32(size, uint32(len(bytes)))
+
+	return append(size, bytes...)
+}
+
+func TestDropMessages(t *testing.T) {
+	t.Parallel()
+
+	drop := DropMessages(opcode.PingCode, 2)
+
+	// Writes which do not hold a single message are never dropped.
+	assert.False(t, drop([]byte{0, 0, 0, 1}))
+	assert.False(t, drop(append(frame(t, opcode.PingCode), frame(t, opcode.PingCode)...)))
+
+	assert.False(t, drop(frame(t, opcode.PongCode)))
+
+	assert.True(t, drop(frame(t, opcode.PingCode)))
+	assert.True(t, drop(frame(t, opcode.PingCode)))
+	assert.False(t, drop(frame(t, opcode.PingCode)), "expected only the first 2 pings to be dropped")
+}
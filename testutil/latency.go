@@ -0,0 +1,80 @@
+package testutil
+
+import (
+	"math/rand"
+	"net"
+	"time"
+
+	"github.com/perlin-network/noise/network/transport"
+)
+
+// LatencyConn wraps a connection such that every write is delayed by delay plus a random
+// duration of up to jitter.
+func LatencyConn(conn net.Conn, delay time.Duration, jitter time.Duration) net.Conn {
+	return &latencyConn{Conn: conn, delay: delay, jitter: jitter}
+}
+
+// LatencyListener wraps a listener such that every connection it accepts is a LatencyConn.
+func LatencyListener(l net.Listener, delay, jitter time.Duration) net.Listener {
+	return &latencyListener{Listener: l, delay: delay, jitter: jitter}
+}
+
+// LatencyLayer wraps a transport layer such that every connection it dials or accepts is
+// a LatencyConn.
+func LatencyLayer(layer transport.Layer, delay, jitter time.Duration) transport.Layer {
+	return &latencyLayer{Layer: layer, delay: delay, jitter: jitter}
+}
+
+type latencyConn struct {
+	net.Conn
+
+	delay, jitter time.Duration
+}
+
+// Write sleeps for the connections delay and jitter before writing.
+func (c *latencyConn) Write(b []byte) (int, error) {
+	delay := c.delay
+	if c.jitter > 0 {
+		delay += time.Duration(rand.Int63n(int64(c.jitter)))
+	}
+
+	time.Sleep(delay)
+
+	return c.Conn.Write(b)
+}
+
+type latencyListener struct {
+	net.Listener
+
+	delay, jitter time.Duration
+}
+
+func (l *latencyListener) Accept() (net.Conn, error) {
+	conn, err := l.Listener.Accept()
+	if err != nil {
+		return nil, err
+	}
+	return LatencyConn(conn, l.delay, l.jitter), nil
+}
+
+type latencyLayer struct {
+	transport.Layer
+
+	delay, jitter time.Duration
+}
+
+func (l *latencyLayer) Listen(port int) (net.Listener, error) {
+	listener, err := l.Layer.Listen(port)
+	if err != nil {
+		return nil, err
+	}
+	return LatencyListener(listener, l.delay, l.jitter), nil
+}
+
+func (l *latencyLayer) Dial(address string) (net.Conn, error) {
+	conn, err := l.Layer.Dial(address)
+	if err != nil {
+		return nil, err
+	}
+	return LatencyConn(conn, l.delay, l.jitter), nil
+}
@@ -0,0 +1,99 @@
+package testutil
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/perlin-network/noise/internal/protobuf"
+	"github.com/perlin-network/noise/network"
+	"github.com/perlin-network/noise/network/discovery"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLatencyLayer(t *testing.T) {
+	t.Parallel()
+
+	const delay = 50 * time.Millisecond
+
+	pipes := NewPipeTransport()
+
+	var nodes []*network.Network
+
+	for i := 0; i < 2; i++ {
+		builder := pipes.NewBuilder()
+		builder.RegisterTransportLayer(PipeProtocol, LatencyLayer(pipes, delay, 0))
+		builder.AddPlugin(new(discovery.Plugin))
+
+		node, err := builder.Build()
+		assert.Nil(t, err)
+
+		defer Start(node)()
+
+		nodes = append(nodes, node)
+	}
+
+	client, err := nodes[0].Client(nodes[1].Address)
+	assert.Nil(t, err)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+
+	// Pings and pongs are written over connections dialed by either node, both of which are delayed.
+	start := time.Now()
+
+	response, err := client.Request(ctx, &protobuf.Ping{})
+	assert.Nil(t, err)
+	assert.IsType(t, &protobuf.Pong{}, response)
+
+	rtt := time.Since(start)
+	assert.True(t, rtt >= 2*delay, "expected RTT of at least %s, got %s", 2*delay, rtt)
+}
+
+func TestLatencyConnJitter(t *testing.T) {
+	t.Parallel()
+
+	const delay, jitter = 20 * time.Millisecond, 20 * time.Millisecond
+
+	pipes := NewPipeTransport()
+
+	listener, err := pipes.Listen(1)
+	assert.Nil(t, err)
+	defer listener.Close()
+
+	go func() {
+		conn, err := LatencyListener(listener, delay, jitter).Accept()
+		if err != nil {
+			return
+		}
+
+		buf := make([]byte, 1)
+		for {
+			if _, err := conn.Read(buf); err != nil {
+				return
+			}
+			conn.Write(buf)
+		}
+	}()
+
+	conn, err := pipes.Dial("127.0.0.1:1")
+	assert.Nil(t, err)
+	defer conn.Close()
+
+	buf := make([]byte, 1)
+
+	for i := 0; i < 5; i++ {
+		start := time.Now()
+
+		_, err = conn.Write(buf)
+		assert.Nil(t, err)
+
+		_, err = conn.Read(buf)
+		assert.Nil(t, err)
+
+		rtt := time.Since(start)
+		assert.True(t, rtt >= delay, "expected echo to be delayed by at least %s, got %s", delay, rtt)
+		assert.True(t, rtt < delay+jitter+50*time.Millisecond, "expected echo to be delayed by at most %s, got %s", delay+jitter, rtt)
+	}
+}
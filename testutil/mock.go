@@ -0,0 +1,288 @@
+package testutil
+
+import (
+	"encoding/binary"
+	"io"
+	"net"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/perlin-network/noise/internal/protobuf"
+	"github.com/perlin-network/noise/network"
+	"github.com/perlin-network/noise/network/transport"
+
+	"github.com/gogo/protobuf/proto"
+	"github.com/pkg/errors"
+)
+
+const (
+	// MockProtocol is the protocol networks listening on a MockLayer are addressed with.
+	MockProtocol = "mock"
+
+	mockHost = "127.0.0.1"
+	mockPort = 1
+
+	mockAcceptBacklog = 16
+	mockInjectBacklog = 1024
+)
+
+var (
+	_ transport.Layer = (*MockLayer)(nil)
+	_ net.Conn        = (*MockConn)(nil)
+
+	errMockListening    = errors.New("mock: layer is already being listened on")
+	errMockConnClosed   = errors.New("mock: connection closed")
+	errMockLayerClosed  = errors.New("mock: listener closed")
+	errMockNotListening = errors.New("mock: layer is not being listened on")
+)
+
+// MockLayer is a transport layer for unit testing a single network, and the plugins
+// registered on it, without any peers. Connections the network dials are MockConns which
+// capture every message sent over them, and connections from peers are simulated by
+// injecting messages into incoming MockConns.
+type MockLayer struct {
+	mutex sync.Mutex
+
+	// conns holds connections dialed by the network, keyed by the address they were dialed at.
+	conns    map[string]*MockConn
+	listener *mockListener
+}
+
+// NewMockLayer instantiates a new mock transport layer without any connections.
+func NewMockLayer() *MockLayer {
+	return &MockLayer{conns: make(map[string]*MockConn)}
+}
+
+// NewBuilder returns a builder of a network listening on the layer at mock://127.0.0.1:1.
+func (l *MockLayer) NewBuilder(opts ...network.BuilderOption) *network.Builder {
+	builder := network.NewBuilderWithOptions(opts...)
+	builder.SetAddress(network.FormatAddress(MockProtocol, mockHost, mockPort))
+	builder.RegisterTransportLayer(MockProtocol, l)
+
+	return builder
+}
+
+// Listen returns a listener accepting connections created with NewIncoming. A layer may
+// only be listened on once.
+func (l *MockLayer) Listen(port int) (net.Listener, error) {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+
+	if l.listener != nil {
+		return nil, errMockListening
+	}
+
+	l.listener = &mockListener{
+		addr:     mockAddr(mockHost + ":" + strconv.Itoa(port)),
+		incoming: make(chan net.Conn, mockAcceptBacklog),
+		closed:   make(chan struct{}),
+	}
+
+	return l.listener, nil
+}
+
+// Dial returns the connection for an address, which is created should it not have been
+// pre-configured with Conn.
+func (l *MockLayer) Dial(address string) (net.Conn, error) {
+	return l.Conn(address), nil
+}
+
+// Conn returns the connection the network is given upon dialing an address, e.g.
+// 127.0.0.1:3000. The connection is created should it not exist yet.
+func (l *MockLayer) Conn(address string) *MockConn {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+
+	conn, exists := l.conns[address]
+	if !exists {
+		conn = newMockConn(mockAddr(mockHost+":0"), mockAddr(address))
+		l.conns[address] = conn
+	}
+
+	return conn
+}
+
+// NewIncoming has the network listening on the layer accept a new connection from a
+// remote address, and returns the connection such that messages may be injected into it.
+func (l *MockLayer) NewIncoming(remoteAddr string) (*MockConn, error) {
+	l.mutex.Lock()
+	listener := l.listener
+	l.mutex.Unlock()
+
+	if listener == nil {
+		return nil, errMockNotListening
+	}
+
+	conn := newMockConn(listener.addr, mockAddr(remoteAddr))
+
+	select {
+	case listener.incoming <- conn:
+		return conn, nil
+	case <-listener.closed:
+		return nil, errMockLayerClosed
+	}
+}
+
+// MockConn is a connection which captures messages written to it, and replays messages
+// injected into it with InjectMessage to whoever reads from it.
+type MockConn struct {
+	local, remote net.Addr
+
+	mutex   sync.Mutex
+	written []byte
+	sent    []*protobuf.Message
+
+	injected chan []byte
+	pending  []byte
+
+	closeOnce sync.Once
+	closed    chan struct{}
+}
+
+func newMockConn(local, remote net.Addr) *MockConn {
+	return &MockConn{
+		local:    local,
+		remote:   remote,
+		injected: make(chan []byte, mockInjectBacklog),
+		closed:   make(chan struct{}),
+	}
+}
+
+// InjectMessage enqueues a message for a mock connection to deliver to its reader,
+// framed as it would be sent by a peer.
+func InjectMessage(conn *MockConn, msg *protobuf.Message) error {
+	raw, err := proto.Marshal(msg)
+	if err != nil {
+		return errors.Wrap(err, "failed to marshal message")
+	}
+
+	frame := make([]byte, 4+len(raw))
+	binary.BigEndian.PutUint32(frame[0:4], uint32(len(raw)))
+	copy(frame[4:], raw)
+
+	select {
+	case conn.injected <- frame:
+		return nil
+	case <-conn.closed:
+		return errMockConnClosed
+	}
+}
+
+// Sent returns all messages written to the connection thus far, in the order they were written.
+func (c *MockConn) Sent() []*protobuf.Message {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	return append([]*protobuf.Message(nil), c.sent...)
+}
+
+// Read replays injected messages, blocking until one is injected or the connection is closed.
+func (c *MockConn) Read(b []byte) (int, error) {
+	if len(c.pending) == 0 {
+		select {
+		case c.pending = <-c.injected:
+		case <-c.closed:
+			return 0, io.EOF
+		}
+	}
+
+	n := copy(b, c.pending)
+	c.pending = c.pending[n:]
+
+	return n, nil
+}
+
+// Write captures every complete message framed within the data written to the connection.
+// Out-of-band probe frames are discarded.
+func (c *MockConn) Write(b []byte) (int, error) {
+	select {
+	case <-c.closed:
+		return 0, errMockConnClosed
+	default:
+	}
+
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	c.written = append(c.written, b...)
+
+	for len(c.written) >= 4 {
+		size := binary.BigEndian.Uint32(c.written[0:4])
+
+		if size == network.ProbeFrameMarker {
+			if len(c.written) < 4+network.ProbeFrameSize {
+				break
+			}
+			c.written = c.written[4+network.ProbeFrameSize:]
+			continue
+		}
+
+		if uint64(len(c.written)) < 4+uint64(size) {
+			break
+		}
+
+		msg := new(protobuf.Message)
+		if err := proto.Unmarshal(c.written[4:4+size], msg); err != nil {
+			return 0, errors.Wrap(err, "mock: failed to unmarshal written message")
+		}
+
+		c.sent = append(c.sent, msg)
+		c.written = c.written[4+size:]
+	}
+
+	return len(b), nil
+}
+
+// Close closes the connection, after which reads return io.EOF.
+func (c *MockConn) Close() error {
+	c.closeOnce.Do(func() {
+		close(c.closed)
+	})
+	return nil
+}
+
+func (c *MockConn) LocalAddr() net.Addr                { return c.local }
+func (c *MockConn) RemoteAddr() net.Addr               { return c.remote }
+func (c *MockConn) SetDeadline(t time.Time) error      { return nil }
+func (c *MockConn) SetReadDeadline(t time.Time) error  { return nil }
+func (c *MockConn) SetWriteDeadline(t time.Time) error { return nil }
+
+type mockListener struct {
+	addr     net.Addr
+	incoming chan net.Conn
+
+	closeOnce sync.Once
+	closed    chan struct{}
+}
+
+func (l *mockListener) Accept() (net.Conn, error) {
+	select {
+	case conn := <-l.incoming:
+		return conn, nil
+	case <-l.closed:
+		return nil, errMockLayerClosed
+	}
+}
+
+func (l *mockListener) Close() error {
+	l.closeOnce.Do(func() {
+		close(l.closed)
+	})
+	return nil
+}
+
+func (l *mockListener) Addr() net.Addr {
+	return l.addr
+}
+
+// mockAddr is the address of either end of a mock connection.
+type mockAddr string
+
+func (a mockAddr) Network() string {
+	return MockProtocol
+}
+
+func (a mockAddr) String() string {
+	return string(a)
+}
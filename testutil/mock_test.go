@@ -0,0 +1,75 @@
+package testutil
+
+import (
+	"encoding/binary"
+	"io"
+	"testing"
+
+	"github.com/perlin-network/noise/internal/protobuf"
+
+	"github.com/gogo/protobuf/proto"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMockConn(t *testing.T) {
+	t.Parallel()
+
+	layer := NewMockLayer()
+
+	conn, err := layer.Dial("127.0.0.1:3000")
+	assert.Nil(t, err)
+	assert.Equal(t, layer.Conn("127.0.0.1:3000"), conn, "expected dialed connection to be pre-configured")
+
+	msg := &protobuf.Message{Opcode: 10, Sequence: 1}
+	raw, err := proto.Marshal(msg)
+	assert.Nil(t, err)
+
+	frame := make([]byte, 4+len(raw))
+	binary.BigEndian.PutUint32(frame[0:4], uint32(len(raw)))
+	copy(frame[4:], raw)
+
+	// Messages split across writes are only captured once written in full.
+	_, err = conn.Write(frame[:3])
+	assert.Nil(t, err)
+	assert.Len(t, layer.Conn("127.0.0.1:3000").Sent(), 0)
+
+	_, err = conn.Write(frame[3:])
+	assert.Nil(t, err)
+
+	sent := layer.Conn("127.0.0.1:3000").Sent()
+	assert.Len(t, sent, 1)
+	assert.True(t, proto.Equal(msg, sent[0]))
+
+	assert.Nil(t, InjectMessage(layer.Conn("127.0.0.1:3000"), msg))
+
+	replayed := make([]byte, len(frame))
+	_, err = io.ReadFull(conn, replayed)
+	assert.Nil(t, err)
+	assert.Equal(t, frame, replayed)
+
+	conn.Close()
+
+	_, err = conn.Read(replayed)
+	assert.Equal(t, io.EOF, err)
+}
+
+func TestMockLayerNewIncoming(t *testing.T) {
+	t.Parallel()
+
+	layer := NewMockLayer()
+
+	_, err := layer.NewIncoming("127.0.0.1:2")
+	assert.NotNil(t, err, "expected incoming connections to require a listener")
+
+	listener, err := layer.Listen(1)
+	assert.Nil(t, err)
+	defer listener.Close()
+
+	incoming, err := layer.NewIncoming("127.0.0.1:2")
+	assert.Nil(t, err)
+
+	accepted, err := listener.Accept()
+	assert.Nil(t, err)
+	assert.Equal(t, incoming, accepted)
+	assert.Equal(t, "127.0.0.1:2", accepted.RemoteAddr().String())
+}
@@ -0,0 +1,135 @@
+package testutil
+
+import (
+	"sync"
+
+	"github.com/perlin-network/noise/network"
+
+	"github.com/pkg/errors"
+)
+
+// PartitionableNetwork is a set of networks connected over a PipeTransport, whose links
+// may be cut to partition the networks into groups which cannot reach one another.
+type PartitionableNetwork struct {
+	*PipeTransport
+
+	mutex   sync.Mutex
+	nodes   []*network.Network
+	ports   []int
+	removed []bool
+}
+
+// NewPartitionableNetwork instantiates a new partitionable network without any networks.
+func NewPartitionableNetwork() *PartitionableNetwork {
+	return &PartitionableNetwork{
+		PipeTransport: NewPipeTransport(),
+	}
+}
+
+// Add builds a network listening on the transport from a builder created with NewBuilder,
+// starts it, and returns its index.
+func (p *PartitionableNetwork) Add(builder *network.Builder) (int, error) {
+	node, err := builder.Build()
+	if err != nil {
+		return 0, err
+	}
+
+	info, err := network.ParseAddress(node.Address)
+	if err != nil {
+		return 0, err
+	}
+
+	if info.Protocol != PipeProtocol {
+		return 0, errors.Errorf("partition: network must listen on the pipe transport, not %s", info.Protocol)
+	}
+
+	Start(node)
+
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+
+	p.nodes = append(p.nodes, node)
+	p.ports = append(p.ports, int(info.Port))
+	p.removed = append(p.removed, false)
+
+	return len(p.nodes) - 1, nil
+}
+
+// Node returns the network at an index.
+func (p *PartitionableNetwork) Node(index int) *network.Network {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+
+	return p.nodes[index]
+}
+
+// Nodes returns all networks, including removed networks, ordered by index.
+func (p *PartitionableNetwork) Nodes() []*network.Network {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+
+	return append([]*network.Network(nil), p.nodes...)
+}
+
+// Running returns the indices of all networks which have not been removed.
+func (p *PartitionableNetwork) Running() []int {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+
+	var running []int
+	for i, removed := range p.removed {
+		if !removed {
+			running = append(running, i)
+		}
+	}
+
+	return running
+}
+
+// Remove closes the network at an index. Indices of other networks are unaffected.
+func (p *PartitionableNetwork) Remove(index int) {
+	p.mutex.Lock()
+	node, removed := p.nodes[index], p.removed[index]
+	p.removed[index] = true
+	p.mutex.Unlock()
+
+	if !removed {
+		node.Close()
+	}
+}
+
+// Partition drops all data sent between the networks of two groups, denoted by their indices.
+// Networks within the same group may still reach one another.
+func (p *PartitionableNetwork) Partition(groupA, groupB []int) {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+
+	for _, a := range groupA {
+		for _, b := range groupB {
+			p.cutLink(p.ports[a], p.ports[b])
+		}
+	}
+}
+
+// Connect restores the link between the networks at two indices should it be cut, and has
+// either network dial the other.
+func (p *PartitionableNetwork) Connect(a, b int) error {
+	p.mutex.Lock()
+	p.restoreLink(p.ports[a], p.ports[b])
+	nodeA, nodeB := p.nodes[a], p.nodes[b]
+	p.mutex.Unlock()
+
+	return Connect(nodeA, nodeB)
+}
+
+// Heal restores connectivity between all networks.
+func (p *PartitionableNetwork) Heal() {
+	p.restoreLinks()
+}
+
+// Close closes all networks which have not been removed.
+func (p *PartitionableNetwork) Close() {
+	for _, index := range p.Running() {
+		p.Remove(index)
+	}
+}
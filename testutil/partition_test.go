@@ -0,0 +1,83 @@
+package testutil
+
+import (
+	"testing"
+	"time"
+
+	"github.com/perlin-network/noise/network/discovery"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPartitionableNetwork(t *testing.T) {
+	t.Parallel()
+
+	net := NewPartitionableNetwork()
+	defer net.Close()
+
+	var plugins []*discovery.Plugin
+
+	for i := 0; i < 6; i++ {
+		plugin := &discovery.Plugin{RefreshInterval: 100 * time.Millisecond}
+
+		builder := net.NewBuilder()
+		builder.AddPlugin(plugin)
+
+		_, err := net.Add(builder)
+		assert.Nil(t, err)
+
+		plugins = append(plugins, plugin)
+	}
+
+	nodes := net.Nodes()
+
+	groupA, groupB := []int{0, 1, 2}, []int{3, 4, 5}
+	net.Partition(groupA, groupB)
+
+	routed := func(i, j int) bool {
+		return plugins[i].Routes.PeerExists(nodes[j].ID)
+	}
+
+	waitUntilRouted := func(group []int, msg string) {
+		deadline := time.Now().Add(5 * time.Second)
+
+		for _, i := range group {
+			for _, j := range group {
+				for i != j && !routed(i, j) {
+					if time.Now().After(deadline) {
+						t.Fatalf("%s: expected node %d to route node %d", msg, i, j)
+					}
+					time.Sleep(20 * time.Millisecond)
+				}
+			}
+		}
+	}
+
+	for _, group := range [][]int{groupA, groupB} {
+		for _, i := range group[1:] {
+			nodes[i].Bootstrap(nodes[group[0]].Address)
+		}
+	}
+
+	// Attempt to bootstrap across the partition.
+	nodes[2].Bootstrap(nodes[3].Address)
+
+	waitUntilRouted(groupA, "partitioned")
+	waitUntilRouted(groupB, "partitioned")
+
+	// Give refreshes time to discover peers across the partition, which should not happen.
+	time.Sleep(500 * time.Millisecond)
+
+	for _, i := range groupA {
+		for _, j := range groupB {
+			assert.False(t, routed(i, j), "expected node %d to not discover node %d across the partition", i, j)
+			assert.False(t, routed(j, i), "expected node %d to not discover node %d across the partition", j, i)
+		}
+	}
+
+	net.Heal()
+
+	nodes[2].Bootstrap(nodes[3].Address)
+
+	waitUntilRouted([]int{0, 1, 2, 3, 4, 5}, "healed")
+}
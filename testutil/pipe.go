@@ -0,0 +1,351 @@
+// Package testutil provides in-memory transports and helpers for testing networks and
+// plugins without any network I/O.
+package testutil
+
+import (
+	"encoding/binary"
+	"io"
+	"net"
+	"strconv"
+	"sync"
+
+	"github.com/perlin-network/noise/network"
+	"github.com/perlin-network/noise/network/transport"
+
+	"github.com/pkg/errors"
+)
+
+const (
+	// PipeProtocol is the protocol networks listening on a PipeTransport are addressed with.
+	PipeProtocol = "pipe"
+
+	pipeHost = "127.0.0.1"
+
+	pipeAcceptBacklog = 16
+)
+
+var (
+	_ transport.Layer = (*PipeTransport)(nil)
+
+	errPipeListenerClosed = errors.New("pipe: listener closed")
+)
+
+// PipeTransport is an in-memory transport layer which connects networks through pairs of
+// connections created with net.Pipe. Networks built off of the same PipeTransport may
+// connect to one another as though they were connected over TCP, though no ports are opened.
+type PipeTransport struct {
+	mutex     sync.Mutex
+	listeners map[int]*pipeListener
+	lastPort  int
+
+	// cut holds pairs of ports of networks between which all data is dropped.
+	cut map[[2]int]struct{}
+}
+
+// NewPipeTransport instantiates a new in-memory transport without any listeners.
+func NewPipeTransport() *PipeTransport {
+	return &PipeTransport{
+		listeners: make(map[int]*pipeListener),
+		cut:       make(map[[2]int]struct{}),
+	}
+}
+
+// NewAddress returns an address not yet listened on, e.g. pipe://127.0.0.1:1.
+func (t *PipeTransport) NewAddress() string {
+	return network.FormatAddress(PipeProtocol, pipeHost, uint16(t.nextPort()))
+}
+
+// nextPort allocates a port which is not listened on.
+func (t *PipeTransport) nextPort() int {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+
+	for {
+		t.lastPort = t.lastPort%65535 + 1
+
+		if _, exists := t.listeners[t.lastPort]; !exists {
+			return t.lastPort
+		}
+	}
+}
+
+// NewBuilder returns a builder of a network listening on the transport at a new address.
+func (t *PipeTransport) NewBuilder(opts ...network.BuilderOption) *network.Builder {
+	port := t.nextPort()
+
+	builder := network.NewBuilderWithOptions(opts...)
+	builder.SetAddress(network.FormatAddress(PipeProtocol, pipeHost, uint16(port)))
+	builder.RegisterTransportLayer(PipeProtocol, &pipeLayer{PipeTransport: t, port: port})
+
+	return builder
+}
+
+// NewTestNetwork builds a network listening on the transport with a set of plugins, and
+// waits for it to start listening. The returned function closes the network.
+func (t *PipeTransport) NewTestNetwork(plugins ...network.PluginInterface) (*network.Network, func(), error) {
+	builder := t.NewBuilder()
+
+	for _, plugin := range plugins {
+		if err := builder.AddPlugin(plugin); err != nil {
+			return nil, nil, err
+		}
+	}
+
+	node, err := builder.Build()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return node, Start(node), nil
+}
+
+// Start has a network start listening, and blocks until it is listening. The returned
+// function closes the network.
+func Start(node *network.Network) func() {
+	go node.Listen()
+	node.BlockUntilListening()
+
+	return node.Close
+}
+
+// Connect creates a virtual link between two networks by having either network dial the other.
+func Connect(a, b *network.Network) error {
+	if _, err := a.Client(b.Address); err != nil {
+		return errors.Wrapf(err, "failed to connect %s to %s", a.Address, b.Address)
+	}
+
+	if _, err := b.Client(a.Address); err != nil {
+		return errors.Wrapf(err, "failed to connect %s to %s", b.Address, a.Address)
+	}
+
+	return nil
+}
+
+// Listen listens for connections dialed to a port over the transport.
+func (t *PipeTransport) Listen(port int) (net.Listener, error) {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+
+	if _, exists := t.listeners[port]; exists {
+		return nil, errors.Errorf("pipe: port %d is already listened on", port)
+	}
+
+	listener := &pipeListener{
+		transport: t,
+		port:      port,
+		conns:     make(chan net.Conn, pipeAcceptBacklog),
+		closed:    make(chan struct{}),
+	}
+	t.listeners[port] = listener
+
+	return listener, nil
+}
+
+// Dial connects to the listener of the port of an address.
+func (t *PipeTransport) Dial(address string) (net.Conn, error) {
+	return t.dial(0, address)
+}
+
+// dial connects to the listener of the port of an address on behalf of the network
+// listening on a port. Connections dialed on behalf of no network are never cut.
+func (t *PipeTransport) dial(from int, address string) (net.Conn, error) {
+	_, rawPort, err := net.SplitHostPort(address)
+	if err != nil {
+		return nil, err
+	}
+
+	port, err := strconv.Atoi(rawPort)
+	if err != nil {
+		return nil, err
+	}
+
+	t.mutex.Lock()
+	listener, exists := t.listeners[port]
+	t.mutex.Unlock()
+
+	if !exists {
+		return nil, errors.Errorf("pipe: connection refused to %s", address)
+	}
+
+	local, remote := pipeAddr(t.nextPort()), pipeAddr(port)
+	client, server := net.Pipe()
+
+	select {
+	case listener.conns <- &pipeConn{Conn: server, transport: t, local: remote, remote: local, from: port, to: from}:
+		return &pipeConn{Conn: client, transport: t, local: local, remote: remote, from: from, to: port}, nil
+	case <-listener.closed:
+		client.Close()
+		server.Close()
+
+		return nil, errors.Errorf("pipe: connection refused to %s", address)
+	}
+}
+
+// cutLink drops all data sent between the networks listening on two ports.
+func (t *PipeTransport) cutLink(a, b int) {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+
+	t.cut[[2]int{a, b}] = struct{}{}
+	t.cut[[2]int{b, a}] = struct{}{}
+}
+
+// restoreLink restores the link between the networks listening on two ports.
+func (t *PipeTransport) restoreLink(a, b int) {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+
+	delete(t.cut, [2]int{a, b})
+	delete(t.cut, [2]int{b, a})
+}
+
+// restoreLinks restores all links which have been cut.
+func (t *PipeTransport) restoreLinks() {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+
+	t.cut = make(map[[2]int]struct{})
+}
+
+// isCut returns true if data sent between the networks listening on two ports is dropped.
+func (t *PipeTransport) isCut(a, b int) bool {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+
+	_, cut := t.cut[[2]int{a, b}]
+	return cut
+}
+
+// pipeLayer dials connections over a PipeTransport on behalf of the network listening on a port.
+type pipeLayer struct {
+	*PipeTransport
+
+	port int
+}
+
+// Dial connects to the listener of the port of an address.
+func (l *pipeLayer) Dial(address string) (net.Conn, error) {
+	return l.dial(l.port, address)
+}
+
+// pipeListener accepts connections dialed to a port of a PipeTransport.
+type pipeListener struct {
+	transport *PipeTransport
+	port      int
+
+	conns chan net.Conn
+
+	closeOnce sync.Once
+	closed    chan struct{}
+}
+
+// Accept waits for a connection to be dialed to the listeners port.
+func (l *pipeListener) Accept() (net.Conn, error) {
+	select {
+	case conn := <-l.conns:
+		return conn, nil
+	case <-l.closed:
+		return nil, errPipeListenerClosed
+	}
+}
+
+// Close stops listening on the port, such that it may be listened on again.
+func (l *pipeListener) Close() error {
+	l.closeOnce.Do(func() {
+		l.transport.mutex.Lock()
+		delete(l.transport.listeners, l.port)
+		l.transport.mutex.Unlock()
+
+		close(l.closed)
+	})
+
+	return nil
+}
+
+// Addr returns the address of the port the listener listens on.
+func (l *pipeListener) Addr() net.Addr {
+	return pipeAddr(l.port)
+}
+
+// pipeConn is one end of a pipe, addressed by the ports of either end. Data sent over a
+// pipe between networks whose link is cut is dropped. Every write is sent as a single
+// length-prefixed chunk, such that writes are either delivered or dropped as a whole.
+type pipeConn struct {
+	net.Conn
+
+	transport *PipeTransport
+
+	local, remote net.Addr
+
+	// from and to are the ports of the networks on either end of the pipe.
+	from, to int
+
+	readMutex sync.Mutex
+
+	// buffer holds the data of a delivered write which has yet to be read.
+	buffer []byte
+}
+
+// Read reads data off of the pipe, dropping writes read while the link is cut. Writes
+// only partially read are buffered until read in full.
+func (c *pipeConn) Read(b []byte) (int, error) {
+	c.readMutex.Lock()
+	defer c.readMutex.Unlock()
+
+	for len(c.buffer) == 0 {
+		var size [4]byte
+		if _, err := io.ReadFull(c.Conn, size[:]); err != nil {
+			return 0, err
+		}
+
+		data := make([]byte, binary.BigEndian.Uint32(size[:]))
+		if _, err := io.ReadFull(c.Conn, data); err != nil {
+			return 0, err
+		}
+
+		if !c.transport.isCut(c.from, c.to) {
+			c.buffer = data
+		}
+	}
+
+	n := copy(b, c.buffer)
+	c.buffer = c.buffer[n:]
+
+	return n, nil
+}
+
+// Write writes data to the pipe, or drops it should the link be cut.
+func (c *pipeConn) Write(b []byte) (int, error) {
+	if c.transport.isCut(c.from, c.to) {
+		return len(b), nil
+	}
+
+	chunk := make([]byte, 4+len(b))
+	binary.BigEndian.PutUint32(chunk, uint32(len(b)))
+	copy(chunk[4:], b)
+
+	if _, err := c.Conn.Write(chunk); err != nil {
+		return 0, err
+	}
+
+	return len(b), nil
+}
+
+func (c *pipeConn) LocalAddr() net.Addr {
+	return c.local
+}
+
+func (c *pipeConn) RemoteAddr() net.Addr {
+	return c.remote
+}
+
+// pipeAddr is the address of a port of a PipeTransport.
+type pipeAddr int
+
+func (a pipeAddr) Network() string {
+	return PipeProtocol
+}
+
+func (a pipeAddr) String() string {
+	return net.JoinHostPort(pipeHost, strconv.Itoa(int(a)))
+}
@@ -0,0 +1,117 @@
+package testutil
+
+import (
+	"context"
+	"io"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/perlin-network/noise/internal/protobuf"
+	"github.com/perlin-network/noise/network/discovery"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPipeTransport(t *testing.T) {
+	t.Parallel()
+
+	pipes := NewPipeTransport()
+
+	a, closeA, err := pipes.NewTestNetwork(new(discovery.Plugin))
+	assert.Nil(t, err)
+	defer closeA()
+
+	b, closeB, err := pipes.NewTestNetwork(new(discovery.Plugin))
+	assert.Nil(t, err)
+	defer closeB()
+
+	assert.Nil(t, Connect(a, b))
+
+	client, err := a.Client(b.Address)
+	assert.Nil(t, err)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+
+	response, err := client.Request(ctx, &protobuf.Ping{})
+	assert.Nil(t, err)
+	assert.IsType(t, &protobuf.Pong{}, response)
+
+	plugin, _ := b.Plugin(discovery.PluginID)
+	assert.True(t, plugin.(*discovery.Plugin).Routes.PeerExists(a.ID), "expected pinging peer to be routed")
+}
+
+func TestPipeTransportListen(t *testing.T) {
+	t.Parallel()
+
+	pipes := NewPipeTransport()
+
+	listener, err := pipes.Listen(1)
+	assert.Nil(t, err)
+
+	_, err = pipes.Listen(1)
+	assert.NotNil(t, err, "expected port to not be listened on twice")
+
+	go func() {
+		conn, err := listener.Accept()
+		if err == nil {
+			conn.Write([]byte("noise"))
+		}
+	}()
+
+	conn, err := pipes.Dial("127.0.0.1:1")
+	assert.Nil(t, err)
+
+	buf := make([]byte, 5)
+	_, err = conn.Read(buf)
+	assert.Nil(t, err)
+	assert.Equal(t, "noise", string(buf))
+	assert.Equal(t, "127.0.0.1:1", conn.RemoteAddr().String())
+
+	assert.Nil(t, listener.Close())
+
+	_, err = pipes.Dial("127.0.0.1:1")
+	assert.NotNil(t, err, "expected dialing a closed port to be refused")
+
+	_, err = pipes.Listen(1)
+	assert.Nil(t, err, "expected port to be listened on again once closed")
+}
+
+func TestPipeConnPartialReads(t *testing.T) {
+	t.Parallel()
+
+	pipes := NewPipeTransport()
+
+	listener, err := pipes.Listen(1)
+	assert.Nil(t, err)
+	defer listener.Close()
+
+	accepted := make(chan net.Conn, 1)
+	go func() {
+		conn, err := listener.Accept()
+		if err == nil {
+			accepted <- conn
+		}
+	}()
+
+	conn, err := pipes.dial(2, "127.0.0.1:1")
+	assert.Nil(t, err)
+
+	server := <-accepted
+
+	go conn.Write([]byte("noise"))
+
+	buf := make([]byte, 2)
+	_, err = server.Read(buf)
+	assert.Nil(t, err)
+	assert.Equal(t, "no", string(buf))
+
+	// The remainder of a write partially read is delivered even once the link is cut.
+	pipes.cutLink(1, 2)
+
+	rest := make([]byte, 3)
+	_, err = io.ReadFull(server, rest)
+	assert.Nil(t, err)
+	assert.Equal(t, "ise", string(rest))
+}
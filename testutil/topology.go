@@ -0,0 +1,70 @@
+package testutil
+
+import (
+	"github.com/perlin-network/noise/network/discovery"
+)
+
+// StarTopology creates a cluster of n networks, in which every network is connected to
+// only the first network.
+func StarTopology(n int, opts ...ClusterOption) (*Cluster, error) {
+	var edges [][2]int
+	for i := 1; i < n; i++ {
+		edges = append(edges, [2]int{0, i})
+	}
+
+	return newTopology(n, edges, opts)
+}
+
+// RingTopology creates a cluster of n networks, in which every network is connected to
+// only the networks before and after it, with the last network connected to the first.
+func RingTopology(n int, opts ...ClusterOption) (*Cluster, error) {
+	var edges [][2]int
+	for i := 0; i < n-1; i++ {
+		edges = append(edges, [2]int{i, i + 1})
+	}
+
+	if n > 2 {
+		edges = append(edges, [2]int{n - 1, 0})
+	}
+
+	return newTopology(n, edges, opts)
+}
+
+// FullMeshTopology creates a cluster of n networks, in which every network is connected
+// to every other network.
+func FullMeshTopology(n int, opts ...ClusterOption) (*Cluster, error) {
+	var edges [][2]int
+	for i := 0; i < n; i++ {
+		for j := i + 1; j < n; j++ {
+			edges = append(edges, [2]int{i, j})
+		}
+	}
+
+	return newTopology(n, edges, opts)
+}
+
+// newTopology creates a cluster whose networks only route the networks they share an edge
+// with. Lookups are not performed upon bootstrapping, such that routing tables hold no
+// peers beyond those connected to.
+func newTopology(n int, edges [][2]int, opts []ClusterOption) (*Cluster, error) {
+	opts = append([]ClusterOption{
+		WithoutBootstrap(),
+		WithDiscovery(func(int) *discovery.Plugin {
+			return &discovery.Plugin{DisableBootstrap: true}
+		}),
+	}, opts...)
+
+	c, err := NewCluster(n, opts...)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, edge := range edges {
+		if err := c.Connect(edge[0], edge[1]); err != nil {
+			c.Stop()
+			return nil, err
+		}
+	}
+
+	return c, nil
+}
@@ -0,0 +1,99 @@
+package testutil
+
+import (
+	"sync/atomic"
+	"testing"
+
+	"github.com/perlin-network/noise/internal/protobuf"
+	"github.com/perlin-network/noise/network"
+	"github.com/perlin-network/noise/network/discovery"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTopologies(t *testing.T) {
+	t.Parallel()
+
+	const n = 5
+
+	testCases := []struct {
+		name     string
+		topology func(n int, opts ...ClusterOption) (*Cluster, error)
+		degree   func(i int) int
+	}{
+		{"star", StarTopology, func(i int) int {
+			if i == 0 {
+				return n - 1
+			}
+			return 1
+		}},
+		{"ring", RingTopology, func(int) int { return 2 }},
+		{"full mesh", FullMeshTopology, func(int) int { return n - 1 }},
+	}
+	for _, tt := range testCases {
+		cluster, err := tt.topology(n)
+		if !assert.Nil(t, err, tt.name) {
+			continue
+		}
+
+		for i := 0; i < n; i++ {
+			assert.Equal(t, tt.degree(i), len(cluster.Plugin(i).Routes.GetPeers()), "%s: unexpected number of peers routed by node %d", tt.name, i)
+		}
+
+		cluster.Stop()
+	}
+}
+
+// lookupCounter counts the lookup requests a network receives.
+type lookupCounter struct {
+	*network.Plugin
+
+	lookups *int64
+}
+
+func (p *lookupCounter) Receive(ctx *network.PluginContext) error {
+	if _, ok := ctx.Message().(*protobuf.LookupNodeRequest); ok {
+		atomic.AddInt64(p.lookups, 1)
+	}
+	return nil
+}
+
+// benchmarkFindNode measures the number of peers queried by a lookup across a topology,
+// reported as hops per lookup.
+func benchmarkFindNode(b *testing.B, topology func(n int, opts ...ClusterOption) (*Cluster, error)) {
+	const n = 16
+
+	var lookups int64
+
+	cluster, err := topology(n, WithSetup(func(_ int, builder *network.Builder) {
+		builder.AddPlugin(&lookupCounter{lookups: &lookups})
+	}))
+	if err != nil {
+		b.Fatal(err)
+	}
+	defer cluster.Stop()
+
+	source, target := cluster.Node(1), cluster.Node(n/2)
+
+	b.ResetTimer()
+
+	for i := 0; i < b.N; i++ {
+		discovery.FindNode(source, target.ID, 3, 1)
+	}
+
+	b.StopTimer()
+
+	b.ReportMetric(float64(atomic.LoadInt64(&lookups))/float64(b.N), "hops/op")
+}
+
+func BenchmarkFindNodeStar(b *testing.B) {
+	benchmarkFindNode(b, StarTopology)
+}
+
+func BenchmarkFindNodeRing(b *testing.B) {
+	benchmarkFindNode(b, RingTopology)
+}
+
+func BenchmarkFindNodeFullMesh(b *testing.B) {
+	benchmarkFindNode(b, FullMeshTopology)
+}
@@ -0,0 +1,214 @@
+// Package tracing records the messages networks exchange over a transport layer to a file,
+// and replays recorded messages to a fresh network such that bugs may be reproduced.
+package tracing
+
+import (
+	"encoding/binary"
+	"encoding/json"
+	"io"
+	"net"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/perlin-network/noise/internal/protobuf"
+	"github.com/perlin-network/noise/network"
+	"github.com/perlin-network/noise/network/transport"
+	"github.com/perlin-network/noise/peer"
+	"github.com/perlin-network/noise/types/opcode"
+
+	"github.com/gogo/protobuf/proto"
+)
+
+const (
+	// DirectionIncoming denotes a message read off of a connection.
+	DirectionIncoming = "in"
+
+	// DirectionOutgoing denotes a message written to a connection.
+	DirectionOutgoing = "out"
+)
+
+var _ transport.Layer = (*Recorder)(nil)
+
+// Entry is a single message sent or received over a recorded connection.
+type Entry struct {
+	Time time.Time `json:"time"`
+
+	// Conn numbers the connection the message was sent over, in the order connections were
+	// dialed or accepted.
+	Conn       uint64 `json:"conn"`
+	Direction  string `json:"direction"`
+	LocalAddr  string `json:"local_addr"`
+	RemoteAddr string `json:"remote_addr"`
+
+	// PeerID is the hex-encoded public key of the messages sender.
+	PeerID      string `json:"peer_id"`
+	Opcode      uint32 `json:"opcode"`
+	MessageType string `json:"message_type,omitempty"`
+
+	// Message is the serialized message, as framed over the wire.
+	Message []byte `json:"message"`
+}
+
+// Decode deserializes the recorded message.
+func (e Entry) Decode() (*protobuf.Message, error) {
+	msg := new(protobuf.Message)
+	if err := proto.Unmarshal(e.Message, msg); err != nil {
+		return nil, err
+	}
+	return msg, nil
+}
+
+// Recorder is a transport layer which wraps another, and writes every message sent or
+// received over its connections to a writer as newline-delimited JSON entries.
+//
+// Only plaintext messages may be recorded, and hence connections should not negotiate
+// features such as forward secrecy or compression.
+type Recorder struct {
+	transport.Layer
+
+	mutex   sync.Mutex
+	encoder *json.Encoder
+
+	lastConn uint64
+}
+
+// NewRecorder instantiates a new recorder of connections dialed and accepted by a layer,
+// writing recorded entries to w.
+func NewRecorder(layer transport.Layer, w io.Writer) *Recorder {
+	return &Recorder{Layer: layer, encoder: json.NewEncoder(w)}
+}
+
+// Listen listens on the wrapped layer, recording all accepted connections.
+func (r *Recorder) Listen(port int) (net.Listener, error) {
+	listener, err := r.Layer.Listen(port)
+	if err != nil {
+		return nil, err
+	}
+	return &recordedListener{Listener: listener, recorder: r}, nil
+}
+
+// Dial dials an address with the wrapped layer, recording the dialed connection.
+func (r *Recorder) Dial(address string) (net.Conn, error) {
+	conn, err := r.Layer.Dial(address)
+	if err != nil {
+		return nil, err
+	}
+	return r.wrap(conn), nil
+}
+
+func (r *Recorder) wrap(conn net.Conn) net.Conn {
+	return &recordedConn{
+		Conn:     conn,
+		recorder: r,
+		id:       atomic.AddUint64(&r.lastConn, 1),
+	}
+}
+
+// record writes an entry for a message sent or received over a connection.
+func (r *Recorder) record(conn *recordedConn, direction string, raw []byte) {
+	msg := new(protobuf.Message)
+	if err := proto.Unmarshal(raw, msg); err != nil {
+		return
+	}
+
+	entry := Entry{
+		Time:       time.Now(),
+		Conn:       conn.id,
+		Direction:  direction,
+		LocalAddr:  conn.LocalAddr().String(),
+		RemoteAddr: conn.RemoteAddr().String(),
+		Opcode:     msg.Opcode,
+		Message:    append([]byte(nil), raw...),
+	}
+
+	if msg.Sender != nil {
+		entry.PeerID = peer.ID(*msg.Sender).PublicKeyHex()
+	}
+
+	if typ, err := opcode.GetMessageType(opcode.Opcode(msg.Opcode)); err == nil {
+		entry.MessageType = proto.MessageName(typ)
+	}
+
+	r.mutex.Lock()
+	r.encoder.Encode(entry)
+	r.mutex.Unlock()
+}
+
+type recordedListener struct {
+	net.Listener
+	recorder *Recorder
+}
+
+func (l *recordedListener) Accept() (net.Conn, error) {
+	conn, err := l.Listener.Accept()
+	if err != nil {
+		return nil, err
+	}
+	return l.recorder.wrap(conn), nil
+}
+
+// recordedConn records messages framed within the data read from, and written to, a connection.
+type recordedConn struct {
+	net.Conn
+
+	recorder *Recorder
+	id       uint64
+
+	readMutex, writeMutex sync.Mutex
+	read, written         frameBuffer
+}
+
+func (c *recordedConn) Read(b []byte) (int, error) {
+	n, err := c.Conn.Read(b)
+
+	if n > 0 {
+		c.readMutex.Lock()
+		c.read.feed(b[:n], func(raw []byte) { c.recorder.record(c, DirectionIncoming, raw) })
+		c.readMutex.Unlock()
+	}
+
+	return n, err
+}
+
+func (c *recordedConn) Write(b []byte) (int, error) {
+	n, err := c.Conn.Write(b)
+
+	if n > 0 {
+		c.writeMutex.Lock()
+		c.written.feed(b[:n], func(raw []byte) { c.recorder.record(c, DirectionOutgoing, raw) })
+		c.writeMutex.Unlock()
+	}
+
+	return n, err
+}
+
+// frameBuffer reassembles length-prefixed message frames out of a stream of data.
+type frameBuffer struct {
+	buf []byte
+}
+
+// feed appends data to the buffer, and calls fn with every complete message frame.
+// Probe frames are discarded.
+func (f *frameBuffer) feed(data []byte, fn func(raw []byte)) {
+	f.buf = append(f.buf, data...)
+
+	for len(f.buf) >= 4 {
+		size := binary.BigEndian.Uint32(f.buf[0:4])
+
+		if size == network.ProbeFrameMarker {
+			if len(f.buf) < 4+network.ProbeFrameSize {
+				return
+			}
+			f.buf = f.buf[4+network.ProbeFrameSize:]
+			continue
+		}
+
+		if uint64(len(f.buf)) < 4+uint64(size) {
+			return
+		}
+
+		fn(f.buf[4 : 4+size])
+		f.buf = f.buf[4+size:]
+	}
+}
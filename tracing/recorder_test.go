@@ -0,0 +1,153 @@
+package tracing
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/perlin-network/noise/internal/protobuf"
+	"github.com/perlin-network/noise/network"
+	"github.com/perlin-network/noise/network/transport"
+	"github.com/perlin-network/noise/testutil"
+
+	"github.com/gogo/protobuf/proto"
+	"github.com/stretchr/testify/assert"
+)
+
+// callbackRecorder records the plugin callbacks fired upon a network.
+type callbackRecorder struct {
+	*network.Plugin
+
+	mutex     sync.Mutex
+	callbacks []string
+}
+
+func (p *callbackRecorder) PeerConnect(client *network.PeerClient) {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+
+	p.callbacks = append(p.callbacks, "connect "+client.Address)
+}
+
+func (p *callbackRecorder) Receive(ctx *network.PluginContext) error {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+
+	p.callbacks = append(p.callbacks, fmt.Sprintf("receive %s from %s", proto.MessageName(ctx.Message()), ctx.Sender().PublicKeyHex()))
+	return nil
+}
+
+func (p *callbackRecorder) received() []string {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+
+	return append([]string(nil), p.callbacks...)
+}
+
+// waitForCallbacks waits until a given number of callbacks have fired.
+func waitForCallbacks(t *testing.T, p *callbackRecorder, count int) []string {
+	deadline := time.Now().Add(3 * time.Second)
+	for len(p.received()) < count {
+		if time.Now().After(deadline) {
+			t.Fatalf("expected %d callbacks to fire, got %v", count, p.received())
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	return p.received()
+}
+
+func TestRecordAndReplay(t *testing.T) {
+	t.Parallel()
+
+	path := filepath.Join(t.TempDir(), "trace.ndjson")
+
+	file, err := os.Create(path)
+	assert.Nil(t, err)
+	defer file.Close()
+
+	recorded := new(callbackRecorder)
+
+	builder := network.NewBuilder()
+	builder.SetAddress(network.FormatAddress("tcp", "localhost", uint16(network.GetRandomUnusedPort())))
+	builder.RegisterTransportLayer("tcp", NewRecorder(transport.NewTCP(), file))
+	builder.AddPlugin(recorded)
+
+	receiver, err := builder.Build()
+	assert.Nil(t, err)
+
+	go receiver.Listen()
+	defer receiver.Close()
+
+	receiver.BlockUntilListening()
+
+	builder = network.NewBuilder()
+	builder.SetAddress(network.FormatAddress("tcp", "localhost", uint16(network.GetRandomUnusedPort())))
+
+	sender, err := builder.Build()
+	assert.Nil(t, err)
+
+	go sender.Listen()
+	defer sender.Close()
+
+	sender.BlockUntilListening()
+
+	client, err := sender.Client(receiver.Address)
+	assert.Nil(t, err)
+
+	exchange := []proto.Message{
+		&protobuf.Ping{},
+		&protobuf.LookupNodeRequest{Target: &protobuf.ID{Address: "tcp://localhost:1"}},
+		&protobuf.Pong{},
+		&protobuf.LookupNodeResponse{},
+		&protobuf.Ping{},
+	}
+
+	// Messages are handled concurrently, so each message is sent once the prior message
+	// was received, such that the exchange is recorded in order. The peer connects before
+	// its first message is received.
+	for i, msg := range exchange {
+		assert.Nil(t, client.Tell(context.Background(), msg))
+		waitForCallbacks(t, recorded, 2+i)
+	}
+
+	expected := recorded.received()[1:]
+
+	file.Seek(0, 0)
+
+	replayer, err := NewReplayer(file)
+	assert.Nil(t, err)
+
+	incoming := 0
+	for _, entry := range replayer.Entries {
+		if entry.Direction == DirectionIncoming {
+			assert.Equal(t, sender.ID.PublicKeyHex(), entry.PeerID)
+			incoming++
+		}
+	}
+	assert.Equal(t, len(exchange), incoming, "expected every sent message to be recorded once")
+	assert.Equal(t, "protobuf.Ping", replayer.Entries[0].MessageType)
+
+	replayed := new(callbackRecorder)
+	layer := testutil.NewMockLayer()
+
+	builder = replayer.NewBuilder(layer)
+	builder.AddPlugin(replayed)
+
+	node, err := builder.Build()
+	assert.Nil(t, err)
+
+	go node.Listen()
+	defer node.Close()
+
+	node.BlockUntilListening()
+
+	assert.Nil(t, replayer.Replay(layer))
+
+	replayedCallbacks := waitForCallbacks(t, replayed, 1+incoming)
+	assert.Equal(t, "connect "+sender.Address, replayedCallbacks[0])
+	assert.Equal(t, expected, replayedCallbacks[len(replayedCallbacks)-len(expected):])
+}
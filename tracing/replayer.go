@@ -0,0 +1,114 @@
+package tracing
+
+import (
+	"bufio"
+	"encoding/json"
+	"io"
+	"time"
+
+	"github.com/perlin-network/noise/network"
+	"github.com/perlin-network/noise/testutil"
+
+	"github.com/pkg/errors"
+)
+
+const (
+	// maxEntrySize bounds the size of a single line of a recording.
+	maxEntrySize = 16 * 1024 * 1024
+
+	defaultReplayInterval = 10 * time.Millisecond
+)
+
+// Replayer replays the messages a recorded network received to a fresh network, whose
+// connections are mocked.
+type Replayer struct {
+	Entries []Entry
+
+	// Interval is the time waited in between injecting messages, such that messages are
+	// dispatched by the network in the order they are replayed. Defaults to 10ms.
+	Interval time.Duration
+}
+
+// NewReplayer reads a recording made by a Recorder.
+func NewReplayer(r io.Reader) (*Replayer, error) {
+	replayer := &Replayer{Interval: defaultReplayInterval}
+
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(nil, maxEntrySize)
+
+	for scanner.Scan() {
+		if len(scanner.Bytes()) == 0 {
+			continue
+		}
+
+		var entry Entry
+		if err := json.Unmarshal(scanner.Bytes(), &entry); err != nil {
+			return nil, errors.Wrapf(err, "tracing: malformed entry %d", len(replayer.Entries)+1)
+		}
+
+		replayer.Entries = append(replayer.Entries, entry)
+	}
+
+	if err := scanner.Err(); err != nil {
+		return nil, errors.Wrap(err, "tracing: failed to read recording")
+	}
+
+	return replayer, nil
+}
+
+// NewBuilder returns a builder of a network listening on a mock layer, which is registered
+// for the protocols of all recorded senders such that replies to them are captured by the layer.
+func (r *Replayer) NewBuilder(layer *testutil.MockLayer, opts ...network.BuilderOption) *network.Builder {
+	builder := layer.NewBuilder(opts...)
+
+	for _, entry := range r.Entries {
+		msg, err := entry.Decode()
+		if err != nil || msg.Sender == nil {
+			continue
+		}
+
+		if info, err := network.ParseAddress(msg.Sender.Address); err == nil {
+			builder.RegisterTransportLayer(info.Protocol, layer)
+		}
+	}
+
+	return builder
+}
+
+// Replay injects every recorded incoming message into incoming connections of a mock layer,
+// one at a time in the order they were recorded. Messages received over the same recorded
+// connection are replayed over the same mock connection. The layer must be listened on.
+func (r *Replayer) Replay(layer *testutil.MockLayer) error {
+	conns := make(map[uint64]*testutil.MockConn)
+	replayed := 0
+
+	for _, entry := range r.Entries {
+		if entry.Direction != DirectionIncoming {
+			continue
+		}
+
+		msg, err := entry.Decode()
+		if err != nil {
+			return errors.Wrapf(err, "tracing: failed to decode message received over connection %d", entry.Conn)
+		}
+
+		if replayed > 0 {
+			time.Sleep(r.Interval)
+		}
+		replayed++
+
+		conn, exists := conns[entry.Conn]
+		if !exists {
+			if conn, err = layer.NewIncoming(entry.RemoteAddr); err != nil {
+				return err
+			}
+			conns[entry.Conn] = conn
+		}
+
+		if err := testutil.InjectMessage(conn, msg); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
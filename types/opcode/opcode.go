@@ -20,6 +20,26 @@ func init() {
 		{&protobuf.Pong{}, PongCode},
 		{&protobuf.LookupNodeRequest{}, LookupNodeRequestCode},
 		{&protobuf.LookupNodeResponse{}, LookupNodeResponseCode},
+		{&protobuf.AddressMigration{}, AddressMigrationCode},
+		{&protobuf.SwimPing{}, SwimPingCode},
+		{&protobuf.SwimPingRequest{}, SwimPingRequestCode},
+		{&protobuf.SwimAck{}, SwimAckCode},
+		{&protobuf.BeaconPartial{}, BeaconPartialCode},
+		{&protobuf.StoreRequest{}, StoreRequestCode},
+		{&protobuf.StoreResponse{}, StoreResponseCode},
+		{&protobuf.FindValueRequest{}, FindValueRequestCode},
+		{&protobuf.FindValueResponse{}, FindValueResponseCode},
+		{&protobuf.Subscribe{}, SubscribeCode},
+		{&protobuf.Publish{}, PublishCode},
+		{&protobuf.Transactions{}, TransactionsCode},
+		{&protobuf.TxSyncRequest{}, TxSyncRequestCode},
+		{&protobuf.TxSyncResponse{}, TxSyncResponseCode},
+		{&protobuf.ReliableMessage{}, ReliableMessageCode},
+		{&protobuf.ReliableAck{}, ReliableAckCode},
+		{&protobuf.BlockHeader{}, BlockHeaderCode},
+		{&protobuf.GetBlock{}, GetBlockCode},
+		{&protobuf.Block{}, BlockCode},
+		{&protobuf.KeyRevocation{}, KeyRevocationCode},
 	}
 
 	for _, pair := range msgOpcodePairs {
@@ -38,6 +58,26 @@ const (
 	PongCode               Opcode = 0x0000b // 11
 	LookupNodeRequestCode  Opcode = 0x0000c // 12
 	LookupNodeResponseCode Opcode = 0x0000d // 13
+	AddressMigrationCode   Opcode = 0x0000e // 14
+	SwimPingCode           Opcode = 0x0000f // 15
+	SwimPingRequestCode    Opcode = 0x00010 // 16
+	SwimAckCode            Opcode = 0x00011 // 17
+	BeaconPartialCode      Opcode = 0x00012 // 18
+	StoreRequestCode       Opcode = 0x00013 // 19
+	StoreResponseCode      Opcode = 0x00014 // 20
+	FindValueRequestCode   Opcode = 0x00015 // 21
+	FindValueResponseCode  Opcode = 0x00016 // 22
+	SubscribeCode          Opcode = 0x00017 // 23
+	PublishCode            Opcode = 0x00018 // 24
+	TransactionsCode       Opcode = 0x00019 // 25
+	TxSyncRequestCode      Opcode = 0x0001a // 26
+	TxSyncResponseCode     Opcode = 0x0001b // 27
+	ReliableMessageCode    Opcode = 0x0001c // 28
+	ReliableAckCode        Opcode = 0x0001d // 29
+	BlockHeaderCode        Opcode = 0x0001e // 30
+	GetBlockCode           Opcode = 0x0001f // 31
+	BlockCode              Opcode = 0x00020 // 32
+	KeyRevocationCode      Opcode = 0x00021 // 33
 )
 
 var (